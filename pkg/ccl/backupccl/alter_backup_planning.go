@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/errors"
 )
 
@@ -41,7 +42,22 @@ func alterBackupTypeCheck(
 	); err != nil {
 		return false, nil, err
 	}
-	return true, nil, nil
+	return true, alterBackupHeader(alterBackupStmt), nil
+}
+
+var alterBackupCompactHeader = colinfo.ResultColumns{
+	{Name: "path", Typ: types.String},
+}
+
+// alterBackupHeader returns the result columns of an ALTER BACKUP statement.
+// Only ALTER BACKUP ... COMPACT returns rows.
+func alterBackupHeader(alterBackupStmt *tree.AlterBackup) colinfo.ResultColumns {
+	for _, cmd := range alterBackupStmt.Cmds {
+		if _, ok := cmd.(*tree.AlterBackupCompact); ok {
+			return alterBackupCompactHeader
+		}
+	}
+	return nil
 }
 
 func alterBackupPlanHook(
@@ -77,9 +93,12 @@ func alterBackupPlanHook(
 
 	var newKms []string
 	var oldKms []string
+	var compact bool
 
 	for _, cmd := range alterBackupStmt.Cmds {
 		switch v := cmd.(type) {
+		case *tree.AlterBackupCompact:
+			compact = true
 		case *tree.AlterBackupKMS:
 			newKms, err = exprEval.StringArray(ctx, tree.Exprs(v.KMSInfo.NewKMSURI))
 			if err != nil {
@@ -92,6 +111,17 @@ func alterBackupPlanHook(
 		}
 	}
 
+	if compact {
+		if len(alterBackupStmt.Cmds) > 1 {
+			return nil, nil, nil, false, errors.New(
+				"ALTER BACKUP ... COMPACT cannot be combined with other commands")
+		}
+		if subdir == "" {
+			return nil, nil, nil, false, errors.New(
+				"ALTER BACKUP ... COMPACT requires a backup collection: use ALTER BACKUP <subdir> IN <collection> COMPACT")
+		}
+	}
+
 	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
 
 		if subdir != "" {
@@ -104,6 +134,19 @@ func alterBackupPlanHook(
 				subdir = latest
 			}
 
+			if compact {
+				compactedSubdir, err := compactBackupChain(ctx, p, backup, subdir)
+				if err != nil {
+					return err
+				}
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case resultsCh <- tree.Datums{tree.NewDString(compactedSubdir)}:
+					return nil
+				}
+			}
+
 			appendPaths := func(uri string, tailDir string) (string, error) {
 				parsed, err := url.Parse(uri)
 				if err != nil {
@@ -122,7 +165,7 @@ func alterBackupPlanHook(
 		return doAlterBackupPlan(ctx, alterBackupStmt, p, backup, newKms, oldKms)
 	}
 
-	return fn, nil, nil, false, nil
+	return fn, alterBackupHeader(alterBackupStmt), nil, false, nil
 }
 
 func doAlterBackupPlan(
@@ -317,6 +317,17 @@ func processScheduleOptions(
 				continue
 			}
 			s.incArgs.UpdatesLastBackupMetric = updatesLastBackupMetric
+		case optRetention, optKeepFulls:
+			// The retention policy is only enforced by the full backup schedule,
+			// which is the one that starts new backup chains.
+			if s.fullArgs.RetentionPolicy == nil {
+				s.fullArgs.RetentionPolicy = &backuppb.ScheduledBackupExecutionArgs_RetentionPolicy{}
+			}
+			if err := parseRetentionPolicyOption(
+				&p.ExtendedEvalContext().Context, k, v, s.fullArgs.RetentionPolicy,
+			); err != nil {
+				return err
+			}
 		default:
 			return errors.Newf("unexpected schedule option: %s = %s", k, v)
 		}
@@ -471,6 +482,7 @@ func processFullBackupRecurrence(
 			s.fullArgs.UpdatesLastBackupMetric,
 			s.incStmt,
 			s.fullArgs.ChainProtectedTimestampRecords,
+			nil, /* retentionPolicy */
 		)
 
 		if err != nil {
@@ -679,6 +691,8 @@ var alterBackupScheduleOptions = exprutil.KVOptionValidationMap{
 	optOnExecFailure:           exprutil.KVStringOptAny,
 	optOnPreviousRunning:       exprutil.KVStringOptAny,
 	optUpdatesLastBackupMetric: exprutil.KVStringOptAny,
+	optRetention:               exprutil.KVStringOptRequireValue,
+	optKeepFulls:               exprutil.KVStringOptRequireValue,
 }
 
 func alterBackupScheduleTypeCheck(
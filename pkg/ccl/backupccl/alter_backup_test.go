@@ -210,3 +210,72 @@ func TestAlterBackupIncremental(t *testing.T) {
 	sqlDB.Exec(t, query)
 	sqlDB.ExecRowsAffected(t, 2, "SELECT * FROM bank")
 }
+
+// TestAlterBackupCompact tests that compacting a backup chain produces a full
+// backup that restores to the same data as the chain it was compacted from,
+// and that later incremental backups are layered on top of it.
+func TestAlterBackupCompact(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 10
+
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, "BACKUP DATABASE data INTO $1", localFoo)
+
+	sqlDB.Exec(t, "UPDATE data.bank SET balance = balance + 100 WHERE id < 3")
+	sqlDB.Exec(t, "DELETE FROM data.bank WHERE id >= 8")
+	sqlDB.Exec(t, "CREATE TABLE data.other (k INT PRIMARY KEY, v STRING, INDEX (v))")
+	sqlDB.Exec(t, "INSERT INTO data.other VALUES (1, 'a'), (2, 'b')")
+	sqlDB.Exec(t, "BACKUP DATABASE data INTO LATEST IN $1", localFoo)
+
+	sqlDB.Exec(t, "INSERT INTO data.bank (id, balance) VALUES (100, 1)")
+	sqlDB.Exec(t, "DELETE FROM data.other WHERE k = 1")
+	sqlDB.Exec(t, "BACKUP DATABASE data INTO LATEST IN $1", localFoo)
+
+	var chain string
+	sqlDB.QueryRow(t, "SELECT path FROM [SHOW BACKUPS IN $1]", localFoo).Scan(&chain)
+
+	var compacted string
+	sqlDB.QueryRow(t, "ALTER BACKUP LATEST IN $1 COMPACT", localFoo).Scan(&compacted)
+	require.NotEqual(t, chain, compacted)
+
+	// The compacted backup is a single full backup and LATEST now points at it.
+	require.Equal(t, [][]string{{chain}, {compacted}},
+		sqlDB.QueryStr(t, "SELECT path FROM [SHOW BACKUPS IN $1] ORDER BY path", localFoo))
+	require.Equal(t, [][]string{{"full"}}, sqlDB.QueryStr(t,
+		"SELECT DISTINCT backup_type FROM [SHOW BACKUP FROM LATEST IN $1]", localFoo))
+
+	expectedBank := sqlDB.QueryStr(t, "SELECT * FROM data.bank ORDER BY id")
+	expectedOther := sqlDB.QueryStr(t, "SELECT * FROM data.other@other_v_idx ORDER BY v")
+	sqlDB.Exec(t, "RESTORE DATABASE data FROM $1 IN $2 WITH new_db_name = 'compacted'",
+		compacted, localFoo)
+	sqlDB.CheckQueryResults(t, "SELECT * FROM compacted.bank ORDER BY id", expectedBank)
+	sqlDB.CheckQueryResults(t, "SELECT * FROM compacted.other@other_v_idx ORDER BY v",
+		expectedOther)
+
+	// An incremental backup into LATEST is layered on the compacted backup.
+	sqlDB.Exec(t, "INSERT INTO data.other VALUES (3, 'c')")
+	sqlDB.Exec(t, "BACKUP DATABASE data INTO LATEST IN $1", localFoo)
+	sqlDB.Exec(t, "RESTORE DATABASE data FROM LATEST IN $1 WITH new_db_name = 'incremental'",
+		localFoo)
+	sqlDB.CheckQueryResults(t, "SELECT * FROM incremental.other ORDER BY k",
+		sqlDB.QueryStr(t, "SELECT * FROM data.other ORDER BY k"))
+
+	t.Run("errors", func(t *testing.T) {
+		sqlDB.Exec(t, "BACKUP DATABASE data INTO $1", "nodelocal://1/full")
+		sqlDB.ExpectErr(t, "backup chain has no incremental backups to compact",
+			"ALTER BACKUP LATEST IN $1 COMPACT", "nodelocal://1/full")
+
+		sqlDB.Exec(t, "BACKUP DATABASE data INTO $1 WITH revision_history", "nodelocal://1/revs")
+		sqlDB.Exec(t, "BACKUP DATABASE data INTO LATEST IN $1 WITH revision_history",
+			"nodelocal://1/revs")
+		sqlDB.ExpectErr(t, "compacting backups with revision history is not supported",
+			"ALTER BACKUP LATEST IN $1 COMPACT", "nodelocal://1/revs")
+
+		sqlDB.ExpectErr(t, "ALTER BACKUP ... COMPACT requires a backup collection",
+			"ALTER BACKUP $1 COMPACT", localFoo)
+	})
+}
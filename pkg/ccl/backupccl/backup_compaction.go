@@ -0,0 +1,488 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/build"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	gogotypes "github.com/gogo/protobuf/types"
+)
+
+// compactBackupChain synthesizes a new full backup from the backup chain in
+// subdir of the collection at collectionURI. The full backup and all of its
+// incremental layers are read from external storage and merged as of the end
+// time of the chain, and the result is written to a new subdirectory of the
+// collection. The live cluster is never scanned, so compaction adds no KV load.
+//
+// It returns the subdirectory that the new full backup was written to. If the
+// compacted chain was the latest chain in the collection, the LATEST file is
+// updated to point at the new full backup so that subsequent incremental
+// backups are layered on top of it.
+func compactBackupChain(
+	ctx context.Context, p sql.PlanHookState, collectionURI string, subdir string,
+) (string, error) {
+	execCfg := p.ExecCfg()
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+	collection := []string{collectionURI}
+
+	fullyResolvedBaseDirectory, err := backuputils.AppendPaths(collection, subdir)
+	if err != nil {
+		return "", err
+	}
+	fullyResolvedIncrementalsDirectory, err := backupdest.ResolveIncrementalsBackupLocation(
+		ctx, p.User(), execCfg, nil /* explicitIncrementalCollections */, collection, subdir,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	baseStores, cleanupFn, err := backupdest.MakeBackupDestinationStores(ctx, p.User(), mkStore,
+		fullyResolvedBaseDirectory)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := cleanupFn(); err != nil {
+			log.Warningf(ctx, "failed to close base store: %+v", err)
+		}
+	}()
+	incStores, cleanupFn, err := backupdest.MakeBackupDestinationStores(ctx, p.User(), mkStore,
+		fullyResolvedIncrementalsDirectory)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := cleanupFn(); err != nil {
+			log.Warningf(ctx, "failed to close incremental store: %+v", err)
+		}
+	}()
+
+	// GetEncryptionInfoFiles only succeeds if the backup has ENCRYPTION-INFO
+	// files.
+	if _, err := backupencryption.GetEncryptionInfoFiles(ctx, baseStores[0]); err == nil {
+		return "", unimplementedCompactionErr("encrypted backups")
+	}
+
+	ioConf := baseStores[0].ExternalIOConf()
+	kmsEnv := backupencryption.MakeBackupKMSEnv(
+		execCfg.Settings, &ioConf, execCfg.InternalDB, p.User(),
+	)
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+
+	_, manifests, localityInfo, memReserved, err := backupdest.ResolveBackupManifests(
+		ctx, &mem, baseStores, incStores, mkStore, fullyResolvedBaseDirectory,
+		fullyResolvedIncrementalsDirectory, hlc.Timestamp{}, nil /* encryption */, &kmsEnv, p.User(),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		mem.Shrink(ctx, memReserved)
+	}()
+
+	if err := validateBackupChainForCompaction(manifests, localityInfo); err != nil {
+		return "", err
+	}
+	if err := checkBackupManifestVersionCompatability(ctx, execCfg.Settings.Version,
+		manifests, false /* unsafeRestoreIncompatibleVersion */); err != nil {
+		return "", err
+	}
+
+	endTime := manifests[len(manifests)-1].EndTime
+	compactedSubdir := endTime.GoTime().Format(backupbase.DateBasedIntoFolderName)
+	destURIs, err := backuputils.AppendPaths(collection, compactedSubdir)
+	if err != nil {
+		return "", err
+	}
+	if err := backupinfo.CheckForPreviousBackup(ctx, execCfg, destURIs[0], 0, /* jobID */
+		p.User()); err != nil {
+		return "", err
+	}
+	dest, err := mkStore(ctx, destURIs[0], p.User())
+	if err != nil {
+		return "", err
+	}
+	defer dest.Close()
+
+	if err := writeCompactedBackup(ctx, execCfg, dest, manifests, localityInfo, &kmsEnv,
+		p.User()); err != nil {
+		return "", errors.Wrapf(err, "compacting backup chain %s", subdir)
+	}
+
+	latest, err := backupdest.ReadLatestFile(ctx, collectionURI, mkStore, p.User())
+	if err != nil {
+		// The compacted backup is complete even if the collection has no
+		// readable LATEST file, so there is nothing to update.
+		log.Warningf(ctx, "failed to read LATEST file after compacting backup chain: %+v", err)
+		return compactedSubdir, nil
+	}
+	if strings.Trim(latest, "/") == strings.Trim(subdir, "/") {
+		collectionStore, err := mkStore(ctx, collectionURI, p.User())
+		if err != nil {
+			return "", err
+		}
+		defer collectionStore.Close()
+		if err := backupdest.WriteNewLatestFile(ctx, execCfg.Settings, collectionStore,
+			compactedSubdir); err != nil {
+			return "", err
+		}
+	}
+	return compactedSubdir, nil
+}
+
+func unimplementedCompactionErr(what string) error {
+	return pgerror.Newf(pgcode.FeatureNotSupported, "compacting %s is not supported", what)
+}
+
+// validateBackupChainForCompaction checks that the backup chain described by
+// manifests can be compacted.
+func validateBackupChainForCompaction(
+	manifests []backuppb.BackupManifest, localityInfo []jobspb.RestoreDetails_BackupLocalityInfo,
+) error {
+	if len(manifests) < 2 {
+		return pgerror.New(pgcode.InvalidParameterValue,
+			"backup chain has no incremental backups to compact")
+	}
+	for i := range manifests {
+		if manifests[i].MVCCFilter == backuppb.MVCCFilter_All {
+			return unimplementedCompactionErr("backups with revision history")
+		}
+		if len(localityInfo[i].URIsByOriginalLocalityKV) > 0 {
+			return unimplementedCompactionErr("locality-aware backups")
+		}
+	}
+	return nil
+}
+
+// writeCompactedBackup writes a full backup at the end time of the chain of
+// backups described by manifests to dest, along with its manifest and table
+// statistics.
+func writeCompactedBackup(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	dest cloud.ExternalStorage,
+	manifests []backuppb.BackupManifest,
+	localityInfo []jobspb.RestoreDetails_BackupLocalityInfo,
+	kmsEnv cloud.KMSEnv,
+	user username.SQLUsername,
+) error {
+	last := manifests[len(manifests)-1]
+	endTime := last.EndTime
+
+	layerToIterFactory, err := backupinfo.GetBackupManifestIterFactories(ctx,
+		execCfg.DistSQLSrv.ExternalStorage, manifests, nil /* encryption */, kmsEnv)
+	if err != nil {
+		return err
+	}
+
+	// The descriptors of the compacted backup are those of the last layer,
+	// which may be stored in an external SST rather than the manifest itself.
+	var descriptors []descpb.Descriptor
+	pkIDs := make(map[uint64]bool)
+	if err := func() error {
+		descIt := layerToIterFactory[len(manifests)-1].NewDescIter(ctx)
+		defer descIt.Close()
+		for ; ; descIt.Next() {
+			if ok, err := descIt.Valid(); err != nil {
+				return err
+			} else if !ok {
+				return nil
+			}
+			desc := *descIt.Value()
+			descriptors = append(descriptors, desc)
+			if t, _, _, _, _ := descpb.GetDescriptors(&desc); t != nil {
+				pkIDs[kvpb.BulkOpSummaryID(uint64(t.ID), uint64(t.PrimaryIndex.ID))] = true
+			}
+		}
+	}(); err != nil {
+		return err
+	}
+
+	backupLocalityMap, err := makeBackupLocalityMap(localityInfo, user)
+	if err != nil {
+		return err
+	}
+	introducedSpanFrontier, err := createIntroducedSpanFrontier(manifests, endTime)
+	if err != nil {
+		return err
+	}
+	filter, err := makeSpanCoveringFilter(
+		nil, /* checkpointFrontier */
+		nil, /* highWater */
+		introducedSpanFrontier,
+		targetRestoreSpanSize.Get(&execCfg.Settings.SV),
+		false, /* useFrontierCheckpointing */
+	)
+	if err != nil {
+		return err
+	}
+
+	spanCh := make(chan execinfrapb.RestoreSpanEntry, 1000)
+	progCh := make(chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress)
+
+	generateSpans := func(ctx context.Context) error {
+		defer close(spanCh)
+		return generateAndSendImportSpans(
+			ctx,
+			last.Spans,
+			manifests,
+			layerToIterFactory,
+			backupLocalityMap,
+			filter,
+			false, /* useSimpleImportSpans */
+			spanCh,
+		)
+	}
+
+	compactSpans := func(ctx context.Context) error {
+		defer close(progCh)
+		sink := makeFileSSTSink(sstSinkConf{
+			progCh:   progCh,
+			id:       execCfg.NodeInfo.NodeID.SQLInstanceID(),
+			settings: &execCfg.Settings.SV,
+		}, dest)
+		defer func() {
+			if err := sink.Close(); err != nil {
+				log.Warningf(ctx, "failed to close backup sink: %+v", err)
+			}
+		}()
+		for entry := range spanCh {
+			if err := compactRestoreSpanEntry(ctx, execCfg, sink, entry, endTime, pkIDs); err != nil {
+				return err
+			}
+		}
+		return sink.flush(ctx)
+	}
+
+	var files []backuppb.BackupManifest_File
+	var entryCounts roachpb.RowCount
+	collectFiles := func(ctx context.Context) error {
+		for prog := range progCh {
+			var progDetails backuppb.BackupManifest_Progress
+			if err := gogotypes.UnmarshalAny(&prog.ProgressDetails, &progDetails); err != nil {
+				return err
+			}
+			for _, file := range progDetails.Files {
+				files = append(files, file)
+				entryCounts.Add(file.EntryCounts)
+			}
+		}
+		return nil
+	}
+
+	if err := ctxgroup.GoAndWait(ctx, generateSpans, compactSpans, collectFiles); err != nil {
+		return err
+	}
+
+	manifest := last
+	manifest.ID = uuid.MakeV4()
+	manifest.StartTime = hlc.Timestamp{}
+	manifest.IntroducedSpans = nil
+	manifest.DescriptorChanges = nil
+	manifest.Descriptors = descriptors
+	manifest.Files = files
+	manifest.EntryCounts = entryCounts
+	manifest.HasExternalManifestSSTs = false
+	manifest.Dir = dest.Conf()
+	manifest.DeprecatedStatistics = nil
+	manifest.StatisticsFilenames = make(map[descpb.ID]string, len(last.StatisticsFilenames))
+	for id := range last.StatisticsFilenames {
+		manifest.StatisticsFilenames[id] = backupinfo.BackupStatisticsFileName
+	}
+
+	if err := backupinfo.WriteBackupManifest(ctx, dest, backupbase.BackupManifestName,
+		nil /* encryption */, kmsEnv, &manifest); err != nil {
+		return err
+	}
+	if backupinfo.WriteMetadataWithExternalSSTsEnabled.Get(&execCfg.Settings.SV) {
+		if err := backupinfo.WriteMetadataWithExternalSSTs(
+			ctx, dest, nil /* encryption */, kmsEnv, &manifest,
+		); err != nil {
+			return err
+		}
+	}
+
+	// Carry over the table statistics of the last layer, which were collected
+	// when the chain's latest backup was taken.
+	lastStore, err := execCfg.DistSQLSrv.ExternalStorage(ctx, last.Dir)
+	if err != nil {
+		return err
+	}
+	defer lastStore.Close()
+	statistics, err := backupinfo.GetStatisticsFromBackup(
+		ctx, lastStore, nil /* encryption */, kmsEnv, last,
+	)
+	if err != nil {
+		return err
+	}
+	statsTable := backuppb.StatsTable{Statistics: statistics}
+	if err := backupinfo.WriteTableStatistics(ctx, dest, nil /* encryption */, kmsEnv,
+		&statsTable); err != nil {
+		return err
+	}
+
+	if backupinfo.WriteMetadataSST.Get(&execCfg.Settings.SV) {
+		if err := backupinfo.WriteBackupMetadataSST(ctx, dest, nil /* encryption */, kmsEnv,
+			&manifest, statistics); err != nil {
+			err = errors.Wrap(err, "writing forward-compat metadata sst")
+			if !build.IsRelease() {
+				return err
+			}
+			log.Warningf(ctx, "%+v", err)
+		}
+	}
+	return nil
+}
+
+// compactRestoreSpanEntry merges the files of entry as of endTime and writes
+// the latest live version of every key in the entry's span to sink. Deleted
+// keys are elided, as they would be from a full backup taken at endTime.
+// The merged data is split into files of roughly the sink's target file size,
+// but only between rows.
+func compactRestoreSpanEntry(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	sink *fileSSTSink,
+	entry execinfrapb.RestoreSpanEntry,
+	endTime hlc.Timestamp,
+	pkIDs map[uint64]bool,
+) error {
+	storeFiles := make([]storageccl.StoreFile, 0, len(entry.Files))
+	defer func() {
+		for _, f := range storeFiles {
+			if err := f.Store.Close(); err != nil {
+				log.Warningf(ctx, "close export storage failed %v", err)
+			}
+		}
+	}()
+	for _, file := range entry.Files {
+		dir, err := execCfg.DistSQLSrv.ExternalStorage(ctx, file.Dir)
+		if err != nil {
+			return err
+		}
+		storeFiles = append(storeFiles, storageccl.StoreFile{Store: dir, FilePath: file.Path})
+	}
+
+	iterOpts := storage.IterOptions{
+		RangeKeyMaskingBelow: endTime,
+		KeyTypes:             storage.IterKeyTypePointsAndRanges,
+		LowerBound:           keys.LocalMax,
+		UpperBound:           keys.MaxKey,
+	}
+	iter, err := storageccl.ExternalSSTReader(ctx, storeFiles, nil /* encryption */, iterOpts)
+	if err != nil {
+		return err
+	}
+	readAsOfIter := storage.NewReadAsOfIterator(iter, endTime)
+	defer readAsOfIter.Close()
+
+	var buf bytes.Buffer
+	var sst storage.SSTWriter
+	var counter storage.RowCounter
+	var prevRow roachpb.Key
+	fileStart := entry.Span.Key
+
+	flush := func(end roachpb.Key) error {
+		if counter.DataSize == 0 {
+			return nil
+		}
+		if err := sst.Finish(); err != nil {
+			return err
+		}
+		if err := sink.write(ctx, exportedSpan{
+			metadata: backuppb.BackupManifest_File{
+				Span:        roachpb.Span{Key: fileStart, EndKey: end},
+				EntryCounts: countRows(counter.BulkOpSummary, pkIDs),
+				EndTime:     endTime,
+			},
+			dataSST:       append([]byte(nil), buf.Bytes()...),
+			atKeyBoundary: true,
+		}); err != nil {
+			return err
+		}
+		buf.Reset()
+		counter = storage.RowCounter{}
+		fileStart = end
+		return nil
+	}
+
+	startKey, endKey := storage.MVCCKey{Key: entry.Span.Key}, storage.MVCCKey{Key: entry.Span.EndKey}
+	for readAsOfIter.SeekGE(startKey); ; readAsOfIter.NextKey() {
+		if ok, err := readAsOfIter.Valid(); err != nil {
+			return err
+		} else if !ok || !readAsOfIter.UnsafeKey().Less(endKey) {
+			break
+		}
+		key := readAsOfIter.UnsafeKey()
+		v, err := readAsOfIter.UnsafeValue()
+		if err != nil {
+			return err
+		}
+
+		row, err := keys.EnsureSafeSplitKey(key.Key)
+		if err != nil {
+			// Non-SQL keys are treated as their own row.
+			row = key.Key
+		}
+		if !row.Equal(prevRow) {
+			if counter.DataSize > targetFileSize.Get(&execCfg.Settings.SV) {
+				if err := flush(key.Key.Clone()); err != nil {
+					return err
+				}
+			}
+			prevRow = append(prevRow[:0], row...)
+		}
+
+		if counter.DataSize == 0 {
+			sst = storage.MakeBackupSSTWriter(ctx, execCfg.Settings, &buf)
+		}
+		if key.Timestamp.IsEmpty() {
+			err = sst.PutUnversioned(key.Key, v)
+		} else {
+			err = sst.PutRawMVCC(key, v)
+		}
+		if err != nil {
+			return err
+		}
+		if err := counter.Count(key.Key); err != nil {
+			return err
+		}
+		counter.DataSize += int64(len(key.Key) + len(v))
+	}
+	return flush(entry.Span.EndKey)
+}
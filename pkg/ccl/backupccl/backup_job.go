@@ -895,6 +895,23 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 		if err := backupdest.WriteNewLatestFile(ctx, p.ExecCfg().Settings, c, suffix); err != nil {
 			return err
 		}
+
+		// Now that the new backup chain is restorable, the schedule that started
+		// it garbage collects the chains that expired under its retention policy.
+		if details.ScheduleID != 0 {
+			deleted, err := deleteExpiredBackupChains(ctx, p, details)
+			if err != nil {
+				// Failing to delete old backups should not fail the new backup; the
+				// next full backup of the schedule retries the deletion.
+				log.Warningf(ctx, "backup schedule %d failed to delete expired backups: %v",
+					details.ScheduleID, err)
+			}
+			if len(deleted) > 0 {
+				if err := recordDeletedExpiredBackups(ctx, b.job, deleted); err != nil {
+					return err
+				}
+			}
+		}
 	}
 
 	b.backupStats = res
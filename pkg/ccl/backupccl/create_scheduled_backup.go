@@ -11,6 +11,7 @@ package backupccl
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
@@ -45,6 +46,8 @@ const (
 	optOnPreviousRunning       = "on_previous_running"
 	optIgnoreExistingBackups   = "ignore_existing_backups"
 	optUpdatesLastBackupMetric = "updates_cluster_last_backup_time_metric"
+	optRetention               = "retention"
+	optKeepFulls               = "keep_fulls"
 )
 
 var scheduledBackupOptionExpectValues = map[string]exprutil.KVStringOptValidate{
@@ -53,6 +56,8 @@ var scheduledBackupOptionExpectValues = map[string]exprutil.KVStringOptValidate{
 	optOnPreviousRunning:       exprutil.KVStringOptRequireValue,
 	optIgnoreExistingBackups:   exprutil.KVStringOptRequireNoValue,
 	optUpdatesLastBackupMetric: exprutil.KVStringOptRequireNoValue,
+	optRetention:               exprutil.KVStringOptRequireValue,
+	optKeepFulls:               exprutil.KVStringOptRequireValue,
 }
 
 // scheduledBackupGCProtectionEnabled is used to enable and disable the chaining
@@ -107,6 +112,63 @@ func makeScheduleDetails(opts map[string]string) (jobspb.ScheduleDetails, error)
 	return details, nil
 }
 
+// makeRetentionPolicy returns the retention policy described by the retention
+// and keep_fulls schedule options, or nil if neither option is set.
+func makeRetentionPolicy(
+	evalCtx *eval.Context, opts map[string]string,
+) (*backuppb.ScheduledBackupExecutionArgs_RetentionPolicy, error) {
+	_, hasRetention := opts[optRetention]
+	_, hasKeepFulls := opts[optKeepFulls]
+	if !hasRetention && !hasKeepFulls {
+		return nil, nil
+	}
+	policy := &backuppb.ScheduledBackupExecutionArgs_RetentionPolicy{}
+	for _, opt := range []string{optRetention, optKeepFulls} {
+		if v, ok := opts[opt]; ok {
+			if err := parseRetentionPolicyOption(evalCtx, opt, v, policy); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return policy, nil
+}
+
+// parseRetentionPolicyOption parses the value of the retention or keep_fulls
+// schedule option into policy.
+func parseRetentionPolicyOption(
+	evalCtx *eval.Context,
+	opt, v string,
+	policy *backuppb.ScheduledBackupExecutionArgs_RetentionPolicy,
+) error {
+	switch opt {
+	case optRetention:
+		d, err := tree.ParseDInterval(evalCtx.GetIntervalStyle(), v)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value for %s", optRetention)
+		}
+		nanos, _, _, err := d.Encode()
+		if err != nil {
+			return errors.Wrapf(err, "invalid value for %s", optRetention)
+		}
+		if nanos <= 0 {
+			return errors.Newf("%s must be a positive interval, found %q", optRetention, v)
+		}
+		policy.Retention = time.Duration(nanos)
+	case optKeepFulls:
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value for %s", optKeepFulls)
+		}
+		if n < 1 {
+			return errors.Newf("%s must be at least 1, found %d", optKeepFulls, n)
+		}
+		policy.KeepFulls = int32(n)
+	default:
+		return errors.AssertionFailedf("unexpected retention policy option %s", opt)
+	}
+	return nil
+}
+
 func scheduleFirstRun(evalCtx *eval.Context, opts map[string]string) (*time.Time, error) {
 	if v, ok := opts[optFirstRun]; ok {
 		firstRun, _, err := tree.ParseDTimestampTZ(evalCtx, v, time.Microsecond)
@@ -308,6 +370,11 @@ func doCreateBackupSchedules(
 		return err
 	}
 
+	retentionPolicy, err := makeRetentionPolicy(evalCtx, scheduleOptions)
+	if err != nil {
+		return err
+	}
+
 	unpauseOnSuccessID := jobs.InvalidScheduleID
 
 	var chainProtectedTimestampRecords bool
@@ -344,7 +411,7 @@ func doCreateBackupSchedules(
 		}
 		inc, incScheduledBackupArgs, err = makeBackupSchedule(
 			env, p.User(), scheduleLabel, incRecurrence, incrementalScheduleDetails, unpauseOnSuccessID,
-			updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords)
+			updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords, nil /* retentionPolicy */)
 		if err != nil {
 			return err
 		}
@@ -368,7 +435,7 @@ func doCreateBackupSchedules(
 	var fullScheduledBackupArgs *backuppb.ScheduledBackupExecutionArgs
 	full, fullScheduledBackupArgs, err := makeBackupSchedule(
 		env, p.User(), scheduleLabel, fullRecurrence, details, unpauseOnSuccessID,
-		updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords)
+		updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords, retentionPolicy)
 	if err != nil {
 		return err
 	}
@@ -475,6 +542,7 @@ func makeBackupSchedule(
 	updateLastMetricOnSuccess bool,
 	backupNode *tree.Backup,
 	chainProtectedTimestampRecords bool,
+	retentionPolicy *backuppb.ScheduledBackupExecutionArgs_RetentionPolicy,
 ) (*jobs.ScheduledJob, *backuppb.ScheduledBackupExecutionArgs, error) {
 	sj := jobs.NewScheduledJob(env)
 	sj.SetScheduleLabel(label)
@@ -485,6 +553,7 @@ func makeBackupSchedule(
 		UnpauseOnSuccess:               unpauseOnSuccess,
 		UpdatesLastBackupMetric:        updateLastMetricOnSuccess,
 		ChainProtectedTimestampRecords: chainProtectedTimestampRecords,
+		RetentionPolicy:                retentionPolicy,
 	}
 	if backupNode.AppendToLatest {
 		args.BackupType = backuppb.ScheduledBackupExecutionArgs_INCREMENTAL
@@ -36,6 +36,7 @@ type restorationData interface {
 	getRekeys() []execinfrapb.TableRekey
 	getTenantRekeys() []execinfrapb.TenantRekey
 	getPKIDs() map[uint64]bool
+	getRowFilter() *execinfrapb.RestoreRowFilter
 
 	// isValidateOnly returns ture iff only validation should occur
 	isValidateOnly() bool
@@ -83,6 +84,10 @@ type restorationDataBase struct {
 
 	// validateOnly indicates this data should only get read from external storage, not written
 	validateOnly bool
+
+	// rowFilter restricts the rows and columns of the table being restored by
+	// a filtered restore. Should be nil otherwise.
+	rowFilter *execinfrapb.RestoreRowFilter
 }
 
 // restorationDataBase implements restorationData.
@@ -103,6 +108,11 @@ func (b *restorationDataBase) getPKIDs() map[uint64]bool {
 	return b.pkIDs
 }
 
+// getRowFilter implements restorationData.
+func (b *restorationDataBase) getRowFilter() *execinfrapb.RestoreRowFilter {
+	return b.rowFilter
+}
+
 // getSpans implements restorationData.
 func (b *restorationDataBase) getSpans() []roachpb.Span {
 	return b.spans
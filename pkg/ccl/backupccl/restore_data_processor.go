@@ -493,6 +493,10 @@ func (rd *restoreDataProcessor) runRestoreWorkers(
 		if err != nil {
 			return err
 		}
+		rf, err := makeRestoreRowFilter(ctx, rd.FlowCtx, rd.spec.RowFilter, rd.spec.TableRekeys)
+		if err != nil {
+			return err
+		}
 
 		var sstIter mergedSST
 		for {
@@ -510,7 +514,7 @@ func (rd *restoreDataProcessor) runRestoreWorkers(
 						return done, err
 					}
 
-					summary, err := rd.processRestoreSpanEntry(ctx, kr, sstIter)
+					summary, err := rd.processRestoreSpanEntry(ctx, kr, rf, sstIter)
 					if err != nil {
 						return done, err
 					}
@@ -538,7 +542,7 @@ func (rd *restoreDataProcessor) runRestoreWorkers(
 }
 
 func (rd *restoreDataProcessor) processRestoreSpanEntry(
-	ctx context.Context, kr *KeyRewriter, sst mergedSST,
+	ctx context.Context, kr *KeyRewriter, rf *restoreRowFilter, sst mergedSST,
 ) (kvpb.BulkOpSummary, error) {
 	db := rd.flowCtx.Cfg.DB
 	evalCtx := rd.EvalCtx
@@ -653,6 +657,14 @@ func (rd *restoreDataProcessor) processRestoreSpanEntry(
 			continue
 		}
 
+		if rf != nil {
+			if ok, err := rf.filterKV(ctx, key.Key, &value); err != nil {
+				return summary, errors.Wrapf(err, "filtering %s", key)
+			} else if !ok {
+				continue
+			}
+		}
+
 		// Rewriting the key means the checksum needs to be updated.
 		value.ClearChecksum()
 		value.InitChecksum(key.Key)
@@ -907,10 +907,32 @@ func createImportingDescriptors(
 		databases = append(databases, tempSystemDB)
 	}
 
+	// A filtered restore only restores a subset of the columns of its single
+	// table, and only reads the parts of its primary index that the filter
+	// allows.
+	var droppedColumnIDs []descpb.ColumnID
+	for _, table := range mutableTables {
+		droppedColumnIDs = projectRestoredTableColumns(table, details.ColumnIDs)
+	}
+
 	// We get the spans of the restoring tables _as they appear in the backup_,
 	// that is, in the 'old' keyspace, before we reassign the table IDs.
 	preRestoreSpans := spansForAllRestoreTableIndexes(backupCodec, preRestoreTables, nil, details.SchemaOnly)
 	postRestoreSpans := spansForAllRestoreTableIndexes(backupCodec, postRestoreTables, nil, details.SchemaOnly)
+	if details.RowFilter != "" {
+		if len(mutableTables) != 1 {
+			return nil, nil, nil, errors.AssertionFailedf(
+				"expected a single table in a filtered restore, found %d", len(mutableTables))
+		}
+		evalCtx := &p.ExtendedEvalContext().Context
+		for _, spans := range []*[]roachpb.Span{&preRestoreSpans, &postRestoreSpans} {
+			if *spans, err = narrowSpansForRowFilter(
+				ctx, evalCtx, backupCodec, mutableTables[0], details.RowFilter, *spans,
+			); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
 	var verifySpans []roachpb.Span
 	if details.VerifyData {
 		// verifySpans contains the spans that should be read and checksum'd during a
@@ -1408,11 +1430,21 @@ func createImportingDescriptors(
 		pkIDs[kvpb.BulkOpSummaryID(uint64(tbl.GetID()), uint64(tbl.GetPrimaryIndexID()))] = true
 	}
 
+	var rowFilter *execinfrapb.RestoreRowFilter
+	if details.RowFilter != "" || len(droppedColumnIDs) > 0 {
+		rowFilter = &execinfrapb.RestoreRowFilter{
+			TableID:          mutableTables[0].GetID(),
+			Predicate:        details.RowFilter,
+			DroppedColumnIDs: droppedColumnIDs,
+		}
+	}
+
 	dataToPreRestore = &restorationDataBase{
 		spans:        preRestoreSpans,
 		tableRekeys:  rekeys,
 		tenantRekeys: tenantRekeys,
 		pkIDs:        pkIDs,
+		rowFilter:    rowFilter,
 	}
 
 	trackedRestore = &mainRestorationData{
@@ -1421,6 +1453,7 @@ func createImportingDescriptors(
 			tableRekeys:  rekeys,
 			tenantRekeys: tenantRekeys,
 			pkIDs:        pkIDs,
+			rowFilter:    rowFilter,
 		},
 	}
 
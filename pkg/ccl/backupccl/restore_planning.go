@@ -1082,6 +1082,8 @@ func resolveOptionsForRestoreJobDescription(
 		UnsafeRestoreIncompatibleVersion: opts.UnsafeRestoreIncompatibleVersion,
 		ExperimentalOnline:               opts.ExperimentalOnline,
 		RemoveRegions:                    opts.RemoveRegions,
+		Filter:                           opts.Filter,
+		Columns:                          opts.Columns,
 	}
 
 	if opts.EncryptionPassphrase != nil {
@@ -1173,6 +1175,7 @@ func restoreTypeCheck(
 			exprutil.MakeStringArraysFromOptList(restoreStmt.From),
 			tree.Exprs(restoreStmt.Options.DecryptionKMSURI),
 			tree.Exprs(restoreStmt.Options.IncrementalStorage),
+			tree.Exprs(restoreStmt.Options.Columns),
 		),
 		exprutil.Bools{
 			restoreStmt.Options.IncludeAllSecondaryTenants,
@@ -1186,6 +1189,7 @@ func restoreTypeCheck(
 			restoreStmt.Options.AsTenant,
 			restoreStmt.Options.DebugPauseOn,
 			restoreStmt.Options.ExecutionLocality,
+			restoreStmt.Options.Filter,
 		},
 	); err != nil {
 		return false, nil, err
@@ -2062,6 +2066,16 @@ func doRestorePlan(
 		return err
 	}
 
+	rowFilter, columnIDs, err := resolveRestoreRowFilter(
+		ctx, p, exprEval, restoreStmt, filteredTablesByID,
+	)
+	if err != nil {
+		return err
+	}
+	for _, t := range filteredTablesByID {
+		projectRestoredTableColumns(t, columnIDs)
+	}
+
 	// When running a full cluster restore, we drop the defaultdb and postgres
 	// databases that are present in a new cluster.
 	// This is done so that they can be restored the same way any other user
@@ -2198,6 +2212,8 @@ func doRestorePlan(
 		ExecutionLocality:   execLocality,
 		ExperimentalOnline:  restoreStmt.Options.ExperimentalOnline,
 		RemoveRegions:       restoreStmt.Options.RemoveRegions,
+		RowFilter:           rowFilter,
+		ColumnIDs:           columnIDs,
 	}
 
 	jr := jobs.Record{
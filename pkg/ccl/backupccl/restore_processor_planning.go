@@ -140,6 +140,7 @@ func distRestore(
 			PKIDs:             md.dataToRestore.getPKIDs(),
 			ValidateOnly:      md.dataToRestore.isValidateOnly(),
 			MemoryMonitorSSTs: memMonSSTs,
+			RowFilter:         md.dataToRestore.getRowFilter(),
 		}
 
 		// Plan SplitAndScatter in a round-robin fashion.
@@ -0,0 +1,668 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/keyside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+)
+
+// A filtered RESTORE restores a subset of a single table: the filter option
+// restricts the rows that are restored and the columns option restricts the
+// columns that are restored.
+//
+// The filter may only reference primary key columns. Every index entry of a
+// row, whether in the primary index or a secondary index, encodes the row's
+// primary key, so the restore data processor can decide whether to ingest each
+// key in isolation, even if the keys of a row are spread across backup files.
+// The other columns of a row are not decoded: their values may be split across
+// column families and secondary index entries do not carry them at all, so a
+// filter referencing them is rejected rather than evaluated on partial rows.
+// Where the filter constrains a prefix of the primary key to constants, the
+// primary index spans read from the backup are narrowed accordingly.
+//
+// The columns that are not restored are removed from the restored table's
+// descriptor and their values are stripped from the ingested primary index
+// entries. Primary key and hidden columns are always restored, and a column
+// may only be excluded if no index, constraint or computed column depends on
+// it.
+
+const (
+	restoreOptFilter  = "filter"
+	restoreOptColumns = "columns"
+)
+
+// resolveRestoreRowFilter validates the filter and columns options of a RESTORE
+// against the table being restored. It returns the serialized filter predicate
+// and the IDs of the columns to restore, either of which is empty if the
+// corresponding option was not specified.
+func resolveRestoreRowFilter(
+	ctx context.Context,
+	p sql.PlanHookState,
+	exprEval *exprutil.Evaluator,
+	restoreStmt *tree.Restore,
+	tablesByID map[descpb.ID]*tabledesc.Mutable,
+) (predicate string, columnIDs []descpb.ColumnID, _ error) {
+	opts := restoreStmt.Options
+	if opts.Filter == nil && opts.Columns == nil {
+		return "", nil, nil
+	}
+	if restoreStmt.DescriptorCoverage != tree.RequestedDescriptors ||
+		restoreStmt.Targets.Tables.TablePatterns == nil {
+		return "", nil, errors.Newf("the %q and %q options can only be used with RESTORE TABLE",
+			restoreOptFilter, restoreOptColumns)
+	}
+	if opts.SchemaOnly || opts.VerifyData || opts.ExperimentalOnline {
+		return "", nil, errors.Newf(
+			"the %q and %q options cannot be used with schema_only, verify_backup_table_data or experimental deferred copy",
+			restoreOptFilter, restoreOptColumns)
+	}
+	if len(tablesByID) != 1 {
+		return "", nil, errors.Newf("the %q and %q options can only be used when restoring a single table",
+			restoreOptFilter, restoreOptColumns)
+	}
+	var table *tabledesc.Mutable
+	for _, t := range tablesByID {
+		table = t
+	}
+	if !table.IsPhysicalTable() || table.IsSequence() {
+		return "", nil, errors.Newf("%q is not a table", table.GetName())
+	}
+	if len(table.AllMutations()) > 0 {
+		return "", nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot filter table %q which was undergoing a schema change when it was backed up",
+			table.GetName())
+	}
+
+	if opts.Filter != nil {
+		var err error
+		predicate, err = resolveRestoreFilterPredicate(ctx, p, exprEval, opts.Filter, table)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	if opts.Columns != nil {
+		var err error
+		columnIDs, err = resolveRestoreColumns(ctx, exprEval, opts.Columns, table)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return predicate, columnIDs, nil
+}
+
+// resolveRestoreFilterPredicate validates the filter predicate and returns it
+// serialized with its columns dequalified.
+func resolveRestoreFilterPredicate(
+	ctx context.Context,
+	p sql.PlanHookState,
+	exprEval *exprutil.Evaluator,
+	filter tree.Expr,
+	table catalog.TableDescriptor,
+) (string, error) {
+	s, err := exprEval.String(ctx, filter)
+	if err != nil {
+		return "", err
+	}
+	expr, err := parser.ParseExpr(s)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing %s", restoreOptFilter)
+	}
+	tn := tree.NewUnqualifiedTableName(tree.Name(table.GetName()))
+	predicate, _, colIDs, err := schemaexpr.DequalifyAndValidateExpr(
+		ctx,
+		table,
+		expr,
+		types.Bool,
+		tree.RestoreFilterExpr,
+		p.SemaCtx(),
+		volatility.Immutable,
+		tn,
+		p.ExecCfg().Settings.Version.ActiveVersion(ctx),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	pkColIDs := table.GetPrimaryIndex().CollectKeyColumnIDs()
+	for _, colID := range colIDs.Ordered() {
+		col, err := catalog.MustFindColumnByID(table, colID)
+		if err != nil {
+			return "", err
+		}
+		if !pkColIDs.Contains(colID) {
+			return "", errors.WithHint(
+				pgerror.Newf(pgcode.FeatureNotSupported,
+					"%s may only reference primary key columns, but %q is not part of the primary key of %q",
+					restoreOptFilter, col.GetName(), table.GetName()),
+				"Rows are filtered one key at a time as they are read from the backup, and only the "+
+					"primary key of a row is encoded in each of its keys. To filter on other columns, "+
+					"restore the rows matching the primary key columns of the filter and delete the "+
+					"remaining rows after the restore.",
+			)
+		}
+		if typ := col.GetType(); typ.UserDefined() || colinfo.CanHaveCompositeKeyEncoding(typ) {
+			return "", pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s cannot reference column %q of type %s", restoreOptFilter, col.GetName(), typ.SQLString())
+		}
+	}
+
+	// The primary key of a row is only encoded in the entry of a unique
+	// secondary index for the row's first column family, so its entries for
+	// other families cannot be filtered in isolation.
+	for _, idx := range table.PublicNonPrimaryIndexes() {
+		if !idx.IsUnique() {
+			continue
+		}
+		for i := 0; i < idx.NumSecondaryStoredColumns(); i++ {
+			if familyOfColumn(table, idx.GetStoredColumnID(i)) != 0 {
+				return "", pgerror.Newf(pgcode.FeatureNotSupported,
+					"cannot filter table %q: unique index %q stores columns of multiple column families",
+					table.GetName(), idx.GetName())
+			}
+		}
+	}
+	return predicate, nil
+}
+
+// resolveRestoreColumns returns the IDs of the columns to restore, which are
+// the columns named by the columns option along with the primary key and
+// hidden columns of the table.
+func resolveRestoreColumns(
+	ctx context.Context,
+	exprEval *exprutil.Evaluator,
+	columns tree.StringOrPlaceholderOptList,
+	table catalog.TableDescriptor,
+) ([]descpb.ColumnID, error) {
+	names, err := exprEval.StringArray(ctx, tree.Exprs(columns))
+	if err != nil {
+		return nil, err
+	}
+	keep := table.GetPrimaryIndex().CollectKeyColumnIDs()
+	for _, name := range names {
+		col := catalog.FindColumnByName(table, name)
+		if col == nil || !col.Public() {
+			return nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q does not exist in table %q", name, table.GetName())
+		}
+		keep.Add(col.GetID())
+	}
+	for _, col := range table.PublicColumns() {
+		if col.IsHidden() || col.IsInaccessible() {
+			keep.Add(col.GetID())
+		}
+	}
+
+	required, err := columnsRequiredByRestoredTable(table, keep)
+	if err != nil {
+		return nil, err
+	}
+	for _, col := range table.PublicColumns() {
+		if keep.Contains(col.GetID()) {
+			continue
+		}
+		if reason, ok := required[col.GetID()]; ok {
+			return nil, pgerror.Newf(pgcode.DependentObjectsStillExist,
+				"column %q must be restored because it is %s", col.GetName(), reason)
+		}
+	}
+	return keep.Ordered(), nil
+}
+
+// columnsRequiredByRestoredTable returns the columns that cannot be removed
+// from the table, along with the reason why, if the columns in keep are
+// restored.
+func columnsRequiredByRestoredTable(
+	table catalog.TableDescriptor, keep catalog.TableColSet,
+) (map[descpb.ColumnID]string, error) {
+	required := make(map[descpb.ColumnID]string)
+	add := func(cols catalog.TableColSet, reason string) {
+		cols.ForEach(func(colID descpb.ColumnID) {
+			if _, ok := required[colID]; !ok {
+				required[colID] = reason
+			}
+		})
+	}
+	addExpr := func(expr string, reason string) error {
+		parsed, err := parser.ParseExpr(expr)
+		if err != nil {
+			return err
+		}
+		cols, err := schemaexpr.ExtractColumnIDs(table, parsed)
+		if err != nil {
+			return err
+		}
+		add(cols, reason)
+		return nil
+	}
+
+	for _, idx := range table.ActiveIndexes() {
+		reason := "indexed by " + tree.NameString(idx.GetName())
+		add(idx.CollectKeyColumnIDs(), reason)
+		add(idx.CollectKeySuffixColumnIDs(), reason)
+		add(idx.CollectSecondaryStoredColumnIDs(), reason)
+		if idx.IsPartial() {
+			if err := addExpr(idx.GetPredicate(), reason); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, col := range table.PublicColumns() {
+		if col.IsComputed() && keep.Contains(col.GetID()) {
+			if err := addExpr(
+				col.GetComputeExpr(), "used by computed column "+tree.NameString(col.GetName()),
+			); err != nil {
+				return nil, err
+			}
+		}
+		if col.NumOwnsSequences() > 0 {
+			required[col.GetID()] = "the owner of a sequence"
+		}
+	}
+	for _, c := range table.CheckConstraints() {
+		add(c.CollectReferencedColumnIDs(), "used by constraint "+tree.NameString(c.GetName()))
+	}
+	for _, c := range table.OutboundForeignKeys() {
+		add(c.CollectOriginColumnIDs(), "used by constraint "+tree.NameString(c.GetName()))
+	}
+	for _, c := range table.InboundForeignKeys() {
+		add(c.CollectReferencedColumnIDs(), "used by constraint "+tree.NameString(c.GetName()))
+	}
+	for _, c := range table.UniqueConstraintsWithoutIndex() {
+		reason := "used by constraint " + tree.NameString(c.GetName())
+		add(c.CollectKeyColumnIDs(), reason)
+		if c.IsPartial() {
+			if err := addExpr(c.GetPredicate(), reason); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, c := range table.ExclusionConstraints() {
+		reason := "used by constraint " + tree.NameString(c.GetName())
+		add(c.CollectKeyColumnIDs(), reason)
+		if c.IsPartial() {
+			if err := addExpr(c.GetPredicate(), reason); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if ttl := table.GetRowLevelTTL(); ttl != nil && ttl.HasExpirationExpr() {
+		if err := addExpr(string(ttl.ExpirationExpr), "used by the row-level TTL"); err != nil {
+			return nil, err
+		}
+	}
+	return required, nil
+}
+
+// familyOfColumn returns the ID of the column family that stores the column.
+func familyOfColumn(table catalog.TableDescriptor, colID descpb.ColumnID) descpb.FamilyID {
+	var familyID descpb.FamilyID
+	_ = table.ForeachFamily(func(family *descpb.ColumnFamilyDescriptor) error {
+		for _, id := range family.ColumnIDs {
+			if id == colID {
+				familyID = family.ID
+				return iterutil.StopIteration()
+			}
+		}
+		return nil
+	})
+	return familyID
+}
+
+// projectRestoredTableColumns removes the public columns of the table that are
+// not in columnIDs, and returns the IDs of the removed columns. Column families
+// that are left without columns, except for the first family, are removed
+// along with them.
+func projectRestoredTableColumns(
+	table *tabledesc.Mutable, columnIDs []descpb.ColumnID,
+) []descpb.ColumnID {
+	if len(columnIDs) == 0 {
+		return nil
+	}
+	keep := catalog.MakeTableColSet(columnIDs...)
+	var dropped []descpb.ColumnID
+	columns := table.Columns[:0]
+	for _, col := range table.Columns {
+		if keep.Contains(col.ID) {
+			columns = append(columns, col)
+			continue
+		}
+		dropped = append(dropped, col.ID)
+	}
+	table.Columns = columns
+	for _, colID := range dropped {
+		table.RemoveColumnFromFamilyAndPrimaryIndex(colID)
+		for i := range table.Families {
+			if table.Families[i].DefaultColumnID == colID {
+				table.Families[i].DefaultColumnID = 0
+			}
+		}
+	}
+	return dropped
+}
+
+// narrowSpansForRowFilter narrows the primary index span of the table in spans
+// to the rows whose leading primary key columns are constrained to constants by
+// equalities in the conjunction of the predicate. The table and spans are those
+// of the backup's keyspace.
+func narrowSpansForRowFilter(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	codec keys.SQLCodec,
+	table catalog.TableDescriptor,
+	predicate string,
+	spans []roachpb.Span,
+) ([]roachpb.Span, error) {
+	semaCtx := tree.MakeSemaContext()
+	expr, _, err := schemaexpr.MakePredicateExpr(ctx, table, predicate, evalCtx, &semaCtx)
+	if err != nil {
+		return nil, err
+	}
+	constants := make(map[descpb.ColumnID]tree.Datum)
+	collectEqualityConstants(expr, table.PublicColumns(), constants)
+
+	pk := table.GetPrimaryIndex()
+	prefix := codec.IndexPrefix(uint32(table.GetID()), uint32(pk.GetID()))
+	var constrained int
+	for ; constrained < pk.NumKeyColumns(); constrained++ {
+		d, ok := constants[pk.GetKeyColumnID(constrained)]
+		if !ok {
+			break
+		}
+		dir, err := catalogkeys.IndexColumnEncodingDirection(pk.GetKeyColumnDirection(constrained))
+		if err != nil {
+			return nil, err
+		}
+		if prefix, err = keyside.Encode(prefix, d, dir); err != nil {
+			return nil, err
+		}
+	}
+	if constrained == 0 {
+		return spans, nil
+	}
+
+	var g roachpb.SpanGroup
+	g.Add(spans...)
+	pkSpan := table.IndexSpan(codec, pk.GetID())
+	if !g.Encloses(pkSpan) {
+		return spans, nil
+	}
+	g.Sub(pkSpan)
+	g.Add(roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+	return g.Slice(), nil
+}
+
+// collectEqualityConstants adds the columns that the conjunction of expr
+// constrains to be equal to non-NULL constants to constants.
+func collectEqualityConstants(
+	expr tree.TypedExpr, cols []catalog.Column, constants map[descpb.ColumnID]tree.Datum,
+) {
+	switch t := expr.(type) {
+	case *tree.AndExpr:
+		collectEqualityConstants(t.TypedLeft(), cols, constants)
+		collectEqualityConstants(t.TypedRight(), cols, constants)
+	case *tree.ParenExpr:
+		collectEqualityConstants(t.TypedInnerExpr(), cols, constants)
+	case *tree.ComparisonExpr:
+		if t.Operator.Symbol != treecmp.EQ {
+			return
+		}
+		left, right := t.TypedLeft(), t.TypedRight()
+		if _, ok := right.(*tree.IndexedVar); ok {
+			left, right = right, left
+		}
+		v, ok := left.(*tree.IndexedVar)
+		if !ok {
+			return
+		}
+		d, ok := right.(tree.Datum)
+		if !ok || d == tree.DNull {
+			return
+		}
+		if col := cols[v.Idx]; d.ResolvedType().Identical(col.GetType()) {
+			constants[col.GetID()] = d
+		}
+	}
+}
+
+// restoreRowFilter decides which keys of the table described by a
+// RestoreRowFilter a restore data processor ingests, and strips the values of
+// the columns that are not restored from them. The keys passed to it must have
+// been rewritten into the restoring cluster's keyspace. It is not safe for
+// concurrent use.
+type restoreRowFilter struct {
+	codec   keys.SQLCodec
+	table   catalog.TableDescriptor
+	dropped catalog.TableColSet
+
+	// predicate is nil if the rows are not filtered.
+	predicate tree.TypedExpr
+	evalCtx   *eval.Context
+	// fetchColumnIDs are the columns referenced by the predicate.
+	fetchColumnIDs []descpb.ColumnID
+	fetchers       map[descpb.IndexID]*row.Fetcher
+	kvs            [1]roachpb.KeyValue
+	alloc          tree.DatumAlloc
+	ivars          schemaexpr.RowIndexedVarContainer
+}
+
+// makeRestoreRowFilter returns a restoreRowFilter for the given spec, or nil if
+// spec is nil.
+func makeRestoreRowFilter(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	spec *execinfrapb.RestoreRowFilter,
+	rekeys []execinfrapb.TableRekey,
+) (*restoreRowFilter, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	var table catalog.TableDescriptor
+	for _, rekey := range rekeys {
+		if rekey.OldID == 0 {
+			continue
+		}
+		var desc descpb.Descriptor
+		if err := protoutil.Unmarshal(rekey.NewDesc, &desc); err != nil {
+			return nil, errors.Wrapf(err, "unmarshalling rekey descriptor for old table id %d", rekey.OldID)
+		}
+		if t, _, _, _, _ := descpb.GetDescriptors(&desc); t != nil && t.ID == spec.TableID {
+			table = tabledesc.NewBuilder(t).BuildImmutableTable()
+			break
+		}
+	}
+	if table == nil {
+		return nil, errors.AssertionFailedf("no rekey found for filtered table %d", spec.TableID)
+	}
+
+	rf := &restoreRowFilter{
+		codec:   flowCtx.Codec(),
+		table:   table,
+		dropped: catalog.MakeTableColSet(spec.DroppedColumnIDs...),
+	}
+	if spec.Predicate != "" {
+		rf.evalCtx = flowCtx.NewEvalCtx()
+		semaCtx := tree.MakeSemaContext()
+		expr, colIDs, err := schemaexpr.MakePredicateExpr(ctx, table, spec.Predicate, rf.evalCtx, &semaCtx)
+		if err != nil {
+			return nil, err
+		}
+		rf.predicate = expr
+		rf.fetchColumnIDs = colIDs.Ordered()
+		rf.fetchers = make(map[descpb.IndexID]*row.Fetcher)
+		rf.ivars.Cols = table.PublicColumns()
+		for i, colID := range rf.fetchColumnIDs {
+			rf.ivars.Mapping.Set(colID, i)
+		}
+	}
+	return rf, nil
+}
+
+// filterKV returns whether the key should be ingested. The value may be
+// modified to strip the columns that are not restored.
+func (rf *restoreRowFilter) filterKV(
+	ctx context.Context, key roachpb.Key, value *roachpb.Value,
+) (bool, error) {
+	_, tableID, indexID, err := rf.codec.DecodeIndexPrefix(key)
+	if err != nil || descpb.ID(tableID) != rf.table.GetID() {
+		// Keys of other tables are not filtered.
+		return true, nil //nolint:returnerrcheck
+	}
+	if len(value.RawBytes) == 0 {
+		// There is no point in ingesting a deletion tombstone into the new table.
+		return false, nil
+	}
+	// Project the entry first so that the predicate is evaluated against the
+	// shape of the new table descriptor.
+	if !rf.dropped.Empty() && descpb.IndexID(indexID) == rf.table.GetPrimaryIndexID() {
+		if ok, err := rf.projectValue(key, value); err != nil || !ok {
+			return false, err
+		}
+	}
+	if rf.predicate != nil {
+		return rf.evalPredicate(ctx, descpb.IndexID(indexID), key, value)
+	}
+	return true, nil
+}
+
+// evalPredicate decodes the columns referenced by the predicate from the given
+// index entry and evaluates the predicate over them.
+func (rf *restoreRowFilter) evalPredicate(
+	ctx context.Context, indexID descpb.IndexID, key roachpb.Key, value *roachpb.Value,
+) (bool, error) {
+	fetcher, ok := rf.fetchers[indexID]
+	if !ok {
+		idx, err := catalog.MustFindIndexByID(rf.table, indexID)
+		if err != nil {
+			return false, err
+		}
+		var spec fetchpb.IndexFetchSpec
+		if err := rowenc.InitIndexFetchSpec(&spec, rf.codec, rf.table, idx, rf.fetchColumnIDs); err != nil {
+			return false, err
+		}
+		fetcher = &row.Fetcher{}
+		if err := fetcher.Init(ctx, row.FetcherInitArgs{
+			WillUseKVProvider: true,
+			Alloc:             &rf.alloc,
+			Spec:              &spec,
+		}); err != nil {
+			return false, err
+		}
+		rf.fetchers[indexID] = fetcher
+	}
+
+	rf.kvs[0] = roachpb.KeyValue{Key: key, Value: *value}
+	if err := fetcher.ConsumeKVProvider(ctx, &row.KVProvider{KVs: rf.kvs[:]}); err != nil {
+		return false, err
+	}
+	datums, err := fetcher.NextRowDecoded(ctx)
+	if err != nil || datums == nil {
+		return false, err
+	}
+
+	rf.ivars.CurSourceRow = datums
+	rf.evalCtx.PushIVarContainer(&rf.ivars)
+	res, err := eval.Expr(ctx, rf.evalCtx, rf.predicate)
+	rf.evalCtx.PopIVarContainer()
+	if err != nil {
+		return false, err
+	}
+	return res == tree.DBoolTrue, nil
+}
+
+// projectValue strips the values of the columns that are not restored from a
+// primary index entry. It returns false if the entry belongs to a column family
+// that is not restored.
+func (rf *restoreRowFilter) projectValue(key roachpb.Key, value *roachpb.Value) (bool, error) {
+	familyID, err := keys.DecodeFamilyKey(key)
+	if err != nil {
+		return false, err
+	}
+	var family *descpb.ColumnFamilyDescriptor
+	for i, f := range rf.table.GetFamilies() {
+		if f.ID == descpb.FamilyID(familyID) {
+			family = &rf.table.GetFamilies()[i]
+			break
+		}
+	}
+	if family == nil {
+		return false, nil
+	}
+	if family.DefaultColumnID != 0 {
+		return true, nil
+	}
+	if value.GetTag() != roachpb.ValueType_TUPLE {
+		// The family used to consist of a single column, which is not restored.
+		value.SetTuple(nil)
+		return true, nil
+	}
+	tuple, err := value.GetTuple()
+	if err != nil {
+		return false, err
+	}
+	projected, err := stripColumnsFromTuple(tuple, rf.dropped)
+	if err != nil {
+		return false, err
+	}
+	value.SetTuple(projected)
+	return true, nil
+}
+
+// stripColumnsFromTuple returns the value encoding of a column family with the
+// given columns removed.
+func stripColumnsFromTuple(tuple []byte, dropped catalog.TableColSet) ([]byte, error) {
+	var out []byte
+	var colID, lastColID descpb.ColumnID
+	for len(tuple) > 0 {
+		_, dataOffset, colIDDelta, typ, err := encoding.DecodeValueTag(tuple)
+		if err != nil {
+			return nil, err
+		}
+		n, err := encoding.PeekValueLengthWithOffsetsAndType(tuple, dataOffset, typ)
+		if err != nil {
+			return nil, err
+		}
+		colID += descpb.ColumnID(colIDDelta)
+		if !dropped.Contains(colID) {
+			out = encoding.EncodeValueTag(out, uint32(colID-lastColID), typ)
+			out = append(out, tuple[dataOffset:n]...)
+			lastColID = colID
+		}
+		tuple = tuple[n:]
+	}
+	return out, nil
+}
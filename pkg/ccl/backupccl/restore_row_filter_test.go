@@ -0,0 +1,201 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/desctestutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreWithRowFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 0
+	tc, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `
+CREATE DATABASE d;
+CREATE TABLE d.t (
+  tenant_id INT,
+  id INT,
+  name STRING,
+  note STRING,
+  secret STRING,
+  PRIMARY KEY (tenant_id, id),
+  INDEX name_idx (name),
+  FAMILY f1 (tenant_id, id, name, note),
+  FAMILY f2 (secret)
+);
+INSERT INTO d.t SELECT t, i, 'name-' || i::STRING, 'note-' || i::STRING, 'secret-' || i::STRING
+  FROM generate_series(1, 5) AS t, generate_series(1, 20) AS i;
+`)
+	const collection = "nodelocal://1/filter"
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO $1`, collection)
+
+	t.Run("invalid", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE invalid`)
+		for _, tc := range []struct {
+			restore string
+			err     string
+		}{
+			{
+				restore: `RESTORE DATABASE d FROM LATEST IN $1 WITH new_db_name = 'invalid2', filter = 'tenant_id = 1'`,
+				err:     "can only be used with RESTORE TABLE",
+			},
+			{
+				restore: `RESTORE TABLE d.t FROM LATEST IN $1 WITH into_db = 'invalid', schema_only, filter = 'tenant_id = 1'`,
+				err:     "cannot be used with schema_only",
+			},
+			{
+				restore: `RESTORE TABLE d.t FROM LATEST IN $1 WITH into_db = 'invalid', filter = 'name = ''x'''`,
+				err:     `"name" is not part of the primary key`,
+			},
+			{
+				restore: `RESTORE TABLE d.t FROM LATEST IN $1 WITH into_db = 'invalid', filter = 'tenant_id'`,
+				err:     "expected RESTORE FILTER expression to have type bool",
+			},
+			{
+				restore: `RESTORE TABLE d.t FROM LATEST IN $1 WITH into_db = 'invalid', filter = 'tenant_id = random()::INT'`,
+				err:     "volatile functions are not allowed in RESTORE FILTER",
+			},
+			{
+				restore: `RESTORE TABLE d.t FROM LATEST IN $1 WITH into_db = 'invalid', columns = ('nope')`,
+				err:     `column "nope" does not exist`,
+			},
+			{
+				restore: `RESTORE TABLE d.t FROM LATEST IN $1 WITH into_db = 'invalid', columns = ('note')`,
+				err:     `column "name" must be restored because it is indexed by name_idx`,
+			},
+		} {
+			sqlDB.ExpectErr(t, tc.err, tc.restore, collection)
+		}
+	})
+
+	t.Run("filter-non-primary-key", func(t *testing.T) {
+		// A filter that mixes primary key and non-primary key columns is
+		// rejected as a whole, with a hint explaining the limitation.
+		_, err := sqlDB.DB.ExecContext(context.Background(),
+			`RESTORE TABLE d.t FROM LATEST IN $1 WITH into_db = 'invalid', filter = 'tenant_id = 1 AND note = ''note-1'''`,
+			collection)
+		var pqErr *pq.Error
+		require.True(t, errors.As(err, &pqErr), "expected a pq error, got %v", err)
+		require.Equal(t, pgcode.FeatureNotSupported.String(), string(pqErr.Code))
+		require.Contains(t, pqErr.Message, `"note" is not part of the primary key of "t"`)
+		require.Contains(t, pqErr.Hint, "only the primary key of a row is encoded in each of its keys")
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM [SHOW TABLES FROM invalid]`, [][]string{{"0"}})
+	})
+
+	t.Run("filter-and-columns", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE scratch`)
+		sqlDB.Exec(t, `RESTORE TABLE d.t FROM LATEST IN $1 WITH into_db = 'scratch',
+filter = 'tenant_id = 3', columns = ('name')`, collection)
+
+		sqlDB.CheckQueryResults(t,
+			`SELECT column_name FROM [SHOW COLUMNS FROM scratch.t] ORDER BY column_name`,
+			[][]string{{"id"}, {"name"}, {"tenant_id"}},
+		)
+		expected := sqlDB.QueryStr(t, `SELECT tenant_id, id, name FROM d.t WHERE tenant_id = 3 ORDER BY id`)
+		require.Len(t, expected, 20)
+		sqlDB.CheckQueryResults(t, `SELECT * FROM scratch.t ORDER BY id`, expected)
+		sqlDB.CheckQueryResults(t, `SELECT tenant_id, id, name FROM scratch.t@name_idx ORDER BY id`, expected)
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM crdb_internal.invalid_objects`, [][]string{{"0"}})
+
+		// The restored table is fully usable.
+		sqlDB.Exec(t, `INSERT INTO scratch.t VALUES (3, 21, 'name-21')`)
+		sqlDB.Exec(t, `ALTER TABLE scratch.t ADD COLUMN note STRING DEFAULT 'new'`)
+		sqlDB.CheckQueryResults(t, `SELECT DISTINCT note FROM scratch.t`, [][]string{{"new"}})
+	})
+
+	t.Run("filter-outside-primary-key-prefix", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE scratch2`)
+		sqlDB.Exec(t, `RESTORE TABLE d.t FROM LATEST IN $1 WITH into_db = 'scratch2', filter = 'id <= 2'`,
+			collection)
+		sqlDB.CheckQueryResults(t, `SELECT * FROM scratch2.t ORDER BY tenant_id, id`,
+			sqlDB.QueryStr(t, `SELECT * FROM d.t WHERE id <= 2 ORDER BY tenant_id, id`))
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM scratch2.t@name_idx`, [][]string{{"10"}})
+	})
+
+	t.Run("narrow-spans", func(t *testing.T) {
+		srv := tc.ApplicationLayer(0)
+		codec := srv.Codec()
+		table := desctestutils.TestingGetPublicTableDescriptor(srv.DB(), codec, "d", "t")
+		evalCtx := eval.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+		defer evalCtx.Stop(context.Background())
+
+		pkSpan := func(prefixes ...int64) roachpb.Span {
+			key := codec.IndexPrefix(uint32(table.GetID()), uint32(table.GetPrimaryIndexID()))
+			for _, p := range prefixes {
+				key = encoding.EncodeVarintAscending(key, p)
+			}
+			return roachpb.Span{Key: key, EndKey: key.PrefixEnd()}
+		}
+		secondarySpan := func(idx catalog.Index) roachpb.Span {
+			return table.IndexSpan(codec, idx.GetID())
+		}(table.PublicNonPrimaryIndexes()[0])
+		allSpans := []roachpb.Span{table.PrimaryIndexSpan(codec), secondarySpan}
+
+		for _, tc := range []struct {
+			predicate string
+			expected  []roachpb.Span
+		}{
+			{predicate: "tenant_id = 3", expected: []roachpb.Span{pkSpan(3), secondarySpan}},
+			{predicate: "3 = tenant_id AND id = 7", expected: []roachpb.Span{pkSpan(3, 7), secondarySpan}},
+			{predicate: "tenant_id = 3 AND id > 7", expected: []roachpb.Span{pkSpan(3), secondarySpan}},
+			{predicate: "id = 7", expected: allSpans},
+			{predicate: "tenant_id = 3 OR tenant_id = 4", expected: allSpans},
+		} {
+			t.Run(tc.predicate, func(t *testing.T) {
+				spans, err := narrowSpansForRowFilter(
+					context.Background(), evalCtx, codec, table, tc.predicate, allSpans,
+				)
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, spans)
+			})
+		}
+	})
+}
+
+func TestStripColumnsFromTuple(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// The tuple encodes columns 1, 3, 5 and 6, each as a delta from the
+	// previous column ID.
+	var tuple []byte
+	tuple = encoding.EncodeIntValue(tuple, 1, 10)
+	tuple = encoding.EncodeBytesValue(tuple, 2, []byte("two"))
+	tuple = encoding.EncodeIntValue(tuple, 2, 40)
+	tuple = encoding.EncodeNullValue(tuple, 1)
+
+	stripped, err := stripColumnsFromTuple(tuple, catalog.MakeTableColSet(3))
+	require.NoError(t, err)
+	var expected []byte
+	expected = encoding.EncodeIntValue(expected, 1, 10)
+	expected = encoding.EncodeIntValue(expected, 4, 40)
+	expected = encoding.EncodeNullValue(expected, 1)
+	require.Equal(t, expected, stripped)
+
+	stripped, err = stripColumnsFromTuple(tuple, catalog.MakeTableColSet(1, 3, 5, 6))
+	require.NoError(t, err)
+	require.Empty(t, stripped)
+}
@@ -10,6 +10,7 @@ package backupccl
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
@@ -23,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
@@ -200,6 +202,7 @@ func (e *scheduledBackupExecutor) GetCreateScheduleStatement(
 
 	recurrence := sj.ScheduleExpr()
 	fullBackup := &tree.FullBackupClause{AlwaysFull: true}
+	retentionPolicy := args.RetentionPolicy
 
 	// Check if sj has a dependent full or incremental schedule associated with it.
 	var dependentSchedule *jobs.ScheduledJob
@@ -217,6 +220,12 @@ func (e *scheduledBackupExecutor) GetCreateScheduleStatement(
 		// schedules recurrence.
 		if backupNode.AppendToLatest {
 			fullBackup.Recurrence = tree.NewDString(dependentSchedule.ScheduleExpr())
+			// The retention policy is stored on the full schedule.
+			fullArgs := &backuppb.ScheduledBackupExecutionArgs{}
+			if err := pbtypes.UnmarshalAny(dependentSchedule.ExecutionArgs().Args, fullArgs); err != nil {
+				return "", errors.Wrap(err, "un-marshaling args")
+			}
+			retentionPolicy = fullArgs.RetentionPolicy
 		} else {
 			// If sj refers to the full schedule, then the dependentSchedule refers to
 			// the incremental schedule that was created as a child of sj. In this
@@ -284,6 +293,20 @@ func (e *scheduledBackupExecutor) GetCreateScheduleStatement(
 			Value: tree.NewDString(wait),
 		},
 	}
+	if policy := retentionPolicy; policy != nil {
+		if policy.Retention != 0 {
+			scheduleOptions = append(scheduleOptions, tree.KVOption{
+				Key:   optRetention,
+				Value: tree.NewDString(duration.MakeDuration(int64(policy.Retention), 0, 0).String()),
+			})
+		}
+		if policy.KeepFulls != 0 {
+			scheduleOptions = append(scheduleOptions, tree.KVOption{
+				Key:   optKeepFulls,
+				Value: tree.NewDString(strconv.Itoa(int(policy.KeepFulls))),
+			})
+		}
+	}
 
 	var destinations []string
 	for i := range backupNode.To {
@@ -0,0 +1,318 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"golang.org/x/exp/slices"
+)
+
+// The retention policy of a backup schedule is enforced by the backup jobs of
+// the full backup schedule, each time one of them successfully starts a new
+// backup chain. Nothing is deleted if the new full backup fails, so the
+// collection never loses a chain before its replacement is restorable. A
+// chain, i.e. a full backup along with all of the incremental backups appended
+// to it, is only ever deleted as a whole, and only once no restore target that
+// the policy promises to keep depends on it:
+//
+//   - keep_fulls = N retains the N most recent chains in the collection.
+//   - retention = <interval> retains every chain required to restore to any
+//     time in the trailing interval. Since a restore to time t is served by the
+//     newest chain whose full backup ended at or before t, a chain may only be
+//     deleted once the chain that follows it ended before the interval began.
+//
+// The chain that the LATEST file points to, and any chain newer than it, is
+// never deleted, and neither is any subdirectory of the collection whose name
+// was not chosen by BACKUP INTO.
+//
+// Deletion is idempotent: the expired chains are computed from the contents of
+// the collection as of the end time of the new full backup, so a job that is
+// retried after having deleted some of them deletes the rest and records all of
+// them.
+
+// backupChain is a full backup subdirectory in a collection along with the end
+// time of the full backup, as encoded in the subdirectory's name.
+type backupChain struct {
+	subdir  string
+	endTime time.Time
+}
+
+// parseBackupChainSubdir returns the end time encoded in a full backup
+// subdirectory name, or false if the subdirectory was not named by BACKUP INTO.
+func parseBackupChainSubdir(subdir string) (time.Time, bool) {
+	t, err := time.Parse(backupbase.DateBasedIntoFolderName, "/"+strings.Trim(subdir, "/"))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// expiredBackupChains returns the full backup subdirectories, out of those in
+// fullBackups, whose chains may be deleted under the retention policy at the
+// time now. latest is the subdirectory that the collection's LATEST file points
+// to.
+func expiredBackupChains(
+	fullBackups []string,
+	latest string,
+	policy *backuppb.ScheduledBackupExecutionArgs_RetentionPolicy,
+	now time.Time,
+) []string {
+	if policy == nil || (policy.Retention == 0 && policy.KeepFulls == 0) {
+		return nil
+	}
+	latestEndTime, ok := parseBackupChainSubdir(latest)
+	if !ok {
+		// We cannot tell which chains are older than the one being appended to,
+		// so we do not touch anything.
+		return nil
+	}
+
+	chains := make([]backupChain, 0, len(fullBackups))
+	for _, subdir := range fullBackups {
+		if endTime, ok := parseBackupChainSubdir(subdir); ok {
+			chains = append(chains, backupChain{subdir: subdir, endTime: endTime})
+		}
+	}
+	sort.Slice(chains, func(i, j int) bool {
+		return chains[i].endTime.Before(chains[j].endTime)
+	})
+
+	var expired []string
+	for i := 0; i+1 < len(chains); i++ {
+		if !chains[i].endTime.Before(latestEndTime) {
+			break
+		}
+		if policy.KeepFulls > 0 && len(chains)-i <= int(policy.KeepFulls) {
+			break
+		}
+		if policy.Retention > 0 && chains[i+1].endTime.After(now.Add(-policy.Retention)) {
+			break
+		}
+		expired = append(expired, chains[i].subdir)
+	}
+	return expired
+}
+
+// deleteExpiredBackupChains deletes the backup chains in the collection that
+// the full backup described by details was written to which have expired under
+// the retention policy of the backup schedule that created the job. It must
+// only be called once the full backup has succeeded and the LATEST file points
+// to it. It returns the subdirectories of the full backups that were deleted.
+func deleteExpiredBackupChains(
+	ctx context.Context, p sql.JobExecContext, details jobspb.BackupDetails,
+) ([]string, error) {
+	execCfg := p.ExecCfg()
+	env := scheduledjobs.ProdJobSchedulerEnv
+	if knobs := execCfg.JobsKnobs(); knobs != nil && knobs.JobSchedulerEnv != nil {
+		env = knobs.JobSchedulerEnv
+	}
+	exprEval := exprutil.MakeEvaluator("BACKUP", p.SemaCtx(), &p.ExtendedEvalContext().Context)
+
+	var policy *backuppb.ScheduledBackupExecutionArgs_RetentionPolicy
+	var destinations, explicitIncCollections []string
+	if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		sj, args, err := getScheduledBackupExecutionArgsFromSchedule(
+			ctx, env, jobs.ScheduledJobTxn(txn), details.ScheduleID,
+		)
+		if err != nil {
+			if jobs.HasScheduledJobNotFoundError(err) {
+				// The schedule was dropped while the backup was running.
+				return nil
+			}
+			return err
+		}
+		if args.BackupType != backuppb.ScheduledBackupExecutionArgs_FULL {
+			return nil
+		}
+		policy = args.RetentionPolicy
+		if policy == nil {
+			return nil
+		}
+		// The job details only retain the resolved destination of the new
+		// backup, so the collections are read from the schedule's statement.
+		backupStmt, err := extractBackupStatement(sj)
+		if err != nil {
+			return err
+		}
+		destinations, err = exprEval.StringArray(ctx, tree.Exprs(backupStmt.To))
+		if err != nil {
+			return err
+		}
+		explicitIncCollections, err = incrementalStorageOfDependentSchedule(ctx, exprEval, env, txn, args)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return nil, nil
+	}
+
+	collectionURI, urisByLocalityKV, err := backupdest.GetURIsByLocalityKV(destinations, "")
+	if err != nil {
+		return nil, err
+	}
+
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+	latest, err := backupdest.ReadLatestFile(ctx, collectionURI, mkStore, p.User())
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := mkStore(ctx, collectionURI, p.User())
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	fullBackups, err := backupdest.ListFullBackupsInCollection(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+	expired := expiredBackupChains(fullBackups, latest, policy, details.EndTime.GoTime())
+	if len(expired) == 0 {
+		return nil, nil
+	}
+
+	collections := []string{collectionURI}
+	for _, uri := range urisByLocalityKV {
+		collections = append(collections, uri)
+	}
+	incCollections, err := backuputils.AppendPaths(collections, backupbase.DefaultIncrementalsSubdir)
+	if err != nil {
+		return nil, err
+	}
+	incCollections = append(incCollections, explicitIncCollections...)
+
+	deleted := make([]string, 0, len(expired))
+	for _, subdir := range expired {
+		// The incremental backups are deleted before the full backup they are
+		// appended to, so that a chain whose deletion is interrupted remains
+		// listed in the collection and is deleted by the next attempt.
+		for _, dirs := range [][]string{incCollections, collections} {
+			uris, err := backuputils.AppendPaths(dirs, subdir)
+			if err != nil {
+				return deleted, err
+			}
+			for _, uri := range uris {
+				if err := deleteBackupDirectory(ctx, mkStore, p.User(), uri); err != nil {
+					return deleted, errors.Wrapf(err, "deleting expired backup %s", subdir)
+				}
+			}
+		}
+		log.Infof(ctx, "backup schedule %d deleted expired backup %s", details.ScheduleID, subdir)
+		deleted = append(deleted, subdir)
+	}
+	return deleted, nil
+}
+
+// incrementalStorageOfDependentSchedule returns the incremental_location of the
+// incremental backup schedule that appends to the backups taken by the full
+// backup schedule with the given args, if any.
+func incrementalStorageOfDependentSchedule(
+	ctx context.Context,
+	exprEval exprutil.Evaluator,
+	env scheduledjobs.JobSchedulerEnv,
+	txn isql.Txn,
+	args *backuppb.ScheduledBackupExecutionArgs,
+) ([]string, error) {
+	if args.DependentScheduleID == 0 {
+		return nil, nil
+	}
+	incSj, _, err := getScheduledBackupExecutionArgsFromSchedule(
+		ctx, env, jobs.ScheduledJobTxn(txn), args.DependentScheduleID,
+	)
+	if err != nil {
+		if jobs.HasScheduledJobNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	incStmt, err := extractBackupStatement(incSj)
+	if err != nil {
+		return nil, err
+	}
+	if incStmt.Options.IncrementalStorage == nil {
+		return nil, nil
+	}
+	return exprEval.StringArray(ctx, tree.Exprs(incStmt.Options.IncrementalStorage))
+}
+
+// deleteBackupDirectory deletes every file under uri. Manifests are deleted
+// last so that a partially deleted backup is still recognized as one.
+func deleteBackupDirectory(
+	ctx context.Context,
+	mkStore cloud.ExternalStorageFromURIFactory,
+	user username.SQLUsername,
+	uri string,
+) error {
+	store, err := mkStore(ctx, uri, user)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var files, manifests []string
+	if err := store.List(ctx, "", "", func(f string) error {
+		if strings.HasPrefix(path.Base(f), backupbase.BackupManifestName) {
+			manifests = append(manifests, f)
+		} else {
+			files = append(files, f)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, f := range append(files, manifests...) {
+		if err := store.Delete(ctx, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordDeletedExpiredBackups adds the backups deleted by the schedule's
+// retention policy to the progress of the backup job, keeping those recorded
+// by previous attempts of the job.
+func recordDeletedExpiredBackups(ctx context.Context, job *jobs.Job, deleted []string) error {
+	return job.NoTxn().Update(ctx, func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+		if err := md.CheckRunningOrReverting(); err != nil {
+			return err
+		}
+		progress := md.Progress.GetBackup()
+		if progress == nil {
+			return errors.AssertionFailedf("expected backup progress, found %T", md.Progress.Details)
+		}
+		for _, subdir := range deleted {
+			if !slices.Contains(progress.DeletedExpiredBackups, subdir) {
+				progress.DeletedExpiredBackups = append(progress.DeletedExpiredBackups, subdir)
+			}
+		}
+		ju.UpdateProgress(md.Progress)
+		return nil
+	})
+}
@@ -0,0 +1,250 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors/oserror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiredBackupChains(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	now := time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	subdir := func(daysAgo int) string {
+		return now.Add(-time.Duration(daysAgo) * day).Format(backupbase.DateBasedIntoFolderName)
+	}
+	// Weekly full backups, the latest of which was taken yesterday.
+	fulls := []string{subdir(29), subdir(1), subdir(22), subdir(8), subdir(15)}
+	latest := subdir(1)
+
+	for _, tc := range []struct {
+		name      string
+		fulls     []string
+		latest    string
+		retention time.Duration
+		keepFulls int32
+		expected  []string
+	}{
+		{
+			name:   "no-policy",
+			fulls:  fulls,
+			latest: latest,
+		},
+		{
+			name:      "keep-fulls",
+			fulls:     fulls,
+			latest:    latest,
+			keepFulls: 2,
+			expected:  []string{subdir(29), subdir(22), subdir(15)},
+		},
+		{
+			name:      "keep-more-fulls-than-exist",
+			fulls:     fulls,
+			latest:    latest,
+			keepFulls: 10,
+		},
+		{
+			// A restore to 10 days ago requires the chain started 15 days ago.
+			name:      "retention",
+			fulls:     fulls,
+			latest:    latest,
+			retention: 10 * day,
+			expected:  []string{subdir(29), subdir(22)},
+		},
+		{
+			name:      "retention-on-chain-boundary",
+			fulls:     fulls,
+			latest:    latest,
+			retention: 15 * day,
+			expected:  []string{subdir(29), subdir(22)},
+		},
+		{
+			name:      "retention-and-keep-fulls",
+			fulls:     fulls,
+			latest:    latest,
+			retention: 10 * day,
+			keepFulls: 4,
+			expected:  []string{subdir(29)},
+		},
+		{
+			name:      "never-delete-latest-or-newer",
+			fulls:     fulls,
+			latest:    subdir(15),
+			keepFulls: 1,
+			expected:  []string{subdir(29), subdir(22)},
+		},
+		{
+			name:      "ignore-foreign-subdirs",
+			fulls:     append([]string{"/manual/backup/dir"}, fulls...),
+			latest:    latest,
+			keepFulls: 4,
+			expected:  []string{subdir(29)},
+		},
+		{
+			name:      "unrecognized-latest",
+			fulls:     fulls,
+			latest:    "/manual/backup/dir",
+			keepFulls: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var policy *backuppb.ScheduledBackupExecutionArgs_RetentionPolicy
+			if tc.retention != 0 || tc.keepFulls != 0 {
+				policy = &backuppb.ScheduledBackupExecutionArgs_RetentionPolicy{
+					Retention: tc.retention,
+					KeepFulls: tc.keepFulls,
+				}
+			}
+			require.Equal(t, tc.expected, expiredBackupChains(tc.fulls, tc.latest, policy, now))
+		})
+	}
+}
+
+func TestScheduledBackupRetention(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	th, cleanup := newTestHelper(t)
+	defer cleanup()
+
+	th.sqlDB.Exec(t, `
+CREATE DATABASE db;
+CREATE TABLE db.t(a int);
+INSERT INTO db.t VALUES (1), (2), (3);
+`)
+	th.cfg.TestingKnobs.(*jobs.TestingKnobs).OverrideAsOfClause = func(clause *tree.AsOfClause, _ time.Time) {
+		expr, err := tree.MakeDTimestampTZ(th.cfg.DB.KV().Clock().PhysicalTime(), time.Microsecond)
+		require.NoError(t, err)
+		clause.Expr = expr
+	}
+
+	const collection = "nodelocal://1/retention"
+	listBackups := func() []string {
+		var subdirs []string
+		for _, row := range th.sqlDB.QueryStr(t, `SHOW BACKUPS IN $1`, collection) {
+			subdirs = append(subdirs, row[0])
+		}
+		return subdirs
+	}
+
+	// Create two chains in the collection, each with an incremental backup.
+	for i := 0; i < 2; i++ {
+		th.sqlDB.Exec(t, `BACKUP DATABASE db INTO $1`, collection)
+		th.sqlDB.Exec(t, `INSERT INTO db.t VALUES ($1)`, 10+i)
+		th.sqlDB.Exec(t, `BACKUP DATABASE db INTO LATEST IN $1`, collection)
+	}
+	chains := listBackups()
+	require.Len(t, chains, 2)
+
+	th.sqlDB.ExpectErr(t, "keep_fulls must be at least 1",
+		`CREATE SCHEDULE FOR BACKUP DATABASE db INTO $1 RECURRING '@hourly'
+WITH SCHEDULE OPTIONS ignore_existing_backups, keep_fulls = '0'`, collection)
+	th.sqlDB.ExpectErr(t, "invalid value for retention",
+		`CREATE SCHEDULE FOR BACKUP DATABASE db INTO $1 RECURRING '@hourly'
+WITH SCHEDULE OPTIONS ignore_existing_backups, retention = 'forever'`, collection)
+
+	schedules, err := th.createBackupSchedule(t,
+		`CREATE SCHEDULE FOR BACKUP DATABASE db INTO $1 RECURRING '@hourly' FULL BACKUP '@daily'
+WITH SCHEDULE OPTIONS ignore_existing_backups, keep_fulls = '2'`, collection)
+	require.NoError(t, err)
+	require.Len(t, schedules, 2)
+	full, inc := schedules[0], schedules[1]
+	if full.IsPaused() {
+		full, inc = inc, full
+	}
+
+	// The retention policy is shown on both schedules, and can be altered.
+	th.sqlDB.Exec(t, fmt.Sprintf(
+		`ALTER BACKUP SCHEDULE %d SET SCHEDULE OPTION retention = '2d'`, inc.ScheduleID()))
+	for _, id := range []int64{full.ScheduleID(), inc.ScheduleID()} {
+		createStmt := th.sqlDB.QueryStr(t, fmt.Sprintf(
+			`SELECT create_statement FROM [SHOW CREATE SCHEDULE %d]`, id))
+		require.Contains(t, createStmt[0][0], "keep_fulls = '2'")
+		require.Contains(t, createStmt[0][0], "retention = '48:00:00'")
+	}
+	th.sqlDB.Exec(t, fmt.Sprintf(
+		`ALTER BACKUP SCHEDULE %d SET SCHEDULE OPTION retention = '1us'`, inc.ScheduleID()))
+	full = th.loadSchedule(t, full.ScheduleID())
+
+	// A run of the full backup schedule whose backup fails does not delete
+	// anything from the collection.
+	th.sqlDB.Exec(t, `SET CLUSTER SETTING jobs.debug.pausepoints = 'backup.before.flow'`)
+	th.env.SetTime(full.NextRun().Add(time.Second))
+	require.NoError(t, th.executeSchedules())
+	var failedJobID jobspb.JobID
+	testutils.SucceedsSoon(t, func() error {
+		th.server.JobRegistry().(*jobs.Registry).TestingNudgeAdoptionQueue()
+		return th.sqlDB.DB.QueryRowContext(context.Background(),
+			`SELECT id FROM system.jobs WHERE status = $1 AND created_by_type = $2 AND created_by_id = $3`,
+			jobs.StatusPaused, jobs.CreatedByScheduledJobs, full.ScheduleID()).Scan(&failedJobID)
+	})
+	th.sqlDB.Exec(t, `SET CLUSTER SETTING jobs.debug.pausepoints = ''`)
+	th.sqlDB.Exec(t, `CANCEL JOB $1`, failedJobID)
+	jobutils.WaitForJobToCancel(t, th.sqlDB, failedJobID)
+	require.Equal(t, chains, listBackups())
+
+	// Run the full backup schedule again. Once its backup succeeds, it deletes
+	// the oldest chain along with its incremental backup, keeping the two most
+	// recent chains.
+	full = th.loadSchedule(t, full.ScheduleID())
+	th.env.SetTime(full.NextRun().Add(time.Second))
+	require.NoError(t, th.executeSchedules())
+	th.waitForSuccessfulScheduledJob(t, full.ScheduleID())
+
+	remaining := listBackups()
+	require.Len(t, remaining, 2)
+	require.Equal(t, chains[1], remaining[0])
+	require.NotContains(t, remaining, chains[0])
+	th.sqlDB.CheckQueryResults(t, fmt.Sprintf(
+		`SELECT count(DISTINCT end_time) FROM [SHOW BACKUP FROM '%s' IN '%s'] WHERE backup_type = 'incremental'`,
+		chains[1], collection), [][]string{{"1"}})
+	// No files of the deleted chain remain in the collection.
+	for _, dir := range []string{"", backupbase.DefaultIncrementalsSubdir} {
+		require.NoError(t, filepath.Walk(filepath.Join(th.iodir, "retention", dir, chains[0]),
+			func(path string, info os.FileInfo, err error) error {
+				if oserror.IsNotExist(err) {
+					return nil
+				}
+				require.NoError(t, err)
+				require.True(t, info.IsDir(), "unexpected file %s", path)
+				return nil
+			}))
+	}
+
+	// The deleted chain is recorded in the progress of the backup job.
+	var jobID jobspb.JobID
+	th.sqlDB.QueryRow(t,
+		`SELECT id FROM system.jobs WHERE status = $1 AND created_by_type = $2 AND created_by_id = $3`,
+		jobs.StatusSucceeded, jobs.CreatedByScheduledJobs, full.ScheduleID()).Scan(&jobID)
+	job, err := th.server.JobRegistry().(*jobs.Registry).LoadJob(context.Background(), jobID)
+	require.NoError(t, err)
+	progress := job.Progress()
+	deleted := progress.GetBackup().DeletedExpiredBackups
+	require.Len(t, deleted, 1)
+	require.Equal(t, strings.TrimPrefix(chains[0], "/"), strings.TrimPrefix(deleted[0], "/"))
+}
@@ -198,6 +198,7 @@ func showBackupTypeCheck(
 		},
 		exprutil.Strings{
 			backup.Path,
+			backup.DiffPath,
 			backup.Options.EncryptionPassphrase,
 			backup.Options.EncryptionInfoDir,
 			backup.Options.CheckConnectionTransferSize,
@@ -214,6 +215,9 @@ func showBackupTypeCheck(
 	if backup.Details == tree.BackupConnectionTest {
 		return true, cloudcheck.Header, nil
 	}
+	if backup.Details == tree.BackupDiffDetails {
+		return true, backupDiffHeader, nil
+	}
 	infoReader := getBackupInfoReader(p, backup)
 	return true, infoReader.header(), nil
 }
@@ -267,6 +271,10 @@ func showBackupPlanHook(
 		return cloudcheck.ShowCloudStorageTestPlanHook(ctx, p, loc, params)
 	}
 
+	if showStmt.Details == tree.BackupDiffDetails {
+		return showBackupDiffPlanHook(ctx, showStmt, p, exprEval)
+	}
+
 	if showStmt.Path == nil && showStmt.InCollection != nil {
 		collection, err := exprEval.StringArray(
 			ctx, tree.Exprs(showStmt.InCollection),
@@ -326,189 +334,14 @@ func showBackupPlanHook(
 			return err
 		}
 
-		fullyResolvedDest := dest
-		if subdir != "" {
-			if strings.EqualFold(subdir, backupbase.LatestFileName) {
-				subdir, err = backupdest.ReadLatestFile(ctx, dest[0],
-					p.ExecCfg().DistSQLSrv.ExternalStorageFromURI,
-					p.User())
-				if err != nil {
-					return errors.Wrap(err, "read LATEST path")
-				}
-			}
-			fullyResolvedDest, err = backuputils.AppendPaths(dest, subdir)
-			if err != nil {
-				return err
-			}
-		}
-		baseStores := make([]cloud.ExternalStorage, len(fullyResolvedDest))
-		for j := range fullyResolvedDest {
-			baseStores[j], err = p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, fullyResolvedDest[j], p.User())
-			if err != nil {
-				return errors.Wrapf(err, "make storage")
-			}
-			defer baseStores[j].Close()
-		}
-
-		// TODO(msbutler): put encryption resolution in helper function, hopefully shared with RESTORE
-
-		encStore := baseStores[0]
-		if showStmt.Options.EncryptionInfoDir != nil {
-			encDir, err := exprEval.String(ctx, showStmt.Options.EncryptionInfoDir)
-			if err != nil {
-				return err
-			}
-			encStore, err = p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, encDir, p.User())
-			if err != nil {
-				return errors.Wrap(err, "make storage")
-			}
-			defer encStore.Close()
-		}
-		var encryption *jobspb.BackupEncryptionOptions
-		kmsEnv := backupencryption.MakeBackupKMSEnv(
-			p.ExecCfg().Settings,
-			&p.ExecCfg().ExternalIODirConfig,
-			p.ExecCfg().InternalDB,
-			p.User(),
-		)
-		showEncErr := `If you are running SHOW BACKUP exclusively on an incremental backup,
-you must pass the 'encryption_info_dir' parameter that points to the directory of your full backup`
-		if showStmt.Options.EncryptionPassphrase != nil {
-			passphrase, err := exprEval.String(ctx, showStmt.Options.EncryptionPassphrase)
-			if err != nil {
-				return err
-			}
-			opts, err := backupencryption.ReadEncryptionOptions(ctx, encStore)
-			if errors.Is(err, backupencryption.ErrEncryptionInfoRead) {
-				return errors.WithHint(err, showEncErr)
-			}
-			if err != nil {
-				return err
-			}
-			encryptionKey := storageccl.GenerateKey([]byte(passphrase), opts[0].Salt)
-			encryption = &jobspb.BackupEncryptionOptions{
-				Mode: jobspb.EncryptionMode_Passphrase,
-				Key:  encryptionKey,
-			}
-		} else if showStmt.Options.DecryptionKMSURI != nil {
-			kms, err := exprEval.StringArray(ctx, tree.Exprs(showStmt.Options.DecryptionKMSURI))
-			if err != nil {
-				return err
-			}
-			opts, err := backupencryption.ReadEncryptionOptions(ctx, encStore)
-			if errors.Is(err, backupencryption.ErrEncryptionInfoRead) {
-				return errors.WithHint(err, showEncErr)
-			}
-			if err != nil {
-				return err
-			}
-			var defaultKMSInfo *jobspb.BackupEncryptionOptions_KMSInfo
-			for _, encFile := range opts {
-				defaultKMSInfo, err = backupencryption.ValidateKMSURIsAgainstFullBackup(
-					ctx,
-					kms,
-					backupencryption.NewEncryptedDataKeyMapFromProtoMap(encFile.EncryptedDataKeyByKMSMasterKeyID),
-					&kmsEnv,
-				)
-				if err == nil {
-					break
-				}
-			}
-			if err != nil {
-				return err
-			}
-			encryption = &jobspb.BackupEncryptionOptions{
-				Mode:    jobspb.EncryptionMode_KMS,
-				KMSInfo: defaultKMSInfo,
-			}
-		}
-		var explicitIncPaths []string
-		if showStmt.Options.IncrementalStorage != nil {
-			explicitIncPaths, err = exprEval.StringArray(ctx, tree.Exprs(showStmt.Options.IncrementalStorage))
-			if err != nil {
-				return err
-			}
-		}
-		collections, computedSubdir, err := backupdest.CollectionsAndSubdir(dest, subdir)
-		if err != nil {
-			return err
-		}
-		fullyResolvedIncrementalsDirectory, err := backupdest.ResolveIncrementalsBackupLocation(
-			ctx,
-			p.User(),
-			p.ExecCfg(),
-			explicitIncPaths,
-			collections,
-			computedSubdir,
-		)
-		if err != nil {
-			if errors.Is(err, cloud.ErrListingUnsupported) {
-				// We can proceed with base backups here just fine, so log a warning and move on.
-				// Note that actually _writing_ an incremental backup to this location would fail loudly.
-				log.Warningf(
-					ctx, "storage sink %v does not support listing, only showing the base backup", explicitIncPaths)
-			} else {
-				return err
-			}
-		}
 		mem := p.ExecCfg().RootMemoryMonitor.MakeBoundAccount()
 		defer mem.Close(ctx)
 
-		var (
-			info        backupInfo
-			memReserved int64
-		)
-		info.collectionURI = dest[0]
-		info.subdir = computedSubdir
-		info.kmsEnv = &kmsEnv
-		info.enc = encryption
-
-		mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
-		incStores, cleanupFn, err := backupdest.MakeBackupDestinationStores(ctx, p.User(), mkStore,
-			fullyResolvedIncrementalsDirectory)
-		if err != nil {
-			return err
-		}
-		defer func() {
-			if err := cleanupFn(); err != nil {
-				log.Warningf(ctx, "failed to close incremental store: %+v", err)
-			}
-		}()
-
-		info.defaultURIs, info.manifests, info.localityInfo, memReserved,
-			err = backupdest.ResolveBackupManifests(
-			ctx, &mem, baseStores, incStores, mkStore, fullyResolvedDest,
-			fullyResolvedIncrementalsDirectory, hlc.Timestamp{}, encryption, &kmsEnv, p.User())
-		defer func() {
-			mem.Shrink(ctx, memReserved)
-		}()
-		if err != nil {
-			if errors.Is(err, backupinfo.ErrLocalityDescriptor) && subdir == "" {
-				p.BufferClientNotice(ctx,
-					pgnotice.Newf("`SHOW BACKUP` using the old syntax ("+
-						"without the `IN` keyword) on a locality aware backup does not display or validate"+
-						" data specific to locality aware backups. "+
-						"Consider using the new `BACKUP INTO` syntax and `SHOW BACKUP"+
-						" FROM <backup> IN <collection>`"))
-			} else if errors.Is(err, cloud.ErrFileDoesNotExist) {
-				latestFileExists, errLatestFile := backupdest.CheckForLatestFileInCollection(ctx, baseStores[0])
-
-				if errLatestFile == nil && latestFileExists {
-					return errors.WithHintf(err, "The specified path is the root of a backup collection. "+
-						"Use SHOW BACKUPS IN with this path to list all the backup subdirectories in the"+
-						" collection. SHOW BACKUP can be used with any of these subdirectories to inspect a"+
-						" backup.")
-				}
-				return errors.CombineErrors(err, errLatestFile)
-			} else {
-				return err
-			}
-		}
-
-		info.layerToIterFactory, err = backupinfo.GetBackupManifestIterFactories(ctx, p.ExecCfg().DistSQLSrv.ExternalStorage, info.manifests, info.enc, info.kmsEnv)
+		info, cleanup, err := resolveShowBackupInfo(ctx, p, exprEval, showStmt, &mem, dest, subdir)
 		if err != nil {
 			return err
 		}
+		defer cleanup()
 
 		// If backup is locality aware, check that user passed at least some localities.
 
@@ -526,13 +359,14 @@ you must pass the 'encryption_info_dir' parameter that points to the directory o
 			}
 		}
 		if showStmt.Options.CheckFiles {
-			fileSizes, err := checkBackupFiles(ctx, info, p.ExecCfg(), p.User(), encryption, &kmsEnv)
+			fileSizes, err := checkBackupFiles(ctx, info, p.ExecCfg(), p.User(), info.enc, info.kmsEnv)
 			if err != nil {
 				return err
 			}
 			info.fileSizes = fileSizes
 		}
-		if err := infoReader.showBackup(ctx, &mem, mkStore, info, p.User(), &kmsEnv, resultsCh); err != nil {
+		mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
+		if err := infoReader.showBackup(ctx, &mem, mkStore, info, p.User(), info.kmsEnv, resultsCh); err != nil {
 			return err
 		}
 		if showStmt.InCollection == nil {
@@ -546,6 +380,215 @@ you must pass the 'encryption_info_dir' parameter that points to the directory o
 	return fn, infoReader.header(), nil, false, nil
 }
 
+// resolveShowBackupInfo resolves the manifests of the backup at subdir in the
+// given collection, or of the backup at dest if subdir is empty, along with the
+// encryption options needed to read them. The returned cleanup function
+// releases the stores and memory held by the backupInfo.
+func resolveShowBackupInfo(
+	ctx context.Context,
+	p sql.PlanHookState,
+	exprEval exprutil.Evaluator,
+	showStmt *tree.ShowBackup,
+	mem *mon.BoundAccount,
+	dest []string,
+	subdir string,
+) (_ backupInfo, _ func(), retErr error) {
+	var cleanupFns []func()
+	cleanup := func() {
+		for i := len(cleanupFns) - 1; i >= 0; i-- {
+			cleanupFns[i]()
+		}
+	}
+	defer func() {
+		if retErr != nil {
+			cleanup()
+		}
+	}()
+
+	var err error
+	var info backupInfo
+	fullyResolvedDest := dest
+	if subdir != "" {
+		if strings.EqualFold(subdir, backupbase.LatestFileName) {
+			subdir, err = backupdest.ReadLatestFile(ctx, dest[0],
+				p.ExecCfg().DistSQLSrv.ExternalStorageFromURI,
+				p.User())
+			if err != nil {
+				return backupInfo{}, nil, errors.Wrap(err, "read LATEST path")
+			}
+		}
+		fullyResolvedDest, err = backuputils.AppendPaths(dest, subdir)
+		if err != nil {
+			return backupInfo{}, nil, err
+		}
+	}
+	baseStores := make([]cloud.ExternalStorage, len(fullyResolvedDest))
+	for j := range fullyResolvedDest {
+		baseStores[j], err = p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, fullyResolvedDest[j], p.User())
+		if err != nil {
+			return backupInfo{}, nil, errors.Wrapf(err, "make storage")
+		}
+		store := baseStores[j]
+		cleanupFns = append(cleanupFns, func() { _ = store.Close() })
+	}
+
+	// TODO(msbutler): put encryption resolution in helper function, hopefully shared with RESTORE
+
+	encStore := baseStores[0]
+	if showStmt.Options.EncryptionInfoDir != nil {
+		encDir, err := exprEval.String(ctx, showStmt.Options.EncryptionInfoDir)
+		if err != nil {
+			return backupInfo{}, nil, err
+		}
+		encStore, err = p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, encDir, p.User())
+		if err != nil {
+			return backupInfo{}, nil, errors.Wrap(err, "make storage")
+		}
+		cleanupFns = append(cleanupFns, func() { _ = encStore.Close() })
+	}
+	var encryption *jobspb.BackupEncryptionOptions
+	kmsEnv := backupencryption.MakeBackupKMSEnv(
+		p.ExecCfg().Settings,
+		&p.ExecCfg().ExternalIODirConfig,
+		p.ExecCfg().InternalDB,
+		p.User(),
+	)
+	showEncErr := `If you are running SHOW BACKUP exclusively on an incremental backup,
+you must pass the 'encryption_info_dir' parameter that points to the directory of your full backup`
+	if showStmt.Options.EncryptionPassphrase != nil {
+		passphrase, err := exprEval.String(ctx, showStmt.Options.EncryptionPassphrase)
+		if err != nil {
+			return backupInfo{}, nil, err
+		}
+		opts, err := backupencryption.ReadEncryptionOptions(ctx, encStore)
+		if errors.Is(err, backupencryption.ErrEncryptionInfoRead) {
+			return backupInfo{}, nil, errors.WithHint(err, showEncErr)
+		}
+		if err != nil {
+			return backupInfo{}, nil, err
+		}
+		encryptionKey := storageccl.GenerateKey([]byte(passphrase), opts[0].Salt)
+		encryption = &jobspb.BackupEncryptionOptions{
+			Mode: jobspb.EncryptionMode_Passphrase,
+			Key:  encryptionKey,
+		}
+	} else if showStmt.Options.DecryptionKMSURI != nil {
+		kms, err := exprEval.StringArray(ctx, tree.Exprs(showStmt.Options.DecryptionKMSURI))
+		if err != nil {
+			return backupInfo{}, nil, err
+		}
+		opts, err := backupencryption.ReadEncryptionOptions(ctx, encStore)
+		if errors.Is(err, backupencryption.ErrEncryptionInfoRead) {
+			return backupInfo{}, nil, errors.WithHint(err, showEncErr)
+		}
+		if err != nil {
+			return backupInfo{}, nil, err
+		}
+		var defaultKMSInfo *jobspb.BackupEncryptionOptions_KMSInfo
+		for _, encFile := range opts {
+			defaultKMSInfo, err = backupencryption.ValidateKMSURIsAgainstFullBackup(
+				ctx,
+				kms,
+				backupencryption.NewEncryptedDataKeyMapFromProtoMap(encFile.EncryptedDataKeyByKMSMasterKeyID),
+				&kmsEnv,
+			)
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return backupInfo{}, nil, err
+		}
+		encryption = &jobspb.BackupEncryptionOptions{
+			Mode:    jobspb.EncryptionMode_KMS,
+			KMSInfo: defaultKMSInfo,
+		}
+	}
+	var explicitIncPaths []string
+	if showStmt.Options.IncrementalStorage != nil {
+		explicitIncPaths, err = exprEval.StringArray(ctx, tree.Exprs(showStmt.Options.IncrementalStorage))
+		if err != nil {
+			return backupInfo{}, nil, err
+		}
+	}
+	collections, computedSubdir, err := backupdest.CollectionsAndSubdir(dest, subdir)
+	if err != nil {
+		return backupInfo{}, nil, err
+	}
+	fullyResolvedIncrementalsDirectory, err := backupdest.ResolveIncrementalsBackupLocation(
+		ctx,
+		p.User(),
+		p.ExecCfg(),
+		explicitIncPaths,
+		collections,
+		computedSubdir,
+	)
+	if err != nil {
+		if errors.Is(err, cloud.ErrListingUnsupported) {
+			// We can proceed with base backups here just fine, so log a warning and move on.
+			// Note that actually _writing_ an incremental backup to this location would fail loudly.
+			log.Warningf(
+				ctx, "storage sink %v does not support listing, only showing the base backup", explicitIncPaths)
+		} else {
+			return backupInfo{}, nil, err
+		}
+	}
+	var memReserved int64
+	info.collectionURI = dest[0]
+	info.subdir = computedSubdir
+	info.kmsEnv = &kmsEnv
+	info.enc = encryption
+
+	mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
+	incStores, cleanupFn, err := backupdest.MakeBackupDestinationStores(ctx, p.User(), mkStore,
+		fullyResolvedIncrementalsDirectory)
+	if err != nil {
+		return backupInfo{}, nil, err
+	}
+	cleanupFns = append(cleanupFns, func() {
+		if err := cleanupFn(); err != nil {
+			log.Warningf(ctx, "failed to close incremental store: %+v", err)
+		}
+	})
+
+	info.defaultURIs, info.manifests, info.localityInfo, memReserved,
+		err = backupdest.ResolveBackupManifests(
+		ctx, mem, baseStores, incStores, mkStore, fullyResolvedDest,
+		fullyResolvedIncrementalsDirectory, hlc.Timestamp{}, encryption, &kmsEnv, p.User())
+	cleanupFns = append(cleanupFns, func() {
+		mem.Shrink(ctx, memReserved)
+	})
+	if err != nil {
+		if errors.Is(err, backupinfo.ErrLocalityDescriptor) && subdir == "" {
+			p.BufferClientNotice(ctx,
+				pgnotice.Newf("`SHOW BACKUP` using the old syntax ("+
+					"without the `IN` keyword) on a locality aware backup does not display or validate"+
+					" data specific to locality aware backups. "+
+					"Consider using the new `BACKUP INTO` syntax and `SHOW BACKUP"+
+					" FROM <backup> IN <collection>`"))
+		} else if errors.Is(err, cloud.ErrFileDoesNotExist) {
+			latestFileExists, errLatestFile := backupdest.CheckForLatestFileInCollection(ctx, baseStores[0])
+
+			if errLatestFile == nil && latestFileExists {
+				return backupInfo{}, nil, errors.WithHintf(err, "The specified path is the root of a backup collection. "+
+					"Use SHOW BACKUPS IN with this path to list all the backup subdirectories in the"+
+					" collection. SHOW BACKUP can be used with any of these subdirectories to inspect a"+
+					" backup.")
+			}
+			return backupInfo{}, nil, errors.CombineErrors(err, errLatestFile)
+		} else {
+			return backupInfo{}, nil, err
+		}
+	}
+
+	info.layerToIterFactory, err = backupinfo.GetBackupManifestIterFactories(ctx, p.ExecCfg().DistSQLSrv.ExternalStorage, info.manifests, info.enc, info.kmsEnv)
+	if err != nil {
+		return backupInfo{}, nil, err
+	}
+
+	return info, cleanup, nil
+}
+
 func getBackupInfoReader(p sql.PlanHookState, showStmt *tree.ShowBackup) backupInfoReader {
 	var infoReader backupInfoReader
 	if showStmt.Options.DebugMetadataSST {
@@ -0,0 +1,712 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudprivilege"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// backupDiffHeader is the schema of the result of SHOW BACKUP DIFF.
+var backupDiffHeader = colinfo.ResultColumns{
+	{Name: "database_name", Typ: types.String},
+	{Name: "parent_schema_name", Typ: types.String},
+	{Name: "object_name", Typ: types.String},
+	{Name: "object_type", Typ: types.String},
+	{Name: "from_end_time", Typ: types.TimestampTZ},
+	{Name: "to_end_time", Typ: types.TimestampTZ},
+	{Name: "schema_change", Typ: types.String},
+	{Name: "details", Typ: types.String},
+	{Name: "rows_delta", Typ: types.Int},
+	{Name: "size_bytes_delta", Typ: types.Int},
+	{Name: "changed_spans", Typ: types.StringArray},
+}
+
+// showBackupDiffPlanHook plans a SHOW BACKUP DIFF statement.
+//
+// SHOW BACKUP DIFF <a> AND <b> IN <collection> compares the state of the
+// backup chain at <a> as of its last layer to that of the chain at <b>.
+// SHOW BACKUP DIFF FROM <a> IN <collection> compares each layer of the chain at
+// <a> to the layer before it.
+//
+// Descriptors are matched by ID, so the backups are expected to come from the
+// same cluster. Unless sizes are skipped, the data of the chain is read as of
+// the end time of each compared layer, as a restore to that time would read
+// it. The row and size deltas of a table are the differences between the live
+// rows and the bytes of the live keys and values of the table as of the two
+// layers, and the changed spans are the spans of the indexes of the table
+// whose fingerprints differ, which pinpoints changes that affect neither.
+func showBackupDiffPlanHook(
+	ctx context.Context, showStmt *tree.ShowBackup, p sql.PlanHookState, exprEval exprutil.Evaluator,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	if err := checkShowBackupDiffOptions(showStmt.Options); err != nil {
+		return nil, nil, nil, false, err
+	}
+	from, err := exprEval.String(ctx, showStmt.Path)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	var to string
+	if showStmt.DiffPath != nil {
+		to, err = exprEval.String(ctx, showStmt.DiffPath)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+	collection, err := exprEval.StringArray(ctx, tree.Exprs(showStmt.InCollection))
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, showStmt.StatementTag())
+		defer span.Finish()
+
+		if err := cloudprivilege.CheckDestinationPrivileges(ctx, p, collection); err != nil {
+			return err
+		}
+
+		mem := p.ExecCfg().RootMemoryMonitor.MakeBoundAccount()
+		defer mem.Close(ctx)
+
+		loadChain := func(subdir string) ([]backupDiffLayer, error) {
+			info, cleanup, err := resolveShowBackupInfo(ctx, p, exprEval, showStmt, &mem, collection, subdir)
+			if err != nil {
+				return nil, err
+			}
+			defer cleanup()
+			// Only the last layer of each chain is compared when diffing two
+			// chains.
+			lastOnly := showStmt.DiffPath != nil
+			return loadBackupDiffLayers(ctx, p, info, showStmt.Options.SkipSize, lastOnly)
+		}
+
+		fromLayers, err := loadChain(from)
+		if err != nil {
+			return err
+		}
+		var rows []tree.Datums
+		if showStmt.DiffPath == nil {
+			for i := 1; i < len(fromLayers); i++ {
+				layerRows, err := diffBackupLayers(&fromLayers[i-1], &fromLayers[i])
+				if err != nil {
+					return err
+				}
+				rows = append(rows, layerRows...)
+			}
+		} else {
+			toLayers, err := loadChain(to)
+			if err != nil {
+				return err
+			}
+			rows, err = diffBackupLayers(&fromLayers[len(fromLayers)-1], &toLayers[len(toLayers)-1])
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, row := range rows {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case resultsCh <- row:
+			}
+		}
+		telemetry.Count("show-backup.diff")
+		return nil
+	}
+	return fn, backupDiffHeader, nil, false, nil
+}
+
+// checkShowBackupDiffOptions returns an error if the SHOW BACKUP options
+// contain an option that does not apply to SHOW BACKUP DIFF.
+func checkShowBackupDiffOptions(opts tree.ShowBackupOptions) error {
+	var unsupported string
+	switch {
+	case opts.AsJson:
+		unsupported = "as_json"
+	case opts.CheckFiles:
+		unsupported = "check_files"
+	case opts.DebugIDs:
+		unsupported = "debug_ids"
+	case opts.Privileges:
+		unsupported = "privileges"
+	case opts.DebugMetadataSST:
+		unsupported = "debug_dump_metadata_sst"
+	case opts.EncryptionInfoDir != nil:
+		unsupported = "encryption_info_dir"
+	default:
+		return nil
+	}
+	return pgerror.Newf(pgcode.FeatureNotSupported,
+		"SHOW BACKUP DIFF does not support the %s option", unsupported)
+}
+
+// backupDiffLayer is the state of a backup chain as of one of its layers.
+type backupDiffLayer struct {
+	endTime hlc.Timestamp
+	descs   map[descpb.ID]catalog.Descriptor
+	// names maps the IDs of the databases and schemas in descs to their names.
+	names map[descpb.ID]string
+	// tables holds the live data of each table in the chain as of this layer.
+	// It is nil if sizes were skipped.
+	tables map[descpb.ID]*backupDiffTableData
+}
+
+// backupDiffTableData summarizes the live data of a table as of a backup
+// layer. Rows counts the rows of its primary index, and DataSize the bytes of
+// the live keys and values of all of its indexes.
+type backupDiffTableData struct {
+	roachpb.RowCount
+	indexes map[descpb.IndexID]backupDiffIndexData
+}
+
+// backupDiffIndexData is the span of an index along with the fingerprint of
+// its live keys and values.
+type backupDiffIndexData struct {
+	span        roachpb.Span
+	fingerprint uint64
+}
+
+// backupDiffFingerprintOptions hash the live keys and values of an index
+// independently of the timestamps at which they were written, so that the
+// fingerprints of an index only differ between two layers if its contents do.
+var backupDiffFingerprintOptions = storage.MVCCExportFingerprintOptions{
+	StripValueChecksum:           true,
+	StripIndexPrefixAndTimestamp: true,
+}
+
+// loadBackupDiffLayers loads the state of the backup chain described by info
+// as of each of its layers, or only as of its last layer if lastOnly is set.
+func loadBackupDiffLayers(
+	ctx context.Context, p sql.PlanHookState, info backupInfo, skipSize, lastOnly bool,
+) ([]backupDiffLayer, error) {
+	if err := maybeUpgradeDescriptorsInBackupManifests(ctx,
+		p.ExecCfg().Settings.Version.ActiveVersion(ctx),
+		info.manifests,
+		info.layerToIterFactory,
+		true /* skipFKsWithNoMatchingTable */); err != nil {
+		return nil, err
+	}
+
+	var encryption *kvpb.FileEncryptionOptions
+	if info.enc != nil && !skipSize {
+		key, err := backupencryption.GetEncryptionKey(ctx, info.enc, info.kmsEnv)
+		if err != nil {
+			return nil, err
+		}
+		encryption = &kvpb.FileEncryptionOptions{Key: key}
+	}
+
+	layers := make([]backupDiffLayer, 0, len(info.manifests))
+	for i, manifest := range info.manifests {
+		if lastOnly && i < len(info.manifests)-1 {
+			continue
+		}
+		descriptors, err := backupinfo.BackupManifestDescriptors(ctx, info.layerToIterFactory[i], manifest.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		layer := backupDiffLayer{
+			endTime: manifest.EndTime,
+			descs:   make(map[descpb.ID]catalog.Descriptor, len(descriptors)),
+			names: map[descpb.ID]string{
+				keys.PublicSchemaIDForBackup: catconstants.PublicSchemaName,
+			},
+		}
+		for _, desc := range descriptors {
+			if desc.Dropped() {
+				continue
+			}
+			layer.descs[desc.GetID()] = desc
+			switch desc.(type) {
+			case catalog.DatabaseDescriptor, catalog.SchemaDescriptor:
+				layer.names[desc.GetID()] = desc.GetName()
+			}
+		}
+
+		if !skipSize {
+			if err := scanBackupDiffLayer(ctx, p, info, i, encryption, &layer); err != nil {
+				return nil, err
+			}
+		}
+		layers = append(layers, layer)
+	}
+	return layers, nil
+}
+
+// scanBackupDiffLayer reads the data of the backup chain described by info as
+// of the end time of its layerIdx'th layer and records the live data of each
+// of the layer's tables in layer.tables. The data is read the same way a
+// restore to that time reads it: the spans of the layer are covered by the
+// files of every layer up to it, which are merged as of its end time.
+func scanBackupDiffLayer(
+	ctx context.Context,
+	p sql.PlanHookState,
+	info backupInfo,
+	layerIdx int,
+	encryption *kvpb.FileEncryptionOptions,
+	layer *backupDiffLayer,
+) error {
+	execCfg := p.ExecCfg()
+	manifests := info.manifests[:layerIdx+1]
+	endTime := manifests[layerIdx].EndTime
+
+	localityInfo := info.localityInfo
+	if len(localityInfo) > len(manifests) {
+		localityInfo = localityInfo[:len(manifests)]
+	}
+	backupLocalityMap, err := makeBackupLocalityMap(localityInfo, p.User())
+	if err != nil {
+		return err
+	}
+	introducedSpanFrontier, err := createIntroducedSpanFrontier(manifests, endTime)
+	if err != nil {
+		return err
+	}
+	filter, err := makeSpanCoveringFilter(
+		nil, /* checkpointFrontier */
+		nil, /* highWater */
+		introducedSpanFrontier,
+		targetRestoreSpanSize.Get(&execCfg.Settings.SV),
+		false, /* useFrontierCheckpointing */
+	)
+	if err != nil {
+		return err
+	}
+
+	spanCh := make(chan execinfrapb.RestoreSpanEntry, 1000)
+	generateSpans := func(ctx context.Context) error {
+		defer close(spanCh)
+		return generateAndSendImportSpans(
+			ctx,
+			manifests[layerIdx].Spans,
+			manifests,
+			info.layerToIterFactory,
+			backupLocalityMap,
+			filter,
+			false, /* useSimpleImportSpans */
+			spanCh,
+		)
+	}
+
+	scanner := backupDiffScanner{
+		layer:          layer,
+		fingerprinters: make(map[backupDiffIndexKey]*storage.PointKeyFingerprinter),
+	}
+	layer.tables = make(map[descpb.ID]*backupDiffTableData)
+	scanSpans := func(ctx context.Context) error {
+		for entry := range spanCh {
+			if err := scanner.scanSpanEntry(ctx, execCfg, entry, endTime, encryption); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := ctxgroup.GoAndWait(ctx, generateSpans, scanSpans); err != nil {
+		return err
+	}
+
+	for idx, fingerprinter := range scanner.fingerprinters {
+		data := layer.tables[idx.tableID].indexes[idx.indexID]
+		data.fingerprint = fingerprinter.Fingerprint()
+		layer.tables[idx.tableID].indexes[idx.indexID] = data
+	}
+	return nil
+}
+
+// backupDiffIndexKey identifies an index of a table.
+type backupDiffIndexKey struct {
+	tableID descpb.ID
+	indexID descpb.IndexID
+}
+
+// backupDiffScanner accumulates the live data of the tables of a backup layer
+// as its spans are read.
+type backupDiffScanner struct {
+	layer          *backupDiffLayer
+	fingerprinters map[backupDiffIndexKey]*storage.PointKeyFingerprinter
+	prevRow        roachpb.Key
+}
+
+// scanSpanEntry merges the files of entry as of endTime and adds the latest
+// live version of every key in the entry's span to the data of its table.
+// Deleted keys are elided, as they would be from a restore to endTime.
+func (s *backupDiffScanner) scanSpanEntry(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	entry execinfrapb.RestoreSpanEntry,
+	endTime hlc.Timestamp,
+	encryption *kvpb.FileEncryptionOptions,
+) error {
+	storeFiles := make([]storageccl.StoreFile, 0, len(entry.Files))
+	defer func() {
+		for _, f := range storeFiles {
+			if err := f.Store.Close(); err != nil {
+				log.Warningf(ctx, "close export storage failed %v", err)
+			}
+		}
+	}()
+	for _, file := range entry.Files {
+		dir, err := execCfg.DistSQLSrv.ExternalStorage(ctx, file.Dir)
+		if err != nil {
+			return err
+		}
+		storeFiles = append(storeFiles, storageccl.StoreFile{Store: dir, FilePath: file.Path})
+	}
+
+	iterOpts := storage.IterOptions{
+		RangeKeyMaskingBelow: endTime,
+		KeyTypes:             storage.IterKeyTypePointsAndRanges,
+		LowerBound:           keys.LocalMax,
+		UpperBound:           keys.MaxKey,
+	}
+	iter, err := storageccl.ExternalSSTReader(ctx, storeFiles, encryption, iterOpts)
+	if err != nil {
+		return err
+	}
+	readAsOfIter := storage.NewReadAsOfIterator(iter, endTime)
+	defer readAsOfIter.Close()
+
+	startKey, endKey := storage.MVCCKey{Key: entry.Span.Key}, storage.MVCCKey{Key: entry.Span.EndKey}
+	for readAsOfIter.SeekGE(startKey); ; readAsOfIter.NextKey() {
+		if ok, err := readAsOfIter.Valid(); err != nil {
+			return err
+		} else if !ok || !readAsOfIter.UnsafeKey().Less(endKey) {
+			break
+		}
+		key := readAsOfIter.UnsafeKey()
+		v, err := readAsOfIter.UnsafeValue()
+		if err != nil {
+			return err
+		}
+		value, err := storage.DecodeMVCCValue(v)
+		if err != nil {
+			return err
+		}
+		if err := s.add(key, value.Value.RawBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add adds a live key and its value to the data of its table. Keys which do
+// not belong to an index of a table of the layer are ignored.
+func (s *backupDiffScanner) add(key storage.MVCCKey, value []byte) error {
+	rem, err := keys.StripTenantPrefix(key.Key)
+	if err != nil {
+		return nil //nolint:returnerrcheck
+	}
+	_, tableID, indexID, err := keys.DecodeTableIDIndexID(rem)
+	if err != nil {
+		return nil //nolint:returnerrcheck
+	}
+	table, ok := s.layer.descs[descpb.ID(tableID)].(catalog.TableDescriptor)
+	if !ok {
+		return nil
+	}
+	data := s.layer.tables[table.GetID()]
+	if data == nil {
+		data = &backupDiffTableData{indexes: make(map[descpb.IndexID]backupDiffIndexData)}
+		s.layer.tables[table.GetID()] = data
+	}
+	idx := backupDiffIndexKey{tableID: table.GetID(), indexID: descpb.IndexID(indexID)}
+	fingerprinter := s.fingerprinters[idx]
+	if fingerprinter == nil {
+		suffix, err := keys.StripIndexPrefix(key.Key)
+		if err != nil {
+			return err
+		}
+		prefix := key.Key[:len(key.Key)-len(suffix)]
+		data.indexes[idx.indexID] = backupDiffIndexData{span: roachpb.Span{
+			Key: append(roachpb.Key(nil), prefix...), EndKey: roachpb.Key(prefix).PrefixEnd(),
+		}}
+		f := storage.MakePointKeyFingerprinter(backupDiffFingerprintOptions)
+		fingerprinter = &f
+		s.fingerprinters[idx] = fingerprinter
+	}
+	if err := fingerprinter.Add(key, value); err != nil {
+		return err
+	}
+
+	data.DataSize += int64(len(key.Key) + len(value))
+	// The keys of the column families of a row share the row's prefix, so a
+	// row is only counted at its first key.
+	if row, err := keys.EnsureSafeSplitKey(key.Key); err == nil && !bytes.Equal(row, s.prevRow) {
+		s.prevRow = append(s.prevRow[:0], row...)
+		if idx.indexID == table.GetPrimaryIndexID() {
+			data.Rows++
+		} else {
+			data.IndexEntries++
+		}
+	}
+	return nil
+}
+
+// diffBackupLayers returns a row for every descriptor that was added, dropped
+// or altered between the two layers, and for every table whose data changed:
+// either its live row count or size differ, or the fingerprint of one of its
+// indexes does.
+func diffBackupLayers(from, to *backupDiffLayer) ([]tree.Datums, error) {
+	fromEnd, err := tree.MakeDTimestampTZ(timeutil.Unix(0, from.endTime.WallTime), time.Nanosecond)
+	if err != nil {
+		return nil, err
+	}
+	toEnd, err := tree.MakeDTimestampTZ(timeutil.Unix(0, to.endTime.WallTime), time.Nanosecond)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]descpb.ID, 0, len(to.descs))
+	for id := range from.descs {
+		ids = append(ids, id)
+	}
+	for id := range to.descs {
+		if _, ok := from.descs[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var rows []tree.Datums
+	for _, id := range ids {
+		before, inFrom := from.descs[id]
+		after, inTo := to.descs[id]
+		desc, layer := after, to
+		var change string
+		var details []string
+		switch {
+		case !inFrom:
+			change = "added"
+		case !inTo:
+			change = "dropped"
+			desc, layer = before, from
+		case before.GetVersion() != after.GetVersion():
+			change = "altered"
+			details = describeDescriptorChanges(before, after)
+		}
+
+		rowsDelta, sizeDelta, changedSpans := tree.DNull, tree.DNull, tree.DNull
+		if _, isTable := desc.(catalog.TableDescriptor); isTable && to.tables != nil {
+			var fromData, toData backupDiffTableData
+			if data := from.tables[id]; data != nil {
+				fromData = *data
+			}
+			if data := to.tables[id]; data != nil {
+				toData = *data
+			}
+			rows := toData.Rows - fromData.Rows
+			size := toData.DataSize - fromData.DataSize
+			spans, err := changedIndexSpans(fromData.indexes, toData.indexes)
+			if err != nil {
+				return nil, err
+			}
+			if change == "" && rows == 0 && size == 0 && spans.Len() == 0 {
+				continue
+			}
+			rowsDelta, sizeDelta = tree.NewDInt(tree.DInt(rows)), tree.NewDInt(tree.DInt(size))
+			changedSpans = spans
+		} else if change == "" {
+			continue
+		}
+
+		var dbName, schemaName, objectType string
+		switch desc.(type) {
+		case catalog.DatabaseDescriptor:
+			objectType = "database"
+		case catalog.SchemaDescriptor:
+			objectType = "schema"
+			dbName = layer.names[desc.GetParentID()]
+		default:
+			dbName = layer.names[desc.GetParentID()]
+			schemaName = layer.names[desc.GetParentSchemaID()]
+			switch desc.(type) {
+			case catalog.TableDescriptor:
+				objectType = "table"
+			case catalog.TypeDescriptor:
+				objectType = "type"
+			case catalog.FunctionDescriptor:
+				objectType = "function"
+			default:
+				objectType = "unknown"
+			}
+		}
+
+		rows = append(rows, tree.Datums{
+			nullIfEmpty(dbName),
+			nullIfEmpty(schemaName),
+			tree.NewDString(desc.GetName()),
+			tree.NewDString(objectType),
+			fromEnd,
+			toEnd,
+			nullIfEmpty(change),
+			nullIfEmpty(strings.Join(details, ", ")),
+			rowsDelta,
+			sizeDelta,
+			changedSpans,
+		})
+	}
+	return rows, nil
+}
+
+// changedIndexSpans returns the spans of the indexes whose fingerprints differ
+// between from and to, ordered by index ID. An index with no live keys in one
+// of them, such as an index that was added or dropped, differs unless it has
+// no live keys in the other either.
+func changedIndexSpans(from, to map[descpb.IndexID]backupDiffIndexData) (*tree.DArray, error) {
+	ids := make([]descpb.IndexID, 0, len(to))
+	for id, data := range to {
+		if prev, ok := from[id]; !ok || prev.fingerprint != data.fingerprint {
+			ids = append(ids, id)
+		}
+	}
+	for id := range from {
+		if _, ok := to[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	spans := tree.NewDArray(types.String)
+	for _, id := range ids {
+		data, ok := to[id]
+		if !ok {
+			data = from[id]
+		}
+		if err := spans.Append(tree.NewDString(data.span.String())); err != nil {
+			return nil, err
+		}
+	}
+	return spans, nil
+}
+
+// describeDescriptorChanges summarizes how a descriptor changed between two
+// of its versions.
+func describeDescriptorChanges(before, after catalog.Descriptor) []string {
+	var changes []string
+	if before.GetName() != after.GetName() {
+		changes = append(changes, fmt.Sprintf("renamed from %s", tree.NameString(before.GetName())))
+	}
+	if beforeTable, ok := before.(catalog.TableDescriptor); ok {
+		if afterTable, ok := after.(catalog.TableDescriptor); ok {
+			changes = append(changes, describeTableChanges(beforeTable, afterTable)...)
+		}
+	}
+	if len(changes) == 0 {
+		changes = append(changes, fmt.Sprintf("version %d to %d", before.GetVersion(), after.GetVersion()))
+	}
+	return changes
+}
+
+// describeTableChanges lists the columns, indexes and constraints that were
+// added, dropped, renamed or retyped between two versions of a table.
+func describeTableChanges(before, after catalog.TableDescriptor) []string {
+	var changes []string
+	beforeCols := make(map[descpb.ColumnID]catalog.Column)
+	for _, col := range before.PublicColumns() {
+		beforeCols[col.GetID()] = col
+	}
+	afterCols := make(map[descpb.ColumnID]catalog.Column)
+	for _, col := range after.PublicColumns() {
+		afterCols[col.GetID()] = col
+		prev, ok := beforeCols[col.GetID()]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("added column %s", tree.NameString(col.GetName())))
+		case prev.GetName() != col.GetName():
+			changes = append(changes, fmt.Sprintf("renamed column %s to %s", tree.NameString(prev.GetName()), tree.NameString(col.GetName())))
+		}
+		if ok && !prev.GetType().Identical(col.GetType()) {
+			changes = append(changes, fmt.Sprintf("altered type of column %s from %s to %s",
+				tree.NameString(col.GetName()), prev.GetType().SQLString(), col.GetType().SQLString()))
+		}
+	}
+	for _, col := range before.PublicColumns() {
+		if _, ok := afterCols[col.GetID()]; !ok {
+			changes = append(changes, fmt.Sprintf("dropped column %s", tree.NameString(col.GetName())))
+		}
+	}
+
+	beforeIdxs := make(map[descpb.IndexID]catalog.Index)
+	for _, idx := range before.ActiveIndexes() {
+		beforeIdxs[idx.GetID()] = idx
+	}
+	afterIdxs := make(map[descpb.IndexID]catalog.Index)
+	for _, idx := range after.ActiveIndexes() {
+		afterIdxs[idx.GetID()] = idx
+		prev, ok := beforeIdxs[idx.GetID()]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("added index %s", tree.NameString(idx.GetName())))
+		case prev.GetName() != idx.GetName():
+			changes = append(changes, fmt.Sprintf("renamed index %s to %s",
+				tree.NameString(prev.GetName()), tree.NameString(idx.GetName())))
+		}
+	}
+	for _, idx := range before.ActiveIndexes() {
+		if _, ok := afterIdxs[idx.GetID()]; !ok {
+			changes = append(changes, fmt.Sprintf("dropped index %s", tree.NameString(idx.GetName())))
+		}
+	}
+
+	// Constraints backed by an index are covered by the indexes above.
+	beforeConstraints := make(map[descpb.ConstraintID]catalog.Constraint)
+	for _, c := range before.EnforcedConstraints() {
+		if c.AsUniqueWithIndex() == nil {
+			beforeConstraints[c.GetConstraintID()] = c
+		}
+	}
+	afterConstraints := make(map[descpb.ConstraintID]catalog.Constraint)
+	for _, c := range after.EnforcedConstraints() {
+		if c.AsUniqueWithIndex() != nil {
+			continue
+		}
+		afterConstraints[c.GetConstraintID()] = c
+		if _, ok := beforeConstraints[c.GetConstraintID()]; !ok {
+			changes = append(changes, fmt.Sprintf("added constraint %s", tree.NameString(c.GetName())))
+		}
+	}
+	for _, c := range before.EnforcedConstraints() {
+		if _, ok := afterConstraints[c.GetConstraintID()]; !ok && c.AsUniqueWithIndex() == nil {
+			changes = append(changes, fmt.Sprintf("dropped constraint %s", tree.NameString(c.GetName())))
+		}
+	}
+	return changes
+}
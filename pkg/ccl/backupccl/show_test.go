@@ -950,3 +950,70 @@ func TestShowBackupCheckFiles(t *testing.T) {
 		}
 	}
 }
+
+func TestShowBackupDiff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 0
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	const collection = localFoo + "/diff"
+	sqlDB.Exec(t, `
+CREATE DATABASE d;
+CREATE TABLE d.t (a INT PRIMARY KEY, b STRING);
+CREATE TABLE d.gone (a INT PRIMARY KEY);
+CREATE TABLE d.same (a INT PRIMARY KEY);
+INSERT INTO d.t SELECT i, i::STRING FROM generate_series(1, 10) AS i;
+INSERT INTO d.gone VALUES (1), (2);
+INSERT INTO d.same VALUES (1);
+`)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO $1`, collection)
+
+	sqlDB.Exec(t, `
+ALTER TABLE d.t RENAME COLUMN b TO name;
+CREATE INDEX name_idx ON d.t (name);
+INSERT INTO d.t SELECT i, i::STRING FROM generate_series(11, 15) AS i;
+DROP TABLE d.gone;
+CREATE TABLE d.added (a INT PRIMARY KEY);
+`)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO $1`, collection)
+
+	backups := sqlDB.QueryStr(t, `SHOW BACKUPS IN $1`, collection)
+	require.Len(t, backups, 2)
+
+	const query = `SELECT object_name, schema_change, details, rows_delta FROM [%s]
+WHERE object_type = 'table' ORDER BY object_name`
+	diff := sqlDB.QueryStr(t, fmt.Sprintf(query, `SHOW BACKUP DIFF $1 AND $2 IN $3`),
+		backups[0][0], backups[1][0], collection)
+	require.Equal(t, [][]string{
+		{"added", "added", "NULL", "0"},
+		{"gone", "dropped", "NULL", "-2"},
+		{"t", "altered", "renamed column b to name, added index name_idx", "5"},
+	}, diff)
+
+	// Each incremental layer is compared to the layer before it.
+	sqlDB.Exec(t, `INSERT INTO d.same VALUES (2), (3)`)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO LATEST IN $1`, collection)
+	sqlDB.Exec(t, `ALTER TABLE d.same ADD CONSTRAINT c CHECK (a > 0)`)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO LATEST IN $1`, collection)
+	require.Equal(t, [][]string{
+		{"same", "NULL", "NULL", "2"},
+		{"same", "altered", "added constraint c", "0"},
+	}, sqlDB.QueryStr(t, `SELECT object_name, schema_change, details, rows_delta
+FROM [SHOW BACKUP DIFF FROM LATEST IN $1] WHERE object_type = 'table' ORDER BY to_end_time`,
+		collection))
+
+	// Without sizes only schema changes are reported.
+	require.Equal(t, [][]string{
+		{"added", "added", "NULL", "NULL"},
+		{"gone", "dropped", "NULL", "NULL"},
+		{"same", "altered", "added constraint c", "NULL"},
+		{"t", "altered", "renamed column b to name, added index name_idx", "NULL"},
+	}, sqlDB.QueryStr(t, fmt.Sprintf(query, `SHOW BACKUP DIFF $1 AND LATEST IN $2 WITH skip size`),
+		backups[0][0], collection))
+
+	sqlDB.ExpectErr(t, "SHOW BACKUP DIFF does not support the check_files option",
+		`SHOW BACKUP DIFF LATEST AND LATEST IN $1 WITH check_files`, collection)
+}
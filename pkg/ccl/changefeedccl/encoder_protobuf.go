@@ -0,0 +1,261 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+// confluentProtobufEncoder encodes changefeed entries as protobuf messages,
+// whose schemas are registered with a Confluent schema registry. Keys are the
+// primary key columns in a message. Values are all columns in a message.
+type confluentProtobufEncoder struct {
+	schemaRegistry            schemaRegistry
+	updatedField, beforeField bool
+	targets                   changefeedbase.Targets
+	envelopeType              changefeedbase.EnvelopeType
+	customKeyColumn           string
+
+	keyCache   *cache.UnorderedCache // [tableIDAndVersion]confluentRegisteredProtobufKeySchema
+	valueCache *cache.UnorderedCache // [tableIDAndVersionPair]confluentRegisteredProtobufEnvelopeSchema
+
+	// resolvedCache doesn't need to be bounded like the other caches because the number of topics
+	// is fixed per changefeed.
+	resolvedCache map[string]confluentRegisteredProtobufEnvelopeSchema
+}
+
+type confluentRegisteredProtobufKeySchema struct {
+	schema     *protobufKeyMessage
+	registryID int32
+}
+
+type confluentRegisteredProtobufEnvelopeSchema struct {
+	schema     *protobufEnvelopeMessage
+	registryID int32
+}
+
+var _ Encoder = &confluentProtobufEncoder{}
+
+func newConfluentProtobufEncoder(
+	opts changefeedbase.EncodingOptions,
+	targets changefeedbase.Targets,
+	p externalConnectionProvider,
+	sliMetrics *sliMetrics,
+) (*confluentProtobufEncoder, error) {
+	e := &confluentProtobufEncoder{
+		targets:         targets,
+		envelopeType:    opts.Envelope,
+		updatedField:    opts.UpdatedTimestamps,
+		beforeField:     opts.Diff,
+		customKeyColumn: opts.CustomKeyColumn,
+	}
+
+	if opts.KeyInValue {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptKeyInValue, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	if opts.TopicInValue {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptTopicInValue, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	if len(opts.SchemaRegistryURI) == 0 {
+		return nil, errors.Errorf(`WITH option %s is required for %s=%s`,
+			changefeedbase.OptConfluentSchemaRegistry, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+
+	reg, err := newConfluentSchemaRegistry(opts.SchemaRegistryURI, p, sliMetrics)
+	if err != nil {
+		return nil, err
+	}
+
+	e.schemaRegistry = reg
+	e.keyCache = cache.NewUnorderedCache(encoderCacheConfig)
+	e.valueCache = cache.NewUnorderedCache(encoderCacheConfig)
+	e.resolvedCache = make(map[string]confluentRegisteredProtobufEnvelopeSchema)
+	return e, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeKey(
+	ctx context.Context, row cdcevent.Row,
+) ([]byte, error) {
+	// No familyID in the cache key for keys because it's the same schema for all families
+	cacheKey := tableIDAndVersion{tableID: row.TableID, version: row.Version}
+
+	keyColumns := row.ForEachKeyColumn()
+	if e.customKeyColumn != "" {
+		var err error
+		keyColumns, err = row.DatumNamed(e.customKeyColumn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var registered confluentRegisteredProtobufKeySchema
+	v, ok := e.keyCache.Get(cacheKey)
+	if ok {
+		registered = v.(confluentRegisteredProtobufKeySchema)
+	} else {
+		tableName, err := confluentTableName(e.targets, "" /* schemaPrefix */, row.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		registered.schema, err = newProtobufKeyMessage(keyColumns, tableName)
+		if err != nil {
+			return nil, err
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(tableName) + confluentSubjectSuffixKey
+		registered.registryID, err = e.register(ctx, &registered.schema.protobufFile, subject)
+		if err != nil {
+			return nil, err
+		}
+		e.keyCache.Add(cacheKey, registered)
+	}
+
+	return registered.schema.BinaryFromRow(confluentProtobufHeader(registered.registryID), keyColumns)
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeValue(
+	ctx context.Context, evCtx eventContext, updatedRow cdcevent.Row, prevRow cdcevent.Row,
+) ([]byte, error) {
+	if e.envelopeType == changefeedbase.OptEnvelopeKeyOnly {
+		return nil, nil
+	}
+
+	var cacheKey tableIDAndVersionPair
+	if e.beforeField && prevRow.IsInitialized() {
+		cacheKey[0] = tableIDAndVersion{
+			tableID: prevRow.TableID, version: prevRow.Version, familyID: prevRow.FamilyID,
+		}
+	}
+	cacheKey[1] = tableIDAndVersion{
+		tableID: updatedRow.TableID, version: updatedRow.Version, familyID: updatedRow.FamilyID,
+	}
+
+	var registered confluentRegisteredProtobufEnvelopeSchema
+	v, ok := e.valueCache.Get(cacheKey)
+	if ok {
+		registered = v.(confluentRegisteredProtobufEnvelopeSchema)
+	} else {
+		var before, after, record *protobufDataMessage
+		var opts protobufEnvelopeOpts
+		current, err := tableToProtobufMessage(updatedRow, "" /* nameSuffix */)
+		if err != nil {
+			return nil, err
+		}
+		// In the wrapped envelope, row data goes in the "after" field. In the raw
+		// envelope, it goes in the "record" field. In the "key_only" envelope it's
+		// omitted.
+		if e.envelopeType == changefeedbase.OptEnvelopeWrapped {
+			opts = protobufEnvelopeOpts{afterField: true, beforeField: e.beforeField, updatedField: e.updatedField}
+			after = current
+			if e.beforeField {
+				// The before row may be missing, or be of an older version of the table
+				// than the after row. Either way, the before field has its own message.
+				beforeRow := updatedRow
+				if prevRow.IsInitialized() {
+					beforeRow = prevRow
+				}
+				before, err = tableToProtobufMessage(beforeRow, `before`)
+				if err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			opts = protobufEnvelopeOpts{recordField: true, updatedField: e.updatedField}
+			record = current
+		}
+
+		name, err := confluentTableName(e.targets, "" /* schemaPrefix */, updatedRow.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		registered.schema, err = newProtobufEnvelopeMessage(name, opts, before, after, record)
+		if err != nil {
+			return nil, err
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(name) + confluentSubjectSuffixValue
+		registered.registryID, err = e.register(ctx, &registered.schema.protobufFile, subject)
+		if err != nil {
+			return nil, err
+		}
+		e.valueCache.Add(cacheKey, registered)
+	}
+
+	var meta protobufMetadata
+	if registered.schema.opts.updatedField {
+		meta.updated = evCtx.updated
+	}
+	return registered.schema.BinaryFromRow(
+		confluentProtobufHeader(registered.registryID), meta, prevRow, updatedRow, updatedRow)
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeResolvedTimestamp(
+	ctx context.Context, topic string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	registered, ok := e.resolvedCache[topic]
+	if !ok {
+		opts := protobufEnvelopeOpts{resolvedField: true}
+		var err error
+		registered.schema, err = newProtobufEnvelopeMessage(topic, opts, nil /* before */, nil /* after */, nil /* record */)
+		if err != nil {
+			return nil, err
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(topic) + confluentSubjectSuffixValue
+		registered.registryID, err = e.register(ctx, &registered.schema.protobufFile, subject)
+		if err != nil {
+			return nil, err
+		}
+
+		e.resolvedCache[topic] = registered
+	}
+	var nilRow cdcevent.Row
+	return registered.schema.BinaryFromRow(confluentProtobufHeader(registered.registryID),
+		protobufMetadata{resolved: resolved}, nilRow, nilRow, nilRow)
+}
+
+func (e *confluentProtobufEncoder) register(
+	ctx context.Context, file *protobufFile, subject string,
+) (int32, error) {
+	return e.schemaRegistry.RegisterSchemaForSubject(ctx, subject, file.schema, schemaTypeProtobuf)
+}
+
+// confluentProtobufHeader returns the header of a protobuf message encoded in
+// the Confluent wire format. Unlike for avro, the schema ID is followed by the
+// indexes of the message in the protobuf file. Messages are always the first
+// message of their file, which is encoded as a single 0.
+//
+// https://docs.confluent.io/platform/current/schema-registry/serdes-develop/index.html#wire-format
+func confluentProtobufHeader(registryID int32) []byte {
+	header := []byte{
+		changefeedbase.ConfluentAvroWireFormatMagic,
+		0, 0, 0, 0, // Placeholder for the ID.
+		0, // Message indexes.
+	}
+	binary.BigEndian.PutUint32(header[1:5], uint32(registryID))
+	return header
+}
@@ -0,0 +1,314 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestProtobufEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	require.NoError(t, err)
+	row := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`bar`)},
+	}
+	ts := hlc.Timestamp{WallTime: 1, Logical: 2}
+
+	expecteds := map[string]struct {
+		// Either err is set or all of insert, delete, and resolved are.
+		err      string
+		insert   string
+		delete   string
+		resolved string
+	}{
+		`envelope=key_only`: {
+			insert:   `{"a":"1"}->`,
+			delete:   `{"a":"1"}->`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		`envelope=key_only,updated`: {
+			err: `updated is only usable with envelope=wrapped`,
+		},
+		`envelope=key_only,diff`: {
+			err: `diff is only usable with envelope=wrapped`,
+		},
+		`envelope=row`: {
+			err: `envelope=row is not supported with format=protobuf`,
+		},
+		`envelope=wrapped`: {
+			insert:   `{"a":"1"}->{"after":{"a":"1","b":"bar"}}`,
+			delete:   `{"a":"1"}->{}`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		`envelope=wrapped,updated`: {
+			insert:   `{"a":"1"}->{"after":{"a":"1","b":"bar"},"updated":"1.0000000002"}`,
+			delete:   `{"a":"1"}->{"updated":"1.0000000002"}`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		`envelope=wrapped,diff`: {
+			insert:   `{"a":"1"}->{"after":{"a":"1","b":"bar"}}`,
+			delete:   `{"a":"1"}->{"before":{"a":"1","b":"bar"}}`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		`envelope=wrapped,updated,diff`: {
+			insert:   `{"a":"1"}->{"after":{"a":"1","b":"bar"},"updated":"1.0000000002"}`,
+			delete:   `{"a":"1"}->{"before":{"a":"1","b":"bar"},"updated":"1.0000000002"}`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		`envelope=bare`: {
+			insert:   `{"a":"1"}->{"record":{"a":"1","b":"bar"}}`,
+			delete:   `{"a":"1"}->{"record":{"a":"1","b":"bar"}}`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+	}
+
+	targets := changefeedbase.Targets{}
+	targets.Add(changefeedbase.Target{
+		Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		TableID:           tableDesc.GetID(),
+		StatementTimeName: changefeedbase.StatementTimeName(tableDesc.GetName()),
+	})
+
+	var opts []changefeedbase.EncodingOptions
+	for _, e := range []changefeedbase.EnvelopeType{
+		changefeedbase.OptEnvelopeKeyOnly, changefeedbase.OptEnvelopeRow,
+		changefeedbase.OptEnvelopeWrapped, changefeedbase.OptEnvelopeBare,
+	} {
+		for _, updated := range []bool{false, true} {
+			for _, diff := range []bool{false, true} {
+				opts = append(opts, changefeedbase.EncodingOptions{
+					Format: changefeedbase.OptFormatProtobuf, Envelope: e, UpdatedTimestamps: updated, Diff: diff,
+				})
+			}
+		}
+	}
+
+	for _, o := range opts {
+		name := fmt.Sprintf("envelope=%s", o.Envelope)
+		if o.UpdatedTimestamps {
+			name += `,updated`
+		}
+		if o.Diff {
+			name += `,diff`
+		}
+		expected, ok := expecteds[name]
+		if !ok {
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			reg := cdctest.StartTestSchemaRegistry()
+			defer reg.Close()
+			o.SchemaRegistryURI = reg.URL()
+
+			if len(expected.err) > 0 {
+				require.EqualError(t, o.Validate(), expected.err)
+				return
+			}
+			require.NoError(t, o.Validate())
+			enc, err := getEncoder(o, targets, false, nil, nil)
+			require.NoError(t, err)
+			e := enc.(*confluentProtobufEncoder)
+			rowString := func(k, v []byte) string {
+				return fmt.Sprintf(`%s->%s`, protobufToJSON(t, e, k), protobufToJSON(t, e, v))
+			}
+
+			ctx := context.Background()
+			rowInsert := cdcevent.TestingMakeEventRow(tableDesc, 0, row, false)
+			prevRow := cdcevent.TestingMakeEventRow(tableDesc, 0, nil, false)
+			evCtx := eventContext{updated: ts}
+
+			keyInsert, err := e.EncodeKey(ctx, rowInsert)
+			require.NoError(t, err)
+			valueInsert, err := e.EncodeValue(ctx, evCtx, rowInsert, prevRow)
+			require.NoError(t, err)
+			require.Equal(t, expected.insert, rowString(keyInsert, valueInsert))
+
+			rowDelete := cdcevent.TestingMakeEventRow(tableDesc, 0, row, true)
+			prevRow = cdcevent.TestingMakeEventRow(tableDesc, 0, row, false)
+
+			keyDelete, err := e.EncodeKey(ctx, rowDelete)
+			require.NoError(t, err)
+			valueDelete, err := e.EncodeValue(ctx, evCtx, rowDelete, prevRow)
+			require.NoError(t, err)
+			require.Equal(t, expected.delete, rowString(keyDelete, valueDelete))
+
+			resolved, err := e.EncodeResolvedTimestamp(ctx, tableDesc.GetName(), ts)
+			require.NoError(t, err)
+			require.Equal(t, expected.resolved, protobufToJSON(t, e, resolved))
+
+			require.Equal(t, `PROTOBUF`, reg.SchemaTypeForSubject(`foo-key`))
+		})
+	}
+}
+
+// TestProtobufSchemaEvolution checks that the numbers of the fields of the
+// protobuf messages are stable as columns are added and dropped.
+func TestProtobufSchemaEvolution(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	v1, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c FLOAT)`)
+	require.NoError(t, err)
+
+	// Drop column b and add column d.
+	v2Desc := protoutil.Clone(v1.TableDesc()).(*descpb.TableDescriptor)
+	v2Desc.Version++
+	v2Desc.Columns = []descpb.ColumnDescriptor{
+		v2Desc.Columns[0],
+		v2Desc.Columns[2],
+		{ID: v2Desc.NextColumnID, Name: `d`, Type: types.Bool, Nullable: true},
+	}
+	v2Desc.NextColumnID++
+	v2Desc.Families[0].ColumnIDs = []descpb.ColumnID{1, 3, 4}
+	v2Desc.Families[0].ColumnNames = []string{`a`, `c`, `d`}
+	v2Desc.PrimaryIndex.StoreColumnIDs = []descpb.ColumnID{3, 4}
+	v2Desc.PrimaryIndex.StoreColumnNames = []string{`c`, `d`}
+	v2 := tabledesc.NewBuilder(v2Desc).BuildImmutableTable()
+
+	reg := cdctest.StartTestSchemaRegistry()
+	defer reg.Close()
+	targets := changefeedbase.Targets{}
+	targets.Add(changefeedbase.Target{
+		Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		TableID:           v1.GetID(),
+		StatementTimeName: changefeedbase.StatementTimeName(v1.GetName()),
+	})
+	enc, err := getEncoder(changefeedbase.EncodingOptions{
+		Format:            changefeedbase.OptFormatProtobuf,
+		Envelope:          changefeedbase.OptEnvelopeWrapped,
+		SchemaRegistryURI: reg.URL(),
+	}, targets, false, nil, nil)
+	require.NoError(t, err)
+	e := enc.(*confluentProtobufEncoder)
+
+	ctx := context.Background()
+	encode := func(desc catalog.TableDescriptor, datums ...tree.Datum) string {
+		var row rowenc.EncDatumRow
+		for _, d := range datums {
+			row = append(row, rowenc.EncDatum{Datum: d})
+		}
+		updated := cdcevent.TestingMakeEventRow(desc, 0, row, false)
+		prev := cdcevent.TestingMakeEventRow(desc, 0, nil, false)
+		key, err := e.EncodeKey(ctx, updated)
+		require.NoError(t, err)
+		key = append([]byte(nil), key...)
+		value, err := e.EncodeValue(ctx, eventContext{}, updated, prev)
+		require.NoError(t, err)
+		return fmt.Sprintf(`%s->%s`, protobufToJSON(t, e, key), protobufToJSON(t, e, value))
+	}
+
+	require.Equal(t, `{"a":"1"}->{"after":{"a":"1","b":"one","c":1.5}}`,
+		encode(v1, tree.NewDInt(1), tree.NewDString(`one`), tree.NewDFloat(1.5)))
+	require.Equal(t, `syntax = "proto3";
+
+message foo {
+  optional int64 a = 1;
+}
+`, reg.SchemaForSubject(`foo-key`))
+	require.Equal(t, `syntax = "proto3";
+
+message foo_envelope {
+  foo after = 1;
+}
+
+message foo {
+  optional int64 a = 1;
+  optional string b = 2;
+  optional double c = 3;
+}
+`, reg.SchemaForSubject(`foo-value`))
+
+	// NULLs are encoded as missing fields.
+	require.Equal(t, `{"a":"2"}->{"after":{"a":"2","d":true}}`,
+		encode(v2, tree.NewDInt(2), tree.DNull, tree.DBoolTrue))
+	require.Equal(t, `syntax = "proto3";
+
+message foo_envelope {
+  foo after = 1;
+}
+
+message foo {
+  optional int64 a = 1;
+  optional double c = 3;
+  optional bool d = 4;
+}
+`, reg.SchemaForSubject(`foo-value`))
+}
+
+// protobufToJSON decodes a message encoded by the given protobuf encoder, using
+// the descriptors of the schemas it registered, and returns it as JSON.
+func protobufToJSON(t testing.TB, e *confluentProtobufEncoder, b []byte) string {
+	if len(b) == 0 {
+		return ``
+	}
+	require.GreaterOrEqual(t, len(b), 6)
+	require.Equal(t, changefeedbase.ConfluentAvroWireFormatMagic, b[0])
+	require.Equal(t, byte(0), b[5], "unexpected message indexes")
+	id := int32(binary.BigEndian.Uint32(b[1:5]))
+
+	var desc protoreflect.MessageDescriptor
+	find := func(f *protobufFile, registryID int32) {
+		if registryID == id {
+			desc = f.desc
+		}
+	}
+	e.keyCache.Do(func(entry *cache.Entry) {
+		registered := entry.Value.(confluentRegisteredProtobufKeySchema)
+		find(&registered.schema.protobufFile, registered.registryID)
+	})
+	e.valueCache.Do(func(entry *cache.Entry) {
+		registered := entry.Value.(confluentRegisteredProtobufEnvelopeSchema)
+		find(&registered.schema.protobufFile, registered.registryID)
+	})
+	for _, registered := range e.resolvedCache {
+		find(&registered.schema.protobufFile, registered.registryID)
+	}
+	require.NotNil(t, desc, "unknown schema ID %d", id)
+
+	msg := dynamicpb.NewMessage(desc)
+	require.NoError(t, proto.Unmarshal(b[6:], msg))
+	j, err := protojson.Marshal(msg)
+	require.NoError(t, err)
+	// The output of protojson is purposefully unstable, so round trip it
+	// through encoding/json which sorts the keys of objects.
+	var native interface{}
+	require.NoError(t, json.Unmarshal(j, &native))
+	j, err = json.Marshal(native)
+	require.NoError(t, err)
+	return string(j)
+}
@@ -0,0 +1,228 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvfeed"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/schemafeed"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/errors"
+)
+
+func init() {
+	sql.RunLogicalReplicationFeedCCL = runLogicalReplicationFeed
+}
+
+// errRestartLogicalReplicationFeed is returned when the KV feed of a logical
+// replication stream stops at a schema change which requires watching
+// different spans, such as a change of the primary index.
+var errRestartLogicalReplicationFeed = errors.New("restarting logical replication feed")
+
+// runLogicalReplicationFeed produces the changes streamed by START_REPLICATION
+// using a KV feed over the primary indexes of the given tables. The feed
+// restarts whenever the primary index of a table changes.
+func runLogicalReplicationFeed(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	tables []catalog.TableDescriptor,
+	startTime hlc.Timestamp,
+	emit func(context.Context, sql.LogicalReplicationEvent) error,
+) error {
+	tables = append([]catalog.TableDescriptor(nil), tables...)
+	for {
+		restartAt, err := runLogicalReplicationFeedOnce(ctx, execCfg, tables, startTime, emit)
+		if err != nil {
+			return err
+		}
+		if err := execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+			for i := range tables {
+				table, err := txn.Descriptors().ByID(txn.KV()).Get().Table(ctx, tables[i].GetID())
+				if err != nil {
+					return err
+				}
+				tables[i] = table
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		startTime = restartAt
+	}
+}
+
+// runLogicalReplicationFeedOnce runs a KV feed until it stops at a schema
+// change, in which case it returns the timestamp from which the feed must be
+// restarted.
+func runLogicalReplicationFeedOnce(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	tables []catalog.TableDescriptor,
+	startTime hlc.Timestamp,
+	emit func(context.Context, sql.LogicalReplicationEvent) error,
+) (restartAt hlc.Timestamp, _ error) {
+	var targets changefeedbase.Targets
+	spans := make([]roachpb.Span, len(tables))
+	for i, table := range tables {
+		targets.Add(changefeedbase.Target{
+			Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+			TableID:           table.GetID(),
+			StatementTimeName: changefeedbase.StatementTimeName(table.GetName()),
+		})
+		spans[i] = table.PrimaryIndexSpan(execCfg.Codec)
+	}
+	frontier, err := span.MakeFrontierAt(startTime, spans...)
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+	decoder, err := cdcevent.NewEventDecoder(ctx, execCfg, targets,
+		false /* includeVirtual */, false /* keyOnly */)
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+
+	sv := &execCfg.Settings.SV
+	parentMon := execCfg.DistSQLSrv.BackfillerMonitor
+	memMon := mon.NewMonitorInheritWithLimit("logical-replication",
+		changefeedbase.PerChangefeedMemLimit.Get(sv), parentMon)
+	memMon.StartNoReserved(ctx, parentMon)
+	defer memMon.Stop(ctx)
+
+	histogramWindow := base.DefaultHistogramWindowInterval()
+	kvMetrics := kvevent.MakeMetrics(histogramWindow)
+	schemaMetrics := schemafeed.MakeMetrics(histogramWindow)
+	buf := kvevent.NewMemBuffer(memMon.MakeBoundAccount(), sv, &kvMetrics)
+	cfg := kvfeed.Config{
+		Writer:   buf,
+		Settings: execCfg.Settings,
+		DB:       execCfg.DB,
+		Codec:    execCfg.Codec,
+		Clock:    execCfg.Clock,
+		Spans:    spans,
+		Targets:  targets,
+		Metrics:  &kvMetrics,
+		MM:       memMon,
+		MonitoringCfg: kvfeed.MonitoringConfig{
+			LaggingRangesCallback:        func(int64) {},
+			LaggingRangesPollingInterval: time.Minute,
+			LaggingRangesThreshold:       time.Minute,
+		},
+		InitialHighWater: startTime,
+		WithDiff:         true,
+		// Changes to the columns of a table are picked up when decoding the rows,
+		// but a change of the primary index restarts the feed.
+		SchemaChangeEvents: changefeedbase.OptSchemaChangeEventClassDefault,
+		SchemaChangePolicy: changefeedbase.OptSchemaChangePolicyNoBackfill,
+		SchemaFeed: schemafeed.New(ctx, &execCfg.DistSQLSrv.ServerConfig,
+			changefeedbase.OptSchemaChangeEventClassDefault, targets, startTime, &schemaMetrics,
+			changefeedbase.CanHandle{VirtualColumns: true}),
+		UseMux: changefeedbase.UseMuxRangeFeed.Get(sv),
+	}
+
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		return kvfeed.Run(ctx, cfg)
+	})
+	g.GoCtx(func(ctx context.Context) error {
+		for {
+			ev, err := buf.Get(ctx)
+			if err != nil {
+				if !restartAt.IsEmpty() && errors.Is(err, kvevent.ErrNormalRestartReason) {
+					return errRestartLogicalReplicationFeed
+				}
+				return err
+			}
+			if err := handleLogicalReplicationEvent(
+				ctx, decoder, frontier, &ev, &restartAt, emit,
+			); err != nil {
+				return err
+			}
+		}
+	})
+	if err := g.Wait(); !errors.Is(err, errRestartLogicalReplicationFeed) {
+		return hlc.Timestamp{}, err
+	}
+	return restartAt, nil
+}
+
+// handleLogicalReplicationEvent decodes an event of the KV feed and emits the
+// corresponding change or checkpoint.
+func handleLogicalReplicationEvent(
+	ctx context.Context,
+	decoder cdcevent.Decoder,
+	frontier *span.Frontier,
+	ev *kvevent.Event,
+	restartAt *hlc.Timestamp,
+	emit func(context.Context, sql.LogicalReplicationEvent) error,
+) error {
+	a := ev.DetachAlloc()
+	defer a.Release(ctx)
+
+	switch ev.Type() {
+	case kvevent.TypeKV:
+		ts := ev.KV().Value.Timestamp
+		row, err := decoder.DecodeKV(ctx, ev.KV(), cdcevent.CurrentRow, ts, false /* keyOnly */)
+		if err != nil {
+			if errors.Is(err, cdcevent.ErrUnwatchedFamily) {
+				return nil
+			}
+			return err
+		}
+		prevRow, err := decoder.DecodeKV(ctx, ev.PrevKeyValue(), cdcevent.PrevRow, ts, false /* keyOnly */)
+		if err != nil {
+			return err
+		}
+		var datums tree.Datums
+		if err := row.ForEachColumn().Datum(func(d tree.Datum, _ cdcevent.ResultColumn) error {
+			datums = append(datums, d)
+			return nil
+		}); err != nil {
+			return err
+		}
+		return emit(ctx, sql.LogicalReplicationEvent{
+			Table:     row.TableDescriptor(),
+			Timestamp: row.MvccTimestamp,
+			Row:       datums,
+			Deleted:   row.IsDeleted(),
+			Existed:   prevRow.IsInitialized() && !prevRow.IsDeleted(),
+		})
+
+	case kvevent.TypeResolved:
+		resolved := ev.Resolved()
+		if resolved.BoundaryType == jobspb.ResolvedSpan_RESTART {
+			*restartAt = resolved.Timestamp
+		}
+		prev := frontier.Frontier()
+		if _, err := frontier.Forward(resolved.Span, resolved.Timestamp); err != nil {
+			return err
+		}
+		if cur := frontier.Frontier(); prev.Less(cur) {
+			return emit(ctx, sql.LogicalReplicationEvent{Resolved: cur})
+		}
+		return nil
+
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLogicalReplication streams changes over a replication connection and
+// checks that they are encoded in the pgoutput format.
+func TestLogicalReplication(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	s, db, stopServer := startTestFullServer(t, feedTestOptions{})
+	defer stopServer()
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE t (a INT PRIMARY KEY, b STRING)`)
+	sqlDB.Exec(t, `INSERT INTO t VALUES (0, 'before')`)
+
+	pgURL, cleanup := sqlutils.PGUrl(t, s.ApplicationLayer().AdvSQLAddr(), "logical_replication", url.User(username.RootUser))
+	defer cleanup()
+	pgURL.Path = `d`
+	cfg, err := pgconn.ParseConfig(pgURL.String())
+	require.NoError(t, err)
+	cfg.RuntimeParams["replication"] = "database"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	conn, err := pgconn.ConnectConfig(ctx, cfg)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close(ctx) }()
+
+	_, err = conn.Exec(ctx, `CREATE PUBLICATION pub FOR TABLE t`).ReadAll()
+	require.NoError(t, err)
+	_, err = conn.Exec(ctx, `CREATE_REPLICATION_SLOT slot LOGICAL pgoutput`).ReadAll()
+	require.NoError(t, err)
+
+	fe := conn.Frontend()
+	fe.Send(&pgproto3.Query{
+		String: `START_REPLICATION SLOT slot LOGICAL 0/0 (proto_version '1', publication_names 'pub')`,
+	})
+	require.NoError(t, fe.Flush())
+	msg, err := conn.ReceiveMessage(ctx)
+	require.NoError(t, err)
+	require.IsType(t, &pgproto3.CopyBothResponse{}, msg)
+
+	sqlDB.Exec(t, `INSERT INTO t VALUES (1, 'one')`)
+	sqlDB.Exec(t, `UPDATE t SET b = 'uno' WHERE a = 1`)
+	sqlDB.Exec(t, `DELETE FROM t WHERE a = 0`)
+
+	// The changes are sent in separate transactions, in commit order. The row
+	// inserted before the slot was created is only seen when it is deleted.
+	var received []string
+	var lastCommit lsn.LSN
+	for len(received) < 10 {
+		msg, err := conn.ReceiveMessage(ctx)
+		require.NoError(t, err)
+		data, ok := msg.(*pgproto3.CopyData)
+		require.True(t, ok, "unexpected message %T", msg)
+		if data.Data[0] != 'w' {
+			// Skip the keepalive messages.
+			require.Equal(t, byte('k'), data.Data[0])
+			continue
+		}
+		var m string
+		m, lastCommit = decodeLogicalReplicationMessage(t, data.Data[25:], lastCommit)
+		received = append(received, m)
+	}
+	require.Equal(t, []string{
+		"BEGIN",
+		"RELATION public.t (a key, b)",
+		"INSERT (1, one)",
+		"COMMIT",
+		"BEGIN",
+		"UPDATE (1, uno)",
+		"COMMIT",
+		"BEGIN",
+		"DELETE (0, NULL)",
+		"COMMIT",
+	}, received)
+
+	// Confirm the changes and end the stream.
+	status := []byte{'r'}
+	for i := 0; i < 3; i++ {
+		status = binary.BigEndian.AppendUint64(status, uint64(lastCommit))
+	}
+	status = binary.BigEndian.AppendUint64(status, 0 /* clientTime */)
+	status = append(status, 0 /* replyRequested */)
+	fe.Send(&pgproto3.CopyData{Data: status})
+	fe.Send(&pgproto3.CopyDone{})
+	require.NoError(t, fe.Flush())
+	for done := false; !done; {
+		msg, err := conn.ReceiveMessage(ctx)
+		require.NoError(t, err)
+		switch msg := msg.(type) {
+		case *pgproto3.CopyData, *pgproto3.CopyDone, *pgproto3.CommandComplete:
+		case *pgproto3.ReadyForQuery:
+			done = true
+		case *pgproto3.ErrorResponse:
+			t.Fatalf("unexpected error: %s", msg.Message)
+		default:
+			t.Fatalf("unexpected message %T", msg)
+		}
+	}
+	sqlDB.CheckQueryResults(t,
+		`SELECT confirmed_flush_lsn FROM pg_catalog.pg_replication_slots WHERE slot_name = 'slot'`,
+		[][]string{{lastCommit.String()}},
+	)
+}
+
+// decodeLogicalReplicationMessage formats a pgoutput message. It also returns
+// the LSN of the last commit, updated if the message is a COMMIT.
+func decodeLogicalReplicationMessage(
+	t *testing.T, msg []byte, lastCommit lsn.LSN,
+) (string, lsn.LSN) {
+	readString := func(b []byte) (string, []byte) {
+		i := strings.IndexByte(string(b), 0)
+		require.GreaterOrEqual(t, i, 0)
+		return string(b[:i]), b[i+1:]
+	}
+	readTuple := func(b []byte) string {
+		n := int(binary.BigEndian.Uint16(b))
+		b = b[2:]
+		vals := make([]string, n)
+		for i := range vals {
+			switch b[0] {
+			case 'n':
+				vals[i] = "NULL"
+				b = b[1:]
+			case 't':
+				l := int(binary.BigEndian.Uint32(b[1:]))
+				vals[i] = string(b[5 : 5+l])
+				b = b[5+l:]
+			default:
+				t.Fatalf("unexpected tuple column kind %q", b[0])
+			}
+		}
+		return "(" + strings.Join(vals, ", ") + ")"
+	}
+
+	switch msg[0] {
+	case 'B':
+		return "BEGIN", lastCommit
+	case 'C':
+		return "COMMIT", lsn.LSN(binary.BigEndian.Uint64(msg[10:]))
+	case 'R':
+		b := msg[5:]
+		var ns, name string
+		ns, b = readString(b)
+		name, b = readString(b)
+		n := int(binary.BigEndian.Uint16(b[1:]))
+		b = b[3:]
+		cols := make([]string, n)
+		for i := range cols {
+			key := b[0] == 1
+			cols[i], b = readString(b[1:])
+			if key {
+				cols[i] += " key"
+			}
+			// Skip the type OID and modifier.
+			b = b[8:]
+		}
+		return fmt.Sprintf("RELATION %s.%s (%s)", ns, name, strings.Join(cols, ", ")), lastCommit
+	case 'I':
+		return "INSERT " + readTuple(msg[6:]), lastCommit
+	case 'U':
+		return "UPDATE " + readTuple(msg[6:]), lastCommit
+	case 'D':
+		return "DELETE " + readTuple(msg[6:]), lastCommit
+	default:
+		t.Fatalf("unexpected message type %q", msg[0])
+		return "", lastCommit
+	}
+}
@@ -0,0 +1,561 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// This file implements the subset of the NATS client protocol needed by the
+// NATS sink: connecting and authenticating, publishing messages (optionally
+// with headers), and receiving the acknowledgements that JetStream sends to
+// the reply subject of published messages.
+//
+// https://docs.nats.io/reference/reference-protocols/nats-protocol
+
+const (
+	// natsClientName is the name with which the sink identifies itself to
+	// NATS servers.
+	natsClientName = `CockroachDB`
+	// natsKeyHeader is the header in which the key of a message is sent, when
+	// the server supports headers.
+	natsKeyHeader = `Cockroach-Changefeed-Key`
+	// natsNoRespondersStatus is the status of the message the server replies
+	// with when nothing is subscribed to the subject of a request, which for
+	// JetStream publishes means that no stream captures the subject.
+	natsNoRespondersStatus = `503`
+)
+
+var errNATSConnClosed = errors.New("NATS connection closed")
+
+// natsServerInfo is the part of the INFO message sent by NATS servers that is
+// used by the client.
+type natsServerInfo struct {
+	ServerID     string `json:"server_id"`
+	Version      string `json:"version"`
+	Headers      bool   `json:"headers"`
+	MaxPayload   int64  `json:"max_payload"`
+	TLSRequired  bool   `json:"tls_required"`
+	AuthRequired bool   `json:"auth_required"`
+}
+
+// natsConnectOptions is the CONNECT message sent by the client.
+type natsConnectOptions struct {
+	Verbose      bool   `json:"verbose"`
+	Pedantic     bool   `json:"pedantic"`
+	TLSRequired  bool   `json:"tls_required"`
+	Name         string `json:"name"`
+	Lang         string `json:"lang"`
+	Protocol     int    `json:"protocol"`
+	Headers      bool   `json:"headers"`
+	NoResponders bool   `json:"no_responders"`
+	User         string `json:"user,omitempty"`
+	Pass         string `json:"pass,omitempty"`
+	AuthToken    string `json:"auth_token,omitempty"`
+}
+
+// natsDialConfig configures connections to a NATS server.
+type natsDialConfig struct {
+	addr string
+	// tlsConfig is nil unless TLS is enabled.
+	tlsConfig *tls.Config
+
+	user, pass, token string
+
+	// jetStream subscribes the connection to an inbox on which the
+	// acknowledgements of published messages are received.
+	jetStream bool
+	// timeout bounds dialing, the handshake with the server, and waiting for
+	// published messages to be processed or acknowledged.
+	timeout time.Duration
+}
+
+// natsMessage is a message to publish to a NATS subject.
+type natsMessage struct {
+	subject string
+	key     []byte
+	data    []byte
+}
+
+// natsReply is a message received on the inbox of a connection.
+type natsReply struct {
+	// status is the status code of the headers of the message, if any.
+	status string
+	data   []byte
+	err    error
+}
+
+// natsConn is a connection to a NATS server. It is safe for concurrent use.
+type natsConn struct {
+	conn    net.Conn
+	info    natsServerInfo
+	inbox   string
+	timeout time.Duration
+
+	writeMu struct {
+		syncutil.Mutex
+		w *bufio.Writer
+	}
+
+	mu struct {
+		syncutil.Mutex
+		// err is set once the connection has failed or was closed.
+		err       error
+		nextReply uint64
+		// replies are the channels waiting for a message on the inbox, by the
+		// last token of their reply subject.
+		replies map[uint64]chan natsReply
+		// pongs are the channels waiting for a PONG, in the order in which the
+		// PINGs were sent.
+		pongs []chan error
+	}
+
+	// readerDone is closed once the reader goroutine has exited.
+	readerDone chan struct{}
+}
+
+// dialNATS connects to the NATS server configured by cfg.
+func dialNATS(ctx context.Context, cfg natsDialConfig) (*natsConn, error) {
+	dialer := net.Dialer{Timeout: cfg.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", cfg.addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "connecting to NATS server %s", cfg.addr)
+	}
+	c := &natsConn{
+		conn:       conn,
+		timeout:    cfg.timeout,
+		readerDone: make(chan struct{}),
+	}
+	c.mu.replies = make(map[uint64]chan natsReply)
+	r, err := c.handshake(cfg)
+	if err != nil {
+		_ = c.conn.Close()
+		return nil, errors.Wrapf(err, "connecting to NATS server %s", cfg.addr)
+	}
+	go c.readLoop(r)
+	return c, nil
+}
+
+// handshake reads the INFO message of the server, upgrades the connection to
+// TLS if needed, and authenticates. It returns the reader from which the
+// following messages of the server are read.
+func (c *natsConn) handshake(cfg natsDialConfig) (*bufio.Reader, error) {
+	if err := c.conn.SetDeadline(timeutil.Now().Add(c.timeout)); err != nil {
+		return nil, err
+	}
+	r := bufio.NewReader(c.conn)
+	op, args, err := readNATSOp(r)
+	if err != nil {
+		return nil, err
+	}
+	if op != "INFO" {
+		return nil, errors.Newf("expected INFO from server, got %s", op)
+	}
+	if err := json.Unmarshal([]byte(args), &c.info); err != nil {
+		return nil, errors.Wrap(err, "invalid INFO from server")
+	}
+
+	if c.info.TLSRequired && cfg.tlsConfig == nil {
+		return nil, errors.New("server requires TLS")
+	}
+	if cfg.tlsConfig != nil {
+		tlsCfg := cfg.tlsConfig.Clone()
+		if tlsCfg.ServerName == "" {
+			if host, _, err := net.SplitHostPort(cfg.addr); err == nil {
+				tlsCfg.ServerName = host
+			}
+		}
+		tlsConn := tls.Client(c.conn, tlsCfg)
+		if err := tlsConn.Handshake(); err != nil {
+			return nil, errors.Wrap(err, "TLS handshake")
+		}
+		c.conn = tlsConn
+		r = bufio.NewReader(c.conn)
+	}
+
+	connect, err := json.Marshal(natsConnectOptions{
+		TLSRequired:  cfg.tlsConfig != nil,
+		Name:         natsClientName,
+		Lang:         "go",
+		Protocol:     1,
+		Headers:      c.info.Headers,
+		NoResponders: c.info.Headers,
+		User:         cfg.user,
+		Pass:         cfg.pass,
+		AuthToken:    cfg.token,
+	})
+	if err != nil {
+		return nil, err
+	}
+	w := bufio.NewWriter(c.conn)
+	w.WriteString("CONNECT ")
+	w.Write(connect)
+	w.WriteString("\r\n")
+	if cfg.jetStream {
+		c.inbox = "_INBOX." + strings.ReplaceAll(uuid.MakeV4().String(), "-", "")
+		w.WriteString("SUB " + c.inbox + ".* 1\r\n")
+	}
+	// The PONG reply to this PING confirms that the server accepted the
+	// connection and the subscription.
+	w.WriteString("PING\r\n")
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	c.writeMu.w = w
+
+	for {
+		op, args, err := readNATSOp(r)
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case "PONG":
+			return r, c.conn.SetDeadline(time.Time{})
+		case "PING":
+			if _, err := c.conn.Write([]byte("PONG\r\n")); err != nil {
+				return nil, err
+			}
+		case "+OK", "INFO":
+		case "-ERR":
+			return nil, errors.Newf("server rejected connection: %s", strings.Trim(args, "'"))
+		default:
+			return nil, errors.Newf("unexpected %s from server", op)
+		}
+	}
+}
+
+// readNATSOp reads a protocol message from the server, and returns its
+// operation name and arguments.
+func readNATSOp(r *bufio.Reader) (op string, args string, err error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	op, args, _ = strings.Cut(line, " ")
+	return strings.ToUpper(op), strings.TrimSpace(args), nil
+}
+
+func (c *natsConn) readLoop(r *bufio.Reader) {
+	defer close(c.readerDone)
+	for {
+		if err := c.readOp(r); err != nil {
+			c.fail(err)
+			return
+		}
+	}
+}
+
+// readOp reads and handles a protocol message from the server.
+func (c *natsConn) readOp(r *bufio.Reader) error {
+	op, args, err := readNATSOp(r)
+	if err != nil {
+		return err
+	}
+	switch op {
+	case "PING":
+		return c.write(func(w *bufio.Writer) {
+			w.WriteString("PONG\r\n")
+		})
+	case "PONG":
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if len(c.mu.pongs) > 0 {
+			c.mu.pongs[0] <- nil
+			c.mu.pongs = c.mu.pongs[1:]
+		}
+		return nil
+	case "MSG", "HMSG":
+		return c.readMsg(r, op == "HMSG", args)
+	case "+OK", "INFO":
+		return nil
+	case "-ERR":
+		return errors.Newf("NATS server error: %s", strings.Trim(args, "'"))
+	default:
+		return errors.Newf("unexpected %s from NATS server", op)
+	}
+}
+
+// readMsg reads the payload of a MSG or HMSG protocol message, whose arguments
+// are:
+//
+//	MSG <subject> <sid> [reply-to] <#bytes>
+//	HMSG <subject> <sid> [reply-to] <#header bytes> <#total bytes>
+func (c *natsConn) readMsg(r *bufio.Reader, hasHeaders bool, args string) error {
+	fields := strings.Fields(args)
+	numSizes := 1
+	if hasHeaders {
+		numSizes = 2
+	}
+	if len(fields) < 2+numSizes || len(fields) > 3+numSizes {
+		return errors.Newf("invalid message from NATS server: %q", args)
+	}
+	subject := fields[0]
+	total, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || total < 0 {
+		return errors.Newf("invalid message from NATS server: %q", args)
+	}
+	var headerLen int
+	if hasHeaders {
+		headerLen, err = strconv.Atoi(fields[len(fields)-2])
+		if err != nil || headerLen < 0 || headerLen > total {
+			return errors.Newf("invalid message from NATS server: %q", args)
+		}
+	}
+	buf := make([]byte, total+2 /* \r\n */)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return err
+	}
+
+	reply := natsReply{data: buf[headerLen:total]}
+	if hasHeaders {
+		// The first line of the headers is the version, optionally followed by a
+		// status code and description, e.g. "NATS/1.0 503".
+		statusLine, _, _ := bytes.Cut(buf[:headerLen], []byte("\r\n"))
+		if f := strings.Fields(string(statusLine)); len(f) > 1 {
+			reply.status = f[1]
+		}
+	}
+
+	if !strings.HasPrefix(subject, c.inbox+".") {
+		return nil
+	}
+	id, err := strconv.ParseUint(strings.TrimPrefix(subject, c.inbox+"."), 10, 64)
+	if err != nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ch, ok := c.mu.replies[id]; ok {
+		delete(c.mu.replies, id)
+		ch <- reply
+	}
+	return nil
+}
+
+// write calls fn with the writer of the connection, and flushes it.
+func (c *natsConn) write(fn func(w *bufio.Writer)) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.writeLocked(fn)
+}
+
+func (c *natsConn) writeLocked(fn func(w *bufio.Writer)) error {
+	if err := c.conn.SetWriteDeadline(timeutil.Now().Add(c.timeout)); err != nil {
+		return err
+	}
+	fn(c.writeMu.w)
+	return c.writeMu.w.Flush()
+}
+
+// fail marks the connection as failed with the given error, which is
+// returned to all callers waiting for the server, and closes it.
+func (c *natsConn) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mu.err != nil {
+		return
+	}
+	c.mu.err = err
+	for id, ch := range c.mu.replies {
+		ch <- natsReply{err: err}
+		delete(c.mu.replies, id)
+	}
+	for _, ch := range c.mu.pongs {
+		ch <- err
+	}
+	c.mu.pongs = nil
+	_ = c.conn.Close()
+}
+
+// err returns the error with which the connection failed, if any.
+func (c *natsConn) err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mu.err
+}
+
+// close closes the connection and waits for its reader goroutine to exit.
+func (c *natsConn) close() {
+	c.fail(errNATSConnClosed)
+	<-c.readerDone
+}
+
+// publish publishes the messages. If jetStream is true, the messages are sent
+// with a reply subject and publish returns once JetStream has acknowledged
+// all of them. Otherwise, it returns once the server has processed them.
+func (c *natsConn) publish(ctx context.Context, msgs []natsMessage, jetStream bool) error {
+	if jetStream && c.inbox == "" {
+		return errors.AssertionFailedf("JetStream publish on a connection without an inbox")
+	}
+	for _, m := range msgs {
+		if size := int64(len(m.data) + len(m.key)); c.info.MaxPayload > 0 && size > c.info.MaxPayload {
+			return errors.Newf("message of %d bytes exceeds the maximum payload of %d bytes of the NATS server",
+				size, c.info.MaxPayload)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	c.writeMu.Lock()
+	c.mu.Lock()
+	if err := c.mu.err; err != nil {
+		c.mu.Unlock()
+		c.writeMu.Unlock()
+		return err
+	}
+	var replyIDs []uint64
+	var replies []chan natsReply
+	var pong chan error
+	if jetStream {
+		replyIDs = make([]uint64, len(msgs))
+		replies = make([]chan natsReply, len(msgs))
+		for i := range msgs {
+			c.mu.nextReply++
+			replyIDs[i] = c.mu.nextReply
+			replies[i] = make(chan natsReply, 1)
+			c.mu.replies[replyIDs[i]] = replies[i]
+		}
+	} else {
+		pong = make(chan error, 1)
+		c.mu.pongs = append(c.mu.pongs, pong)
+	}
+	c.mu.Unlock()
+	err := c.writeLocked(func(w *bufio.Writer) {
+		for i, m := range msgs {
+			var reply string
+			if jetStream {
+				reply = c.inbox + "." + strconv.FormatUint(replyIDs[i], 10)
+			}
+			c.writeMessage(w, m, reply)
+		}
+		if !jetStream {
+			// The server processes the messages of a connection in order, so once
+			// it replies to this PING it has processed all the messages.
+			w.WriteString("PING\r\n")
+		}
+	})
+	c.writeMu.Unlock()
+	if err != nil {
+		c.fail(err)
+		return err
+	}
+
+	if !jetStream {
+		select {
+		case err := <-pong:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	defer func() {
+		// Stop waiting for the acknowledgements that did not arrive.
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for _, id := range replyIDs {
+			delete(c.mu.replies, id)
+		}
+	}()
+	for i, ch := range replies {
+		select {
+		case reply := <-ch:
+			if err := checkJetStreamAck(msgs[i].subject, reply); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "waiting for JetStream to acknowledge message on subject %s",
+				msgs[i].subject)
+		}
+	}
+	return nil
+}
+
+// writeMessage writes a PUB, or if the message has a key and the server
+// supports headers, an HPUB protocol message.
+func (c *natsConn) writeMessage(w *bufio.Writer, m natsMessage, reply string) {
+	var header []byte
+	if c.info.Headers && len(m.key) > 0 && !bytes.ContainsAny(m.key, "\r\n") {
+		header = make([]byte, 0, len("NATS/1.0\r\n")+len(natsKeyHeader)+len(m.key)+6)
+		header = append(header, "NATS/1.0\r\n"...)
+		header = append(header, natsKeyHeader+": "...)
+		header = append(header, m.key...)
+		header = append(header, "\r\n\r\n"...)
+	}
+	if header != nil {
+		w.WriteString("HPUB ")
+	} else {
+		w.WriteString("PUB ")
+	}
+	w.WriteString(m.subject)
+	w.WriteByte(' ')
+	if reply != "" {
+		w.WriteString(reply)
+		w.WriteByte(' ')
+	}
+	if header != nil {
+		w.WriteString(strconv.Itoa(len(header)))
+		w.WriteByte(' ')
+	}
+	w.WriteString(strconv.Itoa(len(header) + len(m.data)))
+	w.WriteString("\r\n")
+	w.Write(header)
+	w.Write(m.data)
+	w.WriteString("\r\n")
+}
+
+// checkJetStreamAck returns an error unless the reply is a successful
+// JetStream acknowledgement of a message published on the subject.
+func checkJetStreamAck(subject string, reply natsReply) error {
+	if reply.err != nil {
+		return reply.err
+	}
+	switch reply.status {
+	case "":
+	case natsNoRespondersStatus:
+		return errors.Newf("no JetStream stream captures subject %s", subject)
+	default:
+		return errors.Newf("unexpected status %s publishing to JetStream subject %s", reply.status, subject)
+	}
+	var ack struct {
+		Stream string `json:"stream"`
+		Seq    uint64 `json:"seq"`
+		Error  *struct {
+			Code        int    `json:"code"`
+			Description string `json:"description"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(reply.data, &ack); err != nil {
+		return errors.Wrapf(err, "invalid JetStream acknowledgement %q", reply.data)
+	}
+	if ack.Error != nil {
+		return errors.Newf("JetStream rejected message on subject %s: %s (%d)",
+			subject, ack.Error.Description, ack.Error.Code)
+	}
+	if ack.Stream == "" {
+		return errors.Newf("invalid JetStream acknowledgement %q", reply.data)
+	}
+	return nil
+}
@@ -0,0 +1,472 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// This file maps SQL rows to protobuf messages, in the same spirit as the
+// mapping to avro records in avro.go.
+//
+// A SQL table schema is mapped to a proto3 message with one field per column.
+// Every field is declared `optional`, regardless of the nullability of the
+// column, so that NULLs can be told apart from zero values. The number of the
+// field is the ID of the column. Column IDs are never reused, and changing the
+// type of a column creates a new column, so the successive messages generated
+// for a table as its schema evolves are compatible with each other: a new
+// column adds a field and a dropped column removes one, but a field number
+// never changes meaning.
+//
+// Types with a native protobuf equivalent are mapped to it. Every other type is
+// encoded as a string holding the textual representation of the datum. Like in
+// proto3, the names of the columns of a table must not only differ by case or
+// underscores.
+//
+// The messages are defined in proto3 files which are registered with the
+// schema registry in the protobuf language. The descriptors of these files are
+// also built in memory, and used to encode the messages with dynamicpb.
+
+// protobufFieldEncoder converts a non-NULL datum to the value of a field.
+type protobufFieldEncoder func(tree.Datum) (protoreflect.Value, error)
+
+// protobufField is the protobuf field that a column is mapped to.
+type protobufField struct {
+	name     string
+	number   protoreflect.FieldNumber
+	typ      descriptorpb.FieldDescriptorProto_Type
+	encodeFn protobufFieldEncoder
+
+	// desc is set once the file defining the message is built.
+	desc protoreflect.FieldDescriptor
+}
+
+// protobufDataMessage is the protobuf message holding the columns of a SQL
+// table or index.
+type protobufDataMessage struct {
+	name           string
+	fields         []*protobufField
+	fieldIdxByName map[string]int
+
+	// desc is set once the file defining the message is built.
+	desc protoreflect.MessageDescriptor
+}
+
+// protobufFile is a protobuf file registered with the schema registry.
+// Changefeed messages are always encoded as the first message of the file.
+type protobufFile struct {
+	// schema is the definition of the file in the protobuf language.
+	schema string
+	desc   protoreflect.MessageDescriptor
+}
+
+// protobufKeyMessage is the protobuf file holding the primary key columns of
+// a SQL table.
+type protobufKeyMessage struct {
+	protobufFile
+	key *protobufDataMessage
+}
+
+// protobufEnvelopeOpts controls which fields in protobufEnvelopeMessage are
+// set.
+type protobufEnvelopeOpts struct {
+	beforeField, afterField, recordField bool
+	updatedField, resolvedField          bool
+}
+
+// protobufMetadata is the protobufEnvelopeMessage metadata.
+type protobufMetadata struct {
+	updated, resolved hlc.Timestamp
+}
+
+// protobufEnvelopeMessage is the protobuf file holding an envelope message,
+// which wraps a changed SQL row and some metadata, followed by the messages
+// of the row.
+type protobufEnvelopeMessage struct {
+	protobufFile
+
+	opts                  protobufEnvelopeOpts
+	before, after, record *protobufDataMessage
+}
+
+// The field numbers of an envelope message don't depend on the envelope
+// options, so that the envelope of a changefeed stays compatible if these
+// options change.
+const (
+	protobufAfterFieldNumber protoreflect.FieldNumber = iota + 1
+	protobufBeforeFieldNumber
+	protobufUpdatedFieldNumber
+	protobufResolvedFieldNumber
+	protobufRecordFieldNumber
+)
+
+// typeToProtobufType returns the type of the protobuf field that a column of
+// the given type is mapped to, and the function encoding its datums.
+func typeToProtobufType(
+	typ *types.T,
+) (descriptorpb.FieldDescriptorProto_Type, protobufFieldEncoder) {
+	switch typ.Family() {
+	case types.BoolFamily:
+		return descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+			func(d tree.Datum) (protoreflect.Value, error) {
+				return protoreflect.ValueOfBool(bool(*d.(*tree.DBool))), nil
+			}
+	case types.IntFamily:
+		if typ.Width() == 64 {
+			return descriptorpb.FieldDescriptorProto_TYPE_INT64,
+				func(d tree.Datum) (protoreflect.Value, error) {
+					return protoreflect.ValueOfInt64(int64(*d.(*tree.DInt))), nil
+				}
+		}
+		return descriptorpb.FieldDescriptorProto_TYPE_INT32,
+			func(d tree.Datum) (protoreflect.Value, error) {
+				return protoreflect.ValueOfInt32(int32(*d.(*tree.DInt))), nil
+			}
+	case types.FloatFamily:
+		if typ.Width() == 32 {
+			return descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+				func(d tree.Datum) (protoreflect.Value, error) {
+					return protoreflect.ValueOfFloat32(float32(*d.(*tree.DFloat))), nil
+				}
+		}
+		return descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+			func(d tree.Datum) (protoreflect.Value, error) {
+				return protoreflect.ValueOfFloat64(float64(*d.(*tree.DFloat))), nil
+			}
+	case types.StringFamily:
+		return descriptorpb.FieldDescriptorProto_TYPE_STRING,
+			func(d tree.Datum) (protoreflect.Value, error) {
+				return protoreflect.ValueOfString(string(*d.(*tree.DString))), nil
+			}
+	case types.CollatedStringFamily:
+		return descriptorpb.FieldDescriptorProto_TYPE_STRING,
+			func(d tree.Datum) (protoreflect.Value, error) {
+				return protoreflect.ValueOfString(d.(*tree.DCollatedString).Contents), nil
+			}
+	case types.BytesFamily:
+		return descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+			func(d tree.Datum) (protoreflect.Value, error) {
+				return protoreflect.ValueOfBytes([]byte(*d.(*tree.DBytes))), nil
+			}
+	default:
+		return descriptorpb.FieldDescriptorProto_TYPE_STRING,
+			func(d tree.Datum) (protoreflect.Value, error) {
+				return protoreflect.ValueOfString(tree.AsStringWithFlags(d, tree.FmtExport)), nil
+			}
+	}
+}
+
+// newProtobufDataMessage constructs the protobuf message for the columns
+// returned by the given iterator.
+func newProtobufDataMessage(it cdcevent.Iterator, name string) (*protobufDataMessage, error) {
+	var cols []cdcevent.ResultColumn
+	// Columns which aren't backed by a table column, such as the columns of a
+	// projection, have no ID. Their position is used as field number instead.
+	useColumnIDs := true
+	if err := it.Col(func(col cdcevent.ResultColumn) error {
+		cols = append(cols, col)
+		useColumnIDs = useColumnIDs && col.PGAttributeNum != 0
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	msg := &protobufDataMessage{
+		name:           SQLNameToAvroName(name),
+		fieldIdxByName: make(map[string]int, len(cols)),
+	}
+	for i, col := range cols {
+		number := protoreflect.FieldNumber(i + 1)
+		if useColumnIDs {
+			number = protoreflect.FieldNumber(col.PGAttributeNum)
+		}
+		typ, encodeFn := typeToProtobufType(col.Typ)
+		msg.fieldIdxByName[col.Name] = len(msg.fields)
+		msg.fields = append(msg.fields, &protobufField{
+			// Protobuf identifiers have the same restrictions as avro names.
+			name:     SQLNameToAvroName(col.Name),
+			number:   number,
+			typ:      typ,
+			encodeFn: encodeFn,
+		})
+	}
+	return msg, nil
+}
+
+// descriptorProto returns the descriptor of the message.
+func (m *protobufDataMessage) descriptorProto() *descriptorpb.DescriptorProto {
+	desc := &descriptorpb.DescriptorProto{Name: proto.String(m.name)}
+	names := make(map[string]struct{}, len(m.fields))
+	for _, f := range m.fields {
+		names[f.name] = struct{}{}
+	}
+	for _, f := range m.fields {
+		// Optional proto3 fields belong to a synthetic oneof, named like protoc
+		// does.
+		oneof := `_` + f.name
+		for _, ok := names[oneof]; ok; _, ok = names[oneof] {
+			oneof = `X` + oneof
+		}
+		names[oneof] = struct{}{}
+		desc.Field = append(desc.Field, &descriptorpb.FieldDescriptorProto{
+			Name:           proto.String(f.name),
+			Number:         proto.Int32(int32(f.number)),
+			Label:          descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:           f.typ.Enum(),
+			OneofIndex:     proto.Int32(int32(len(desc.OneofDecl))),
+			Proto3Optional: proto.Bool(true),
+		})
+		desc.OneofDecl = append(desc.OneofDecl, &descriptorpb.OneofDescriptorProto{
+			Name: proto.String(oneof),
+		})
+	}
+	return desc
+}
+
+// resolve sets the descriptors of the message and its fields, using the given
+// file.
+func (m *protobufDataMessage) resolve(file protoreflect.FileDescriptor) {
+	m.desc = file.Messages().ByName(protoreflect.Name(m.name))
+	for _, f := range m.fields {
+		f.desc = m.desc.Fields().ByNumber(f.number)
+	}
+}
+
+// messageFromRow returns the message holding the datums returned by the given
+// iterator.
+func (m *protobufDataMessage) messageFromRow(it cdcevent.Iterator) (*dynamicpb.Message, error) {
+	msg := dynamicpb.NewMessage(m.desc)
+	if err := it.Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		fieldIdx, ok := m.fieldIdxByName[col.Name]
+		if !ok {
+			return changefeedbase.WithTerminalError(
+				errors.AssertionFailedf("could not find protobuf field for column %s", col.Name))
+		}
+		if d == tree.DNull {
+			return nil
+		}
+		f := m.fields[fieldIdx]
+		v, err := f.encodeFn(tree.UnwrapDOidWrapper(d))
+		if err != nil {
+			return err
+		}
+		msg.Set(f.desc, v)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// newProtobufFile builds a proto3 file holding the given messages. The first
+// message is the one changefeed messages are encoded as.
+func newProtobufFile(
+	name string, msgs ...*descriptorpb.DescriptorProto,
+) (protobufFile, protoreflect.FileDescriptor, error) {
+	fileProto := &descriptorpb.FileDescriptorProto{
+		Name:        proto.String(name + `.proto`),
+		Syntax:      proto.String(`proto3`),
+		MessageType: msgs,
+	}
+	file, err := protodesc.NewFile(fileProto, nil /* resolver */)
+	if err != nil {
+		return protobufFile{}, nil, changefeedbase.WithTerminalError(
+			errors.Wrapf(err, "building protobuf schema %s", name))
+	}
+	return protobufFile{
+		schema: protobufFileToString(fileProto),
+		desc:   file.Messages().Get(0),
+	}, file, nil
+}
+
+// protobufFileToString returns the definition of the given file in the
+// protobuf language. Only the constructs used by changefeeds are supported.
+func protobufFileToString(file *descriptorpb.FileDescriptorProto) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "syntax = %q;\n", file.GetSyntax())
+	for _, msg := range file.MessageType {
+		fmt.Fprintf(&b, "\nmessage %s {\n", msg.GetName())
+		for _, f := range msg.Field {
+			typ := strings.ToLower(strings.TrimPrefix(f.GetType().String(), `TYPE_`))
+			if f.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
+				typ = strings.TrimPrefix(f.GetTypeName(), `.`)
+			}
+			if f.GetProto3Optional() {
+				typ = `optional ` + typ
+			}
+			fmt.Fprintf(&b, "  %s %s = %d;\n", typ, f.GetName(), f.GetNumber())
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// newProtobufKeyMessage constructs the protobuf file for the columns returned
+// by the given iterator, which are the key of the changefeed messages.
+func newProtobufKeyMessage(it cdcevent.Iterator, sqlName string) (*protobufKeyMessage, error) {
+	key, err := newProtobufDataMessage(it, sqlName)
+	if err != nil {
+		return nil, err
+	}
+	pf, file, err := newProtobufFile(key.name, key.descriptorProto())
+	if err != nil {
+		return nil, err
+	}
+	key.resolve(file)
+	return &protobufKeyMessage{protobufFile: pf, key: key}, nil
+}
+
+// tableToProtobufMessage constructs the protobuf message for the values of the
+// given row. If a name suffix is provided, it is appended to the name of the
+// message.
+func tableToProtobufMessage(row cdcevent.Row, nameSuffix string) (*protobufDataMessage, error) {
+	sqlName := row.TableName
+	// Like with avro, the family name is only part of the name of the message
+	// for tables with multiple families.
+	if row.HasOtherFamilies {
+		sqlName += `.` + row.FamilyName
+	}
+	if nameSuffix != `` {
+		sqlName += `_` + nameSuffix
+	}
+	return newProtobufDataMessage(row.ForEachColumn(), sqlName)
+}
+
+// newProtobufEnvelopeMessage constructs the protobuf file for an envelope
+// containing before and after versions of a row change and metadata about that
+// row change. before is optional, and after can instead be record.
+func newProtobufEnvelopeMessage(
+	topic string, opts protobufEnvelopeOpts, before, after, record *protobufDataMessage,
+) (*protobufEnvelopeMessage, error) {
+	m := &protobufEnvelopeMessage{opts: opts}
+	envelope := &descriptorpb.DescriptorProto{
+		Name: proto.String(SQLNameToAvroName(topic) + `_envelope`),
+	}
+	msgs := []*descriptorpb.DescriptorProto{envelope}
+	addMessageField := func(name string, number protoreflect.FieldNumber, data *protobufDataMessage) {
+		envelope.Field = append(envelope.Field, &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			Number:   proto.Int32(int32(number)),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String(`.` + data.name),
+		})
+		msgs = append(msgs, data.descriptorProto())
+	}
+	addTimestampField := func(name string, number protoreflect.FieldNumber) {
+		envelope.Field = append(envelope.Field, &descriptorpb.FieldDescriptorProto{
+			Name:           proto.String(name),
+			Number:         proto.Int32(int32(number)),
+			Label:          descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:           descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			OneofIndex:     proto.Int32(int32(len(envelope.OneofDecl))),
+			Proto3Optional: proto.Bool(true),
+		})
+		envelope.OneofDecl = append(envelope.OneofDecl, &descriptorpb.OneofDescriptorProto{
+			Name: proto.String(`_` + name),
+		})
+	}
+
+	if opts.afterField {
+		m.after = after
+		addMessageField(`after`, protobufAfterFieldNumber, after)
+	}
+	if opts.beforeField {
+		m.before = before
+		addMessageField(`before`, protobufBeforeFieldNumber, before)
+	}
+	if opts.updatedField {
+		addTimestampField(`updated`, protobufUpdatedFieldNumber)
+	}
+	if opts.resolvedField {
+		addTimestampField(`resolved`, protobufResolvedFieldNumber)
+	}
+	if opts.recordField {
+		m.record = record
+		addMessageField(`record`, protobufRecordFieldNumber, record)
+	}
+
+	var file protoreflect.FileDescriptor
+	var err error
+	m.protobufFile, file, err = newProtobufFile(envelope.GetName(), msgs...)
+	if err != nil {
+		return nil, err
+	}
+	for _, data := range []*protobufDataMessage{m.before, m.after, m.record} {
+		if data != nil {
+			data.resolve(file)
+		}
+	}
+	return m, nil
+}
+
+// BinaryFromRow encodes the given row data into protobuf's binary format,
+// appended to buf.
+func (m *protobufKeyMessage) BinaryFromRow(buf []byte, it cdcevent.Iterator) ([]byte, error) {
+	msg, err := m.key.messageFromRow(it)
+	if err != nil {
+		return nil, err
+	}
+	return proto.MarshalOptions{Deterministic: true}.MarshalAppend(buf, msg)
+}
+
+// BinaryFromRow encodes the given metadata and row data into protobuf's binary
+// format, appended to buf.
+func (m *protobufEnvelopeMessage) BinaryFromRow(
+	buf []byte, meta protobufMetadata, beforeRow, afterRow, recordRow cdcevent.Row,
+) ([]byte, error) {
+	msg := dynamicpb.NewMessage(m.desc)
+	fields := m.desc.Fields()
+	setRow := func(number protoreflect.FieldNumber, data *protobufDataMessage, row cdcevent.Row) error {
+		dataMsg, err := data.messageFromRow(row.ForEachColumn())
+		if err != nil {
+			return err
+		}
+		msg.Set(fields.ByNumber(number), protoreflect.ValueOfMessage(dataMsg))
+		return nil
+	}
+
+	if m.opts.afterField && afterRow.HasValues() && !afterRow.IsDeleted() {
+		if err := setRow(protobufAfterFieldNumber, m.after, afterRow); err != nil {
+			return nil, err
+		}
+	}
+	if m.opts.beforeField && beforeRow.HasValues() && !beforeRow.IsDeleted() {
+		if err := setRow(protobufBeforeFieldNumber, m.before, beforeRow); err != nil {
+			return nil, err
+		}
+	}
+	if m.opts.recordField && recordRow.HasValues() {
+		if err := setRow(protobufRecordFieldNumber, m.record, recordRow); err != nil {
+			return nil, err
+		}
+	}
+	if m.opts.updatedField && !meta.updated.IsEmpty() {
+		msg.Set(fields.ByNumber(protobufUpdatedFieldNumber),
+			protoreflect.ValueOfString(timestampToString(meta.updated)))
+	}
+	if m.opts.resolvedField && !meta.resolved.IsEmpty() {
+		msg.Set(fields.ByNumber(protobufResolvedFieldNumber),
+			protoreflect.ValueOfString(timestampToString(meta.resolved)))
+	}
+	return proto.MarshalOptions{Deterministic: true}.MarshalAppend(buf, msg)
+}
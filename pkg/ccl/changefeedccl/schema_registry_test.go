@@ -113,7 +113,7 @@ func TestConfluentSchemaRegistrySharedCache(t *testing.T) {
 		go func() {
 			r, err := newConfluentSchemaRegistry(regServer.URL(), nil, nil)
 			require.NoError(t, err)
-			_, err = r.RegisterSchemaForSubject(context.Background(), "subject1", "schema")
+			_, err = r.RegisterSchemaForSubject(context.Background(), "subject1", "schema", schemaTypeAvro)
 			require.NoError(t, err)
 			wg.Done()
 
@@ -128,7 +128,7 @@ func TestConfluentSchemaRegistrySharedCache(t *testing.T) {
 		go func(i int) {
 			r, err := newConfluentSchemaRegistry(regServer.URL(), nil, nil)
 			require.NoError(t, err)
-			_, err = r.RegisterSchemaForSubject(context.Background(), "subject1", fmt.Sprintf("schema1%d", i))
+			_, err = r.RegisterSchemaForSubject(context.Background(), "subject1", fmt.Sprintf("schema1%d", i), schemaTypeAvro)
 			require.NoError(t, err)
 			wg.Done()
 
@@ -180,7 +180,7 @@ func TestConfluentSchemaRegistryRetryMetrics(t *testing.T) {
 		require.NoError(t, err)
 		ctx, cancel := context.WithCancel(context.Background())
 		go func() {
-			_, err = reg.RegisterSchemaForSubject(ctx, "subject1", "schema1")
+			_, err = reg.RegisterSchemaForSubject(ctx, "subject1", "schema1", schemaTypeAvro)
 		}()
 		require.NoError(t, err)
 		testutils.SucceedsSoon(t, func() error {
@@ -0,0 +1,387 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// natsDefaultPort is the port of NATS servers when the sink URI doesn't
+	// have one.
+	natsDefaultPort = `4222`
+	// natsTimeout bounds dialing NATS servers and waiting for them to process
+	// or acknowledge published messages.
+	natsTimeout = 30 * time.Second
+)
+
+// NATS subjects are made of tokens separated by `.`, and may not contain
+// whitespace or the `*` and `>` wildcards. Topic names are allowed to contain
+// `.`, so that column families and topic prefixes can form their own tokens.
+var natsSubjectDisallowedRE = regexp.MustCompile(`[^\x21-\x7e]|[*>]`)
+var natsTokenDisallowedRE = regexp.MustCompile(`[^\x21-\x7e]|[.*>]`)
+
+// sqlNameToNATSSubject escapes a sql table name into a valid NATS subject.
+func sqlNameToNATSSubject(s string) string {
+	return escapeSQLName(s, natsSubjectDisallowedRE)
+}
+
+func isNATSSink(u *url.URL) bool {
+	return u.Scheme == changefeedbase.SinkSchemeNATS
+}
+
+// natsSinkClient publishes messages to the subjects of a NATS server. Each
+// topic is published to the subject of the same name. With the
+// subject_per_key param, each message is published to a subject made of the
+// topic name followed by a token for each column of its key, such that
+// consumers can subscribe to the changes of individual rows.
+//
+// With the jetstream param, messages are published with a reply subject, and
+// a flush completes once JetStream has acknowledged that it stored all the
+// messages. This requires a JetStream stream that captures the subjects of
+// the changefeed. Otherwise, a flush completes once the server has processed
+// the messages, which does not guarantee delivery to any subscriber.
+type natsSinkClient struct {
+	batchCfg      sinkBatchConfig
+	dialCfg       natsDialConfig
+	subjectPerKey bool
+
+	mu struct {
+		syncutil.Mutex
+		conn *natsConn
+	}
+}
+
+var _ SinkClient = (*natsSinkClient)(nil)
+var _ SinkPayload = (*natsPublishRequest)(nil)
+
+// natsPublishRequest is a batch of messages to publish.
+type natsPublishRequest struct {
+	messages []natsMessage
+}
+
+func makeNATSSinkClient(
+	u sinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	batchCfg sinkBatchConfig,
+) (*natsSinkClient, error) {
+	if !isNATSSink(u.URL) {
+		return nil, errors.Errorf("unknown scheme: %s", u.Scheme)
+	}
+
+	switch encodingOpts.Format {
+	case changefeedbase.OptFormatJSON, changefeedbase.OptFormatCSV:
+	default:
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptFormat, encodingOpts.Format)
+	}
+
+	switch encodingOpts.Envelope {
+	case changefeedbase.OptEnvelopeWrapped, changefeedbase.OptEnvelopeBare:
+	default:
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptEnvelope, encodingOpts.Envelope)
+	}
+
+	sinkClient := &natsSinkClient{batchCfg: batchCfg}
+	if _, err := u.consumeBool(changefeedbase.SinkParamSubjectPerKey, &sinkClient.subjectPerKey); err != nil {
+		return nil, err
+	}
+	if sinkClient.subjectPerKey && encodingOpts.Format != changefeedbase.OptFormatJSON {
+		return nil, errors.Errorf(`%s requires %s=%s`,
+			changefeedbase.SinkParamSubjectPerKey, changefeedbase.OptFormat, changefeedbase.OptFormatJSON)
+	}
+
+	dialCfg, err := makeNATSDialConfig(u)
+	if err != nil {
+		return nil, err
+	}
+	sinkClient.dialCfg = dialCfg
+	return sinkClient, nil
+}
+
+func makeNATSDialConfig(u sinkURL) (natsDialConfig, error) {
+	cfg := natsDialConfig{timeout: natsTimeout}
+
+	if u.Host == "" {
+		return cfg, errors.New("missing NATS server address")
+	}
+	cfg.addr = u.Host
+	if u.Port() == "" {
+		cfg.addr = net.JoinHostPort(u.Hostname(), natsDefaultPort)
+	}
+
+	// Credentials are either a user and password, or a token.
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			cfg.user, cfg.pass = u.User.Username(), pass
+		} else {
+			cfg.token = u.User.Username()
+		}
+	}
+
+	if _, err := u.consumeBool(changefeedbase.SinkParamJetStream, &cfg.jetStream); err != nil {
+		return cfg, err
+	}
+
+	var tlsEnabled, tlsSkipVerify bool
+	var caCert, clientCert, clientKey []byte
+	if _, err := u.consumeBool(changefeedbase.SinkParamTLSEnabled, &tlsEnabled); err != nil {
+		return cfg, err
+	}
+	if _, err := u.consumeBool(changefeedbase.SinkParamSkipTLSVerify, &tlsSkipVerify); err != nil {
+		return cfg, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamCACert, &caCert); err != nil {
+		return cfg, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientCert, &clientCert); err != nil {
+		return cfg, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientKey, &clientKey); err != nil {
+		return cfg, err
+	}
+
+	if !tlsEnabled {
+		if caCert != nil {
+			return cfg, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamCACert, changefeedbase.SinkParamTLSEnabled)
+		}
+		if clientCert != nil {
+			return cfg, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamTLSEnabled)
+		}
+		return cfg, nil
+	}
+
+	cfg.tlsConfig = &tls.Config{
+		InsecureSkipVerify: tlsSkipVerify,
+	}
+	if caCert != nil {
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return cfg, errors.Errorf("failed to parse certificate data:%s", string(caCert))
+		}
+		cfg.tlsConfig.RootCAs = caCertPool
+	}
+	if clientCert != nil && clientKey == nil {
+		return cfg, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamClientKey)
+	} else if clientKey != nil && clientCert == nil {
+		return cfg, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientKey, changefeedbase.SinkParamClientCert)
+	}
+	if clientCert != nil && clientKey != nil {
+		cert, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return cfg, errors.Wrap(err, `invalid client certificate data provided`)
+		}
+		cfg.tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// getConn returns the connection to the server, connecting again if the
+// previous connection failed.
+func (sc *natsSinkClient) getConn(ctx context.Context) (*natsConn, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.mu.conn != nil {
+		if sc.mu.conn.err() == nil {
+			return sc.mu.conn, nil
+		}
+		sc.mu.conn.close()
+		sc.mu.conn = nil
+	}
+	conn, err := dialNATS(ctx, sc.dialCfg)
+	if err != nil {
+		return nil, err
+	}
+	sc.mu.conn = conn
+	return conn, nil
+}
+
+// FlushResolvedPayload implements the SinkClient interface.
+func (sc *natsSinkClient) FlushResolvedPayload(
+	ctx context.Context,
+	body []byte,
+	forEachTopic func(func(topic string) error) error,
+	retryOpts retry.Options,
+) error {
+	return forEachTopic(func(topic string) error {
+		pl := &natsPublishRequest{messages: []natsMessage{{subject: topic, data: body}}}
+		return retry.WithMaxAttempts(ctx, retryOpts, retryOpts.MaxRetries+1, func() error {
+			return sc.Flush(ctx, pl)
+		})
+	})
+}
+
+// Flush implements the SinkClient interface.
+func (sc *natsSinkClient) Flush(ctx context.Context, payload SinkPayload) error {
+	req := payload.(*natsPublishRequest)
+	conn, err := sc.getConn(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.publish(ctx, req.messages, sc.dialCfg.jetStream)
+}
+
+// Close implements the SinkClient interface.
+func (sc *natsSinkClient) Close() error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.mu.conn != nil {
+		sc.mu.conn.close()
+		sc.mu.conn = nil
+	}
+	return nil
+}
+
+// MakeBatchBuffer implements the SinkClient interface.
+func (sc *natsSinkClient) MakeBatchBuffer(topic string) BatchBuffer {
+	return &natsBuffer{
+		sc:       sc,
+		topic:    topic,
+		messages: make([]natsMessage, 0, sc.batchCfg.Messages),
+	}
+}
+
+type natsBuffer struct {
+	sc       *natsSinkClient
+	topic    string
+	messages []natsMessage
+	numBytes int
+	err      error
+}
+
+var _ BatchBuffer = (*natsBuffer)(nil)
+
+// Append implements the BatchBuffer interface.
+func (nb *natsBuffer) Append(key []byte, value []byte) {
+	subject := nb.topic
+	if nb.sc.subjectPerKey {
+		tokens, err := natsSubjectTokensFromKey(key)
+		if err != nil {
+			if nb.err == nil {
+				nb.err = err
+			}
+			return
+		}
+		subject += "." + tokens
+	}
+	nb.messages = append(nb.messages, natsMessage{subject: subject, key: key, data: value})
+	nb.numBytes += len(key) + len(value)
+}
+
+// ShouldFlush implements the BatchBuffer interface.
+func (nb *natsBuffer) ShouldFlush() bool {
+	return shouldFlushBatch(nb.numBytes, len(nb.messages), nb.sc.batchCfg)
+}
+
+// Close implements the BatchBuffer interface.
+func (nb *natsBuffer) Close() (SinkPayload, error) {
+	if nb.err != nil {
+		return nil, nb.err
+	}
+	return &natsPublishRequest{messages: nb.messages}, nil
+}
+
+// natsSubjectTokensFromKey returns the subject tokens for a key encoded as a
+// JSON array, one for each key column. Strings are used as is, other values
+// are rendered as JSON. Characters that are not allowed in tokens are escaped
+// like in topic names.
+func natsSubjectTokensFromKey(key []byte) (string, error) {
+	var cols []json.RawMessage
+	if err := json.Unmarshal(key, &cols); err != nil {
+		return "", errors.Wrapf(err, "key %s is not a JSON array", key)
+	}
+	if len(cols) == 0 {
+		return "", errors.Newf("key %s has no columns", key)
+	}
+	tokens := make([]string, len(cols))
+	for i, col := range cols {
+		token := string(col)
+		if len(col) > 0 && col[0] == '"' {
+			if err := json.Unmarshal(col, &token); err != nil {
+				return "", err
+			}
+		}
+		if token == "" {
+			// Tokens cannot be empty, so escape the quotes of empty strings.
+			token = `""`
+		}
+		tokens[i] = escapeSQLName(token, natsTokenDisallowedRE)
+	}
+	return strings.Join(tokens, "."), nil
+}
+
+func makeNATSSink(
+	ctx context.Context,
+	u sinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	jsonConfig changefeedbase.SinkSpecificJSONConfig,
+	targets changefeedbase.Targets,
+	parallelism int,
+	pacerFactory func() *admission.Pacer,
+	source timeutil.TimeSource,
+	mb metricsRecorderBuilder,
+) (Sink, error) {
+	batchCfg, retryOpts, err := getSinkConfigFromJson(jsonConfig, sinkJSONConfig{})
+	if err != nil {
+		return nil, err
+	}
+
+	subjectPrefix := u.consumeParam(changefeedbase.SinkParamTopicPrefix)
+	subjectName := u.consumeParam(changefeedbase.SinkParamTopicName)
+	topicNamer, err := MakeTopicNamer(targets,
+		WithPrefix(subjectPrefix), WithSingleName(subjectName), WithSanitizeFn(sqlNameToNATSSubject))
+	if err != nil {
+		return nil, err
+	}
+
+	sinkClient, err := makeNATSSinkClient(u, encodingOpts, batchCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if unknownParams := u.remainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown NATS sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	// Connect right away to surface configuration errors when the changefeed
+	// is created.
+	if _, err := sinkClient.getConn(ctx); err != nil {
+		return nil, err
+	}
+
+	return makeBatchingSink(
+		ctx,
+		sinkTypeNATS,
+		sinkClient,
+		time.Duration(batchCfg.Frequency),
+		retryOpts,
+		parallelism,
+		topicNamer,
+		pacerFactory,
+		source,
+		mb(requiresResourceAccounting),
+	), nil
+}
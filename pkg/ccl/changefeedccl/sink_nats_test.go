@@ -0,0 +1,359 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNATSMessage is a message received by a fakeNATSServer.
+type fakeNATSMessage struct {
+	subject string
+	key     string
+	data    string
+}
+
+// fakeNATSServer is a NATS server that speaks just enough of the protocol
+// for the NATS sink. If jetStreamSubjectPrefix is set, messages published
+// with a reply subject on subjects with this prefix are acknowledged like
+// JetStream does, while other messages with a reply subject receive a
+// no-responders status.
+type fakeNATSServer struct {
+	ln                     net.Listener
+	jetStreamSubjectPrefix string
+	password               string
+	wg                     sync.WaitGroup
+
+	mu struct {
+		syncutil.Mutex
+		conns    []net.Conn
+		connects []natsConnectOptions
+		messages []fakeNATSMessage
+	}
+}
+
+func startFakeNATSServer(
+	t *testing.T, jetStreamSubjectPrefix string, password string,
+) *fakeNATSServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeNATSServer{ln: ln, jetStreamSubjectPrefix: jetStreamSubjectPrefix, password: password}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.mu.conns = append(s.mu.conns, conn)
+			s.mu.Unlock()
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				defer conn.Close()
+				_ = s.serve(conn)
+			}()
+		}
+	}()
+	return s
+}
+
+func (s *fakeNATSServer) serve(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	info, err := json.Marshal(natsServerInfo{
+		ServerID: "fake", Version: "2.9.0", Headers: true, MaxPayload: 1 << 20,
+		AuthRequired: s.password != "",
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "INFO %s\r\n", info)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	var sid string
+	var seq int
+	for {
+		op, args, err := readNATSOp(r)
+		if err != nil {
+			return err
+		}
+		switch op {
+		case "CONNECT":
+			var opts natsConnectOptions
+			if err := json.Unmarshal([]byte(args), &opts); err != nil {
+				return err
+			}
+			s.mu.Lock()
+			s.mu.connects = append(s.mu.connects, opts)
+			s.mu.Unlock()
+			if opts.Pass != s.password {
+				fmt.Fprintf(w, "-ERR 'Authorization Violation'\r\n")
+				return w.Flush()
+			}
+		case "PING":
+			fmt.Fprintf(w, "PONG\r\n")
+		case "SUB":
+			fields := strings.Fields(args)
+			sid = fields[len(fields)-1]
+		case "PUB", "HPUB":
+			fields := strings.Fields(args)
+			total, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil {
+				return err
+			}
+			var headerLen int
+			numSizes := 1
+			if op == "HPUB" {
+				numSizes = 2
+				if headerLen, err = strconv.Atoi(fields[len(fields)-2]); err != nil {
+					return err
+				}
+			}
+			var reply string
+			if len(fields) == 2+numSizes {
+				reply = fields[1]
+			}
+			buf := make([]byte, total+2)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return err
+			}
+			m := fakeNATSMessage{subject: fields[0], data: string(buf[headerLen:total])}
+			for _, line := range strings.Split(string(buf[:headerLen]), "\r\n") {
+				if k, v, ok := strings.Cut(line, ": "); ok && k == natsKeyHeader {
+					m.key = v
+				}
+			}
+			s.mu.Lock()
+			s.mu.messages = append(s.mu.messages, m)
+			s.mu.Unlock()
+
+			if reply == "" {
+				break
+			}
+			if s.jetStreamSubjectPrefix != "" && strings.HasPrefix(m.subject, s.jetStreamSubjectPrefix) {
+				seq++
+				ack := fmt.Sprintf(`{"stream":"test","seq":%d}`, seq)
+				fmt.Fprintf(w, "MSG %s %s %d\r\n%s\r\n", reply, sid, len(ack), ack)
+			} else {
+				header := "NATS/1.0 503\r\n\r\n"
+				fmt.Fprintf(w, "HMSG %s %s %d %d\r\n%s\r\n", reply, sid, len(header), len(header), header)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *fakeNATSServer) url() string {
+	return "nats://" + s.ln.Addr().String()
+}
+
+func (s *fakeNATSServer) messages() []fakeNATSMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]fakeNATSMessage(nil), s.mu.messages...)
+}
+
+func (s *fakeNATSServer) connects() []natsConnectOptions {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]natsConnectOptions(nil), s.mu.connects...)
+}
+
+func (s *fakeNATSServer) close() {
+	_ = s.ln.Close()
+	s.mu.Lock()
+	for _, conn := range s.mu.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// natsTestTopic returns a topic for a table with the given ID, so that topics
+// of different tables are named separately.
+func natsTestTopic(id descpb.ID, name string) *tableDescriptorTopic {
+	t := topic(name)
+	t.TableID = id
+	t.spec.TableID = id
+	return t
+}
+
+func makeTestNATSSink(t *testing.T, sinkURI string, opts map[string]string) (Sink, error) {
+	u, err := url.Parse(sinkURI)
+	require.NoError(t, err)
+	encodingOpts, err := changefeedbase.MakeStatementOptions(opts).GetEncodingOptions()
+	require.NoError(t, err)
+	return makeNATSSink(context.Background(), sinkURL{URL: u}, encodingOpts,
+		`{"Retry":{"Max":1,"Backoff":"5ms"}}`, makeChangefeedTargets("t", "u"),
+		1 /* parallelism */, nilPacerFactory, timeutil.DefaultTimeSource{}, nilMetricsRecorderBuilder)
+}
+
+func TestNATSSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	server := startFakeNATSServer(t, "" /* jetStreamSubjectPrefix */, "" /* password */)
+	defer server.close()
+
+	sink, err := makeTestNATSSink(t, server.url()+`?topic_prefix=cdc.&subject_per_key=true`,
+		map[string]string{changefeedbase.OptFormat: string(changefeedbase.OptFormatJSON)})
+	require.NoError(t, err)
+
+	var pool testAllocPool
+	require.NoError(t, sink.EmitRow(ctx, natsTestTopic(1, `t`), []byte(`[1, "a b.c"]`), []byte(`{"after":{"a":1}}`), zeroTS, zeroTS, pool.alloc()))
+	require.NoError(t, sink.EmitRow(ctx, natsTestTopic(2, `u`), []byte(`[2, ""]`), []byte(`{"after":null}`), zeroTS, zeroTS, pool.alloc()))
+	require.NoError(t, sink.Flush(ctx))
+	require.ElementsMatch(t, []fakeNATSMessage{
+		{subject: `cdc.t.1.a_u0020_b_u002e_c`, key: `[1, "a b.c"]`, data: `{"after":{"a":1}}`},
+		{subject: `cdc.u.2.""`, key: `[2, ""]`, data: `{"after":null}`},
+	}, server.messages())
+	testutils.SucceedsSoon(t, func() error {
+		if remaining := pool.used(); remaining != 0 {
+			return errors.Newf("waiting for 0 allocs (%d)", remaining)
+		}
+		return nil
+	})
+
+	// Resolved timestamps are published to the subject of every topic.
+	opts, err := changefeedbase.MakeStatementOptions(nil).GetEncodingOptions()
+	require.NoError(t, err)
+	enc, err := makeJSONEncoder(jsonEncoderOptions{EncodingOptions: opts})
+	require.NoError(t, err)
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, enc, hlc.Timestamp{WallTime: 2}))
+	var resolvedSubjects []string
+	for _, m := range server.messages()[2:] {
+		require.Equal(t, `{"resolved":"2.0000000000"}`, m.data)
+		resolvedSubjects = append(resolvedSubjects, m.subject)
+	}
+	require.ElementsMatch(t, []string{`cdc.t`, `cdc.u`}, resolvedSubjects)
+
+	require.NoError(t, sink.Close())
+}
+
+func TestNATSSinkJetStream(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	server := startFakeNATSServer(t, "t" /* jetStreamSubjectPrefix */, "" /* password */)
+	defer server.close()
+
+	sink, err := makeTestNATSSink(t, server.url()+`?jetstream=true`,
+		map[string]string{changefeedbase.OptFormat: string(changefeedbase.OptFormatJSON)})
+	require.NoError(t, err)
+
+	var pool testAllocPool
+	for i := 0; i < 10; i++ {
+		require.NoError(t, sink.EmitRow(ctx, natsTestTopic(1, `t`), []byte(fmt.Sprintf(`[%d]`, i)), []byte(`{}`), zeroTS, zeroTS, pool.alloc()))
+	}
+	require.NoError(t, sink.Flush(ctx))
+	require.Len(t, server.messages(), 10)
+
+	// No stream captures the subject of the u topic, so its messages are not
+	// acknowledged.
+	require.NoError(t, sink.EmitRow(ctx, natsTestTopic(2, `u`), []byte(`[1]`), []byte(`{}`), zeroTS, zeroTS, pool.alloc()))
+	require.Regexp(t, `no JetStream stream captures subject u`, sink.Flush(ctx))
+
+	require.NoError(t, sink.Close())
+}
+
+func TestNATSSinkConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	server := startFakeNATSServer(t, "" /* jetStreamSubjectPrefix */, "secret" /* password */)
+	defer server.close()
+
+	jsonOpts := map[string]string{changefeedbase.OptFormat: string(changefeedbase.OptFormatJSON)}
+	csvOpts := map[string]string{
+		changefeedbase.OptFormat:   string(changefeedbase.OptFormatCSV),
+		changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeBare),
+	}
+	for _, tc := range []struct {
+		uri    string
+		opts   map[string]string
+		expErr string
+	}{
+		{uri: `nats://user:wrong@` + server.ln.Addr().String(), opts: jsonOpts, expErr: `Authorization Violation`},
+		{uri: server.url() + `?foo=bar`, opts: jsonOpts, expErr: `unknown NATS sink query parameters: foo`},
+		{uri: server.url() + `?subject_per_key=true`, opts: csvOpts, expErr: `subject_per_key requires format=json`},
+		{uri: server.url() + `?ca_cert=Zm9v`, opts: jsonOpts, expErr: `ca_cert requires tls_enabled=true`},
+		{uri: `nats://`, opts: jsonOpts, expErr: `missing NATS server address`},
+		{uri: server.url(), opts: map[string]string{changefeedbase.OptFormat: string(changefeedbase.OptFormatAvro)},
+			expErr: `this sink is incompatible with format=avro`},
+	} {
+		t.Run(tc.uri, func(t *testing.T) {
+			_, err := makeTestNATSSink(t, tc.uri, tc.opts)
+			require.Regexp(t, tc.expErr, err)
+		})
+	}
+
+	sink, err := makeTestNATSSink(t, `nats://user:secret@`+server.ln.Addr().String(), jsonOpts)
+	require.NoError(t, err)
+	require.NoError(t, sink.Close())
+	connects := server.connects()
+	require.Equal(t, "user", connects[len(connects)-1].User)
+	require.Equal(t, natsClientName, connects[len(connects)-1].Name)
+}
+
+func TestNATSSubjectTokensFromKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		key    string
+		tokens string
+		expErr string
+	}{
+		{key: `[1]`, tokens: `1`},
+		{key: `[1.5, "a", true, null]`, tokens: `1_u002e_5.a.true.null`},
+		{key: `["a.b", "c*", "d>", "e f"]`, tokens: `a_u002e_b.c_u002a_.d_u003e_.e_u0020_f`},
+		{key: `["_u0020_"]`, tokens: `_u005f__u0075__u0030__u0030__u0032__u0030__u005f_`},
+		{key: `[{"a": [1]}]`, tokens: `{"a":_u0020_[1]}`},
+		{key: `[]`, expErr: `key \[\] has no columns`},
+		{key: `1`, expErr: `key 1 is not a JSON array`},
+	} {
+		t.Run(tc.key, func(t *testing.T) {
+			tokens, err := natsSubjectTokensFromKey([]byte(tc.key))
+			if tc.expErr != "" {
+				require.Regexp(t, tc.expErr, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.tokens, tokens)
+		})
+	}
+}
@@ -0,0 +1,620 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	gosql "database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// postgresSinkMaxPlaceholders bounds the number of placeholders of the
+	// statements that apply changes, below the limit of the pgwire protocol.
+	postgresSinkMaxPlaceholders = 10000
+
+	postgresSinkColumnsQuery = `SELECT column_name, data_type FROM information_schema.columns
+WHERE table_schema = current_schema() AND table_name = $1`
+	postgresSinkPrimaryKeyQuery = `SELECT kcu.column_name
+FROM information_schema.table_constraints AS tc
+JOIN information_schema.key_column_usage AS kcu
+  ON kcu.constraint_schema = tc.constraint_schema
+  AND kcu.constraint_name = tc.constraint_name
+  AND kcu.table_name = tc.table_name
+WHERE tc.constraint_type = 'PRIMARY KEY'
+  AND tc.table_schema = current_schema() AND tc.table_name = $1
+ORDER BY kcu.ordinal_position`
+	postgresSinkCreateResolvedTableStmt = `CREATE TABLE IF NOT EXISTS %s (
+		topic TEXT PRIMARY KEY,
+		resolved TEXT NOT NULL
+	)`
+	postgresSinkUpsertResolvedStmt = `INSERT INTO %s (topic, resolved) VALUES ($1, $2)
+ON CONFLICT (topic) DO UPDATE SET resolved = excluded.resolved`
+)
+
+func isPostgresSink(u *url.URL) bool {
+	switch u.Scheme {
+	case changefeedbase.SinkSchemePostgres, changefeedbase.SinkSchemePostgresQL:
+		return true
+	default:
+		return false
+	}
+}
+
+// postgresSink applies changefeed rows to mirror tables in a PostgreSQL
+// compatible database, such as another CockroachDB cluster, which makes it a
+// one-way replication stream.
+//
+// The changes of each watched table are applied to the table of the same
+// name, in the schema that the connection resolves unqualified names to (the
+// search_path connection parameter can set it). Columns are mapped by name,
+// so mirror tables may have their columns in a different order, and may have
+// extra columns as long as these have defaults. The mirror table must have
+// the same primary key columns, in the same order, as the watched table.
+// Upserts are applied with INSERT ... ON CONFLICT DO UPDATE, and deletes with
+// DELETE, which makes replays of changes idempotent.
+//
+// Rows are buffered until the changefeed flushes the sink, and all of them
+// are applied in a single transaction. The changefeed flushes before each
+// resolved timestamp is emitted, so once a resolved timestamp has been
+// emitted, the mirror tables contain all the changes up to it. If the
+// resolved_table param is set, resolved timestamps are written to the table
+// of that name.
+//
+// Values are passed to the mirror database as text, in their JSON
+// representation, which types like geometries or tuples do not accept.
+type postgresSink struct {
+	uri           string
+	resolvedTable string
+	db            *gosql.DB
+	topicNamer    *TopicNamer
+	metrics       metricsRecorder
+
+	// tables caches the layout of the mirror tables, by name. It is reset
+	// when applying changes fails, so that changes made to the mirror tables
+	// to fix the failure are picked up.
+	tables map[string]*postgresMirrorTable
+
+	// buf holds the changes emitted since the last flush.
+	buf struct {
+		tableNames  []string
+		changes     map[string]map[string]*postgresRowChange
+		numMessages int
+		numBytes    int
+		mvcc        hlc.Timestamp
+		alloc       kvevent.Alloc
+	}
+}
+
+var _ Sink = (*postgresSink)(nil)
+
+// postgresMirrorTable is the layout of a mirror table.
+type postgresMirrorTable struct {
+	quotedName string
+	primaryKey []string
+	// columnTypes is the information_schema data type of the columns, by name.
+	columnTypes map[string]string
+}
+
+// postgresRowChange is the latest change of a row in the buffer of a
+// postgresSink.
+type postgresRowChange struct {
+	key     []json.RawMessage
+	deleted bool
+	mvcc    hlc.Timestamp
+	// columns are the values of the row, by column name. Changes to different
+	// column families of a row are merged.
+	columns map[string]json.RawMessage
+}
+
+func makePostgresSink(
+	u sinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	targets changefeedbase.Targets,
+	mb metricsRecorderBuilder,
+) (Sink, error) {
+	if encodingOpts.Format != changefeedbase.OptFormatJSON {
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptFormat, encodingOpts.Format)
+	}
+	if encodingOpts.Envelope != changefeedbase.OptEnvelopeWrapped {
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptEnvelope, encodingOpts.Envelope)
+	}
+
+	if u.Path == `` || u.Path == `/` {
+		return nil, errors.Errorf(`must specify database`)
+	}
+	u.Scheme = changefeedbase.SinkSchemePostgres
+
+	topicNamer, err := MakeTopicNamer(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	// All the params that are not consumed by the sink are connection params.
+	resolvedTable := u.consumeParam(changefeedbase.SinkParamResolvedTable)
+
+	s := &postgresSink{
+		uri:           u.String(),
+		resolvedTable: resolvedTable,
+		topicNamer:    topicNamer,
+		metrics:       mb(requiresResourceAccounting),
+		tables:        make(map[string]*postgresMirrorTable),
+	}
+	s.resetBuffer()
+	return s, nil
+}
+
+func (s *postgresSink) getConcreteType() sinkType {
+	return sinkTypePostgres
+}
+
+// Dial implements the Sink interface.
+func (s *postgresSink) Dial() error {
+	db, err := gosql.Open(`postgres`, s.uri)
+	if err != nil {
+		return err
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return errors.Wrap(err, "connecting to postgres sink")
+	}
+	if s.resolvedTable != "" {
+		if _, err := db.Exec(fmt.Sprintf(
+			postgresSinkCreateResolvedTableStmt, lexbase.EscapeSQLIdent(s.resolvedTable),
+		)); err != nil {
+			_ = db.Close()
+			return errors.Wrapf(err, "creating resolved table %s", s.resolvedTable)
+		}
+	}
+	s.db = db
+	return nil
+}
+
+func (s *postgresSink) resetBuffer() {
+	s.buf.tableNames = s.buf.tableNames[:0]
+	s.buf.changes = make(map[string]map[string]*postgresRowChange)
+	s.buf.numMessages = 0
+	s.buf.numBytes = 0
+	s.buf.mvcc = hlc.Timestamp{}
+	s.buf.alloc = kvevent.Alloc{}
+}
+
+// postgresMirrorTableName returns the name of the mirror table of a topic,
+// which is the unqualified name of the watched table.
+func postgresMirrorTableName(topic TopicDescriptor) (string, error) {
+	name, _ := topic.GetNameComponents()
+	tn, err := parser.ParseTableName(string(name))
+	if err != nil {
+		return "", err
+	}
+	return tn.Parts[0], nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *postgresSink) EmitRow(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	s.metrics.recordMessageSize(int64(len(key) + len(value)))
+	s.buf.alloc.Merge(&alloc)
+
+	tableName, err := postgresMirrorTableName(topic)
+	if err != nil {
+		return err
+	}
+	change := &postgresRowChange{mvcc: mvcc}
+	if err := json.Unmarshal(key, &change.key); err != nil {
+		return errors.Wrapf(err, "decoding key %s", key)
+	}
+	var envelope struct {
+		After json.RawMessage `json:"after"`
+	}
+	if err := json.Unmarshal(value, &envelope); err != nil {
+		return errors.Wrapf(err, "decoding value %s", value)
+	}
+	switch {
+	case envelope.After == nil:
+		return errors.Newf("value %s has no after field", value)
+	case string(envelope.After) == `null`:
+		change.deleted = true
+	default:
+		if err := json.Unmarshal(envelope.After, &change.columns); err != nil {
+			return errors.Wrapf(err, "decoding value %s", value)
+		}
+	}
+
+	changes, ok := s.buf.changes[tableName]
+	if !ok {
+		changes = make(map[string]*postgresRowChange)
+		s.buf.changes[tableName] = changes
+		s.buf.tableNames = append(s.buf.tableNames, tableName)
+	}
+	changes[string(key)] = change.mergeInto(changes[string(key)])
+
+	s.buf.numMessages++
+	s.buf.numBytes += len(key) + len(value)
+	if s.buf.mvcc.IsEmpty() || mvcc.Less(s.buf.mvcc) {
+		s.buf.mvcc = mvcc
+	}
+	return nil
+}
+
+// mergeInto returns the change that results from applying c after prev,
+// which may be nil. Changes older than prev, which are replays, are ignored.
+func (c *postgresRowChange) mergeInto(prev *postgresRowChange) *postgresRowChange {
+	if prev == nil {
+		return c
+	}
+	if c.mvcc.Less(prev.mvcc) {
+		return prev
+	}
+	if c.deleted || prev.deleted {
+		return c
+	}
+	// Both changes are upserts, possibly of different column families. The
+	// columns of prev that c doesn't have keep their values.
+	for col, v := range c.columns {
+		prev.columns[col] = v
+	}
+	prev.mvcc = c.mvcc
+	return prev
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *postgresSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	defer s.metrics.recordResolvedCallback()()
+
+	if s.resolvedTable == "" {
+		return nil
+	}
+	stmt := fmt.Sprintf(postgresSinkUpsertResolvedStmt, lexbase.EscapeSQLIdent(s.resolvedTable))
+	return s.topicNamer.Each(func(topic string) error {
+		_, err := s.db.ExecContext(ctx, stmt, topic, resolved.AsOfSystemTime())
+		return err
+	})
+}
+
+// Flush implements the Sink interface. It applies all the buffered changes in
+// a single transaction.
+func (s *postgresSink) Flush(ctx context.Context) error {
+	defer s.metrics.recordFlushRequestCallback()()
+
+	if s.buf.numMessages == 0 {
+		return nil
+	}
+
+	start := timeutil.Now()
+	tables := make([]*postgresMirrorTable, len(s.buf.tableNames))
+	for i, name := range s.buf.tableNames {
+		var err error
+		if tables[i], err = s.getMirrorTable(ctx, name); err != nil {
+			return err
+		}
+	}
+	if err := crdb.ExecuteTx(ctx, s.db, nil /* txopts */, func(tx *gosql.Tx) error {
+		for i, name := range s.buf.tableNames {
+			if err := tables[i].apply(ctx, tx, s.buf.changes[name]); err != nil {
+				return errors.Wrapf(err, "applying changes to mirror table %s", name)
+			}
+		}
+		return nil
+	}); err != nil {
+		s.tables = make(map[string]*postgresMirrorTable)
+		return err
+	}
+
+	s.metrics.recordEmittedBatch(
+		start, s.buf.numMessages, s.buf.mvcc, s.buf.numBytes, sinkDoesNotCompress)
+	s.buf.alloc.Release(ctx)
+	s.resetBuffer()
+	return nil
+}
+
+// getMirrorTable returns the layout of the mirror table with the given name.
+func (s *postgresSink) getMirrorTable(
+	ctx context.Context, name string,
+) (*postgresMirrorTable, error) {
+	if t, ok := s.tables[name]; ok {
+		return t, nil
+	}
+	t := &postgresMirrorTable{
+		quotedName:  lexbase.EscapeSQLIdent(name),
+		columnTypes: make(map[string]string),
+	}
+
+	rows, err := s.db.QueryContext(ctx, postgresSinkColumnsQuery, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var col, typ string
+		if err := rows.Scan(&col, &typ); err != nil {
+			return nil, err
+		}
+		t.columnTypes[col] = typ
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(t.columnTypes) == 0 {
+		return nil, errors.Newf("mirror table %s does not exist", name)
+	}
+
+	rows, err = s.db.QueryContext(ctx, postgresSinkPrimaryKeyQuery, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var col string
+		if err := rows.Scan(&col); err != nil {
+			return nil, err
+		}
+		t.primaryKey = append(t.primaryKey, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(t.primaryKey) == 0 {
+		return nil, errors.Newf("mirror table %s has no primary key", name)
+	}
+
+	s.tables[name] = t
+	return t, nil
+}
+
+// apply applies changes to the table. Each row has at most one change, so
+// the order in which they are applied doesn't matter, and the changes are
+// grouped into as few statements as possible.
+func (t *postgresMirrorTable) apply(
+	ctx context.Context, tx *gosql.Tx, changes map[string]*postgresRowChange,
+) error {
+	var deletes [][]interface{}
+	// upserts are the rows to upsert, grouped by their set of columns.
+	upserts := make(map[string][][]interface{})
+	upsertColumns := make(map[string][]string)
+	for _, c := range changes {
+		if len(c.key) != len(t.primaryKey) {
+			return errors.Newf("key %s does not match the primary key (%s) of the mirror table",
+				c.key, strings.Join(t.primaryKey, ", "))
+		}
+		row := make([]interface{}, 0, len(t.primaryKey)+len(c.columns))
+		for i, col := range t.primaryKey {
+			d, err := t.datum(col, c.key[i])
+			if err != nil {
+				return err
+			}
+			row = append(row, d)
+		}
+		if c.deleted {
+			deletes = append(deletes, row)
+			continue
+		}
+
+		cols := make([]string, 0, len(c.columns))
+		for col := range c.columns {
+			if !t.isPrimaryKeyColumn(col) {
+				cols = append(cols, col)
+			}
+		}
+		sort.Strings(cols)
+		for _, col := range cols {
+			d, err := t.datum(col, c.columns[col])
+			if err != nil {
+				return err
+			}
+			row = append(row, d)
+		}
+		group := strings.Join(cols, ",")
+		upserts[group] = append(upserts[group], row)
+		upsertColumns[group] = append(t.primaryKey[:len(t.primaryKey):len(t.primaryKey)], cols...)
+	}
+
+	for group, rows := range upserts {
+		cols := upsertColumns[group]
+		if err := execInBatches(ctx, tx, rows, len(cols), func(numRows int) string {
+			return t.upsertStmt(cols, numRows)
+		}); err != nil {
+			return err
+		}
+	}
+	return execInBatches(ctx, tx, deletes, len(t.primaryKey), t.deleteStmt)
+}
+
+func (t *postgresMirrorTable) isPrimaryKeyColumn(col string) bool {
+	for _, pkCol := range t.primaryKey {
+		if col == pkCol {
+			return true
+		}
+	}
+	return false
+}
+
+// execInBatches executes the statements returned by makeStmt for batches of
+// rows, each of which has numCols values.
+func execInBatches(
+	ctx context.Context,
+	tx *gosql.Tx,
+	rows [][]interface{},
+	numCols int,
+	makeStmt func(numRows int) string,
+) error {
+	batchSize := postgresSinkMaxPlaceholders / numCols
+	for len(rows) > 0 {
+		n := len(rows)
+		if n > batchSize {
+			n = batchSize
+		}
+		args := make([]interface{}, 0, n*numCols)
+		for _, row := range rows[:n] {
+			args = append(args, row...)
+		}
+		if _, err := tx.ExecContext(ctx, makeStmt(n), args...); err != nil {
+			return err
+		}
+		rows = rows[n:]
+	}
+	return nil
+}
+
+func writePostgresPlaceholders(b *strings.Builder, numRows, numCols int) {
+	for r := 0; r < numRows; r++ {
+		if r > 0 {
+			b.WriteString(`, `)
+		}
+		b.WriteByte('(')
+		for c := 0; c < numCols; c++ {
+			if c > 0 {
+				b.WriteString(`, `)
+			}
+			fmt.Fprintf(b, `$%d`, r*numCols+c+1)
+		}
+		b.WriteByte(')')
+	}
+}
+
+func writePostgresColumnList(b *strings.Builder, cols []string) {
+	b.WriteByte('(')
+	for i, col := range cols {
+		if i > 0 {
+			b.WriteString(`, `)
+		}
+		b.WriteString(lexbase.EscapeSQLIdent(col))
+	}
+	b.WriteByte(')')
+}
+
+// upsertStmt returns an INSERT ... ON CONFLICT statement, rather than an
+// UPSERT statement, which is also supported by PostgreSQL.
+func (t *postgresMirrorTable) upsertStmt(cols []string, numRows int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `INSERT INTO %s `, t.quotedName)
+	writePostgresColumnList(&b, cols)
+	b.WriteString(` VALUES `)
+	writePostgresPlaceholders(&b, numRows, len(cols))
+	b.WriteString(` ON CONFLICT `)
+	writePostgresColumnList(&b, t.primaryKey)
+	if len(cols) == len(t.primaryKey) {
+		b.WriteString(` DO NOTHING`)
+		return b.String()
+	}
+	b.WriteString(` DO UPDATE SET `)
+	for i, col := range cols[len(t.primaryKey):] {
+		if i > 0 {
+			b.WriteString(`, `)
+		}
+		quoted := lexbase.EscapeSQLIdent(col)
+		fmt.Fprintf(&b, `%s = excluded.%s`, quoted, quoted)
+	}
+	return b.String()
+}
+
+func (t *postgresMirrorTable) deleteStmt(numRows int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `DELETE FROM %s WHERE `, t.quotedName)
+	writePostgresColumnList(&b, t.primaryKey)
+	b.WriteString(` IN (`)
+	writePostgresPlaceholders(&b, numRows, len(t.primaryKey))
+	b.WriteByte(')')
+	return b.String()
+}
+
+// datum returns the value to pass for the JSON value of a column: NULL, or
+// its text representation.
+func (t *postgresMirrorTable) datum(col string, v json.RawMessage) (interface{}, error) {
+	typ, ok := t.columnTypes[col]
+	if !ok {
+		return nil, errors.Newf("mirror table has no column %s", col)
+	}
+	switch {
+	case string(v) == `null`:
+		return nil, nil
+	case typ == `json` || typ == `jsonb`:
+		return string(v), nil
+	case v[0] == '"':
+		var s string
+		if err := json.Unmarshal(v, &s); err != nil {
+			return nil, err
+		}
+		return s, nil
+	case v[0] == '[' && typ == `ARRAY`:
+		var b strings.Builder
+		if err := writePostgresArray(&b, v); err != nil {
+			return nil, err
+		}
+		return b.String(), nil
+	default:
+		return string(v), nil
+	}
+}
+
+// writePostgresArray writes the text representation of a JSON array as a
+// PostgreSQL array.
+func writePostgresArray(b *strings.Builder, v json.RawMessage) error {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(v, &elems); err != nil {
+		return err
+	}
+	b.WriteByte('{')
+	for i, e := range elems {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		s := string(e)
+		switch {
+		case s == `null`:
+			b.WriteString(`NULL`)
+			continue
+		case e[0] == '[':
+			if err := writePostgresArray(b, e); err != nil {
+				return err
+			}
+			continue
+		case e[0] == '"':
+			if err := json.Unmarshal(e, &s); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('"')
+		b.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return nil
+}
+
+// Close implements the Sink interface.
+func (s *postgresSink) Close() error {
+	s.buf.alloc.Release(context.Background())
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
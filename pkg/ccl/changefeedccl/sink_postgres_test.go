@@ -0,0 +1,165 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	makeTopic := func(id descpb.ID, name string) *tableDescriptorTopic {
+		td := tabledesc.NewBuilder(&descpb.TableDescriptor{Name: name, ID: id}).BuildImmutableTable()
+		spec := changefeedbase.Target{
+			Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+			TableID:           td.GetID(),
+			StatementTimeName: changefeedbase.StatementTimeName(name),
+		}
+		return &tableDescriptorTopic{Metadata: makeMetadata(td), spec: spec}
+	}
+	ts := func(wallTime int64) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: wallTime}
+	}
+
+	ctx := context.Background()
+	s, sqlDBRaw, _ := serverutils.StartServer(t, base.TestServerArgs{UseDatabase: "d"})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(sqlDBRaw)
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE foo (b STRING, a INT PRIMARY KEY, c INT[], j JSONB)`)
+	sqlDB.Exec(t, `CREATE TABLE bar (k1 STRING, k2 INT, v STRING, PRIMARY KEY (k1, k2))`)
+
+	pgURL, cleanup := sqlutils.PGUrl(t, s.ApplicationLayer().AdvSQLAddr(), t.Name(), url.User(username.RootUser))
+	defer cleanup()
+	pgURL.Path = `d`
+	q := pgURL.Query()
+	q.Set(changefeedbase.SinkParamResolvedTable, `resolved`)
+	pgURL.RawQuery = q.Encode()
+
+	fooTopic := makeTopic(52, `foo`)
+	barTopic := makeTopic(53, `bar`)
+	targets := changefeedbase.Targets{}
+	targets.Add(fooTopic.GetTargetSpecification())
+	targets.Add(barTopic.GetTargetSpecification())
+
+	encodingOpts := changefeedbase.EncodingOptions{
+		Format:   changefeedbase.OptFormatJSON,
+		Envelope: changefeedbase.OptEnvelopeWrapped,
+	}
+	sink, err := makePostgresSink(sinkURL{URL: &pgURL}, encodingOpts, targets, nilMetricsRecorderBuilder)
+	require.NoError(t, err)
+	require.NoError(t, sink.Dial())
+	defer func() { require.NoError(t, sink.Close()) }()
+
+	emit := func(topic *tableDescriptorTopic, key, value string, mvcc hlc.Timestamp) {
+		t.Helper()
+		require.NoError(t, sink.EmitRow(ctx, topic, []byte(key), []byte(value), mvcc, mvcc, zeroAlloc))
+	}
+
+	// Empty
+	require.NoError(t, sink.Flush(ctx))
+
+	// Nothing is applied until Flush is called.
+	emit(fooTopic, `[1]`, `{"after": {"a": 1, "b": "x", "c": [1, null], "j": {"k": "v"}}}`, ts(1))
+	emit(barTopic, `["k", 1]`, `{"after": {"k1": "k", "k2": 1, "v": "v1"}}`, ts(1))
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM foo`, [][]string{{`0`}})
+	require.NoError(t, sink.Flush(ctx))
+	sqlDB.CheckQueryResults(t, `SELECT a, b, c, j FROM foo`,
+		[][]string{{`1`, `x`, `{1,NULL}`, `{"k": "v"}`}},
+	)
+	sqlDB.CheckQueryResults(t, `SELECT k1, k2, v FROM bar`, [][]string{{`k`, `1`, `v1`}})
+
+	// Updates and deletes, with the latest change of each row winning, and
+	// replays of older changes ignored.
+	emit(fooTopic, `[1]`, `{"after": {"a": 1, "b": "y", "c": null, "j": null}}`, ts(3))
+	emit(fooTopic, `[1]`, `{"after": {"a": 1, "b": "old", "c": null, "j": null}}`, ts(2))
+	emit(fooTopic, `[2]`, `{"after": {"a": 2, "b": "z", "c": null, "j": null}}`, ts(3))
+	emit(fooTopic, `[2]`, `{"after": null}`, ts(4))
+	emit(barTopic, `["k", 1]`, `{"after": null}`, ts(3))
+	emit(barTopic, `["k", 2]`, `{"after": {"k1": "k", "k2": 2, "v": "v2"}}`, ts(3))
+	require.NoError(t, sink.Flush(ctx))
+	sqlDB.CheckQueryResults(t, `SELECT a, b, c, j FROM foo`,
+		[][]string{{`1`, `y`, `NULL`, `NULL`}},
+	)
+	sqlDB.CheckQueryResults(t, `SELECT k1, k2, v FROM bar`, [][]string{{`k`, `2`, `v2`}})
+
+	// Replaying changes that were already applied is idempotent.
+	emit(barTopic, `["k", 2]`, `{"after": {"k1": "k", "k2": 2, "v": "v2"}}`, ts(3))
+	emit(fooTopic, `[2]`, `{"after": null}`, ts(4))
+	require.NoError(t, sink.Flush(ctx))
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM foo`, [][]string{{`1`}})
+	sqlDB.CheckQueryResults(t, `SELECT k1, k2, v FROM bar`, [][]string{{`k`, `2`, `v2`}})
+
+	// Changes to different column families of a row are merged.
+	emit(fooTopic, `[3]`, `{"after": {"a": 3, "b": "w"}}`, ts(5))
+	emit(fooTopic, `[3]`, `{"after": {"a": 3, "c": [4]}}`, ts(5))
+	require.NoError(t, sink.Flush(ctx))
+	sqlDB.CheckQueryResults(t, `SELECT a, b, c FROM foo WHERE a = 3`,
+		[][]string{{`3`, `w`, `{4}`}},
+	)
+
+	// Resolved timestamps are written for every topic.
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, nil /* encoder */, ts(6)))
+	sqlDB.CheckQueryResults(t, `SELECT topic, resolved FROM resolved ORDER BY topic`,
+		[][]string{{`bar`, `6.0000000000`}, {`foo`, `6.0000000000`}},
+	)
+
+	// Changes to a table without a mirror table fail the flush.
+	bazTopic := makeTopic(54, `baz`)
+	emit(bazTopic, `[1]`, `{"after": {"a": 1}}`, ts(7))
+	require.Regexp(t, `mirror table baz does not exist`, sink.Flush(ctx))
+}
+
+func TestPostgresSinkConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	u, err := url.Parse(`postgresql://root@localhost:26257/d?sslmode=disable&resolved_table=r`)
+	require.NoError(t, err)
+	wrapped := changefeedbase.EncodingOptions{
+		Format:   changefeedbase.OptFormatJSON,
+		Envelope: changefeedbase.OptEnvelopeWrapped,
+	}
+
+	sink, err := makePostgresSink(sinkURL{URL: u}, wrapped, changefeedbase.Targets{}, nilMetricsRecorderBuilder)
+	require.NoError(t, err)
+	ps := sink.(*postgresSink)
+	require.Equal(t, `r`, ps.resolvedTable)
+	require.Equal(t, `postgres://root@localhost:26257/d?sslmode=disable`, ps.uri)
+
+	avro := wrapped
+	avro.Format = changefeedbase.OptFormatAvro
+	_, err = makePostgresSink(sinkURL{URL: u}, avro, changefeedbase.Targets{}, nilMetricsRecorderBuilder)
+	require.Regexp(t, `incompatible with format=avro`, err)
+
+	bare := wrapped
+	bare.Envelope = changefeedbase.OptEnvelopeBare
+	_, err = makePostgresSink(sinkURL{URL: u}, bare, changefeedbase.Targets{}, nilMetricsRecorderBuilder)
+	require.Regexp(t, `incompatible with envelope=bare`, err)
+
+	u.Path = ``
+	_, err = makePostgresSink(sinkURL{URL: u}, wrapped, changefeedbase.Targets{}, nilMetricsRecorderBuilder)
+	require.Regexp(t, `must specify database`, err)
+}
@@ -11,6 +11,7 @@
 package clisqlshell
 
 import (
+	"io"
 	"os"
 
 	"github.com/cockroachdb/cockroach/pkg/server/pgurl"
@@ -25,3 +26,39 @@ type Shell interface {
 // URLParser represents a function able to convert user-supplied
 // strings to a URL object.
 type URLParser = func(url string) (*pgurl.URL, error)
+
+// ParquetCodec encodes and decodes Parquet files on behalf of the
+// \export and \import client-side commands.
+//
+// Values are exchanged in their PostgreSQL text representation. A nil
+// value denotes NULL.
+type ParquetCodec interface {
+	// NewWriter returns a RowWriter that encodes rows into w. colTypes
+	// contains the type names reported by the server for each column.
+	NewWriter(w io.Writer, colNames, colTypes []string) (RowWriter, error)
+
+	// NewReader returns a RowReader for the rows in f. colTypes maps the
+	// names of the target columns to their type names, and is used
+	// to decode files that do not carry CockroachDB type metadata.
+	NewReader(f *os.File, colTypes map[string]string) (RowReader, error)
+}
+
+// RowWriter writes rows to a data file.
+type RowWriter interface {
+	// WriteRow writes one row.
+	WriteRow(row []*string) error
+	// Close flushes the buffered rows. It does not close the underlying
+	// io.Writer.
+	Close() error
+}
+
+// RowReader reads rows from a data file.
+type RowReader interface {
+	// Columns returns the names of the columns in the file.
+	Columns() []string
+	// ReadRow returns the next row, or io.EOF after the last row.
+	ReadRow() ([]*string, error)
+	// Close releases the resources held by the reader. It does not close
+	// the underlying file.
+	Close() error
+}
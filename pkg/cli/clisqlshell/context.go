@@ -57,6 +57,14 @@ type Context struct {
 	// CertsDir is an extra directory to look for client certs in,
 	// when the \c command is used.
 	CertsDir string
+
+	// ParquetCodec implements the Parquet format for the \export and
+	// \import commands.
+	//
+	// When left undefined, only the CSV and NDJSON formats are
+	// available. CockroachDB's own CLI package provides an
+	// implementation based on the Parquet writer used by EXPORT.
+	ParquetCodec ParquetCodec
 }
 
 // internalContext represents the internal configuration state of the
@@ -115,6 +123,14 @@ type internalContext struct {
 
 	statementWrappers []statementWrapper
 
+	// vars contains the client-side variables defined via \set or
+	// \gset, and substituted in SQL text via :name.
+	vars map[string]string
+
+	// lastQuery is the last SQL query sent to the server, re-executed
+	// by \watch when the query buffer is empty.
+	lastQuery string
+
 	// state about the current query.
 	mu struct {
 		syncutil.Mutex
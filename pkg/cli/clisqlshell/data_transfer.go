@@ -0,0 +1,744 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package clisqlshell
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/scanner"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding/csv"
+	"github.com/cockroachdb/errors"
+)
+
+// dataFormat is a file format supported by \export and \import.
+type dataFormat string
+
+const (
+	dataFormatCSV     dataFormat = "csv"
+	dataFormatNDJSON  dataFormat = "ndjson"
+	dataFormatParquet dataFormat = "parquet"
+)
+
+// \import sends the rows to the server using one COPY statement per
+// batch. A batch is sent when it reaches either of these limits.
+const (
+	importBatchRows  = 10000
+	importBatchBytes = 4 << 20
+)
+
+const (
+	exportUsage = `\export QUERY TO 'FILE' [WITH FORMAT csv|ndjson|parquet]`
+	importUsage = `\import 'FILE' INTO TABLE [(COLUMN, ...)] [WITH FORMAT csv|ndjson|parquet]`
+)
+
+var errParquetUnsupported = errors.WithHint(
+	errors.New("the parquet format is not supported by this SQL client"),
+	"Use 'cockroach sql', or the csv or ndjson format.")
+
+// exportCmd is the parsed form of \export.
+type exportCmd struct {
+	query  string
+	file   string
+	format dataFormat
+}
+
+// importCmd is the parsed form of \import.
+type importCmd struct {
+	file  string
+	table string
+	// cols, if non-empty, restricts the target columns.
+	cols   []string
+	format dataFormat
+}
+
+// runExport implements \export.
+func (c *cliState) runExport(line string, nextState, errState cliStateEnum) cliStateEnum {
+	cmd, err := parseExportCmd(line)
+	if err != nil {
+		return c.cliError(errState, err)
+	}
+	if cmd.format == dataFormatParquet && c.sqlCtx.ParquetCodec == nil {
+		return c.cliError(errState, errParquetUnsupported)
+	}
+
+	f, err := os.Create(cmd.file)
+	if err != nil {
+		return c.cliError(errState, err)
+	}
+	var numRows int
+	err = c.runWithInterruptableCtx(func(ctx context.Context) (err error) {
+		numRows, err = c.exportRows(ctx, cmd, f)
+		return err
+	})
+	if err = errors.CombineErrors(err, f.Close()); err != nil {
+		return c.cliError(errState, errors.Wrapf(err, "exporting to %s", cmd.file))
+	}
+	fmt.Fprintf(c.iCtx.queryOutput, "EXPORT %d\n", numRows)
+	c.maybeFlushOutput()
+	return nextState
+}
+
+// exportRows runs the query and writes its results to w.
+func (c *cliState) exportRows(
+	ctx context.Context, cmd exportCmd, w io.Writer,
+) (numRows int, resErr error) {
+	rows, err := c.conn.Query(ctx, cmd.query)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { resErr = errors.CombineErrors(resErr, rows.Close()) }()
+
+	cols := rows.Columns()
+	if len(cols) == 0 {
+		return 0, errors.New("the query does not return rows")
+	}
+	colTypes := make([]string, len(cols))
+	for i := range cols {
+		colTypes[i] = rows.ColumnTypeDatabaseTypeName(i)
+	}
+	rw, err := c.newRowWriter(cmd.format, w, cols, colTypes)
+	if err != nil {
+		return 0, err
+	}
+
+	vals := make([]driver.Value, len(cols))
+	row := make([]*string, len(cols))
+	for {
+		if err := rows.Next(vals); err == io.EOF {
+			break
+		} else if err != nil {
+			return numRows, err
+		}
+		for i, v := range vals {
+			row[i] = textValue(v)
+		}
+		if err := rw.WriteRow(row); err != nil {
+			return numRows, err
+		}
+		numRows++
+	}
+	return numRows, rw.Close()
+}
+
+// runImport implements \import.
+func (c *cliState) runImport(line string, nextState, errState cliStateEnum) cliStateEnum {
+	cmd, err := parseImportCmd(line)
+	if err != nil {
+		return c.cliError(errState, err)
+	}
+	if cmd.format == dataFormatParquet && c.sqlCtx.ParquetCodec == nil {
+		return c.cliError(errState, errParquetUnsupported)
+	}
+
+	f, err := os.Open(cmd.file)
+	if err != nil {
+		return c.cliError(errState, err)
+	}
+	defer func() { _ = f.Close() }()
+	var numRows int
+	if err := c.runWithInterruptableCtx(func(ctx context.Context) (err error) {
+		numRows, err = c.importRows(ctx, cmd, f)
+		return err
+	}); err != nil {
+		if numRows > 0 {
+			err = errors.WithDetailf(err, "%d rows were imported before the error.", numRows)
+		}
+		return c.cliError(errState, errors.Wrapf(err, "importing from %s", cmd.file))
+	}
+	fmt.Fprintf(c.iCtx.queryOutput, "IMPORT %d\n", numRows)
+	c.maybeFlushOutput()
+	return nextState
+}
+
+// importRows reads the rows in f and sends them to the server with
+// COPY. It returns the number of rows imported successfully.
+func (c *cliState) importRows(ctx context.Context, cmd importCmd, f *os.File) (int, error) {
+	colTypes, err := c.getImportColumnTypes(ctx, cmd)
+	if err != nil {
+		return 0, err
+	}
+	rr, err := c.newRowReader(cmd.format, f, colTypes)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rr.Close() }()
+
+	fileCols := rr.Columns()
+	if len(fileCols) == 0 {
+		// Empty file.
+		return 0, nil
+	}
+	var copyStmt strings.Builder
+	fmt.Fprintf(&copyStmt, "COPY %s (", cmd.table)
+	for i, col := range fileCols {
+		if _, ok := colTypes[col]; !ok {
+			return 0, errors.Newf("column %q in the file is not a target column of %s", col, cmd.table)
+		}
+		if i > 0 {
+			copyStmt.WriteString(", ")
+		}
+		copyStmt.WriteString(lexbase.EscapeSQLIdent(col))
+	}
+	copyStmt.WriteString(") FROM STDIN")
+
+	numRows, batchRows := 0, 0
+	var buf bytes.Buffer
+	flush := func() error {
+		if batchRows == 0 {
+			return nil
+		}
+		if _, err := c.conn.GetDriverConn().CopyFrom(ctx, &buf, copyStmt.String()); err != nil {
+			return err
+		}
+		numRows += batchRows
+		batchRows = 0
+		buf.Reset()
+		return nil
+	}
+	for {
+		row, err := rr.ReadRow()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return numRows, err
+		}
+		if len(row) != len(fileCols) {
+			return numRows, errors.Newf("row %d: expected %d values, found %d",
+				numRows+batchRows+1, len(fileCols), len(row))
+		}
+		appendCopyTextRow(&buf, row)
+		batchRows++
+		if batchRows >= importBatchRows || buf.Len() >= importBatchBytes {
+			if err := flush(); err != nil {
+				return numRows, err
+			}
+		}
+	}
+	return numRows, flush()
+}
+
+// getImportColumnTypes returns the type names of the target columns of
+// \import, keyed by column name.
+func (c *cliState) getImportColumnTypes(
+	ctx context.Context, cmd importCmd,
+) (_ map[string]string, resErr error) {
+	target := "*"
+	if len(cmd.cols) > 0 {
+		quotedCols := make([]string, len(cmd.cols))
+		for i, col := range cmd.cols {
+			quotedCols[i] = lexbase.EscapeSQLIdent(col)
+		}
+		target = strings.Join(quotedCols, ", ")
+	}
+	rows, err := c.conn.Query(ctx, fmt.Sprintf("SELECT %s FROM %s LIMIT 0", target, cmd.table))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { resErr = errors.CombineErrors(resErr, rows.Close()) }()
+	colTypes := make(map[string]string)
+	for i, col := range rows.Columns() {
+		colTypes[col] = rows.ColumnTypeDatabaseTypeName(i)
+	}
+	return colTypes, nil
+}
+
+func (c *cliState) newRowWriter(
+	format dataFormat, w io.Writer, colNames, colTypes []string,
+) (RowWriter, error) {
+	switch format {
+	case dataFormatCSV:
+		return newCSVRowWriter(w, colNames)
+	case dataFormatNDJSON:
+		return newNDJSONRowWriter(w, colNames, colTypes), nil
+	case dataFormatParquet:
+		return c.sqlCtx.ParquetCodec.NewWriter(w, colNames, colTypes)
+	default:
+		return nil, errors.AssertionFailedf("unknown format: %s", format)
+	}
+}
+
+func (c *cliState) newRowReader(
+	format dataFormat, f *os.File, colTypes map[string]string,
+) (RowReader, error) {
+	switch format {
+	case dataFormatCSV:
+		return newCSVRowReader(f)
+	case dataFormatNDJSON:
+		return newNDJSONRowReader(f)
+	case dataFormatParquet:
+		return c.sqlCtx.ParquetCodec.NewReader(f, colTypes)
+	default:
+		return nil, errors.AssertionFailedf("unknown format: %s", format)
+	}
+}
+
+// textValue converts a value returned by the driver to its text
+// representation.
+func textValue(v driver.Value) *string {
+	var s string
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case string:
+		s = t
+	case []byte:
+		s = string(t)
+	default:
+		s = fmt.Sprint(t)
+	}
+	return &s
+}
+
+// appendCopyTextRow appends a row to buf in the text format of COPY.
+func appendCopyTextRow(buf *bytes.Buffer, row []*string) {
+	for i, v := range row {
+		if i > 0 {
+			buf.WriteByte('\t')
+		}
+		if v == nil {
+			buf.WriteString(`\N`)
+			continue
+		}
+		for j := 0; j < len(*v); j++ {
+			switch ch := (*v)[j]; ch {
+			case '\\':
+				buf.WriteString(`\\`)
+			case '\n':
+				buf.WriteString(`\n`)
+			case '\r':
+				buf.WriteString(`\r`)
+			case '\t':
+				buf.WriteString(`\t`)
+			default:
+				buf.WriteByte(ch)
+			}
+		}
+	}
+	buf.WriteByte('\n')
+}
+
+// csvRowWriter writes rows in the CSV format, preceded by a header
+// row. NULL values are written as empty unquoted fields, and empty
+// strings as "".
+type csvRowWriter struct {
+	w *csv.Writer
+}
+
+func newCSVRowWriter(w io.Writer, colNames []string) (*csvRowWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(colNames); err != nil {
+		return nil, err
+	}
+	return &csvRowWriter{w: cw}, nil
+}
+
+// WriteRow is part of the RowWriter interface.
+func (w *csvRowWriter) WriteRow(row []*string) error {
+	for _, v := range row {
+		var err error
+		switch {
+		case v == nil:
+			err = w.w.WriteField(&bytes.Buffer{})
+		case *v == "":
+			err = w.w.ForceEmptyField()
+		default:
+			err = w.w.WriteField(bytes.NewBufferString(*v))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return w.w.FinishRecord()
+}
+
+// Close is part of the RowWriter interface.
+func (w *csvRowWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// csvRowReader reads rows in the format produced by csvRowWriter.
+type csvRowReader struct {
+	r    *csv.Reader
+	cols []string
+}
+
+func newCSVRowReader(r io.Reader) (*csvRowReader, error) {
+	cr := csv.NewReader(bufio.NewReader(r))
+	header, err := cr.Read()
+	if err == io.EOF {
+		return &csvRowReader{r: cr}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	cols := make([]string, len(header))
+	for i := range header {
+		cols[i] = header[i].Val
+	}
+	return &csvRowReader{r: cr, cols: cols}, nil
+}
+
+// Columns is part of the RowReader interface.
+func (r *csvRowReader) Columns() []string {
+	return r.cols
+}
+
+// ReadRow is part of the RowReader interface.
+func (r *csvRowReader) ReadRow() ([]*string, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	row := make([]*string, len(record))
+	for i := range record {
+		if record[i].Val != "" || record[i].Quoted {
+			row[i] = &record[i].Val
+		}
+	}
+	return row, nil
+}
+
+// Close is part of the RowReader interface.
+func (r *csvRowReader) Close() error {
+	return nil
+}
+
+// ndjsonRowWriter writes rows as newline-delimited JSON objects.
+// Numbers, booleans and JSON values are written as JSON values, and
+// all other values as JSON strings. Note that a JSON null value cannot
+// be distinguished from NULL when the file is imported.
+type ndjsonRowWriter struct {
+	w        *bufio.Writer
+	keys     [][]byte
+	colTypes []string
+}
+
+func newNDJSONRowWriter(w io.Writer, colNames, colTypes []string) *ndjsonRowWriter {
+	keys := make([][]byte, len(colNames))
+	for i, col := range colNames {
+		// Marshaling a string cannot fail.
+		keys[i], _ = json.Marshal(col)
+	}
+	return &ndjsonRowWriter{w: bufio.NewWriter(w), keys: keys, colTypes: colTypes}
+}
+
+// WriteRow is part of the RowWriter interface.
+func (w *ndjsonRowWriter) WriteRow(row []*string) error {
+	_ = w.w.WriteByte('{')
+	for i, v := range row {
+		if i > 0 {
+			_ = w.w.WriteByte(',')
+		}
+		_, _ = w.w.Write(w.keys[i])
+		_ = w.w.WriteByte(':')
+		_, _ = w.w.Write(jsonValue(w.colTypes[i], v))
+	}
+	_, err := w.w.WriteString("}\n")
+	return err
+}
+
+// Close is part of the RowWriter interface.
+func (w *ndjsonRowWriter) Close() error {
+	return w.w.Flush()
+}
+
+// jsonValue encodes a value with the given type name as JSON.
+func jsonValue(typName string, v *string) []byte {
+	if v == nil {
+		return []byte("null")
+	}
+	switch typName {
+	case "INT2", "INT4", "INT8", "FLOAT4", "FLOAT8", "NUMERIC", "JSON", "JSONB":
+		// NaN and infinities are not valid JSON, and are encoded as strings
+		// below.
+		if json.Valid([]byte(*v)) {
+			return []byte(*v)
+		}
+	case "BOOL":
+		switch *v {
+		case "t", "true":
+			return []byte("true")
+		case "f", "false":
+			return []byte("false")
+		}
+	}
+	// Marshaling a string cannot fail.
+	b, _ := json.Marshal(*v)
+	return b
+}
+
+// ndjsonRowReader reads rows from newline-delimited JSON objects. The
+// columns are the keys of the first object. Strings are imported as-is;
+// other JSON values are imported using their JSON representation.
+type ndjsonRowReader struct {
+	dec    *json.Decoder
+	cols   []string
+	colIdx map[string]int
+	// first is the first row, read during initialization.
+	first     []*string
+	recordNum int
+}
+
+func newNDJSONRowReader(r io.Reader) (*ndjsonRowReader, error) {
+	nr := &ndjsonRowReader{dec: json.NewDecoder(bufio.NewReader(r))}
+	keys, vals, err := nr.readRecord()
+	if err == io.EOF {
+		return nr, nil
+	} else if err != nil {
+		return nil, err
+	}
+	nr.cols, nr.first = keys, vals
+	nr.colIdx = make(map[string]int, len(keys))
+	for i, k := range keys {
+		nr.colIdx[k] = i
+	}
+	return nr, nil
+}
+
+// Columns is part of the RowReader interface.
+func (r *ndjsonRowReader) Columns() []string {
+	return r.cols
+}
+
+// ReadRow is part of the RowReader interface.
+func (r *ndjsonRowReader) ReadRow() ([]*string, error) {
+	if r.first != nil {
+		row := r.first
+		r.first = nil
+		return row, nil
+	}
+	keys, vals, err := r.readRecord()
+	if err != nil {
+		return nil, err
+	}
+	row := make([]*string, len(r.cols))
+	for i, k := range keys {
+		idx, ok := r.colIdx[k]
+		if !ok {
+			return nil, errors.Newf("record %d: unexpected key %q", r.recordNum, k)
+		}
+		row[idx] = vals[i]
+	}
+	return row, nil
+}
+
+// readRecord reads one JSON object, preserving the order of its keys.
+func (r *ndjsonRowReader) readRecord() (keys []string, vals []*string, err error) {
+	tok, err := r.dec.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	r.recordNum++
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return nil, nil, errors.Newf("record %d: expected a JSON object", r.recordNum)
+	}
+	for r.dec.More() {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "record %d", r.recordNum)
+		}
+		// Object keys are always strings.
+		key := tok.(string)
+		var raw json.RawMessage
+		if err := r.dec.Decode(&raw); err != nil {
+			return nil, nil, errors.Wrapf(err, "record %d", r.recordNum)
+		}
+		val, err := jsonToText(raw)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "record %d", r.recordNum)
+		}
+		keys = append(keys, key)
+		vals = append(vals, val)
+	}
+	// Consume the closing brace.
+	if _, err := r.dec.Token(); err != nil {
+		return nil, nil, errors.Wrapf(err, "record %d", r.recordNum)
+	}
+	return keys, vals, nil
+}
+
+// jsonToText converts a JSON value to the text representation of the
+// corresponding SQL value.
+func jsonToText(raw json.RawMessage) (*string, error) {
+	switch raw[0] {
+	case 'n':
+		return nil, nil
+	case '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		return &s, nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return nil, err
+	}
+	s := buf.String()
+	return &s, nil
+}
+
+// Close is part of the RowReader interface.
+func (r *ndjsonRowReader) Close() error {
+	return nil
+}
+
+// parseExportCmd parses:
+//
+//	\export QUERY TO 'FILE' [WITH FORMAT csv|ndjson|parquet]
+func parseExportCmd(line string) (exportCmd, error) {
+	args := strings.TrimSpace(strings.TrimPrefix(line, `\export`))
+	toks, err := inspectCmdArgs(args)
+	if err != nil {
+		return exportCmd{}, usageError(line, exportUsage)
+	}
+	// The query itself may contain TO, so use the last one followed by a
+	// string literal.
+	toIdx := -1
+	for i := len(toks) - 2; i > 0; i-- {
+		if isWord(toks[i], "to") && toks[i+1].ID == lexbase.SCONST {
+			toIdx = i
+			break
+		}
+	}
+	if toIdx < 0 {
+		return exportCmd{}, usageError(line, exportUsage)
+	}
+	cmd := exportCmd{
+		query: strings.TrimSpace(args[:toks[toIdx].Start]),
+		file:  toks[toIdx+1].Str,
+	}
+	formatName, ok := parseFormatOption(toks[toIdx+2:])
+	if !ok {
+		return exportCmd{}, usageError(line, exportUsage)
+	}
+	cmd.format, err = resolveDataFormat(cmd.file, formatName)
+	return cmd, err
+}
+
+// parseImportCmd parses:
+//
+//	\import 'FILE' INTO TABLE [(COLUMN, ...)] [WITH FORMAT csv|ndjson|parquet]
+func parseImportCmd(line string) (importCmd, error) {
+	args := strings.TrimSpace(strings.TrimPrefix(line, `\import`))
+	toks, err := inspectCmdArgs(args)
+	if err != nil || len(toks) < 3 || toks[0].ID != lexbase.SCONST || !isWord(toks[1], "into") {
+		return importCmd{}, usageError(line, importUsage)
+	}
+	cmd := importCmd{file: toks[0].Str}
+
+	// The table name extends until the column list or the options.
+	i := 2
+	for i < len(toks) && toks[i].ID != '(' && !isWord(toks[i], "with") {
+		i++
+	}
+	if i == 2 {
+		return importCmd{}, usageError(line, importUsage)
+	}
+	end := int32(len(args))
+	if i < len(toks) {
+		end = toks[i].Start
+	}
+	cmd.table = strings.TrimSpace(args[toks[2].Start:end])
+
+	if i < len(toks) && toks[i].ID == '(' {
+		for i++; ; i += 2 {
+			if i+1 >= len(toks) || toks[i].MaybeID != lexbase.IDENT {
+				return importCmd{}, usageError(line, importUsage)
+			}
+			cmd.cols = append(cmd.cols, toks[i].Str)
+			if toks[i+1].ID == ')' {
+				i += 2
+				break
+			}
+			if toks[i+1].ID != ',' {
+				return importCmd{}, usageError(line, importUsage)
+			}
+		}
+	}
+
+	formatName, ok := parseFormatOption(toks[i:])
+	if !ok {
+		return importCmd{}, usageError(line, importUsage)
+	}
+	cmd.format, err = resolveDataFormat(cmd.file, formatName)
+	return cmd, err
+}
+
+// inspectCmdArgs splits the arguments of \export and \import into SQL
+// tokens.
+func inspectCmdArgs(args string) ([]scanner.InspectToken, error) {
+	toks := scanner.Inspect(args)
+	last := toks[len(toks)-1]
+	if last.ID != 0 {
+		return nil, errors.Newf("invalid input: %s", last.Str)
+	}
+	return toks[:len(toks)-1], nil
+}
+
+// isWord returns whether the token is the given unquoted keyword or
+// identifier.
+func isWord(tok scanner.InspectToken, word string) bool {
+	return tok.MaybeID == lexbase.IDENT && !tok.Quoted && tok.Str == word
+}
+
+// parseFormatOption parses the optional [WITH] FORMAT name clause.
+func parseFormatOption(toks []scanner.InspectToken) (name string, ok bool) {
+	if len(toks) == 0 {
+		return "", true
+	}
+	if isWord(toks[0], "with") {
+		toks = toks[1:]
+	}
+	switch {
+	case len(toks) == 2 && isWord(toks[0], "format") && toks[1].MaybeID == lexbase.IDENT:
+		return toks[1].Str, true
+	default:
+		return "", false
+	}
+}
+
+// resolveDataFormat determines the format of a data file, from the
+// format name if specified or otherwise from the file extension.
+func resolveDataFormat(file, formatName string) (dataFormat, error) {
+	if formatName == "" {
+		formatName = strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+		if formatName == "" {
+			return "", errors.WithHint(errors.Newf("cannot determine the format of %q", file),
+				"Use WITH FORMAT csv, ndjson or parquet.")
+		}
+	}
+	switch formatName {
+	case "csv":
+		return dataFormatCSV, nil
+	case "ndjson", "jsonl", "json":
+		return dataFormatNDJSON, nil
+	case "parquet":
+		return dataFormatParquet, nil
+	default:
+		return "", errors.WithHint(errors.Newf("unsupported format: %q", formatName),
+			"Supported formats: csv, ndjson, parquet.")
+	}
+}
+
+func usageError(line, usage string) error {
+	return errors.WithHint(errors.Newf("invalid syntax: %s", line), "Usage: "+usage)
+}
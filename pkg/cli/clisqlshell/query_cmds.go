@@ -0,0 +1,440 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package clisqlshell
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/clisqlclient"
+	"github.com/cockroachdb/cockroach/pkg/cli/clisqlexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/scanner"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// queryCmd describes a client-side command that terminates a query
+// and defines how its results are processed: \gset, \gexec or \watch.
+type queryCmd struct {
+	// name is the command name, including the leading backslash.
+	name string
+	// prefix is the variable name prefix for \gset.
+	prefix string
+	// interval is the delay between executions for \watch.
+	interval time.Duration
+	// count is the number of executions for \watch. Zero means
+	// "until interrupted or until an error occurs".
+	count int
+}
+
+const defaultWatchInterval = 2 * time.Second
+
+// clientVarNameRe defines the valid names for client-side variables.
+var clientVarNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// isQueryCmd returns true if the given input line starts with one of
+// the query-terminating client-side commands.
+func isQueryCmd(line string) bool {
+	switch localCmdName(line) {
+	case `\gset`, `\gexec`, `\watch`:
+		return true
+	}
+	return false
+}
+
+// localCmdName returns the first word of a client-side command line.
+func localCmdName(line string) string {
+	name := strings.TrimRight(line, "; ")
+	if i := strings.IndexAny(name, " \t"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// splitQueryCmd looks for a query-terminating client-side command in
+// the given SQL text. If one is found, it returns the SQL text
+// preceding the command and the command line.
+func splitQueryCmd(sql string) (stmt, cmdLine string, ok bool) {
+	for _, tok := range scanner.Inspect(sql) {
+		if tok.ID == 0 || tok.ID == lexbase.ERROR {
+			break
+		}
+		if tok.ID != '\\' {
+			continue
+		}
+		// Only the first backslash outside of a string or identifier is
+		// considered.
+		cmdLine = sql[tok.Start:]
+		if !isQueryCmd(cmdLine) {
+			return "", "", false
+		}
+		return strings.TrimSpace(sql[:tok.Start]), cmdLine, true
+	}
+	return "", "", false
+}
+
+// parseQueryCmd parses the arguments of a query-terminating
+// client-side command.
+func parseQueryCmd(cmdLine string) (queryCmd, error) {
+	args, err := scanLocalCmdArgs(strings.TrimRight(cmdLine, "; "))
+	if err != nil {
+		return queryCmd{}, err
+	}
+	cmd := queryCmd{name: args[0]}
+	args = args[1:]
+	switch cmd.name {
+	case `\gset`:
+		if len(args) > 1 {
+			return queryCmd{}, errors.Newf(`%s: too many arguments`, cmd.name)
+		}
+		if len(args) == 1 {
+			cmd.prefix = args[0]
+			if !clientVarNameRe.MatchString(cmd.prefix) {
+				return queryCmd{}, errors.Newf(`%s: invalid variable name prefix: %q`, cmd.name, cmd.prefix)
+			}
+		}
+
+	case `\gexec`:
+		if len(args) > 0 {
+			return queryCmd{}, errors.Newf(`%s: too many arguments`, cmd.name)
+		}
+
+	case `\watch`:
+		// Syntax: \watch [SEC] [i=SEC|interval=SEC] [c=N|count=N].
+		cmd.interval = defaultWatchInterval
+		hasInterval := false
+		for _, arg := range args {
+			key, val, hasKey := strings.Cut(arg, "=")
+			if !hasKey {
+				key, val = "interval", arg
+			}
+			switch key {
+			case "i", "interval":
+				if hasInterval {
+					return queryCmd{}, errors.Newf(`%s: interval specified more than once`, cmd.name)
+				}
+				hasInterval = true
+				secs, err := strconv.ParseFloat(val, 64)
+				if err != nil || secs < 0 {
+					return queryCmd{}, errors.Newf(`%s: invalid interval: %q`, cmd.name, val)
+				}
+				cmd.interval = time.Duration(secs * float64(time.Second))
+			case "c", "count":
+				if cmd.count != 0 {
+					return queryCmd{}, errors.Newf(`%s: count specified more than once`, cmd.name)
+				}
+				n, err := strconv.Atoi(val)
+				if err != nil || n <= 0 {
+					return queryCmd{}, errors.Newf(`%s: invalid count: %q`, cmd.name, val)
+				}
+				cmd.count = n
+			default:
+				return queryCmd{}, errors.Newf(`%s: unknown option: %q`, cmd.name, key)
+			}
+		}
+	}
+	return cmd, nil
+}
+
+// prepareQueryCmd processes the statement text terminated by a
+// query-terminating client-side command. An empty statement stands
+// for the last query executed.
+func (c *cliState) prepareQueryCmd(
+	stmt, cmdLine string, startState, checkState, execState cliStateEnum,
+) cliStateEnum {
+	c.addHistory(c.concatLines)
+
+	cmd, err := parseQueryCmd(c.interpolateVars(cmdLine))
+	if err != nil {
+		return c.cliError(startState, err)
+	}
+	if stmt == "" {
+		if c.iCtx.lastQuery == "" {
+			return c.cliError(startState, errors.Newf(`%s: no query to execute`, cmd.name))
+		}
+		// The last query was already interpolated.
+		stmt = c.iCtx.lastQuery
+	} else {
+		stmt = c.interpolateVars(stmt)
+	}
+	c.concatLines = stmt
+	c.queryCmd = &cmd
+
+	if !c.iCtx.checkSyntax {
+		return execState
+	}
+	return checkState
+}
+
+// doRunQueryCmd runs the statements in concatLines and processes
+// their results according to the query-terminating command.
+func (c *cliState) doRunQueryCmd(nextState cliStateEnum) cliStateEnum {
+	cmd := c.queryCmd
+	c.queryCmd = nil
+	c.iCtx.lastQuery = c.concatLines
+
+	var err error
+	switch cmd.name {
+	case `\gset`:
+		err = c.runGset(c.concatLines, cmd.prefix)
+	case `\gexec`:
+		err = c.runGexec(c.concatLines)
+	case `\watch`:
+		err = c.runWatch(c.concatLines, cmd.interval, cmd.count)
+	default:
+		err = errors.AssertionFailedf("unknown query command: %s", cmd.name)
+	}
+	if err != nil {
+		return c.cliError(nextState, err)
+	}
+	return nextState
+}
+
+// runGset implements \gset: the query must return exactly one row,
+// whose values are stored in the client-side variables named after the
+// result columns. NULL values unset the corresponding variable.
+func (c *cliState) runGset(query, prefix string) error {
+	cols, rows, err := c.runQueryForValues(query)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return errors.New(`\gset: no rows returned`)
+	}
+	if len(rows) > 1 {
+		return errors.New(`\gset: more than one row returned`)
+	}
+	for _, col := range cols {
+		if name := prefix + col; !clientVarNameRe.MatchString(name) {
+			return errors.WithHint(errors.Newf(`\gset: invalid variable name: %q`, name),
+				"Use AS to give the column a valid name.")
+		}
+	}
+	for i, col := range cols {
+		name := prefix + col
+		if rows[0][i] == nil {
+			err = c.unsetClientVar(name)
+		} else {
+			err = c.setClientVar(name, *rows[0][i])
+		}
+		if err != nil {
+			return errors.Wrapf(err, `\gset %s`, name)
+		}
+	}
+	return nil
+}
+
+// runGexec implements \gexec: every non-NULL value returned by the
+// query is executed in turn as a SQL statement, row by row and column
+// by column. Execution stops at the first error.
+func (c *cliState) runGexec(query string) error {
+	_, rows, err := c.runQueryForValues(query)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		for _, val := range row {
+			if val == nil {
+				continue
+			}
+			stmt := *val
+			if err := c.runWithInterruptableCtx(func(ctx context.Context) error {
+				defer c.maybeFlushOutput()
+				return c.sqlExecCtx.RunQueryAndFormatResults(ctx,
+					c.conn,
+					c.iCtx.queryOutput, // query output
+					c.iCtx.stdout,      // timings
+					c.iCtx.stderr,      // errors
+					clisqlclient.MakeQuery(stmt))
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runWatch implements \watch: the query is executed repeatedly, with
+// the given interval between executions, until it fails, the count is
+// reached or the user interrupts it with Ctrl+C.
+func (c *cliState) runWatch(query string, interval time.Duration, count int) error {
+	for i := 0; count == 0 || i < count; i++ {
+		if i > 0 && c.waitForWatchInterval(interval) {
+			// Interrupted.
+			return nil
+		}
+		if c.cliCtx.IsInteractive {
+			fmt.Fprintf(c.iCtx.queryOutput, "%s (every %s)\n\n",
+				timeutil.Now().Format(time.RFC1123), interval)
+		}
+		if err := c.runWithInterruptableCtx(func(ctx context.Context) error {
+			defer c.maybeFlushOutput()
+			return c.sqlExecCtx.RunQueryAndFormatResults(ctx,
+				c.conn,
+				c.iCtx.queryOutput, // query output
+				c.iCtx.stdout,      // timings
+				c.iCtx.stderr,      // errors
+				clisqlclient.MakeQuery(query))
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForWatchInterval waits for the given duration, and returns true
+// if the wait was interrupted with Ctrl+C.
+func (c *cliState) waitForWatchInterval(d time.Duration) (interrupted bool) {
+	intCh := make(chan os.Signal, 1)
+	signal.Notify(intCh, os.Interrupt)
+	defer signal.Stop(intCh)
+	select {
+	case <-intCh:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+// runQueryForValues runs the given query and returns the columns and
+// rows of its last result set. NULL values are reported as nil.
+func (c *cliState) runQueryForValues(query string) (cols []string, rows [][]*string, err error) {
+	err = c.runWithInterruptableCtx(func(ctx context.Context) (resErr error) {
+		r, err := c.conn.Query(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer func() { resErr = errors.CombineErrors(resErr, r.Close()) }()
+		for {
+			if rCols := r.Columns(); len(rCols) > 0 {
+				cols, rows = rCols, nil
+			}
+			vals := make([]driver.Value, len(r.Columns()))
+			for {
+				if err := r.Next(vals); err == io.EOF {
+					break
+				} else if err != nil {
+					return err
+				}
+				row := make([]*string, len(vals))
+				for i, v := range vals {
+					if v != nil {
+						s := clisqlexec.FormatVal(v, true /* showPrintableUnicode */, true /* showNewLinesAndTabs */)
+						row[i] = &s
+					}
+				}
+				rows = append(rows, row)
+			}
+			if more, err := r.NextResultSet(); err != nil {
+				return err
+			} else if !more {
+				return nil
+			}
+		}
+	})
+	return cols, rows, err
+}
+
+// setClientVar sets a client-side variable. If the name designates a
+// shell option, the option is configured instead.
+func (c *cliState) setClientVar(name, val string) error {
+	opt, ok := options[name]
+	if !ok {
+		if !clientVarNameRe.MatchString(name) {
+			return errors.Newf("invalid variable name: %q", name)
+		}
+		if c.iCtx.vars == nil {
+			c.iCtx.vars = make(map[string]string)
+		}
+		c.iCtx.vars[name] = val
+		return nil
+	}
+	if !opt.isBoolean {
+		return opt.set(c, val)
+	}
+	b, err := clisqlclient.ParseBool(val)
+	if err != nil {
+		return err
+	}
+	if b {
+		return opt.set(c, "true")
+	}
+	return opt.reset(c)
+}
+
+// unsetClientVar removes a client-side variable. If the name
+// designates a shell option, the option is reset instead.
+func (c *cliState) unsetClientVar(name string) error {
+	if opt, ok := options[name]; ok {
+		return opt.reset(c)
+	}
+	delete(c.iCtx.vars, name)
+	return nil
+}
+
+// interpolateVars substitutes the client-side variables in the given
+// SQL text. Like in psql, :name is replaced by the value verbatim,
+// :'name' by the value as a SQL string literal and :"name" by the value
+// as a SQL identifier. References to undefined variables, and
+// references inside string literals or quoted identifiers, are left
+// unchanged.
+func (c *cliState) interpolateVars(sql string) string {
+	if len(c.iCtx.vars) == 0 {
+		return sql
+	}
+	toks := scanner.Inspect(sql)
+	var buf strings.Builder
+	last := 0
+	for i := 0; i+1 < len(toks); i++ {
+		tok, next := toks[i], toks[i+1]
+		if tok.ID != ':' || next.Start != tok.End ||
+			next.ID == 0 || next.ID == lexbase.ERROR {
+			continue
+		}
+		// NB: the scanner includes the whitespace following string
+		// literals into the token.
+		ref := strings.TrimRight(sql[next.Start:next.End], " \t\r\n\f")
+		end := int(next.Start) + len(ref)
+		quote := byte(0)
+		if len(ref) >= 2 && (ref[0] == '\'' || ref[0] == '"') && ref[len(ref)-1] == ref[0] {
+			quote = ref[0]
+			ref = ref[1 : len(ref)-1]
+		}
+		val, ok := c.iCtx.vars[ref]
+		if !ok || !clientVarNameRe.MatchString(ref) {
+			continue
+		}
+		switch quote {
+		case '\'':
+			val = lexbase.EscapeSQLString(val)
+		case '"':
+			val = lexbase.EscapeSQLIdent(val)
+		}
+		buf.WriteString(sql[last:tok.Start])
+		buf.WriteString(val)
+		last = end
+		i++
+	}
+	if last == 0 {
+		return sql
+	}
+	buf.WriteString(sql[last:])
+	return buf.String()
+}
@@ -64,6 +64,11 @@ Query Buffer
   \p                during a multi-line statement, show the SQL entered so far.
   \r                during a multi-line statement, erase all the SQL entered so far.
   \| CMD            run an external command and run its output as SQL statements.
+  \gset [PREFIX]    execute the query and store its single result row into client-side variables.
+  \gexec            execute the query, then execute each value of its result as a statement.
+  \watch [SEC] [i=SEC] [c=N]
+                    execute the query every SEC seconds (default 2), N times (default: until interrupted).
+                    Without a query, re-executes the last query.
 
 Connection
   \info             display server details including connection strings.
@@ -76,7 +81,11 @@ Connection
 
 Input/Output
   \echo [STRING]    write the provided string to standard output.
+  \export QUERY TO 'FILE' [WITH FORMAT csv|ndjson|parquet]
+                    write the results of a query to a local file.
   \i                execute commands from the specified file.
+  \import 'FILE' INTO TABLE [(COLUMN, ...)] [WITH FORMAT csv|ndjson|parquet]
+                    load a local file into a table using batched COPY statements.
   \ir               as \i, but relative to the location of the current script.
   \o [FILE]         send all query results to the specified file.
   \qecho [STRING]   write the provided string to the query output stream (see \o).
@@ -109,8 +118,10 @@ Operating System
   \! CMD            run an external command and print its results on standard output.
 
 Configuration
-  \set [NAME]       set a client-side flag or (without argument) print the current settings.
-  \unset NAME       unset a flag.
+  \set [NAME [VALUE]]
+                    set a client-side flag or variable, or (without argument) print the current settings.
+  \unset NAME       unset a flag or variable.
+                    Variables are substituted in SQL text as :NAME, :'NAME' (string) or :"NAME" (identifier).
 
 Statement diagnostics
   \statement-diag list                               list available bundles.
@@ -215,6 +226,12 @@ type cliState struct {
 	// doCheckStatement().
 	concatLines string
 
+	// queryCmd, when non-nil, is the query-terminating client-side
+	// command (\gset, \gexec, \watch) that ended the statement in
+	// concatLines. It is computed during doPrepareStatementLine and
+	// consumed by doRunStatements().
+	queryCmd *queryCmd
+
 	// exitErr defines the error to report to the user upon termination.
 	// This can carry over from one line of input to another. For
 	// example in the interactive shell, a statement causing a SQL
@@ -608,6 +625,24 @@ func (c *cliState) handleSet(
 			panic(err)
 		}
 
+		if len(c.iCtx.vars) > 0 {
+			varNames := make([]string, 0, len(c.iCtx.vars))
+			for n := range c.iCtx.vars {
+				varNames = append(varNames, n)
+			}
+			sort.Strings(varNames)
+			varData := make([][]string, len(varNames))
+			for i, n := range varNames {
+				varData[i] = []string{n, c.iCtx.vars[n]}
+			}
+			err := c.sqlExecCtx.PrintQueryOutput(c.iCtx.stdout, c.iCtx.stderr,
+				[]string{"Variable", "Value"},
+				clisqlexec.NewRowSliceIter(varData, "ll" /*align*/))
+			if err != nil {
+				panic(err)
+			}
+		}
+
 		return nextState
 	}
 
@@ -618,7 +653,19 @@ func (c *cliState) handleSet(
 
 	opt, ok := options[optName]
 	if !ok {
-		return c.cliError(errState, errors.Newf("unknown variable name: %q", optName))
+		// Not a shell option: this defines a client-side variable.
+		// Like in psql, the value defaults to the empty string.
+		if hasValue && len(val) > 0 && val[0] == '"' {
+			var err error
+			val, err = strconv.Unquote(val)
+			if err != nil {
+				return c.invalidSyntax(errState)
+			}
+		}
+		if err := c.setClientVar(optName, val); err != nil {
+			return c.cliError(errState, err)
+		}
+		return nextState
 	}
 	if len(c.partialLines) > 0 && !opt.validDuringMultilineEntry {
 		return c.invalidOptionChange(errState, optName)
@@ -661,7 +708,11 @@ func (c *cliState) handleUnset(args []string, nextState, errState cliStateEnum)
 	}
 	opt, ok := options[args[0]]
 	if !ok {
-		return c.cliError(errState, errors.Newf("unknown variable name: %q", args[0]))
+		if _, ok := c.iCtx.vars[args[0]]; !ok {
+			return c.cliError(errState, errors.Newf("unknown variable name: %q", args[0]))
+		}
+		delete(c.iCtx.vars, args[0])
+		return nextState
 	}
 	if len(c.partialLines) > 0 && !opt.validDuringMultilineEntry {
 		return c.invalidOptionChange(errState, args[0])
@@ -1402,6 +1453,13 @@ func (c *cliState) doHandleCliCmd(loopState, nextState cliStateEnum) cliStateEnu
 		errState = cliStop
 	}
 
+	if isQueryCmd(c.lastInputLine) {
+		// \gset, \gexec and \watch terminate the current query. They
+		// are processed together with the query text by
+		// doPrepareStatementLine.
+		return nextState
+	}
+
 	// This is a client-side command. Whatever happens, we are not going
 	// to handle it as a statement, so save the history.
 	c.addHistory(c.lastInputLine)
@@ -1409,6 +1467,17 @@ func (c *cliState) doHandleCliCmd(loopState, nextState cliStateEnum) cliStateEnu
 	// As a convenience to the user, we strip the final semicolon, if
 	// any, in all cases.
 	line := strings.TrimRight(c.lastInputLine, "; ")
+	// Substitute client-side variables in the arguments.
+	line = c.interpolateVars(line)
+
+	// \export and \import embed SQL syntax in their arguments, so they
+	// parse the line themselves.
+	switch localCmdName(line) {
+	case `\export`:
+		return c.runExport(line, loopState, errState)
+	case `\import`:
+		return c.runImport(line, loopState, errState)
+	}
 
 	cmd, err := scanLocalCmdArgs(line)
 	if err != nil {
@@ -1955,6 +2024,13 @@ func (c *cliState) doPrepareStatementLine(
 		return startState
 	}
 
+	if !c.inCopy() {
+		if stmt, cmdLine, ok := splitQueryCmd(c.concatLines); ok {
+			// The query is terminated by \gset, \gexec or \watch.
+			return c.prepareQueryCmd(stmt, cmdLine, startState, checkState, execState)
+		}
+	}
+
 	lastTok, ok := scanner.LastLexicalToken(c.concatLines)
 	if c.partialStmtsLen == 0 && !ok {
 		// More whitespace, or comments. Still nothing to do. However
@@ -1992,6 +2068,7 @@ func (c *cliState) doPrepareStatementLine(
 	// Complete input. Remember it in the history.
 	if !c.inCopy() {
 		c.addHistory(c.concatLines)
+		c.concatLines = c.interpolateVars(c.concatLines)
 	}
 
 	if !c.iCtx.checkSyntax {
@@ -2107,6 +2184,14 @@ func (c *cliState) doRunStatements(nextState cliStateEnum) cliStateEnum {
 	// Clear the known state so that further entries do not assume anything.
 	c.lastKnownTxnStatus = " ?"
 
+	if c.queryCmd != nil {
+		return c.doRunQueryCmd(nextState)
+	}
+	if !c.inCopy() && scanner.FirstLexicalToken(c.concatLines) != lexbase.COPY {
+		// Remember the query for \watch.
+		c.iCtx.lastQuery = c.concatLines
+	}
+
 	// Are we tracing?
 	if c.iCtx.autoTrace != "" {
 		// Clear the trace by disabling tracing, then restart tracing
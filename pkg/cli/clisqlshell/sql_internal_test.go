@@ -14,6 +14,7 @@ import (
 	"bufio"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/cli/clicfg"
 	"github.com/cockroachdb/cockroach/pkg/cli/clisqlclient"
@@ -192,3 +193,130 @@ func TestGetSetArgs(t *testing.T) {
 		}
 	}
 }
+
+func TestInterpolateVars(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	c := setupTestCliState()
+	c.iCtx.vars = map[string]string{"a": "123", "t": "it's", "id": `my"tab`}
+
+	td := []struct {
+		input    string
+		expected string
+	}{
+		{`SELECT :a`, `SELECT 123`},
+		{`SELECT :'t'`, `SELECT e'it\'s'`},
+		{`SELECT * FROM :"id"`, `SELECT * FROM "my""tab"`},
+		{`SELECT :a + :a, :b`, `SELECT 123 + 123, :b`},
+		// Casts, literals and quoted identifiers are left alone.
+		{`SELECT 1::a`, `SELECT 1::a`},
+		{`SELECT ':a', ":a"`, `SELECT ':a', ":a"`},
+		// Space after the colon.
+		{`SELECT x[1: a]`, `SELECT x[1: a]`},
+	}
+	for _, tc := range td {
+		assert.Equal(t, tc.expected, c.interpolateVars(tc.input), tc.input)
+	}
+}
+
+func TestSplitQueryCmd(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	td := []struct {
+		input   string
+		ok      bool
+		stmt    string
+		cmdLine string
+	}{
+		{`SELECT 1 \gset`, true, `SELECT 1`, `\gset`},
+		{"SELECT 1\n\\gset p_", true, `SELECT 1`, `\gset p_`},
+		{`SELECT 1; \gexec`, true, `SELECT 1;`, `\gexec`},
+		{`\watch 1`, true, ``, `\watch 1`},
+		{`SELECT '\gset'`, false, ``, ``},
+		{`SELECT 1 \x`, false, ``, ``},
+		{`SELECT 1;`, false, ``, ``},
+	}
+	for _, tc := range td {
+		stmt, cmdLine, ok := splitQueryCmd(tc.input)
+		assert.Equal(t, tc.ok, ok, tc.input)
+		assert.Equal(t, tc.stmt, stmt, tc.input)
+		assert.Equal(t, tc.cmdLine, cmdLine, tc.input)
+	}
+
+	for _, tc := range []struct {
+		input    string
+		expected queryCmd
+		err      string
+	}{
+		{`\gset`, queryCmd{name: `\gset`}, ``},
+		{`\gset p_;`, queryCmd{name: `\gset`, prefix: `p_`}, ``},
+		{`\gset a b`, queryCmd{}, `too many arguments`},
+		{`\watch`, queryCmd{name: `\watch`, interval: defaultWatchInterval}, ``},
+		{`\watch 0.5 c=3`, queryCmd{name: `\watch`, interval: 500 * time.Millisecond, count: 3}, ``},
+		{`\watch interval=1 count=2`, queryCmd{name: `\watch`, interval: time.Second, count: 2}, ``},
+		{`\watch c=0`, queryCmd{}, `invalid count`},
+		{`\watch 1 i=2`, queryCmd{}, `interval specified more than once`},
+		{`\watch x=1`, queryCmd{}, `unknown option`},
+	} {
+		cmd, err := parseQueryCmd(tc.input)
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err, tc.input)
+			continue
+		}
+		assert.NoError(t, err, tc.input)
+		assert.Equal(t, tc.expected, cmd, tc.input)
+	}
+}
+
+func TestParseDataTransferCmds(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		input    string
+		expected exportCmd
+		err      string
+	}{
+		{`\export SELECT 1 TO 'a.csv'`, exportCmd{query: `SELECT 1`, file: `a.csv`, format: dataFormatCSV}, ``},
+		{`\export SELECT 'to' AS "to" FROM t TO 'a.JSONL'`,
+			exportCmd{query: `SELECT 'to' AS "to" FROM t`, file: `a.JSONL`, format: dataFormatNDJSON}, ``},
+		{`\export TABLE t to 'out' with format parquet`,
+			exportCmd{query: `TABLE t`, file: `out`, format: dataFormatParquet}, ``},
+		{`\export SELECT 1 TO 'a' FORMAT csv`, exportCmd{query: `SELECT 1`, file: `a`, format: dataFormatCSV}, ``},
+		{`\export TO 'a.csv'`, exportCmd{}, `invalid syntax`},
+		{`\export SELECT 1 TO 'a.csv' WITH`, exportCmd{}, `invalid syntax`},
+		{`\export SELECT 1 TO 'a.txt'`, exportCmd{}, `unsupported format: "txt"`},
+		{`\export SELECT 1 TO 'a.csv' WITH FORMAT avro`, exportCmd{}, `unsupported format: "avro"`},
+	} {
+		cmd, err := parseExportCmd(tc.input)
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err, tc.input)
+			continue
+		}
+		assert.NoError(t, err, tc.input)
+		assert.Equal(t, tc.expected, cmd, tc.input)
+	}
+
+	for _, tc := range []struct {
+		input    string
+		expected importCmd
+		err      string
+	}{
+		{`\import 'a.csv' INTO t`, importCmd{file: `a.csv`, table: `t`, format: dataFormatCSV}, ``},
+		{`\import 'a.ndjson' into db.public."T" (a, "B")`,
+			importCmd{file: `a.ndjson`, table: `db.public."T"`, cols: []string{`a`, `B`}, format: dataFormatNDJSON}, ``},
+		{`\import 'a' INTO t WITH FORMAT parquet`, importCmd{file: `a`, table: `t`, format: dataFormatParquet}, ``},
+		{`\import 'a.csv' t`, importCmd{}, `invalid syntax`},
+		{`\import 'a.csv' INTO (a)`, importCmd{}, `invalid syntax`},
+		{`\import 'a.csv' INTO t (a`, importCmd{}, `invalid syntax`},
+		{`\import 'a.csv' INTO t (a b)`, importCmd{}, `invalid syntax`},
+		{`\import 'a' INTO t`, importCmd{}, `cannot determine the format`},
+	} {
+		cmd, err := parseImportCmd(tc.input)
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err, tc.input)
+			continue
+		}
+		assert.NoError(t, err, tc.input)
+		assert.Equal(t, tc.expected, cmd, tc.input)
+	}
+}
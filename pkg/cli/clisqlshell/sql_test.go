@@ -132,7 +132,7 @@ func Example_sql_config() {
 	// Possible to run client-side commands with -e.
 	c.RunWithArgs([]string{`sql`, `-e`, `\set display_format=raw`, `-e`, `select 123 as "123"`})
 	// A failure in a client-side command prevents subsequent statements from executing.
-	c.RunWithArgs([]string{`sql`, `--set`, `unknownoption`, `-e`, `select 123 as "123"`})
+	c.RunWithArgs([]string{`sql`, `--set`, `1invalid`, `-e`, `select 123 as "123"`})
 	c.RunWithArgs([]string{`sql`, `--set`, `display_format=invalidvalue`, `-e`, `select 123 as "123"`})
 	c.RunWithArgs([]string{`sql`, `-e`, `\set display_format=invalidvalue`, `-e`, `select 123 as "123"`})
 
@@ -158,8 +158,8 @@ func Example_sql_config() {
 	// ## 3
 	// 123
 	// # 1 row
-	// sql --set unknownoption -e select 123 as "123"
-	// ERROR: -e: unknown variable name: "unknownoption"
+	// sql --set 1invalid -e select 123 as "123"
+	// ERROR: -e: invalid variable name: "1invalid"
 	// sql --set display_format=invalidvalue -e select 123 as "123"
 	// ERROR: -e: \set display_format=invalidvalue: invalid table display format: invalidvalue
 	// HINT: Possible values: tsv, csv, table, records, ndjson, json, sql, html, unnumbered-html, raw.
@@ -188,6 +188,168 @@ func Example_sql_watch() {
 	// SQLSTATE: 22012
 }
 
+func Example_sql_export_import() {
+	c := cli.NewCLITest(cli.TestCLIParams{})
+	defer c.Cleanup()
+
+	// Use relative file names, so that the output is stable.
+	dir, err := os.MkdirTemp("", "export-import")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	prevDir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if err := os.Chdir(dir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	defer func() { _ = os.Chdir(prevDir) }()
+	printFile := func(name string) {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Print(string(data))
+	}
+
+	const cols = `i int primary key, s string, f float, b bool, j jsonb, a int[], d decimal`
+	c.RunWithArgs([]string{`sql`, `-e`, `create database t; create table t.src(` + cols + `)`, `-e`,
+		`insert into t.src values (1, e'a,b\tc', 1.5, true, '{"k": [1]}', array[1, null], 1.50), (2, '', -0.25, false, '[]', array[], 'NaN'), (3, null, null, null, null, null, null)`})
+	for _, format := range []string{"csv", "ndjson", "parquet"} {
+		c.RunWithArgs([]string{`sql`, `-e`, `\export select * from t.src order by i to 'rows.` + format + `'`})
+		if format != "parquet" {
+			printFile("rows." + format)
+		}
+		c.RunWithArgs([]string{`sql`, `-e`, `create table t.` + format + `(` + cols + `)`,
+			`-e`, `\import 'rows.` + format + `' into t.` + format, `-e`, `select * from t.` + format + ` order by i`})
+	}
+
+	// Explicit format and target columns.
+	c.RunWithArgs([]string{`sql`, `-e`, `\export select i, s from t.src where i > 1 to 'rows.txt' with format csv`,
+		`-e`, `create table t.partial(i int primary key, s string default 'x', n int)`,
+		`-e`, `\import 'rows.txt' into t.partial (i, s) with format csv`, `-e`, `select * from t.partial`})
+	// Errors.
+	c.RunWithArgs([]string{`sql`, `-e`, `\export select 1 to 'rows'`})
+	c.RunWithArgs([]string{`sql`, `-e`, `\export select 1`})
+	c.RunWithArgs([]string{`sql`, `-e`, `\import 'rows.csv' into t.partial (i)`})
+	c.RunWithArgs([]string{`sql`, `-e`, `\import 'rows.csv' into t.partial`})
+
+	// Output:
+	// sql -e create database t; create table t.src(i int primary key, s string, f float, b bool, j jsonb, a int[], d decimal) -e insert into t.src values (1, e'a,b\tc', 1.5, true, '{"k": [1]}', array[1, null], 1.50), (2, '', -0.25, false, '[]', array[], 'NaN'), (3, null, null, null, null, null, null)
+	// CREATE DATABASE
+	// CREATE TABLE
+	// INSERT 0 3
+	// sql -e \export select * from t.src order by i to 'rows.csv'
+	// EXPORT 3
+	// i,s,f,b,j,a,d
+	// 1,"a,b	c",1.5,t,"{""k"": [1]}","{1,NULL}",1.50
+	// 2,"",-0.25,f,[],{},NaN
+	// 3,,,,,,
+	// sql -e create table t.csv(i int primary key, s string, f float, b bool, j jsonb, a int[], d decimal) -e \import 'rows.csv' into t.csv -e select * from t.csv order by i
+	// CREATE TABLE
+	// IMPORT 3
+	// i	s	f	b	j	a	d
+	// 1	"a,b	c"	1.5	t	"{""k"": [1]}"	{1,NULL}	1.50
+	// 2		-0.25	f	[]	{}	NaN
+	// 3	NULL	NULL	NULL	NULL	NULL	NULL
+	// sql -e \export select * from t.src order by i to 'rows.ndjson'
+	// EXPORT 3
+	// {"i":1,"s":"a,b\tc","f":1.5,"b":true,"j":{"k": [1]},"a":"{1,NULL}","d":1.50}
+	// {"i":2,"s":"","f":-0.25,"b":false,"j":[],"a":"{}","d":"NaN"}
+	// {"i":3,"s":null,"f":null,"b":null,"j":null,"a":null,"d":null}
+	// sql -e create table t.ndjson(i int primary key, s string, f float, b bool, j jsonb, a int[], d decimal) -e \import 'rows.ndjson' into t.ndjson -e select * from t.ndjson order by i
+	// CREATE TABLE
+	// IMPORT 3
+	// i	s	f	b	j	a	d
+	// 1	"a,b	c"	1.5	t	"{""k"": [1]}"	{1,NULL}	1.50
+	// 2		-0.25	f	[]	{}	NaN
+	// 3	NULL	NULL	NULL	NULL	NULL	NULL
+	// sql -e \export select * from t.src order by i to 'rows.parquet'
+	// EXPORT 3
+	// sql -e create table t.parquet(i int primary key, s string, f float, b bool, j jsonb, a int[], d decimal) -e \import 'rows.parquet' into t.parquet -e select * from t.parquet order by i
+	// CREATE TABLE
+	// IMPORT 3
+	// i	s	f	b	j	a	d
+	// 1	"a,b	c"	1.5	t	"{""k"": [1]}"	{1,NULL}	1.50
+	// 2		-0.25	f	[]	{}	NaN
+	// 3	NULL	NULL	NULL	NULL	NULL	NULL
+	// sql -e \export select i, s from t.src where i > 1 to 'rows.txt' with format csv -e create table t.partial(i int primary key, s string default 'x', n int) -e \import 'rows.txt' into t.partial (i, s) with format csv -e select * from t.partial
+	// EXPORT 2
+	// CREATE TABLE
+	// IMPORT 2
+	// i	s	n
+	// 2		NULL
+	// 3	NULL	NULL
+	// sql -e \export select 1 to 'rows'
+	// ERROR: -e: cannot determine the format of "rows"
+	// HINT: Use WITH FORMAT csv, ndjson or parquet.
+	// sql -e \export select 1
+	// ERROR: -e: invalid syntax: \export select 1
+	// HINT: Usage: \export QUERY TO 'FILE' [WITH FORMAT csv|ndjson|parquet]
+	// sql -e \import 'rows.csv' into t.partial (i)
+	// ERROR: -e: importing from rows.csv: column "s" in the file is not a target column of t.partial
+	// sql -e \import 'rows.csv' into t.partial
+	// ERROR: -e: importing from rows.csv: column "f" in the file is not a target column of t.partial
+}
+
+func Example_sql_query_cmds() {
+	c := cli.NewCLITest(cli.TestCLIParams{})
+	defer c.Cleanup()
+
+	// Client-side variables, set via \set or \gset.
+	c.RunWithArgs([]string{`sql`, `-e`, `\set v 'world'`, `-e`, `select 'hello' as greeting, 41 as n \gset`,
+		`-e`, `\echo :greeting :v`, `-e`, `select :n + 1 as answer, :'v' as s, 1 as :"v"`})
+	c.RunWithArgs([]string{`sql`, `-e`, `select 1 as a, null as b \gset p_`, `-e`, `\echo :p_a :p_b`})
+	c.RunWithArgs([]string{`sql`, `-e`, `select 1 as a where false \gset`})
+	c.RunWithArgs([]string{`sql`, `-e`, `select generate_series(1,2) as a \gset`})
+	c.RunWithArgs([]string{`sql`, `-e`, `select 1 \gset`})
+	// Statements generated by a query.
+	c.RunWithArgs([]string{`sql`, `-e`,
+		`select * from (values ('select 1 as x'), (null), ('select 2 as y')) \gexec`})
+	// Repeated execution of a query.
+	c.RunWithArgs([]string{`sql`, `-e`, `create table w(x int); insert into w values (0)`})
+	c.RunWithArgs([]string{`sql`, `-e`, `update w set x = x + 1 returning x \watch 0.01 c=3`})
+	c.RunWithArgs([]string{`sql`, `-e`, `select 1 \watch c=x`})
+
+	// Output:
+	// sql -e \set v 'world' -e select 'hello' as greeting, 41 as n \gset -e \echo :greeting :v -e select :n + 1 as answer, :'v' as s, 1 as :"v"
+	// hello world
+	// answer	s	world
+	// 42	world	1
+	// sql -e select 1 as a, null as b \gset p_ -e \echo :p_a :p_b
+	// 1 :p_b
+	// sql -e select 1 as a where false \gset
+	// ERROR: -e: \gset: no rows returned
+	// sql -e select generate_series(1,2) as a \gset
+	// ERROR: -e: \gset: more than one row returned
+	// sql -e select 1 \gset
+	// ERROR: -e: \gset: invalid variable name: "?column?"
+	// HINT: Use AS to give the column a valid name.
+	// sql -e select * from (values ('select 1 as x'), (null), ('select 2 as y')) \gexec
+	// x
+	// 1
+	// y
+	// 2
+	// sql -e create table w(x int); insert into w values (0)
+	// CREATE TABLE
+	// INSERT 0 1
+	// sql -e update w set x = x + 1 returning x \watch 0.01 c=3
+	// x
+	// 1
+	// x
+	// 2
+	// x
+	// 3
+	// sql -e select 1 \watch c=x
+	// ERROR: -e: \watch: invalid count: "x"
+}
+
 func Example_misc_table() {
 	c := cli.NewCLITest(cli.TestCLIParams{})
 	defer c.Cleanup()
@@ -357,6 +357,7 @@ func runDemoInternal(
 	_, _, certsDir := c.GetSQLCredentials()
 	sqlCtx.ShellCtx.CertsDir = certsDir
 	sqlCtx.ShellCtx.ParseURL = clienturl.MakeURLParserFn(cmd, cliCtx.clientOpts)
+	sqlCtx.ShellCtx.ParquetCodec = parquetCodec{}
 
 	if err := extraInit(ctx, conn); err != nil {
 		return err
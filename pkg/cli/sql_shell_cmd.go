@@ -61,5 +61,6 @@ func runTerm(cmd *cobra.Command, args []string) (resErr error) {
 
 	sqlCtx.ShellCtx.CertsDir = baseCfg.SSLCertsDir
 	sqlCtx.ShellCtx.ParseURL = clienturl.MakeURLParserFn(cmd, cliCtx.clientOpts)
+	sqlCtx.ShellCtx.ParquetCodec = parquetCodec{}
 	return sqlCtx.Run(context.Background(), conn)
 }
@@ -0,0 +1,157 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cli
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/clisqlshell"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgtype"
+	"github.com/lib/pq/oid"
+)
+
+// parquetRowGroupLength is the maximum number of rows in each row
+// group of the parquet files written by \export.
+const parquetRowGroupLength = 10000
+
+// parquetCodec implements clisqlshell.ParquetCodec using the parquet
+// writer also used by EXPORT.
+type parquetCodec struct{}
+
+var _ clisqlshell.ParquetCodec = parquetCodec{}
+
+// NewWriter is part of the clisqlshell.ParquetCodec interface.
+func (parquetCodec) NewWriter(
+	w io.Writer, colNames, colTypes []string,
+) (clisqlshell.RowWriter, error) {
+	typs := make([]*types.T, len(colTypes))
+	for i, name := range colTypes {
+		typs[i] = typeFromPGName(name)
+	}
+	sch, err := parquet.NewSchema(colNames, typs)
+	if err != nil {
+		return nil, err
+	}
+	// The parquet writer closes its sink when it is closed. Hide the
+	// Close method of w, which is owned by the caller.
+	pw, err := parquet.NewWriter(sch, struct{ io.Writer }{w},
+		parquet.WithMaxRowGroupLength(parquetRowGroupLength),
+		parquet.WithReaderMetadata())
+	if err != nil {
+		return nil, err
+	}
+	return &parquetRowWriter{w: pw, typs: typs, datums: make(tree.Datums, len(typs))}, nil
+}
+
+// NewReader is part of the clisqlshell.ParquetCodec interface.
+func (parquetCodec) NewReader(
+	f *os.File, colTypes map[string]string,
+) (clisqlshell.RowReader, error) {
+	typs := make(map[string]*types.T, len(colTypes))
+	for col, name := range colTypes {
+		typs[col] = typeFromPGName(name)
+	}
+	pr, err := parquet.NewReader(f, typs)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetRowReader{r: pr}, nil
+}
+
+// typeFromPGName returns the type corresponding to a type name reported
+// by the SQL driver. Unknown types, for example user-defined types, are
+// handled as strings.
+func typeFromPGName(name string) *types.T {
+	if dt, ok := pgtype.NewConnInfo().DataTypeForName(strings.ToLower(name)); ok {
+		if typ, ok := types.OidToType[oid.Oid(dt.OID)]; ok {
+			return typ
+		}
+	}
+	return types.String
+}
+
+type parquetRowWriter struct {
+	w      *parquet.Writer
+	typs   []*types.T
+	datums tree.Datums
+}
+
+// WriteRow is part of the clisqlshell.RowWriter interface.
+func (w *parquetRowWriter) WriteRow(row []*string) error {
+	parseCtx := tree.NewParseContext(timeutil.Now())
+	for i, v := range row {
+		if v == nil {
+			w.datums[i] = tree.DNull
+			continue
+		}
+		d, _, err := tree.ParseAndRequireString(w.typs[i], *v, parseCtx)
+		if err != nil {
+			return err
+		}
+		w.datums[i] = d
+	}
+	return w.w.AddRow(w.datums)
+}
+
+// Close is part of the clisqlshell.RowWriter interface.
+func (w *parquetRowWriter) Close() error {
+	return w.w.Close()
+}
+
+type parquetRowReader struct {
+	r *parquet.Reader
+	// nextRowGroup is the next row group to read once rows is exhausted.
+	nextRowGroup int
+	rows         []tree.Datums
+}
+
+// Columns is part of the clisqlshell.RowReader interface.
+func (r *parquetRowReader) Columns() []string {
+	return r.r.ColumnNames()
+}
+
+// ReadRow is part of the clisqlshell.RowReader interface.
+func (r *parquetRowReader) ReadRow() ([]*string, error) {
+	for len(r.rows) == 0 {
+		if r.nextRowGroup >= r.r.NumRowGroups() {
+			return nil, io.EOF
+		}
+		rows, err := r.r.ReadRowGroup(r.nextRowGroup)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading row group %d", r.nextRowGroup)
+		}
+		r.rows = rows
+		r.nextRowGroup++
+	}
+	datums := r.rows[0]
+	r.rows = r.rows[1:]
+	row := make([]*string, len(datums))
+	for i, d := range datums {
+		if d == tree.DNull {
+			continue
+		}
+		s := tree.AsStringWithFlags(d, tree.FmtPgwireText)
+		row[i] = &s
+	}
+	return row, nil
+}
+
+// Close is part of the clisqlshell.RowReader interface.
+func (r *parquetRowReader) Close() error {
+	return r.r.Close()
+}
@@ -73,6 +73,7 @@ table_name NOT IN (
 	'cluster_contended_indexes',
 	'cluster_contended_tables',
 	'cluster_inflight_traces',
+	'cluster_lock_waits_graph',
 	'cross_db_references',
 	'databases',
 	'forward_dependencies',
@@ -572,6 +572,30 @@ const (
 	// role for all existing functions.
 	V23_2_GrantExecuteToPublic
 
+	// V23_2_NotificationsTable creates the system.notifications table, which
+	// backs LISTEN and NOTIFY.
+	V23_2_NotificationsTable
+
+	// V23_2_LogicalReplication creates the system.publications and
+	// system.replication_slots tables, which back CREATE PUBLICATION and
+	// logical replication over pgwire.
+	V23_2_LogicalReplication
+
+	// V23_2_ExclusionConstraints is the version where EXCLUDE constraints can
+	// be added to tables.
+	V23_2_ExclusionConstraints
+
+	// V23_2_TableInheritance is the version where tables can inherit from
+	// other tables and be created as partitions of other tables.
+	V23_2_TableInheritance
+
+	// V23_2_Jsonpath is the version where the JSONPATH type can be used.
+	V23_2_Jsonpath
+
+	// V23_2_PGVector is the version where the VECTOR type and vector indexes
+	// can be used.
+	V23_2_PGVector
+
 	// *************************************************
 	// Step (1) Add new versions here.
 	// Do not add new versions to a patch release.
@@ -1001,6 +1025,30 @@ var rawVersionsSingleton = keyedVersions{
 		Key:     V23_2_GrantExecuteToPublic,
 		Version: roachpb.Version{Major: 23, Minor: 1, Internal: 26},
 	},
+	{
+		Key:     V23_2_NotificationsTable,
+		Version: roachpb.Version{Major: 23, Minor: 1, Internal: 28},
+	},
+	{
+		Key:     V23_2_LogicalReplication,
+		Version: roachpb.Version{Major: 23, Minor: 1, Internal: 30},
+	},
+	{
+		Key:     V23_2_ExclusionConstraints,
+		Version: roachpb.Version{Major: 23, Minor: 1, Internal: 32},
+	},
+	{
+		Key:     V23_2_TableInheritance,
+		Version: roachpb.Version{Major: 23, Minor: 1, Internal: 34},
+	},
+	{
+		Key:     V23_2_Jsonpath,
+		Version: roachpb.Version{Major: 23, Minor: 1, Internal: 36},
+	},
+	{
+		Key:     V23_2_PGVector,
+		Version: roachpb.Version{Major: 23, Minor: 1, Internal: 38},
+	},
 
 	// *************************************************
 	// Step (2): Add new versions here.
@@ -35,6 +35,7 @@ const (
 	Constraints            // constraints
 	VoterConstraints       // voter_constraints
 	LeasePreferences       // lease_preferences
+	Tiering                // tiering
 
 	// NumFields is the number of fields in the config.
 	NumFields int = iota - 1
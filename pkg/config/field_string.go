@@ -17,6 +17,7 @@ func _() {
 	_ = x[Constraints-7]
 	_ = x[VoterConstraints-8]
 	_ = x[LeasePreferences-9]
+	_ = x[Tiering-10]
 }
 
 func (i Field) String() string {
@@ -39,6 +40,8 @@ func (i Field) String() string {
 		return "voter_constraints"
 	case LeasePreferences:
 		return "lease_preferences"
+	case Tiering:
+		return "tiering"
 	default:
 		return "Field(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
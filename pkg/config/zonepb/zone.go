@@ -514,6 +514,39 @@ func (z *ZoneConfig) Validate() error {
 		}
 	}
 
+	if z.Tiering != nil {
+		if err := z.Tiering.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsEmpty returns whether the tiering policy is empty. An empty policy
+// explicitly disables tiering, rather than inheriting it from the parent zone.
+func (p *TieringPolicy) IsEmpty() bool {
+	return len(p.Hot) == 0 && len(p.Cold) == 0 && p.ColdAfterSeconds == 0
+}
+
+func (p *TieringPolicy) validate() error {
+	if p.IsEmpty() {
+		return nil
+	}
+	if len(p.Hot) == 0 || len(p.Cold) == 0 {
+		return fmt.Errorf("tiering must specify both hot and cold constraints")
+	}
+	for _, constraints := range [][]Constraint{p.Hot, p.Cold} {
+		for _, constraint := range constraints {
+			if constraint.Type == Constraint_DEPRECATED_POSITIVE {
+				return fmt.Errorf("tiering constraints must either be required " +
+					"(prefixed with a '+') or prohibited (prefixed with a '-')")
+			}
+		}
+	}
+	if p.ColdAfterSeconds < 1 {
+		return fmt.Errorf("tiering cold_after must be at least 1s")
+	}
 	return nil
 }
 
@@ -592,6 +625,12 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 		z.LeasePreferences = parent.LeasePreferences
 		z.InheritedLeasePreferences = false
 	}
+	if z.Tiering == nil {
+		if parent.Tiering != nil {
+			tempTiering := *parent.Tiering
+			z.Tiering = &tempTiering
+		}
+	}
 }
 
 // CopyFromZone copies over the specified fields from the other zone.
@@ -638,6 +677,12 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 		case "lease_preferences":
 			z.LeasePreferences = other.LeasePreferences
 			z.InheritedLeasePreferences = other.InheritedLeasePreferences
+		case "tiering":
+			z.Tiering = nil
+			if other.Tiering != nil {
+				tempTiering := *other.Tiering
+				z.Tiering = &tempTiering
+			}
 		}
 	}
 }
@@ -796,6 +841,12 @@ func (z *ZoneConfig) DiffWithZone(
 					}
 				}
 			}
+		case "tiering":
+			if !z.Tiering.Equal(other.Tiering) {
+				return false, DiffWithZoneMismatch{
+					Field: "tiering",
+				}, nil
+			}
 		default:
 			return false, DiffWithZoneMismatch{}, errors.AssertionFailedf("unknown zone configuration field %q", fieldName)
 		}
@@ -1191,6 +1242,17 @@ func (z *ZoneConfig) toSpanConfig() (roachpb.SpanConfig, error) {
 			}
 		}
 	}
+
+	// An empty tiering policy disables tiering.
+	if z.Tiering != nil && !z.Tiering.IsEmpty() {
+		sc.Tiering = &roachpb.TieringPolicy{ColdAfterSeconds: z.Tiering.ColdAfterSeconds}
+		if sc.Tiering.Hot, err = toSpanConfigConstraints(z.Tiering.Hot); err != nil {
+			return roachpb.SpanConfig{}, err
+		}
+		if sc.Tiering.Cold, err = toSpanConfigConstraints(z.Tiering.Cold); err != nil {
+			return roachpb.SpanConfig{}, err
+		}
+	}
 	return sc, nil
 }
 
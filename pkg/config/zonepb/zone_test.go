@@ -245,6 +245,66 @@ func TestZoneConfigValidate(t *testing.T) {
 			},
 			"",
 		},
+		{
+			ZoneConfig{
+				NumReplicas:   proto.Int32(1),
+				RangeMaxBytes: DefaultZoneConfig().RangeMaxBytes,
+				GC:            &GCPolicy{TTLSeconds: 1},
+				Tiering: &TieringPolicy{
+					Hot:              []Constraint{{Value: "ssd", Type: Constraint_REQUIRED}},
+					ColdAfterSeconds: 60,
+				},
+			},
+			"tiering must specify both hot and cold constraints",
+		},
+		{
+			ZoneConfig{
+				NumReplicas:   proto.Int32(1),
+				RangeMaxBytes: DefaultZoneConfig().RangeMaxBytes,
+				GC:            &GCPolicy{TTLSeconds: 1},
+				Tiering: &TieringPolicy{
+					Hot:              []Constraint{{Value: "ssd", Type: Constraint_DEPRECATED_POSITIVE}},
+					Cold:             []Constraint{{Value: "hdd", Type: Constraint_REQUIRED}},
+					ColdAfterSeconds: 60,
+				},
+			},
+			"tiering constraints must either be required .+ or prohibited .+",
+		},
+		{
+			ZoneConfig{
+				NumReplicas:   proto.Int32(1),
+				RangeMaxBytes: DefaultZoneConfig().RangeMaxBytes,
+				GC:            &GCPolicy{TTLSeconds: 1},
+				Tiering: &TieringPolicy{
+					Hot:  []Constraint{{Value: "ssd", Type: Constraint_REQUIRED}},
+					Cold: []Constraint{{Value: "hdd", Type: Constraint_REQUIRED}},
+				},
+			},
+			"tiering cold_after must be at least 1s",
+		},
+		{
+			ZoneConfig{
+				NumReplicas:   proto.Int32(1),
+				RangeMaxBytes: DefaultZoneConfig().RangeMaxBytes,
+				GC:            &GCPolicy{TTLSeconds: 1},
+				Tiering: &TieringPolicy{
+					Hot:              []Constraint{{Value: "ssd", Type: Constraint_REQUIRED}},
+					Cold:             []Constraint{{Value: "ssd", Type: Constraint_PROHIBITED}},
+					ColdAfterSeconds: 60,
+				},
+			},
+			"",
+		},
+		{
+			// An empty tiering policy disables tiering.
+			ZoneConfig{
+				NumReplicas:   proto.Int32(1),
+				RangeMaxBytes: DefaultZoneConfig().RangeMaxBytes,
+				GC:            &GCPolicy{TTLSeconds: 1},
+				Tiering:       &TieringPolicy{},
+			},
+			"",
+		},
 	}
 
 	for i, c := range testCases {
@@ -966,6 +1026,84 @@ func TestConstraintsListYAML(t *testing.T) {
 	}
 }
 
+func TestTieringPolicyYAML(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		input    string
+		expected TieringPolicy
+		output   string
+		err      string
+	}{
+		{
+			input: "{hot: '+ssd', cold: '+hdd', cold_after: 30d}",
+			expected: TieringPolicy{
+				Hot:              []Constraint{{Type: Constraint_REQUIRED, Value: "ssd"}},
+				Cold:             []Constraint{{Type: Constraint_REQUIRED, Value: "hdd"}},
+				ColdAfterSeconds: 30 * 24 * 60 * 60,
+			},
+			output: "{hot: +ssd, cold: +hdd, cold_after: 30d}",
+		},
+		{
+			input: "{hot: '+ssd,+region=us-east1', cold: '-ssd', cold_after: 90m}",
+			expected: TieringPolicy{
+				Hot: []Constraint{
+					{Type: Constraint_REQUIRED, Value: "ssd"},
+					{Type: Constraint_REQUIRED, Key: "region", Value: "us-east1"},
+				},
+				Cold:             []Constraint{{Type: Constraint_PROHIBITED, Value: "ssd"}},
+				ColdAfterSeconds: 90 * 60,
+			},
+			output: "{hot: '+ssd,+region=us-east1', cold: -ssd, cold_after: 1h30m0s}",
+		},
+		{
+			input:  "{}",
+			output: "{}",
+		},
+		{
+			input: "{hot: '+ssd', cold: '+hdd', cold_after: 30}",
+			err:   `invalid cold_after "30"`,
+		},
+		{
+			input: "{hot: '+ssd', cold: '+hdd', cold_after: xd}",
+			err:   `invalid cold_after "xd": expected a number of days`,
+		},
+		{
+			input: "{hot: '+ssd', cold: '+hdd', warm: '+nvme'}",
+			err:   "field warm not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			var policy TieringPolicy
+			err := yaml.UnmarshalStrict([]byte(tc.input), &policy)
+			if tc.err != "" {
+				require.True(t, testutils.IsError(err, tc.err), "expected %q, got %v", tc.err, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, policy)
+
+			out, err := yaml.Marshal(policy)
+			require.NoError(t, err)
+			// Marshaling into a flow-style zone config produces the same output as
+			// SHOW ZONE CONFIGURATION.
+			var flow struct {
+				Tiering TieringPolicy `yaml:"tiering,flow"`
+			}
+			flow.Tiering = policy
+			flowOut, err := yaml.Marshal(flow)
+			require.NoError(t, err)
+			require.Equal(t, "tiering: "+tc.output+"\n", string(flowOut))
+
+			var roundTripped TieringPolicy
+			require.NoError(t, yaml.UnmarshalStrict(out, &roundTripped))
+			require.Equal(t, policy, roundTripped)
+		})
+	}
+}
+
 func TestMarshalableZoneConfigRoundTrip(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -1424,6 +1562,54 @@ func TestZoneConfigToSpanConfigConversion(t *testing.T) {
 				},
 			},
 		},
+		{
+			zoneConfig: ZoneConfig{
+				RangeMinBytes: proto.Int64(100000),
+				RangeMaxBytes: proto.Int64(200000),
+				GC: &GCPolicy{
+					TTLSeconds: 2400,
+				},
+				NumReplicas: proto.Int32(3),
+				Tiering: &TieringPolicy{
+					Hot:              []Constraint{{Type: Constraint_REQUIRED, Value: "ssd"}},
+					Cold:             []Constraint{{Type: Constraint_REQUIRED, Value: "hdd"}},
+					ColdAfterSeconds: 3600,
+				},
+			},
+			expectSpanConfig: roachpb.SpanConfig{
+				RangeMinBytes: 100000,
+				RangeMaxBytes: 200000,
+				GCPolicy: roachpb.GCPolicy{
+					TTLSeconds: 2400,
+				},
+				NumReplicas: 3,
+				Tiering: &roachpb.TieringPolicy{
+					Hot:              []roachpb.Constraint{{Type: roachpb.Constraint_REQUIRED, Value: "ssd"}},
+					Cold:             []roachpb.Constraint{{Type: roachpb.Constraint_REQUIRED, Value: "hdd"}},
+					ColdAfterSeconds: 3600,
+				},
+			},
+		},
+		{
+			// An empty tiering policy disables tiering.
+			zoneConfig: ZoneConfig{
+				RangeMinBytes: proto.Int64(100000),
+				RangeMaxBytes: proto.Int64(200000),
+				GC: &GCPolicy{
+					TTLSeconds: 2400,
+				},
+				NumReplicas: proto.Int32(3),
+				Tiering:     &TieringPolicy{},
+			},
+			expectSpanConfig: roachpb.SpanConfig{
+				RangeMinBytes: 100000,
+				RangeMaxBytes: 200000,
+				GCPolicy: roachpb.GCPolicy{
+					TTLSeconds: 2400,
+				},
+				NumReplicas: 3,
+			},
+		},
 	}
 	for _, tc := range testCases {
 		spanConfig, err := tc.zoneConfig.toSpanConfig()
@@ -14,7 +14,9 @@ import (
 	"fmt"
 	"runtime/debug"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/gogo/protobuf/proto"
@@ -190,6 +192,105 @@ func (c *ConstraintsList) UnmarshalYAML(unmarshal func(interface{}) error) error
 	return nil
 }
 
+// marshalableTieringPolicy is the YAML representation of a TieringPolicy:
+//
+//	{hot: '+ssd', cold: '+hdd', cold_after: 30d}
+//
+// Each tier is a comma-separated list of constraints, and cold_after is either
+// a number of days or a Go duration string. An empty policy disables tiering.
+type marshalableTieringPolicy struct {
+	Hot       string `yaml:"hot,omitempty"`
+	Cold      string `yaml:"cold,omitempty"`
+	ColdAfter string `yaml:"cold_after,omitempty"`
+}
+
+var _ yaml.Marshaler = TieringPolicy{}
+var _ yaml.Unmarshaler = &TieringPolicy{}
+
+// MarshalYAML implements yaml.Marshaler.
+func (p TieringPolicy) MarshalYAML() (interface{}, error) {
+	m := marshalableTieringPolicy{
+		Hot:  constraintsToString(p.Hot),
+		Cold: constraintsToString(p.Cold),
+	}
+	if p.ColdAfterSeconds != 0 {
+		m.ColdAfter = formatColdAfter(p.ColdAfterSeconds)
+	}
+	return m, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (p *TieringPolicy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var m marshalableTieringPolicy
+	if err := unmarshal(&m); err != nil {
+		return err
+	}
+	var res TieringPolicy
+	var err error
+	if res.Hot, err = constraintsFromString(m.Hot); err != nil {
+		return err
+	}
+	if res.Cold, err = constraintsFromString(m.Cold); err != nil {
+		return err
+	}
+	if m.ColdAfter != "" {
+		if res.ColdAfterSeconds, err = parseColdAfter(m.ColdAfter); err != nil {
+			return err
+		}
+	}
+	*p = res
+	return nil
+}
+
+func constraintsToString(constraints []Constraint) string {
+	short := make([]string, len(constraints))
+	for i, c := range constraints {
+		short[i] = c.String()
+	}
+	return strings.Join(short, ",")
+}
+
+func constraintsFromString(s string) ([]Constraint, error) {
+	if s == "" {
+		return nil, nil
+	}
+	shortConstraints := strings.Split(s, ",")
+	constraints := make([]Constraint, len(shortConstraints))
+	for i, short := range shortConstraints {
+		if err := constraints[i].FromString(strings.TrimSpace(short)); err != nil {
+			return nil, err
+		}
+	}
+	return constraints, nil
+}
+
+const secondsPerDay = 24 * 60 * 60
+
+// formatColdAfter formats a number of seconds as a number of days when it is
+// a whole number of days, and as a Go duration string otherwise.
+func formatColdAfter(seconds int64) string {
+	if seconds%secondsPerDay == 0 {
+		return fmt.Sprintf("%dd", seconds/secondsPerDay)
+	}
+	return (time.Duration(seconds) * time.Second).String()
+}
+
+// parseColdAfter is the inverse of formatColdAfter.
+func parseColdAfter(s string) (int64, error) {
+	if strings.HasSuffix(s, "d") {
+		n, err := strconv.ParseInt(strings.TrimSuffix(s, "d"), 10, 64)
+		if err != nil {
+			return 0, errors.Newf("invalid cold_after %q: expected a number of days", s)
+		}
+		return n * secondsPerDay, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid cold_after %q", s)
+	}
+	return int64(d / time.Second), nil
+}
+
 // marshalableZoneConfig should be kept up-to-date with the real,
 // auto-generated ZoneConfig type, but with []Constraints changed to
 // ConstraintsList for backwards-compatible yaml marshaling and unmarshaling.
@@ -209,6 +310,7 @@ type marshalableZoneConfig struct {
 	VoterConstraints             ConstraintsList   `json:"voter_constraints" yaml:"voter_constraints,flow"`
 	LeasePreferences             []LeasePreference `json:"lease_preferences" yaml:"lease_preferences,flow"`
 	ExperimentalLeasePreferences []LeasePreference `json:"experimental_lease_preferences" yaml:"experimental_lease_preferences,flow,omitempty"`
+	Tiering                      *TieringPolicy    `json:"tiering" yaml:"tiering,flow,omitempty"`
 	Subzones                     []Subzone         `json:"subzones" yaml:"-"`
 	SubzoneSpans                 []SubzoneSpan     `json:"subzone_spans" yaml:"-"`
 }
@@ -245,6 +347,10 @@ func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
 	}
 	// We intentionally do not round-trip ExperimentalLeasePreferences. We never
 	// want to return yaml containing it.
+	if c.Tiering != nil {
+		tempTiering := *c.Tiering
+		m.Tiering = &tempTiering
+	}
 	m.Subzones = c.Subzones
 	m.SubzoneSpans = c.SubzoneSpans
 	return m
@@ -293,6 +399,10 @@ func zoneConfigFromMarshalable(m marshalableZoneConfig, c ZoneConfig) ZoneConfig
 	if m.LeasePreferences != nil || m.ExperimentalLeasePreferences != nil {
 		c.InheritedLeasePreferences = false
 	}
+	if m.Tiering != nil {
+		tempTiering := *m.Tiering
+		c.Tiering = &tempTiering
+	}
 	c.Subzones = m.Subzones
 	c.SubzoneSpans = m.SubzoneSpans
 	return c
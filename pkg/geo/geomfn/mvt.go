@@ -0,0 +1,418 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geomfn
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+	"github.com/twpayne/go-geom"
+)
+
+// This file implements the encoding of Mapbox Vector Tiles, as described by
+// version 2.1 of the specification at
+// https://github.com/mapbox/vector-tile-spec/tree/master/2.1.
+//
+// The protobuf messages are small enough that they are written by hand rather
+// than generated from the vector_tile.proto definition.
+
+// MVTVersion is the version of the vector tile specification implemented by
+// MVTLayer.
+const MVTVersion = 2
+
+// Field numbers of the vector_tile.proto messages.
+const (
+	mvtTileLayers = 3
+
+	mvtLayerName     = 1
+	mvtLayerFeatures = 2
+	mvtLayerKeys     = 3
+	mvtLayerValues   = 4
+	mvtLayerExtent   = 5
+	mvtLayerVersion  = 15
+
+	mvtFeatureID       = 1
+	mvtFeatureTags     = 2
+	mvtFeatureType     = 3
+	mvtFeatureGeometry = 4
+
+	mvtValueString = 1
+	mvtValueFloat  = 2
+	mvtValueDouble = 3
+	mvtValueUint   = 5
+	mvtValueSint   = 6
+	mvtValueBool   = 7
+)
+
+// Protobuf wire types used by the vector_tile.proto messages.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// mvtGeomType is the GeomType enum of a vector tile feature.
+type mvtGeomType uint64
+
+const (
+	mvtPoint      mvtGeomType = 1
+	mvtLineString mvtGeomType = 2
+	mvtPolygon    mvtGeomType = 3
+)
+
+// Geometry commands of a vector tile feature.
+const (
+	mvtCmdMoveTo    = 1
+	mvtCmdLineTo    = 2
+	mvtCmdClosePath = 7
+)
+
+// MVTValue is the value of a feature attribute in a vector tile.
+type MVTValue struct {
+	field uint8
+	s     string
+	f     float64
+	i     int64
+	b     bool
+}
+
+// MakeMVTStringValue returns a string MVTValue.
+func MakeMVTStringValue(s string) MVTValue {
+	return MVTValue{field: mvtValueString, s: s}
+}
+
+// MakeMVTFloatValue returns a single precision floating point MVTValue.
+func MakeMVTFloatValue(f float32) MVTValue {
+	return MVTValue{field: mvtValueFloat, f: float64(f)}
+}
+
+// MakeMVTDoubleValue returns a double precision floating point MVTValue.
+func MakeMVTDoubleValue(f float64) MVTValue {
+	return MVTValue{field: mvtValueDouble, f: f}
+}
+
+// MakeMVTIntValue returns an integer MVTValue. Non-negative values are encoded
+// as uint_value, and negative values as sint_value, which is what PostGIS does.
+func MakeMVTIntValue(i int64) MVTValue {
+	if i >= 0 {
+		return MVTValue{field: mvtValueUint, i: i}
+	}
+	return MVTValue{field: mvtValueSint, i: i}
+}
+
+// MakeMVTBoolValue returns a boolean MVTValue.
+func MakeMVTBoolValue(b bool) MVTValue {
+	return MVTValue{field: mvtValueBool, b: b}
+}
+
+// appendEncoded appends the encoding of the value as a Value message.
+func (v MVTValue) appendEncoded(buf []byte) []byte {
+	switch v.field {
+	case mvtValueString:
+		return appendBytesField(buf, mvtValueString, []byte(v.s))
+	case mvtValueFloat:
+		buf = appendTag(buf, mvtValueFloat, wireFixed32)
+		return binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(v.f)))
+	case mvtValueDouble:
+		buf = appendTag(buf, mvtValueDouble, wireFixed64)
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v.f))
+	case mvtValueUint:
+		return appendVarintField(buf, mvtValueUint, uint64(v.i))
+	case mvtValueSint:
+		return appendVarintField(buf, mvtValueSint, zigzag(v.i))
+	case mvtValueBool:
+		var b uint64
+		if v.b {
+			b = 1
+		}
+		return appendVarintField(buf, mvtValueBool, b)
+	}
+	return buf
+}
+
+// MVTAttribute is a key/value pair attached to a vector tile feature.
+type MVTAttribute struct {
+	Key   string
+	Value MVTValue
+}
+
+// MVTLayer accumulates the features of a single vector tile layer. Keys and
+// values are deduplicated across the features of the layer, as required by
+// the specification.
+type MVTLayer struct {
+	name   string
+	extent uint64
+
+	keys     []string
+	keyIdx   map[string]uint64
+	values   []MVTValue
+	valueIdx map[MVTValue]uint64
+
+	// features contains the encoded Feature messages, each prefixed with the
+	// tag and length of the features field of the Layer message.
+	features []byte
+
+	// scratch buffers reused across calls to AddFeature.
+	feature  []byte
+	tags     []byte
+	geometry []byte
+}
+
+// NewMVTLayer returns an empty MVTLayer with the given name and extent.
+func NewMVTLayer(name string, extent int) (*MVTLayer, error) {
+	if extent <= 0 {
+		return nil, pgerror.New(pgcode.InvalidParameterValue, "extent must be greater than 0")
+	}
+	return &MVTLayer{
+		name:     name,
+		extent:   uint64(extent),
+		keyIdx:   make(map[string]uint64),
+		valueIdx: make(map[MVTValue]uint64),
+	}, nil
+}
+
+// Size returns an estimate of the memory used by the layer, in bytes.
+func (l *MVTLayer) Size() int64 {
+	size := int64(len(l.name) + cap(l.features) + cap(l.feature) + cap(l.tags) + cap(l.geometry))
+	for _, k := range l.keys {
+		size += int64(len(k)) * 2
+	}
+	for _, v := range l.values {
+		size += int64(len(v.s)) * 2
+	}
+	return size
+}
+
+// AddFeature appends a feature with the given geometry, id and attributes to
+// the layer. The geometry is expected to already be in the coordinate space of
+// the tile, for example as returned by AsMVTGeometry. Empty geometries are
+// skipped. If id is nil, the feature is encoded without an id.
+func (l *MVTLayer) AddFeature(g geo.Geometry, id *uint64, attrs []MVTAttribute) error {
+	if g.Empty() {
+		return nil
+	}
+	gt, err := g.AsGeomT()
+	if err != nil {
+		return errors.Wrap(err, "failed to convert geometry to geom.T")
+	}
+	if _, ok := gt.(*geom.GeometryCollection); ok {
+		basicType, err := getBasicType(gt)
+		if err != nil {
+			return err
+		}
+		if g, err = convertToBasicType(g, basicType); err != nil {
+			return err
+		}
+		if g.Empty() {
+			return nil
+		}
+		if gt, err = g.AsGeomT(); err != nil {
+			return errors.Wrap(err, "failed to convert geometry to geom.T")
+		}
+	}
+	typ, ok, err := l.encodeGeometry(gt)
+	if err != nil || !ok {
+		return err
+	}
+
+	l.tags = l.tags[:0]
+	for _, attr := range attrs {
+		l.tags = binary.AppendUvarint(l.tags, l.keyIndex(attr.Key))
+		l.tags = binary.AppendUvarint(l.tags, l.valueIndex(attr.Value))
+	}
+
+	l.feature = l.feature[:0]
+	if id != nil {
+		l.feature = appendVarintField(l.feature, mvtFeatureID, *id)
+	}
+	if len(l.tags) > 0 {
+		l.feature = appendBytesField(l.feature, mvtFeatureTags, l.tags)
+	}
+	l.feature = appendVarintField(l.feature, mvtFeatureType, uint64(typ))
+	l.feature = appendBytesField(l.feature, mvtFeatureGeometry, l.geometry)
+	l.features = appendBytesField(l.features, mvtLayerFeatures, l.feature)
+	return nil
+}
+
+// Encode returns the encoding of a Tile message containing the layer.
+// Multiple layers can be combined into a single tile by concatenating their
+// encodings.
+func (l *MVTLayer) Encode() []byte {
+	// The fields are written in field number order, which matches the output
+	// of PostGIS.
+	var layer []byte
+	layer = appendBytesField(layer, mvtLayerName, []byte(l.name))
+	layer = append(layer, l.features...)
+	for _, k := range l.keys {
+		layer = appendBytesField(layer, mvtLayerKeys, []byte(k))
+	}
+	var value []byte
+	for _, v := range l.values {
+		value = v.appendEncoded(value[:0])
+		layer = appendBytesField(layer, mvtLayerValues, value)
+	}
+	layer = appendVarintField(layer, mvtLayerExtent, l.extent)
+	layer = appendVarintField(layer, mvtLayerVersion, MVTVersion)
+	return appendBytesField(nil, mvtTileLayers, layer)
+}
+
+func (l *MVTLayer) keyIndex(k string) uint64 {
+	idx, ok := l.keyIdx[k]
+	if !ok {
+		idx = uint64(len(l.keys))
+		l.keys = append(l.keys, k)
+		l.keyIdx[k] = idx
+	}
+	return idx
+}
+
+func (l *MVTLayer) valueIndex(v MVTValue) uint64 {
+	idx, ok := l.valueIdx[v]
+	if !ok {
+		idx = uint64(len(l.values))
+		l.values = append(l.values, v)
+		l.valueIdx[v] = idx
+	}
+	return idx
+}
+
+// mvtGeometryEncoder writes the command integers of a feature geometry. The
+// cursor position carries over between the parts of a multi geometry.
+type mvtGeometryEncoder struct {
+	buf    []byte
+	cx, cy int64
+}
+
+func (e *mvtGeometryEncoder) command(id uint64, count int) {
+	e.buf = binary.AppendUvarint(e.buf, (id&0x7)|(uint64(count)<<3))
+}
+
+func (e *mvtGeometryEncoder) point(coords []float64) {
+	x, y := int64(math.Round(coords[0])), int64(math.Round(coords[1]))
+	e.buf = binary.AppendUvarint(e.buf, zigzag(x-e.cx))
+	e.buf = binary.AppendUvarint(e.buf, zigzag(y-e.cy))
+	e.cx, e.cy = x, y
+}
+
+// points writes MoveTo for the first point followed by LineTo for the rest.
+func (e *mvtGeometryEncoder) points(flatCoords []float64, stride int) {
+	e.command(mvtCmdMoveTo, 1)
+	e.point(flatCoords)
+	e.command(mvtCmdLineTo, len(flatCoords)/stride-1)
+	for i := stride; i < len(flatCoords); i += stride {
+		e.point(flatCoords[i:])
+	}
+}
+
+func (e *mvtGeometryEncoder) lineString(g *geom.LineString) {
+	if g.NumCoords() < 2 {
+		return
+	}
+	e.points(g.FlatCoords(), g.Stride())
+}
+
+func (e *mvtGeometryEncoder) polygon(g *geom.Polygon) {
+	for i := 0; i < g.NumLinearRings(); i++ {
+		ring := g.LinearRing(i)
+		if ring.NumCoords() < 4 {
+			continue
+		}
+		// The closing point of the ring is implied by ClosePath.
+		flatCoords := ring.FlatCoords()
+		e.points(flatCoords[:len(flatCoords)-ring.Stride()], ring.Stride())
+		e.command(mvtCmdClosePath, 1)
+	}
+}
+
+// encodeGeometry writes the geometry commands of gt into l.geometry, and
+// returns the type of the feature. It returns false if there was nothing to
+// encode.
+func (l *MVTLayer) encodeGeometry(gt geom.T) (mvtGeomType, bool, error) {
+	e := mvtGeometryEncoder{buf: l.geometry[:0]}
+	var typ mvtGeomType
+	switch gt := gt.(type) {
+	case *geom.Point:
+		typ = mvtPoint
+		e.command(mvtCmdMoveTo, 1)
+		e.point(gt.FlatCoords())
+	case *geom.MultiPoint:
+		typ = mvtPoint
+		var n int
+		for i := 0; i < gt.NumPoints(); i++ {
+			if !gt.Point(i).Empty() {
+				n++
+			}
+		}
+		if n > 0 {
+			e.command(mvtCmdMoveTo, n)
+			for i := 0; i < gt.NumPoints(); i++ {
+				if p := gt.Point(i); !p.Empty() {
+					e.point(p.FlatCoords())
+				}
+			}
+		}
+	case *geom.LineString:
+		typ = mvtLineString
+		e.lineString(gt)
+	case *geom.MultiLineString:
+		typ = mvtLineString
+		for i := 0; i < gt.NumLineStrings(); i++ {
+			e.lineString(gt.LineString(i))
+		}
+	case *geom.Polygon, *geom.MultiPolygon:
+		typ = mvtPolygon
+		// Exterior rings must have a positive area and interior rings a
+		// negative area, i.e. they are clockwise and counter-clockwise
+		// respectively when drawn with the y axis pointing down. This is the
+		// orientation AsMVTGeometry produces, so this is usually a no-op.
+		if err := forcePolygonOrientation(gt, OrientationCCW); err != nil {
+			return 0, false, err
+		}
+		switch gt := gt.(type) {
+		case *geom.Polygon:
+			e.polygon(gt)
+		case *geom.MultiPolygon:
+			for i := 0; i < gt.NumPolygons(); i++ {
+				e.polygon(gt.Polygon(i))
+			}
+		}
+	default:
+		return 0, false, geom.ErrUnsupportedType{Value: gt}
+	}
+	l.geometry = e.buf
+	return typ, len(e.buf) > 0, nil
+}
+
+// zigzag returns the zigzag encoding of n, which is used for both geometry
+// parameters and sint_value.
+func zigzag(n int64) uint64 {
+	return uint64((n << 1) ^ (n >> 63))
+}
+
+func appendTag(buf []byte, field uint64, wireType uint64) []byte {
+	return binary.AppendUvarint(buf, field<<3|wireType)
+}
+
+func appendVarintField(buf []byte, field uint64, v uint64) []byte {
+	buf = appendTag(buf, field, wireVarint)
+	return binary.AppendUvarint(buf, v)
+}
+
+func appendBytesField(buf []byte, field uint64, b []byte) []byte {
+	buf = appendTag(buf, field, wireBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
@@ -0,0 +1,184 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package geomfn
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMVTLayerGeometry(t *testing.T) {
+	// The expected commands are the examples from section 4.3.5 of the
+	// specification, except where noted.
+	testCases := []struct {
+		wkt          string
+		expectedType mvtGeomType
+		expected     []uint64
+	}{
+		{
+			wkt:          "POINT(25 17)",
+			expectedType: mvtPoint,
+			expected:     []uint64{9, 50, 34},
+		},
+		{
+			wkt:          "MULTIPOINT(5 7, 3 2)",
+			expectedType: mvtPoint,
+			expected:     []uint64{17, 10, 14, 3, 9},
+		},
+		{
+			wkt:          "LINESTRING(2 2, 2 10, 10 10)",
+			expectedType: mvtLineString,
+			expected:     []uint64{9, 4, 4, 18, 0, 16, 16, 0},
+		},
+		{
+			wkt:          "MULTILINESTRING((2 2, 2 10, 10 10), (1 1, 3 5))",
+			expectedType: mvtLineString,
+			expected:     []uint64{9, 4, 4, 18, 0, 16, 16, 0, 9, 17, 17, 10, 4, 8},
+		},
+		{
+			wkt:          "POLYGON((3 6, 8 12, 20 34, 3 6))",
+			expectedType: mvtPolygon,
+			expected:     []uint64{9, 6, 12, 18, 10, 12, 24, 44, 15},
+		},
+		{
+			// The same polygon with the wrong orientation is reversed.
+			wkt:          "POLYGON((3 6, 20 34, 8 12, 3 6))",
+			expectedType: mvtPolygon,
+			expected:     []uint64{9, 6, 12, 18, 10, 12, 24, 44, 15},
+		},
+		{
+			wkt:          "MULTIPOLYGON(((0 0, 10 0, 10 10, 0 10, 0 0)), ((11 11, 20 11, 20 20, 11 20, 11 11), (13 13, 13 17, 17 17, 17 13, 13 13)))",
+			expectedType: mvtPolygon,
+			expected: []uint64{
+				9, 0, 0, 26, 20, 0, 0, 20, 19, 0, 15,
+				9, 22, 2, 26, 18, 0, 0, 18, 17, 0, 15,
+				9, 4, 13, 26, 0, 8, 8, 0, 0, 7, 15,
+			},
+		},
+		{
+			// Collections are reduced to their highest dimensional basic type.
+			wkt:          "GEOMETRYCOLLECTION(POINT(1 1), LINESTRING(2 2, 2 10, 10 10))",
+			expectedType: mvtLineString,
+			expected:     []uint64{9, 4, 4, 18, 0, 16, 16, 0},
+		},
+		{
+			// Coordinates are rounded to the integer grid.
+			wkt:          "POINT(24.6 17.4)",
+			expectedType: mvtPoint,
+			expected:     []uint64{9, 50, 34},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.wkt, func(t *testing.T) {
+			l, err := NewMVTLayer("test", 4096)
+			require.NoError(t, err)
+			require.NoError(t, l.AddFeature(geo.MustParseGeometry(tc.wkt), nil, nil))
+
+			// The layer contains a single feature, which only has the type and
+			// geometry fields.
+			var expected []byte
+			expected = appendVarintField(expected, mvtFeatureType, uint64(tc.expectedType))
+			var commands []byte
+			for _, c := range tc.expected {
+				commands = binary.AppendUvarint(commands, c)
+			}
+			expected = appendBytesField(expected, mvtFeatureGeometry, commands)
+			require.Equal(t, appendBytesField(nil, mvtLayerFeatures, expected), l.features)
+		})
+	}
+
+	t.Run("empty geometries are skipped", func(t *testing.T) {
+		l, err := NewMVTLayer("test", 4096)
+		require.NoError(t, err)
+		for _, wkt := range []string{
+			"POINT EMPTY",
+			"POLYGON EMPTY",
+			"GEOMETRYCOLLECTION EMPTY",
+		} {
+			require.NoError(t, l.AddFeature(geo.MustParseGeometry(wkt), nil, nil))
+		}
+		require.Empty(t, l.features)
+	})
+}
+
+func TestMVTLayerEncode(t *testing.T) {
+	l, err := NewMVTLayer("test", 4096)
+	require.NoError(t, err)
+	id := uint64(1)
+	require.NoError(t, l.AddFeature(
+		geo.MustParseGeometry("POINT(25 17)"),
+		&id,
+		[]MVTAttribute{{Key: "c1", Value: MakeMVTIntValue(1)}},
+	))
+	require.Equal(
+		t,
+		"1a22"+ // Tile.layers
+			"0a0474657374"+ // Layer.name
+			"120d"+ // Layer.features
+			"0801"+ // Feature.id
+			"12020000"+ // Feature.tags
+			"1801"+ // Feature.type
+			"2203093222"+ // Feature.geometry
+			"1a026331"+ // Layer.keys
+			"22022801"+ // Layer.values
+			"288020"+ // Layer.extent
+			"7802", // Layer.version
+		hex.EncodeToString(l.Encode()),
+	)
+
+	t.Run("keys and values are deduplicated", func(t *testing.T) {
+		l, err := NewMVTLayer("test", 4096)
+		require.NoError(t, err)
+		g := geo.MustParseGeometry("POINT(1 1)")
+		require.NoError(t, l.AddFeature(g, nil, []MVTAttribute{
+			{Key: "a", Value: MakeMVTStringValue("x")},
+			{Key: "b", Value: MakeMVTBoolValue(true)},
+		}))
+		require.NoError(t, l.AddFeature(g, nil, []MVTAttribute{
+			{Key: "b", Value: MakeMVTStringValue("x")},
+			{Key: "c", Value: MakeMVTDoubleValue(1.5)},
+			{Key: "d", Value: MakeMVTIntValue(-1)},
+		}))
+		require.Equal(t, []string{"a", "b", "c", "d"}, l.keys)
+		require.Equal(t, []MVTValue{
+			MakeMVTStringValue("x"),
+			MakeMVTBoolValue(true),
+			MakeMVTDoubleValue(1.5),
+			MakeMVTIntValue(-1),
+		}, l.values)
+	})
+
+	t.Run("values", func(t *testing.T) {
+		for _, tc := range []struct {
+			v        MVTValue
+			expected string
+		}{
+			{v: MakeMVTStringValue("ab"), expected: "0a026162"},
+			{v: MakeMVTFloatValue(1.5), expected: "150000c03f"},
+			{v: MakeMVTDoubleValue(1.5), expected: "19000000000000f83f"},
+			{v: MakeMVTIntValue(300), expected: "28ac02"},
+			{v: MakeMVTIntValue(-2), expected: "3003"},
+			{v: MakeMVTBoolValue(true), expected: "3801"},
+		} {
+			require.Equal(t, tc.expected, hex.EncodeToString(tc.v.appendEncoded(nil)))
+		}
+	})
+
+	t.Run("invalid extent", func(t *testing.T) {
+		_, err := NewMVTLayer("test", 0)
+		require.EqualError(t, err, "extent must be greater than 0")
+	})
+}
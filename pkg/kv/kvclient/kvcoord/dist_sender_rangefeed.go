@@ -88,6 +88,7 @@ type rangeFeedConfig struct {
 	useMuxRangeFeed bool
 	overSystemTable bool
 	withDiff        bool
+	filter          *kvpb.RangeFeedFilter
 	rangeObserver   func(ForEachRangeFn)
 
 	knobs struct {
@@ -137,6 +138,15 @@ func WithDiff() RangeFeedOption {
 	})
 }
 
+// WithFilter configures the rangefeed to ask the servers to only send value
+// events that match the provided filter. Servers that don't support filtering
+// send all events, so the filter does not replace filtering by the caller.
+func WithFilter(filter *kvpb.RangeFeedFilter) RangeFeedOption {
+	return optionFunc(func(c *rangeFeedConfig) {
+		c.filter = filter
+	})
+}
+
 // WithRangeObserver is called when the rangefeed starts with a function that
 // can be used to iterate over all the ranges.
 func WithRangeObserver(observer func(ForEachRangeFn)) RangeFeedOption {
@@ -730,14 +740,15 @@ func newTransportForRange(
 
 // makeRangeFeedRequest constructs kvpb.RangeFeedRequest for specified span and
 // rangeID. Request is constructed to watch event after specified timestamp, and
-// with optional diff.  If the request corresponds to a system range, request
-// receives higher admission priority.
+// with optional diff and filter.  If the request corresponds to a system range,
+// request receives higher admission priority.
 func makeRangeFeedRequest(
 	span roachpb.Span,
 	rangeID roachpb.RangeID,
 	isSystemRange bool,
 	startAfter hlc.Timestamp,
 	withDiff bool,
+	filter *kvpb.RangeFeedFilter,
 ) kvpb.RangeFeedRequest {
 	admissionPri := admissionpb.BulkNormalPri
 	if isSystemRange {
@@ -750,6 +761,7 @@ func makeRangeFeedRequest(
 			RangeID:   rangeID,
 		},
 		WithDiff: withDiff,
+		Filter:   filter,
 		AdmissionHeader: kvpb.AdmissionHeader{
 			// NB: AdmissionHeader is used only at the start of the range feed
 			// stream since the initial catch-up scan is expensive.
@@ -807,7 +819,8 @@ func (ds *DistSender) singleRangeFeed(
 		cancelFeed()
 	}()
 
-	args := makeRangeFeedRequest(span, desc.RangeID, cfg.overSystemTable, startAfter, cfg.withDiff)
+	args := makeRangeFeedRequest(
+		span, desc.RangeID, cfg.overSystemTable, startAfter, cfg.withDiff, cfg.filter)
 	transport, err := newTransportForRange(ctx, desc, ds)
 	if err != nil {
 		return args.Timestamp, err
@@ -39,6 +39,7 @@ type config struct {
 	useRowTimestampInInitialScan bool
 
 	withDiff             bool
+	filter               *kvpb.RangeFeedFilter
 	onUnrecoverableError OnUnrecoverableError
 	onCheckpoint         OnCheckpoint
 	onFrontierAdvance    OnFrontierAdvance
@@ -144,6 +145,17 @@ func WithDiff(withDiff bool) Option {
 	})
 }
 
+// WithFilter makes an option to push the provided filter down to the
+// rangefeed registrations on the servers, so that value events which don't
+// match it are not sent. The filter is not applied to the initial scan, and
+// servers may ignore it, so it is an optimization that does not replace any
+// filtering done by the callbacks.
+func WithFilter(filter *kvpb.RangeFeedFilter) Option {
+	return optionFunc(func(c *config) {
+		c.filter = filter
+	})
+}
+
 // WithRetry configures the retry options for the rangefeed.
 func WithRetry(options retry.Options) Option {
 	return optionFunc(func(c *config) {
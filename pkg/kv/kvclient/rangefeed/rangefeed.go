@@ -297,6 +297,9 @@ func (f *RangeFeed) run(ctx context.Context, frontier *span.Frontier) {
 	if f.withDiff {
 		rangefeedOpts = append(rangefeedOpts, kvcoord.WithDiff())
 	}
+	if f.filter != nil {
+		rangefeedOpts = append(rangefeedOpts, kvcoord.WithFilter(f.filter))
+	}
 
 	for i := 0; r.Next(); i++ {
 		ts := frontier.Frontier()
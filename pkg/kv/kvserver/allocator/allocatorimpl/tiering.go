@@ -0,0 +1,146 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package allocatorimpl
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+)
+
+// EnableStorageTiering controls whether the allocator honors the tiering
+// policies of span configs. When disabled, ranges are placed according to
+// their constraints alone.
+var EnableStorageTiering = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.allocator.storage_tiering.enabled",
+	"if enabled, ranges with a tiering policy are moved between the hot and "+
+		"cold stores of the policy based on how recently they were accessed",
+	true,
+)
+
+// StorageTier is the set of stores, as described by a tiering policy, that a
+// range's replicas should be placed on.
+type StorageTier int
+
+const (
+	// StorageTierUnknown indicates that there is not yet enough access history
+	// to tell whether a range is hot or cold. The range's placement is left
+	// unchanged.
+	StorageTierUnknown StorageTier = iota
+	// StorageTierHot indicates that the range was accessed recently and belongs
+	// on the hot stores.
+	StorageTierHot
+	// StorageTierCold indicates that the range was not accessed for at least
+	// the cold_after period of the policy and belongs on the cold stores.
+	StorageTierCold
+)
+
+func (t StorageTier) String() string {
+	switch t {
+	case StorageTierHot:
+		return "hot"
+	case StorageTierCold:
+		return "cold"
+	default:
+		return "unknown"
+	}
+}
+
+// RangeStorageTier returns the tier a range with the given usage belongs to
+// under the tiering policy.
+//
+// A range is cold once it has not been read or written for the cold_after
+// period of the policy, and hot otherwise. Access tracking restarts whenever
+// the lease moves, so a range for which no access has been observed is only
+// known to be cold once it has been tracked for the full period. Until then
+// its tier is unknown, which avoids promoting cold ranges back to the hot
+// stores every time their lease is transferred.
+func RangeStorageTier(
+	policy *roachpb.TieringPolicy, usage allocator.RangeUsageInfo, now time.Time,
+) StorageTier {
+	coldAfter := policy.ColdAfter()
+	if !usage.LastAccess.IsZero() {
+		if now.Sub(usage.LastAccess) >= coldAfter {
+			return StorageTierCold
+		}
+		return StorageTierHot
+	}
+	if !usage.AccessTrackedSince.IsZero() && now.Sub(usage.AccessTrackedSince) >= coldAfter {
+		return StorageTierCold
+	}
+	return StorageTierUnknown
+}
+
+// TieredSpanConfig returns the span config the allocator should use to place
+// the replicas of a range. If the config has a tiering policy, the constraints
+// of the range's current tier are added to every replica of the range so that
+// the range is moved onto the hot or cold stores as its usage changes. The
+// passed in config is not modified. If the config has no tiering policy, or
+// the range's tier is unknown, the config is returned as is.
+func (a *Allocator) TieredSpanConfig(
+	conf *roachpb.SpanConfig, usage allocator.RangeUsageInfo, now time.Time,
+) *roachpb.SpanConfig {
+	if conf.Tiering == nil || !EnableStorageTiering.Get(&a.st.SV) {
+		return conf
+	}
+	var tierConstraints []roachpb.Constraint
+	switch RangeStorageTier(conf.Tiering, usage, now) {
+	case StorageTierHot:
+		tierConstraints = conf.Tiering.Hot
+	case StorageTierCold:
+		tierConstraints = conf.Tiering.Cold
+	default:
+		return conf
+	}
+	tiered := *conf
+	tiered.Constraints = withTierConstraints(conf.Constraints, tierConstraints, conf.NumReplicas)
+	if len(conf.VoterConstraints) > 0 {
+		tiered.VoterConstraints = withTierConstraints(
+			conf.VoterConstraints, tierConstraints, conf.GetNumVoters())
+	}
+	return &tiered
+}
+
+// withTierConstraints returns a copy of the conjunctions with the tier's
+// constraints added to each of them. If the conjunctions only constrain some
+// of the numReplicas replicas, a conjunction constraining the remaining
+// replicas to the tier is appended.
+func withTierConstraints(
+	conjunctions []roachpb.ConstraintsConjunction,
+	tierConstraints []roachpb.Constraint,
+	numReplicas int32,
+) []roachpb.ConstraintsConjunction {
+	if len(conjunctions) == 0 {
+		return []roachpb.ConstraintsConjunction{{Constraints: tierConstraints}}
+	}
+	var constrained int32
+	ret := make([]roachpb.ConstraintsConjunction, 0, len(conjunctions)+1)
+	for _, conj := range conjunctions {
+		constraints := make([]roachpb.Constraint, 0, len(conj.Constraints)+len(tierConstraints))
+		constraints = append(constraints, conj.Constraints...)
+		constraints = append(constraints, tierConstraints...)
+		ret = append(ret, roachpb.ConstraintsConjunction{
+			NumReplicas: conj.NumReplicas,
+			Constraints: constraints,
+		})
+		constrained += conj.NumReplicas
+	}
+	if conjunctions[0].NumReplicas != 0 && constrained < numReplicas {
+		ret = append(ret, roachpb.ConstraintsConjunction{
+			NumReplicas: numReplicas - constrained,
+			Constraints: tierConstraints,
+		})
+	}
+	return ret
+}
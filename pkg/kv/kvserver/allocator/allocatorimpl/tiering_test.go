@@ -0,0 +1,186 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package allocatorimpl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils/gossiputil"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+var testTieringPolicy = &roachpb.TieringPolicy{
+	Hot:              []roachpb.Constraint{{Type: roachpb.Constraint_REQUIRED, Value: "ssd"}},
+	Cold:             []roachpb.Constraint{{Type: roachpb.Constraint_REQUIRED, Value: "hdd"}},
+	ColdAfterSeconds: int64((24 * time.Hour).Seconds()),
+}
+
+func TestRangeStorageTier(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name     string
+		usage    allocator.RangeUsageInfo
+		expected StorageTier
+	}{
+		{
+			name:     "no history",
+			expected: StorageTierUnknown,
+		},
+		{
+			name: "recently accessed",
+			usage: allocator.RangeUsageInfo{
+				LastAccess:         now.Add(-time.Hour),
+				AccessTrackedSince: now.Add(-48 * time.Hour),
+			},
+			expected: StorageTierHot,
+		},
+		{
+			name: "not accessed for cold_after",
+			usage: allocator.RangeUsageInfo{
+				LastAccess:         now.Add(-24 * time.Hour),
+				AccessTrackedSince: now.Add(-48 * time.Hour),
+			},
+			expected: StorageTierCold,
+		},
+		{
+			name: "never accessed while tracked for cold_after",
+			usage: allocator.RangeUsageInfo{
+				AccessTrackedSince: now.Add(-25 * time.Hour),
+			},
+			expected: StorageTierCold,
+		},
+		{
+			name: "never accessed but only tracked recently",
+			usage: allocator.RangeUsageInfo{
+				AccessTrackedSince: now.Add(-time.Hour),
+			},
+			expected: StorageTierUnknown,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, RangeStorageTier(testTieringPolicy, tc.usage, now))
+		})
+	}
+}
+
+func TestWithTierConstraints(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	hdd := testTieringPolicy.Cold
+	region := func(r string) roachpb.Constraint {
+		return roachpb.Constraint{Type: roachpb.Constraint_REQUIRED, Key: "region", Value: r}
+	}
+	for _, tc := range []struct {
+		name         string
+		conjunctions []roachpb.ConstraintsConjunction
+		numReplicas  int32
+		expected     string
+	}{
+		{
+			name:        "unconstrained",
+			numReplicas: 3,
+			expected:    "[+hdd]",
+		},
+		{
+			name: "all replicas",
+			conjunctions: []roachpb.ConstraintsConjunction{
+				{Constraints: []roachpb.Constraint{region("us-east1")}},
+			},
+			numReplicas: 3,
+			expected:    "[+region=us-east1,+hdd]",
+		},
+		{
+			name: "per replica",
+			conjunctions: []roachpb.ConstraintsConjunction{
+				{NumReplicas: 1, Constraints: []roachpb.Constraint{region("us-east1")}},
+				{NumReplicas: 2, Constraints: []roachpb.Constraint{region("us-west1")}},
+			},
+			numReplicas: 3,
+			expected:    "[+region=us-east1,+hdd:1 +region=us-west1,+hdd:2]",
+		},
+		{
+			name: "per replica with unconstrained remainder",
+			conjunctions: []roachpb.ConstraintsConjunction{
+				{NumReplicas: 1, Constraints: []roachpb.Constraint{region("us-east1")}},
+			},
+			numReplicas: 5,
+			expected:    "[+region=us-east1,+hdd:1 +hdd:4]",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			before := append([]roachpb.ConstraintsConjunction(nil), tc.conjunctions...)
+			tiered := withTierConstraints(tc.conjunctions, hdd, tc.numReplicas)
+			require.Equal(t, tc.expected, fmt.Sprint(tiered))
+			// The input is not modified.
+			require.Equal(t, before, tc.conjunctions)
+		})
+	}
+}
+
+// TestAllocatorTieredAllocation verifies that replicas of a range with a
+// tiering policy are allocated to the stores of the range's current tier.
+func TestAllocatorTieredAllocation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper, g, sp, a, _ := CreateTestAllocator(ctx, 10, false /* deterministic */)
+	defer stopper.Stop(ctx)
+	gossiputil.NewStoreGossiper(g).GossipStores(sameDCStores, t)
+
+	conf := &roachpb.SpanConfig{NumReplicas: 1, Tiering: testTieringPolicy}
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	hot := allocator.RangeUsageInfo{
+		LastAccess:         now.Add(-time.Minute),
+		AccessTrackedSince: now.Add(-time.Hour),
+	}
+	cold := allocator.RangeUsageInfo{
+		LastAccess:         now.Add(-48 * time.Hour),
+		AccessTrackedSince: now.Add(-72 * time.Hour),
+	}
+	unknown := allocator.RangeUsageInfo{AccessTrackedSince: now.Add(-time.Hour)}
+
+	// Ranges with unknown tiers are placed according to the untiered config.
+	require.Same(t, conf, a.TieredSpanConfig(conf, unknown, now))
+
+	for i := 0; i < 10; i++ {
+		target, _, err := a.AllocateVoter(
+			ctx, sp, a.TieredSpanConfig(conf, hot, now),
+			nil /* existingVoters */, nil /* existingNonVoters */, nil, /* replacing */
+			Dead,
+		)
+		require.NoError(t, err)
+		require.Contains(t, []roachpb.StoreID{1, 2}, target.StoreID)
+
+		target, _, err = a.AllocateVoter(
+			ctx, sp, a.TieredSpanConfig(conf, cold, now),
+			nil /* existingVoters */, nil /* existingNonVoters */, nil, /* replacing */
+			Dead,
+		)
+		require.NoError(t, err)
+		require.Contains(t, []roachpb.StoreID{3, 4}, target.StoreID)
+	}
+
+	// When tiering is disabled, the tiering policy is ignored.
+	EnableStorageTiering.Override(ctx, &a.st.SV, false)
+	require.Same(t, conf, a.TieredSpanConfig(conf, cold, now))
+}
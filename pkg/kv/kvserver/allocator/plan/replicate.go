@@ -148,6 +148,7 @@ func (rp ReplicaPlanner) ShouldPlanChange(
 	conf *roachpb.SpanConfig,
 	canTransferLeaseFrom CanTransferLeaseFrom,
 ) (shouldPlanChange bool, priority float64) {
+	conf = rp.allocator.TieredSpanConfig(conf, repl.RangeUsageInfo(), now.ToTimestamp().GoTime())
 
 	log.KvDistribution.VEventf(ctx, 6,
 		"computing range action desc=%s config=%s",
@@ -252,6 +253,7 @@ func (rp ReplicaPlanner) PlanOneChange(
 		Op:      AllocationNoop{},
 		Replica: repl,
 	}
+	conf = rp.allocator.TieredSpanConfig(conf, repl.RangeUsageInfo(), rp.storePool.Clock().PhysicalTime())
 	log.KvDistribution.VEventf(ctx, 6,
 		"planning range change desc=%s config=%s",
 		desc, conf.String())
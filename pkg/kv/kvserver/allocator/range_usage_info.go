@@ -29,6 +29,10 @@ type RangeUsageInfo struct {
 	RequestsPerSecond        float64
 	RaftCPUNanosPerSecond    float64
 	RequestLocality          *RangeRequestLocalityInfo
+	// LastAccess is the last time a key in the range was read or written, or
+	// zero if no access has been observed since AccessTrackedSince.
+	LastAccess         time.Time
+	AccessTrackedSince time.Time
 }
 
 // RangeRequestLocalityInfo is the same as PerLocalityCounts and is used for
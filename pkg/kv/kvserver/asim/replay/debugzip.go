@@ -0,0 +1,197 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package replay
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/oserror"
+)
+
+// StoreLayout describes a store of a recorded cluster.
+type StoreLayout struct {
+	NodeID   roachpb.NodeID
+	StoreID  roachpb.StoreID
+	Locality roachpb.Locality
+	// Capacity is the disk capacity of the store in bytes, or 0 if unknown.
+	Capacity int64
+}
+
+// RangeLayout describes a range of a recorded cluster.
+type RangeLayout struct {
+	Desc        roachpb.RangeDescriptor
+	Leaseholder roachpb.StoreID
+	// Size is the logical size of the range in bytes.
+	Size int64
+}
+
+// Layout is the store, range and replica layout of a recorded cluster.
+type Layout struct {
+	// Stores holds the stores of the cluster, ordered by node and store ID.
+	Stores []StoreLayout
+	// Ranges holds the ranges of the cluster, ordered by start key.
+	Ranges []RangeLayout
+}
+
+// nodesJSON mirrors the JSON encoding of serverpb.NodesResponse, as written to
+// nodes.json in a debug zip.
+type nodesJSON struct {
+	Nodes []struct {
+		Desc struct {
+			NodeID   roachpb.NodeID   `json:"node_id"`
+			Locality roachpb.Locality `json:"locality"`
+		} `json:"desc"`
+		StoreStatuses []struct {
+			Desc struct {
+				StoreID  roachpb.StoreID       `json:"store_id"`
+				Capacity roachpb.StoreCapacity `json:"capacity"`
+			} `json:"desc"`
+		} `json:"store_statuses"`
+	} `json:"nodes"`
+}
+
+// rangeJSON mirrors the JSON encoding of serverpb.RangeInfo, as written to
+// nodes/<node_id>/ranges.json in a debug zip.
+type rangeJSON struct {
+	State struct {
+		State struct {
+			Desc  *roachpb.RangeDescriptor `json:"desc"`
+			Lease *roachpb.Lease           `json:"lease"`
+			Stats *enginepb.MVCCStats      `json:"stats"`
+		} `json:"state"`
+	} `json:"state"`
+}
+
+// ReadDebugZip reads the layout of a cluster from a debug zip created by
+// `cockroach debug zip`, which may be given as either the zip file or the
+// directory it was extracted to. The debug zip must have been created with
+// range information included.
+func ReadDebugZip(zipPath string) (*Layout, error) {
+	info, err := os.Stat(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	var fsys fs.FS
+	if info.IsDir() {
+		fsys = os.DirFS(zipPath)
+	} else {
+		r, err := zip.OpenReader(zipPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "opening debug zip %s", zipPath)
+		}
+		defer r.Close()
+		fsys = r
+	}
+	layout, err := readDebugZip(fsys)
+	return layout, errors.Wrapf(err, "reading debug zip %s", zipPath)
+}
+
+func readDebugZip(fsys fs.FS) (*Layout, error) {
+	// The files are within a debug directory, unless the path given was the
+	// debug directory itself.
+	dir := "debug"
+	if _, err := fs.Stat(fsys, path.Join(dir, "nodes.json")); oserror.IsNotExist(err) {
+		dir = "."
+	}
+
+	var nodes nodesJSON
+	if err := readJSON(fsys, path.Join(dir, "nodes.json"), &nodes); err != nil {
+		return nil, err
+	}
+	layout := &Layout{}
+	for _, n := range nodes.Nodes {
+		for _, ss := range n.StoreStatuses {
+			layout.Stores = append(layout.Stores, StoreLayout{
+				NodeID:   n.Desc.NodeID,
+				StoreID:  ss.Desc.StoreID,
+				Locality: n.Desc.Locality,
+				Capacity: ss.Desc.Capacity.Capacity,
+			})
+		}
+	}
+	if len(layout.Stores) == 0 {
+		return nil, errors.New("no stores found in nodes.json")
+	}
+	sort.Slice(layout.Stores, func(i, j int) bool {
+		a, b := layout.Stores[i], layout.Stores[j]
+		if a.NodeID != b.NodeID {
+			return a.NodeID < b.NodeID
+		}
+		return a.StoreID < b.StoreID
+	})
+
+	// Every replica of a range reports the range, take the most recent
+	// descriptor.
+	rangeFiles, err := fs.Glob(fsys, path.Join(dir, "nodes", "*", "ranges.json"))
+	if err != nil {
+		return nil, err
+	}
+	ranges := make(map[roachpb.RangeID]RangeLayout)
+	for _, file := range rangeFiles {
+		var infos []rangeJSON
+		if err := readJSON(fsys, file, &infos); err != nil {
+			return nil, err
+		}
+		for _, info := range infos {
+			rs := info.State.State
+			if rs.Desc == nil {
+				continue
+			}
+			if existing, ok := ranges[rs.Desc.RangeID]; ok && existing.Desc.Generation >= rs.Desc.Generation {
+				continue
+			}
+			rng := RangeLayout{Desc: *rs.Desc}
+			if rs.Lease != nil {
+				rng.Leaseholder = rs.Lease.Replica.StoreID
+			}
+			if rs.Stats != nil {
+				rng.Size = rs.Stats.Total()
+			}
+			ranges[rs.Desc.RangeID] = rng
+		}
+	}
+	if len(ranges) == 0 {
+		return nil, errors.New("no ranges found, the debug zip must include range information")
+	}
+	sorted := make([]RangeLayout, 0, len(ranges))
+	for _, rng := range ranges {
+		sorted = append(sorted, rng)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Desc.StartKey.Less(sorted[j].Desc.StartKey)
+	})
+	// A stale replica of a range which has since been merged or split may
+	// report a descriptor that overlaps other ranges, skip these.
+	for _, rng := range sorted {
+		if n := len(layout.Ranges); n > 0 && rng.Desc.StartKey.Less(layout.Ranges[n-1].Desc.EndKey) {
+			continue
+		}
+		layout.Ranges = append(layout.Ranges, rng)
+	}
+	return layout, nil
+}
+
+func readJSON(fsys fs.FS, name string, v interface{}) error {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return errors.Wrapf(json.NewDecoder(f).Decode(v), "parsing %s", name)
+}
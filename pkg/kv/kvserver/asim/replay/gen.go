@@ -0,0 +1,221 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package replay
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/config"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/gen"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/state"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/workload"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
+
+// Load implements the gen.LoadGen interface, replaying the recorded load.
+type Load struct {
+	Replay *Replay
+}
+
+var _ gen.LoadGen = Load{}
+
+func (l Load) String() string {
+	return fmt.Sprintf("replayed load with samples=%d, duration=%s", len(l.Replay.samples), l.Replay.duration)
+}
+
+// Generate returns a workload generator which replays the recorded load,
+// starting at the start time of the simulation. The recorded load of a span is
+// spread over random keys within the span, using the provided seed.
+func (l Load) Generate(seed int64, settings *config.SimulationSettings) []workload.Generator {
+	return []workload.Generator{
+		workload.NewReplayGenerator(settings.StartTime, seed, l.Replay.samples),
+	}
+}
+
+// Cluster implements the gen.ClusterGen interface, creating the nodes and
+// stores of the recorded layout.
+type Cluster struct {
+	Replay *Replay
+}
+
+var _ gen.ClusterGen = Cluster{}
+
+func (c Cluster) String() string {
+	nodes := 0
+	for i, s := range c.layout().Stores {
+		if i == 0 || s.NodeID != c.layout().Stores[i-1].NodeID {
+			nodes++
+		}
+	}
+	return fmt.Sprintf("replayed cluster with nodes=%d, stores=%d", nodes, len(c.layout().Stores))
+}
+
+// Generate returns a new simulator state containing a node, with the recorded
+// locality, for every recorded node and a store for each of the recorded
+// stores on the node. Nodes and stores are assigned IDs by the simulator in
+// the order of the recorded node and store IDs. There is no randomness in
+// this cluster generation.
+func (c Cluster) Generate(seed int64, settings *config.SimulationSettings) state.State {
+	s := state.NewState(settings)
+	var node state.Node
+	for i, sl := range c.layout().Stores {
+		if i == 0 || sl.NodeID != c.layout().Stores[i-1].NodeID {
+			node = s.AddNode()
+			s.SetNodeLocality(node.NodeID(), sl.Locality)
+		}
+		store, ok := s.AddStore(node.NodeID())
+		if !ok {
+			panic(fmt.Sprintf("unable to replay cluster: cannot add store s%d", sl.StoreID))
+		}
+		if sl.Capacity > 0 {
+			s.SetStoreCapacity(store.StoreID(), sl.Capacity)
+		}
+	}
+	return s
+}
+
+// Regions returns the regions and zones of the recorded nodes, taken from
+// the region and zone locality tiers.
+func (c Cluster) Regions() []state.Region {
+	var regions []state.Region
+	regionIdx := make(map[string]int)
+	zoneIdx := make(map[[2]string]int)
+	for i, sl := range c.layout().Stores {
+		newNode := i == 0 || sl.NodeID != c.layout().Stores[i-1].NodeID
+		region, _ := sl.Locality.Find("region")
+		zone, _ := sl.Locality.Find("zone")
+		ri, ok := regionIdx[region]
+		if !ok {
+			ri = len(regions)
+			regionIdx[region] = ri
+			regions = append(regions, state.Region{Name: region})
+		}
+		zi, ok := zoneIdx[[2]string{region, zone}]
+		if !ok {
+			zi = len(regions[ri].Zones)
+			zoneIdx[[2]string{region, zone}] = zi
+			regions[ri].Zones = append(regions[ri].Zones, state.Zone{Name: zone})
+		}
+		z := &regions[ri].Zones[zi]
+		if newNode {
+			z.NodeCount++
+		}
+		// The stores per node of a zone is the maximum over its nodes.
+		if stores := c.storesOnNode(sl.NodeID); stores > z.StoresPerNode {
+			z.StoresPerNode = stores
+		}
+	}
+	return regions
+}
+
+func (c Cluster) storesOnNode(nodeID roachpb.NodeID) int {
+	n := 0
+	for _, sl := range c.layout().Stores {
+		if sl.NodeID == nodeID {
+			n++
+		}
+	}
+	return n
+}
+
+func (c Cluster) layout() *Layout {
+	if c.Replay.layout == nil {
+		panic("unable to replay cluster: the replay has no recorded layout")
+	}
+	return c.Replay.layout
+}
+
+// Ranges implements the gen.RangeGen interface, creating the recorded ranges
+// and placing their replicas and leases as recorded. It must be used with the
+// state generated by Cluster.
+type Ranges struct {
+	Replay *Replay
+}
+
+var _ gen.RangeGen = Ranges{}
+
+func (r Ranges) String() string {
+	return fmt.Sprintf("replayed ranges with ranges=%d", len(r.layout().Ranges))
+}
+
+// Generate returns an updated simulator state, where the recorded ranges have
+// been created with the recorded replicas, leaseholder and size. Each range
+// uses the default span config, with the number of replicas and voters of the
+// recorded range. There is no randomness in this range generation.
+func (r Ranges) Generate(
+	seed int64, settings *config.SimulationSettings, s state.State,
+) state.State {
+	layout := r.layout()
+	storeIDs := make(map[roachpb.StoreID]state.StoreID, len(layout.Stores))
+	for i, sl := range layout.Stores {
+		storeIDs[sl.StoreID] = state.StoreID(i + 1)
+	}
+	mapStores := func(replicas []roachpb.ReplicaDescriptor) []state.StoreID {
+		var ret []state.StoreID
+		for _, repl := range replicas {
+			if storeID, ok := storeIDs[repl.StoreID]; ok {
+				ret = append(ret, storeID)
+			}
+		}
+		return ret
+	}
+
+	// The state is initialized with a single range, using the default span
+	// config.
+	defaultConfig := *s.RangeFor(state.MinKey).SpanConfig()
+	infos := make(state.RangesInfo, 0, len(layout.Ranges))
+	lastKey := state.InvalidKey
+	for _, rng := range layout.Ranges {
+		voters := mapStores(rng.Desc.Replicas().VoterDescriptors())
+		nonVoters := mapStores(rng.Desc.Replicas().NonVoterDescriptors())
+		if len(voters) == 0 {
+			continue
+		}
+		startKey := r.Replay.keyMap.Key(rng.Desc.StartKey.AsRawKey())
+		if len(infos) == 0 {
+			// The first range always starts at the minimum key, even if the
+			// layout is missing the first recorded range.
+			startKey = state.MinKey
+		}
+		if startKey == lastKey {
+			continue
+		}
+		lastKey = startKey
+		leaseholder, ok := storeIDs[rng.Leaseholder]
+		if !ok || !containsStore(voters, leaseholder) {
+			leaseholder = voters[0]
+		}
+		conf := defaultConfig
+		conf.NumReplicas = int32(len(voters) + len(nonVoters))
+		conf.NumVoters = int32(len(voters))
+		info := state.RangeInfoWithReplicas(startKey, voters, nonVoters, leaseholder, &conf)
+		info.Size = rng.Size
+		infos = append(infos, info)
+	}
+	state.LoadRangeInfo(s, infos...)
+	return s
+}
+
+func (r Ranges) layout() *Layout {
+	if r.Replay.layout == nil {
+		panic("unable to replay ranges: the replay has no recorded layout")
+	}
+	return r.Replay.layout
+}
+
+func containsStore(stores []state.StoreID, storeID state.StoreID) bool {
+	for _, s := range stores {
+		if s == storeID {
+			return true
+		}
+	}
+	return false
+}
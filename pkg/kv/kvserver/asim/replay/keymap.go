@@ -0,0 +1,87 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package replay
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/state"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+)
+
+// KeyMap maps keys of a recorded cluster onto the integer keyspace of the
+// simulator, preserving their order. The keyspace of the simulator is divided
+// evenly between a set of boundary keys, such as the range boundaries and
+// key visualizer span boundaries of the recording. A key maps to the
+// simulator key of the greatest boundary that is less than or equal to it, so
+// only the order of boundaries, not their distance from each other, carries
+// over into the simulator.
+type KeyMap struct {
+	bounds  []roachpb.Key
+	spacing int64
+}
+
+// NewKeyMap returns a KeyMap with the given boundary keys. The minimum key is
+// always a boundary, which maps to state.MinKey.
+func NewKeyMap(keys ...roachpb.Key) *KeyMap {
+	bounds := make([]roachpb.Key, 0, len(keys)+1)
+	bounds = append(bounds, roachpb.KeyMin)
+	for _, key := range keys {
+		if key.Compare(roachpb.KeyMax) < 0 {
+			bounds = append(bounds, key)
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool {
+		return bounds[i].Compare(bounds[j]) < 0
+	})
+	unique := bounds[:1]
+	for _, key := range bounds[1:] {
+		if !key.Equal(unique[len(unique)-1]) {
+			unique = append(unique, key)
+		}
+	}
+	return &KeyMap{
+		bounds:  unique,
+		spacing: int64(state.MaxKey) / int64(len(unique)),
+	}
+}
+
+// Len returns the number of boundary keys in the map.
+func (km *KeyMap) Len() int {
+	return len(km.bounds)
+}
+
+// Key returns the simulator key that the given key maps to. Keys greater than
+// or equal to the maximum key map to state.MaxKey.
+func (km *KeyMap) Key(key roachpb.Key) state.Key {
+	if key.Compare(roachpb.KeyMax) >= 0 {
+		return state.MaxKey
+	}
+	idx := sort.Search(len(km.bounds), func(i int) bool {
+		return km.bounds[i].Compare(key) > 0
+	}) - 1
+	return state.Key(int64(idx) * km.spacing)
+}
+
+// Span returns the simulator span [start, end) that the span of the given
+// keys maps to. The returned span is never empty, even when both keys map to
+// the same simulator key.
+func (km *KeyMap) Span(startKey, endKey roachpb.Key) (start, end state.Key) {
+	start, end = km.Key(startKey), km.Key(endKey)
+	if len(endKey) == 0 {
+		// An empty end key denotes the end of the keyspace.
+		end = state.MaxKey
+	}
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
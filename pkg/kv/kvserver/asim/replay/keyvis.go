@@ -0,0 +1,136 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package replay
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/keysutil"
+	"github.com/cockroachdb/errors"
+)
+
+// KeyVisBucket is the number of requests to the span [StartKey, EndKey) over
+// a key visualizer sample. The keys are pretty printed.
+type KeyVisBucket struct {
+	StartKey, EndKey string
+	Requests         uint64
+}
+
+// KeyVisSample is a key visualizer sample, which ended at Time.
+type KeyVisSample struct {
+	Time    time.Time
+	Buckets []KeyVisBucket
+}
+
+// KeyVisSamples holds the span statistics collected by the key visualizer, as
+// returned by the /_status/keyvissamples endpoint.
+type KeyVisSamples struct {
+	// SortedPrettyKeys holds the pretty printed keys of every bucket boundary,
+	// sorted by their raw value.
+	SortedPrettyKeys []string
+	// Samples holds the samples, ordered by time.
+	Samples []KeyVisSample
+}
+
+// keyVisSamplesJSON mirrors the JSON encoding of
+// serverpb.KeyVisSamplesResponse.
+type keyVisSamplesJSON struct {
+	PrettyKeyForUUID map[string]string `json:"prettyKeyForUuid"`
+	SortedPrettyKeys []string          `json:"sortedPrettyKeys"`
+	Samples          []struct {
+		Timestamp time.Time `json:"timestamp"`
+		Buckets   []struct {
+			StartKeyID string     `json:"startKeyId"`
+			EndKeyID   string     `json:"endKeyId"`
+			Requests   jsonUint64 `json:"requests"`
+		} `json:"buckets"`
+	} `json:"samples"`
+}
+
+// jsonUint64 is a uint64 which may be encoded as a JSON number or, as it is by
+// jsonpb, a JSON string.
+type jsonUint64 uint64
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (u *jsonUint64) UnmarshalJSON(data []byte) error {
+	v, err := strconv.ParseUint(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+		return err
+	}
+	*u = jsonUint64(v)
+	return nil
+}
+
+// ParseKeyVisSamples parses the response of the /_status/keyvissamples
+// endpoint, which returns the samples stored by keyvisstorage.
+func ParseKeyVisSamples(r io.Reader) (*KeyVisSamples, error) {
+	var resp keyVisSamplesJSON
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, errors.Wrap(err, "parsing key visualizer samples")
+	}
+	prettyKey := func(id string) (string, error) {
+		// The keys of PrettyKeyForUUID are hex encoded, while the bucket key
+		// IDs are formatted as UUIDs.
+		if pretty, ok := resp.PrettyKeyForUUID[strings.ReplaceAll(id, "-", "")]; ok {
+			return pretty, nil
+		}
+		return "", errors.Newf("parsing key visualizer samples: unknown key ID %s", id)
+	}
+	ret := &KeyVisSamples{SortedPrettyKeys: resp.SortedPrettyKeys}
+	for _, s := range resp.Samples {
+		sample := KeyVisSample{Time: s.Timestamp, Buckets: make([]KeyVisBucket, len(s.Buckets))}
+		for i, b := range s.Buckets {
+			var err error
+			if sample.Buckets[i].StartKey, err = prettyKey(b.StartKeyID); err != nil {
+				return nil, err
+			}
+			if sample.Buckets[i].EndKey, err = prettyKey(b.EndKeyID); err != nil {
+				return nil, err
+			}
+			sample.Buckets[i].Requests = uint64(b.Requests)
+		}
+		ret.Samples = append(ret.Samples, sample)
+	}
+	sort.SliceStable(ret.Samples, func(i, j int) bool {
+		return ret.Samples[i].Time.Before(ret.Samples[j].Time)
+	})
+	return ret, nil
+}
+
+// resolveKeys returns the raw key of every pretty printed key in
+// SortedPrettyKeys. Keys are resolved using known, which maps the pretty
+// printed form of keys, such as range boundaries, to their raw value, and
+// otherwise by scanning the pretty printed key. Pretty printing is lossy, so
+// keys which can't be resolved either way are replaced by a key which sorts
+// directly after the preceding resolved key, preserving their order.
+func (kv *KeyVisSamples) resolveKeys(known map[string]roachpb.Key) map[string]roachpb.Key {
+	scanner := keysutil.MakePrettyScanner(nil /* tableParser */, nil /* tenantParser */)
+	resolved := make(map[string]roachpb.Key, len(kv.SortedPrettyKeys))
+	prev := roachpb.KeyMin
+	for _, pretty := range kv.SortedPrettyKeys {
+		key, ok := known[pretty]
+		if !ok {
+			var err error
+			if key, err = scanner.Scan(pretty); err != nil || key.Compare(prev) < 0 {
+				key = prev.Next()
+			}
+		}
+		resolved[pretty] = key
+		prev = key
+	}
+	return resolved
+}
@@ -0,0 +1,293 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package replay replays the load and layout recorded from a real cluster in
+// the allocation simulator. The load is taken from the span statistics
+// collected by the key visualizer and the store load in a tsdump, while the
+// store, range and replica layout is taken from a debug zip.
+package replay
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/state"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/workload"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/errors"
+)
+
+// defaultKeyVisSampleInterval is the duration of the first key visualizer
+// sample, whose start isn't recorded. It is the default value of
+// keyvisualizer.sample_interval.
+const defaultKeyVisSampleInterval = 5 * time.Minute
+
+// defaultTSResolution is the duration of the last datapoint in a tsdump,
+// whose end isn't recorded. It is the resolution of the stored time series.
+const defaultTSResolution = 10 * time.Second
+
+// storeLoadMetrics are the store metrics which are replayed from a tsdump.
+var storeLoadMetrics = []string{
+	readsMetric, writesMetric, readBytesMetric, writeBytesMetric, requestsMetric, cpuMetric,
+}
+
+// Replay is a recording of the load and, optionally, the layout of a cluster,
+// mapped onto the simulator keyspace.
+type Replay struct {
+	layout   *Layout
+	keyMap   *KeyMap
+	samples  []workload.ReplaySample
+	duration time.Duration
+}
+
+// New returns a Replay of the given recording, any of which may be nil.
+//
+// When key visualizer samples are given, the load of each key visualizer
+// bucket is replayed against its span. The key visualizer counts requests
+// without distinguishing reads and writes or recording their size, so the
+// cluster wide load in the tsdump, if given, is used to divide requests into
+// reads and writes and to estimate their size and CPU time. Otherwise, all
+// requests are replayed as reads.
+//
+// When only a tsdump is given, the load of each store is replayed uniformly
+// over the ranges it holds the lease for in the layout, or over the whole
+// keyspace when no layout is given.
+func New(layout *Layout, tsdump *TSDump, keyVis *KeyVisSamples) (*Replay, error) {
+	if layout == nil && tsdump == nil && keyVis == nil {
+		return nil, errors.New("nothing to replay")
+	}
+
+	// The pretty printed form of the range boundaries is used to resolve the
+	// pretty printed keys of the key visualizer.
+	var bounds []roachpb.Key
+	known := make(map[string]roachpb.Key)
+	if layout != nil {
+		for _, rng := range layout.Ranges {
+			for _, key := range []roachpb.Key{rng.Desc.StartKey.AsRawKey(), rng.Desc.EndKey.AsRawKey()} {
+				bounds = append(bounds, key)
+				known[key.String()] = key
+			}
+		}
+	}
+	var resolved map[string]roachpb.Key
+	if keyVis != nil {
+		resolved = keyVis.resolveKeys(known)
+		for _, key := range resolved {
+			bounds = append(bounds, key)
+		}
+	}
+
+	r := &Replay{layout: layout, keyMap: NewKeyMap(bounds...)}
+	var err error
+	switch {
+	case keyVis != nil:
+		r.samples, err = keyVisReplaySamples(r.keyMap, keyVis, resolved, tsdump)
+	case tsdump != nil:
+		r.samples, err = tsdumpReplaySamples(r.keyMap, layout, tsdump)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if n := len(r.samples); n > 0 {
+		r.duration = r.samples[n-1].Offset + r.samples[n-1].Duration
+	}
+	return r, nil
+}
+
+// Samples returns the load to replay.
+func (r *Replay) Samples() []workload.ReplaySample {
+	return r.samples
+}
+
+// Duration returns the duration of the recorded load.
+func (r *Replay) Duration() time.Duration {
+	return r.duration
+}
+
+// KeyMap returns the mapping of recorded keys onto the simulator keyspace.
+func (r *Replay) KeyMap() *KeyMap {
+	return r.keyMap
+}
+
+// Layout returns the recorded layout, which may be nil.
+func (r *Replay) Layout() *Layout {
+	return r.layout
+}
+
+func (r *Replay) String() string {
+	stores, ranges := 0, 0
+	if r.layout != nil {
+		stores, ranges = len(r.layout.Stores), len(r.layout.Ranges)
+	}
+	return fmt.Sprintf("replay with stores=%d, ranges=%d, samples=%d, duration=%s, keys=%d",
+		stores, ranges, len(r.samples), r.duration, r.keyMap.Len())
+}
+
+// loadSplit describes how to replay requests, which aren't broken down into
+// reads and writes, using the cluster wide load recorded in a tsdump.
+type loadSplit struct {
+	readFraction                float64
+	bytesPerRead, bytesPerWrite float64
+	cpuPerRequest               float64
+}
+
+// makeLoadSplit returns the load split of the load recorded in [start, end).
+// Without a tsdump, every request is a read, of unknown size and CPU time.
+func makeLoadSplit(d *TSDump, start, end time.Time) loadSplit {
+	ls := loadSplit{readFraction: 1}
+	if d == nil {
+		return ls
+	}
+	reads, writes := d.Total(readsMetric, start, end), d.Total(writesMetric, start, end)
+	if reads+writes > 0 {
+		ls.readFraction = reads / (reads + writes)
+	}
+	if reads > 0 {
+		ls.bytesPerRead = d.Total(readBytesMetric, start, end) / reads
+	}
+	if writes > 0 {
+		ls.bytesPerWrite = d.Total(writeBytesMetric, start, end) / writes
+	}
+	if requests := d.Total(requestsMetric, start, end); requests > 0 {
+		ls.cpuPerRequest = d.Total(cpuMetric, start, end) / requests
+	}
+	return ls
+}
+
+func (ls loadSplit) spanLoad(start, end state.Key, requestsPerSecond float64) workload.SpanLoad {
+	reads := requestsPerSecond * ls.readFraction
+	writes := requestsPerSecond - reads
+	return workload.SpanLoad{
+		StartKey:            int64(start),
+		EndKey:              int64(end),
+		ReadsPerSecond:      reads,
+		WritesPerSecond:     writes,
+		ReadBytesPerSecond:  reads * ls.bytesPerRead,
+		WriteBytesPerSecond: writes * ls.bytesPerWrite,
+		RequestCPUPerSecond: requestsPerSecond * ls.cpuPerRequest,
+	}
+}
+
+// keyVisReplaySamples returns a replay sample for each key visualizer sample.
+// A sample covers the time since the previous sample, so gaps in the
+// recording are folded into the following sample.
+func keyVisReplaySamples(
+	km *KeyMap, kv *KeyVisSamples, resolved map[string]roachpb.Key, tsdump *TSDump,
+) ([]workload.ReplaySample, error) {
+	if len(kv.Samples) == 0 {
+		return nil, errors.New("no key visualizer samples to replay")
+	}
+	start := kv.Samples[0].Time.Add(-defaultKeyVisSampleInterval)
+	prev := start
+	var samples []workload.ReplaySample
+	for _, s := range kv.Samples {
+		duration := s.Time.Sub(prev)
+		if duration <= 0 {
+			continue
+		}
+		split := makeLoadSplit(tsdump, prev, s.Time)
+		sample := workload.ReplaySample{Offset: prev.Sub(start), Duration: duration}
+		for _, b := range s.Buckets {
+			if b.Requests == 0 {
+				continue
+			}
+			startKey, ok := resolved[b.StartKey]
+			if !ok {
+				return nil, errors.Newf("key visualizer key %s is not in the sorted keys", b.StartKey)
+			}
+			endKey, ok := resolved[b.EndKey]
+			if !ok {
+				return nil, errors.Newf("key visualizer key %s is not in the sorted keys", b.EndKey)
+			}
+			spanStart, spanEnd := km.Span(startKey, endKey)
+			sample.Spans = append(sample.Spans,
+				split.spanLoad(spanStart, spanEnd, float64(b.Requests)/duration.Seconds()))
+		}
+		samples = append(samples, sample)
+		prev = s.Time
+	}
+	return samples, nil
+}
+
+// tsdumpReplaySamples returns a replay sample for each datapoint of the store
+// load in the tsdump.
+func tsdumpReplaySamples(
+	km *KeyMap, layout *Layout, d *TSDump,
+) ([]workload.ReplaySample, error) {
+	times := d.Times(storeLoadMetrics...)
+	if len(times) == 0 {
+		return nil, errors.New("no store load found in the tsdump")
+	}
+
+	// The spans which the load of each store is replayed against, keyed by
+	// the store ID, which is the source of the store metrics.
+	type span struct{ start, end state.Key }
+	var spansForStore map[string][]span
+	if layout != nil {
+		spansForStore = make(map[string][]span)
+		for _, rng := range layout.Ranges {
+			source := strconv.Itoa(int(rng.Leaseholder))
+			start, end := km.Span(rng.Desc.StartKey.AsRawKey(), rng.Desc.EndKey.AsRawKey())
+			spansForStore[source] = append(spansForStore[source], span{start: start, end: end})
+		}
+	}
+	sources := make(map[string]struct{})
+	for _, metric := range storeLoadMetrics {
+		for _, source := range d.Sources(metric) {
+			sources[source] = struct{}{}
+		}
+	}
+
+	samples := make([]workload.ReplaySample, len(times))
+	for i, t := range times {
+		duration := defaultTSResolution
+		if i+1 < len(times) {
+			duration = times[i+1].Sub(t)
+		} else if i > 0 {
+			duration = t.Sub(times[i-1])
+		}
+		samples[i] = workload.ReplaySample{Offset: t.Sub(times[0]), Duration: duration}
+		for _, source := range sortedKeys(sources) {
+			spans := []span{{start: state.MinKey, end: state.MaxKey}}
+			if layout != nil {
+				// Load on stores without leases in the layout isn't
+				// replayed.
+				spans = spansForStore[source]
+			}
+			value := func(metric string) float64 {
+				v, _ := d.Value(metric, source, t)
+				return v / float64(len(spans))
+			}
+			for _, sp := range spans {
+				samples[i].Spans = append(samples[i].Spans, workload.SpanLoad{
+					StartKey:            int64(sp.start),
+					EndKey:              int64(sp.end),
+					ReadsPerSecond:      value(readsMetric),
+					WritesPerSecond:     value(writesMetric),
+					ReadBytesPerSecond:  value(readBytesMetric),
+					WriteBytesPerSecond: value(writeBytesMetric),
+					RequestCPUPerSecond: value(cpuMetric),
+				})
+			}
+		}
+	}
+	return samples, nil
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
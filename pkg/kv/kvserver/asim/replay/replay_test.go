@@ -0,0 +1,324 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package replay
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/config"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/state"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/workload"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyMap(t *testing.T) {
+	table := func(id uint32) roachpb.Key {
+		return keys.SystemSQLCodec.TablePrefix(id)
+	}
+	km := NewKeyMap(table(106), table(104), table(104), roachpb.KeyMax, table(105))
+	require.Equal(t, 4, km.Len())
+
+	spacing := int64(state.MaxKey) / 4
+	require.Equal(t, state.MinKey, km.Key(roachpb.KeyMin))
+	require.Equal(t, state.MinKey, km.Key(keys.SystemSQLCodec.TablePrefix(50)))
+	require.Equal(t, state.Key(spacing), km.Key(table(104)))
+	require.Equal(t, state.Key(spacing), km.Key(keys.SystemSQLCodec.IndexPrefix(104, 1)))
+	require.Equal(t, state.Key(2*spacing), km.Key(table(105)))
+	require.Equal(t, state.Key(3*spacing), km.Key(table(200)))
+	require.Equal(t, state.MaxKey, km.Key(roachpb.KeyMax))
+
+	start, end := km.Span(table(104), table(105))
+	require.Equal(t, state.Key(spacing), start)
+	require.Equal(t, state.Key(2*spacing), end)
+	// Keys within the same boundaries map to a non-empty span.
+	start, end = km.Span(keys.SystemSQLCodec.IndexPrefix(104, 1), keys.SystemSQLCodec.IndexPrefix(104, 2))
+	require.Equal(t, state.Key(spacing), start)
+	require.Equal(t, state.Key(spacing+1), end)
+	start, end = km.Span(table(106), nil)
+	require.Equal(t, state.Key(3*spacing), start)
+	require.Equal(t, state.MaxKey, end)
+}
+
+func TestParseTSDump(t *testing.T) {
+	t0 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	expected := map[string][]Datapoint{
+		"1": {{Time: t0, Value: 10}, {Time: t0.Add(10 * time.Second), Value: 20}},
+		"2": {{Time: t0, Value: 1.5}},
+	}
+	for _, tc := range []struct {
+		format, input string
+	}{
+		{
+			format: "text",
+			input: `cr.store.rebalancing.readspersecond 1
+1685577610000000000 20
+1685577600000000000 10
+cr.store.rebalancing.readspersecond 2
+1685577600000000000 1.5
+cr.node.sys.uptime 1
+1685577600000000000 100
+`,
+		},
+		{
+			format: "csv",
+			input: `cr.store.rebalancing.readspersecond,2023-06-01T00:00:00Z,1,10
+cr.store.rebalancing.readspersecond,2023-06-01T00:00:10Z,1,20
+cr.store.rebalancing.readspersecond,2023-06-01T00:00:00Z,2,1.5
+cr.node.sys.uptime,2023-06-01T00:00:00Z,1,100
+`,
+		},
+		{
+			format: "tsv",
+			input: "cr.store.rebalancing.readspersecond\t2023-06-01T00:00:00Z\t1\t10\n" +
+				"cr.store.rebalancing.readspersecond\t2023-06-01T00:00:10Z\t1\t20\n" +
+				"cr.store.rebalancing.readspersecond\t2023-06-01T00:00:00Z\t2\t1.5\n" +
+				"cr.node.sys.uptime\t2023-06-01T00:00:00Z\t1\t100\n",
+		},
+	} {
+		t.Run(tc.format, func(t *testing.T) {
+			d, err := ParseTSDump(strings.NewReader(tc.input))
+			require.NoError(t, err)
+			require.Equal(t, []string{"1", "2"}, d.Sources(readsMetric))
+			for source, points := range expected {
+				require.Equal(t, points, d.Series(readsMetric, source))
+			}
+			require.Equal(t, []time.Time{t0, t0.Add(10 * time.Second)}, d.Times(readsMetric))
+			v, ok := d.Value(readsMetric, "1", t0.Add(10*time.Second))
+			require.True(t, ok)
+			require.Equal(t, 20.0, v)
+			_, ok = d.Value(readsMetric, "2", t0.Add(10*time.Second))
+			require.False(t, ok)
+			// Source 1 has a single datapoint in the window, while source 2 has
+			// none and uses its mean over the whole series.
+			require.Equal(t, 21.5, d.Total(readsMetric, t0.Add(5*time.Second), t0.Add(time.Minute)))
+		})
+	}
+
+	_, err := ParseTSDump(strings.NewReader("1685577600000000000 10\n"))
+	require.ErrorContains(t, err, "datapoint without a series")
+}
+
+const testKeyVisSamples = `{
+  "prettyKeyForUuid": {
+    "00000000000000000000000000000001": "/Min",
+    "00000000000000000000000000000002": "/Table/104",
+    "00000000000000000000000000000003": "/Table/104/1/\"a\"",
+    "00000000000000000000000000000004": "/Table/104/1/\"b\"",
+    "00000000000000000000000000000005": "/Max"
+  },
+  "sortedPrettyKeys": ["/Min", "/Table/104", "/Table/104/1/\"a\"", "/Table/104/1/\"b\"", "/Max"],
+  "samples": [
+    {
+      "timestamp": "2023-06-01T00:10:00Z",
+      "buckets": [
+        {"startKeyId": "00000000-0000-0000-0000-000000000003", "endKeyId": "00000000-0000-0000-0000-000000000004", "requests": "1200"}
+      ]
+    },
+    {
+      "timestamp": "2023-06-01T00:05:00Z",
+      "buckets": [
+        {"startKeyId": "00000000-0000-0000-0000-000000000001", "endKeyId": "00000000-0000-0000-0000-000000000002", "requests": "0"},
+        {"startKeyId": "00000000-0000-0000-0000-000000000002", "endKeyId": "00000000-0000-0000-0000-000000000005", "requests": 3000}
+      ]
+    }
+  ]
+}`
+
+func TestKeyVisReplay(t *testing.T) {
+	kv, err := ParseKeyVisSamples(strings.NewReader(testKeyVisSamples))
+	require.NoError(t, err)
+	require.Len(t, kv.Samples, 2)
+	require.Equal(t, time.Date(2023, 6, 1, 0, 5, 0, 0, time.UTC), kv.Samples[0].Time)
+	require.Equal(t, []KeyVisBucket{
+		{StartKey: "/Table/104/1/\"a\"", EndKey: "/Table/104/1/\"b\"", Requests: 1200},
+	}, kv.Samples[1].Buckets)
+
+	// The quoted keys can't be scanned, they are resolved to keys which sort
+	// in the same order after /Table/104.
+	resolved := kv.resolveKeys(nil)
+	table104 := keys.SystemSQLCodec.TablePrefix(104)
+	require.Equal(t, table104, resolved["/Table/104"])
+	require.Equal(t, table104.Next(), resolved["/Table/104/1/\"a\""])
+	require.Equal(t, table104.Next().Next(), resolved["/Table/104/1/\"b\""])
+	require.Equal(t, roachpb.KeyMax, resolved["/Max"])
+
+	// Without a tsdump, every request is replayed as a read. The first sample
+	// is assumed to span the default sample interval.
+	r, err := New(nil /* layout */, nil /* tsdump */, kv)
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Minute, r.Duration())
+	km := r.KeyMap()
+	require.Equal(t, []workload.ReplaySample{
+		{
+			Offset:   0,
+			Duration: 5 * time.Minute,
+			Spans: []workload.SpanLoad{
+				{StartKey: int64(km.Key(table104)), EndKey: int64(state.MaxKey), ReadsPerSecond: 10},
+			},
+		},
+		{
+			Offset:   5 * time.Minute,
+			Duration: 5 * time.Minute,
+			Spans: []workload.SpanLoad{
+				{
+					StartKey:       int64(km.Key(table104.Next())),
+					EndKey:         int64(km.Key(table104.Next().Next())),
+					ReadsPerSecond: 4,
+				},
+			},
+		},
+	}, r.Samples())
+
+	// With a tsdump, requests are split into reads and writes using the
+	// cluster wide load over each sample.
+	d, err := ParseTSDump(strings.NewReader(`cr.store.rebalancing.readspersecond,2023-06-01T00:06:00Z,1,30
+cr.store.rebalancing.writespersecond,2023-06-01T00:06:00Z,1,10
+cr.store.rebalancing.readbytespersecond,2023-06-01T00:06:00Z,1,3000
+cr.store.rebalancing.writebytespersecond,2023-06-01T00:06:00Z,1,10000
+cr.store.rebalancing.requestspersecond,2023-06-01T00:06:00Z,1,20
+cr.store.rebalancing.cpunanospersecond,2023-06-01T00:06:00Z,1,2000000
+`))
+	require.NoError(t, err)
+	r, err = New(nil /* layout */, d, kv)
+	require.NoError(t, err)
+	require.Equal(t, workload.SpanLoad{
+		StartKey:            int64(km.Key(table104.Next())),
+		EndKey:              int64(km.Key(table104.Next().Next())),
+		ReadsPerSecond:      3,
+		WritesPerSecond:     1,
+		ReadBytesPerSecond:  300,
+		WriteBytesPerSecond: 1000,
+		RequestCPUPerSecond: 400000,
+	}, r.Samples()[1].Spans[0])
+}
+
+var testDebugZip = map[string]string{
+	"debug/nodes.json": `{"nodes": [
+  {"desc": {"node_id": 3, "locality": {"tiers": [{"key": "region", "value": "us-west"}, {"key": "zone", "value": "a"}]}},
+   "store_statuses": [{"desc": {"store_id": 7, "capacity": {"capacity": 1000}}}, {"desc": {"store_id": 4}}]},
+  {"desc": {"node_id": 1, "locality": {"tiers": [{"key": "region", "value": "us-east"}, {"key": "zone", "value": "b"}]}},
+   "store_statuses": [{"desc": {"store_id": 1}}]}
+]}`,
+	// /Table/104 is 8A==, /Table/105 is 8Q==.
+	"debug/nodes/1/ranges.json": `[
+  {"state": {"state": {"desc": {"range_id": 1, "start_key": "", "end_key": "8A==", "generation": 1,
+    "internal_replicas": [{"node_id": 1, "store_id": 1, "replica_id": 1}, {"node_id": 3, "store_id": 7, "replica_id": 2, "type": 5}]},
+    "lease": {"replica": {"store_id": 1}}, "stats": {"key_bytes": 10, "val_bytes": 20}}}},
+  {"state": {"state": {"desc": {"range_id": 2, "start_key": "8A==", "end_key": "//8=", "generation": 0,
+    "internal_replicas": [{"node_id": 1, "store_id": 1, "replica_id": 1}]}}}}
+]`,
+	"debug/nodes/3/ranges.json": `[
+  {"state": {"state": {"desc": {"range_id": 2, "start_key": "8A==", "end_key": "8Q==", "generation": 2,
+    "internal_replicas": [{"node_id": 1, "store_id": 1, "replica_id": 1}, {"node_id": 3, "store_id": 4, "replica_id": 2}]},
+    "lease": {"replica": {"store_id": 4}}, "stats": {"key_bytes": 100, "val_bytes": 200}}}},
+  {"state": {"state": {"desc": {"range_id": 3, "start_key": "8Q==", "end_key": "//8=", "generation": 2,
+    "internal_replicas": [{"node_id": 3, "store_id": 4, "replica_id": 1}]},
+    "lease": {"replica": {"store_id": 4}}}}},
+  {"state": {"state": {"desc": {"range_id": 4, "start_key": "8A==", "end_key": "8Q==", "generation": 0,
+    "internal_replicas": [{"node_id": 3, "store_id": 4, "replica_id": 1}]}}}}
+]`,
+}
+
+func TestDebugZipReplay(t *testing.T) {
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, "debug.zip")
+	f, err := os.Create(zipPath)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	for name, contents := range testDebugZip {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(contents))
+		require.NoError(t, err)
+		// Write the extracted debug zip too.
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644))
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	for _, path := range []string{zipPath, dir, filepath.Join(dir, "debug")} {
+		layout, err := ReadDebugZip(path)
+		require.NoError(t, err, path)
+		require.Equal(t, []StoreLayout{
+			{NodeID: 1, StoreID: 1, Locality: roachpb.Locality{Tiers: []roachpb.Tier{
+				{Key: "region", Value: "us-east"}, {Key: "zone", Value: "b"}}}},
+			{NodeID: 3, StoreID: 4, Locality: roachpb.Locality{Tiers: []roachpb.Tier{
+				{Key: "region", Value: "us-west"}, {Key: "zone", Value: "a"}}}},
+			{NodeID: 3, StoreID: 7, Capacity: 1000, Locality: roachpb.Locality{Tiers: []roachpb.Tier{
+				{Key: "region", Value: "us-west"}, {Key: "zone", Value: "a"}}}},
+		}, layout.Stores)
+		// The stale descriptor of r2 and the overlapping r4 are skipped.
+		require.Len(t, layout.Ranges, 3)
+		for i, rangeID := range []roachpb.RangeID{1, 2, 3} {
+			require.Equal(t, rangeID, layout.Ranges[i].Desc.RangeID)
+		}
+		require.Equal(t, roachpb.StoreID(4), layout.Ranges[1].Leaseholder)
+		require.Equal(t, int64(300), layout.Ranges[1].Size)
+	}
+	_, err = ReadDebugZip(filepath.Join(dir, "debug", "nodes"))
+	require.ErrorContains(t, err, "nodes.json")
+
+	layout, err := ReadDebugZip(zipPath)
+	require.NoError(t, err)
+	d, err := ParseTSDump(strings.NewReader(`cr.store.rebalancing.readspersecond 1
+1685577600000000000 100
+1685577610000000000 50
+cr.store.rebalancing.readspersecond 4
+1685577600000000000 10
+`))
+	require.NoError(t, err)
+	r, err := New(layout, d, nil /* keyVis */)
+	require.NoError(t, err)
+	require.Equal(t, "replay with stores=3, ranges=3, samples=2, duration=20s, keys=3", r.String())
+	// The load of s4 is spread over the ranges it holds the lease for.
+	km := r.KeyMap()
+	r2Start, r2End := km.Span(layout.Ranges[1].Desc.StartKey.AsRawKey(), layout.Ranges[1].Desc.EndKey.AsRawKey())
+	r3Start, r3End := km.Span(layout.Ranges[2].Desc.StartKey.AsRawKey(), layout.Ranges[2].Desc.EndKey.AsRawKey())
+	require.Equal(t, []workload.SpanLoad{
+		{StartKey: 0, EndKey: int64(r2Start), ReadsPerSecond: 100},
+		{StartKey: int64(r2Start), EndKey: int64(r2End), ReadsPerSecond: 5},
+		{StartKey: int64(r3Start), EndKey: int64(r3End), ReadsPerSecond: 5},
+	}, r.Samples()[0].Spans)
+
+	settings := config.DefaultSimulationSettings()
+	s := Cluster{Replay: r}.Generate(0, settings)
+	s = Ranges{Replay: r}.Generate(0, settings, s)
+	require.Len(t, s.Nodes(), 2)
+	require.Len(t, s.Stores(), 3)
+	require.Equal(t, []state.Region{
+		{Name: "us-east", Zones: []state.Zone{{Name: "b", NodeCount: 1, StoresPerNode: 1}}},
+		{Name: "us-west", Zones: []state.Zone{{Name: "a", NodeCount: 1, StoresPerNode: 2}}},
+	}, Cluster{Replay: r}.Regions())
+
+	// The recorded stores s1, s4 and s7 are s1, s2 and s3 in the simulator.
+	leaseholder := func(rng state.Range) state.StoreID {
+		store, ok := s.LeaseholderStore(rng.RangeID())
+		require.True(t, ok)
+		return store.StoreID()
+	}
+	rng := s.RangeFor(state.MinKey)
+	require.Equal(t, state.StoreID(1), leaseholder(rng))
+	require.Equal(t, int32(2), rng.SpanConfig().NumReplicas)
+	require.Equal(t, int32(1), rng.SpanConfig().NumVoters)
+	rng = s.RangeFor(r2Start)
+	require.Equal(t, state.StoreID(2), leaseholder(rng))
+	require.Len(t, rng.Replicas(), 2)
+	require.Equal(t, int64(300), s.RangeUsageInfo(rng.RangeID(), 2).LogicalBytes)
+	require.Len(t, s.Ranges(), 3)
+}
@@ -0,0 +1,232 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package replay
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// The store metrics, recorded per store, which are replayed from a tsdump.
+const (
+	readsMetric      = "cr.store.rebalancing.readspersecond"
+	writesMetric     = "cr.store.rebalancing.writespersecond"
+	readBytesMetric  = "cr.store.rebalancing.readbytespersecond"
+	writeBytesMetric = "cr.store.rebalancing.writebytespersecond"
+	requestsMetric   = "cr.store.rebalancing.requestspersecond"
+	cpuMetric        = "cr.store.rebalancing.cpunanospersecond"
+)
+
+// Datapoint is a single value of a time series.
+type Datapoint struct {
+	Time  time.Time
+	Value float64
+}
+
+type seriesKey struct {
+	name, source string
+}
+
+// TSDump holds the time series read from the output of `cockroach debug
+// tsdump`.
+type TSDump struct {
+	series map[seriesKey][]Datapoint
+}
+
+// ParseTSDump parses the output of `cockroach debug tsdump` in the text, csv
+// or tsv format. The format is detected from the first line. The raw format
+// is not supported; a raw dump may be imported into a single node cluster
+// using COCKROACH_DEBUG_TS_IMPORT_FILE and dumped again in a supported format.
+func ParseTSDump(r io.Reader) (*TSDump, error) {
+	br := bufio.NewReader(r)
+	first, err := br.Peek(4096)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	if idx := strings.IndexByte(string(first), '\n'); idx >= 0 {
+		first = first[:idx]
+	}
+	d := &TSDump{series: make(map[seriesKey][]Datapoint)}
+	switch {
+	case strings.Contains(string(first), "\t"):
+		err = d.parseCSV(br, '\t')
+	case strings.Contains(string(first), ","):
+		err = d.parseCSV(br, ',')
+	default:
+		err = d.parseText(br)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, points := range d.series {
+		points := points
+		sort.SliceStable(points, func(i, j int) bool {
+			return points[i].Time.Before(points[j].Time)
+		})
+	}
+	return d, nil
+}
+
+// parseCSV parses lines of the form: name,timestamp,source,value.
+func (d *TSDump) parseCSV(r io.Reader, comma rune) error {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.FieldsPerRecord = 4
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "parsing tsdump")
+		}
+		ts, err := time.Parse(time.RFC3339, rec[1])
+		if err != nil {
+			return errors.Wrapf(err, "parsing tsdump timestamp %q", rec[1])
+		}
+		value, err := strconv.ParseFloat(rec[3], 64)
+		if err != nil {
+			return errors.Wrapf(err, "parsing tsdump value %q", rec[3])
+		}
+		key := seriesKey{name: rec[0], source: rec[2]}
+		d.series[key] = append(d.series[key], Datapoint{Time: ts, Value: value})
+	}
+}
+
+// parseText parses the default tsdump format, where each series begins with a
+// line containing the name and source of the series, followed by a line
+// containing the timestamp, in nanoseconds, and value of each datapoint.
+func (d *TSDump) parseText(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	var key *seriesKey
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimRight(scanner.Text(), " ")
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			if nanos, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+				if key == nil {
+					return errors.Newf("parsing tsdump: line %d: datapoint without a series", lineNum)
+				}
+				value, err := strconv.ParseFloat(fields[1], 64)
+				if err != nil {
+					return errors.Wrapf(err, "parsing tsdump: line %d", lineNum)
+				}
+				d.series[*key] = append(d.series[*key],
+					Datapoint{Time: timeutil.Unix(0, nanos).UTC(), Value: value})
+				continue
+			}
+		}
+		if len(fields) > 2 {
+			return errors.Newf("parsing tsdump: line %d: unexpected line %q", lineNum, line)
+		}
+		key = &seriesKey{name: fields[0]}
+		if len(fields) == 2 {
+			key.source = fields[1]
+		}
+	}
+	return errors.Wrap(scanner.Err(), "parsing tsdump")
+}
+
+// Series returns the datapoints of the series with the given name and source,
+// ordered by time.
+func (d *TSDump) Series(name, source string) []Datapoint {
+	return d.series[seriesKey{name: name, source: source}]
+}
+
+// Sources returns the sources which recorded the series with the given name,
+// in sorted order.
+func (d *TSDump) Sources(name string) []string {
+	var sources []string
+	for key := range d.series {
+		if key.name == name {
+			sources = append(sources, key.source)
+		}
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// Times returns the distinct timestamps of the datapoints of every series
+// with one of the given names, in order.
+func (d *TSDump) Times(names ...string) []time.Time {
+	seen := make(map[time.Time]struct{})
+	var times []time.Time
+	for key, points := range d.series {
+		found := false
+		for _, name := range names {
+			found = found || key.name == name
+		}
+		if !found {
+			continue
+		}
+		for _, p := range points {
+			if _, ok := seen[p.Time]; !ok {
+				seen[p.Time] = struct{}{}
+				times = append(times, p.Time)
+			}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+	return times
+}
+
+// Value returns the value of the series with the given name and source at the
+// given time, if there is one.
+func (d *TSDump) Value(name, source string, t time.Time) (float64, bool) {
+	points := d.Series(name, source)
+	idx := sort.Search(len(points), func(i int) bool {
+		return !points[i].Time.Before(t)
+	})
+	if idx < len(points) && points[idx].Time.Equal(t) {
+		return points[idx].Value, true
+	}
+	return 0, false
+}
+
+// Total returns the sum over every source of the mean value of the series
+// with the given name in [start, end). When a source recorded no datapoint in
+// [start, end), the mean over its whole series is used instead.
+func (d *TSDump) Total(name string, start, end time.Time) float64 {
+	var total float64
+	for _, source := range d.Sources(name) {
+		points := d.Series(name, source)
+		sum, n := 0.0, 0
+		for _, p := range points {
+			if !p.Time.Before(start) && p.Time.Before(end) {
+				sum += p.Value
+				n++
+			}
+		}
+		if n == 0 {
+			for _, p := range points {
+				sum += p.Value
+			}
+			n = len(points)
+		}
+		if n > 0 {
+			total += sum / float64(n)
+		}
+	}
+	return total
+}
@@ -305,10 +305,10 @@ func (m *ManualSimClock) Set(tsNanos int64) {
 // lexicographically ordered as strings. The simplification to limit keys to
 // integers simplifies workload generation and testing.
 //
-// TODO(kvoli): This is a simplification. In order to replay workloads or use
-// the workload tool, real keys, which may be arbitrary bytes will need to
-// either be remapped or the key format extended to support them. Revisit this
-// when we are ready.
+// TODO(kvoli): This is a simplification. Real keys, which may be arbitrary
+// bytes, are remapped onto this keyspace when replaying a recorded cluster
+// (see replay.KeyMap), which preserves their order but not their distribution.
+// Using the workload tool would need the key format extended to support them.
 
 // Key is a single slot in the keyspace.
 type Key int64
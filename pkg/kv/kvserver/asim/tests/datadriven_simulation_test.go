@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -25,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/gen"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/history"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/metrics"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/replay"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/asim/state"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
 	"github.com/cockroachdb/cockroach/pkg/spanconfig/spanconfigtestutils"
@@ -61,6 +63,15 @@ import (
 //     regions having 3 zones. complex: 28 nodes, 3 regions with a skewed
 //     number of nodes per region.
 //
+//   - "replay" [debug_zip=<path>] [tsdump=<path>] [keyvis=<path>]
+//     Replay the load, and the cluster and range layout, recorded from a real
+//     cluster. The paths are relative to testdata/replay. The load is replayed
+//     from the key visualizer samples, as returned by /_status/keyvissamples,
+//     and the store load in the output of `cockroach debug tsdump`. When a
+//     debug zip is given, the cluster and ranges are generated from the
+//     nodes, stores and ranges it contains, replacing the cluster and range
+//     generators.
+//
 //   - "gen_ranges" [ranges=<int>] [placement_skew=<bool>] [repl_factor=<int>]
 //     [keyspace=<int>] [range_bytes=<int>]
 //     Initialize the range generator parameters. On the next call to eval, the
@@ -158,7 +169,7 @@ func TestDataDriven(t *testing.T) {
 	dir := datapathutils.TestDataPath(t, "non_rand")
 	datadriven.Walk(t, dir, func(t *testing.T, path string) {
 		const defaultKeyspace = 10000
-		loadGen := gen.BasicLoad{}
+		var loadGen gen.LoadGen = gen.BasicLoad{}
 		var clusterGen gen.ClusterGen
 		var rangeGen gen.RangeGen = gen.BasicRanges{
 			BaseRanges: gen.BaseRanges{
@@ -187,14 +198,52 @@ func TestDataDriven(t *testing.T) {
 				scanIfExists(t, d, "min_key", &minKey)
 				scanIfExists(t, d, "max_key", &maxKey)
 
-				loadGen.SkewedAccess = accessSkew
-				loadGen.MinKey = minKey
-				loadGen.MaxKey = maxKey
-				loadGen.RWRatio = rwRatio
-				loadGen.Rate = rate
-				loadGen.MaxBlockSize = maxBlock
-				loadGen.MinBlockSize = minBlock
+				loadGen = gen.BasicLoad{
+					SkewedAccess: accessSkew,
+					MinKey:       minKey,
+					MaxKey:       maxKey,
+					RWRatio:      rwRatio,
+					Rate:         rate,
+					MaxBlockSize: maxBlock,
+					MinBlockSize: minBlock,
+				}
 				return ""
+			case "replay":
+				var debugZip, tsdumpFile, keyVisFile string
+				scanIfExists(t, d, "debug_zip", &debugZip)
+				scanIfExists(t, d, "tsdump", &tsdumpFile)
+				scanIfExists(t, d, "keyvis", &keyVisFile)
+
+				var layout *replay.Layout
+				var tsdump *replay.TSDump
+				var keyVis *replay.KeyVisSamples
+				var err error
+				if debugZip != "" {
+					layout, err = replay.ReadDebugZip(datapathutils.TestDataPath(t, "replay", debugZip))
+					require.NoError(t, err)
+				}
+				if tsdumpFile != "" {
+					f, err := os.Open(datapathutils.TestDataPath(t, "replay", tsdumpFile))
+					require.NoError(t, err)
+					defer f.Close()
+					tsdump, err = replay.ParseTSDump(f)
+					require.NoError(t, err)
+				}
+				if keyVisFile != "" {
+					f, err := os.Open(datapathutils.TestDataPath(t, "replay", keyVisFile))
+					require.NoError(t, err)
+					defer f.Close()
+					keyVis, err = replay.ParseKeyVisSamples(f)
+					require.NoError(t, err)
+				}
+				r, err := replay.New(layout, tsdump, keyVis)
+				require.NoError(t, err)
+				loadGen = replay.Load{Replay: r}
+				if layout != nil {
+					clusterGen = replay.Cluster{Replay: r}
+					rangeGen = replay.Ranges{Replay: r}
+				}
+				return r.String()
 			case "gen_ranges":
 				var ranges, replFactor, keyspace = 1, 3, defaultKeyspace
 				var bytes int64 = 0
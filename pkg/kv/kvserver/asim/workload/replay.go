@@ -0,0 +1,197 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package workload
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// maxReplayKeysPerSpan is the maximum number of distinct keys within a span
+// that the load recorded against the span is spread over on each tick. It
+// bounds the size of the load batches returned by the replay generator, while
+// still allowing load based splitting to find split points within the span.
+const maxReplayKeysPerSpan = 16
+
+// SpanLoad is the load recorded against a span of the keyspace [StartKey,
+// EndKey), as rates per second.
+type SpanLoad struct {
+	StartKey, EndKey    int64
+	ReadsPerSecond      float64
+	WritesPerSecond     float64
+	ReadBytesPerSecond  float64
+	WriteBytesPerSecond float64
+	// RequestCPUPerSecond is the CPU time, in nanoseconds per second, spent
+	// serving requests to the span.
+	RequestCPUPerSecond float64
+}
+
+// ReplaySample is the load recorded over the interval [Offset,
+// Offset+Duration), relative to the start of the recording.
+type ReplaySample struct {
+	Offset, Duration time.Duration
+	Spans            []SpanLoad
+}
+
+// replaySpan accumulates the load of a span that has been replayed, but not
+// yet returned in a load batch because it amounts to less than one operation.
+type replaySpan struct {
+	startKey, endKey                          int64
+	reads, writes, readBytes, writeBytes, cpu float64
+}
+
+// ReplayGenerator replays load recorded against spans of the keyspace. The
+// load of each span is spread uniformly at random over keys within the span.
+type ReplayGenerator struct {
+	start   time.Time
+	lastRun time.Time
+	rand    *rand.Rand
+	samples []ReplaySample
+	// spans holds the outstanding load of every span seen so far, in the
+	// order it was first seen so that the generated load is deterministic
+	// for a seed.
+	spans   []*replaySpan
+	spanIdx map[[2]int64]int
+}
+
+// NewReplayGenerator returns a generator that replays the recorded samples,
+// where the first sample is replayed starting at start.
+func NewReplayGenerator(start time.Time, seed int64, samples []ReplaySample) Generator {
+	sorted := append([]ReplaySample(nil), samples...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Offset < sorted[j].Offset
+	})
+	return &ReplayGenerator{
+		start:   start,
+		lastRun: start,
+		rand:    rand.New(rand.NewSource(seed)),
+		samples: sorted,
+		spanIdx: make(map[[2]int64]int),
+	}
+}
+
+// Tick returns the load events up till time tick, from the last time the
+// workload generator was called. Load which amounts to less than a single
+// operation on a span is carried over to later ticks.
+func (rg *ReplayGenerator) Tick(maxTime time.Time) LoadBatch {
+	from, to := rg.lastRun.Sub(rg.start), maxTime.Sub(rg.start)
+	if to <= from {
+		return LoadBatch{}
+	}
+	for _, sample := range rg.samples {
+		if sample.Offset >= to {
+			break
+		}
+		overlap := minDuration(to, sample.Offset+sample.Duration) - maxDuration(from, sample.Offset)
+		if overlap <= 0 {
+			continue
+		}
+		secs := overlap.Seconds()
+		for _, sl := range sample.Spans {
+			span := rg.span(sl.StartKey, sl.EndKey)
+			span.reads += sl.ReadsPerSecond * secs
+			span.writes += sl.WritesPerSecond * secs
+			span.readBytes += sl.ReadBytesPerSecond * secs
+			span.writeBytes += sl.WriteBytesPerSecond * secs
+			span.cpu += sl.RequestCPUPerSecond * secs
+		}
+	}
+	rg.lastRun = maxTime
+
+	next := make(map[int64]LoadEvent)
+	for _, span := range rg.spans {
+		reads, writes := int64(span.reads), int64(span.writes)
+		if reads+writes == 0 {
+			continue
+		}
+		var readBytes, writeBytes int64
+		if reads > 0 {
+			readBytes = int64(span.readBytes)
+			span.readBytes -= float64(readBytes)
+		}
+		if writes > 0 {
+			writeBytes = int64(span.writeBytes)
+			span.writeBytes -= float64(writeBytes)
+		}
+		cpu := int64(span.cpu)
+		span.reads -= float64(reads)
+		span.writes -= float64(writes)
+		span.cpu -= float64(cpu)
+
+		// Spread the operations over up to maxReplayKeysPerSpan random keys
+		// in the span, splitting the bytes and CPU proportionally.
+		n := reads + writes
+		if n > maxReplayKeysPerSpan {
+			n = maxReplayKeysPerSpan
+		}
+		if width := span.endKey - span.startKey; width < n {
+			n = width
+		}
+		for i := int64(0); i < n; i++ {
+			key := span.startKey + rg.rand.Int63n(span.endKey-span.startKey)
+			event := next[key]
+			event.Reads += share(reads, i, n)
+			event.ReadSize += share(readBytes, i, n)
+			event.Writes += share(writes, i, n)
+			event.WriteSize += share(writeBytes, i, n)
+			event.RequestCPU += share(cpu, i, n)
+			next[key] = event
+		}
+	}
+
+	ret := make(LoadBatch, 0, len(next))
+	for k, v := range next {
+		v.Key = k
+		ret = append(ret, v)
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+// span returns the outstanding load of the span [startKey, endKey), creating
+// it if it doesn't exist.
+func (rg *ReplayGenerator) span(startKey, endKey int64) *replaySpan {
+	if endKey <= startKey {
+		panic(fmt.Sprintf("span end key (%d) must be greater than start key (%d)", endKey, startKey))
+	}
+	key := [2]int64{startKey, endKey}
+	if idx, ok := rg.spanIdx[key]; ok {
+		return rg.spans[idx]
+	}
+	rg.spanIdx[key] = len(rg.spans)
+	span := &replaySpan{startKey: startKey, endKey: endKey}
+	rg.spans = append(rg.spans, span)
+	return span
+}
+
+// share returns the i'th of n near equal parts of total, such that the n
+// parts sum to total.
+func share(total, i, n int64) int64 {
+	return int64(math.Round(float64(total)*float64(i+1)/float64(n))) -
+		int64(math.Round(float64(total)*float64(i)/float64(n)))
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -162,3 +162,68 @@ func TestRandWorkloadGenerator(t *testing.T) {
 		require.Equal(t, math.Round(tc.readRatio*100), math.Round((float64(stats.reads)/float64(stats.reads+stats.writes))*100))
 	}
 }
+
+// TestReplayGenerator asserts that the replayed load matches the recorded load
+// of each span over the replayed interval, including load carried over between
+// ticks, and that every load event falls within the span it was recorded for.
+func TestReplayGenerator(t *testing.T) {
+	samples := []ReplaySample{
+		{
+			Offset:   time.Minute,
+			Duration: time.Minute,
+			Spans: []SpanLoad{
+				{StartKey: 0, EndKey: 100, ReadsPerSecond: 10, ReadBytesPerSecond: 1000, RequestCPUPerSecond: 1e6},
+				{StartKey: 100, EndKey: 200, WritesPerSecond: 0.5, WriteBytesPerSecond: 50},
+			},
+		},
+		{
+			Offset:   0,
+			Duration: time.Minute,
+			Spans: []SpanLoad{
+				{StartKey: 0, EndKey: 100, ReadsPerSecond: 1, WritesPerSecond: 1, ReadBytesPerSecond: 10, WriteBytesPerSecond: 20},
+			},
+		},
+	}
+
+	start := time.Date(2022, 03, 21, 11, 0, 0, 0, time.UTC)
+	gen := NewReplayGenerator(start, testingSeed, samples)
+
+	type totals struct {
+		reads, writes, readSize, writeSize, cpu int64
+	}
+	spanTotals := func(batch LoadBatch, startKey, endKey int64) totals {
+		var ret totals
+		for _, event := range batch {
+			if event.Key < startKey || event.Key >= endKey {
+				continue
+			}
+			ret.reads += event.Reads
+			ret.writes += event.Writes
+			ret.readSize += event.ReadSize
+			ret.writeSize += event.WriteSize
+			ret.cpu += event.RequestCPU
+		}
+		return ret
+	}
+
+	// Replay the first sample in 1s ticks.
+	var first LoadBatch
+	for i := 1; i <= 60; i++ {
+		batch := gen.Tick(start.Add(time.Duration(i) * time.Second))
+		require.True(t, sort.IsSorted(batch))
+		first = append(first, batch...)
+	}
+	require.Equal(t, totals{reads: 60, writes: 60, readSize: 600, writeSize: 1200}, spanTotals(first, 0, 100))
+	require.Equal(t, totals{}, spanTotals(first, 100, 200))
+
+	// Replay the second sample, and past the end of the recording, in 7s
+	// ticks. The writes to [100,200) occur every other second, so they are
+	// carried over between ticks.
+	var second LoadBatch
+	for i := 67; i <= 200; i += 7 {
+		second = append(second, gen.Tick(start.Add(time.Duration(i)*time.Second))...)
+	}
+	require.Equal(t, totals{reads: 600, readSize: 60000, cpu: 6e7}, spanTotals(second, 0, 100))
+	require.Equal(t, totals{writes: 30, writeSize: 3000}, spanTotals(second, 100, 200))
+	require.Empty(t, gen.Tick(start.Add(time.Hour)))
+}
@@ -317,6 +317,11 @@ type MetricExporter interface {
 	// LockTableMetrics returns information about the state of the lockTable.
 	LockTableMetrics() LockTableMetrics
 
+	// TxnWaitQueueWaitingPushes returns information about the PushTxn requests
+	// currently waiting in the txnWaitQueue. Along with the waiters reported by
+	// QueryLockTableState, these form the edges of the range's wait-for graph.
+	TxnWaitQueueWaitingPushes() []txnwait.WaitingPushInfo
+
 	// TODO(nvanbenschoten): provide better observability into the state of the
 	// txn wait queue. Currently, all observability is provided by metrics that
 	// are passed to the txn wait queue constructor.
@@ -989,6 +994,10 @@ type txnWaitQueue interface {
 	// deadlock detection.
 	GetDependents(uuid.UUID) []uuid.UUID
 
+	// WaitingPushes returns information about each of the PushTxn requests
+	// currently waiting in the queue.
+	WaitingPushes() []txnwait.WaitingPushInfo
+
 	// MaybeWaitForPush checks whether there is a queue already established for
 	// transaction being pushed by the provided request. If not, or if the
 	// PushTxn request isn't queueable, the method returns immediately. If there
@@ -636,6 +636,11 @@ func (m *managerImpl) LockTableMetrics() LockTableMetrics {
 	return m.lt.Metrics()
 }
 
+// TxnWaitQueueWaitingPushes implements the MetricExporter interface.
+func (m *managerImpl) TxnWaitQueueWaitingPushes() []txnwait.WaitingPushInfo {
+	return m.twq.WaitingPushes()
+}
+
 // TestingLockTableString implements the MetricExporter interface.
 func (m *managerImpl) TestingLockTableString() string {
 	return m.lt.String()
@@ -11,6 +11,8 @@
 package load
 
 import (
+	"time"
+
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/replicastats"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -204,6 +206,27 @@ func (rl *ReplicaLoad) Stats() ReplicaLoadStats {
 	}
 }
 
+// LastAccess returns the last time a key was read or written on the replica,
+// along with the time since which reads and writes have been tracked. The
+// former is zero if no access has been observed since the latter. Tracking
+// restarts whenever the replica acquires or loses its lease, so only the
+// leaseholder observes reads.
+func (rl *ReplicaLoad) LastAccess() (lastAccess, trackedSince time.Time) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	for _, stat := range []LoadStat{ReadKeys, WriteKeys} {
+		lastRecord, recordedSince := rl.mu.stats[stat].LastRecord()
+		if lastRecord.After(lastAccess) {
+			lastAccess = lastRecord
+		}
+		if recordedSince.After(trackedSince) {
+			trackedSince = recordedSince
+		}
+	}
+	return lastAccess, trackedSince
+}
+
 // RequestLocalityInfo returns the summary of client localities for requests
 // made to this replica.
 func (rl *ReplicaLoad) RequestLocalityInfo() *replicastats.RatedSummary {
@@ -25,6 +25,12 @@ var (
 		Measurement: "Nanoseconds",
 		Unit:        metric.Unit_NANOSECONDS,
 	}
+	metaRangeFeedFilteredEvents = metric.Metadata{
+		Name:        "kv.rangefeed.filtered_events",
+		Help:        "Number of RangeFeed value events not sent because they did not match the registration filter",
+		Measurement: "Events",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaRangeFeedExhausted = metric.Metadata{
 		Name:        "kv.rangefeed.budget_allocation_failed",
 		Help:        "Number of times RangeFeed failed because memory budget was exceeded",
@@ -72,6 +78,7 @@ var (
 // Metrics are for production monitoring of RangeFeeds.
 type Metrics struct {
 	RangeFeedCatchUpScanNanos        *metric.Counter
+	RangeFeedFilteredEvents          *metric.Counter
 	RangeFeedBudgetExhausted         *metric.Counter
 	RangeFeedBudgetBlocked           *metric.Counter
 	RangeFeedRegistrations           *metric.Gauge
@@ -95,6 +102,7 @@ func (*Metrics) MetricStruct() {}
 func NewMetrics() *Metrics {
 	return &Metrics{
 		RangeFeedCatchUpScanNanos:            metric.NewCounter(metaRangeFeedCatchUpScanNanos),
+		RangeFeedFilteredEvents:              metric.NewCounter(metaRangeFeedFilteredEvents),
 		RangeFeedBudgetExhausted:             metric.NewCounter(metaRangeFeedExhausted),
 		RangeFeedBudgetBlocked:               metric.NewCounter(metaRangeFeedBudgetBlocked),
 		RangeFeedRegistrations:               metric.NewGauge(metaRangeFeedRegistrations),
@@ -0,0 +1,165 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rangefeed
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/intsets"
+)
+
+// registrationFilter evaluates the kvpb.RangeFeedFilter supplied by a
+// registration against the value events published to it, so that events which
+// the consumer would discard are dropped before they are buffered and sent.
+// Checkpoint and SST events are never filtered.
+type registrationFilter struct {
+	// spans is sorted and non-overlapping. It is nil if the filter does not
+	// constrain spans, and empty if no key of the registration can match.
+	spans    roachpb.Spans
+	families intsets.Fast
+	columns  intsets.Fast
+}
+
+// newRegistrationFilter compiles the provided filter for a registration over the
+// given span. It returns nil if the filter does not constrain any events.
+func newRegistrationFilter(f *kvpb.RangeFeedFilter, regSpan roachpb.Span) *registrationFilter {
+	if f == nil || (len(f.Spans) == 0 && len(f.FamilyIDs) == 0 && len(f.ColumnIDs) == 0) {
+		return nil
+	}
+	ef := &registrationFilter{}
+	if len(f.Spans) > 0 {
+		spans := make([]roachpb.Span, 0, len(f.Spans))
+		for _, sp := range f.Spans {
+			if len(sp.EndKey) == 0 {
+				sp.EndKey = sp.Key.Next()
+			}
+			if sp = sp.Intersect(regSpan); sp.Valid() {
+				spans = append(spans, sp)
+			}
+		}
+		ef.spans, _ = roachpb.MergeSpans(&spans)
+	}
+	for _, id := range f.FamilyIDs {
+		ef.families.Add(int(id))
+	}
+	for _, id := range f.ColumnIDs {
+		ef.columns.Add(int(id))
+	}
+	return ef
+}
+
+// apply returns the event that should be emitted in place of the provided one,
+// or false if the event does not match the filter and should be dropped. The
+// provided event is never mutated; if it needs to be projected, a copy is
+// returned.
+func (f *registrationFilter) apply(event *kvpb.RangeFeedEvent) (*kvpb.RangeFeedEvent, bool) {
+	switch t := event.GetValue().(type) {
+	case *kvpb.RangeFeedValue:
+		if !f.matchesKey(t.Key) {
+			return nil, false
+		}
+		if f.columns.Empty() {
+			return event, true
+		}
+		value, valueOK := f.project(t.Value)
+		prevValue, prevValueOK := f.project(t.PrevValue)
+		if !valueOK && !prevValueOK {
+			return event, true
+		}
+		ret := event.ShallowCopy()
+		v := ret.GetValue().(*kvpb.RangeFeedValue)
+		if valueOK {
+			v.Value = value
+		}
+		if prevValueOK {
+			v.PrevValue = prevValue
+		}
+		return ret, true
+	case *kvpb.RangeFeedDeleteRange:
+		return event, f.overlapsSpans(t.Span)
+	default:
+		return event, true
+	}
+}
+
+// matchesKey returns whether a value event on the given key satisfies the
+// span and column family conditions of the filter.
+func (f *registrationFilter) matchesKey(key roachpb.Key) bool {
+	if f.spans != nil {
+		i := sort.Search(len(f.spans), func(i int) bool {
+			return key.Compare(f.spans[i].EndKey) < 0
+		})
+		if i == len(f.spans) || !f.spans[i].ContainsKey(key) {
+			return false
+		}
+	}
+	if !f.families.Empty() {
+		famID, err := keys.DecodeFamilyKey(key)
+		if err != nil {
+			// Not a SQL row key, so it has no family to filter on.
+			return true
+		}
+		return f.families.Contains(int(famID))
+	}
+	return true
+}
+
+// overlapsSpans returns whether the given span overlaps any of the spans of the
+// filter.
+func (f *registrationFilter) overlapsSpans(span roachpb.Span) bool {
+	if f.spans == nil {
+		return true
+	}
+	i := sort.Search(len(f.spans), func(i int) bool {
+		return span.Key.Compare(f.spans[i].EndKey) < 0
+	})
+	return i < len(f.spans) && f.spans[i].Overlaps(span)
+}
+
+// project returns the value with all tuple-encoded columns which are not part
+// of the filter's projection removed. It returns false if the value is not a
+// tuple and so was not projected, in which case it is emitted unchanged.
+func (f *registrationFilter) project(v roachpb.Value) (roachpb.Value, bool) {
+	if !v.IsPresent() || v.GetTag() != roachpb.ValueType_TUPLE {
+		return roachpb.Value{}, false
+	}
+	data, err := v.GetTuple()
+	if err != nil {
+		return roachpb.Value{}, false
+	}
+	out := make([]byte, 0, len(data))
+	var colID, lastColID uint32
+	for len(data) > 0 {
+		_, dataOffset, colIDDelta, typ, err := encoding.DecodeValueTag(data)
+		if err != nil {
+			return roachpb.Value{}, false
+		}
+		n, err := encoding.PeekValueLengthWithOffsetsAndType(data, dataOffset, typ)
+		if err != nil {
+			return roachpb.Value{}, false
+		}
+		colID += colIDDelta
+		if f.columns.Contains(int(colID)) {
+			out = encoding.EncodeValueTag(out, colID-lastColID, typ)
+			out = append(out, data[dataOffset:n]...)
+			lastColID = colID
+		}
+		data = data[n:]
+	}
+	var projected roachpb.Value
+	projected.SetTuple(out)
+	projected.Timestamp = v.Timestamp
+	return projected, true
+}
@@ -79,6 +79,7 @@ type registration struct {
 	span             roachpb.Span
 	catchUpTimestamp hlc.Timestamp // exclusive
 	withDiff         bool
+	filter           *registrationFilter
 	metrics          *Metrics
 
 	// Output.
@@ -117,6 +118,7 @@ func newRegistration(
 	startTS hlc.Timestamp,
 	catchUpIter *CatchUpIterator,
 	withDiff bool,
+	eventFilter *kvpb.RangeFeedFilter,
 	bufferSz int,
 	blockWhenFull bool,
 	metrics *Metrics,
@@ -128,6 +130,7 @@ func newRegistration(
 		span:             span,
 		catchUpTimestamp: startTS,
 		withDiff:         withDiff,
+		filter:           newRegistrationFilter(eventFilter, span),
 		metrics:          metrics,
 		stream:           stream,
 		done:             done,
@@ -150,7 +153,17 @@ func (r *registration) publish(
 	ctx context.Context, event *kvpb.RangeFeedEvent, alloc *SharedBudgetAllocation,
 ) {
 	r.validateEvent(event)
-	e := getPooledSharedEvent(sharedEvent{event: r.maybeStripEvent(event), alloc: alloc})
+	event = r.maybeStripEvent(event)
+	if r.filter != nil {
+		var ok bool
+		if event, ok = r.filter.apply(event); !ok {
+			// The event doesn't match the registration's filter, so there is no
+			// need to buffer it or to send it to the consumer.
+			r.metrics.RangeFeedFilteredEvents.Inc(1)
+			return
+		}
+	}
+	e := getPooledSharedEvent(sharedEvent{event: event, alloc: alloc})
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -397,7 +410,20 @@ func (r *registration) maybeRunCatchUpScan(ctx context.Context) error {
 		r.metrics.RangeFeedCatchUpScanNanos.Inc(timeutil.Since(start).Nanoseconds())
 	}()
 
-	return catchUpIter.CatchUpScan(ctx, r.stream.Send, r.withDiff)
+	outputFn := r.stream.Send
+	if r.filter != nil {
+		// The catch-up scan still visits every version in the registration's
+		// span, but only the events which match the filter are sent.
+		outputFn = func(event *kvpb.RangeFeedEvent) error {
+			event, ok := r.filter.apply(event)
+			if !ok {
+				r.metrics.RangeFeedFilteredEvents.Inc(1)
+				return nil
+			}
+			return r.stream.Send(event)
+		}
+	}
+	return catchUpIter.CatchUpScan(ctx, outputFn, r.withDiff)
 }
 
 // ID implements interval.Interface.
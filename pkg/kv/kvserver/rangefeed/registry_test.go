@@ -16,10 +16,11 @@ import (
 	"sync"
 	"testing"
 
-	_ "github.com/cockroachdb/cockroach/pkg/keys" // hook up pretty printer
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/future"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
@@ -122,6 +123,7 @@ func newTestRegistration(
 		ts,
 		makeCatchUpIterator(catchup, span, ts),
 		withDiff,
+		nil, /* eventFilter */
 		5,
 		false, /* blockWhenFull */
 		NewMetrics(),
@@ -327,6 +329,131 @@ func TestRegistrationCatchUpScan(t *testing.T) {
 	require.Equal(t, expEvents, r.Events())
 }
 
+func TestRegistrationFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	run := func(
+		t *testing.T,
+		r *testRegistration,
+		filter *kvpb.RangeFeedFilter,
+		events []*kvpb.RangeFeedEvent,
+	) []*kvpb.RangeFeedEvent {
+		r.filter = newRegistrationFilter(filter, r.span)
+		for _, ev := range events {
+			r.publish(ctx, ev, nil /* alloc */)
+		}
+		go r.runOutputLoop(ctx, 0)
+		require.NoError(t, r.waitForCaughtUp())
+		defer r.disconnect(nil)
+		return r.Events()
+	}
+
+	t.Run("spans", func(t *testing.T) {
+		keyBB := roachpb.Key("bb")
+		evA := rangeFeedValue(keyA, makeValWithTs("a", 2))
+		evB := rangeFeedValue(keyB, makeValWithTs("b", 2))
+		evBB := rangeFeedValue(keyBB, makeValWithTs("bb", 2))
+		evC := rangeFeedValue(keyC, makeValWithTs("c", 2))
+		delAB := makeRangeFeedEvent(&kvpb.RangeFeedDeleteRange{
+			Span: spAB, Timestamp: hlc.Timestamp{WallTime: 3},
+		})
+		delAC := makeRangeFeedEvent(&kvpb.RangeFeedDeleteRange{
+			Span: spAC, Timestamp: hlc.Timestamp{WallTime: 3},
+		})
+		checkpoint := rangeFeedCheckpoint(spAC, hlc.Timestamp{WallTime: 4})
+
+		r := newTestRegistration(spAC, hlc.Timestamp{}, nil, false /* withDiff */)
+		events := run(t, r, &kvpb.RangeFeedFilter{
+			Spans: []roachpb.Span{spBC, spXY},
+		}, []*kvpb.RangeFeedEvent{evA, evB, evBB, evC, delAB, delAC, checkpoint})
+		require.Equal(t, []*kvpb.RangeFeedEvent{evB, evBB, delAC, checkpoint}, events)
+		require.Equal(t, int64(3), r.metrics.RangeFeedFilteredEvents.Count())
+
+		// Point spans match only their key, and a filter without any span
+		// overlapping the registration drops all values.
+		r = newTestRegistration(spAC, hlc.Timestamp{}, nil, false /* withDiff */)
+		events = run(t, r, &kvpb.RangeFeedFilter{
+			Spans: []roachpb.Span{{Key: keyBB}},
+		}, []*kvpb.RangeFeedEvent{evA, evB, evBB})
+		require.Equal(t, []*kvpb.RangeFeedEvent{evBB}, events)
+
+		r = newTestRegistration(spAC, hlc.Timestamp{}, nil, false /* withDiff */)
+		events = run(t, r, &kvpb.RangeFeedFilter{
+			Spans: []roachpb.Span{spXY},
+		}, []*kvpb.RangeFeedEvent{evA, evB, delAC, checkpoint})
+		require.Equal(t, []*kvpb.RangeFeedEvent{checkpoint}, events)
+	})
+
+	t.Run("families", func(t *testing.T) {
+		rowKey := encoding.EncodeVarintAscending(keys.SystemSQLCodec.IndexPrefix(104, 1), 7)
+		tableSpan := roachpb.Span{
+			Key:    keys.SystemSQLCodec.TablePrefix(104),
+			EndKey: keys.SystemSQLCodec.TablePrefix(105),
+		}
+		var famEvents []*kvpb.RangeFeedEvent
+		for famID := uint32(0); famID < 3; famID++ {
+			famEvents = append(famEvents, rangeFeedValue(
+				keys.MakeFamilyKey(rowKey[:len(rowKey):len(rowKey)], famID), makeValWithTs("v", 2),
+			))
+		}
+		// An index key without a family suffix is not filtered.
+		indexOnly := rangeFeedValue(keys.SystemSQLCodec.IndexPrefix(104, 1), makeValWithTs("v", 2))
+
+		r := newTestRegistration(tableSpan, hlc.Timestamp{}, nil, false /* withDiff */)
+		events := run(t, r, &kvpb.RangeFeedFilter{
+			FamilyIDs: []uint32{0, 2},
+		}, append(famEvents, indexOnly))
+		require.Equal(t, []*kvpb.RangeFeedEvent{famEvents[0], famEvents[2], indexOnly}, events)
+	})
+
+	t.Run("columns", func(t *testing.T) {
+		makeTuple := func(colIDs []uint32, ts int64) roachpb.Value {
+			var b []byte
+			var lastColID uint32
+			for _, colID := range colIDs {
+				b = encoding.EncodeIntValue(b, colID-lastColID, int64(colID)*10)
+				lastColID = colID
+			}
+			var v roachpb.Value
+			v.SetTuple(b)
+			v.Timestamp = hlc.Timestamp{WallTime: ts}
+			return v
+		}
+		tuple := rangeFeedValueWithPrev(keyA, makeTuple([]uint32{1, 2, 3, 5}, 2), makeTuple([]uint32{2, 3}, 0))
+		notTuple := rangeFeedValue(keyB, makeValWithTs("b", 2))
+
+		r := newTestRegistration(spAC, hlc.Timestamp{}, nil, true /* withDiff */)
+		events := run(t, r, &kvpb.RangeFeedFilter{
+			ColumnIDs: []uint32{1, 3, 4},
+		}, []*kvpb.RangeFeedEvent{tuple, notTuple})
+		require.Equal(t, []*kvpb.RangeFeedEvent{
+			rangeFeedValueWithPrev(keyA, makeTuple([]uint32{1, 3}, 2), makeTuple([]uint32{3}, 0)),
+			notTuple,
+		}, events)
+		// The published event must not have been modified.
+		require.Equal(t, makeTuple([]uint32{1, 2, 3, 5}, 2), tuple.Val.Value)
+	})
+
+	t.Run("catch-up scan", func(t *testing.T) {
+		iter := newTestIterator([]storage.MVCCKeyValue{
+			makeKV("a", "valA1", 10),
+			makeKV("b", "valB2", 12),
+			makeKV("b", "valB1", 11),
+			makeKV("c", "valC1", 10),
+		}, nil)
+		r := newTestRegistration(roachpb.Span{Key: keyA, EndKey: keyD}, hlc.Timestamp{WallTime: 1}, iter, false /* withDiff */)
+		r.filter = newRegistrationFilter(&kvpb.RangeFeedFilter{Spans: []roachpb.Span{spBC}}, r.span)
+		require.NoError(t, r.maybeRunCatchUpScan(ctx))
+		require.True(t, iter.closed)
+		require.Equal(t, []*kvpb.RangeFeedEvent{
+			rangeFeedValue(keyB, makeValWithTs("valB1", 11)),
+			rangeFeedValue(keyB, makeValWithTs("valB2", 12)),
+		}, r.Events())
+		require.Equal(t, int64(2), r.metrics.RangeFeedFilteredEvents.Count())
+	})
+}
+
 func TestRegistryBasic(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	ctx := context.Background()
@@ -2403,6 +2403,7 @@ func (r *Replica) MeasureRaftCPUNanos(start time.Duration) {
 func (r *Replica) RangeUsageInfo() allocator.RangeUsageInfo {
 	loadStats := r.LoadStats()
 	localityInfo := r.loadStats.RequestLocalityInfo()
+	lastAccess, accessTrackedSince := r.loadStats.LastAccess()
 	return allocator.RangeUsageInfo{
 		LogicalBytes:             r.GetMVCCStats().Total(),
 		QueriesPerSecond:         loadStats.QueriesPerSecond,
@@ -2417,6 +2418,8 @@ func (r *Replica) RangeUsageInfo() allocator.RangeUsageInfo {
 			Counts:   localityInfo.LocalityCounts,
 			Duration: localityInfo.Duration,
 		},
+		LastAccess:         lastAccess,
+		AccessTrackedSince: accessTrackedSince,
 	}
 }
 
@@ -83,6 +83,9 @@ type ReplicaStats struct {
 	records    [6]*replicaStatsRecord
 	lastRotate time.Time
 	lastReset  time.Time
+	// lastRecord is the last time a non-zero count was recorded. It is zero if
+	// no count has been recorded since lastReset.
+	lastRecord time.Time
 
 	// Testing only.
 	avgRateForTesting float64
@@ -235,6 +238,14 @@ func (rs *ReplicaStats) MergeRequestCounts(other *ReplicaStats) {
 	if rs.lastRotate.After(other.lastRotate) {
 		rs.lastRotate = other.lastRotate
 	}
+	// The merged stats have only been tracked for as long as the most recently
+	// reset of the two, and were last recorded when either was.
+	if other.lastReset.After(rs.lastReset) {
+		rs.lastReset = other.lastReset
+	}
+	if other.lastRecord.After(rs.lastRecord) {
+		rs.lastRecord = other.lastRecord
+	}
 
 }
 
@@ -248,6 +259,7 @@ func (rs *ReplicaStats) SplitRequestCounts(other *ReplicaStats) {
 	other.idx = rs.idx
 	other.lastRotate = rs.lastRotate
 	other.lastReset = rs.lastReset
+	other.lastRecord = rs.lastRecord
 
 	for i := range rs.records {
 		// When the lhs isn't active, set the rhs to inactive as well.
@@ -268,6 +280,9 @@ func (rs *ReplicaStats) RecordCount(now time.Time, count float64, nodeID roachpb
 
 	record := rs.records[rs.idx]
 	record.sum += count
+	if count > 0 {
+		rs.lastRecord = now
+	}
 
 	if rs.getNodeLocality != nil {
 		(*record.localityCounts)[rs.getNodeLocality(nodeID)] += count
@@ -384,6 +399,15 @@ func (rs *ReplicaStats) ResetRequestCounts(now time.Time) {
 	rs.records[rs.idx].activate()
 	rs.lastRotate = now
 	rs.lastReset = rs.lastRotate
+	rs.lastRecord = time.Time{}
+}
+
+// LastRecord returns the last time a non-zero count was recorded, along with
+// the time since which counts have been recorded, i.e. when the stats were
+// created or last reset. The former is zero if no count has been recorded
+// since the latter.
+func (rs *ReplicaStats) LastRecord() (lastRecord, recordedSince time.Time) {
+	return rs.lastRecord, rs.lastReset
 }
 
 // SnapshotRatedSummary returns a RatedSummary representing a snapshot of the
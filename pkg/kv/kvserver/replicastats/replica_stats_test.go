@@ -658,3 +658,51 @@ func TestReplicaStatsRecordAggregate(t *testing.T) {
 
 	require.Equal(t, expectedStatsRecord, rs.records[rs.idx])
 }
+
+func TestReplicaStatsLastRecord(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	start := testingStartTime()
+	now := start
+	rs := NewReplicaStats(now, nil)
+
+	// Nothing has been recorded yet.
+	lastRecord, recordedSince := rs.LastRecord()
+	require.True(t, lastRecord.IsZero())
+	require.Equal(t, start, recordedSince)
+
+	// Zero counts don't count as a record.
+	now = now.Add(time.Minute)
+	rs.RecordCount(now, 0, 0)
+	lastRecord, _ = rs.LastRecord()
+	require.True(t, lastRecord.IsZero())
+
+	now = now.Add(time.Minute)
+	rs.RecordCount(now, 1, 0)
+	lastRecord, _ = rs.LastRecord()
+	require.Equal(t, now, lastRecord)
+
+	// The right hand side of a split inherits the last record time.
+	other := NewReplicaStats(now.Add(time.Hour), nil)
+	rs.SplitRequestCounts(other)
+	lastRecord, recordedSince = other.LastRecord()
+	require.Equal(t, now, lastRecord)
+	require.Equal(t, start, recordedSince)
+
+	// Merging takes the latest record, and the latest reset.
+	later := now.Add(time.Hour)
+	other.ResetRequestCounts(later)
+	other.RecordCount(later, 1, 0)
+	rs.MergeRequestCounts(other)
+	lastRecord, recordedSince = rs.LastRecord()
+	require.Equal(t, later, lastRecord)
+	require.Equal(t, later, recordedSince)
+
+	// Resetting clears the last record time.
+	now = later.Add(time.Minute)
+	rs.ResetRequestCounts(now)
+	lastRecord, recordedSince = rs.LastRecord()
+	require.True(t, lastRecord.IsZero())
+	require.Equal(t, now, recordedSince)
+}
@@ -868,6 +868,8 @@ func (sr *StoreRebalancer) chooseRangeToRebalance(
 		}
 
 		rangeDesc, conf := candidateReplica.DescAndSpanConfig()
+		conf = sr.allocator.TieredSpanConfig(
+			conf, candidateReplica.RangeUsageInfo(), sr.storePool.Clock().PhysicalTime())
 		clusterNodes := sr.storePool.ClusterNodeCount()
 		numDesiredVoters := allocatorimpl.GetNeededVoters(conf.GetNumVoters(), clusterNodes)
 		numDesiredNonVoters := allocatorimpl.GetNeededNonVoters(numDesiredVoters, int(conf.GetNumNonVoters()), clusterNodes)
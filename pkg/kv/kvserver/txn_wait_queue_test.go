@@ -129,6 +129,13 @@ func TestTxnWaitQueueEnableDisable(t *testing.T) {
 		if act, exp := m.PusheeWaiting.Value(), int64(1); act != exp {
 			return errors.Errorf("%d pushees, but want %d", act, exp)
 		}
+		pushes := q.WaitingPushes()
+		if len(pushes) != 1 {
+			return errors.Errorf("expected 1 waiting push; got %+v", pushes)
+		}
+		if pushes[0].Pusher.ID != pusher.ID || pushes[0].Pushee.ID != txn.ID {
+			return errors.Errorf("expected push of %s by %s; got %+v", txn.ID, pusher.ID, pushes[0])
+		}
 
 		return nil
 	})
@@ -153,6 +160,9 @@ func TestTxnWaitQueueEnableDisable(t *testing.T) {
 	if deps := q.GetDependents(txn.ID); deps != nil {
 		t.Errorf("expected GetDependents to return nil as queue is disabled; got %+v", deps)
 	}
+	if pushes := q.WaitingPushes(); pushes != nil {
+		t.Errorf("expected WaitingPushes to return nil as queue is disabled; got %+v", pushes)
+	}
 
 	q.EnqueueTxn(txn)
 	if q.IsEnabled() {
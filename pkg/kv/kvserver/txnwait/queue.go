@@ -169,7 +169,9 @@ type waitingPush struct {
 	req *kvpb.PushTxnRequest
 	// pending channel receives updated, pushed txn or nil if queue is cleared.
 	pending chan *roachpb.Transaction
-	mu      struct {
+	// start is the time at which the push began waiting in the queue.
+	start time.Time
+	mu    struct {
 		syncutil.Mutex
 		dependents map[uuid.UUID]struct{} // transitive set of txns waiting on this txn
 	}
@@ -449,6 +451,49 @@ func (q *Queue) GetDependents(txnID uuid.UUID) []uuid.UUID {
 	return nil
 }
 
+// WaitingPushInfo describes a PushTxn request that is waiting in the Queue
+// for its pushee transaction to commit or abort.
+type WaitingPushInfo struct {
+	// Pusher is the pushing transaction. Its ID is empty if the pusher is a
+	// non-transactional request.
+	Pusher enginepb.TxnMeta
+	// Pushee is the transaction being waited on.
+	Pushee enginepb.TxnMeta
+	// PushType is the type of the waiting push.
+	PushType kvpb.PushTxnType
+	// WaitDuration is the amount of time the push has spent in the queue.
+	WaitDuration time.Duration
+}
+
+// WaitingPushes returns a (newly minted) slice describing each of the PushTxn
+// requests that are currently waiting in the queue. Each entry is an edge of
+// the wait-for graph between the pusher and the pushee transactions.
+func (q *Queue) WaitingPushes() []WaitingPushInfo {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.mu.txns == nil {
+		// Not enabled; do nothing.
+		return nil
+	}
+	now := timeutil.Now()
+	var pushes []WaitingPushInfo
+	for _, pending := range q.mu.txns {
+		if pending.waitingPushes == nil {
+			continue
+		}
+		for e := pending.waitingPushes.Front(); e != nil; e = e.Next() {
+			push := e.Value.(*waitingPush)
+			pushes = append(pushes, WaitingPushInfo{
+				Pusher:       push.req.PusherTxn.TxnMeta,
+				Pushee:       pending.getTxn().TxnMeta,
+				PushType:     push.req.PushType,
+				WaitDuration: now.Sub(push.start),
+			})
+		}
+	}
+	return pushes
+}
+
 // isTxnUpdated returns whether the transaction specified in
 // the QueryTxnRequest has had its status or priority updated
 // or whether the known set of dependent transactions has
@@ -520,6 +565,7 @@ func (q *Queue) MaybeWaitForPush(
 	push := &waitingPush{
 		req:     req,
 		pending: make(chan *roachpb.Transaction, 1),
+		start:   timeutil.Now(),
 	}
 	pushElem := pending.waitingPushes.PushBack(push)
 	waitingPushesCount := pending.waitingPushes.Len()
@@ -103,6 +103,9 @@ func (s *SpanConfig) ValidateSystemTargetSpanConfig() error {
 	if s.ExcludeDataFromBackup {
 		return errors.AssertionFailedf("ExcludeDataFromBackup set on system span config")
 	}
+	if s.Tiering != nil {
+		return errors.AssertionFailedf("Tiering set on system span config")
+	}
 	return nil
 }
 
@@ -150,6 +153,12 @@ func (c ConstraintsConjunction) String() string {
 	return sb.String()
 }
 
+// ColdAfter returns the period without reads or writes after which a range is
+// demoted to the cold stores.
+func (p *TieringPolicy) ColdAfter() time.Duration {
+	return time.Duration(p.ColdAfterSeconds) * time.Second
+}
+
 // String implements the stringer interface.
 func (p ProtectionPolicy) String() string {
 	var sb strings.Builder
@@ -0,0 +1,354 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/authserver"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/srverrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/appstatspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LockWaitsGraph returns the wait-for graph between transactions, assembled
+// from the lock tables and txn wait queues of the requested nodes. The
+// transaction and statement fingerprints of the waiters and holders are
+// resolved on the node that receives the request.
+func (s *systemStatusServer) LockWaitsGraph(
+	ctx context.Context, req *serverpb.LockWaitsGraphRequest,
+) (*serverpb.LockWaitsGraphResponse, error) {
+	ctx = s.AnnotateCtx(authserver.ForwardSQLIdentityThroughRPCCalls(ctx))
+
+	if err := s.privilegeChecker.RequireViewActivityOrViewActivityRedactedPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	shouldRedactKeys, err := s.shouldRedactLockWaitKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &serverpb.LockWaitsGraphResponse{
+		ErrorsByNodeID: make(map[roachpb.NodeID]string),
+	}
+
+	if len(req.NodeID) > 0 {
+		requestedNodeID, local, err := s.parseNodeID(req.NodeID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		var nodeResp *serverpb.LockWaitsGraphResponse
+		if local {
+			nodeResp, err = s.LocalLockWaitsGraph(ctx, req)
+		} else {
+			var statusClient serverpb.StatusClient
+			statusClient, err = s.dialNode(ctx, requestedNodeID)
+			if err == nil {
+				nodeResp, err = statusClient.LocalLockWaitsGraph(ctx, req)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp.Edges = nodeResp.Edges
+	} else {
+		dialFn := func(ctx context.Context, nodeID roachpb.NodeID) (interface{}, error) {
+			statusClient, err := s.dialNode(ctx, nodeID)
+			return statusClient, err
+		}
+		nodeFn := func(ctx context.Context, client interface{}, _ roachpb.NodeID) (interface{}, error) {
+			statusClient := client.(serverpb.StatusClient)
+			return statusClient.LocalLockWaitsGraph(ctx, &serverpb.LockWaitsGraphRequest{})
+		}
+		responseFn := func(_ roachpb.NodeID, nodeResp interface{}) {
+			resp.Edges = append(resp.Edges, nodeResp.(*serverpb.LockWaitsGraphResponse).Edges...)
+		}
+		errorFn := func(nodeID roachpb.NodeID, err error) {
+			resp.ErrorsByNodeID[nodeID] = err.Error()
+		}
+		if err := s.iterateNodes(ctx, "lock waits graph for node",
+			noTimeout, dialFn, nodeFn, responseFn, errorFn,
+		); err != nil {
+			return nil, srverrors.ServerError(ctx, err)
+		}
+	}
+
+	s.resolveLockWaitEdges(ctx, resp.Edges)
+	sortLockWaitEdges(resp.Edges)
+	if shouldRedactKeys {
+		redactLockWaitKeys(resp.Edges)
+	}
+	if req.Format == serverpb.LockWaitsGraphRequest_DOT {
+		resp.DOT = lockWaitsGraphDOT(resp.Edges)
+	}
+	return resp, nil
+}
+
+// LocalLockWaitsGraph returns the edges of the wait-for graph collected from
+// the stores of this node.
+func (s *systemStatusServer) LocalLockWaitsGraph(
+	ctx context.Context, req *serverpb.LockWaitsGraphRequest,
+) (*serverpb.LockWaitsGraphResponse, error) {
+	ctx = s.AnnotateCtx(authserver.ForwardSQLIdentityThroughRPCCalls(ctx))
+
+	if err := s.privilegeChecker.RequireViewActivityOrViewActivityRedactedPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	shouldRedactKeys, err := s.shouldRedactLockWaitKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeID := roachpb.NodeID(s.serverIterator.getID())
+	var edges []serverpb.LockWaitEdge
+	if err := s.stores.VisitStores(func(store *kvserver.Store) error {
+		store.VisitReplicas(func(r *kvserver.Replica) bool {
+			edges = appendReplicaLockWaitEdges(ctx, edges, nodeID, store.StoreID(), r)
+			return ctx.Err() == nil
+		})
+		return ctx.Err()
+	}); err != nil {
+		return nil, srverrors.ServerError(ctx, err)
+	}
+	if shouldRedactKeys {
+		redactLockWaitKeys(edges)
+	}
+	return &serverpb.LockWaitsGraphResponse{Edges: edges}, nil
+}
+
+// shouldRedactLockWaitKeys returns whether the keys of the lock wait edges
+// must be redacted for the current user, which is the case for non-admin
+// users with the VIEWACTIVITYREDACTED role option.
+func (s *systemStatusServer) shouldRedactLockWaitKeys(ctx context.Context) (bool, error) {
+	user, isAdmin, err := s.privilegeChecker.GetUserAndRole(ctx)
+	if err != nil {
+		return false, srverrors.ServerError(ctx, err)
+	}
+	if isAdmin {
+		return false, nil
+	}
+	shouldRedactKeys, err := s.privilegeChecker.HasRoleOption(ctx, user, roleoption.VIEWACTIVITYREDACTED)
+	if err != nil {
+		return false, srverrors.ServerError(ctx, err)
+	}
+	return shouldRedactKeys, nil
+}
+
+// redactLockWaitKeys removes the contended keys from the given edges.
+func redactLockWaitKeys(edges []serverpb.LockWaitEdge) {
+	for i := range edges {
+		edges[i].Key = nil
+	}
+}
+
+// appendReplicaLockWaitEdges appends the edges of the wait-for graph that are
+// tracked by the replica's concurrency manager. Only transactional waiters
+// that are blocked on another transaction contribute an edge.
+func appendReplicaLockWaitEdges(
+	ctx context.Context,
+	edges []serverpb.LockWaitEdge,
+	nodeID roachpb.NodeID,
+	storeID roachpb.StoreID,
+	r *kvserver.Replica,
+) []serverpb.LockWaitEdge {
+	concMgr := r.GetConcurrencyManager()
+	// Only query the keys owned by the replica: its range-local keys (e.g. the
+	// range descriptor) and its global keys.
+	rSpan := r.Desc().RSpan()
+	spans := [...]roachpb.Span{
+		{Key: keys.MakeRangeKeyPrefix(rSpan.Key), EndKey: keys.MakeRangeKeyPrefix(rSpan.EndKey)},
+		rSpan.AsRawSpanWithNoLocals(),
+	}
+	for _, span := range spans {
+		locks, _ := concMgr.QueryLockTableState(ctx, span, concurrency.QueryLockTableOptions{})
+		for _, l := range locks {
+			if l.LockHolder == nil {
+				continue
+			}
+			for _, w := range l.Waiters {
+				if w.WaitingTxn == nil {
+					continue
+				}
+				edges = append(edges, serverpb.LockWaitEdge{
+					Source:                  serverpb.LockWaitEdge_LOCK_TABLE,
+					NodeID:                  nodeID,
+					StoreID:                 storeID,
+					RangeID:                 l.RangeID,
+					Key:                     l.Key,
+					WaiterTxnID:             w.WaitingTxn.ID,
+					WaiterCoordinatorNodeID: roachpb.NodeID(w.WaitingTxn.CoordinatorNodeID),
+					HolderTxnID:             l.LockHolder.ID,
+					HolderCoordinatorNodeID: roachpb.NodeID(l.LockHolder.CoordinatorNodeID),
+					Strength:                w.Strength,
+					WaitDuration:            w.WaitDuration,
+				})
+			}
+		}
+	}
+	for _, push := range concMgr.TxnWaitQueueWaitingPushes() {
+		if push.Pusher.ID == (uuid.UUID{}) {
+			continue
+		}
+		edges = append(edges, serverpb.LockWaitEdge{
+			Source:                  serverpb.LockWaitEdge_TXN_WAIT_QUEUE,
+			NodeID:                  nodeID,
+			StoreID:                 storeID,
+			RangeID:                 r.RangeID,
+			WaiterTxnID:             push.Pusher.ID,
+			WaiterCoordinatorNodeID: roachpb.NodeID(push.Pusher.CoordinatorNodeID),
+			HolderTxnID:             push.Pushee.ID,
+			HolderCoordinatorNodeID: roachpb.NodeID(push.Pushee.CoordinatorNodeID),
+			WaitDuration:            push.WaitDuration,
+		})
+	}
+	return edges
+}
+
+// resolveLockWaitEdges fills in the transaction fingerprint IDs of the
+// waiters and holders of the given edges, as recorded in the transaction ID
+// cache of their coordinator nodes, as well as the fingerprints of the
+// statements most recently executed by the transactions that are still open
+// in a SQL session. Resolution is best effort: transactions that cannot be
+// resolved are left as is.
+func (s *systemStatusServer) resolveLockWaitEdges(
+	ctx context.Context, edges []serverpb.LockWaitEdge,
+) {
+	if len(edges) == 0 {
+		return
+	}
+
+	txnIDsByCoordinator := make(map[roachpb.NodeID]map[uuid.UUID]struct{})
+	addTxnID := func(coordinatorID roachpb.NodeID, txnID uuid.UUID) {
+		if coordinatorID == 0 {
+			return
+		}
+		if _, ok := txnIDsByCoordinator[coordinatorID]; !ok {
+			txnIDsByCoordinator[coordinatorID] = make(map[uuid.UUID]struct{})
+		}
+		txnIDsByCoordinator[coordinatorID][txnID] = struct{}{}
+	}
+	for i := range edges {
+		addTxnID(edges[i].WaiterCoordinatorNodeID, edges[i].WaiterTxnID)
+		addTxnID(edges[i].HolderCoordinatorNodeID, edges[i].HolderTxnID)
+	}
+
+	txnFingerprintIDs := make(map[uuid.UUID]appstatspb.TransactionFingerprintID)
+	for coordinatorID, txnIDs := range txnIDsByCoordinator {
+		req := &serverpb.TxnIDResolutionRequest{
+			CoordinatorID: coordinatorID.String(),
+			TxnIDs:        make([]uuid.UUID, 0, len(txnIDs)),
+		}
+		for txnID := range txnIDs {
+			req.TxnIDs = append(req.TxnIDs, txnID)
+		}
+		resp, err := s.TxnIDResolution(ctx, req)
+		if err != nil {
+			log.VEventf(ctx, 2, "failed to resolve txn IDs on node %d: %v", coordinatorID, err)
+			continue
+		}
+		for _, resolved := range resp.ResolvedTxnIDs {
+			txnFingerprintIDs[resolved.TxnID] = resolved.TxnFingerprintID
+		}
+	}
+
+	statements := make(map[uuid.UUID]string)
+	if sessions, err := s.ListSessions(ctx, &serverpb.ListSessionsRequest{}); err != nil {
+		log.VEventf(ctx, 2, "failed to list sessions: %v", err)
+	} else {
+		for _, session := range sessions.Sessions {
+			if session.ActiveTxn == nil {
+				continue
+			}
+			stmt := session.LastActiveQueryNoConstants
+			for _, query := range session.ActiveQueries {
+				if query.TxnID == session.ActiveTxn.ID {
+					stmt = query.SqlNoConstants
+					break
+				}
+			}
+			statements[session.ActiveTxn.ID] = stmt
+		}
+	}
+
+	for i := range edges {
+		e := &edges[i]
+		e.WaiterTxnFingerprintID = txnFingerprintIDs[e.WaiterTxnID]
+		e.HolderTxnFingerprintID = txnFingerprintIDs[e.HolderTxnID]
+		e.WaiterStatement = statements[e.WaiterTxnID]
+		e.HolderStatement = statements[e.HolderTxnID]
+	}
+}
+
+// sortLockWaitEdges sorts the edges by decreasing wait duration, so that the
+// longest waits are listed first.
+func sortLockWaitEdges(edges []serverpb.LockWaitEdge) {
+	sort.SliceStable(edges, func(i, j int) bool {
+		if edges[i].WaitDuration != edges[j].WaitDuration {
+			return edges[i].WaitDuration > edges[j].WaitDuration
+		}
+		if c := bytes.Compare(edges[i].WaiterTxnID.GetBytes(), edges[j].WaiterTxnID.GetBytes()); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(edges[i].HolderTxnID.GetBytes(), edges[j].HolderTxnID.GetBytes()) < 0
+	})
+}
+
+// lockWaitsGraphDOT renders the wait-for graph in the Graphviz DOT language.
+// Each transaction is a vertex labeled with its short ID and the fingerprint
+// of its most recent statement, and each edge points from a waiter to the
+// transaction it is waiting on.
+func lockWaitsGraphDOT(edges []serverpb.LockWaitEdge) string {
+	var buf strings.Builder
+	buf.WriteString("digraph lock_waits {\n")
+	buf.WriteString("  node [shape=box];\n")
+	seen := make(map[uuid.UUID]struct{})
+	addVertex := func(txnID uuid.UUID, stmt string) {
+		if _, ok := seen[txnID]; ok {
+			return
+		}
+		seen[txnID] = struct{}{}
+		label := txnID.Short()
+		if stmt != "" {
+			label += "\n" + stmt
+		}
+		fmt.Fprintf(&buf, "  %q [label=%q];\n", txnID.String(), label)
+	}
+	for i := range edges {
+		addVertex(edges[i].WaiterTxnID, edges[i].WaiterStatement)
+		addVertex(edges[i].HolderTxnID, edges[i].HolderStatement)
+	}
+	for i := range edges {
+		e := &edges[i]
+		label := fmt.Sprintf("r%d %s\n%s", e.RangeID, e.Source, e.WaitDuration)
+		if e.Source == serverpb.LockWaitEdge_LOCK_TABLE {
+			label = fmt.Sprintf("r%d %s %s\n%s", e.RangeID, e.Source, e.Strength, e.WaitDuration)
+		}
+		fmt.Fprintf(&buf, "  %q -> %q [label=%q];\n",
+			e.WaiterTxnID.String(), e.HolderTxnID.String(), label)
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
@@ -0,0 +1,70 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uint128"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockWaitsGraphDOT(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	txn1 := uuid.FromUint128(uint128.Uint128{Lo: 1})
+	txn2 := uuid.FromUint128(uint128.Uint128{Lo: 2})
+	txn3 := uuid.FromUint128(uint128.Uint128{Lo: 3})
+	edges := []serverpb.LockWaitEdge{
+		{
+			Source:          serverpb.LockWaitEdge_TXN_WAIT_QUEUE,
+			RangeID:         5,
+			WaiterTxnID:     txn3,
+			HolderTxnID:     txn1,
+			WaitDuration:    time.Second,
+			HolderStatement: "UPDATE t SET v = _ WHERE k = _",
+		},
+		{
+			Source:          serverpb.LockWaitEdge_LOCK_TABLE,
+			RangeID:         4,
+			Key:             roachpb.Key("a"),
+			WaiterTxnID:     txn2,
+			HolderTxnID:     txn1,
+			Strength:        lock.Exclusive,
+			WaitDuration:    2 * time.Second,
+			WaiterStatement: "DELETE FROM t WHERE k = _",
+			HolderStatement: "UPDATE t SET v = _ WHERE k = _",
+		},
+	}
+
+	// The longest waits are listed first.
+	sortLockWaitEdges(edges)
+	require.Equal(t, txn2, edges[0].WaiterTxnID)
+	require.Equal(t, txn3, edges[1].WaiterTxnID)
+
+	require.Equal(t, `digraph lock_waits {
+  node [shape=box];
+  "00000000-0000-0000-0000-000000000002" [label="00000000\nDELETE FROM t WHERE k = _"];
+  "00000000-0000-0000-0000-000000000001" [label="00000000\nUPDATE t SET v = _ WHERE k = _"];
+  "00000000-0000-0000-0000-000000000003" [label="00000000"];
+  "00000000-0000-0000-0000-000000000002" -> "00000000-0000-0000-0000-000000000001" [label="r4 LOCK_TABLE Exclusive\n2s"];
+  "00000000-0000-0000-0000-000000000003" -> "00000000-0000-0000-0000-000000000001" [label="r5 TXN_WAIT_QUEUE\n1s"];
+}
+`, lockWaitsGraphDOT(edges))
+}
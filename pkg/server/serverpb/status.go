@@ -72,6 +72,7 @@ func MakeOptionalNodesStatusServer(s NodesStatusServer) OptionalNodesStatusServe
 // It is unavailable to tenants.
 type NodesStatusServer interface {
 	ListNodesInternal(context.Context, *NodesRequest) (*NodesResponse, error)
+	LockWaitsGraph(context.Context, *LockWaitsGraphRequest) (*LockWaitsGraphResponse, error)
 }
 
 // TenantStatusServer is the subset of the serverpb.StatusServer that is
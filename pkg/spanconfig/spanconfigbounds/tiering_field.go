@@ -0,0 +1,94 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package spanconfigbounds
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities/tenantcapabilitiespb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+type tieringField int
+
+var _ field[*roachpb.TieringPolicy] = tieringField(0)
+
+func (f tieringField) String() string {
+	return config.Field(f).String()
+}
+
+func (f tieringField) SafeFormat(s redact.SafePrinter, verb rune) {
+	s.Print(config.Field(f))
+}
+
+func (f tieringField) FieldValue(sc *roachpb.SpanConfig) Value {
+	return tieringValue{p: *f.fieldValue(sc)}
+}
+
+func (f tieringField) FieldBound(b *Bounds) ValueBounds {
+	if b.ConstraintBounds == nil {
+		return unbounded{}
+	}
+	switch f {
+	case tiering:
+		return (*tieringBound)(b.ConstraintBounds)
+	default:
+		// This is safe because we test that all the fields in the proto have
+		// a corresponding field, and we call this for each of them, and the user
+		// never provides the input to this function.
+		panic(errors.AssertionFailedf("failed to look up field spanConfigBound %s", f))
+	}
+}
+
+func (f tieringField) fieldValue(c *roachpb.SpanConfig) **roachpb.TieringPolicy {
+	switch f {
+	case tiering:
+		return &c.Tiering
+	default:
+		// This is safe because we test that all the fields in the proto have
+		// a corresponding field, and we call this for each of them, and the user
+		// never provides the input to this function.
+		panic(errors.AssertionFailedf("failed to look up field %s", f))
+	}
+}
+
+type tieringBound tenantcapabilitiespb.SpanConfigBounds_ConstraintBounds
+
+func (c *tieringBound) SafeFormat(s redact.SafePrinter, verb rune) {
+	(*constraintsConjunctionBounds)(c).SafeFormat(s, verb)
+}
+
+func (c *tieringBound) String() string {
+	return (*constraintsConjunctionBounds)(c).String()
+}
+
+// conforms returns true if both the hot and the cold constraints of the
+// tiering policy only reference allowed constraints.
+func (c *tieringBound) conforms(t *roachpb.SpanConfig, f Field) (conforms bool) {
+	p := *f.(field[*roachpb.TieringPolicy]).fieldValue(t)
+	if p == nil {
+		return true
+	}
+	s := sortedConstraints(c.Allowed)
+	return s.conjunctionConforms(p.Hot) && s.conjunctionConforms(p.Cold)
+}
+
+// clamp removes a tiering policy which does not conform with the bounds. There
+// is no sensible fallback for the tiers, so the range is instead placed
+// according to its other constraints.
+func (c *tieringBound) clamp(t *roachpb.SpanConfig, f Field) (changed bool) {
+	if c.conforms(t, f) {
+		return false
+	}
+	*f.(field[*roachpb.TieringPolicy]).fieldValue(t) = nil
+	return true
+}
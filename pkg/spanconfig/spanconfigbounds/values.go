@@ -69,3 +69,22 @@ func (b boolValue) String() string {
 func (b boolValue) SafeFormat(s interfaces.SafePrinter, verb rune) {
 	s.Print(bool(b))
 }
+
+type tieringValue struct {
+	p *roachpb.TieringPolicy
+}
+
+func (t tieringValue) String() string {
+	return redact.StringWithoutMarkers(t)
+}
+func (t tieringValue) SafeFormat(s interfaces.SafePrinter, verb rune) {
+	if t.p == nil {
+		s.Printf("none")
+		return
+	}
+	s.Printf("{hot: ")
+	formatConstraints(s, t.p.Hot)
+	s.Printf(", cold: ")
+	formatConstraints(s, t.p.Cold)
+	s.Printf(", cold_after: %s}", t.p.ColdAfter())
+}
@@ -662,6 +662,10 @@ func PrintSpanConfigDiffedAgainstDefaults(conf roachpb.SpanConfig) string {
 	if conf.ExcludeDataFromBackup != defaultConf.ExcludeDataFromBackup {
 		diffs = append(diffs, fmt.Sprintf("exclude_data_from_backup=%v", conf.ExcludeDataFromBackup))
 	}
+	if !conf.Tiering.Equal(defaultConf.Tiering) {
+		diffs = append(diffs, fmt.Sprintf("tiering={hot=%v cold=%v cold_after=%s}",
+			conf.Tiering.Hot, conf.Tiering.Cold, conf.Tiering.ColdAfter()))
+	}
 
 	return strings.Join(diffs, " ")
 }
@@ -183,6 +183,10 @@ func (n *alterTableNode) startExec(params runParams) error {
 			return errors.Newf("table %q does not have a primary key, cannot perform%s", n.tableDesc.Name, tree.AsString(cmd))
 		}
 
+		if err := checkAlterTableCmdAllowedWithInheritance(n.tableDesc, cmd); err != nil {
+			return err
+		}
+
 		switch t := cmd.(type) {
 		case *tree.AlterTableAddColumn:
 			if t.ColumnDef.Unique.WithoutIndex {
@@ -466,6 +470,22 @@ func (n *alterTableNode) startExec(params runParams) error {
 				// 	return err
 				// }
 
+			case *tree.ExclusionConstraintTableDef:
+				if t.ValidationBehavior == tree.ValidationSkip {
+					return sqlerrors.NewUnsupportedUnvalidatedConstraintError(catconstants.ConstraintTypeExclusion)
+				}
+				if err := addExclusionConstraintTableDef(
+					params.ctx,
+					params.EvalContext(),
+					d,
+					n.tableDesc,
+					*tn,
+					NonEmptyTable,
+					params.p.SemaCtx(),
+				); err != nil {
+					return err
+				}
+
 			default:
 				return errors.AssertionFailedf(
 					"unsupported constraint: %T", t.ConstraintDef)
@@ -828,6 +848,33 @@ func (n *alterTableNode) startExec(params runParams) error {
 				return err
 			}
 			descriptorChanged = true
+
+		case *tree.AlterTableInherit:
+			if err := params.p.alterTableInherit(params.ctx, n.tableDesc, &t.Parent); err != nil {
+				return err
+			}
+			descriptorChanged = true
+
+		case *tree.AlterTableNoInherit:
+			if err := params.p.alterTableNoInherit(params.ctx, n.tableDesc, &t.Parent); err != nil {
+				return err
+			}
+			descriptorChanged = true
+
+		case *tree.AlterTableAttachPartition:
+			if err := params.p.alterTableAttachPartition(
+				params.ctx, n.tableDesc, &t.Partition, t.Bound,
+			); err != nil {
+				return err
+			}
+			descriptorChanged = true
+
+		case *tree.AlterTableDetachPartition:
+			if err := params.p.alterTableDetachPartition(params.ctx, n.tableDesc, &t.Partition); err != nil {
+				return err
+			}
+			descriptorChanged = true
+
 		default:
 			return errors.AssertionFailedf("unsupported alter command: %T", cmd)
 		}
@@ -1444,6 +1491,9 @@ func validateConstraintNameIsNotUsed(
 	case *tree.ForeignKeyConstraintTableDef:
 		name = d.Name
 		hasIfNotExists = d.IfNotExists
+	case *tree.ExclusionConstraintTableDef:
+		name = d.Name
+		hasIfNotExists = d.IfNotExists
 	case *tree.UniqueConstraintTableDef:
 		name = d.Name
 		hasIfNotExists = d.IfNotExists
@@ -1776,6 +1826,32 @@ func dropColumnImpl(
 		}
 	}
 
+	// Drop exclusion constraints which reference the column, either as a key
+	// column or in their predicate.
+	for _, ec := range tableDesc.EnforcedExclusionConstraints() {
+		if ec.Dropped() {
+			continue
+		}
+		referencesCol := ec.CollectKeyColumnIDs().Contains(colToDrop.GetID())
+		if !referencesCol && ec.IsPartial() {
+			expr, err := parser.ParseExpr(ec.GetPredicate())
+			if err != nil {
+				return nil, err
+			}
+			colIDs, err := schemaexpr.ExtractColumnIDs(tableDesc, expr)
+			if err != nil {
+				return nil, err
+			}
+			referencesCol = colIDs.Contains(colToDrop.GetID())
+		}
+		if !referencesCol {
+			continue
+		}
+		if err := tableDesc.DropConstraint(ec, nil /* removeFKBackRef */, nil /* removeFnBackRef */); err != nil {
+			return nil, err
+		}
+	}
+
 	// Drop check constraints which reference the column.
 	for _, check := range tableDesc.CheckConstraints() {
 		if check.Dropped() {
@@ -534,6 +534,23 @@ func (sc *SchemaChanger) dropConstraints(
 						constraint,
 					)
 				}
+			} else if constraint.AsExclusion() != nil {
+				found := false
+				for j, c := range scTable.Exclusions {
+					if c.Name == constraint.GetName() {
+						scTable.Exclusions = append(scTable.Exclusions[:j], scTable.Exclusions[j+1:]...)
+						found = true
+						break
+					}
+				}
+				if !found {
+					log.VEventf(
+						ctx, 2,
+						"backfiller tried to drop constraint %s but it was not found, "+
+							"presumably due to a retry or rollback",
+						constraint,
+					)
+				}
 			}
 		}
 		if err := txn.Descriptors().WriteDescToBatch(
@@ -711,6 +728,27 @@ func (sc *SchemaChanger) addConstraints(
 					scTable.UniqueWithoutIndexConstraints =
 						append(scTable.UniqueWithoutIndexConstraints, *uwi.UniqueWithoutIndexDesc())
 				}
+			} else if ec := constraint.AsExclusion(); ec != nil {
+				found := false
+				for i := range scTable.Exclusions {
+					c := &scTable.Exclusions[i]
+					if c.Name == constraint.GetName() {
+						log.VEventf(
+							ctx, 2,
+							"backfiller tried to add constraint %s but found existing constraint %+v, "+
+								"presumably due to a retry or rollback",
+							constraint, c,
+						)
+						// Ensure the constraint on the descriptor is set to Validating, in
+						// case we're in the middle of rolling back DROP CONSTRAINT
+						c.Validity = descpb.ConstraintValidity_Validating
+						found = true
+						break
+					}
+				}
+				if !found {
+					scTable.Exclusions = append(scTable.Exclusions, *ec.ExclusionDesc())
+				}
 			}
 		}
 		if err := txn.Descriptors().WriteDescToBatch(
@@ -823,6 +861,10 @@ func (sc *SchemaChanger) validateConstraints(
 					if err := validateUniqueWithoutIndexConstraintInTxn(ctx, txn, desc, evalCtx.SessionData().User(), c.GetName()); err != nil {
 						return err
 					}
+				} else if c.AsExclusion() != nil {
+					if err := validateExclusionConstraintInTxn(ctx, txn, desc, evalCtx.SessionData().User(), c.GetName()); err != nil {
+						return err
+					}
 				} else {
 					return errors.Errorf("unsupported constraint type: %s", c)
 				}
@@ -1633,6 +1675,16 @@ func ValidateConstraint(
 					)
 				},
 			)
+		case catconstants.ConstraintTypeExclusion:
+			ec := constraint.AsExclusion()
+			return txn.WithSyntheticDescriptors(
+				[]catalog.Descriptor{tableDesc},
+				func() error {
+					return validateExclusionConstraint(
+						ctx, tableDesc, ec.ExclusionDesc(), txn, sessionData.User(),
+					)
+				},
+			)
 		default:
 			return errors.AssertionFailedf("validation of unsupported constraint type")
 		}
@@ -1825,7 +1877,14 @@ func countExpectedRowsForInvertedIndex(
 	) error {
 		var stmt string
 		geoConfig := idx.GetGeoConfig()
-		if geoConfig.IsEmpty() {
+		vectorConfig := idx.GetVectorConfig()
+		if !vectorConfig.IsEmpty() {
+			// Each non-NULL vector has exactly one index entry.
+			stmt = fmt.Sprintf(
+				`SELECT count(%s) FROM [%d AS t]`,
+				colNameOrExpr, desc.GetID(),
+			)
+		} else if geoConfig.IsEmpty() {
 			stmt = fmt.Sprintf(
 				`SELECT coalesce(sum_int(crdb_internal.num_inverted_index_entries(%s, %d)), 0) FROM [%d AS t]`,
 				colNameOrExpr, idx.GetVersion(), desc.GetID(),
@@ -2554,6 +2613,13 @@ func runSchemaChangesInTxn(
 							break
 						}
 					}
+				} else if c.AsExclusion() != nil {
+					for i := range tableDesc.Exclusions {
+						if tableDesc.Exclusions[i].Name == c.GetName() {
+							tableDesc.Exclusions = append(tableDesc.Exclusions[:i], tableDesc.Exclusions[i+1:]...)
+							break
+						}
+					}
 				} else {
 					return errors.AssertionFailedf("unsupported constraint type: %s", c)
 				}
@@ -2583,6 +2649,9 @@ func runSchemaChangesInTxn(
 		} else if uwi := c.AsUniqueWithoutIndex(); uwi != nil {
 			ctu.ConstraintType = descpb.ConstraintToUpdate_UNIQUE_WITHOUT_INDEX
 			ctu.UniqueWithoutIndexConstraint = *uwi.UniqueWithoutIndexDesc()
+		} else if ec := c.AsExclusion(); ec != nil {
+			ctu.ConstraintType = descpb.ConstraintToUpdate_EXCLUSION
+			ctu.ExclusionConstraint = *ec.ExclusionDesc()
 		} else {
 			return errors.AssertionFailedf("unknown constraint type: %s", c)
 		}
@@ -2637,6 +2706,19 @@ func runSchemaChangesInTxn(
 				}
 				uwi.UniqueWithoutIndexDesc().Validity = descpb.ConstraintValidity_Validated
 			}
+		} else if ec := c.AsExclusion(); ec != nil {
+			if ec.GetConstraintValidity() == descpb.ConstraintValidity_Validating {
+				if err := validateExclusionConstraintInTxn(
+					ctx,
+					planner.InternalSQLTxn(),
+					tableDesc,
+					planner.User(),
+					c.GetName(),
+				); err != nil {
+					return err
+				}
+				ec.ExclusionDesc().Validity = descpb.ConstraintValidity_Validated
+			}
 		} else {
 			return errors.AssertionFailedf("unsupported constraint type: %s", c)
 		}
@@ -2678,6 +2760,8 @@ func runSchemaChangesInTxn(
 			}
 		} else if uwi := c.AsUniqueWithoutIndex(); uwi != nil {
 			tableDesc.UniqueWithoutIndexConstraints = append(tableDesc.UniqueWithoutIndexConstraints, *uwi.UniqueWithoutIndexDesc())
+		} else if ec := c.AsExclusion(); ec != nil {
+			tableDesc.Exclusions = append(tableDesc.Exclusions, *ec.ExclusionDesc())
 		} else {
 			return errors.AssertionFailedf("unsupported constraint type: %s", c)
 		}
@@ -2838,6 +2922,42 @@ func validateUniqueWithoutIndexConstraintInTxn(
 		})
 }
 
+// validateExclusionConstraintInTxn validates an exclusion constraint within
+// the provided transaction. If the provided table descriptor version is newer
+// than the cluster version, it will be used in the InternalExecutor that
+// performs the validation query.
+//
+// It operates entirely on the current goroutine and is thus able to
+// reuse an existing kv.Txn safely.
+func validateExclusionConstraintInTxn(
+	ctx context.Context,
+	txn isql.Txn,
+	tableDesc *tabledesc.Mutable,
+	user username.SQLUsername,
+	constraintName string,
+) error {
+	var syntheticDescs []catalog.Descriptor
+	if tableDesc.Version > tableDesc.ClusterVersion().Version {
+		syntheticDescs = append(syntheticDescs, tableDesc)
+	}
+	var ec *descpb.ExclusionConstraint
+	for _, c := range tableDesc.ExclusionConstraints() {
+		if c.GetName() == constraintName {
+			ec = c.ExclusionDesc()
+			break
+		}
+	}
+	if ec == nil {
+		return errors.AssertionFailedf("exclusion constraint %s does not exist", constraintName)
+	}
+
+	return txn.WithSyntheticDescriptors(
+		syntheticDescs,
+		func() error {
+			return validateExclusionConstraint(ctx, tableDesc, ec, txn, user)
+		})
+}
+
 // columnBackfillInTxn backfills columns for all mutation columns in
 // the mutation list.
 //
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/errors"
 )
 
@@ -333,6 +334,17 @@ func formatStorageConfigs(
 		}
 	}
 
+	if index.VectorConfig.Lists != 0 && index.VectorConfig.Lists != vecindex.DefaultLists {
+		if numCustomSettings > 0 {
+			f.WriteString(", ")
+		} else {
+			f.WriteString(" WITH (")
+		}
+		numCustomSettings++
+		f.WriteString(`lists=`)
+		f.WriteString(strconv.Itoa(int(index.VectorConfig.Lists)))
+	}
+
 	if index.IsSharded() {
 		if numCustomSettings > 0 {
 			f.WriteString(", ")
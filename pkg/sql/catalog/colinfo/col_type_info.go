@@ -99,6 +99,11 @@ func ValidateColumnDefType(ctx context.Context, version clusterversion.Handle, t
 			return unimplemented.NewWithIssueDetailf(23468, t.String(),
 				"arrays of JSON unsupported as column type")
 		}
+		if t.ArrayContents().Family() == types.JsonpathFamily {
+			// JSONPATH arrays are not supported as a column type.
+			return unimplemented.NewWithIssueDetailf(22513, t.String(),
+				"arrays of JSONPATH unsupported as column type")
+		}
 		if err := types.CheckArrayElementType(t.ArrayContents()); err != nil {
 			return err
 		}
@@ -132,6 +137,22 @@ func ValidateColumnDefType(ctx context.Context, version clusterversion.Handle, t
 			)
 		}
 
+	case types.JsonpathFamily:
+		if !version.IsActive(ctx, clusterversion.V23_2_Jsonpath) {
+			return pgerror.Newf(
+				pgcode.FeatureNotSupported,
+				"jsonpath not supported until version 23.2",
+			)
+		}
+
+	case types.PGVectorFamily:
+		if !version.IsActive(ctx, clusterversion.V23_2_PGVector) {
+			return pgerror.Newf(
+				pgcode.FeatureNotSupported,
+				"vector not supported until version 23.2",
+			)
+		}
+
 	default:
 		return pgerror.Newf(pgcode.InvalidTableDefinition,
 			"value type %s cannot be used for table columns", t.String())
@@ -173,6 +194,7 @@ func ColumnTypeIsOnlyInvertedIndexable(t *types.T) bool {
 	case types.GeographyFamily:
 	case types.GeometryFamily:
 	case types.TSVectorFamily:
+	case types.PGVectorFamily:
 	default:
 		return false
 	}
@@ -192,7 +214,7 @@ func MustBeValueEncoded(semanticType *types.T) bool {
 		}
 	case types.TupleFamily, types.GeographyFamily, types.GeometryFamily:
 		return true
-	case types.TSVectorFamily, types.TSQueryFamily:
+	case types.TSVectorFamily, types.TSQueryFamily, types.JsonpathFamily, types.PGVectorFamily:
 		return true
 	}
 	return false
@@ -86,7 +86,9 @@ func CanHaveCompositeKeyEncoding(typ *types.T) bool {
 		types.VoidFamily,
 		types.EncodedKeyFamily,
 		types.TSQueryFamily,
-		types.TSVectorFamily:
+		types.TSVectorFamily,
+		types.JsonpathFamily,
+		types.PGVectorFamily:
 		return false
 	case types.UnknownFamily,
 		types.AnyFamily:
@@ -12,6 +12,7 @@ package catalog
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -629,6 +630,17 @@ type TableDescriptor interface {
 	// GetDependsOnFunctions returns the IDs of all functions that this view
 	// depends on. It's only non-nil if IsView is true.
 	GetDependsOnFunctions() []descpb.ID
+	// GetInherits returns the IDs of the tables this table inherits from.
+	GetInherits() []descpb.ID
+	// GetInheritedBy returns the IDs of the tables which inherit from this
+	// table.
+	GetInheritedBy() []descpb.ID
+	// GetPartitionKey returns the partition key of a table which is partitioned
+	// into separate tables, or nil if it isn't.
+	GetPartitionKey() *descpb.TableDescriptor_PartitionKey
+	// GetPartitionBound returns the bounds of a table which is a partition of
+	// a partitioned table, or nil if it isn't one.
+	GetPartitionBound() *descpb.TableDescriptor_PartitionBound
 
 	// AllConstraints returns all constraints in this table, regardless if
 	// they're enforced yet or not. The ordering of the constraints within this
@@ -680,6 +692,13 @@ type TableDescriptor interface {
 	// in the same order.
 	EnforcedUniqueConstraintsWithoutIndex() []UniqueWithoutIndexConstraint
 
+	// ExclusionConstraints returns the subset of exclusion constraints in
+	// AllConstraints for this table, in the same order.
+	ExclusionConstraints() []ExclusionConstraint
+	// EnforcedExclusionConstraints returns the subset of exclusion constraints
+	// in EnforcedConstraints for this table, in the same order.
+	EnforcedExclusionConstraints() []ExclusionConstraint
+
 	// CheckConstraintColumns returns the slice of columns referenced by a check
 	// constraint.
 	CheckConstraintColumns(ck CheckConstraint) []Column
@@ -759,6 +778,10 @@ type TableDescriptor interface {
 	// IsSchemaLocked returns true if we don't allow performing schema changes
 	// on this table descriptor.
 	IsSchemaLocked() bool
+	// GetLockTimeout returns the default lock timeout for statements reading or
+	// writing this table, or zero if the lock_timeout storage parameter is not
+	// set.
+	GetLockTimeout() time.Duration
 }
 
 // MutableTableDescriptor is both a MutableDescriptor and a TableDescriptor.
@@ -65,6 +65,10 @@ func redactTableDescriptor(d *descpb.TableDescriptor) (errs []error) {
 		uwi := &d.UniqueWithoutIndexConstraints[i]
 		handleErr(errors.Wrapf(redactUniqueWithoutIndexConstraint(uwi), "constraint #%d", uwi.ConstraintID))
 	}
+	for i := range d.Exclusions {
+		ec := &d.Exclusions[i]
+		handleErr(errors.Wrapf(redactExclusionConstraint(ec), "constraint #%d", ec.ConstraintID))
+	}
 	for _, m := range d.Mutations {
 		if idx := m.GetIndex(); idx != nil {
 			handleErr(errors.Wrapf(redactIndex(idx), "index #%d", idx.ID))
@@ -80,6 +84,9 @@ func redactTableDescriptor(d *descpb.TableDescriptor) (errs []error) {
 			case descpb.ConstraintToUpdate_UNIQUE_WITHOUT_INDEX:
 				uwi := &ctu.UniqueWithoutIndexConstraint
 				handleErr(errors.Wrapf(redactUniqueWithoutIndexConstraint(uwi), "constraint #%d", uwi.ConstraintID))
+			case descpb.ConstraintToUpdate_EXCLUSION:
+				ec := &ctu.ExclusionConstraint
+				handleErr(errors.Wrapf(redactExclusionConstraint(ec), "constraint #%d", ec.ConstraintID))
 			}
 		}
 	}
@@ -139,6 +146,10 @@ func redactUniqueWithoutIndexConstraint(uwi *descpb.UniqueWithoutIndexConstraint
 	return redactExprStr(&uwi.Predicate)
 }
 
+func redactExclusionConstraint(ec *descpb.ExclusionConstraint) error {
+	return redactExprStr(&ec.Predicate)
+}
+
 func redactTypeDescriptor(d *descpb.TypeDescriptor) {
 	for i := range d.EnumMembers {
 		e := &d.EnumMembers[i]
@@ -191,6 +191,26 @@ func TableDescs(
 			}
 		}
 
+		// Inheritance relationships with tables which are not being restored
+		// are dropped, which turns restored partitions into standalone tables.
+		origInherits := table.Inherits
+		table.Inherits = nil
+		for _, id := range origInherits {
+			if rewrite, ok := descriptorRewrites[id]; ok {
+				table.Inherits = append(table.Inherits, rewrite.ID)
+			}
+		}
+		if len(table.Inherits) == 0 {
+			table.PartitionBound = nil
+		}
+		origInheritedBy := table.InheritedBy
+		table.InheritedBy = nil
+		for _, id := range origInheritedBy {
+			if rewrite, ok := descriptorRewrites[id]; ok {
+				table.InheritedBy = append(table.InheritedBy, rewrite.ID)
+			}
+		}
+
 		// Rewrite unique_without_index in both `UniqueWithoutIndexConstraints`
 		// and `Mutations` slice.
 		origUniqueWithoutIndexConstraints := table.UniqueWithoutIndexConstraints
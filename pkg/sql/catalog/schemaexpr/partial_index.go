@@ -184,12 +184,35 @@ func makePartialIndexHelper(
 	}
 }
 
+// MakePredicateExpr turns a serialized boolean predicate over the public
+// columns of a table, such as the one returned by DequalifyAndValidateExpr,
+// into a TypedExpr. It also returns the set of column IDs referenced in the
+// predicate. The returned expression can be evaluated with a
+// RowIndexedVarContainer over the public columns of the table.
+func MakePredicateExpr(
+	ctx context.Context,
+	table catalog.TableDescriptor,
+	predicate string,
+	evalCtx *eval.Context,
+	semaCtx *tree.SemaContext,
+) (tree.TypedExpr, catalog.TableColSet, error) {
+	h := makePartialIndexHelper(table, table.PublicColumns(), evalCtx, semaCtx)
+	return h.makePredicateExpr(ctx, predicate)
+}
+
 // makePartialIndexExpr turns an index's partial index predicate from a string to
 // a TypedExpr.
 func (pi partialIndexHelper) makePartialIndexExpr(
 	ctx context.Context, idx catalog.Index,
 ) (tree.TypedExpr, catalog.TableColSet, error) {
-	expr, err := parser.ParseExpr(idx.GetPredicate())
+	return pi.makePredicateExpr(ctx, idx.GetPredicate())
+}
+
+// makePredicateExpr turns a predicate from a string to a TypedExpr.
+func (pi partialIndexHelper) makePredicateExpr(
+	ctx context.Context, predicate string,
+) (tree.TypedExpr, catalog.TableColSet, error) {
+	expr, err := parser.ParseExpr(predicate)
 	if err != nil {
 		return nil, catalog.TableColSet{}, err
 	}
@@ -55,3 +55,31 @@ func ValidateUniqueWithoutIndexPredicate(
 	}
 	return expr, nil
 }
+
+// ValidateExclusionConstraintPredicate verifies that an expression is a valid
+// exclusion constraint predicate. The same rules as for unique without index
+// predicates apply, see ValidateUniqueWithoutIndexPredicate.
+func ValidateExclusionConstraintPredicate(
+	ctx context.Context,
+	tn tree.TableName,
+	desc catalog.TableDescriptor,
+	pred tree.Expr,
+	semaCtx *tree.SemaContext,
+	version clusterversion.ClusterVersion,
+) (string, error) {
+	expr, _, _, err := DequalifyAndValidateExpr(
+		ctx,
+		desc,
+		pred,
+		types.Bool,
+		tree.ExclusionConstraintPredicateExpr,
+		semaCtx,
+		volatility.Immutable,
+		&tn,
+		version,
+	)
+	if err != nil {
+		return "", err
+	}
+	return expr, nil
+}
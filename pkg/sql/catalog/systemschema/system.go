@@ -1007,6 +1007,56 @@ CREATE TABLE system.span_stats_tenant_boundaries (
 	  FAMILY "primary" (crdb_region, unavailable_at)
 ) ;
 `
+
+	// NotificationsTableSchema stores the notifications sent by NOTIFY and
+	// pg_notify. Rows are written by the notifying transaction and are
+	// delivered to listening sessions through a rangefeed, so listeners only
+	// observe notifications sent by committed transactions. The channel and
+	// payload are part of the primary key so that identical notifications sent
+	// by the same transaction are collapsed into one.
+	NotificationsTableSchema = `
+CREATE TABLE system.notifications (
+	txn_id     UUID NOT NULL,
+	channel    STRING NOT NULL,
+	payload    STRING NOT NULL,
+	sender_pid INT4 NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now():::TIMESTAMPTZ,
+	CONSTRAINT "primary" PRIMARY KEY (txn_id, channel, payload),
+	FAMILY "primary" (txn_id, channel, payload, sender_pid, created_at)
+);`
+
+	// PublicationsTableSchema stores the publications created by CREATE
+	// PUBLICATION. A publication names the tables of a database whose changes
+	// are streamed to logical replication clients.
+	PublicationsTableSchema = `
+CREATE TABLE system.publications (
+	database_id INT8 NOT NULL,
+	name        STRING NOT NULL,
+	owner       STRING NOT NULL,
+	owner_id    OID NOT NULL,
+	all_tables  BOOL NOT NULL,
+	table_ids   INT8[] NOT NULL,
+	created_at  TIMESTAMPTZ NOT NULL DEFAULT now():::TIMESTAMPTZ,
+	CONSTRAINT "primary" PRIMARY KEY (database_id, name),
+	FAMILY "primary" (database_id, name, owner, owner_id, all_tables, table_ids, created_at)
+);`
+
+	// ReplicationSlotsTableSchema stores the logical replication slots created
+	// with CREATE_REPLICATION_SLOT. confirmed_flush_lsn is the position up to
+	// which the slot's client has confirmed receiving changes, from which
+	// streaming resumes.
+	ReplicationSlotsTableSchema = `
+CREATE TABLE system.replication_slots (
+	slot_name           STRING NOT NULL,
+	database_id         INT8 NOT NULL,
+	plugin              STRING NOT NULL,
+	owner               STRING NOT NULL,
+	owner_id            OID NOT NULL,
+	confirmed_flush_lsn PG_LSN NOT NULL,
+	created_at          TIMESTAMPTZ NOT NULL DEFAULT now():::TIMESTAMPTZ,
+	CONSTRAINT "primary" PRIMARY KEY (slot_name),
+	FAMILY "primary" (slot_name, database_id, plugin, owner, owner_id, confirmed_flush_lsn, created_at)
+);`
 )
 
 func pk(name string) descpb.IndexDescriptor {
@@ -1237,6 +1287,9 @@ func MakeSystemTables() []SystemTable {
 		StatementActivityTable,
 		TransactionActivityTable,
 		RegionLivenessTable,
+		NotificationsTable,
+		PublicationsTable,
+		ReplicationSlotsTable,
 	}
 }
 
@@ -4103,6 +4156,110 @@ var (
 			},
 		),
 	)
+
+	// NotificationsTable is the descriptor for the notifications table.
+	NotificationsTable = makeSystemTable(
+		NotificationsTableSchema,
+		systemTable(
+			catconstants.NotificationsTableName,
+			descpb.InvalidID, // dynamically assigned
+			[]descpb.ColumnDescriptor{
+				{Name: "txn_id", ID: 1, Type: types.Uuid},
+				{Name: "channel", ID: 2, Type: types.String},
+				{Name: "payload", ID: 3, Type: types.String},
+				{Name: "sender_pid", ID: 4, Type: types.Int4},
+				{Name: "created_at", ID: 5, Type: types.TimestampTZ, DefaultExpr: &nowTZString},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:        "primary",
+					ID:          0,
+					ColumnNames: []string{"txn_id", "channel", "payload", "sender_pid", "created_at"},
+					ColumnIDs:   []descpb.ColumnID{1, 2, 3, 4, 5},
+				},
+			},
+			descpb.IndexDescriptor{
+				Name:           "primary",
+				ID:             1,
+				Unique:         true,
+				KeyColumnNames: []string{"txn_id", "channel", "payload"},
+				KeyColumnDirections: []catenumpb.IndexColumn_Direction{
+					catenumpb.IndexColumn_ASC, catenumpb.IndexColumn_ASC, catenumpb.IndexColumn_ASC,
+				},
+				KeyColumnIDs: []descpb.ColumnID{1, 2, 3},
+			},
+		),
+	)
+
+	// PublicationsTable is the descriptor for the publications table.
+	PublicationsTable = makeSystemTable(
+		PublicationsTableSchema,
+		systemTable(
+			catconstants.PublicationsTableName,
+			descpb.InvalidID, // dynamically assigned
+			[]descpb.ColumnDescriptor{
+				{Name: "database_id", ID: 1, Type: types.Int},
+				{Name: "name", ID: 2, Type: types.String},
+				{Name: "owner", ID: 3, Type: types.String},
+				{Name: "owner_id", ID: 4, Type: types.Oid},
+				{Name: "all_tables", ID: 5, Type: types.Bool},
+				{Name: "table_ids", ID: 6, Type: types.IntArray},
+				{Name: "created_at", ID: 7, Type: types.TimestampTZ, DefaultExpr: &nowTZString},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:        "primary",
+					ID:          0,
+					ColumnNames: []string{"database_id", "name", "owner", "owner_id", "all_tables", "table_ids", "created_at"},
+					ColumnIDs:   []descpb.ColumnID{1, 2, 3, 4, 5, 6, 7},
+				},
+			},
+			descpb.IndexDescriptor{
+				Name:           "primary",
+				ID:             1,
+				Unique:         true,
+				KeyColumnNames: []string{"database_id", "name"},
+				KeyColumnDirections: []catenumpb.IndexColumn_Direction{
+					catenumpb.IndexColumn_ASC, catenumpb.IndexColumn_ASC,
+				},
+				KeyColumnIDs: []descpb.ColumnID{1, 2},
+			},
+		),
+	)
+
+	// ReplicationSlotsTable is the descriptor for the replication slots table.
+	ReplicationSlotsTable = makeSystemTable(
+		ReplicationSlotsTableSchema,
+		systemTable(
+			catconstants.ReplicationSlotsTableName,
+			descpb.InvalidID, // dynamically assigned
+			[]descpb.ColumnDescriptor{
+				{Name: "slot_name", ID: 1, Type: types.String},
+				{Name: "database_id", ID: 2, Type: types.Int},
+				{Name: "plugin", ID: 3, Type: types.String},
+				{Name: "owner", ID: 4, Type: types.String},
+				{Name: "owner_id", ID: 5, Type: types.Oid},
+				{Name: "confirmed_flush_lsn", ID: 6, Type: types.PGLSN},
+				{Name: "created_at", ID: 7, Type: types.TimestampTZ, DefaultExpr: &nowTZString},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:        "primary",
+					ID:          0,
+					ColumnNames: []string{"slot_name", "database_id", "plugin", "owner", "owner_id", "confirmed_flush_lsn", "created_at"},
+					ColumnIDs:   []descpb.ColumnID{1, 2, 3, 4, 5, 6, 7},
+				},
+			},
+			descpb.IndexDescriptor{
+				Name:                "primary",
+				ID:                  1,
+				Unique:              true,
+				KeyColumnNames:      []string{"slot_name"},
+				KeyColumnDirections: singleASC,
+				KeyColumnIDs:        singleID1,
+			},
+		),
+	)
 )
 
 // SpanConfigurationsTableName represents system.span_configurations.
@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/semenumpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
 	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
@@ -86,6 +87,10 @@ type ConstraintProvider interface {
 	// AsUniqueWithoutIndex returns the corresponding
 	// UniqueWithoutIndexConstraint if there is one, nil otherwise.
 	AsUniqueWithoutIndex() UniqueWithoutIndexConstraint
+
+	// AsExclusion returns the corresponding ExclusionConstraint if there is
+	// one, nil otherwise.
+	AsExclusion() ExclusionConstraint
 }
 
 // Mutation is an interface around a table descriptor mutation.
@@ -178,6 +183,7 @@ type Index interface {
 	GetPredicate() string
 	GetType() descpb.IndexDescriptor_Type
 	GetGeoConfig() geoindex.Config
+	GetVectorConfig() vecindex.Config
 	GetVersion() descpb.IndexDescriptorVersion
 	GetEncodingType() catenumpb.IndexDescriptorEncodingType
 
@@ -601,6 +607,36 @@ type UniqueWithoutIndexConstraint interface {
 	ParentTableID() descpb.ID
 }
 
+// ExclusionConstraint is an interface around an exclusion constraint, which
+// is not backed by an index.
+type ExclusionConstraint interface {
+	WithoutIndexConstraint
+
+	// ExclusionDesc returns the underlying descriptor protobuf.
+	ExclusionDesc() *descpb.ExclusionConstraint
+
+	// NumKeyColumns returns the number of columns in this exclusion constraint.
+	NumKeyColumns() int
+
+	// GetKeyColumnID returns the ID of the column in the exclusion constraint
+	// at ordinal `columnOrdinal`.
+	GetKeyColumnID(columnOrdinal int) descpb.ColumnID
+
+	// GetOperator returns the operator that the column in the exclusion
+	// constraint at ordinal `columnOrdinal` is compared with.
+	GetOperator(columnOrdinal int) string
+
+	// CollectKeyColumnIDs returns the columns in the exclusion constraint in a
+	// new TableColSet.
+	CollectKeyColumnIDs() TableColSet
+
+	// IsPartial returns true iff this is a partial exclusion constraint.
+	IsPartial() bool
+
+	// GetPredicate returns the partial predicate if there is one, "" otherwise.
+	GetPredicate() string
+}
+
 // PrimaryKeySwap is an interface around a primary key swap mutation.
 type PrimaryKeySwap interface {
 	TableElementMaybeMutation
@@ -1020,6 +1056,8 @@ func GetConstraintType(c Constraint) catconstants.ConstraintType {
 		return catconstants.ConstraintTypeFK
 	} else if c.AsUniqueWithoutIndex() != nil {
 		return catconstants.ConstraintTypeUniqueWithoutIndex
+	} else if c.AsExclusion() != nil {
+		return catconstants.ConstraintTypeExclusion
 	} else if c.AsUniqueWithIndex() != nil {
 		if c.AsUniqueWithIndex().GetEncodingType() == catenumpb.PrimaryIndexEncoding {
 			return catconstants.ConstraintTypePK
@@ -43,6 +43,11 @@ func (c constraintBase) AsUniqueWithIndex() catalog.UniqueWithIndexConstraint {
 	return nil
 }
 
+// AsExclusion implements the catalog.ConstraintProvider interface.
+func (c constraintBase) AsExclusion() catalog.ExclusionConstraint {
+	return nil
+}
+
 type checkConstraint struct {
 	constraintBase
 	desc *descpb.TableDescriptor_CheckConstraint
@@ -220,6 +225,88 @@ func (c uniqueWithoutIndexConstraint) IsEnforced() bool {
 	return !c.IsMutation() || c.WriteAndDeleteOnly()
 }
 
+type exclusionConstraint struct {
+	constraintBase
+	desc *descpb.ExclusionConstraint
+}
+
+var _ catalog.ExclusionConstraint = (*exclusionConstraint)(nil)
+
+// ExclusionDesc implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) ExclusionDesc() *descpb.ExclusionConstraint {
+	return c.desc
+}
+
+// NumKeyColumns implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) NumKeyColumns() int {
+	return len(c.desc.ColumnIDs)
+}
+
+// GetKeyColumnID implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) GetKeyColumnID(columnOrdinal int) descpb.ColumnID {
+	return c.desc.ColumnIDs[columnOrdinal]
+}
+
+// GetOperator implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) GetOperator(columnOrdinal int) string {
+	return c.desc.Operators[columnOrdinal]
+}
+
+// CollectKeyColumnIDs implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) CollectKeyColumnIDs() catalog.TableColSet {
+	return catalog.MakeTableColSet(c.desc.ColumnIDs...)
+}
+
+// IsPartial implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) IsPartial() bool {
+	return c.desc.Predicate != ""
+}
+
+// GetPredicate implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) GetPredicate() string {
+	return c.desc.Predicate
+}
+
+// GetConstraintID implements the catalog.Constraint interface.
+func (c exclusionConstraint) GetConstraintID() descpb.ConstraintID {
+	return c.desc.ConstraintID
+}
+
+// GetConstraintValidity implements the catalog.Constraint interface.
+func (c exclusionConstraint) GetConstraintValidity() descpb.ConstraintValidity {
+	return c.desc.Validity
+}
+
+// IsConstraintValidated implements the catalog.Constraint interface.
+func (c exclusionConstraint) IsConstraintValidated() bool {
+	return c.desc.Validity == descpb.ConstraintValidity_Validated
+}
+
+// IsConstraintUnvalidated implements the catalog.Constraint interface.
+func (c exclusionConstraint) IsConstraintUnvalidated() bool {
+	return c.desc.Validity == descpb.ConstraintValidity_Unvalidated
+}
+
+// GetName implements the catalog.Constraint interface.
+func (c exclusionConstraint) GetName() string {
+	return c.desc.Name
+}
+
+// AsExclusion implements the catalog.ConstraintProvider interface.
+func (c exclusionConstraint) AsExclusion() catalog.ExclusionConstraint {
+	return &c
+}
+
+// String implements the catalog.Constraint interface.
+func (c exclusionConstraint) String() string {
+	return fmt.Sprintf("%+v", c.desc)
+}
+
+// IsEnforced implements the catalog.Constraint interface.
+func (c exclusionConstraint) IsEnforced() bool {
+	return !c.IsMutation() || c.WriteAndDeleteOnly()
+}
+
 type foreignKeyConstraint struct {
 	constraintBase
 	desc *descpb.ForeignKeyConstraint
@@ -343,6 +430,8 @@ type constraintCache struct {
 	fks, fksEnforced       []catalog.ForeignKeyConstraint
 	uwis, uwisEnforced     []catalog.UniqueWithIndexConstraint
 	uwois, uwoisEnforced   []catalog.UniqueWithoutIndexConstraint
+	exclusions             []catalog.ExclusionConstraint
+	exclusionsEnforced     []catalog.ExclusionConstraint
 	fkBackRefs             []catalog.ForeignKeyConstraint
 }
 
@@ -358,7 +447,9 @@ func newConstraintCache(
 	capFKs := numEnforcedFKs + len(mutations.fks)
 	numEnforcedUWOIs := len(desc.UniqueWithoutIndexConstraints)
 	capUWOIs := numEnforcedUWOIs + len(mutations.uniqueWithoutIndexes)
-	capAll := capChecks + capFKs + capUWOIs + capUWIs
+	numEnforcedExclusions := len(desc.Exclusions)
+	capExclusions := numEnforcedExclusions + len(mutations.exclusions)
+	capAll := capChecks + capFKs + capUWOIs + capUWIs + capExclusions
 	// Pre-allocate slices which are known not to be empty:
 	// physical tables always have at least one index-backed unique constraint
 	// in the form of the primary key.
@@ -473,6 +564,37 @@ func newConstraintCache(
 			}
 		}
 	}
+	// Populate with exclusion constraints.
+	if capExclusions > 0 {
+		c.exclusions = make([]catalog.ExclusionConstraint, 0, capExclusions)
+		var byID util.FastIntMap
+		var exclusionBackingStructs []exclusionConstraint
+		if numEnforcedExclusions > 0 {
+			exclusionBackingStructs = make([]exclusionConstraint, numEnforcedExclusions)
+			for i := range desc.Exclusions {
+				exclusionBackingStructs[i].desc = &desc.Exclusions[i]
+				ec := &exclusionBackingStructs[i]
+				byID.Set(int(ec.desc.ConstraintID), i)
+				c.all = append(c.all, ec)
+				c.allEnforced = append(c.allEnforced, ec)
+				c.exclusions = append(c.exclusions, ec)
+				c.exclusionsEnforced = append(c.exclusionsEnforced, ec)
+			}
+		}
+		for _, m := range mutations.exclusions {
+			ec := m.AsExclusion()
+			if ordinal, found := byID.Get(int(ec.GetConstraintID())); found {
+				exclusionBackingStructs[ordinal].maybeMutation = ec.(*exclusionConstraint).maybeMutation
+			} else {
+				c.all = append(c.all, ec)
+				c.exclusions = append(c.exclusions, ec)
+				if m.WriteAndDeleteOnly() {
+					c.allEnforced = append(c.allEnforced, ec)
+					c.exclusionsEnforced = append(c.exclusionsEnforced, ec)
+				}
+			}
+		}
+	}
 	// Populate foreign key back-reference slice.
 	// These are not constraints on this table, but having them wrapped in the
 	// catalog.ForeignKeyConstraint interface is useful.
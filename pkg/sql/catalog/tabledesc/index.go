@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -269,6 +270,11 @@ func (w index) GetGeoConfig() geoindex.Config {
 	return w.desc.GeoConfig
 }
 
+// GetVectorConfig returns the vector config in the index descriptor.
+func (w index) GetVectorConfig() vecindex.Config {
+	return w.desc.VectorConfig
+}
+
 // GetSharded returns the ShardedDescriptor in the index descriptor
 func (w index) GetSharded() catpb.ShardedDescriptor {
 	return w.desc.Sharded
@@ -473,6 +479,11 @@ func (w index) AsUniqueWithoutIndex() catalog.UniqueWithoutIndexConstraint {
 	return nil
 }
 
+// AsExclusion implements the catalog.ConstraintProvider interface.
+func (w index) AsExclusion() catalog.ExclusionConstraint {
+	return nil
+}
+
 // AsUniqueWithIndex implements the catalog.ConstraintProvider interface.
 func (w index) AsUniqueWithIndex() catalog.UniqueWithIndexConstraint {
 	if w.Primary() {
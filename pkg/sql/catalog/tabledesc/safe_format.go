@@ -155,6 +155,7 @@ func formatSafeTableConstraints(w *redact.StringBuilder, desc catalog.TableDescr
 	td := desc.TableDesc()
 	formatSafeTableChecks(w, td.Checks)
 	formatSafeTableUniqueWithoutIndexConstraints(w, td.UniqueWithoutIndexConstraints)
+	formatSafeTableExclusionConstraints(w, td.Exclusions)
 	formatSafeTableFKs(w, "InboundFKs", td.InboundFKs)
 	formatSafeTableFKs(w, "OutboundFKs", td.OutboundFKs)
 }
@@ -225,6 +226,23 @@ func formatSafeTableUniqueWithoutIndexConstraints(
 	}
 }
 
+func formatSafeTableExclusionConstraints(
+	w *redact.StringBuilder, constraints []descpb.ExclusionConstraint,
+) {
+	for i := range constraints {
+		c := &constraints[i]
+		if i == 0 {
+			w.Printf(", Exclusion Constraints: [")
+		} else {
+			w.Printf(", ")
+		}
+		formatSafeExclusionConstraint(w, c, nil)
+	}
+	if len(constraints) > 0 {
+		w.Printf("]")
+	}
+}
+
 func formatSafeTableColumnFamilies(w *redact.StringBuilder, desc catalog.TableDescriptor) {
 	td := desc.TableDesc()
 	w.Printf(", NextFamilyID: %d", td.NextFamilyID)
@@ -295,6 +313,9 @@ func formatSafeMutation(w *redact.StringBuilder, m *descpb.DescriptorMutation) {
 		case !md.Constraint.Check.Equal(&descpb.TableDescriptor_CheckConstraint{}):
 			w.Printf(", Check: ")
 			formatSafeCheck(w, &md.Constraint.Check, m)
+		case !md.Constraint.ExclusionConstraint.Equal(&descpb.ExclusionConstraint{}):
+			w.Printf(", Exclusion: ")
+			formatSafeExclusionConstraint(w, &md.Constraint.ExclusionConstraint, m)
 		}
 	case *descpb.DescriptorMutation_Index:
 		w.Printf(", Index: ")
@@ -365,6 +386,25 @@ func formatSafeUniqueWithoutIndexConstraint(
 	w.Printf("}")
 }
 
+func formatSafeExclusionConstraint(
+	w *redact.StringBuilder, c *descpb.ExclusionConstraint, m *descpb.DescriptorMutation,
+) {
+	w.Printf("{Columns: ")
+	formatSafeColumnIDs(w, c.ColumnIDs)
+	w.Printf(", Operators: [")
+	for i, op := range c.Operators {
+		if i > 0 {
+			w.Printf(", ")
+		}
+		w.Printf("%s", redact.SafeString(op))
+	}
+	w.Printf("], Validity: %s", c.Validity.String())
+	if m != nil {
+		w.Printf(", State: %s, MutationID: %d", m.Direction, m.MutationID)
+	}
+	w.Printf("}")
+}
+
 func formatSafeColumnIDs(w *redact.StringBuilder, colIDs []descpb.ColumnID) {
 	w.Printf("[")
 	for i, colID := range colIDs {
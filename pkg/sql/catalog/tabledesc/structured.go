@@ -283,6 +283,12 @@ func ForEachExprStringInTableDesc(descI catalog.TableDescriptor, f func(expr *st
 		}
 		return nil
 	}
+	doExclusion := func(ec *descpb.ExclusionConstraint) error {
+		if ec.Predicate != "" {
+			return f(&ec.Predicate)
+		}
+		return nil
+	}
 
 	// Process columns.
 	for i := range desc.Columns {
@@ -314,6 +320,13 @@ func ForEachExprStringInTableDesc(descI catalog.TableDescriptor, f func(expr *st
 		}
 	}
 
+	// Process exclusion constraints.
+	for i := range desc.Exclusions {
+		if err := doExclusion(&desc.Exclusions[i]); err != nil {
+			return err
+		}
+	}
+
 	// Process all non-index mutations.
 	for _, mut := range desc.Mutations {
 		if c := mut.GetColumn(); c != nil {
@@ -333,6 +346,12 @@ func ForEachExprStringInTableDesc(descI catalog.TableDescriptor, f func(expr *st
 				return err
 			}
 		}
+		if c := mut.GetConstraint(); c != nil &&
+			c.ConstraintType == descpb.ConstraintToUpdate_EXCLUSION {
+			if err := doExclusion(&c.ExclusionConstraint); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -1294,6 +1313,17 @@ func (desc *Mutable) DropConstraint(
 				return nil
 			}
 		}
+	} else if ec := constraint.AsExclusion(); ec != nil {
+		// Search through the descriptor's exclusion constraints and delete the
+		// one that we're supposed to be deleting.
+		for i := range desc.Exclusions {
+			ref := &desc.Exclusions[i]
+			if ref.Name == ec.GetName() {
+				ref.Validity = descpb.ConstraintValidity_Dropping
+				desc.AddExclusionMutation(ref, descpb.DescriptorMutation_DROP)
+				return nil
+			}
+		}
 	} else if ck := constraint.AsCheck(); ck != nil {
 		for i, c := range desc.Checks {
 			if c.Name == ck.GetName() {
@@ -1373,6 +1403,8 @@ func (desc *Mutable) RenameConstraint(
 		fk.ForeignKeyDesc().Name = newName
 	} else if uwoi := constraint.AsUniqueWithoutIndex(); uwoi != nil {
 		uwoi.UniqueWithoutIndexDesc().Name = newName
+	} else if ec := constraint.AsExclusion(); ec != nil {
+		ec.ExclusionDesc().Name = newName
 	} else {
 		return unimplemented.Newf(fmt.Sprintf("rename-constraint-%T", constraint),
 			"constraint %q has unsupported type", tree.ErrNameString(constraint.GetName()))
@@ -1527,6 +1559,28 @@ func (desc *Mutable) MakeMutationComplete(m descpb.DescriptorMutation) error {
 						t.Constraint.UniqueWithoutIndexConstraint.Validity,
 					)
 				}
+			case descpb.ConstraintToUpdate_EXCLUSION:
+				switch t.Constraint.ExclusionConstraint.Validity {
+				case descpb.ConstraintValidity_Validating:
+					// Constraint already added, just mark it as Validated.
+					for i := range desc.Exclusions {
+						ec := &desc.Exclusions[i]
+						if ec.ConstraintID == t.Constraint.ExclusionConstraint.ConstraintID {
+							ec.Validity = descpb.ConstraintValidity_Validated
+							break
+						}
+					}
+				case descpb.ConstraintValidity_Validated:
+					// add the constraint to the list of exclusion constraints on the
+					// table descriptor.
+					desc.Exclusions = append(
+						desc.Exclusions, t.Constraint.ExclusionConstraint,
+					)
+				default:
+					return errors.AssertionFailedf("invalid constraint validity state: %d",
+						t.Constraint.ExclusionConstraint.Validity,
+					)
+				}
 			case descpb.ConstraintToUpdate_NOT_NULL:
 				// Remove the dummy check constraint that was in place during
 				// validation.
@@ -1797,6 +1851,24 @@ func (desc *Mutable) AddUniqueWithoutIndexMutation(
 	desc.addIndexMutationMaybeWithTempIndex(m)
 }
 
+// AddExclusionMutation adds an exclusion constraint mutation to
+// desc.Mutations.
+func (desc *Mutable) AddExclusionMutation(
+	ec *descpb.ExclusionConstraint, direction descpb.DescriptorMutation_Direction,
+) {
+	m := descpb.DescriptorMutation{
+		Descriptor_: &descpb.DescriptorMutation_Constraint{
+			Constraint: &descpb.ConstraintToUpdate{
+				ConstraintType:      descpb.ConstraintToUpdate_EXCLUSION,
+				Name:                ec.Name,
+				ExclusionConstraint: *ec,
+			},
+		},
+		Direction: direction,
+	}
+	desc.addIndexMutationMaybeWithTempIndex(m)
+}
+
 // MakeNotNullCheckConstraint creates a dummy check constraint equivalent to a
 // NOT NULL constraint on a column, so that NOT NULL constraints can be added
 // and dropped correctly in the schema changer. This function mutates inuseNames
@@ -2256,6 +2328,13 @@ func (desc *wrapper) FindAllReferences() (map[descpb.ID]struct{}, error) {
 	for _, c := range desc.DependedOnBy {
 		refs[c.ID] = struct{}{}
 	}
+
+	for _, id := range desc.Inherits {
+		refs[id] = struct{}{}
+	}
+	for _, id := range desc.InheritedBy {
+		refs[id] = struct{}{}
+	}
 	return refs, nil
 }
 
@@ -2434,6 +2513,9 @@ func (desc *wrapper) GetStorageParams(spaceBetweenEqual bool) []string {
 	if desc.IsSchemaLocked() {
 		appendStorageParam(`schema_locked`, `true`)
 	}
+	if lockTimeout := desc.GetLockTimeout(); lockTimeout != 0 {
+		appendStorageParam(`lock_timeout`, fmt.Sprintf(`'%s'`, lockTimeout))
+	}
 	return storageParams
 }
 
@@ -393,6 +393,17 @@ func (desc *wrapper) EnforcedUniqueConstraintsWithoutIndex() []catalog.UniqueWit
 	return desc.getExistingOrNewConstraintCache().uwoisEnforced
 }
 
+// ExclusionConstraints implements the catalog.TableDescriptor interface.
+func (desc *wrapper) ExclusionConstraints() []catalog.ExclusionConstraint {
+	return desc.getExistingOrNewConstraintCache().exclusions
+}
+
+// EnforcedExclusionConstraints implements the catalog.TableDescriptor
+// interface.
+func (desc *wrapper) EnforcedExclusionConstraints() []catalog.ExclusionConstraint {
+	return desc.getExistingOrNewConstraintCache().exclusionsEnforced
+}
+
 // InitTableDescriptor returns a blank TableDescriptor.
 func InitTableDescriptor(
 	id, parentID, parentSchemaID descpb.ID,
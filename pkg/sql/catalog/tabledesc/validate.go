@@ -117,6 +117,13 @@ func (desc *wrapper) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
 	for _, ref := range desc.GetDependedOnBy() {
 		ids.Add(ref.ID)
 	}
+	// Add inheritance parents and children.
+	for _, id := range desc.GetInherits() {
+		ids.Add(id)
+	}
+	for _, id := range desc.GetInheritedBy() {
+		ids.Add(id)
+	}
 	// Add sequence dependencies
 	return ids, nil
 }
@@ -203,6 +210,11 @@ func (desc *wrapper) ValidateForwardReferences(
 		vea.Report(desc.validateOutboundFK(fk.ForeignKeyDesc(), vdg))
 	}
 
+	// Check the tables this table inherits from.
+	for _, id := range desc.Inherits {
+		vea.Report(desc.validateInheritsRef(id, vdg))
+	}
+
 	// Check partitioning is correctly set.
 	// We only check these for active indexes, as inactive indexes may be in the
 	// process of being backfilled without PartitionAllBy.
@@ -274,6 +286,22 @@ func (desc *wrapper) ValidateBackReferences(
 		}
 	}
 
+	// Check that inheritance references have matching back-references.
+	for _, id := range desc.Inherits {
+		ref, _ := vdg.GetTableDescriptor(id)
+		if ref == nil || ref.Dropped() {
+			continue
+		}
+		if !containsID(ref.GetInheritedBy(), desc.GetID()) {
+			vea.Report(errors.AssertionFailedf(
+				"inherited table %q (%d) has no corresponding inherited-by back reference",
+				ref.GetName(), ref.GetID()))
+		}
+	}
+	for _, id := range desc.InheritedBy {
+		vea.Report(desc.validateInheritedByRef(id, vdg))
+	}
+
 	for _, id := range desc.DependsOn {
 		ref, _ := vdg.GetTableDescriptor(id)
 		if ref == nil {
@@ -454,6 +482,60 @@ func (desc *wrapper) validateInboundTableRef(
 		backReferencedTable.GetName(), by.ID)
 }
 
+func (desc *wrapper) validateInheritsRef(id descpb.ID, vdg catalog.ValidationDescGetter) error {
+	parent, err := vdg.GetTableDescriptor(id)
+	if err != nil {
+		return errors.NewAssertionErrorWithWrappedErrf(err, "invalid inherited table reference")
+	}
+	if parent.Dropped() {
+		return errors.AssertionFailedf("inherited table %q (%d) is dropped",
+			parent.GetName(), parent.GetID())
+	}
+	if !parent.IsPhysicalTable() || parent.IsSequence() {
+		return errors.AssertionFailedf("inherited relation %q (%d) is not a table",
+			parent.GetName(), parent.GetID())
+	}
+	if bound := desc.GetPartitionBound(); bound != nil {
+		key := parent.GetPartitionKey()
+		if key == nil {
+			return errors.AssertionFailedf("partition parent %q (%d) is not partitioned",
+				parent.GetName(), parent.GetID())
+		}
+		if len(bound.ColumnIDs) != len(key.ColumnIDs) {
+			return errors.AssertionFailedf(
+				"partition bound has %d columns but partition parent %q (%d) has %d partition key columns",
+				len(bound.ColumnIDs), parent.GetName(), parent.GetID(), len(key.ColumnIDs))
+		}
+	}
+	return nil
+}
+
+func (desc *wrapper) validateInheritedByRef(id descpb.ID, vdg catalog.ValidationDescGetter) error {
+	child, err := vdg.GetTableDescriptor(id)
+	if err != nil {
+		return errors.NewAssertionErrorWithWrappedErrf(err, "invalid inherited-by table back reference")
+	}
+	if child.Dropped() {
+		return errors.AssertionFailedf("inherited-by table %q (%d) is dropped",
+			child.GetName(), child.GetID())
+	}
+	if !containsID(child.GetInherits(), desc.GetID()) {
+		return errors.AssertionFailedf(
+			"inherited-by table %q (%d) has no corresponding inherits forward reference",
+			child.GetName(), child.GetID())
+	}
+	return nil
+}
+
+func containsID(ids []descpb.ID, id descpb.ID) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
 func (desc *wrapper) validateOutboundFK(
 	fk *descpb.ForeignKeyConstraint, vdg catalog.ValidationDescGetter,
 ) error {
@@ -753,8 +835,10 @@ func (desc *wrapper) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 			desc.validateColumnFamilies(columnsByID),
 			desc.validateCheckConstraints(columnsByID),
 			desc.validateUniqueWithoutIndexConstraints(columnsByID),
+			desc.validateExclusionConstraints(columnsByID),
 			desc.validateTableIndexes(columnsByID),
 			desc.validatePartitioning(),
+			desc.validateInheritance(columnsByID),
 		}
 		hasErrs := false
 		for _, err := range newErrs {
@@ -1269,6 +1353,113 @@ func (desc *wrapper) validateUniqueWithoutIndexConstraints(
 	return nil
 }
 
+// validateExclusionConstraints validates that exclusion constraints are well
+// formed. Checks include validating the column IDs, the operators and the
+// predicate.
+func (desc *wrapper) validateExclusionConstraints(
+	columnsByID map[descpb.ColumnID]catalog.Column,
+) error {
+	for _, c := range desc.ExclusionConstraints() {
+		if len(c.GetName()) == 0 {
+			return pgerror.Newf(pgcode.Syntax, "empty exclusion constraint name")
+		}
+
+		ec := c.ExclusionDesc()
+		if len(ec.ColumnIDs) == 0 {
+			return errors.Newf("exclusion constraint %q has no columns", c.GetName())
+		}
+		if len(ec.Operators) != len(ec.ColumnIDs) {
+			return errors.Newf(
+				"exclusion constraint %q has %d operators for %d columns",
+				c.GetName(), len(ec.Operators), len(ec.ColumnIDs),
+			)
+		}
+
+		// Verify that the constraint's column IDs are valid. Unlike unique
+		// constraints, a column may appear more than once with different
+		// operators.
+		for i, n := 0, c.NumKeyColumns(); i < n; i++ {
+			colID := c.GetKeyColumnID(i)
+			if _, ok := columnsByID[colID]; !ok {
+				return errors.Newf(
+					"exclusion constraint %q contains unknown column \"%d\"", c.GetName(), colID,
+				)
+			}
+			if c.GetOperator(i) == "" {
+				return errors.Newf(
+					"exclusion constraint %q has no operator for column \"%d\"", c.GetName(), colID,
+				)
+			}
+		}
+
+		if c.IsPartial() {
+			expr, err := parser.ParseExpr(c.GetPredicate())
+			if err != nil {
+				return err
+			}
+			valid, err := schemaexpr.HasValidColumnReferences(desc, expr)
+			if err != nil {
+				return err
+			}
+			if !valid {
+				return errors.Newf(
+					"partial exclusion constraint %q refers to unknown columns in predicate: %s",
+					c.GetName(),
+					c.GetPredicate(),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateInheritance validates the inheritance references, the partition
+// key and the partition bound of the table.
+func (desc *wrapper) validateInheritance(
+	columnsByID map[descpb.ColumnID]catalog.Column,
+) error {
+	if len(desc.Inherits) > catalog.MakeDescriptorIDSet(desc.Inherits...).Len() {
+		return errors.AssertionFailedf("duplicate table IDs found in inherits references: %v",
+			desc.Inherits)
+	}
+	for _, ids := range [][]descpb.ID{desc.Inherits, desc.InheritedBy} {
+		for _, id := range ids {
+			if id == descpb.InvalidID || id == desc.ID {
+				return errors.AssertionFailedf("invalid table ID %d in inheritance references", id)
+			}
+		}
+	}
+	if key := desc.PartitionKey; key != nil {
+		if len(key.ColumnIDs) == 0 {
+			return errors.AssertionFailedf("partition key has no columns")
+		}
+		for _, colID := range key.ColumnIDs {
+			if _, ok := columnsByID[colID]; !ok {
+				return errors.AssertionFailedf("partition key column %d does not exist", colID)
+			}
+		}
+	}
+	if bound := desc.PartitionBound; bound != nil {
+		if len(desc.Inherits) != 1 {
+			return errors.AssertionFailedf(
+				"partition inherits from %d tables instead of its partitioned table", len(desc.Inherits))
+		}
+		if len(bound.ColumnIDs) == 0 ||
+			len(bound.From) != len(bound.ColumnIDs) || len(bound.To) != len(bound.ColumnIDs) {
+			return errors.AssertionFailedf(
+				"partition bound has %d columns but %d lower and %d upper bound values",
+				len(bound.ColumnIDs), len(bound.From), len(bound.To))
+		}
+		for _, colID := range bound.ColumnIDs {
+			if _, ok := columnsByID[colID]; !ok {
+				return errors.AssertionFailedf("partition bound column %d does not exist", colID)
+			}
+		}
+	}
+	return nil
+}
+
 // validateTableIndexes validates that indexes are well formed. Checks include
 // validating the columns involved in the index, verifying the index names and
 // IDs are unique, and the family of the primary key is 0. This does not check
@@ -138,6 +138,13 @@ var validationMap = []struct {
 			"HistogramBuckets":              {status: thisFieldReferencesNoObjects},
 			"HistogramSamples":              {status: thisFieldReferencesNoObjects},
 			"SchemaLocked":                  {status: thisFieldReferencesNoObjects},
+			"MaterializedViewRefreshInfo":   {status: thisFieldReferencesNoObjects},
+			"Exclusions":                    {status: iSolemnlySwearThisFieldIsValidated},
+			"Inherits":                      {status: iSolemnlySwearThisFieldIsValidated},
+			"InheritedBy":                   {status: iSolemnlySwearThisFieldIsValidated},
+			"PartitionKey":                  {status: iSolemnlySwearThisFieldIsValidated},
+			"PartitionBound":                {status: iSolemnlySwearThisFieldIsValidated},
+			"LockTimeout":                   {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
@@ -173,6 +180,7 @@ var validationMap = []struct {
 			"Sharded":                     {status: iSolemnlySwearThisFieldIsValidated},
 			"Disabled":                    {status: thisFieldReferencesNoObjects},
 			"GeoConfig":                   {status: thisFieldReferencesNoObjects},
+			"VectorConfig":                {status: thisFieldReferencesNoObjects},
 			"Predicate":                   {status: iSolemnlySwearThisFieldIsValidated},
 			"UseDeletePreservingEncoding": {status: thisFieldReferencesNoObjects},
 			"ConstraintID":                {status: iSolemnlySwearThisFieldIsValidated},
@@ -700,6 +700,136 @@ func validateUniqueConstraint(
 	return nil
 }
 
+// exclusionViolationQuery generates and returns a SQL query that returns a
+// pair of rows which violates the given exclusion constraint, if one exists.
+// The query joins the table with itself, pairing every row with every other
+// row for which all of the constraint's comparisons are true. The form of the
+// query is:
+//
+// SELECT a.a, a.b, b.a, b.b
+// FROM (SELECT a, b, k FROM [tbl_id AS t] WHERE pred) AS a
+// JOIN (SELECT a, b, k FROM [tbl_id AS t] WHERE pred) AS b
+// ON a.a = b.a AND a.b && b.b AND (a.k) != (b.k)
+// LIMIT 1
+//
+// where k is the primary key of the table. The WHERE clauses are only added if
+// the constraint is partial.
+func exclusionViolationQuery(
+	srcTbl catalog.TableDescriptor, ec *descpb.ExclusionConstraint,
+) (sql string, colNames []string, _ error) {
+	colNames, err := catalog.ColumnNamesForIDs(srcTbl, ec.ColumnIDs)
+	if err != nil {
+		return "", nil, err
+	}
+	pkColNames, err := catalog.ColumnNamesForIDs(
+		srcTbl, srcTbl.GetPrimaryIndex().CollectKeyColumnIDs().Ordered(),
+	)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Collect the columns which are projected by each side of the join.
+	var projected []string
+	seen := make(map[string]struct{})
+	for _, names := range [][]string{colNames, pkColNames} {
+		for _, n := range names {
+			if _, ok := seen[n]; !ok {
+				seen[n] = struct{}{}
+				projected = append(projected, tree.NameString(n))
+			}
+		}
+	}
+
+	var where string
+	if ec.Predicate != "" {
+		where = fmt.Sprintf(" WHERE (%s)", ec.Predicate)
+	}
+	side := fmt.Sprintf(
+		`(SELECT %s FROM [%d AS t]%s)`, strings.Join(projected, ", "), srcTbl.GetID(), where,
+	)
+
+	sel := make([]string, 0, 2*len(colNames))
+	on := make([]string, 0, len(colNames)+1)
+	for _, alias := range []string{"a", "b"} {
+		for _, n := range colNames {
+			sel = append(sel, fmt.Sprintf("%s.%s", alias, tree.NameString(n)))
+		}
+	}
+	for i, n := range colNames {
+		n = tree.NameString(n)
+		on = append(on, fmt.Sprintf("a.%[1]s %[2]s b.%[1]s", n, ec.Operators[i]))
+	}
+	pkA := make([]string, len(pkColNames))
+	pkB := make([]string, len(pkColNames))
+	for i, n := range pkColNames {
+		pkA[i] = "a." + tree.NameString(n)
+		pkB[i] = "b." + tree.NameString(n)
+	}
+	on = append(on, fmt.Sprintf(
+		"(%s) IS DISTINCT FROM (%s)", strings.Join(pkA, ", "), strings.Join(pkB, ", "),
+	))
+
+	query := fmt.Sprintf(
+		`SELECT %[1]s FROM %[2]s AS a JOIN %[2]s AS b ON %[3]s LIMIT 1`,
+		strings.Join(sel, ", "),   // 1
+		side,                      // 2
+		strings.Join(on, " AND "), // 3
+	)
+	return query, colNames, nil
+}
+
+// validateExclusionConstraint verifies that no pair of rows in the table
+// violates the given exclusion constraint.
+func validateExclusionConstraint(
+	ctx context.Context,
+	srcTable catalog.TableDescriptor,
+	ec *descpb.ExclusionConstraint,
+	txn isql.Txn,
+	user username.SQLUsername,
+) error {
+	query, colNames, err := exclusionViolationQuery(srcTable, ec)
+	if err != nil {
+		return err
+	}
+
+	log.Infof(ctx, "validating exclusion constraint %q (%q [%v]) with query %q",
+		ec.Name,
+		srcTable.GetName(),
+		colNames,
+		query,
+	)
+
+	sessionDataOverride := sessiondata.NoSessionDataOverride
+	sessionDataOverride.User = user
+	values, err := txn.QueryRowEx(ctx, "validate exclusion constraint", txn.KV(), sessionDataOverride, query)
+	if err != nil {
+		return err
+	}
+	if values.Len() > 0 {
+		valuesStr := make([]string, len(values))
+		for i := range values {
+			valuesStr[i] = values[i].String()
+		}
+		n := len(colNames)
+		cols := strings.Join(colNames, ", ")
+		// Note: this error message mirrors the message produced by Postgres
+		// when it fails to add an exclusion constraint.
+		return errors.WithDetail(
+			pgerror.WithConstraintName(
+				pgerror.Newf(
+					pgcode.ExclusionViolation, "could not create exclusion constraint %q", ec.Name,
+				),
+				ec.Name,
+			),
+			fmt.Sprintf(
+				"Key (%s)=(%s) conflicts with key (%s)=(%s).",
+				cols, strings.Join(valuesStr[:n], ", "), cols, strings.Join(valuesStr[n:], ", "),
+			),
+		)
+	}
+	return nil
+}
+
 // ValidateTTLScheduledJobsInCurrentDB is part of the EvalPlanner interface.
 func (p *planner) ValidateTTLScheduledJobsInCurrentDB(ctx context.Context) error {
 	dbName := p.CurrentDatabase()
@@ -872,6 +1002,18 @@ func checkMutationInput(
 		}
 		colIdx++
 	}
+
+	// The check constraint which enforces the bound of a partition is the first
+	// check constraint synthesized by the optimizer (see newOptTable). The other
+	// synthesized check constraints hold by construction.
+	if tabDesc.GetPartitionBound() != nil && checkOrds.Contains(len(checks)) {
+		if res, err := tree.GetBool(checkVals[colIdx]); err != nil {
+			return err
+		} else if !res && checkVals[colIdx] != tree.DNull {
+			return pgerror.Newf(pgcode.CheckViolation,
+				"new row for relation %q violates partition constraint", tabDesc.GetName())
+		}
+	}
 	return nil
 }
 
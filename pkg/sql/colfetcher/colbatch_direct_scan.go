@@ -215,7 +215,7 @@ func NewColBatchDirectScan(
 		spec.Reverse,
 		spec.LockingStrength,
 		spec.LockingWaitPolicy,
-		flowCtx.EvalCtx.SessionData().LockTimeout,
+		row.GetLockTimeout(flowCtx.EvalCtx.SessionData().LockTimeout, spec.FetchSpec.LockTimeout),
 		kvFetcherMemAcc,
 		flowCtx.EvalCtx.TestingKnobs.ForceProductionValues,
 	)
@@ -350,7 +350,7 @@ func NewColBatchScan(
 		spec.Reverse,
 		spec.LockingStrength,
 		spec.LockingWaitPolicy,
-		flowCtx.EvalCtx.SessionData().LockTimeout,
+		row.GetLockTimeout(flowCtx.EvalCtx.SessionData().LockTimeout, spec.FetchSpec.LockTimeout),
 		kvFetcherMemAcc,
 		flowCtx.EvalCtx.TestingKnobs.ForceProductionValues,
 	)
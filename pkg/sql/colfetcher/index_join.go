@@ -580,7 +580,7 @@ func NewColIndexJoin(
 			false, /* reverse */
 			spec.LockingStrength,
 			spec.LockingWaitPolicy,
-			flowCtx.EvalCtx.SessionData().LockTimeout,
+			row.GetLockTimeout(flowCtx.EvalCtx.SessionData().LockTimeout, spec.FetchSpec.LockTimeout),
 			kvFetcherMemAcc,
 			flowCtx.EvalCtx.TestingKnobs.ForceProductionValues,
 		)
@@ -2907,8 +2907,13 @@ func (ex *connExecutor) addActiveQuery(
 	cancelQuery context.CancelFunc,
 ) {
 	_, hidden := stmt.AST.(tree.HiddenFromShowQueries)
+	// START_REPLICATION runs outside of a transaction.
+	var txnID uuid.UUID
+	if txn := ex.state.mu.txn; txn != nil {
+		txnID = txn.ID()
+	}
 	qm := &queryMeta{
-		txnID:         ex.state.mu.txn.ID(),
+		txnID:         txnID,
 		start:         ex.phaseTimes.GetSessionPhaseTime(sessionphase.SessionQueryReceived),
 		stmt:          stmt,
 		placeholders:  placeholders,
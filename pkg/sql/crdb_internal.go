@@ -137,6 +137,7 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalClusterExecutionInsightsTableID:    crdbInternalClusterExecutionInsightsTable,
 		catconstants.CrdbInternalClusterTxnExecutionInsightsTableID: crdbInternalClusterTxnExecutionInsightsTable,
 		catconstants.CrdbInternalClusterLocksTableID:                crdbInternalClusterLocksTable,
+		catconstants.CrdbInternalClusterLockWaitsGraphTableID:       crdbInternalClusterLockWaitsGraphTable,
 		catconstants.CrdbInternalClusterQueriesTableID:              crdbInternalClusterQueriesTable,
 		catconstants.CrdbInternalClusterTransactionsTableID:         crdbInternalClusterTxnsTable,
 		catconstants.CrdbInternalClusterSessionsTableID:             crdbInternalClusterSessionsTable,
@@ -7991,6 +7992,95 @@ func populateClusterLocksWithFilter(
 	return matched, err
 }
 
+var crdbInternalClusterLockWaitsGraphTable = virtualSchemaTable{
+	comment: `cluster-wide wait-for graph between transactions, assembled from the
+		lock tables and txn wait queues of every store. Querying this table is an
+		expensive operation since it creates a cluster-wide RPC-fanout.`,
+	schema: `
+CREATE TABLE crdb_internal.cluster_lock_waits_graph (
+    waiter_txn_id                UUID NOT NULL,
+    holder_txn_id                UUID NOT NULL,
+    source                       STRING NOT NULL,
+    node_id                      INT NOT NULL,
+    store_id                     INT NOT NULL,
+    range_id                     INT NOT NULL,
+    lock_key                     BYTES,
+    lock_key_pretty              STRING,
+    lock_strength                STRING,
+    wait_duration                INTERVAL NOT NULL,
+    waiter_txn_fingerprint_id    BYTES,
+    holder_txn_fingerprint_id    BYTES,
+    waiter_statement             STRING,
+    holder_statement             STRING
+);`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		hasPermission, shouldRedactKeys, err := p.HasViewActivityOrViewActivityRedactedRole(ctx)
+		if err != nil {
+			return err
+		}
+		if !hasPermission {
+			return noViewActivityOrViewActivityRedactedRoleError(p.User())
+		}
+
+		ss, err := p.ExecCfg().NodesStatusServer.OptionalNodesStatusServer()
+		if err != nil {
+			return err
+		}
+		resp, err := ss.LockWaitsGraph(ctx, &serverpb.LockWaitsGraphRequest{})
+		if err != nil {
+			return err
+		}
+
+		fingerprintIDDatum := func(id appstatspb.TransactionFingerprintID) tree.Datum {
+			if id == appstatspb.InvalidTransactionFingerprintID {
+				return tree.DNull
+			}
+			return tree.NewDBytes(tree.DBytes(sqlstatsutil.EncodeUint64ToBytes(uint64(id))))
+		}
+		stringOrNull := func(s string) tree.Datum {
+			if s == "" {
+				return tree.DNull
+			}
+			return tree.NewDString(s)
+		}
+
+		for i := range resp.Edges {
+			e := &resp.Edges[i]
+			keyDatum, prettyKeyDatum, strengthDatum := tree.DNull, tree.DNull, tree.DNull
+			if e.Source == serverpb.LockWaitEdge_LOCK_TABLE {
+				strengthDatum = tree.NewDString(e.Strength.String())
+				if !shouldRedactKeys {
+					decodedKey, _, _ := keys.DecodeTenantPrefix(e.Key)
+					keyDatum = tree.NewDBytes(tree.DBytes(decodedKey))
+					prettyKeyDatum = tree.NewDString(keys.PrettyPrint(nil /* valDirs */, decodedKey))
+				}
+			}
+			if err := addRow(
+				tree.NewDUuid(tree.DUuid{UUID: e.WaiterTxnID}),      // waiter_txn_id
+				tree.NewDUuid(tree.DUuid{UUID: e.HolderTxnID}),      // holder_txn_id
+				tree.NewDString(strings.ToLower(e.Source.String())), // source
+				tree.NewDInt(tree.DInt(e.NodeID)),                   // node_id
+				tree.NewDInt(tree.DInt(e.StoreID)),                  // store_id
+				tree.NewDInt(tree.DInt(e.RangeID)),                  // range_id
+				keyDatum,                                            // lock_key
+				prettyKeyDatum,                                      // lock_key_pretty
+				strengthDatum,                                       // lock_strength
+				tree.NewDInterval(
+					duration.MakeDuration(e.WaitDuration.Nanoseconds(), 0 /* days */, 0 /* months */),
+					types.DefaultIntervalTypeMetadata,
+				), // wait_duration
+				fingerprintIDDatum(e.WaiterTxnFingerprintID), // waiter_txn_fingerprint_id
+				fingerprintIDDatum(e.HolderTxnFingerprintID), // holder_txn_fingerprint_id
+				stringOrNull(e.WaiterStatement),              // waiter_statement
+				stringOrNull(e.HolderStatement),              // holder_statement
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 // This is the table structure for both {cluster,node}_txn_execution_insights.
 const txnExecutionInsightsSchemaPattern = `
 CREATE TABLE crdb_internal.%s (
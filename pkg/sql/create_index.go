@@ -35,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/storageparam"
 	"github.com/cockroachdb/cockroach/pkg/sql/storageparam/indexstorageparam"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/errors"
@@ -301,6 +302,9 @@ func makeIndexDescriptor(
 		if len(indexDesc.InvertedColumnKinds) > 0 && indexDesc.InvertedColumnKinds[0] == catpb.InvertedIndexColumnKind_TRIGRAM {
 			telemetry.Inc(sqltelemetry.TrigramInvertedIndexCounter)
 		}
+		if !indexDesc.VectorConfig.IsEmpty() {
+			telemetry.Inc(sqltelemetry.VectorInvertedIndexCounter)
+		}
 		if indexDesc.IsPartial() {
 			telemetry.Inc(sqltelemetry.PartialInvertedIndexCounter)
 		}
@@ -437,6 +441,15 @@ func populateInvertedIndexDescriptor(
 		default:
 			return newUndefinedOpclassError(invCol.OpClass)
 		}
+	case types.PGVectorFamily:
+		// The list assignment does not depend on the distance metric, so all
+		// of the pgvector operator classes share the same index layout.
+		switch invCol.OpClass {
+		case "vector_l2_ops", "vector_cosine_ops", "vector_ip_ops", "":
+		default:
+			return newUndefinedOpclassError(invCol.OpClass)
+		}
+		indexDesc.VectorConfig = *vecindex.DefaultConfig()
 	default:
 		return tabledesc.NewInvalidInvertedColumnError(column.GetName(), column.GetType().Name())
 	}
@@ -819,6 +819,147 @@ func ResolveUniqueWithoutIndexConstraint(
 	return nil
 }
 
+// addExclusionConstraintTableDef validates the predicate of the given
+// ExclusionConstraintTableDef before adding it as an exclusion constraint to
+// the given table descriptor.
+func addExclusionConstraintTableDef(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	d *tree.ExclusionConstraintTableDef,
+	desc *tabledesc.Mutable,
+	tn tree.TableName,
+	ts TableState,
+	semaCtx *tree.SemaContext,
+) error {
+	if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.V23_2_ExclusionConstraints) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"exclusion constraints are not supported until the cluster upgrade is finalized")
+	}
+	var predicate string
+	if d.Predicate != nil {
+		var err error
+		predicate, err = schemaexpr.ValidateExclusionConstraintPredicate(
+			ctx, tn, desc, d.Predicate, semaCtx, evalCtx.Settings.Version.ActiveVersionOrEmpty(ctx),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return ResolveExclusionConstraint(ctx, desc, d, predicate, ts)
+}
+
+// exclusionConstraintOperators contains the operators which can be used in
+// exclusion constraints. An exclusion constraint compares every pair of rows
+// in the table, so only commutative operators are allowed.
+var exclusionConstraintOperators = map[treecmp.ComparisonOperatorSymbol]struct{}{
+	treecmp.EQ:       {},
+	treecmp.NE:       {},
+	treecmp.Overlaps: {},
+}
+
+// exclusionConstraintOperator returns the operator with the given symbol
+// string, as stored in exclusion constraint descriptors.
+func exclusionConstraintOperator(op string) (treecmp.ComparisonOperatorSymbol, error) {
+	for sym := range exclusionConstraintOperators {
+		if sym.String() == op {
+			return sym, nil
+		}
+	}
+	return 0, errors.AssertionFailedf("unexpected exclusion constraint operator %q", op)
+}
+
+// ResolveExclusionConstraint looks up the columns and operators mentioned in
+// an EXCLUDE constraint and adds metadata representing that constraint to the
+// descriptor. The predicate must have been validated by the caller.
+//
+// Exclusion constraints added to existing tables are always validated against
+// the existing rows.
+func ResolveExclusionConstraint(
+	ctx context.Context,
+	tbl *tabledesc.Mutable,
+	d *tree.ExclusionConstraintTableDef,
+	predicate string,
+	ts TableState,
+) error {
+	switch d.IndexMethod {
+	case "", "btree", "gist":
+	default:
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"access method %q does not support exclusion constraints", d.IndexMethod)
+	}
+
+	ec := descpb.ExclusionConstraint{
+		Predicate:   predicate,
+		IndexMethod: string(d.IndexMethod),
+		ColumnIDs:   make(descpb.ColumnIDs, len(d.Elems)),
+		Operators:   make([]string, len(d.Elems)),
+	}
+	colNames := make([]string, len(d.Elems))
+	for i := range d.Elems {
+		elem := &d.Elems[i]
+		if elem.Expr != nil {
+			return unimplemented.New("exclusion constraint expressions",
+				"expressions in exclusion constraints are not yet supported")
+		}
+		if elem.OpClass != "" {
+			return unimplemented.New("exclusion constraint opclass",
+				"operator classes in exclusion constraints are not yet supported")
+		}
+		if elem.Direction != tree.DefaultDirection || elem.NullsOrder != tree.DefaultNullsOrder {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"exclusion constraints do not support ordering options")
+		}
+		col, err := tbl.FindActiveOrNewColumnByName(elem.Column)
+		if err != nil {
+			return err
+		}
+		op := elem.Operator.Symbol
+		if _, ok := exclusionConstraintOperators[op]; !ok {
+			return errors.WithDetail(
+				pgerror.Newf(pgcode.WrongObjectType, "operator %s is not commutative", op),
+				"Only commutative operators can be used in exclusion constraints.",
+			)
+		}
+		lookupOp := op
+		if lookupOp == treecmp.NE {
+			lookupOp = treecmp.EQ
+		}
+		if _, ok := tree.CmpOps[lookupOp].LookupImpl(col.GetType(), col.GetType()); !ok {
+			return pgerror.Newf(pgcode.UndefinedFunction,
+				"operator does not exist: %s %s %s",
+				col.GetType().SQLString(), op, col.GetType().SQLString())
+		}
+		ec.ColumnIDs[i] = col.GetID()
+		ec.Operators[i] = op.String()
+		colNames[i] = col.GetName()
+	}
+
+	// Verify we are not writing a constraint over the same name.
+	constraintName := string(d.Name)
+	if constraintName == "" {
+		constraintName = tabledesc.GenerateUniqueName(
+			fmt.Sprintf("%s_%s_excl", tbl.Name, strings.Join(colNames, "_")),
+			func(p string) bool {
+				return catalog.FindConstraintByName(tbl, p) != nil
+			},
+		)
+	} else if c := catalog.FindConstraintByName(tbl, constraintName); c != nil {
+		return pgerror.Newf(pgcode.DuplicateObject, "duplicate constraint name: %q", constraintName)
+	}
+	ec.Name = constraintName
+
+	ec.ConstraintID = tbl.NextConstraintID
+	tbl.NextConstraintID++
+	if ts == NewTable {
+		ec.Validity = descpb.ConstraintValidity_Validated
+		tbl.Exclusions = append(tbl.Exclusions, ec)
+	} else {
+		ec.Validity = descpb.ConstraintValidity_Validating
+		tbl.AddExclusionMutation(&ec, descpb.DescriptorMutation_ADD)
+	}
+	return nil
+}
+
 // ResolveFK looks up the tables and columns mentioned in a `REFERENCES`
 // constraint and adds metadata representing that constraint to the descriptor.
 // It may, in doing so, add to or alter descriptors in the passed in `backrefs`
@@ -2039,7 +2180,8 @@ func NewTableDesc(
 					return nil, err
 				}
 			}
-		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef:
+		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef,
+			*tree.ExclusionConstraintTableDef:
 			// pass, handled below.
 
 		default:
@@ -2225,6 +2367,13 @@ func NewTableDesc(
 				return nil, err
 			}
 
+		case *tree.ExclusionConstraintTableDef:
+			if err := addExclusionConstraintTableDef(
+				ctx, evalCtx, d, &desc, n.Table, NewTable, semaCtx,
+			); err != nil {
+				return nil, err
+			}
+
 		default:
 			return nil, errors.Errorf("unsupported table def: %T", def)
 		}
@@ -2280,6 +2429,9 @@ func NewTableDesc(
 			if idx.InvertedColumnKind() == catpb.InvertedIndexColumnKind_TRIGRAM {
 				telemetry.Inc(sqltelemetry.TrigramInvertedIndexCounter)
 			}
+			if vectorConfig := idx.GetVectorConfig(); !vectorConfig.IsEmpty() {
+				telemetry.Inc(sqltelemetry.VectorInvertedIndexCounter)
+			}
 			if idx.IsPartial() {
 				telemetry.Inc(sqltelemetry.PartialInvertedIndexCounter)
 			}
@@ -2337,6 +2489,13 @@ func newTableDesc(
 		return nil, err
 	}
 
+	// Tables created with INHERITS or PARTITION OF get the columns of the
+	// tables they inherit from through LIKE table defs.
+	localCols, err := prepareCreateTableInheritance(params, n)
+	if err != nil {
+		return nil, err
+	}
+
 	newDefs, err := replaceLikeTableOpts(n, params)
 	if err != nil {
 		return nil, err
@@ -2349,6 +2508,12 @@ func newTableDesc(
 		n.Defs = newDefs
 	}
 
+	if len(n.Inherits) > 0 {
+		if err := mergeInheritedColumnDefs(params, n, localCols); err != nil {
+			return nil, err
+		}
+	}
+
 	// Process any SERIAL columns to remove the SERIAL type, as required by
 	// NewTableDesc.
 	colNameToOwnedSeq, err := createSequencesForSerialColumns(
@@ -2394,6 +2559,10 @@ func newTableDesc(
 		return nil, err
 	}
 
+	if err := params.p.finishCreateTableInheritance(params.ctx, n, ret); err != nil {
+		return nil, err
+	}
+
 	// We need to ensure sequence ownerships so that column owned sequences are
 	// correctly dropped when a column/table is dropped.
 	for colName, seqDesc := range colNameToOwnedSeq {
@@ -0,0 +1,30 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+// CreateTrigger creates a trigger. Triggers can be parsed, but are not yet
+// stored or executed.
+func (p *planner) CreateTrigger(ctx context.Context, n *tree.CreateTrigger) (planNode, error) {
+	return nil, unimplemented.NewWithIssue(28296, "CREATE TRIGGER")
+}
+
+// DropTrigger drops a trigger. Triggers can be parsed, but are not yet
+// stored or executed.
+func (p *planner) DropTrigger(ctx context.Context, n *tree.DropTrigger) (planNode, error) {
+	return nil, unimplemented.NewWithIssue(28296, "DROP TRIGGER")
+}
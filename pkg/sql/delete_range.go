@@ -120,7 +120,7 @@ func (d *deleteRangeNode) startExec(params runParams) error {
 		for len(spans) != 0 {
 			b := params.p.txn.NewBatch()
 			b.Header.MaxSpanRequestKeys = row.TableTruncateChunkSize
-			b.Header.LockTimeout = params.SessionData().LockTimeout
+			b.Header.LockTimeout = row.GetLockTimeout(params.SessionData().LockTimeout, d.desc.GetLockTimeout())
 			d.deleteSpans(params, b, spans)
 			if err := params.p.txn.Run(ctx, b); err != nil {
 				return row.ConvertBatchError(ctx, d.desc, b)
@@ -141,7 +141,7 @@ func (d *deleteRangeNode) startExec(params runParams) error {
 		// the optimizer only enables autoCommit if the maximum possible number of
 		// keys to delete in this command are low, so we're made safe.
 		b := params.p.txn.NewBatch()
-		b.Header.LockTimeout = params.SessionData().LockTimeout
+		b.Header.LockTimeout = row.GetLockTimeout(params.SessionData().LockTimeout, d.desc.GetLockTimeout())
 		d.deleteSpans(params, b, spans)
 		if err := params.p.txn.CommitInBatch(ctx, b); err != nil {
 			return row.ConvertBatchError(ctx, d.desc, b)
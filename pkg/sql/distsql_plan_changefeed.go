@@ -513,6 +513,20 @@ func (d *familyTableDescriptor) EnforcedUniqueConstraintsWithoutIndex() []catalo
 	return filtered
 }
 
+// EnforcedExclusionConstraints implements catalog.TableDescriptor interface.
+// This implementation filters out constraints that reference columns outside of
+// target column family.
+func (d *familyTableDescriptor) EnforcedExclusionConstraints() []catalog.ExclusionConstraint {
+	constraints := d.TableDescriptor.EnforcedExclusionConstraints()
+	filtered := make([]catalog.ExclusionConstraint, 0, len(constraints))
+	for _, c := range constraints {
+		if c.CollectKeyColumnIDs().SubsetOf(d.includeSet) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
 // FindColumnWithID implements catalog.TableDescriptor and provides
 // access to extra CDC columns.
 func (d *familyTableDescriptor) FindColumnWithID(id descpb.ColumnID) (catalog.Column, error) {
@@ -93,6 +93,9 @@ func (p *planner) DropTable(ctx context.Context, n *tree.DropTable) (planNode, e
 		if err := p.canRemoveAllTableOwnedSequences(ctx, droppedDesc, n.DropBehavior); err != nil {
 			return nil, err
 		}
+		if err := p.canDropInheritanceParent(ctx, droppedDesc, td, n.DropBehavior); err != nil {
+			return nil, err
+		}
 
 	}
 
@@ -113,7 +116,9 @@ func (n *dropTableNode) startExec(params runParams) error {
 	ctx := params.ctx
 	for _, toDel := range n.td {
 		droppedDesc := toDel.desc
-		if droppedDesc == nil {
+		// Tables which inherit from other tables in the list may already have
+		// been dropped along with them.
+		if droppedDesc == nil || droppedDesc.Dropped() {
 			continue
 		}
 
@@ -317,6 +322,14 @@ func (p *planner) dropTableImpl(
 		}
 	}
 
+	// Remove the inheritance relationships of the table, and drop the tables
+	// which inherit from it.
+	cascadedTables, err := p.dropTableInheritance(ctx, tableDesc, droppingParent, jobDesc, behavior)
+	if err != nil {
+		return droppedViews, err
+	}
+	droppedViews = append(droppedViews, cascadedTables...)
+
 	b := p.Txn().NewBatch()
 	if err := p.descCollection.DeleteTableComments(
 		ctx, p.ExtendedEvalContext().Tracing.KVTracingEnabled(), b, tableDesc.GetID(),
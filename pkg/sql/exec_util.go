@@ -75,6 +75,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/optionalnodeliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
@@ -1321,6 +1322,10 @@ type ExecutorConfig struct {
 
 	RangeFeedFactory *rangefeed.Factory
 
+	// NotificationRegistry delivers the notifications sent with NOTIFY to the
+	// sessions on this node that LISTEN on their channel.
+	NotificationRegistry *notify.Registry
+
 	// VersionUpgradeHook is called after validating a `SET CLUSTER SETTING
 	// version` but before executing it. It can carry out arbitrary upgrades
 	// that allow us to eventually remove legacy code.
@@ -1987,6 +1992,8 @@ func checkResultType(typ *types.T) error {
 	case types.TSVectorFamily:
 	case types.IntervalFamily:
 	case types.JsonFamily:
+	case types.JsonpathFamily:
+	case types.PGVectorFamily:
 	case types.UuidFamily:
 	case types.INetFamily:
 	case types.OidFamily:
@@ -3460,6 +3467,10 @@ func (m *sessionDataMutator) SetMaxRetriesForReadCommitted(val int32) {
 	m.data.MaxRetriesForReadCommitted = val
 }
 
+func (m *sessionDataMutator) SetVectorSearchProbes(val int64) {
+	m.data.VectorSearchProbes = val
+}
+
 func (m *sessionDataMutator) SetJoinReaderOrderingStrategyBatchSize(val int64) {
 	m.data.JoinReaderOrderingStrategyBatchSize = val
 }
@@ -332,6 +332,16 @@ func (*DummyEvalPlanner) Optimizer() interface{} {
 	return nil
 }
 
+// SendNotification is part of the eval.Planner interface.
+func (*DummyEvalPlanner) SendNotification(ctx context.Context, channel, payload string) error {
+	return errors.WithStack(errEvalPlanner)
+}
+
+// ListeningChannels is part of the eval.Planner interface.
+func (*DummyEvalPlanner) ListeningChannels() []string {
+	return nil
+}
+
 var _ eval.Planner = &DummyEvalPlanner{}
 
 var errEvalPlanner = pgerror.New(pgcode.ScalarOperationCannotRunWithoutFullSessionContext,
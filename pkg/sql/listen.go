@@ -0,0 +1,151 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// listenState tracks the channels a session listens on.
+type listenState struct {
+	// listener is nil if the session cannot receive notifications, which is
+	// the case of internal sessions.
+	listener *notify.Listener
+	// pending contains the LISTEN and UNLISTEN operations executed by the
+	// current transaction, in order. They take effect when it commits.
+	pending []listenOp
+}
+
+type listenOp struct {
+	// channel is empty for UNLISTEN *.
+	channel string
+	listen  bool
+}
+
+// commit applies the operations executed by the transaction that just
+// committed.
+func (s *listenState) commit(ctx context.Context) {
+	for _, op := range s.pending {
+		switch {
+		case op.listen:
+			// The channel and the feed were already checked by LISTEN, so this
+			// is not expected to fail.
+			if err := s.listener.Listen(ctx, op.channel); err != nil {
+				log.Warningf(ctx, "failed to listen on channel %q: %v", op.channel, err)
+			}
+		case op.channel == "":
+			s.listener.UnlistenAll()
+		default:
+			s.listener.Unlisten(op.channel)
+		}
+	}
+	s.pending = nil
+}
+
+// close stops listening on all channels.
+func (s *listenState) close() {
+	s.pending = nil
+	if s.listener != nil {
+		s.listener.Close()
+	}
+}
+
+// Listen implements the LISTEN statement.
+func (p *planner) Listen(ctx context.Context, n *tree.Listen) (planNode, error) {
+	s := p.extendedEvalCtx.listenState
+	if s == nil || s.listener == nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported, "LISTEN is not supported in this session")
+	}
+	return &listenNode{state: s, op: listenOp{channel: string(n.ChannelName), listen: true}}, nil
+}
+
+// Unlisten implements the UNLISTEN statement.
+func (p *planner) Unlisten(ctx context.Context, n *tree.Unlisten) (planNode, error) {
+	s := p.extendedEvalCtx.listenState
+	if s == nil || s.listener == nil {
+		// The session cannot listen on any channel, so there is nothing to do.
+		return newZeroNode(nil /* columns */), nil
+	}
+	var op listenOp
+	if !n.Star && n.ChannelName != nil {
+		op.channel = n.ChannelName.Object()
+	}
+	return &listenNode{state: s, op: op}, nil
+}
+
+type listenNode struct {
+	state *listenState
+	op    listenOp
+}
+
+func (n *listenNode) startExec(params runParams) error {
+	if n.op.listen {
+		if err := n.state.listener.Prepare(params.ctx, n.op.channel); err != nil {
+			return err
+		}
+	}
+	n.state.pending = append(n.state.pending, n.op)
+	return nil
+}
+
+func (n *listenNode) Next(_ runParams) (bool, error) { return false, nil }
+func (n *listenNode) Values() tree.Datums            { return nil }
+func (n *listenNode) Close(_ context.Context)        {}
+
+// Notify implements the NOTIFY statement.
+func (p *planner) Notify(ctx context.Context, n *tree.Notify) (planNode, error) {
+	return &notifyNode{channel: string(n.ChannelName), payload: n.Payload}, nil
+}
+
+type notifyNode struct {
+	channel, payload string
+}
+
+func (n *notifyNode) startExec(params runParams) error {
+	return params.p.SendNotification(params.ctx, n.channel, n.payload)
+}
+
+func (n *notifyNode) Next(_ runParams) (bool, error) { return false, nil }
+func (n *notifyNode) Values() tree.Datums            { return nil }
+func (n *notifyNode) Close(_ context.Context)        {}
+
+// SendNotification is part of the eval.Planner interface.
+func (p *planner) SendNotification(ctx context.Context, channel, payload string) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V23_2_NotificationsTable) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"NOTIFY is not supported until the cluster upgrade is finalized")
+	}
+	if p.EvalContext().TxnReadOnly {
+		return pgerror.New(pgcode.ReadOnlySQLTransaction,
+			"cannot execute NOTIFY in a read-only transaction")
+	}
+	return notify.Send(ctx, p.InternalSQLTxn(), notify.Notification{
+		Channel:   channel,
+		Payload:   payload,
+		SenderPID: int32(p.EvalContext().QueryCancelKey.GetPGBackendPID()),
+	})
+}
+
+// ListeningChannels is part of the eval.Planner interface.
+func (p *planner) ListeningChannels() []string {
+	s := p.extendedEvalCtx.listenState
+	if s == nil || s.listener == nil {
+		return nil
+	}
+	return s.listener.Channels()
+}
@@ -0,0 +1,163 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package notify
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// maxPending is the maximum number of notifications queued on a listener that
+// have not been delivered to its client yet. Notifications received beyond
+// that are dropped.
+const maxPending = 10000
+
+var droppedLogEvery = log.Every(10 * time.Second)
+
+// Listener receives the notifications sent on the channels a session listens
+// on.
+//
+// Lock ordering: Registry.mu is acquired before Listener.mu.
+type Listener struct {
+	registry *Registry
+	// signal is notified when notifications are queued.
+	signal chan struct{}
+
+	mu struct {
+		syncutil.Mutex
+		// channels maps each channel listened on to the timestamp from which
+		// notifications sent on it are received.
+		channels map[string]hlc.Timestamp
+		// pending contains the notifications that have not been delivered yet.
+		pending []Notification
+		closed  bool
+	}
+}
+
+// Prepare returns an error if Listen would fail for channel. It is used to
+// report errors when LISTEN is executed, since it only takes effect when the
+// transaction commits.
+func (l *Listener) Prepare(ctx context.Context, channel string) error {
+	if err := ValidateChannel(channel); err != nil {
+		return err
+	}
+	return l.registry.startFeed(ctx)
+}
+
+// Listen starts listening on channel. It is a no-op if the listener already
+// listens on channel.
+func (l *Listener) Listen(ctx context.Context, channel string) error {
+	if err := ValidateChannel(channel); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	_, ok := l.mu.channels[channel]
+	l.mu.Unlock()
+	if ok {
+		return nil
+	}
+	ts, err := l.registry.register(ctx, l, channel)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.mu.channels[channel]; !ok {
+		l.mu.channels[channel] = ts
+	}
+	return nil
+}
+
+// Unlisten stops listening on channel. Notifications sent on channel that are
+// already queued are still delivered.
+func (l *Listener) Unlisten(channel string) {
+	l.mu.Lock()
+	_, ok := l.mu.channels[channel]
+	delete(l.mu.channels, channel)
+	l.mu.Unlock()
+	if ok {
+		l.registry.unregister(l, channel)
+	}
+}
+
+// UnlistenAll stops listening on all channels.
+func (l *Listener) UnlistenAll() {
+	channels := l.Channels()
+	l.mu.Lock()
+	l.mu.channels = make(map[string]hlc.Timestamp)
+	l.mu.Unlock()
+	l.registry.unregister(l, channels...)
+}
+
+// Channels returns the sorted list of channels listened on.
+func (l *Listener) Channels() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	channels := make([]string, 0, len(l.mu.channels))
+	for channel := range l.mu.channels {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// Signal returns a channel that receives a value when notifications are
+// queued. The notifications are retrieved with TakePending.
+func (l *Listener) Signal() <-chan struct{} {
+	return l.signal
+}
+
+// TakePending returns the queued notifications, in the order in which they
+// were received, and clears the queue.
+func (l *Listener) TakePending() []Notification {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	pending := l.mu.pending
+	l.mu.pending = nil
+	return pending
+}
+
+// Close stops listening on all channels and drops the queued notifications.
+func (l *Listener) Close() {
+	l.UnlistenAll()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.mu.closed = true
+	l.mu.pending = nil
+}
+
+// enqueue queues a notification committed at ts, if the listener was
+// listening on its channel at that time.
+func (l *Listener) enqueue(n Notification, ts hlc.Timestamp) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	listenTS, ok := l.mu.channels[n.Channel]
+	if !ok || l.mu.closed || !listenTS.Less(ts) {
+		return
+	}
+	if len(l.mu.pending) >= maxPending {
+		if droppedLogEvery.ShouldLog() {
+			log.Warningf(context.Background(),
+				"dropping notification on channel %q: too many pending notifications", n.Channel)
+		}
+		return
+	}
+	l.mu.pending = append(l.mu.pending, n)
+	select {
+	case l.signal <- struct{}{}:
+	default:
+	}
+}
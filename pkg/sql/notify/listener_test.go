@@ -0,0 +1,98 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package notify
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListener(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	manual := hlc.NewHybridManualClock()
+	clock := hlc.NewClock(manual, time.Nanosecond /* maxOffset */, time.Nanosecond /* toleratedOffset */)
+	st := cluster.MakeTestingClusterSettings()
+	// The rangefeed is not started without a rangefeed factory, so
+	// notifications are delivered directly.
+	r := NewRegistry(keys.SystemSQLCodec, clock, st, stopper, nil /* db */, nil /* rangeFeedFactory */, nil /* tableIDResolver */)
+
+	l1, l2 := r.NewListener(), r.NewListener()
+	defer l1.Close()
+	defer l2.Close()
+
+	sentBeforeListen := clock.Now()
+	manual.Increment(10)
+	require.NoError(t, l1.Listen(ctx, "a"))
+	require.NoError(t, l1.Listen(ctx, "b"))
+	require.NoError(t, l1.Listen(ctx, "a"))
+	require.NoError(t, l2.Listen(ctx, "b"))
+	require.Equal(t, []string{"a", "b"}, l1.Channels())
+	require.Equal(t, []string{"b"}, l2.Channels())
+
+	// Notifications committed before LISTEN are not delivered.
+	r.deliver(Notification{Channel: "a", Payload: "old"}, sentBeforeListen)
+	require.Empty(t, l1.TakePending())
+
+	manual.Increment(10)
+	r.deliver(Notification{Channel: "a", Payload: "1", SenderPID: 7}, clock.Now())
+	r.deliver(Notification{Channel: "b", Payload: "2"}, clock.Now())
+	r.deliver(Notification{Channel: "c", Payload: "3"}, clock.Now())
+
+	select {
+	case <-l1.Signal():
+	default:
+		t.Fatal("expected listener to be signaled")
+	}
+	require.Equal(t, []Notification{
+		{Channel: "a", Payload: "1", SenderPID: 7},
+		{Channel: "b", Payload: "2"},
+	}, l1.TakePending())
+	require.Empty(t, l1.TakePending())
+	require.Equal(t, []Notification{{Channel: "b", Payload: "2"}}, l2.TakePending())
+
+	l1.Unlisten("a")
+	require.Equal(t, []string{"b"}, l1.Channels())
+	r.deliver(Notification{Channel: "a", Payload: "4"}, clock.Now())
+	require.Empty(t, l1.TakePending())
+
+	l1.UnlistenAll()
+	require.Empty(t, l1.Channels())
+	r.deliver(Notification{Channel: "b", Payload: "5"}, clock.Now())
+	require.Empty(t, l1.TakePending())
+	require.Equal(t, []Notification{{Channel: "b", Payload: "5"}}, l2.TakePending())
+
+	// The number of pending notifications is bounded.
+	for i := 0; i < maxPending+10; i++ {
+		r.deliver(Notification{Channel: "b"}, clock.Now())
+	}
+	require.Len(t, l2.TakePending(), maxPending)
+
+	// Channel names are validated.
+	require.Error(t, l1.Listen(ctx, ""))
+	require.Error(t, l1.Listen(ctx, strings.Repeat("x", MaxChannelLength+1)))
+	require.NoError(t, l1.Listen(ctx, strings.Repeat("x", MaxChannelLength)))
+}
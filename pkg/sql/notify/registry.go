@@ -0,0 +1,372 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package notify implements the delivery of the notifications sent with
+// NOTIFY and pg_notify to the sessions that LISTEN on their channel.
+//
+// Notifications are written to system.notifications by the notifying
+// transaction, so they become visible only if and when that transaction
+// commits. Each node that has at least one listening session runs a rangefeed
+// over that table and fans the notifications out to its local listeners. Rows
+// are deleted by a background task once they are older than
+// sql.notifications.retention; the rangefeed only needs the rows to exist
+// long enough to be emitted.
+package notify
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/valueside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+)
+
+// MaxChannelLength is the maximum length of a channel name, in bytes. It
+// matches the maximum length of an identifier in Postgres.
+const MaxChannelLength = 63
+
+// MaxPayloadLength is the maximum length of a notification payload, in bytes.
+// It matches the limit imposed by Postgres.
+const MaxPayloadLength = 7999
+
+// gcBatchSize is the maximum number of rows deleted from system.notifications
+// by a single statement.
+const gcBatchSize = 1000
+
+// Retention is the amount of time for which notifications are retained in
+// system.notifications.
+var Retention = settings.RegisterDurationSetting(
+	settings.TenantWritable,
+	"sql.notifications.retention",
+	"the amount of time for which notifications sent with NOTIFY are kept in "+
+		"system.notifications before being deleted",
+	5*time.Minute,
+	settings.PositiveDuration,
+)
+
+// Notification is a message sent with NOTIFY or pg_notify.
+type Notification struct {
+	Channel string
+	Payload string
+	// SenderPID identifies the session that sent the notification. It is the
+	// value returned by pg_backend_pid() in that session.
+	SenderPID int32
+}
+
+// ValidateChannel returns an error if the given channel name cannot be used
+// with LISTEN or NOTIFY.
+func ValidateChannel(channel string) error {
+	if channel == "" {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name cannot be empty")
+	}
+	if len(channel) > MaxChannelLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name too long")
+	}
+	return nil
+}
+
+// Send writes a notification using the given transaction. Listeners receive
+// it once the transaction commits. Identical notifications sent by the same
+// transaction are collapsed into one.
+func Send(ctx context.Context, txn isql.Txn, n Notification) error {
+	if err := ValidateChannel(n.Channel); err != nil {
+		return err
+	}
+	if len(n.Payload) > MaxPayloadLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "payload string too long")
+	}
+	_, err := txn.ExecEx(
+		ctx, "send-notification", txn.KV(), sessiondata.NodeUserSessionDataOverride,
+		`INSERT INTO system.notifications (txn_id, channel, payload, sender_pid)
+VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`,
+		tree.NewDUuid(tree.DUuid{UUID: txn.KV().ID()}), n.Channel, n.Payload, n.SenderPID,
+	)
+	return err
+}
+
+// Registry tracks the sessions on this node that listen for notifications
+// and delivers the notifications read from system.notifications to them.
+type Registry struct {
+	codec            keys.SQLCodec
+	clock            *hlc.Clock
+	settings         *cluster.Settings
+	stopper          *stop.Stopper
+	db               isql.DB
+	rangeFeedFactory *rangefeed.Factory
+	tableIDResolver  catalog.SystemTableIDResolver
+	decoder          rowDecoder
+
+	mu struct {
+		syncutil.Mutex
+		// feed is the rangefeed over system.notifications. It is started when a
+		// session on this node first listens on a channel, and then runs until
+		// the node shuts down.
+		feed *rangefeed.RangeFeed
+		// feedStart is the timestamp at which feed was started.
+		feedStart hlc.Timestamp
+		// listeners maps each channel to the listeners registered on it.
+		listeners map[string]map[*Listener]struct{}
+	}
+}
+
+// NewRegistry creates a new Registry.
+func NewRegistry(
+	codec keys.SQLCodec,
+	clock *hlc.Clock,
+	settings *cluster.Settings,
+	stopper *stop.Stopper,
+	db isql.DB,
+	rangeFeedFactory *rangefeed.Factory,
+	tableIDResolver catalog.SystemTableIDResolver,
+) *Registry {
+	r := &Registry{
+		codec:            codec,
+		clock:            clock,
+		settings:         settings,
+		stopper:          stopper,
+		db:               db,
+		rangeFeedFactory: rangeFeedFactory,
+		tableIDResolver:  tableIDResolver,
+		decoder:          makeRowDecoder(codec),
+	}
+	r.mu.listeners = make(map[string]map[*Listener]struct{})
+	return r
+}
+
+// Start starts the background task that deletes expired rows from
+// system.notifications.
+func (r *Registry) Start(ctx context.Context) error {
+	return r.stopper.RunAsyncTask(ctx, "notifications-gc", r.runGC)
+}
+
+func (r *Registry) runGC(ctx context.Context) {
+	ctx, cancel := r.stopper.WithCancelOnQuiesce(ctx)
+	defer cancel()
+
+	var timer timeutil.Timer
+	defer timer.Stop()
+	for {
+		// Run about twice per retention period. The jitter avoids having all
+		// the nodes of the cluster delete the same rows at the same time.
+		interval := Retention.Get(&r.settings.SV) / 2
+		timer.Reset(time.Duration(float64(interval) * (0.75 + 0.5*rand.Float64())))
+		select {
+		case <-timer.C:
+			timer.Read = true
+			if err := r.deleteExpired(ctx); err != nil {
+				log.Warningf(ctx, "failed to delete expired notifications: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// deleteExpired deletes the rows of system.notifications that are older than
+// the retention period.
+func (r *Registry) deleteExpired(ctx context.Context) error {
+	if !r.settings.Version.IsActive(ctx, clusterversion.V23_2_NotificationsTable) {
+		return nil
+	}
+	cutoff := r.clock.PhysicalTime().Add(-Retention.Get(&r.settings.SV))
+	for {
+		n, err := r.db.Executor().ExecEx(
+			ctx, "delete-expired-notifications", nil /* txn */, sessiondata.NodeUserSessionDataOverride,
+			`DELETE FROM system.notifications WHERE created_at < $1 LIMIT $2`,
+			cutoff, gcBatchSize,
+		)
+		if err != nil || n < gcBatchSize {
+			return err
+		}
+	}
+}
+
+// NewListener creates a Listener that is not registered on any channel yet.
+func (r *Registry) NewListener() *Listener {
+	l := &Listener{
+		registry: r,
+		signal:   make(chan struct{}, 1),
+	}
+	l.mu.channels = make(map[string]hlc.Timestamp)
+	return l
+}
+
+// register registers l on channel, and returns the timestamp from which l
+// receives the notifications sent on channel.
+func (r *Registry) register(ctx context.Context, l *Listener, channel string) (hlc.Timestamp, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.maybeStartFeedLocked(ctx); err != nil {
+		return hlc.Timestamp{}, err
+	}
+	// Notifications sent by transactions that committed on other nodes shortly
+	// before this one started listening may have a higher timestamp than the
+	// current time on this node. Go back by the maximum clock offset so that
+	// they are not missed; receiving a notification sent a little before
+	// LISTEN is preferable to missing one sent after it.
+	listenTS := r.clock.Now().Add(-r.clock.MaxOffset().Nanoseconds(), 0)
+	if listenTS.Less(r.mu.feedStart) {
+		listenTS = r.mu.feedStart
+	}
+	listeners, ok := r.mu.listeners[channel]
+	if !ok {
+		listeners = make(map[*Listener]struct{})
+		r.mu.listeners[channel] = listeners
+	}
+	listeners[l] = struct{}{}
+	return listenTS, nil
+}
+
+// unregister unregisters l from the given channels.
+func (r *Registry) unregister(l *Listener, channels ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, channel := range channels {
+		listeners := r.mu.listeners[channel]
+		delete(listeners, l)
+		if len(listeners) == 0 {
+			delete(r.mu.listeners, channel)
+		}
+	}
+}
+
+// startFeed starts the rangefeed over system.notifications if it is not
+// running yet.
+func (r *Registry) startFeed(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.maybeStartFeedLocked(ctx)
+}
+
+func (r *Registry) maybeStartFeedLocked(ctx context.Context) error {
+	if r.mu.feed != nil || r.rangeFeedFactory == nil {
+		return nil
+	}
+	if !r.settings.Version.IsActive(ctx, clusterversion.V23_2_NotificationsTable) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"LISTEN is not supported until the cluster upgrade is finalized")
+	}
+	tableID, err := r.tableIDResolver.LookupSystemTableID(ctx, string(catconstants.NotificationsTableName))
+	if err != nil {
+		return err
+	}
+	prefix := r.codec.IndexPrefix(uint32(tableID), uint32(systemschema.NotificationsTable.GetPrimaryIndexID()))
+	// The feed outlives the statement that started it.
+	feedCtx := logtags.WithTags(context.Background(), logtags.FromContext(ctx))
+	start := r.clock.Now().Add(-r.clock.MaxOffset().Nanoseconds(), 0)
+	feed, err := r.rangeFeedFactory.RangeFeed(
+		feedCtx,
+		"notifications",
+		[]roachpb.Span{{Key: prefix, EndKey: prefix.PrefixEnd()}},
+		start,
+		r.onValue,
+		rangefeed.WithSystemTablePriority(),
+	)
+	if err != nil {
+		return err
+	}
+	r.mu.feed = feed
+	r.mu.feedStart = start
+	return nil
+}
+
+func (r *Registry) onValue(ctx context.Context, value *kvpb.RangeFeedValue) {
+	if !value.Value.IsPresent() {
+		// Rows are only ever deleted once they expire.
+		return
+	}
+	n, err := r.decoder.decode(roachpb.KeyValue{Key: value.Key, Value: value.Value})
+	if err != nil {
+		log.Warningf(ctx, "failed to decode notification %v: %v", value.Key, err)
+		return
+	}
+	r.deliver(n, value.Value.Timestamp)
+}
+
+// deliver queues the notification on every listener registered on its
+// channel. ts is the commit timestamp of the transaction that sent it.
+func (r *Registry) deliver(n Notification, ts hlc.Timestamp) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for l := range r.mu.listeners[n.Channel] {
+		l.enqueue(n, ts)
+	}
+}
+
+// rowDecoder decodes the rows of system.notifications.
+type rowDecoder struct {
+	codec   keys.SQLCodec
+	columns []catalog.Column
+	decoder valueside.Decoder
+	alloc   tree.DatumAlloc
+}
+
+func makeRowDecoder(codec keys.SQLCodec) rowDecoder {
+	columns := systemschema.NotificationsTable.PublicColumns()
+	return rowDecoder{
+		codec:   codec,
+		columns: columns,
+		decoder: valueside.MakeDecoder(columns),
+	}
+}
+
+// decode decodes a row of system.notifications. It is only called from the
+// rangefeed, one row at a time.
+func (d *rowDecoder) decode(kv roachpb.KeyValue) (Notification, error) {
+	// The channel and payload are part of the primary key.
+	keyTypes := []*types.T{d.columns[0].GetType(), d.columns[1].GetType(), d.columns[2].GetType()}
+	key, err := rowenc.DecodeIndexKeyToDatums(d.codec, keyTypes, nil /* colDirs */, kv.Key, &d.alloc)
+	if err != nil {
+		return Notification{}, errors.Wrap(err, "failed to decode key")
+	}
+	if len(key) != len(keyTypes) {
+		return Notification{}, errors.AssertionFailedf("expected %d key columns, found %d", len(keyTypes), len(key))
+	}
+	bytes, err := kv.Value.GetTuple()
+	if err != nil {
+		return Notification{}, err
+	}
+	datums, err := d.decoder.Decode(&d.alloc, bytes)
+	if err != nil {
+		return Notification{}, err
+	}
+	n := Notification{
+		Channel: string(tree.MustBeDString(key[1])),
+		Payload: string(tree.MustBeDString(key[2])),
+	}
+	if pid := datums[3]; pid != tree.DNull {
+		n.SenderPID = int32(tree.MustBeDInt(pid))
+	}
+	return n, nil
+}
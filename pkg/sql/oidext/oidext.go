@@ -8,8 +8,9 @@
 // by the Apache License, Version 2.0, included in the file
 // licenses/APL.txt.
 
-// Package oidext contains oids that are not in `github.com/lib/pq/oid`
-// as they are not shipped by default with postgres.
+// Package oidext contains oids that are not in `github.com/lib/pq/oid`,
+// either because they are not shipped by default with postgres or because
+// the package predates them.
 // As CRDB does not support extensions, we'll need to automatically assign
 // a few OIDs of our own.
 package oidext
@@ -34,6 +35,15 @@ const (
 	T__geography = oid.Oid(90003)
 	T_box2d      = oid.Oid(90004)
 	T__box2d     = oid.Oid(90005)
+	T_pgvector   = oid.Oid(90006)
+	T__pgvector  = oid.Oid(90007)
+)
+
+// OIDs in this block are postgres types that are missing from
+// `github.com/lib/pq/oid`. They use the same OIDs as in postgres.
+const (
+	T_jsonpath  = oid.Oid(4072)
+	T__jsonpath = oid.Oid(4073)
 )
 
 // ExtensionTypeName returns a mapping from extension oids
@@ -45,6 +55,10 @@ var ExtensionTypeName = map[oid.Oid]string{
 	T__geography: "_GEOGRAPHY",
 	T_box2d:      "BOX2D",
 	T__box2d:     "_BOX2D",
+	T_pgvector:   "VECTOR",
+	T__pgvector:  "_VECTOR",
+	T_jsonpath:   "JSONPATH",
+	T__jsonpath:  "_JSONPATH",
 }
 
 // TypeName checks the name for a given type by first looking up oid.TypeName
@@ -15,6 +15,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 )
 
 // IndexOrdinal identifies an index (in the context of a Table).
@@ -194,6 +195,10 @@ type Index interface {
 	// describes the configuration for this geospatial inverted index.
 	GeoConfig() geoindex.Config
 
+	// VectorConfig returns a vector index configuration. If not empty, it
+	// describes the configuration for this vector inverted index.
+	VectorConfig() vecindex.Config
+
 	// Version returns the IndexDescriptorVersion of the index.
 	Version() descpb.IndexDescriptorVersion
 
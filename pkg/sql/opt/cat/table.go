@@ -15,6 +15,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
 
@@ -139,6 +140,28 @@ type Table interface {
 	// i < UniqueCount.
 	Unique(i UniqueOrdinal) UniqueConstraint
 
+	// ExclusionConstraintCount returns the number of exclusion constraints
+	// defined on this table.
+	ExclusionConstraintCount() int
+
+	// ExclusionConstraint returns the ith exclusion constraint defined on this
+	// table, where i < ExclusionConstraintCount.
+	ExclusionConstraint(i int) ExclusionConstraint
+
+	// InheritanceChildCount returns the number of tables which directly
+	// inherit from this table. Scans of this table also return the rows of
+	// those tables and of their own descendants.
+	InheritanceChildCount() int
+
+	// InheritanceChild returns the ID of the ith table which directly inherits
+	// from this table, where i < InheritanceChildCount.
+	InheritanceChild(i int) StableID
+
+	// IsPartitioned returns true if the rows of this table are stored in
+	// partitions which are separate tables inheriting from it. Such a table
+	// holds no rows of its own.
+	IsPartitioned() bool
+
 	// Zone returns a table's zone.
 	Zone() Zone
 
@@ -367,6 +390,38 @@ type UniqueConstraint interface {
 	UniquenessGuaranteedByAnotherIndex() bool
 }
 
+// ExclusionConstraint represents an exclusion constraint, which guarantees
+// that no two rows of the table satisfy all of the constraint's comparisons.
+// For example, the following statement creates an exclusion constraint which
+// ensures that no two rows have equal values in column a and overlapping
+// values in column b:
+//
+//	ALTER TABLE t ADD CONSTRAINT e EXCLUDE (a WITH =, b WITH &&);
+//
+// Exclusion constraints are not backed by an index. In order to enforce them,
+// the optimizer must add a check as a postquery to any query that inserts into
+// or updates their columns.
+type ExclusionConstraint interface {
+	// Name of the exclusion constraint.
+	Name() string
+
+	// ColumnCount returns the number of columns in this constraint.
+	ColumnCount() int
+
+	// ColumnOrdinal returns the table column ordinal of the ith column in this
+	// constraint.
+	ColumnOrdinal(tab Table, i int) int
+
+	// Operator returns the operator with which the ith column of a row is
+	// compared to the ith column of other rows.
+	Operator(i int) treecmp.ComparisonOperatorSymbol
+
+	// Predicate returns the partial predicate expression and true if the
+	// constraint is a partial exclusion constraint. If it is not, the empty
+	// string and false are returned.
+	Predicate() (string, bool)
+}
+
 // UniqueOrdinal identifies a unique constraint (in the context of a Table).
 type UniqueOrdinal = int
 
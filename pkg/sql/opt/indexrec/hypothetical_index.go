@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
 	"github.com/cockroachdb/errors"
 )
@@ -227,6 +228,17 @@ func (hi *hypotheticalIndex) GeoConfig() geoindex.Config {
 	return geoindex.Config{}
 }
 
+// VectorConfig is part of the cat.Index interface.
+func (hi *hypotheticalIndex) VectorConfig() vecindex.Config {
+	if hi.IsInverted() {
+		srcCol := hi.tab.Column(hi.InvertedColumn().InvertedSourceColumnOrdinal())
+		if srcCol.DatumType().Family() == types.PGVectorFamily {
+			return *vecindex.DefaultConfig()
+		}
+	}
+	return vecindex.Config{}
+}
+
 // Version is part of the cat.Index interface.
 func (hi *hypotheticalIndex) Version() descpb.IndexDescriptorVersion {
 	return descpb.LatestIndexDescriptorVersion
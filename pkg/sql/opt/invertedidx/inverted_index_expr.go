@@ -193,6 +193,10 @@ func TryJoinInvertedIndex(
 	if !index.IsInverted() {
 		return nil
 	}
+	if vectorConfig := index.VectorConfig(); !vectorConfig.IsEmpty() {
+		// Vector indexes can only be used for nearest neighbor searches.
+		return nil
+	}
 
 	config := index.GeoConfig()
 	var joinPlanner invertedJoinPlanner
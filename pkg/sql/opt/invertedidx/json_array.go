@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
 	"github.com/cockroachdb/errors"
 )
 
@@ -400,6 +401,10 @@ func (j *jsonOrArrayFilterPlanner) extractInvertedFilterConditionFromLeaf(
 		}
 	case *memo.OverlapsExpr:
 		invertedExpr = j.extractArrayOverlapsCondition(ctx, evalCtx, t.Left, t.Right)
+	case *memo.JsonPathMatchExpr:
+		invertedExpr = j.extractJSONPathCondition(ctx, evalCtx, t.Left, t.Right, true /* predicate */)
+	case *memo.JsonPathExistsExpr:
+		invertedExpr = j.extractJSONPathCondition(ctx, evalCtx, t.Left, t.Right, false /* predicate */)
 	}
 
 	if invertedExpr == nil {
@@ -559,6 +564,88 @@ func (j *jsonOrArrayFilterPlanner) extractJSONExistsCondition(
 	return inverted.NonInvertedColExpression{}
 }
 
+// maxLaxJSONPathKeys is the maximum number of keys in a lax mode jsonpath for
+// which extractJSONPathCondition generates an inverted expression. The number
+// of containment objects grows exponentially with the number of keys.
+const maxLaxJSONPathKeys = 3
+
+// extractJSONPathCondition extracts an InvertedExpression representing an
+// inverted filter with the jsonpath @@ or @? operator over the planner's
+// inverted index, based on the given left and right expression arguments. The
+// type of operator is indicated by the predicate parameter, which is true for
+// @@ and false for @?. Returns an empty InvertedExpression if no inverted
+// filter could be extracted.
+//
+// An inverted expression is only generated for paths that compare the value
+// at a chain of object keys to a constant, such as:
+//
+//	j @@ '$.a.b == 1'
+//	j @? '$.a ? (@.b == 1)'
+//
+// Both of these filters are equivalent to j @> '{"a": {"b": 1}}' in strict
+// mode. In lax mode, arrays are unwrapped when the keys are followed and when
+// the value is compared, so the filter is the disjunction of the containment
+// of every combination of wrapping these objects and the value in arrays,
+// such as j @> '[{"a": {"b": 1}}]' and j @> '{"a": [{"b": [1]}]}'.
+func (j *jsonOrArrayFilterPlanner) extractJSONPathCondition(
+	ctx context.Context, evalCtx *eval.Context, left, right opt.ScalarExpr, predicate bool,
+) inverted.Expression {
+	if !isIndexColumn(j.tabID, j.index, left, j.computedColumns) || !memo.CanExtractConstDatum(right) {
+		return inverted.NonInvertedColExpression{}
+	}
+	jp, ok := memo.ExtractConstDatum(right).(*tree.DJsonpath)
+	if !ok {
+		return inverted.NonInvertedColExpression{}
+	}
+	keys, val, ok := jsonpath.KeyEquality(&jp.Jsonpath, predicate)
+	if !ok || (!jp.Strict && len(keys) > maxLaxJSONPathKeys) {
+		return inverted.NonInvertedColExpression{}
+	}
+
+	lax := !jp.Strict
+	objs := []json.JSON{val}
+	if lax {
+		objs = append(objs, wrapInArray(val))
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		next := make([]json.JSON, 0, 2*len(objs))
+		for _, o := range objs {
+			b := json.NewObjectBuilder(1)
+			b.Add(keys[i], o)
+			obj := b.Build()
+			next = append(next, obj)
+			if lax {
+				next = append(next, wrapInArray(obj))
+			}
+		}
+		objs = next
+	}
+
+	var invertedExpr inverted.Expression
+	for _, obj := range objs {
+		expr := getInvertedExprForJSONOrArrayIndexForContaining(ctx, evalCtx, tree.NewDJSON(obj))
+		if invertedExpr == nil {
+			invertedExpr = expr
+		} else {
+			invertedExpr = inverted.Or(invertedExpr, expr)
+		}
+	}
+
+	// The original filter must always be applied after the inverted index scan,
+	// since containment does not imply that the path matches. For example,
+	// '{"a": [1, 2]}' contains '{"a": [2]}' but does not match the strict mode
+	// path '$.a == 2'.
+	invertedExpr.SetNotTight()
+	return invertedExpr
+}
+
+// wrapInArray returns a JSON array with val as its single element.
+func wrapInArray(val json.JSON) json.JSON {
+	b := json.NewArrayBuilder(1)
+	b.Add(val)
+	return b.Build()
+}
+
 // extractJSONEqCondition extracts an InvertedExpression representing an
 // inverted filter over the planner's inverted index, based on equality between
 // two scalar expressions. If an InvertedExpression cannot be generated from the
@@ -0,0 +1,70 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// TryVectorSearchIndex tries to derive the spans of the given vector index
+// that must be scanned to find the nearest neighbors of a constant query
+// vector, according to the distance computed by expr. It returns ok=false if
+// expr is not a vector distance operator that compares the indexed column
+// with a constant vector, or if the index is not a vector index without
+// prefix columns.
+//
+// The returned spans contain the probes lists whose centroids are closest to
+// the query vector. Since every indexed vector has a single index entry, the
+// rows produced by a scan of the spans never contain duplicates.
+func TryVectorSearchIndex(
+	tabID opt.TableID, index cat.Index, expr opt.ScalarExpr, probes int,
+) (spans inverted.Spans, ok bool) {
+	config := index.VectorConfig()
+	if config.IsEmpty() || index.NonInvertedPrefixColumnCount() > 0 {
+		return nil, false
+	}
+	var left, right opt.ScalarExpr
+	switch e := expr.(type) {
+	case *memo.VectorDistanceExpr:
+		left, right = e.Left, e.Right
+	case *memo.VectorCosDistanceExpr:
+		left, right = e.Left, e.Right
+	case *memo.VectorNegInnerProductExpr:
+		left, right = e.Left, e.Right
+	default:
+		return nil, false
+	}
+	// All of the distance operators are commutative.
+	var constantVal opt.ScalarExpr
+	if isIndexColumn(tabID, index, left, nil /* computedColumns */) && memo.CanExtractConstDatum(right) {
+		constantVal = right
+	} else if isIndexColumn(tabID, index, right, nil /* computedColumns */) && memo.CanExtractConstDatum(left) {
+		constantVal = left
+	} else {
+		return nil, false
+	}
+	q, ok := memo.ExtractConstDatum(constantVal).(*tree.DPGVector)
+	if !ok {
+		// The query vector is NULL.
+		return nil, false
+	}
+	lists := config.Probe(q.T, probes)
+	spans = make(inverted.Spans, len(lists))
+	for i, list := range lists {
+		spans[i] = inverted.MakeSingleValSpan(rowenc.EncodeVectorInvertedIndexKey(nil /* inKey */, list))
+	}
+	return spans, true
+}
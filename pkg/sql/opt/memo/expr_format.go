@@ -1231,7 +1231,16 @@ func (f *ExprFmtCtx) formatScalarPrivate(scalar opt.ScalarExpr) {
 
 	case *UniqueChecksItem:
 		tab := f.Memo.metadata.TableMeta(t.Table)
-		constraint := tab.Table.Unique(t.CheckOrdinal)
+		var constraint interface {
+			ColumnCount() int
+			ColumnOrdinal(tab cat.Table, i int) int
+		}
+		if t.Exclusion {
+			constraint = tab.Table.ExclusionConstraint(t.CheckOrdinal)
+			f.Buffer.WriteString(" (exclusion)")
+		} else {
+			constraint = tab.Table.Unique(t.CheckOrdinal)
+		}
 		fmt.Fprintf(f.Buffer, ": %s(", tab.Alias.ObjectName)
 		for i := 0; i < constraint.ColumnCount(); i++ {
 			if i > 0 {
@@ -171,6 +171,8 @@ type Memo struct {
 	useImprovedJoinElimination                 bool
 	implicitFKLockingForSerializable           bool
 	durableLockingForSerializable              bool
+	allowMaterializedViewMutations             bool
+	vectorSearchProbes                         int64
 
 	// txnIsoLevel is the isolation level under which the plan was created. This
 	// affects the planning of some locking operations, so it must be included in
@@ -240,6 +242,8 @@ func (m *Memo) Init(ctx context.Context, evalCtx *eval.Context) {
 		useImprovedJoinElimination:                 evalCtx.SessionData().OptimizerUseImprovedJoinElimination,
 		implicitFKLockingForSerializable:           evalCtx.SessionData().ImplicitFKLockingForSerializable,
 		durableLockingForSerializable:              evalCtx.SessionData().DurableLockingForSerializable,
+		allowMaterializedViewMutations:             evalCtx.SessionData().AllowMaterializedViewMutations,
+		vectorSearchProbes:                         evalCtx.SessionData().VectorSearchProbes,
 		txnIsoLevel:                                evalCtx.TxnIsoLevel,
 	}
 	m.metadata.Init()
@@ -383,6 +387,8 @@ func (m *Memo) IsStale(
 		m.useImprovedJoinElimination != evalCtx.SessionData().OptimizerUseImprovedJoinElimination ||
 		m.implicitFKLockingForSerializable != evalCtx.SessionData().ImplicitFKLockingForSerializable ||
 		m.durableLockingForSerializable != evalCtx.SessionData().DurableLockingForSerializable ||
+		m.allowMaterializedViewMutations != evalCtx.SessionData().AllowMaterializedViewMutations ||
+		m.vectorSearchProbes != evalCtx.SessionData().VectorSearchProbes ||
 		m.txnIsoLevel != evalCtx.TxnIsoLevel {
 		return true, nil
 	}
@@ -400,6 +400,18 @@ func TestMemoIsStale(t *testing.T) {
 	evalCtx.SessionData().DurableLockingForSerializable = false
 	notStale()
 
+	// Stale allow materialized view mutations.
+	evalCtx.SessionData().AllowMaterializedViewMutations = true
+	stale()
+	evalCtx.SessionData().AllowMaterializedViewMutations = false
+	notStale()
+
+	// Stale vector search probes.
+	evalCtx.SessionData().VectorSearchProbes = 10
+	stale()
+	evalCtx.SessionData().VectorSearchProbes = 0
+	notStale()
+
 	// Stale txn isolation level.
 	evalCtx.TxnIsoLevel = isolation.ReadCommitted
 	stale()
@@ -146,28 +146,33 @@ var ComparisonOpReverseMap = map[Operator]treecmp.ComparisonOperatorSymbol{
 	BBoxCoversOp:     treecmp.RegMatch,
 	BBoxIntersectsOp: treecmp.Overlaps,
 	TSMatchesOp:      treecmp.TSMatches,
+	JsonPathMatchOp:  treecmp.TSMatches,
+	JsonPathExistsOp: treecmp.JSONPathExists,
 }
 
 // BinaryOpReverseMap maps from an optimizer operator type to a semantic tree
 // binary operator type.
 var BinaryOpReverseMap = map[Operator]treebin.BinaryOperatorSymbol{
-	BitandOp:        treebin.Bitand,
-	BitorOp:         treebin.Bitor,
-	BitxorOp:        treebin.Bitxor,
-	PlusOp:          treebin.Plus,
-	MinusOp:         treebin.Minus,
-	MultOp:          treebin.Mult,
-	DivOp:           treebin.Div,
-	FloorDivOp:      treebin.FloorDiv,
-	ModOp:           treebin.Mod,
-	PowOp:           treebin.Pow,
-	ConcatOp:        treebin.Concat,
-	LShiftOp:        treebin.LShift,
-	RShiftOp:        treebin.RShift,
-	FetchValOp:      treebin.JSONFetchVal,
-	FetchTextOp:     treebin.JSONFetchText,
-	FetchValPathOp:  treebin.JSONFetchValPath,
-	FetchTextPathOp: treebin.JSONFetchTextPath,
+	BitandOp:                treebin.Bitand,
+	BitorOp:                 treebin.Bitor,
+	BitxorOp:                treebin.Bitxor,
+	PlusOp:                  treebin.Plus,
+	MinusOp:                 treebin.Minus,
+	MultOp:                  treebin.Mult,
+	DivOp:                   treebin.Div,
+	FloorDivOp:              treebin.FloorDiv,
+	ModOp:                   treebin.Mod,
+	PowOp:                   treebin.Pow,
+	ConcatOp:                treebin.Concat,
+	LShiftOp:                treebin.LShift,
+	RShiftOp:                treebin.RShift,
+	FetchValOp:              treebin.JSONFetchVal,
+	FetchTextOp:             treebin.JSONFetchText,
+	FetchValPathOp:          treebin.JSONFetchValPath,
+	FetchTextPathOp:         treebin.JSONFetchTextPath,
+	VectorDistanceOp:        treebin.VectorDistance,
+	VectorCosDistanceOp:     treebin.VectorCosDistance,
+	VectorNegInnerProductOp: treebin.VectorNegInnerProduct,
 }
 
 // UnaryOpReverseMap maps from an optimizer operator type to a semantic tree
@@ -229,6 +234,7 @@ var AggregateOpReverseMap = map[Operator]string{
 	STUnionOp:             "st_union",
 	STCollectOp:           "st_collect",
 	STExtentOp:            "st_extent",
+	STAsMVTOp:             "st_asmvt",
 }
 
 // WindowOpReverseMap maps from an optimizer operator type to the name of a
@@ -327,7 +333,7 @@ func AggregateIgnoresNulls(op Operator) bool {
 		PercentileContOp, STMakeLineOp, STCollectOp, STExtentOp, STUnionOp, StdDevPopOp,
 		VarPopOp, CovarPopOp, CovarSampOp, RegressionAvgXOp, RegressionAvgYOp,
 		RegressionInterceptOp, RegressionR2Op, RegressionSlopeOp, RegressionSXXOp,
-		RegressionSXYOp, RegressionSYYOp, RegressionCountOp:
+		RegressionSXYOp, RegressionSYYOp, RegressionCountOp, STAsMVTOp:
 		return true
 
 	case ArrayAggOp, ArrayCatAggOp, ConcatAggOp, ConstAggOp, CountRowsOp,
@@ -356,7 +362,7 @@ func AggregateIsNullOnEmpty(op Operator) bool {
 		RegressionSXYOp, RegressionSYYOp:
 		return true
 
-	case CountOp, CountRowsOp, RegressionCountOp:
+	case CountOp, CountRowsOp, RegressionCountOp, STAsMVTOp:
 		return false
 
 	default:
@@ -381,7 +387,7 @@ func AggregateIsNeverNullOnNonNullInput(op Operator) bool {
 		StringAggOp, SumOp, SumIntOp, XorAggOp, PercentileDiscOp, PercentileContOp,
 		JsonObjectAggOp, JsonbObjectAggOp, StdDevPopOp, STCollectOp, STUnionOp,
 		VarPopOp, CovarPopOp, RegressionAvgXOp, RegressionAvgYOp, RegressionSXXOp,
-		RegressionSXYOp, RegressionSYYOp, RegressionCountOp:
+		RegressionSXYOp, RegressionSYYOp, RegressionCountOp, STAsMVTOp:
 		return true
 
 	case VarianceOp, StdDevOp, CorrOp, CovarSampOp, RegressionInterceptOp,
@@ -398,7 +404,7 @@ func AggregateIsNeverNullOnNonNullInput(op Operator) bool {
 // returns NULL, even if the input is empty, or one more more inputs are NULL.
 func AggregateIsNeverNull(op Operator) bool {
 	switch op {
-	case CountOp, CountRowsOp, RegressionCountOp:
+	case CountOp, CountRowsOp, RegressionCountOp, STAsMVTOp:
 		return true
 	}
 	return false
@@ -436,7 +442,7 @@ func AggregatesCanMerge(inner, outer Operator) bool {
 		SqrDiffOp, STCollectOp, StdDevOp, StringAggOp, VarianceOp, StdDevPopOp,
 		VarPopOp, CovarPopOp, CovarSampOp, RegressionAvgXOp, RegressionAvgYOp,
 		RegressionInterceptOp, RegressionR2Op, RegressionSlopeOp, RegressionSXXOp,
-		RegressionSXYOp, RegressionSYYOp, RegressionCountOp:
+		RegressionSXYOp, RegressionSYYOp, RegressionCountOp, STAsMVTOp:
 		return false
 
 	default:
@@ -458,7 +464,7 @@ func AggregateIgnoresDuplicates(op Operator) bool {
 		VarPopOp, JsonObjectAggOp, JsonbObjectAggOp, STCollectOp, CovarPopOp,
 		CovarSampOp, RegressionAvgXOp, RegressionAvgYOp, RegressionInterceptOp,
 		RegressionR2Op, RegressionSlopeOp, RegressionSXXOp, RegressionSXYOp,
-		RegressionSYYOp, RegressionCountOp:
+		RegressionSYYOp, RegressionCountOp, STAsMVTOp:
 		return false
 
 	default:
@@ -253,7 +253,7 @@ func (a aggregateInfo) isOrderingSensitive() bool {
 	switch a.def.Name {
 	case "array_agg", "array_cat_agg", "concat_agg", "string_agg", "json_agg",
 		"jsonb_agg", "json_object_agg", "jsonb_object_agg", "st_makeline",
-		"st_collect", "st_memcollect":
+		"st_collect", "st_memcollect", "st_asmvt":
 		return true
 	default:
 		return false
@@ -677,6 +677,29 @@ func translateAggName(name string) string {
 	return name
 }
 
+// getTypedAggArgs returns the arguments to the aggregate function as a
+// []tree.TypedExpr. In the case of arguments with default values, it fills in
+// the values if they are missing, so that the aggregate operator always has
+// the same number of arguments. See also getTypedWindowArgs.
+func getTypedAggArgs(name string, exprs []tree.Expr) []tree.TypedExpr {
+	argExprs := getTypedExprs(exprs)
+
+	switch name {
+	// The layer name of st_asmvt is "default" by default, the extent is 4096,
+	// and the geometry and feature ID column names are NULL.
+	case "st_asmvt":
+		defaults := []tree.TypedExpr{
+			tree.NewDString("default"),
+			tree.NewDInt(4096),
+			reType(tree.DNull, types.String),
+			reType(tree.DNull, types.String),
+		}
+		argExprs = append(argExprs, defaults[len(argExprs)-1:]...)
+	}
+
+	return argExprs
+}
+
 // buildAggregateFunction is called when we are building a function which is an
 // aggregate. Any non-trivial parameters (i.e. not column reference) to the
 // aggregate function are extracted and added to aggInScope. The aggregate
@@ -702,7 +725,6 @@ func (b *Builder) buildAggregateFunction(
 		FuncExpr: f,
 		def:      *def,
 		distinct: (f.Type == tree.DistinctFuncType),
-		args:     make(memo.ScalarListExpr, len(f.Exprs)),
 	}
 
 	// Temporarily set b.subquery to nil so we don't add outer columns to the
@@ -711,8 +733,10 @@ func (b *Builder) buildAggregateFunction(
 	b.subquery = nil
 	defer func() { b.subquery = subq }()
 
-	for i, pexpr := range f.Exprs {
-		info.args[i] = b.buildAggArg(pexpr.(tree.TypedExpr), &info, tempScope, fromScope)
+	argExprs := getTypedAggArgs(def.Name, f.Exprs)
+	info.args = make(memo.ScalarListExpr, len(argExprs))
+	for i, pexpr := range argExprs {
+		info.args[i] = b.buildAggArg(pexpr, &info, tempScope, fromScope)
 	}
 
 	// If we have a filter, add it to tempScope after all the arguments. We'll
@@ -875,6 +899,8 @@ func (b *Builder) constructAggregate(name string, args []opt.ScalarExpr) opt.Sca
 		return b.factory.ConstructSTExtent(args[0])
 	case "st_union", "st_memunion":
 		return b.factory.ConstructSTUnion(args[0])
+	case "st_asmvt":
+		return b.factory.ConstructSTAsMVT(args[0], args[1], args[2], args[3], args[4])
 	case "xor_agg":
 		return b.factory.ConstructXorAgg(args[0])
 	case "json_agg":
@@ -0,0 +1,154 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// buildInheritanceScan extends outScope, which scans the given table, with
+// the rows of all of the tables which inherit from it, directly or
+// indirectly. The rows of each child table are combined with the rows of the
+// parent using UNION ALL, and the columns of the parent are matched to the
+// columns of the child by name. Columns which the child does not have, such
+// as the implicit rowid column of the parent, are NULL for the rows of the
+// child.
+//
+// Scans are not expanded while building a view or function definition, since
+// their bodies are built again when they are used. Numeric table references
+// are not expanded either, which allows internal queries to read the rows of
+// the parent alone.
+func (b *Builder) buildInheritanceScan(
+	tab cat.Table, locking lockingSpec, inScope, outScope *scope,
+) *scope {
+	if tab.InheritanceChildCount() == 0 || b.insideViewDef || b.insideFuncDef {
+		return outScope
+	}
+	for i, n := 0, tab.InheritanceChildCount(); i < n; i++ {
+		ds, _, err := b.catalog.ResolveDataSourceByID(b.ctx, cat.Flags{}, tab.InheritanceChild(i))
+		if err != nil {
+			panic(err)
+		}
+		child, ok := ds.(cat.Table)
+		if !ok {
+			panic(errors.AssertionFailedf("inheritance child %q is not a table", ds.Name()))
+		}
+		// Access to the rows of a child through its parent is governed by the
+		// privileges on the parent, as in Postgres. The child is still added as
+		// a dependency so that a cached plan is invalidated if it changes.
+		b.factory.Metadata().AddDependency(opt.DepByID(child.ID()), child, 0 /* priv */)
+
+		childScope := b.buildScan(
+			b.addTable(child, tree.NewUnqualifiedTableName(child.Name())),
+			tableOrdinals(child, columnKinds{
+				includeMutations: false,
+				includeSystem:    true,
+				includeInverted:  false,
+			}),
+			nil, /* indexFlags */
+			locking,
+			inScope,
+			false, /* disableNotVisibleIndex */
+		)
+		childScope = b.buildInheritanceScan(child, locking, inScope, childScope)
+		outScope = b.buildInheritanceUnion(inScope, outScope, childScope)
+	}
+	return outScope
+}
+
+// buildInheritanceUnion combines the rows of parentScope with the rows of
+// childScope, matching their columns by name. The returned scope has the same
+// columns as parentScope.
+func (b *Builder) buildInheritanceUnion(inScope, parentScope, childScope *scope) *scope {
+	md := b.factory.Metadata()
+	childCols := make(opt.ColList, len(parentScope.cols))
+	var passthrough opt.ColSet
+	var projections memo.ProjectionsExpr
+	for i := range parentScope.cols {
+		col := &parentScope.cols[i]
+		if childCol := findInheritedColumn(childScope, col); childCol != nil {
+			childCols[i] = childCol.id
+			passthrough.Add(childCol.id)
+			continue
+		}
+		id := md.AddColumn(col.name.MetadataName(), col.typ)
+		projections = append(projections, b.factory.ConstructProjectionsItem(
+			b.factory.ConstructNull(col.typ), id,
+		))
+		childCols[i] = id
+	}
+	child := childScope.expr
+	if len(projections) > 0 {
+		child = b.factory.ConstructProject(child, projections, passthrough)
+	}
+
+	outScope := inScope.push()
+	outScope.cols = make([]scopeColumn, 0, len(parentScope.cols))
+	for i := range parentScope.cols {
+		col := &parentScope.cols[i]
+		newCol := b.synthesizeColumn(outScope, col.name, col.typ, nil, nil /* scalar */)
+		newCol.table = col.table
+		newCol.visibility = col.visibility
+		newCol.kind = col.kind
+		newCol.tableOrdinal = col.tableOrdinal
+	}
+	outScope.expr = b.factory.ConstructUnionAll(parentScope.expr, child, &memo.SetPrivate{
+		LeftCols:  colsToColList(parentScope.cols),
+		RightCols: childCols,
+		OutCols:   colsToColList(outScope.cols),
+	})
+	return outScope
+}
+
+// findInheritedColumn returns the column of childScope which corresponds to
+// the given column of its parent, or nil if there is none.
+func findInheritedColumn(childScope *scope, parentCol *scopeColumn) *scopeColumn {
+	for i := range childScope.cols {
+		col := &childScope.cols[i]
+		if col.name.MatchesReferenceName(parentCol.name.ReferenceName()) {
+			if !col.typ.Identical(parentCol.typ) {
+				return nil
+			}
+			return col
+		}
+	}
+	return nil
+}
+
+// checkInheritanceForMutation raises an error if the given table cannot be
+// the target of a mutation which requires the given privilege because of its
+// inheritance relationships. Rows inserted into a partitioned table are not
+// routed to its partitions, and updates and deletes of a table do not reach the
+// rows of the tables which inherit from it, so these mutations are rejected
+// rather than silently applied to the parent alone.
+func checkInheritanceForMutation(tab cat.Table, priv privilege.Kind) {
+	switch priv {
+	case privilege.INSERT:
+		if tab.IsPartitioned() {
+			panic(errors.WithHint(
+				unimplemented.NewWithIssuef(22456,
+					"INSERT into partitioned table %q is not supported", tab.Name()),
+				"Insert into one of its partitions instead.",
+			))
+		}
+	case privilege.UPDATE, privilege.DELETE:
+		if tab.InheritanceChildCount() > 0 {
+			panic(unimplemented.NewWithIssuef(22456,
+				"%s of table %q with inheritance children is not supported", priv, tab.Name()))
+		}
+	}
+}
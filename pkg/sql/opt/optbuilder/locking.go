@@ -65,6 +65,20 @@ type lockingSpec []*tree.LockingItem
 // noRowLocking indicates that no row-level locking has been specified.
 var noRowLocking lockingSpec
 
+// mutationLocking returns the row-level locking spec used by the initial row
+// scan of an UPDATE or DELETE statement with the given wait policy. Without a
+// wait policy no locking is specified here, and the execbuilder decides whether
+// to lock the scanned rows (see shouldApplyImplicitLockingToMutationInput).
+// With SKIP LOCKED or NOWAIT the scan must lock the rows it returns, so that
+// rows locked by other transactions are skipped or cause an error before they
+// are mutated.
+func mutationLocking(waitPolicy tree.LockingWaitPolicy) lockingSpec {
+	if waitPolicy == tree.LockWaitBlock {
+		return noRowLocking
+	}
+	return lockingSpec{{Strength: tree.ForUpdate, WaitPolicy: waitPolicy}}
+}
+
 // isSet returns whether the spec contains any row-level locking modes.
 func (lm lockingSpec) isSet() bool {
 	return len(lm) != 0
@@ -0,0 +1,468 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+)
+
+const (
+	// mergeSourceName is the name of the CTE that holds the source rows of a
+	// MERGE statement joined with the matching target rows.
+	mergeSourceName = "crdb_internal_merge"
+
+	// mergeActionColName is the name of the column in the mergeSourceName CTE
+	// that holds the 1-based ordinal of the WHEN clause that applies to each
+	// row, or NULL if no clause applies.
+	mergeActionColName = "crdb_internal_merge_action"
+
+	// mergeTargetColPrefix and mergeKeyColPrefix are the prefixes of the names
+	// of the columns in the mergeSourceName CTE that hold the primary key of the
+	// matched target row. The key columns are only non-NULL if the row is
+	// updated or deleted.
+	mergeTargetColPrefix = "crdb_internal_merge_target_"
+	mergeKeyColPrefix    = "crdb_internal_merge_key_"
+)
+
+// buildMerge builds a memo group for a MERGE statement. MERGE is built as a
+// set of DELETE, UPDATE and INSERT mutations that read from a single,
+// materialized join of the source and target tables. Given a table
+// t(k PRIMARY KEY, v), this statement:
+//
+//	MERGE INTO t USING s ON t.k = s.k
+//	WHEN MATCHED AND s.v IS NULL THEN DELETE
+//	WHEN MATCHED THEN UPDATE SET v = s.v
+//	WHEN NOT MATCHED THEN INSERT VALUES (s.k, s.v)
+//
+// is built roughly like this:
+//
+//	WITH crdb_internal_merge AS MATERIALIZED (
+//	  SELECT DISTINCT ON (crdb_internal_merge_key_1) <error on duplicates> *
+//	  FROM (
+//	    SELECT *, CASE WHEN crdb_internal_merge_action IN (1, 2)
+//	              THEN crdb_internal_merge_target_1 END AS crdb_internal_merge_key_1
+//	    FROM (
+//	      SELECT s.*, t.k AS crdb_internal_merge_target_1,
+//	        CASE
+//	          WHEN t.k IS NOT NULL AND s.v IS NULL THEN 1
+//	          WHEN t.k IS NOT NULL THEN 2
+//	          WHEN t.k IS NULL THEN 3
+//	        END AS crdb_internal_merge_action
+//	      FROM s LEFT JOIN t ON t.k = s.k
+//	    ) AS s
+//	  )
+//	),
+//	crdb_internal_merge_delete AS (
+//	  DELETE FROM t USING crdb_internal_merge AS s
+//	  WHERE t.k = s.crdb_internal_merge_key_1 AND s.crdb_internal_merge_action IN (1)
+//	  RETURNING true
+//	),
+//	crdb_internal_merge_update_2 AS (
+//	  UPDATE t SET v = s.v FROM crdb_internal_merge AS s
+//	  WHERE t.k = s.crdb_internal_merge_key_1 AND s.crdb_internal_merge_action = 2
+//	  RETURNING true
+//	),
+//	crdb_internal_merge_insert_3 AS (
+//	  INSERT INTO t SELECT s.k, s.v FROM crdb_internal_merge AS s
+//	  WHERE s.crdb_internal_merge_action = 3
+//	  RETURNING true
+//	)
+//	SELECT count(*) FROM (
+//	  SELECT * FROM crdb_internal_merge_delete
+//	  UNION ALL SELECT * FROM crdb_internal_merge_update_2
+//	  UNION ALL SELECT * FROM crdb_internal_merge_insert_3
+//	)
+//
+// If there is a RETURNING clause, each mutation returns the RETURNING
+// expressions instead, and the results are combined with UNION ALL.
+//
+// The distinct-on operator raises an error if a target row would be updated or
+// deleted more than once, so each target row is modified by at most one of the
+// mutations. This makes it safe for the mutations to modify the same table in
+// the same statement, so each mutation is built as a separate statement in the
+// statement tree. See checkMultipleMutations.
+func (b *Builder) buildMerge(mg *tree.Merge, inScope *scope) (outScope *scope) {
+	tab, _, alias, _ := b.resolveTableForMutation(mg.Table, privilege.SELECT)
+	targetName := alias.ToUnresolvedObjectName()
+	sourceName := mergeSourceAlias(mg.Source)
+
+	primaryIndex := tab.Index(cat.PrimaryIndex)
+	numKeyCols := primaryIndex.KeyColumnCount()
+	targetCol := func(i int) tree.Expr {
+		return &tree.ColumnItem{TableName: targetName, ColumnName: primaryIndex.Column(i).ColName()}
+	}
+	sourceCol := func(name string) tree.Expr {
+		return &tree.ColumnItem{
+			TableName:  tree.NewUnqualifiedTableName(sourceName).ToUnresolvedObjectName(),
+			ColumnName: tree.Name(name),
+		}
+	}
+	actionIn := func(actions tree.Exprs) tree.Expr {
+		if len(actions) == 1 {
+			return &tree.ComparisonExpr{
+				Operator: treecmp.MakeComparisonOperator(treecmp.EQ),
+				Left:     sourceCol(mergeActionColName),
+				Right:    actions[0],
+			}
+		}
+		return &tree.ComparisonExpr{
+			Operator: treecmp.MakeComparisonOperator(treecmp.In),
+			Left:     sourceCol(mergeActionColName),
+			Right:    &tree.Tuple{Exprs: actions},
+		}
+	}
+
+	// Determine the action for each row of the join. The WHEN clauses are
+	// evaluated in order, and the first one that applies is used. A row is
+	// matched if the first target primary key column is not NULL.
+	var whens []*tree.When
+	var deleteActions, modifyActions tree.Exprs
+	for i, w := range mg.Whens {
+		action := tree.NewDInt(tree.DInt(i + 1))
+		var cond tree.Expr
+		if w.Matched {
+			cond = &tree.IsNotNullExpr{Expr: targetCol(0)}
+		} else {
+			cond = &tree.IsNullExpr{Expr: targetCol(0)}
+		}
+		if w.Cond != nil {
+			cond = &tree.AndExpr{Left: cond, Right: &tree.ParenExpr{Expr: w.Cond}}
+		}
+		whens = append(whens, &tree.When{Cond: cond, Val: action})
+		switch w.Action {
+		case tree.MergeActionDelete:
+			deleteActions = append(deleteActions, action)
+			modifyActions = append(modifyActions, action)
+		case tree.MergeActionUpdate:
+			modifyActions = append(modifyActions, action)
+		}
+	}
+
+	// Build the join of the source and target tables.
+	innerExprs := tree.SelectExprs{{
+		Expr: &tree.AllColumnsSelector{
+			TableName: tree.NewUnqualifiedTableName(sourceName).ToUnresolvedObjectName(),
+		},
+	}}
+	for i := 0; i < numKeyCols; i++ {
+		innerExprs = append(innerExprs, tree.SelectExpr{
+			Expr: targetCol(i),
+			As:   tree.UnrestrictedName(fmt.Sprintf("%s%d", mergeTargetColPrefix, i+1)),
+		})
+	}
+	innerExprs = append(innerExprs, tree.SelectExpr{
+		Expr: &tree.CaseExpr{Whens: whens},
+		As:   mergeActionColName,
+	})
+	var source tree.SelectStatement = &tree.SelectClause{
+		Exprs: innerExprs,
+		From: tree.From{Tables: tree.TableExprs{&tree.JoinTableExpr{
+			JoinType: tree.AstLeft,
+			Left:     mg.Source,
+			Right:    mg.Table,
+			Cond:     &tree.OnJoinCond{Expr: mg.On},
+		}}},
+	}
+
+	// If any rows can be updated or deleted, project the key columns used to
+	// identify them.
+	numInternalCols := numKeyCols + 1
+	if len(modifyActions) > 0 {
+		outerExprs := tree.SelectExprs{{Expr: tree.UnqualifiedStar{}}}
+		for i := 0; i < numKeyCols; i++ {
+			outerExprs = append(outerExprs, tree.SelectExpr{
+				Expr: &tree.CaseExpr{Whens: []*tree.When{{
+					Cond: actionIn(modifyActions),
+					Val:  sourceCol(fmt.Sprintf("%s%d", mergeTargetColPrefix, i+1)),
+				}}},
+				As: tree.UnrestrictedName(fmt.Sprintf("%s%d", mergeKeyColPrefix, i+1)),
+			})
+		}
+		source = &tree.SelectClause{
+			Exprs: outerExprs,
+			From: tree.From{Tables: tree.TableExprs{&tree.AliasedTableExpr{
+				Expr: &tree.Subquery{Select: &tree.ParenSelect{Select: &tree.Select{Select: source}}},
+				As:   tree.AliasClause{Alias: sourceName},
+			}}},
+		}
+		numInternalCols += numKeyCols
+	}
+	sourceStmt := &tree.Select{Select: source}
+	sourceScope := b.buildStmt(sourceStmt, nil /* desiredTypes */, inScope)
+
+	// The internal columns are the last columns of the source. They are hidden
+	// so that they are not included in stars that refer to the source.
+	var internalCols, keyCols opt.ColSet
+	for i := len(sourceScope.cols) - numInternalCols; i < len(sourceScope.cols); i++ {
+		internalCols.Add(sourceScope.cols[i].id)
+	}
+	if len(modifyActions) > 0 {
+		for i := len(sourceScope.cols) - numKeyCols; i < len(sourceScope.cols); i++ {
+			keyCols.Add(sourceScope.cols[i].id)
+		}
+		sourceScope = b.buildDistinctOn(
+			keyCols, sourceScope, true /* nullsAreDistinct */, "MERGE command cannot affect row a second time",
+		)
+	}
+
+	id := b.factory.Memo().NextWithID()
+	b.factory.Metadata().AddWithBinding(id, sourceScope.expr)
+	cte := &cteSource{
+		name:         tree.AliasClause{Alias: mergeSourceName},
+		cols:         sourceScope.makePresentation(),
+		originalExpr: sourceStmt,
+		expr:         sourceScope.expr,
+		id:           id,
+		mtr:          tree.CTEMaterializeAlways,
+		hiddenCols:   internalCols,
+	}
+	b.addCTE(cte)
+	mergeScope := inScope.push()
+	mergeScope.ctes = map[string]*cteSource{mergeSourceName: cte}
+
+	// Build the RETURNING clause of each mutation. If the MERGE has no RETURNING
+	// clause, the mutations return a constant so that the modified rows can be
+	// counted.
+	makeReturning := func() *tree.ReturningExprs {
+		if !resultsNeeded(mg.Returning) {
+			return &tree.ReturningExprs{{Expr: tree.DBoolTrue}}
+		}
+		returning := append(tree.ReturningExprs(nil), *mg.Returning.(*tree.ReturningExprs)...)
+		for i := range returning {
+			// Expand * to the columns of the target table, since the source is not
+			// visible to INSERT actions.
+			if _, ok := returning[i].Expr.(tree.UnqualifiedStar); ok {
+				returning[i].Expr = &tree.AllColumnsSelector{TableName: targetName}
+			}
+		}
+		return &returning
+	}
+
+	mergeSource := &tree.AliasedTableExpr{
+		Expr: tree.NewUnqualifiedTableName(mergeSourceName),
+		As:   tree.AliasClause{Alias: sourceName},
+	}
+	var keyMatch tree.Expr
+	for i := 0; i < numKeyCols; i++ {
+		eq := &tree.ComparisonExpr{
+			Operator: treecmp.MakeComparisonOperator(treecmp.EQ),
+			Left:     targetCol(i),
+			Right:    sourceCol(fmt.Sprintf("%s%d", mergeKeyColPrefix, i+1)),
+		}
+		if keyMatch == nil {
+			keyMatch = eq
+		} else {
+			keyMatch = &tree.AndExpr{Left: keyMatch, Right: eq}
+		}
+	}
+
+	var actionNames []string
+	buildAction := func(name string, stmt tree.Statement) {
+		actionNames = append(actionNames, name)
+		b.buildMergeAction(name, stmt, mergeScope)
+	}
+
+	// Rows are deleted first, so that updates and inserts do not conflict with
+	// them.
+	if len(deleteActions) > 0 {
+		buildAction(mergeSourceName+"_delete", &tree.Delete{
+			Table: mg.Table,
+			Using: tree.TableExprs{mergeSource},
+			Where: tree.NewWhere(tree.AstWhere, &tree.AndExpr{
+				Left: keyMatch, Right: actionIn(deleteActions),
+			}),
+			Returning: makeReturning(),
+		})
+	}
+	for i, w := range mg.Whens {
+		if w.Action != tree.MergeActionUpdate {
+			continue
+		}
+		action := tree.Exprs{tree.NewDInt(tree.DInt(i + 1))}
+		buildAction(fmt.Sprintf("%s_update_%d", mergeSourceName, i+1), &tree.Update{
+			Table: mg.Table,
+			Exprs: w.Exprs,
+			From:  tree.TableExprs{mergeSource},
+			Where: tree.NewWhere(tree.AstWhere, &tree.AndExpr{
+				Left: keyMatch, Right: actionIn(action),
+			}),
+			Returning: makeReturning(),
+		})
+	}
+	for i, w := range mg.Whens {
+		if w.Action != tree.MergeActionInsert {
+			continue
+		}
+		action := tree.Exprs{tree.NewDInt(tree.DInt(i + 1))}
+		cols, vals := mergeInsertColsAndValues(tab, w)
+		insertTable := mg.Table
+		if ate, ok := insertTable.(*tree.AliasedTableExpr); ok {
+			// Index hints are not allowed for INSERT.
+			insertTable = &tree.AliasedTableExpr{Expr: ate.Expr, As: ate.As}
+		}
+		buildAction(fmt.Sprintf("%s_insert_%d", mergeSourceName, i+1), &tree.Insert{
+			Table:   insertTable,
+			Columns: cols,
+			Rows: &tree.Select{Select: &tree.SelectClause{
+				Exprs: vals,
+				From:  tree.From{Tables: tree.TableExprs{mergeSource}},
+				Where: tree.NewWhere(tree.AstWhere, actionIn(action)),
+			}},
+			Returning: makeReturning(),
+		})
+	}
+
+	// Combine the results of the mutations.
+	var result tree.SelectStatement
+	for _, name := range actionNames {
+		sel := &tree.SelectClause{
+			Exprs: tree.SelectExprs{{Expr: tree.UnqualifiedStar{}}},
+			From:  tree.From{Tables: tree.TableExprs{tree.NewUnqualifiedTableName(tree.Name(name))}},
+		}
+		if result == nil {
+			result = sel
+		} else {
+			result = &tree.UnionClause{
+				Type:  tree.UnionOp,
+				Left:  &tree.Select{Select: result},
+				Right: &tree.Select{Select: sel},
+				All:   true,
+			}
+		}
+	}
+	if result == nil {
+		// All WHEN clauses are DO NOTHING, so no rows are modified.
+		result = &tree.SelectClause{
+			Exprs: tree.SelectExprs(*makeReturning()),
+			From:  tree.From{Tables: tree.TableExprs{mg.Table, mergeSource}},
+			Where: tree.NewWhere(tree.AstWhere, tree.DBoolFalse),
+		}
+	}
+	if !resultsNeeded(mg.Returning) {
+		result = &tree.SelectClause{
+			Exprs: tree.SelectExprs{{Expr: &tree.FuncExpr{
+				Func:  tree.WrapFunction("count"),
+				Exprs: tree.Exprs{tree.StarExpr()},
+			}}},
+			From: tree.From{Tables: tree.TableExprs{&tree.AliasedTableExpr{
+				Expr: &tree.Subquery{Select: &tree.ParenSelect{Select: &tree.Select{Select: result}}},
+				As:   tree.AliasClause{Alias: mergeSourceName + "_result"},
+			}}},
+		}
+	}
+	return b.buildStmt(&tree.Select{Select: result}, nil /* desiredTypes */, mergeScope)
+}
+
+// buildMergeAction builds one of the mutations of a MERGE statement and adds
+// it as a CTE with the given name to mergeScope.
+func (b *Builder) buildMergeAction(name string, stmt tree.Statement, mergeScope *scope) {
+	// Each mutation is built as a separate statement, since they are allowed to
+	// modify the same table. See buildMerge.
+	b.stmtTree.Push()
+	actionScope := b.buildStmt(stmt, nil /* desiredTypes */, mergeScope)
+	b.stmtTree.Pop()
+
+	id := b.factory.Memo().NextWithID()
+	b.factory.Metadata().AddWithBinding(id, actionScope.expr)
+	cte := &cteSource{
+		name:         tree.AliasClause{Alias: tree.Name(name)},
+		cols:         actionScope.makePresentation(),
+		originalExpr: stmt,
+		expr:         actionScope.expr,
+		id:           id,
+	}
+	b.addCTE(cte)
+	mergeScope.ctes[name] = cte
+}
+
+// mergeSourceAlias returns the name by which the source of a MERGE statement
+// is referenced.
+func mergeSourceAlias(source tree.TableExpr) tree.Name {
+	switch t := source.(type) {
+	case *tree.AliasedTableExpr:
+		if t.As.Alias != "" {
+			return t.As.Alias
+		}
+		if tn, ok := t.Expr.(*tree.TableName); ok {
+			return tn.ObjectName
+		}
+		if _, ok := t.Expr.(*tree.Subquery); ok {
+			panic(pgerror.New(pgcode.Syntax, "subquery in MERGE USING clause must have an alias"))
+		}
+	case *tree.TableName:
+		return t.ObjectName
+	}
+	panic(pgerror.Newf(pgcode.FeatureNotSupported,
+		"MERGE source must be a table or a subquery: %s", tree.AsString(source),
+	))
+}
+
+// mergeInsertColsAndValues returns the target columns and input expressions
+// of the INSERT action of a MERGE WHEN clause. The values of an INSERT action
+// are built as a projection of the source, so columns set to DEFAULT are
+// removed from the column list instead, which causes their default values to
+// be inserted.
+func mergeInsertColsAndValues(
+	tab cat.Table, w *tree.MergeWhen,
+) (cols tree.NameList, vals tree.SelectExprs) {
+	hasDefault := false
+	for _, v := range w.Values {
+		if _, ok := v.(tree.DefaultVal); ok {
+			hasDefault = true
+			break
+		}
+	}
+	if !hasDefault {
+		for _, v := range w.Values {
+			vals = append(vals, tree.SelectExpr{Expr: v})
+		}
+		return w.Columns, vals
+	}
+
+	names := w.Columns
+	if len(names) == 0 {
+		// The values target the visible columns of the table in order, as in
+		// mutationBuilder.addTargetTableColsForInsert.
+		for i, n := 0, tab.ColumnCount(); i < n && len(names) < len(w.Values); i++ {
+			col := tab.Column(i)
+			if col.Kind() != cat.Ordinary || col.Visibility() != cat.Visible {
+				continue
+			}
+			names = append(names, col.ColName())
+		}
+	}
+	if len(names) != len(w.Values) {
+		more, less := "expressions", "target columns"
+		if len(w.Values) < len(names) {
+			more, less = less, more
+		}
+		panic(pgerror.Newf(pgcode.Syntax,
+			"INSERT has more %s than %s, %d expressions for %d targets",
+			more, less, len(w.Values), len(names)))
+	}
+	cols = make(tree.NameList, 0, len(names))
+	for i, v := range w.Values {
+		if _, ok := v.(tree.DefaultVal); ok {
+			continue
+		}
+		cols = append(cols, names[i])
+		vals = append(vals, tree.SelectExpr{Expr: v})
+	}
+	return cols, vals
+}
@@ -248,6 +248,7 @@ func (mb *mutationBuilder) setFetchColIDs(cols []scopeColumn) {
 //	WHERE <where>
 //	ORDER BY <order-by>
 //	LIMIT <limit>
+//	[FOR UPDATE SKIP LOCKED | FOR UPDATE NOWAIT]
 //
 // All columns from the table to update are added to fetchColList.
 // If a FROM clause is defined, we build out each of the table
@@ -271,6 +272,7 @@ func (mb *mutationBuilder) buildInputForUpdate(
 	where *tree.Where,
 	limit *tree.Limit,
 	orderBy tree.OrderBy,
+	waitPolicy tree.LockingWaitPolicy,
 ) {
 	var indexFlags *tree.IndexFlags
 	if source, ok := texpr.(*tree.AliasedTableExpr); ok && source.IndexFlags != nil {
@@ -294,7 +296,7 @@ func (mb *mutationBuilder) buildInputForUpdate(
 			includeInverted:  false,
 		}),
 		indexFlags,
-		noRowLocking,
+		mutationLocking(waitPolicy),
 		inScope,
 		false, /* disableNotVisibleIndex */
 	)
@@ -371,6 +373,7 @@ func (mb *mutationBuilder) buildInputForUpdate(
 //	WHERE <where>
 //	ORDER BY <order-by>
 //	LIMIT <limit>
+//	[FOR UPDATE SKIP LOCKED | FOR UPDATE NOWAIT]
 //
 // All columns from the table to update are added to fetchColList.
 // TODO(andyk): Do needed column analysis to project fewer columns if possible.
@@ -381,6 +384,7 @@ func (mb *mutationBuilder) buildInputForDelete(
 	using tree.TableExprs,
 	limit *tree.Limit,
 	orderBy tree.OrderBy,
+	waitPolicy tree.LockingWaitPolicy,
 ) {
 	var indexFlags *tree.IndexFlags
 	if source, ok := texpr.(*tree.AliasedTableExpr); ok && source.IndexFlags != nil {
@@ -405,7 +409,7 @@ func (mb *mutationBuilder) buildInputForDelete(
 			includeInverted:  false,
 		}),
 		indexFlags,
-		noRowLocking,
+		mutationLocking(waitPolicy),
 		inScope,
 		false, /* disableNotVisibleIndex */
 	)
@@ -0,0 +1,188 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+// buildExclusionChecks builds check queries for the exclusion constraints of
+// the table. If isUpdate is true, checks are only built for the constraints
+// which reference updated columns. The check queries are added to
+// mb.uniqueChecks, since they are executed in the same way as uniqueness
+// checks.
+func (mb *mutationBuilder) buildExclusionChecks(isUpdate bool) {
+	if mb.tab.ExclusionConstraintCount() == 0 {
+		return
+	}
+	if mb.b.evalCtx.TxnIsoLevel != isolation.Serializable {
+		panic(unimplemented.NewWithIssue(
+			100193, "exclusion constraints are not yet supported under weaker isolation levels",
+		))
+	}
+
+	mb.ensureWithID()
+	for i, n := 0, mb.tab.ExclusionConstraintCount(); i < n; i++ {
+		if isUpdate && !mb.exclusionColsUpdated(i) {
+			continue
+		}
+		mb.uniqueChecks = append(mb.uniqueChecks, mb.buildExclusionCheck(i))
+	}
+}
+
+// exclusionColsUpdated returns true if any of the columns of an exclusion
+// constraint are being updated (according to updateColIDs). When the
+// constraint has a partial predicate, it also returns true if the predicate
+// references any of the columns being updated.
+func (mb *mutationBuilder) exclusionColsUpdated(ord int) bool {
+	ec := mb.tab.ExclusionConstraint(ord)
+
+	for i, n := 0, ec.ColumnCount(); i < n; i++ {
+		if tabOrd := ec.ColumnOrdinal(mb.tab, i); mb.updateColIDs[tabOrd] != 0 {
+			return true
+		}
+	}
+
+	if _, isPartial := ec.Predicate(); isPartial {
+		pred := mb.parseExclusionConstraintPredicateExpr(ord)
+		typedPred := mb.fetchScope.resolveAndRequireType(pred, types.Bool)
+
+		var predCols opt.ColSet
+		mb.b.buildScalar(typedPred, mb.fetchScope, nil, nil, &predCols)
+		for colID, ok := predCols.Next(0); ok; colID, ok = predCols.Next(colID + 1) {
+			tabOrd := mb.md.ColumnMeta(colID).Table.ColumnOrdinal(colID)
+			if mb.updateColIDs[tabOrd] != 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parseExclusionConstraintPredicateExpr parses the predicate of the given
+// partial exclusion constraint.
+func (mb *mutationBuilder) parseExclusionConstraintPredicateExpr(ord int) tree.Expr {
+	predStr, _ := mb.tab.ExclusionConstraint(ord).Predicate()
+	expr, err := parser.ParseExpr(predStr)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
+
+// buildExclusionCheck creates a check for the rows which are added to or
+// updated in the table. The check is a semi join of the new rows with the
+// table, which returns the new rows for which there exists another row in
+// the table such that all of the constraint's comparisons are true:
+//
+//	SELECT new.a, new.b FROM new
+//	WHERE EXISTS (
+//	  SELECT * FROM tab
+//	  WHERE new.a = tab.a AND new.b && tab.b AND new.pk IS DISTINCT FROM tab.pk
+//	)
+//
+// Since the check is run after the mutation, the table includes all of the
+// new rows, so conflicts between two new rows are detected as well.
+func (mb *mutationBuilder) buildExclusionCheck(ord int) memo.UniqueChecksItem {
+	f := mb.b.factory
+	ec := mb.tab.ExclusionConstraint(ord)
+
+	scanScope, scanOrdinals := mb.buildCheckTableScan()
+	withScanScope, _ := mb.buildCheckInputScan(
+		checkInputScanNewVals, scanOrdinals, false, /* isFK */
+	)
+
+	// Build the join filters:
+	//   (new_a = existing_a) AND (new_b && existing_b) AND ...
+	_, isPartial := ec.Predicate()
+	semiJoinFilters := make(memo.FiltersExpr, 0, ec.ColumnCount()+3)
+	for i, n := 0, ec.ColumnCount(); i < n; i++ {
+		tabOrd := ec.ColumnOrdinal(mb.tab, i)
+		op := ec.Operator(i)
+		cmp := &tree.ComparisonExpr{Operator: treecmp.MakeComparisonOperator(op)}
+		// constructComparison uses the overload of some operators, such as &&,
+		// to determine which operator to build. The overload of != is that of =.
+		lookupOp := op
+		if lookupOp == treecmp.NE {
+			lookupOp = treecmp.EQ
+		}
+		colType := mb.tab.Column(tabOrd).DatumType()
+		if cmpOp, ok := tree.CmpOps[lookupOp].LookupImpl(colType, colType); ok {
+			cmp.Op = cmpOp
+		}
+		semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(
+			mb.b.constructComparison(
+				cmp,
+				f.ConstructVariable(withScanScope.cols[tabOrd].id),
+				f.ConstructVariable(scanScope.cols[tabOrd].id),
+			),
+		))
+	}
+
+	// If the constraint is partial, only the new and existing rows which
+	// satisfy the predicate are compared.
+	if isPartial {
+		pred := mb.parseExclusionConstraintPredicateExpr(ord)
+
+		typedPred := withScanScope.resolveAndRequireType(pred, types.Bool)
+		withScanPred := mb.b.buildScalar(typedPred, withScanScope, nil, nil, nil)
+		semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(withScanPred))
+
+		typedPred = scanScope.resolveAndRequireType(pred, types.Bool)
+		scanPred := mb.b.buildScalar(typedPred, scanScope, nil, nil, nil)
+		semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(scanPred))
+	}
+
+	// Prevent rows from matching themselves in the semi join:
+	//    (new_pk1 != existing_pk1) OR (new_pk2 != existing_pk2) OR ...
+	var pkFilter opt.ScalarExpr
+	primaryOrds := getIndexLaxKeyOrdinals(mb.tab.Index(cat.PrimaryIndex))
+	primaryOrds.ForEach(func(i int) {
+		pkFilterLocal := f.ConstructIsNot(
+			f.ConstructVariable(withScanScope.cols[i].id),
+			f.ConstructVariable(scanScope.cols[i].id),
+		)
+		if pkFilter == nil {
+			pkFilter = pkFilterLocal
+		} else {
+			pkFilter = f.ConstructOr(pkFilter, pkFilterLocal)
+		}
+	})
+	semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(pkFilter))
+
+	semiJoin := f.ConstructSemiJoin(
+		withScanScope.expr, scanScope.expr, semiJoinFilters, memo.EmptyJoinPrivate,
+	)
+
+	// Collect the key columns that will be shown in the error message if there
+	// is a violation of the constraint.
+	keyCols := make(opt.ColList, ec.ColumnCount())
+	for i := range keyCols {
+		keyCols[i] = withScanScope.cols[ec.ColumnOrdinal(mb.tab, i)].id
+	}
+	project := f.ConstructProject(semiJoin, nil /* projections */, keyCols.ToSet())
+
+	return f.ConstructUniqueChecksItem(project, &memo.UniqueChecksItemPrivate{
+		Table:        mb.tabID,
+		CheckOrdinal: ord,
+		KeyCols:      keyCols,
+		Exclusion:    true,
+	})
+}
@@ -270,7 +270,7 @@ func (h *uniqueCheckHelper) init(mb *mutationBuilder, uniqueOrdinal int) bool {
 	// Build the scan that will serve as the right side of the semi join in the
 	// uniqueness check. We need to build the scan now so that we can use its
 	// FDs below.
-	h.scanScope, h.scanOrdinals = h.buildTableScan()
+	h.scanScope, h.scanOrdinals = mb.buildCheckTableScan()
 
 	// Check that the columns in the unique constraint aren't already known to
 	// form a lax key. This can happen if there is a unique index on a superset of
@@ -406,10 +406,11 @@ func (h *uniqueCheckHelper) buildInsertionCheck() memo.UniqueChecksItem {
 	})
 }
 
-// buildTableScan builds a Scan of the table. The ordinals of the columns
-// scanned are also returned.
-func (h *uniqueCheckHelper) buildTableScan() (outScope *scope, ordinals []int) {
-	tabMeta := h.mb.b.addTable(h.mb.tab, tree.NewUnqualifiedTableName(h.mb.tab.Name()))
+// buildCheckTableScan builds a Scan of the table which serves as the right side
+// of the semi join in uniqueness and exclusion checks. The ordinals of the
+// columns scanned are also returned.
+func (mb *mutationBuilder) buildCheckTableScan() (outScope *scope, ordinals []int) {
+	tabMeta := mb.b.addTable(mb.tab, tree.NewUnqualifiedTableName(mb.tab.Name()))
 	ordinals = tableOrdinals(tabMeta.Table, columnKinds{
 		includeMutations: false,
 		includeSystem:    false,
@@ -418,29 +419,29 @@ func (h *uniqueCheckHelper) buildTableScan() (outScope *scope, ordinals []int) {
 	locking := noRowLocking
 	// If we're using a weaker isolation level, we lock the checked predicate(s)
 	// to prevent concurrent inserts from other transactions from violating the
-	// unique constraint.
-	if h.mb.b.evalCtx.TxnIsoLevel != isolation.Serializable {
+	// constraint.
+	if mb.b.evalCtx.TxnIsoLevel != isolation.Serializable {
 		locking = lockingSpec{
 			&tree.LockingItem{
 				// TODO(michae2): Change this to ForKeyShare when it is supported.
 				Strength:   tree.ForShare,
-				Targets:    []tree.TableName{tree.MakeUnqualifiedTableName(h.mb.tab.Name())},
+				Targets:    []tree.TableName{tree.MakeUnqualifiedTableName(mb.tab.Name())},
 				WaitPolicy: tree.LockWaitBlock,
-				// Unique checks must ensure the non-existence of certain rows, so we
+				// Checks must ensure the non-existence of certain rows, so we
 				// use predicate locks instead of record locks to prevent insertion of
 				// new rows into the locked span(s) by other concurrent transactions.
 				Form: tree.LockPredicate,
 			},
 		}
 	}
-	return h.mb.b.buildScan(
+	return mb.b.buildScan(
 		tabMeta,
 		ordinals,
 		// After the update we can't guarantee that the constraints are unique
 		// (which is why we need the uniqueness checks in the first place).
 		&tree.IndexFlags{IgnoreUniqueWithoutIndexKeys: true},
 		locking,
-		h.mb.b.allocScope(),
+		mb.b.allocScope(),
 		true, /* disableNotVisibleIndex */
 	), ordinals
 }
@@ -302,5 +302,8 @@ func ensureColumnOrderable(e tree.TypedExpr) {
 	switch typ.Family() {
 	case types.TSQueryFamily, types.TSVectorFamily:
 		panic(unimplementedWithIssueDetailf(92165, "", "can't order by column type %s", typ.SQLString()))
+	case types.JsonpathFamily:
+		panic(pgerror.Newf(pgcode.UndefinedFunction,
+			"could not identify an ordering operator for type %s", typ.SQLString()))
 	}
 }
@@ -880,7 +880,14 @@ func (b *Builder) constructComparison(
 		}
 		return b.factory.ConstructOverlaps(left, right)
 	case treecmp.TSMatches:
+		if cmp.Op.LeftType.Family() == types.JsonFamily {
+			// The @@ operator means "jsonpath predicate check" when used with
+			// jsonb and jsonpath operands.
+			return b.factory.ConstructJsonPathMatch(left, right)
+		}
 		return b.factory.ConstructTSMatches(left, right)
+	case treecmp.JSONPathExists:
+		return b.factory.ConstructJsonPathExists(left, right)
 	}
 	panic(errors.AssertionFailedf("unhandled comparison operator: %s", redact.Safe(cmp.Operator)))
 }
@@ -923,6 +930,12 @@ func (b *Builder) constructBinary(
 		return b.factory.ConstructFetchValPath(left, right)
 	case treebin.JSONFetchTextPath:
 		return b.factory.ConstructFetchTextPath(left, right)
+	case treebin.VectorDistance:
+		return b.factory.ConstructVectorDistance(left, right)
+	case treebin.VectorCosDistance:
+		return b.factory.ConstructVectorCosDistance(left, right)
+	case treebin.VectorNegInnerProduct:
+		return b.factory.ConstructVectorNegInnerProduct(left, right)
 	}
 	panic(errors.AssertionFailedf("unhandled binary operator: %s", redact.Safe(bin)))
 }
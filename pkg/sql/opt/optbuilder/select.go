@@ -120,6 +120,9 @@ func (b *Builder) buildDataSource(
 				c := b.factory.Metadata().ColumnMeta(id)
 				newCol := b.synthesizeColumn(outScope, scopeColName(tree.Name(col.Alias)), c.Type, nil, nil)
 				newCol.table = *tn
+				if cte.hiddenCols.Contains(id) {
+					newCol.visibility = accessibleByName
+				}
 				inCols[i] = id
 				outCols[i] = newCol.id
 			}
@@ -146,7 +149,7 @@ func (b *Builder) buildDataSource(
 		switch t := ds.(type) {
 		case cat.Table:
 			tabMeta := b.addTable(t, &resName)
-			return b.buildScan(
+			outScope = b.buildScan(
 				tabMeta,
 				tableOrdinals(t, columnKinds{
 					includeMutations: false,
@@ -156,6 +159,7 @@ func (b *Builder) buildDataSource(
 				indexFlags, locking, inScope,
 				false, /* disableNotVisibleIndex */
 			)
+			return b.buildInheritanceScan(t, locking, inScope, outScope)
 
 		case cat.Sequence:
 			return b.buildSequenceSelect(t, &resName, inScope)
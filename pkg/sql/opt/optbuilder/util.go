@@ -552,6 +552,7 @@ func (b *Builder) resolveTableForMutation(
 	case *tree.TableName:
 		tab, alias = b.resolveTable(t, priv)
 		depName = opt.DepByName(t)
+		checkInheritanceForMutation(tab, priv)
 
 	case *tree.TableRef:
 		tab = b.resolveTableRef(t, priv)
@@ -576,8 +577,9 @@ func (b *Builder) resolveTableForMutation(
 		alias = *outerAlias
 	}
 
-	// We can't mutate materialized views.
-	if tab.IsMaterializedView() {
+	// We can't mutate materialized views, except to refresh them
+	// incrementally.
+	if tab.IsMaterializedView() && !b.evalCtx.SessionData().AllowMaterializedViewMutations {
 		panic(pgerror.Newf(pgcode.WrongObjectType, "cannot mutate materialized view %q", tab.Name()))
 	}
 
@@ -261,7 +261,7 @@ func (b *Builder) buildAggregationAsWindow(
 
 	// Build the arguments, partitions and orderings for each aggregate.
 	for i, agg := range g.aggs {
-		argExprs := getTypedExprs(agg.Exprs)
+		argExprs := getTypedAggArgs(agg.def.Name, agg.Exprs)
 
 		// Build the appropriate arguments.
 		argLists[i] = b.buildWindowArgs(argExprs, i, agg.def.Name, fromScope, g.aggInScope)
@@ -337,7 +337,7 @@ func (b *Builder) buildAggregationAsWindow(
 // projecting the default argument to some window functions when we could just
 // not do that projection.
 func (b *Builder) getTypedWindowArgs(w *windowInfo) []tree.TypedExpr {
-	argExprs := getTypedExprs(w.Exprs)
+	argExprs := getTypedAggArgs(w.def.Name, w.Exprs)
 
 	switch w.def.Name {
 	// The second argument of {lead,lag} is 1 by default, and the third argument
@@ -35,6 +35,9 @@ type cteSource struct {
 	originalExpr tree.Statement
 	expr         memo.RelExpr
 	mtr          tree.CTEMaterializeClause
+	// hiddenCols contains columns that are only accessible by name when the CTE
+	// is referenced. It is only set for CTEs synthesized by the optbuilder.
+	hiddenCols opt.ColSet
 	// If set, this function is called when a CTE is referenced. It can throw an
 	// error.
 	onRef func()
@@ -33,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
 )
 
@@ -369,6 +370,9 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 		case *tree.IndexTableDef:
 			tab.addIndex(def, nonUniqueIndex)
 
+		case *tree.ExclusionConstraintTableDef:
+			tab.addExclusionConstraint(def)
+
 		case *tree.FamilyTableDef:
 			tab.addFamily(def)
 
@@ -726,6 +730,27 @@ func (tt *Table) addUniqueConstraint(
 	tt.uniqueConstraints = append(tt.uniqueConstraints, u)
 }
 
+// addExclusionConstraint adds an exclusion constraint to the table.
+func (tt *Table) addExclusionConstraint(def *tree.ExclusionConstraintTableDef) {
+	e := ExclusionConstraint{
+		name:           string(def.Name),
+		tabID:          tt.TabID,
+		columnOrdinals: make([]int, len(def.Elems)),
+		operators:      make([]treecmp.ComparisonOperatorSymbol, len(def.Elems)),
+	}
+	if e.name == "" {
+		e.name = fmt.Sprintf("%s_excl%d", tt.TabName.Table(), len(tt.exclusionConstraints)+1)
+	}
+	for i := range def.Elems {
+		e.columnOrdinals[i] = tt.FindOrdinal(string(def.Elems[i].Column))
+		e.operators[i] = def.Elems[i].Operator.Symbol
+	}
+	if def.Predicate != nil {
+		e.predicate = tree.Serialize(def.Predicate)
+	}
+	tt.exclusionConstraints = append(tt.exclusionConstraints, e)
+}
+
 func (tt *Table) addColumn(def *tree.ColumnTableDef) {
 	ordinal := len(tt.Columns)
 	nullable := !def.PrimaryKey.IsPrimaryKey && def.Nullable.Nullability != tree.NotNull
@@ -924,6 +949,11 @@ func (tt *Table) addIndexWithVersion(
 						MaxCells: 3,
 					}},
 				}
+
+			case types.PGVectorFamily:
+				// Don't use the default config because it creates a large number of
+				// lists.
+				idx.vectorConfig = vecindex.Config{Lists: 4}
 			}
 		}
 	}
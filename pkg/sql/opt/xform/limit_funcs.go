@@ -13,6 +13,7 @@ package xform
 import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedidx"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/ordering"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
@@ -346,6 +347,80 @@ func (c *CustomFuncs) GenerateLimitedTopKScans(
 	})
 }
 
+// GenerateVectorSearchScans generates a TopK over a constrained scan of each
+// vector index on the scanned table that can be used to find the nearest
+// neighbors of the first ordering column of the TopK. The ordering column must
+// be an ascending distance between the indexed column and a constant vector
+// that is computed by one of the given projections. An IndexJoin supplies the
+// columns that are missing from the index.
+func (c *CustomFuncs) GenerateVectorSearchScans(
+	grp memo.RelExpr,
+	required *physical.Required,
+	sp *memo.ScanPrivate,
+	projections memo.ProjectionsExpr,
+	passthrough opt.ColSet,
+	tp *memo.TopKPrivate,
+) {
+	if len(tp.Ordering.Columns) == 0 || tp.Ordering.Columns[0].Descending {
+		return
+	}
+	if sp.Flags.NoIndexJoin {
+		return
+	}
+
+	// Find the projection that computes the distance used by the ordering.
+	var distance opt.ScalarExpr
+	for i := range projections {
+		if tp.Ordering.Columns[0].Group.Contains(projections[i].Col) {
+			distance = projections[i].Element
+			break
+		}
+	}
+	if distance == nil {
+		return
+	}
+
+	probes := int(c.e.evalCtx.SessionData().VectorSearchProbes)
+	var pkCols opt.ColSet
+	var iter scanIndexIter
+	var sb indexScanBuilder
+	sb.Init(c, sp.Table)
+	iter.Init(c.e.evalCtx, c.e, c.e.mem, &c.im, sp, nil /* filters */, rejectNonInvertedIndexes)
+	iter.ForEach(func(index cat.Index, _ memo.FiltersExpr, _ opt.ColSet, _ bool, constProj memo.ProjectionsExpr) {
+		// The iterator only produces pseudo-partial indexes (the predicate is
+		// true) because no filters are passed to iter.Init to imply a partial
+		// index predicate. constProj should therefore always be empty.
+		if len(constProj) != 0 {
+			panic(errors.AssertionFailedf("expected constProj to be empty"))
+		}
+
+		spans, ok := invertedidx.TryVectorSearchIndex(sp.Table, index, distance, probes)
+		if !ok {
+			return
+		}
+
+		// Calculate the PK columns once.
+		if pkCols.Empty() {
+			pkCols = c.PrimaryKeyCols(sp.Table)
+		}
+
+		// Scan the primary key columns from the lists closest to the query
+		// vector.
+		newScanPrivate := *sp
+		newScanPrivate.Distribution.Regions = nil
+		newScanPrivate.Index = index.Ordinal()
+		newScanPrivate.Cols = pkCols.Copy()
+		newScanPrivate.InvertedConstraint = spans
+		sb.SetScan(&newScanPrivate)
+
+		// Construct an IndexJoin operator that provides the columns missing from
+		// the index, and recompute the distance on top of it.
+		sb.AddIndexJoin(sp.Cols)
+		input := c.e.f.ConstructProject(sb.BuildNewExpr(), projections, passthrough)
+		grp.Memo().AddTopKToGroup(&memo.TopKExpr{Input: input, TopKPrivate: *tp}, grp)
+	})
+}
+
 // getPrefixFromOrdering returns an OrderingChoice that holds the prefix
 // of Ordering o that satisfies part of the required OrderingChoice intraOrd,
 // a bool indicating whether the entire Ordering o was satisfied, and a bool
@@ -45,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/syntheticprivilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -766,6 +767,8 @@ type optTable struct {
 
 	uniqueConstraints []optUniqueConstraint
 
+	exclusionConstraints []optExclusionConstraint
+
 	outboundFKs []optForeignKeyConstraint
 	inboundFKs  []optForeignKeyConstraint
 
@@ -916,6 +919,25 @@ func newOptTable(
 		}
 	}
 
+	// Add exclusion constraints.
+	ot.exclusionConstraints = make([]optExclusionConstraint, len(ot.desc.EnforcedExclusionConstraints()))
+	for i, e := range ot.desc.EnforcedExclusionConstraints() {
+		ec := &ot.exclusionConstraints[i]
+		ec.name = e.GetName()
+		ec.table = ot.ID()
+		ec.predicate = e.GetPredicate()
+		ec.columns = make([]descpb.ColumnID, e.NumKeyColumns())
+		ec.operators = make([]treecmp.ComparisonOperatorSymbol, e.NumKeyColumns())
+		for j := range ec.columns {
+			ec.columns[j] = e.GetKeyColumnID(j)
+			op, err := exclusionConstraintOperator(e.GetOperator(j))
+			if err != nil {
+				return nil, err
+			}
+			ec.operators[j] = op
+		}
+	}
+
 	// Build the indexes.
 	ot.indexes = make([]optIndex, 1+len(secondaryIndexes))
 	// partZones is allocated lazily and is reused for all indexes.
@@ -1039,8 +1061,22 @@ func newOptTable(
 		ot.families[i].init(ot, &desc.GetFamilies()[i+1])
 	}
 
-	// Synthesize any check constraints for user defined types.
+	// Synthesize a check constraint which enforces the bound of a table that is
+	// a partition of a partitioned table. It must be the first synthesized
+	// check constraint, since it is enforced by checkMutationInput.
 	var synthesizedChecks []cat.CheckConstraint
+	if bound := desc.GetPartitionBound(); bound != nil {
+		expr, err := partitionBoundCheckExpr(desc, bound)
+		if err != nil {
+			return nil, err
+		}
+		synthesizedChecks = append(synthesizedChecks, cat.CheckConstraint{
+			Constraint: tree.Serialize(expr),
+			Validated:  true,
+		})
+	}
+
+	// Synthesize any check constraints for user defined types.
 	for i := 0; i < ot.ColumnCount(); i++ {
 		col := ot.Column(i)
 		if col.IsMutation() {
@@ -1300,6 +1336,31 @@ func (ot *optTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	return &ot.uniqueConstraints[i]
 }
 
+// ExclusionConstraintCount is part of the cat.Table interface.
+func (ot *optTable) ExclusionConstraintCount() int {
+	return len(ot.exclusionConstraints)
+}
+
+// ExclusionConstraint is part of the cat.Table interface.
+func (ot *optTable) ExclusionConstraint(i int) cat.ExclusionConstraint {
+	return &ot.exclusionConstraints[i]
+}
+
+// InheritanceChildCount is part of the cat.Table interface.
+func (ot *optTable) InheritanceChildCount() int {
+	return len(ot.desc.GetInheritedBy())
+}
+
+// InheritanceChild is part of the cat.Table interface.
+func (ot *optTable) InheritanceChild(i int) cat.StableID {
+	return cat.StableID(ot.desc.GetInheritedBy()[i])
+}
+
+// IsPartitioned is part of the cat.Table interface.
+func (ot *optTable) IsPartitioned() bool {
+	return ot.desc.GetPartitionKey() != nil
+}
+
 // Zone is part of the cat.Table interface.
 func (ot *optTable) Zone() cat.Zone {
 	return ot.zone
@@ -1696,6 +1757,11 @@ func (oi *optIndex) GeoConfig() geoindex.Config {
 	return oi.idx.IndexDesc().GeoConfig
 }
 
+// VectorConfig is part of the cat.Index interface.
+func (oi *optIndex) VectorConfig() vecindex.Config {
+	return oi.idx.IndexDesc().VectorConfig
+}
+
 // Version is part of the cat.Index interface.
 func (oi *optIndex) Version() descpb.IndexDescriptorVersion {
 	return oi.idx.GetVersion()
@@ -1948,6 +2014,52 @@ func (u *optUniqueConstraint) UniquenessGuaranteedByAnotherIndex() bool {
 	return u.uniquenessGuaranteedByAnotherIndex
 }
 
+// optExclusionConstraint implements cat.ExclusionConstraint and represents an
+// exclusion constraint.
+type optExclusionConstraint struct {
+	name string
+
+	table     cat.StableID
+	columns   []descpb.ColumnID
+	operators []treecmp.ComparisonOperatorSymbol
+	predicate string
+}
+
+var _ cat.ExclusionConstraint = &optExclusionConstraint{}
+
+// Name is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Name() string {
+	return e.name
+}
+
+// ColumnCount is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) ColumnCount() int {
+	return len(e.columns)
+}
+
+// ColumnOrdinal is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) ColumnOrdinal(tab cat.Table, i int) int {
+	if tab.ID() != e.table {
+		panic(errors.AssertionFailedf(
+			"invalid table %d passed to ColumnOrdinal (expected %d)",
+			tab.ID(), e.table,
+		))
+	}
+	optTab := convertTableToOptTable(tab)
+	ord, _ := optTab.lookupColumnOrdinal(e.columns[i])
+	return ord
+}
+
+// Operator is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Operator(i int) treecmp.ComparisonOperatorSymbol {
+	return e.operators[i]
+}
+
+// Predicate is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Predicate() (string, bool) {
+	return e.predicate, e.predicate != ""
+}
+
 // optForeignKeyConstraint implements cat.ForeignKeyConstraint and represents a
 // foreign key relationship. Both the origin and the referenced table store the
 // same optForeignKeyConstraint (as an outbound and inbound reference,
@@ -2334,6 +2446,31 @@ func (ot *optVirtualTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	panic(errors.AssertionFailedf("no unique constraints"))
 }
 
+// ExclusionConstraintCount is part of the cat.Table interface.
+func (ot *optVirtualTable) ExclusionConstraintCount() int {
+	return 0
+}
+
+// ExclusionConstraint is part of the cat.Table interface.
+func (ot *optVirtualTable) ExclusionConstraint(i int) cat.ExclusionConstraint {
+	panic(errors.AssertionFailedf("no exclusion constraints"))
+}
+
+// InheritanceChildCount is part of the cat.Table interface.
+func (ot *optVirtualTable) InheritanceChildCount() int {
+	return 0
+}
+
+// InheritanceChild is part of the cat.Table interface.
+func (ot *optVirtualTable) InheritanceChild(i int) cat.StableID {
+	panic(errors.AssertionFailedf("no inheritance children"))
+}
+
+// IsPartitioned is part of the cat.Table interface.
+func (ot *optVirtualTable) IsPartitioned() bool {
+	return false
+}
+
 // Zone is part of the cat.Table interface.
 func (ot *optVirtualTable) Zone() cat.Zone {
 	panic(errors.AssertionFailedf("no zone"))
@@ -2558,6 +2695,11 @@ func (oi *optVirtualIndex) GeoConfig() geoindex.Config {
 	return geoindex.Config{}
 }
 
+// VectorConfig is part of the cat.Index interface.
+func (oi *optVirtualIndex) VectorConfig() vecindex.Config {
+	return vecindex.Config{}
+}
+
 // Version is part of the cat.Index interface.
 func (oi *optVirtualIndex) Version() descpb.IndexDescriptorVersion {
 	return 0
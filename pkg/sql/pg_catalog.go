@@ -909,7 +909,6 @@ var (
 
 	// Avoid unused warning for constants.
 	_ = conTypeTrigger
-	_ = conTypeExclusion
 
 	fkActionNone       = tree.NewDString("a")
 	fkActionRestrict   = tree.NewDString("r")
@@ -1072,6 +1071,41 @@ func populateTableConstraints(
 				f.WriteString(fmt.Sprintf(" WHERE (%s)", pred))
 			}
 			condef = tree.NewDString(f.CloseAndGetString())
+		} else if ec := c.AsExclusion(); ec != nil {
+			contype = conTypeExclusion
+			conoid = h.ExclusionConstraintOid(db.GetID(), sc.GetID(), table.GetID(), ec)
+			if conkey, err = colIDArrayToDatum(ec.ExclusionDesc().ColumnIDs); err != nil {
+				return err
+			}
+			f := tree.NewFmtCtx(tree.FmtSimple)
+			f.WriteString("EXCLUDE ")
+			if method := ec.ExclusionDesc().IndexMethod; method != "" {
+				f.WriteString("USING ")
+				f.WriteString(method)
+				f.WriteByte(' ')
+			}
+			f.WriteByte('(')
+			for i := 0; i < ec.NumKeyColumns(); i++ {
+				if i > 0 {
+					f.WriteString(", ")
+				}
+				col, err := catalog.MustFindColumnByID(table, ec.GetKeyColumnID(i))
+				if err != nil {
+					return err
+				}
+				f.FormatNameP(&col.ColumnDesc().Name)
+				f.WriteString(" WITH ")
+				f.WriteString(ec.GetOperator(i))
+			}
+			f.WriteByte(')')
+			if ec.IsPartial() {
+				pred, err := schemaexpr.FormatExprForDisplay(ctx, table, ec.GetPredicate(), p.SemaCtx(), p.SessionData(), tree.FmtPGCatalog)
+				if err != nil {
+					return err
+				}
+				f.WriteString(fmt.Sprintf(" WHERE (%s)", pred))
+			}
+			condef = tree.NewDString(f.CloseAndGetString())
 		} else if ck := c.AsCheck(); ck != nil {
 			conoid = h.CheckConstraintOid(db.GetID(), sc.GetID(), table.GetID(), ck)
 			contype = conTypeCheck
@@ -1729,6 +1763,13 @@ func getOIDFromConstraint(
 			tableID,
 			uc,
 		)
+	} else if ec := constraint.AsExclusion(); ec != nil {
+		oid = hasher.ExclusionConstraintOid(
+			dbID,
+			scID,
+			tableID,
+			ec,
+		)
 	} else if ic := constraint.AsUniqueWithIndex(); ic != nil {
 		if ic.GetID() == tableDesc.GetPrimaryIndexID() {
 			oid = hasher.PrimaryKeyConstraintOid(
@@ -3988,12 +4029,35 @@ var pgCatalogStatUserFunctionsTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationTable = virtualSchemaTable{
-	comment: "pg_publication was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublication,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `publications
+https://www.postgresql.org/docs/13/catalog-pg-publication.html`,
+	schema: vtable.PgCatalogPublication,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachDatabaseDesc(ctx, p, dbContext, false, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				pubs, err := getPublications(ctx, p.InternalSQLTxn(), db.GetID())
+				if err != nil {
+					return err
+				}
+				for _, pub := range pubs {
+					if err := addRow(
+						h.PublicationOid(db.GetID(), pub.name),    // oid
+						tree.NewDName(pub.name),                   // pubname
+						h.UserOid(pub.owner),                      // pubowner
+						tree.MakeDBool(tree.DBool(pub.allTables)), // puballtables
+						tree.DBoolTrue,                            // pubinsert
+						tree.DBoolTrue,                            // pubupdate
+						tree.DBoolTrue,                            // pubdelete
+						tree.DBoolFalse,                           // pubtruncate
+						tree.DBoolFalse,                           // pubviaroot
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogAmprocTable = virtualSchemaTable{
@@ -4148,12 +4212,19 @@ var pgCatalogStatXactUserTablesTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationTablesTable = virtualSchemaTable{
-	comment: "pg_publication_tables was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationTables,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `tables in publications
+https://www.postgresql.org/docs/13/view-pg-publication-tables.html`,
+	schema: vtable.PgCatalogPublicationTables,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachPublishedTable(ctx, p, dbContext, true, /* includeAllTables */
+			func(db catalog.DatabaseDescriptor, pub *publication, sc catalog.SchemaDescriptor, table catalog.TableDescriptor) error {
+				return addRow(
+					tree.NewDName(pub.name),        // pubname
+					tree.NewDName(sc.GetName()),    // schemaname
+					tree.NewDName(table.GetName()), // tablename
+				)
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogStatProgressClusterTable = virtualSchemaTable{
@@ -4184,12 +4255,49 @@ var pgCatalogLargeobjectMetadataTable = virtualSchemaTable{
 }
 
 var pgCatalogReplicationSlotsTable = virtualSchemaTable{
-	comment: "pg_replication_slots was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogReplicationSlots,
+	comment: `replication slots
+https://www.postgresql.org/docs/13/view-pg-replication-slots.html`,
+	schema: vtable.PgCatalogReplicationSlots,
 	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		dbNames := make(map[descpb.ID]string)
+		if err := forEachDatabaseDesc(ctx, p, nil /* dbContext */, false, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				dbNames[db.GetID()] = db.GetName()
+				return nil
+			}); err != nil {
+			return err
+		}
+		slots, err := getReplicationSlots(ctx, p.InternalSQLTxn())
+		if err != nil {
+			return err
+		}
+		for _, slot := range slots {
+			database := tree.DNull
+			if name, ok := dbNames[slot.databaseID]; ok {
+				database = tree.NewDName(name)
+			}
+			confirmedFlush := tree.NewDString(slot.confirmedFlush.String())
+			if err := addRow(
+				tree.NewDName(slot.name),   // slot_name
+				tree.NewDName(slot.plugin), // plugin
+				tree.NewDString("logical"), // slot_type
+				dbOid(slot.databaseID),     // datoid
+				database,                   // database
+				tree.DBoolFalse,            // temporary
+				tree.DBoolFalse,            // active
+				tree.DNull,                 // active_pid
+				tree.DNull,                 // xmin
+				tree.DNull,                 // catalog_xmin
+				confirmedFlush,             // restart_lsn
+				confirmedFlush,             // confirmed_flush_lsn
+				tree.DNull,                 // wal_status
+				tree.DNull,                 // safe_wal_size
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 var pgCatalogSubscriptionRelTable = virtualSchemaTable{
@@ -4460,12 +4568,20 @@ var pgCatalogStatReplicationTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationRelTable = virtualSchemaTable{
-	comment: "pg_publication_rel was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationRel,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `mapping between publications and the tables they explicitly list
+https://www.postgresql.org/docs/13/catalog-pg-publication-rel.html`,
+	schema: vtable.PgCatalogPublicationRel,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachPublishedTable(ctx, p, dbContext, false, /* includeAllTables */
+			func(db catalog.DatabaseDescriptor, pub *publication, _ catalog.SchemaDescriptor, table catalog.TableDescriptor) error {
+				return addRow(
+					h.publicationRelOid(db.GetID(), pub.name, table.GetID()), // oid
+					h.PublicationOid(db.GetID(), pub.name),                   // prpubid
+					tableOid(table.GetID()),                                  // prrelid
+				)
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogAvailableExtensionVersionsTable = virtualSchemaTable{
@@ -4529,6 +4645,8 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.GeographyFamily:   typCategoryUserDefined,
 	types.GeometryFamily:    typCategoryUserDefined,
 	types.JsonFamily:        typCategoryUserDefined,
+	types.JsonpathFamily:    typCategoryUserDefined,
+	types.PGVectorFamily:    typCategoryUserDefined,
 	types.DecimalFamily:     typCategoryNumeric,
 	types.StringFamily:      typCategoryString,
 	types.TimestampFamily:   typCategoryDateTime,
@@ -4756,6 +4874,9 @@ const (
 	rewriteTypeTag
 	dbSchemaRoleTypeTag
 	castTypeTag
+	publicationTypeTag
+	publicationRelTypeTag
+	exclusionConstraintTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -4861,6 +4982,17 @@ func (h oidHasher) UniqueWithoutIndexConstraintOid(
 	return h.getOid()
 }
 
+func (h oidHasher) ExclusionConstraintOid(
+	dbID descpb.ID, scID descpb.ID, tableID descpb.ID, ec catalog.ExclusionConstraint,
+) *tree.DOid {
+	h.writeTypeTag(exclusionConstraintTypeTag)
+	h.writeDB(dbID)
+	h.writeSchema(scID)
+	h.writeTable(tableID)
+	h.writeStr(ec.GetName())
+	return h.getOid()
+}
+
 func (h oidHasher) UniqueConstraintOid(
 	dbID descpb.ID, scID descpb.ID, tableID descpb.ID, uwi catalog.UniqueWithIndexConstraint,
 ) *tree.DOid {
@@ -4955,6 +5087,25 @@ func (h oidHasher) CastOid(srcID oid.Oid, tgtID oid.Oid) *tree.DOid {
 	return h.getOid()
 }
 
+// PublicationOid creates an OID for the publication with the given name in
+// the given database.
+func (h oidHasher) PublicationOid(dbID descpb.ID, name string) *tree.DOid {
+	h.writeTypeTag(publicationTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(name)
+	return h.getOid()
+}
+
+func (h oidHasher) publicationRelOid(
+	dbID descpb.ID, name string, tableID descpb.ID,
+) *tree.DOid {
+	h.writeTypeTag(publicationRelTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(name)
+	h.writeTable(tableID)
+	return h.getOid()
+}
+
 func funcVolatility(v catpb.Function_Volatility) string {
 	switch v {
 	case catpb.Function_IMMUTABLE:
@@ -17,9 +17,30 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 )
 
+// logicalBits is the number of low bits of a LSN holding the logical component
+// of a HLC timestamp. The remaining high bits hold the wall time in
+// microseconds, which leaves room for timestamps until the year 2262.
+const logicalBits = 12
+
+const maxLogical = 1<<logicalBits - 1
+
 // HLCToLSN converts a HLC to a LSN.
 // It is in a separate package to prevent the `lsn` package importing `log`.
+//
+// The conversion preserves the order of timestamps, but timestamps less than a
+// microsecond apart may map to the same LSN.
 func HLCToLSN(h hlc.Timestamp) lsn.LSN {
-	// TODO(#105130): correctly populate this field.
-	return lsn.LSN(h.WallTime/int64(time.Millisecond)) << 32
+	logical := h.Logical
+	if logical > maxLogical {
+		logical = maxLogical
+	}
+	return lsn.LSN(h.WallTime/int64(time.Microsecond))<<logicalBits | lsn.LSN(logical)
+}
+
+// LSNToHLC returns the smallest HLC timestamp that converts to the given LSN.
+func LSNToHLC(l lsn.LSN) hlc.Timestamp {
+	return hlc.Timestamp{
+		WallTime: int64(l>>logicalBits) * int64(time.Microsecond),
+		Logical:  int32(l & maxLogical),
+	}
 }
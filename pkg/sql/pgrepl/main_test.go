@@ -86,30 +86,16 @@ func TestDataDriven(t *testing.T) {
 				return out
 			case "identify_system":
 				// IDENTIFY_SYSTEM needs some redaction to be deterministic.
-				rows, err := conn.Query(ctx, "IDENTIFY_SYSTEM", pgx.QuerySimpleProtocol(true))
-				require.NoError(t, err)
-				var sb strings.Builder
-				for rows.Next() {
-					vals, err := rows.Values()
-					require.NoError(t, err)
-					for i, val := range vals {
-						if i > 0 {
-							sb.WriteRune('\n')
-						}
-						switch string(rows.FieldDescriptions()[i].Name) {
-						case "systemid":
-							val = "some_cluster_id"
-						case "xlogpos":
-							val = "some_lsn"
-						}
-						sb.Write(rows.FieldDescriptions()[i].Name)
-						sb.WriteString(": ")
-						sb.WriteString(fmt.Sprintf("%v", val))
-					}
-				}
-				require.NoError(t, rows.Err())
-				rows.Close()
-				return sb.String()
+				return queryRedacted(t, ctx, conn, "IDENTIFY_SYSTEM", map[string]string{
+					"systemid": "some_cluster_id",
+					"xlogpos":  "some_lsn",
+				})
+			case "create_replication_slot":
+				// The consistent point of a slot depends on the time at which it
+				// was created.
+				return queryRedacted(t, ctx, conn, d.Input, map[string]string{
+					"consistent_point": "some_lsn",
+				})
 			default:
 				t.Errorf("unhandled command %s", d.Cmd)
 			}
@@ -117,3 +103,33 @@ func TestDataDriven(t *testing.T) {
 		})
 	})
 }
+
+// queryRedacted runs a query returning a single row using the simple protocol,
+// and formats the row with one line per column. The values of the columns in
+// redact are replaced by the corresponding placeholders.
+func queryRedacted(
+	t *testing.T, ctx context.Context, conn *pgx.Conn, query string, redact map[string]string,
+) string {
+	rows, err := conn.Query(ctx, query, pgx.QuerySimpleProtocol(true))
+	require.NoError(t, err)
+	var sb strings.Builder
+	for rows.Next() {
+		vals, err := rows.Values()
+		require.NoError(t, err)
+		for i, val := range vals {
+			if i > 0 {
+				sb.WriteRune('\n')
+			}
+			name := string(rows.FieldDescriptions()[i].Name)
+			if r, ok := redact[name]; ok {
+				val = r
+			}
+			sb.WriteString(name)
+			sb.WriteString(": ")
+			sb.WriteString(fmt.Sprintf("%v", val))
+		}
+	}
+	require.NoError(t, rows.Err())
+	rows.Close()
+	return sb.String()
+}
@@ -0,0 +1,245 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package pgoutput encodes logical replication messages in the format of
+// PostgreSQL's pgoutput output plugin, as well as the streaming replication
+// messages that carry them over a CopyBoth connection.
+//
+// See https://www.postgresql.org/docs/current/protocol-logicalrep-message-formats.html
+// and https://www.postgresql.org/docs/current/protocol-replication.html.
+package pgoutput
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/errors"
+)
+
+// PluginName is the name of the output plugin.
+const PluginName = "pgoutput"
+
+// ProtoVersion is the version of the logical replication protocol that is
+// produced. Version 1 does not stream in-progress transactions, which is the
+// only mode supported.
+const ProtoVersion = 1
+
+// Logical replication message types.
+const (
+	msgBegin    = 'B'
+	msgCommit   = 'C'
+	msgRelation = 'R'
+	msgInsert   = 'I'
+	msgUpdate   = 'U'
+	msgDelete   = 'D'
+)
+
+// Streaming replication message types.
+const (
+	msgXLogData           = 'w'
+	msgPrimaryKeepalive   = 'k'
+	msgStandbyStatus      = 'r'
+	msgHotStandbyFeedback = 'h'
+)
+
+// Tuple column kinds.
+const (
+	tupleNull = 'n'
+	tupleText = 't'
+)
+
+// replicaIdentityDefault indicates that the old values of the primary key
+// columns are sent with UPDATE and DELETE messages.
+const replicaIdentityDefault = 'd'
+
+// Column describes a column of a replicated relation.
+type Column struct {
+	Name string
+	Type *types.T
+	// Key is true if the column is part of the replica identity, which is the
+	// primary key.
+	Key bool
+}
+
+// Relation describes a replicated table. A RELATION message describing a
+// table is sent before the first change to it, and again every time its
+// schema changes.
+type Relation struct {
+	// ID identifies the relation in INSERT, UPDATE and DELETE messages.
+	ID        uint32
+	Namespace string
+	Name      string
+	Columns   []Column
+}
+
+// AppendBegin appends a BEGIN message to buf. finalLSN is the LSN of the
+// transaction's commit.
+func AppendBegin(buf []byte, finalLSN lsn.LSN, commitTime time.Time, xid uint32) []byte {
+	buf = append(buf, msgBegin)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(finalLSN))
+	buf = appendTime(buf, commitTime)
+	return binary.BigEndian.AppendUint32(buf, xid)
+}
+
+// AppendCommit appends a COMMIT message to buf.
+func AppendCommit(buf []byte, commitLSN, endLSN lsn.LSN, commitTime time.Time) []byte {
+	buf = append(buf, msgCommit)
+	buf = append(buf, 0 /* flags */)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(commitLSN))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(endLSN))
+	return appendTime(buf, commitTime)
+}
+
+// AppendRelation appends a RELATION message describing rel to buf.
+func AppendRelation(buf []byte, rel *Relation) []byte {
+	buf = append(buf, msgRelation)
+	buf = binary.BigEndian.AppendUint32(buf, rel.ID)
+	buf = appendString(buf, rel.Namespace)
+	buf = appendString(buf, rel.Name)
+	buf = append(buf, replicaIdentityDefault)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(rel.Columns)))
+	for _, col := range rel.Columns {
+		var flags byte
+		if col.Key {
+			flags = 1
+		}
+		buf = append(buf, flags)
+		buf = appendString(buf, col.Name)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(col.Type.Oid()))
+		buf = binary.BigEndian.AppendUint32(buf, uint32(col.Type.TypeModifier()))
+	}
+	return buf
+}
+
+// AppendInsert appends an INSERT message for a new row of rel to buf.
+func AppendInsert(buf []byte, rel *Relation, row tree.Datums) []byte {
+	buf = append(buf, msgInsert)
+	buf = binary.BigEndian.AppendUint32(buf, rel.ID)
+	buf = append(buf, 'N')
+	return appendTuple(buf, row)
+}
+
+// AppendUpdate appends an UPDATE message for a row of rel to buf. The primary
+// key of a row never changes in an update, since such a change is replicated
+// as a delete followed by an insert, so the old key is never sent.
+func AppendUpdate(buf []byte, rel *Relation, row tree.Datums) []byte {
+	buf = append(buf, msgUpdate)
+	buf = binary.BigEndian.AppendUint32(buf, rel.ID)
+	buf = append(buf, 'N')
+	return appendTuple(buf, row)
+}
+
+// AppendDelete appends a DELETE message for a row of rel to buf. Only the
+// values of the key columns of row are sent.
+func AppendDelete(buf []byte, rel *Relation, row tree.Datums) []byte {
+	buf = append(buf, msgDelete)
+	buf = binary.BigEndian.AppendUint32(buf, rel.ID)
+	buf = append(buf, 'K')
+	key := make(tree.Datums, len(row))
+	for i := range row {
+		key[i] = tree.DNull
+		if rel.Columns[i].Key {
+			key[i] = row[i]
+		}
+	}
+	return appendTuple(buf, key)
+}
+
+// AppendXLogData appends a XLogData message carrying the logical replication
+// message msg to buf. start is the LSN of the message and end is the current
+// end of the stream.
+func AppendXLogData(buf []byte, start, end lsn.LSN, sendTime time.Time, msg []byte) []byte {
+	buf = append(buf, msgXLogData)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(start))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(end))
+	buf = appendTime(buf, sendTime)
+	return append(buf, msg...)
+}
+
+// AppendPrimaryKeepalive appends a primary keepalive message to buf. end is
+// the current end of the stream. If replyRequested is true, the client should
+// reply with a standby status update as soon as possible.
+func AppendPrimaryKeepalive(buf []byte, end lsn.LSN, sendTime time.Time, replyRequested bool) []byte {
+	buf = append(buf, msgPrimaryKeepalive)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(end))
+	buf = appendTime(buf, sendTime)
+	var reply byte
+	if replyRequested {
+		reply = 1
+	}
+	return append(buf, reply)
+}
+
+// StandbyStatusUpdate is sent by the client to report its progress.
+type StandbyStatusUpdate struct {
+	// Written, Flushed and Applied are the LSNs up to which the client has
+	// received, durably stored and applied the stream, respectively.
+	Written, Flushed, Applied lsn.LSN
+	ClientTime                time.Time
+	// ReplyRequested is true if the client asked for an immediate keepalive.
+	ReplyRequested bool
+}
+
+// ParseClientMessage parses a message sent by the client in a CopyData
+// message. It returns nil for messages that carry no information of interest.
+func ParseClientMessage(data []byte) (*StandbyStatusUpdate, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty replication feedback message")
+	}
+	switch data[0] {
+	case msgStandbyStatus:
+		if len(data) != 34 {
+			return nil, errors.Newf("invalid standby status update length: %d", len(data))
+		}
+		u := &StandbyStatusUpdate{
+			Written:        lsn.LSN(binary.BigEndian.Uint64(data[1:])),
+			Flushed:        lsn.LSN(binary.BigEndian.Uint64(data[9:])),
+			Applied:        lsn.LSN(binary.BigEndian.Uint64(data[17:])),
+			ClientTime:     duration.AddMicros(pgwirebase.PGEpochJDate, int64(binary.BigEndian.Uint64(data[25:]))),
+			ReplyRequested: data[33] != 0,
+		}
+		return u, nil
+	case msgHotStandbyFeedback:
+		// Hot standby feedback only matters for physical replication.
+		return nil, nil
+	default:
+		return nil, errors.Newf("unknown replication feedback message type %q", data[0])
+	}
+}
+
+func appendTime(buf []byte, t time.Time) []byte {
+	return binary.BigEndian.AppendUint64(buf, uint64(duration.DiffMicros(t, pgwirebase.PGEpochJDate)))
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = append(buf, s...)
+	return append(buf, 0)
+}
+
+// appendTuple appends the values of row in text format.
+func appendTuple(buf []byte, row tree.Datums) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(row)))
+	for _, d := range row {
+		if d == tree.DNull {
+			buf = append(buf, tupleNull)
+			continue
+		}
+		s := tree.AsStringWithFlags(d, tree.FmtPgwireText, tree.FmtLocation(time.UTC))
+		buf = append(buf, tupleText)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(s)))
+		buf = append(buf, s...)
+	}
+	return buf
+}
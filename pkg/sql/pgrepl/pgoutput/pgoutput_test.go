@@ -0,0 +1,141 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgoutput
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// One second after the PostgreSQL epoch.
+	ts := pgwirebase.PGEpochJDate.Add(time.Second)
+	micros := []byte{0, 0, 0, 0, 0, 0x0f, 0x42, 0x40}
+	l := lsn.LSN(0x0102030405060708)
+	lsnBytes := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+	cat := func(parts ...[]byte) []byte {
+		var b []byte
+		for _, p := range parts {
+			b = append(b, p...)
+		}
+		return b
+	}
+
+	rel := &Relation{
+		ID:        52,
+		Namespace: "public",
+		Name:      "t",
+		Columns: []Column{
+			{Name: "k", Type: types.Int, Key: true},
+			{Name: "v", Type: types.MakeVarChar(10)},
+			{Name: "b", Type: types.Bool},
+		},
+	}
+	row := tree.Datums{tree.NewDInt(1), tree.NewDString("a"), tree.DBoolTrue}
+
+	for _, tc := range []struct {
+		name     string
+		actual   []byte
+		expected []byte
+	}{
+		{
+			name:     "begin",
+			actual:   AppendBegin(nil, l, ts, 7),
+			expected: cat([]byte("B"), lsnBytes, micros, []byte{0, 0, 0, 7}),
+		},
+		{
+			name:     "commit",
+			actual:   AppendCommit(nil, l, l, ts),
+			expected: cat([]byte("C\x00"), lsnBytes, lsnBytes, micros),
+		},
+		{
+			name:   "relation",
+			actual: AppendRelation(nil, rel),
+			expected: cat(
+				[]byte("R\x00\x00\x00\x34public\x00t\x00d\x00\x03"),
+				[]byte("\x01k\x00\x00\x00\x00\x14\xff\xff\xff\xff"),
+				[]byte("\x00v\x00\x00\x00\x04\x13\x00\x00\x00\x0e"),
+				[]byte("\x00b\x00\x00\x00\x00\x10\xff\xff\xff\xff"),
+			),
+		},
+		{
+			name:   "insert",
+			actual: AppendInsert(nil, rel, row),
+			expected: []byte("I\x00\x00\x00\x34N\x00\x03" +
+				"t\x00\x00\x00\x011t\x00\x00\x00\x01at\x00\x00\x00\x01t"),
+		},
+		{
+			name:   "update",
+			actual: AppendUpdate(nil, rel, tree.Datums{tree.NewDInt(1), tree.DNull, tree.DBoolFalse}),
+			expected: []byte("U\x00\x00\x00\x34N\x00\x03" +
+				"t\x00\x00\x00\x011nt\x00\x00\x00\x01f"),
+		},
+		{
+			name:     "delete",
+			actual:   AppendDelete(nil, rel, row),
+			expected: []byte("D\x00\x00\x00\x34K\x00\x03t\x00\x00\x00\x011nn"),
+		},
+		{
+			name:     "xlogdata",
+			actual:   AppendXLogData(nil, l, l, ts, []byte("msg")),
+			expected: cat([]byte("w"), lsnBytes, lsnBytes, micros, []byte("msg")),
+		},
+		{
+			name:     "keepalive",
+			actual:   AppendPrimaryKeepalive(nil, l, ts, true),
+			expected: cat([]byte("k"), lsnBytes, micros, []byte{1}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.actual)
+		})
+	}
+}
+
+func TestParseClientMessage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	msg := []byte{'r'}
+	for i := 1; i <= 3; i++ {
+		msg = append(msg, 0, 0, 0, 0, 0, 0, 0, byte(i))
+	}
+	msg = append(msg, 0, 0, 0, 0, 0, 0x0f, 0x42, 0x40, 1)
+	u, err := ParseClientMessage(msg)
+	require.NoError(t, err)
+	require.Equal(t, &StandbyStatusUpdate{
+		Written:        1,
+		Flushed:        2,
+		Applied:        3,
+		ClientTime:     pgwirebase.PGEpochJDate.Add(time.Second),
+		ReplyRequested: true,
+	}, u)
+
+	u, err = ParseClientMessage([]byte("h"))
+	require.NoError(t, err)
+	require.Nil(t, u)
+
+	_, err = ParseClientMessage(msg[:10])
+	require.Error(t, err)
+	_, err = ParseClientMessage([]byte("x"))
+	require.Error(t, err)
+	_, err = ParseClientMessage(nil)
+	require.Error(t, err)
+}
@@ -162,10 +162,16 @@ func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndica
 	case closeComplete:
 		r.conn.bufferCloseComplete()
 	case readyForQuery:
+		if t == sql.IdleTxnBlock {
+			r.conn.bufferNotifications()
+		}
 		r.conn.bufferReadyForQuery(byte(t))
 		// The error is saved on conn.err.
 		_ /* err */ = r.conn.Flush(r.pos)
 		r.conn.maybeReallocate()
+		if t == sql.IdleTxnBlock {
+			r.conn.setIdle(true)
+		}
 	case emptyQueryResponse:
 		r.conn.bufferEmptyQueryResponse()
 	case flush:
@@ -369,6 +375,16 @@ func (r *commandResult) SendCopyOut(
 	return r.conn.bufferCopyOut(cols, format)
 }
 
+// SendCopyBoth is part of the sql.StartReplicationResult interface.
+func (r *commandResult) SendCopyBoth(ctx context.Context) error {
+	r.assertNotReleased()
+	r.conn.writerState.fi.registerCmd(r.pos)
+	if err := r.conn.bufferCopyBoth(); err != nil {
+		return err
+	}
+	return r.conn.Flush(r.pos)
+}
+
 // SendCopyData is part of the sql.CopyOutResult interface.
 func (r *commandResult) SendCopyData(ctx context.Context, copyData []byte, isHeader bool) error {
 	if err := r.beforeAdd(); err != nil {
@@ -390,6 +406,12 @@ func (r *commandResult) SendCopyDone(ctx context.Context) error {
 	return r.conn.bufferCopyDone()
 }
 
+// Flush is part of the sql.StartReplicationResult interface.
+func (r *commandResult) Flush(ctx context.Context) error {
+	r.assertNotReleased()
+	return r.conn.Flush(r.pos)
+}
+
 // SetRowsAffected is part of the sql.RestrictedCommandResult interface.
 func (r *commandResult) SetRowsAffected(ctx context.Context, n int) {
 	r.assertNotReleased()
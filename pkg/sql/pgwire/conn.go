@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/clusterunique"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser/statements"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgreplparser"
@@ -45,6 +46,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/ring"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -104,6 +106,40 @@ type conn struct {
 	readBuf    pgwirebase.ReadBuffer
 	msgBuilder writeBuffer
 
+	// notifications groups together the state used to deliver the
+	// notifications sent on the channels the session listens on.
+	notifications struct {
+		// listener is nil if the session cannot listen on channels.
+		listener *notify.Listener
+		// msgBuilder and buf are used to send notifications asynchronously,
+		// outside of the goroutine that executes commands.
+		msgBuilder writeBuffer
+		buf        bytes.Buffer
+
+		// mu serializes the writes to the network connection, which can happen
+		// concurrently with the asynchronous delivery of notifications.
+		mu struct {
+			syncutil.Mutex
+			// idle is set while the session waits for a command from the client
+			// outside of a transaction. Notifications are only sent
+			// asynchronously while idle; otherwise they are sent right before
+			// the next ReadyForQuery message sent outside of a transaction.
+			idle bool
+		}
+	}
+
+	// replication is the state of the replication stream started by the last
+	// START_REPLICATION command. It is only accessed by the goroutine reading
+	// from the network connection.
+	replication struct {
+		// feedback receives the CopyData messages sent by the client while the
+		// stream is active, and is closed when the client ends the stream. It
+		// is nil if no stream is active.
+		feedback chan []byte
+		// done is closed when the execution of the stream finishes.
+		done <-chan struct{}
+	}
+
 	// vecsScratch is a scratch space used by bufferBatch.
 	vecsScratch coldata.TypedVecs
 
@@ -231,6 +267,13 @@ func (c *conn) processCommands(
 	ac.AuthOK(ctx)
 	ac.LogAuthOK(ctx)
 
+	if listener := connHandler.NotificationListener(); listener != nil {
+		c.notifications.listener = listener
+		done := make(chan struct{})
+		defer close(done)
+		go c.deliverNotifications(done)
+	}
+
 	// We count the connection establish latency until we are ready to
 	// serve a SQL query. It includes the time it takes to authenticate and
 	// send the initial ReadyForQuery message.
@@ -264,6 +307,84 @@ func (c *conn) bufferNotice(ctx context.Context, noticeErr pgnotice.Notice) erro
 	return c.writeErrFields(ctx, noticeErr, &c.writerState.buf)
 }
 
+// bufferNotifications buffers the pending notifications. It is called right
+// before ReadyForQuery is sent outside of a transaction.
+func (c *conn) bufferNotifications() {
+	if c.notifications.listener == nil {
+		return
+	}
+	for _, n := range c.notifications.listener.TakePending() {
+		writeNotification(&c.msgBuilder, n)
+		if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+			panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err from buffer"))
+		}
+	}
+}
+
+// setIdle records whether the session waits for a command from the client
+// outside of a transaction. When it becomes idle, the notifications received
+// since the last ReadyForQuery message are sent.
+func (c *conn) setIdle(idle bool) {
+	c.notifications.mu.Lock()
+	c.notifications.mu.idle = idle
+	c.notifications.mu.Unlock()
+	if idle {
+		_ /* err */ = c.sendNotificationsIfIdle()
+	}
+}
+
+// sendNotificationsIfIdle sends the pending notifications to the client if the
+// session is idle. If it is not, they are sent with the next ReadyForQuery
+// message.
+func (c *conn) sendNotificationsIfIdle() error {
+	c.notifications.mu.Lock()
+	defer c.notifications.mu.Unlock()
+	if !c.notifications.mu.idle || c.notifications.listener == nil {
+		return nil
+	}
+	if err := c.GetErr(); err != nil {
+		return err
+	}
+	pending := c.notifications.listener.TakePending()
+	if len(pending) == 0 {
+		return nil
+	}
+	for _, n := range pending {
+		writeNotification(&c.notifications.msgBuilder, n)
+		if err := c.notifications.msgBuilder.finishMsg(&c.notifications.buf); err != nil {
+			return err
+		}
+	}
+	if _ /* n */, err := c.notifications.buf.WriteTo(c.conn); err != nil {
+		c.setErr(err)
+		return err
+	}
+	return nil
+}
+
+// deliverNotifications sends the notifications received while the session is
+// idle to the client, until done is closed.
+func (c *conn) deliverNotifications(done <-chan struct{}) {
+	for {
+		select {
+		case <-c.notifications.listener.Signal():
+			if err := c.sendNotificationsIfIdle(); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// writeNotification writes a NotificationResponse message for n to b.
+func writeNotification(b *writeBuffer, n notify.Notification) {
+	b.initMsg(pgwirebase.ServerMsgNotificationResponse)
+	b.putInt32(n.SenderPID)
+	b.writeTerminatedString(n.Channel)
+	b.writeTerminatedString(n.Payload)
+}
+
 func (c *conn) sendInitialConnData(
 	ctx context.Context,
 	sqlServer *sql.Server,
@@ -333,6 +454,43 @@ func (c *conn) bufferInitialReadyForQuery(queryCancelKey pgwirecancel.BackendKey
 	return nil
 }
 
+// replicationFeedbackBufferSize is the number of CopyData messages sent by the
+// client of a replication stream that can be buffered before the reading of
+// the connection blocks.
+const replicationFeedbackBufferSize = 16
+
+// handleReplicationFeedback forwards a copy message sent by the client of a
+// replication stream to the execution of the stream. A CopyDone or CopyFail
+// message ends the stream.
+func (c *conn) handleReplicationFeedback(
+	ctx context.Context, typ pgwirebase.ClientMessageType,
+) error {
+	feedback := c.replication.feedback
+	select {
+	case <-c.replication.done:
+		// The stream ended on the server side, so the copy messages are
+		// ignored.
+		c.replication.feedback = nil
+		return nil
+	default:
+	}
+	if typ != pgwirebase.ClientMsgCopyData {
+		close(feedback)
+		c.replication.feedback = nil
+		return nil
+	}
+	// The read buffer is reused for the next message, so the data is copied.
+	data := append([]byte(nil), c.readBuf.Msg...)
+	select {
+	case feedback <- data:
+	case <-c.replication.done:
+		c.replication.feedback = nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
 // An error is returned iff the statement buffer has been closed. In that case,
 // the connection should be considered toast.
 func (c *conn) handleSimpleQuery(
@@ -354,8 +512,26 @@ func (c *conn) handleSimpleQuery(
 			log.SqlExec.Infof(ctx, "could not parse simple query in replication protocol: %s", query)
 			return c.stmtBuf.Push(ctx, sql.SendError{Err: err})
 		}
-		switch stmt.AST.(type) {
-		case *pgrepltree.IdentifySystem:
+		switch n := stmt.AST.(type) {
+		case *pgrepltree.IdentifySystem, *pgrepltree.CreateReplicationSlot,
+			*pgrepltree.DropReplicationSlot:
+		case *pgrepltree.StartReplication:
+			feedback := make(chan []byte, replicationFeedbackBufferSize)
+			done := make(chan struct{})
+			c.replication.feedback = feedback
+			c.replication.done = done
+			return c.stmtBuf.Push(
+				ctx,
+				sql.StartReplication{
+					ParsedStmt:   stmt,
+					Stmt:         n,
+					Feedback:     feedback,
+					Done:         done,
+					TimeReceived: timeReceived,
+					ParseStart:   startParse,
+					ParseEnd:     timeutil.Now(),
+				},
+			)
 		default:
 			log.SqlExec.Infof(ctx, "unhandled replication protocol query: %s", query)
 			return c.stmtBuf.Push(ctx, sql.SendError{
@@ -862,7 +1038,7 @@ func cookTag(
 			tag = strconv.AppendUint(tag, uint64(rowsAffected), 10)
 		}
 
-	case tree.Ack, tree.DDL:
+	case tree.Ack, tree.DDL, tree.Replication:
 		if tagStr == "SELECT" {
 			tag = append(tag, ' ')
 			tag = strconv.AppendInt(tag, int64(rowsAffected), 10)
@@ -1175,6 +1351,16 @@ func (c *conn) bufferCopyOut(columns []colinfo.ResultColumn, format pgwirebase.F
 	return c.msgBuilder.finishMsg(&c.writerState.buf)
 }
 
+// bufferCopyBoth buffers a CopyBothResponse message, which starts the
+// streaming of a replication command. The data of the stream is not split in
+// columns.
+func (c *conn) bufferCopyBoth() error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyBothResponse)
+	c.msgBuilder.writeByte(byte(pgwirebase.FormatText))
+	c.msgBuilder.putInt16(0)
+	return c.msgBuilder.finishMsg(&c.writerState.buf)
+}
+
 func (c *conn) bufferCopyData(copyData []byte, res *commandResult) error {
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyDataCommand)
 	if _, err := c.msgBuilder.Write(copyData); err != nil {
@@ -1249,6 +1435,8 @@ func (c *conn) Flush(pos sql.CmdPos) error {
 	// Make sure that the entire cmdStarts buffer is drained.
 	c.writerState.fi.cmdStarts.Discard()
 
+	c.notifications.mu.Lock()
+	defer c.notifications.mu.Unlock()
 	_ /* n */, err := c.writerState.buf.WriteTo(c.conn)
 	if err != nil {
 		c.setErr(err)
@@ -1418,6 +1606,16 @@ func (c *conn) CreateCopyOutResult(cmd sql.CopyOut, pos sql.CmdPos) sql.CopyOutR
 	return res
 }
 
+// CreateStartReplicationResult is part of the sql.ClientComm interface.
+func (c *conn) CreateStartReplicationResult(
+	cmd sql.StartReplication, pos sql.CmdPos,
+) sql.StartReplicationResult {
+	res := c.newMiscResult(pos, commandComplete)
+	res.stmtType = cmd.Stmt.StatementReturnType()
+	res.cmdCompleteTag = cmd.Stmt.StatementTag()
+	return res
+}
+
 // pgwireReader is an io.Reader that wraps a conn, maintaining its metrics as
 // it is consumed.
 type pgwireReader struct {
@@ -0,0 +1,108 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListenNotify checks that notifications sent on one node are delivered
+// asynchronously to the sessions listening on another node, once the
+// notifying transaction commits.
+func TestListenNotify(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := serverutils.StartCluster(t, 2, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+
+	pgURL, cleanup := sqlutils.PGUrl(
+		t, tc.Server(0).AdvSQLAddr(), "TestListenNotify", url.User(username.RootUser),
+	)
+	defer cleanup()
+	listener, err := pgx.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+	defer func() { _ = listener.Close(ctx) }()
+
+	// Use a single connection to send notifications, so that they all have the
+	// same sender PID.
+	notifierConn, err := tc.ServerConn(1).Conn(ctx)
+	require.NoError(t, err)
+	defer func() { _ = notifierConn.Close() }()
+	notifier := sqlutils.MakeSQLRunner(notifierConn)
+	var notifierPID uint32
+	notifier.QueryRow(t, "SELECT pg_backend_pid()").Scan(&notifierPID)
+
+	waitForNotification := func(timeout time.Duration) (*pgconn.Notification, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return listener.WaitForNotification(ctx)
+	}
+	expectNotification := func(channel, payload string) {
+		t.Helper()
+		n, err := waitForNotification(45 * time.Second)
+		require.NoError(t, err)
+		require.Equal(t, channel, n.Channel)
+		require.Equal(t, payload, n.Payload)
+		require.Equal(t, notifierPID, n.PID)
+	}
+
+	_, err = listener.Exec(ctx, "LISTEN foo")
+	require.NoError(t, err)
+
+	notifier.Exec(t, "NOTIFY foo, 'hello'")
+	expectNotification("foo", "hello")
+
+	// Notifications sent by a transaction are only delivered if it commits.
+	notifier.Exec(t, "BEGIN")
+	notifier.Exec(t, "NOTIFY foo, 'rolled back'")
+	notifier.Exec(t, "ROLLBACK")
+	notifier.Exec(t, "BEGIN")
+	notifier.Exec(t, "SELECT pg_notify('foo', 'committed')")
+	notifier.Exec(t, "COMMIT")
+	expectNotification("foo", "committed")
+
+	// Notifications sent on other channels are not delivered.
+	notifier.Exec(t, "NOTIFY bar, 'ignored'")
+	notifier.Exec(t, "NOTIFY foo")
+	expectNotification("foo", "")
+
+	// Notifications received while the listening session is in a transaction
+	// are delivered after the transaction ends.
+	_, err = listener.Exec(ctx, "BEGIN")
+	require.NoError(t, err)
+	notifier.Exec(t, "NOTIFY foo, 'during txn'")
+	_, err = listener.Exec(ctx, "SELECT 1")
+	require.NoError(t, err)
+	_, err = listener.Exec(ctx, "COMMIT")
+	require.NoError(t, err)
+	expectNotification("foo", "during txn")
+
+	_, err = listener.Exec(ctx, "UNLISTEN foo")
+	require.NoError(t, err)
+	notifier.Exec(t, "NOTIFY foo, 'not listening'")
+	_, err = waitForNotification(time.Second)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
 	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
 	"github.com/dustin/go-humanize"
@@ -468,6 +469,16 @@ func DecodeDatum(
 				return nil, err
 			}
 			return &tree.DTSVector{TSVector: ret}, nil
+		case oidext.T_jsonpath:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDJsonpath(bs)
+		case oidext.T_pgvector:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDPGVector(bs)
 		}
 		if typ.Family() == types.ArrayFamily {
 			// Arrays come in in their string form, so we parse them as such and later
@@ -768,6 +779,38 @@ func DecodeDatum(
 				return nil, err
 			}
 			return tree.NewDTSVector(ret), nil
+		case oidext.T_jsonpath:
+			if len(b) < 1 {
+				return nil, NewProtocolViolationErrorf("no data to decode")
+			}
+			if b[0] != 1 {
+				return nil, NewProtocolViolationErrorf("expected JSONPATH version 1")
+			}
+			// Skip over the version number.
+			b = b[1:]
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDJsonpath(encoding.UnsafeConvertBytesToString(b))
+		case oidext.T_pgvector:
+			if len(b) < 4 {
+				return nil, pgerror.Newf(pgcode.Syntax, "vector requires at least 4 bytes for binary format")
+			}
+			dims := int(binary.BigEndian.Uint16(b))
+			b = b[4:]
+			if len(b) != 4*dims {
+				return nil, pgerror.Newf(pgcode.Syntax,
+					"expected %d bytes for vector with %d dimensions, found %d", 4*dims, dims, len(b))
+			}
+			fs := make([]float64, dims)
+			for i := range fs {
+				fs[i] = float64(math.Float32frombits(binary.BigEndian.Uint32(b[4*i:])))
+			}
+			v, err := vector.FromFloats(fs)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDPGVector(v), nil
 		case oidext.T_geometry:
 			ret, err := geo.ParseGeometryFromEWKB(b)
 			if err != nil {
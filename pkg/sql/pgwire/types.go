@@ -246,6 +246,12 @@ func writeTextDatumNotNull(
 	case *tree.DJSON:
 		b.writeLengthPrefixedString(v.JSON.String())
 
+	case *tree.DJsonpath:
+		b.writeLengthPrefixedString(v.Jsonpath.String())
+
+	case *tree.DPGVector:
+		b.writeLengthPrefixedString(v.T.String())
+
 	case *tree.DTSQuery:
 		b.textFormatter.FormatNode(v)
 		b.writeFromFmtCtx(b.textFormatter)
@@ -809,6 +815,23 @@ func writeBinaryDatumNotNull(
 	case *tree.DJSON:
 		writeBinaryJSON(b, v.JSON, t)
 
+	case *tree.DJsonpath:
+		s := v.Jsonpath.String()
+		b.putInt32(int32(len(s) + 1))
+		// Postgres version number, as of writing, `1` is the only valid value.
+		b.writeByte(1)
+		b.writeString(s)
+
+	case *tree.DPGVector:
+		// The binary format of pgvector is the number of dimensions and an
+		// unused field, both 16 bits, followed by the elements.
+		b.putInt32(int32(4 + 4*len(v.T)))
+		b.putInt16(int16(len(v.T)))
+		b.putInt16(0)
+		for _, f := range v.T {
+			b.putInt32(int32(math.Float32bits(f)))
+		}
+
 	case *tree.DOid:
 		b.putInt32(4)
 		b.putInt32(int32(v.Oid))
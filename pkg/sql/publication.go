@@ -0,0 +1,284 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// publication is a row of system.publications.
+type publication struct {
+	name      string
+	owner     username.SQLUsername
+	allTables bool
+	// tableIDs is empty for FOR ALL TABLES publications.
+	tableIDs []descpb.ID
+}
+
+// getPublications returns the publications of the given database, ordered by
+// name. If names is not empty, only the publications with these names are
+// returned.
+func getPublications(
+	ctx context.Context, txn isql.Txn, dbID descpb.ID, names ...string,
+) ([]publication, error) {
+	query := `SELECT name, owner, all_tables, table_ids FROM system.publications WHERE database_id = $1`
+	args := []interface{}{dbID}
+	if len(names) > 0 {
+		arr := tree.NewDArray(types.String)
+		for _, name := range names {
+			if err := arr.Append(tree.NewDString(name)); err != nil {
+				return nil, err
+			}
+		}
+		query += ` AND name = ANY $2`
+		args = append(args, arr)
+	}
+	rows, err := txn.QueryBufferedEx(
+		ctx, "get-publications", txn.KV(), sessiondata.NodeUserSessionDataOverride,
+		query+` ORDER BY name`, args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	pubs := make([]publication, len(rows))
+	for i, row := range rows {
+		pubs[i] = publication{
+			name:      string(tree.MustBeDString(row[0])),
+			owner:     username.MakeSQLUsernameFromPreNormalizedString(string(tree.MustBeDString(row[1]))),
+			allTables: bool(tree.MustBeDBool(row[2])),
+		}
+		for _, id := range tree.MustBeDArray(row[3]).Array {
+			pubs[i].tableIDs = append(pubs[i].tableIDs, descpb.ID(tree.MustBeDInt(id)))
+		}
+	}
+	return pubs, nil
+}
+
+// includes returns whether changes to the given table are published.
+func (pub *publication) includes(desc catalog.TableDescriptor) bool {
+	if pub.allTables {
+		return isPublishableTable(desc)
+	}
+	for _, id := range pub.tableIDs {
+		if id == desc.GetID() {
+			return true
+		}
+	}
+	return false
+}
+
+// isPublishableTable returns whether the given table can be part of a
+// publication.
+func isPublishableTable(desc catalog.TableDescriptor) bool {
+	return desc.IsTable() && !desc.IsVirtualTable() && !desc.IsTemporary()
+}
+
+// forEachPublishedTable calls fn for every table of every publication of the
+// databases visible through dbContext. If includeAllTables is false,
+// publications created FOR ALL TABLES are skipped.
+func forEachPublishedTable(
+	ctx context.Context,
+	p *planner,
+	dbContext catalog.DatabaseDescriptor,
+	includeAllTables bool,
+	fn func(catalog.DatabaseDescriptor, *publication, catalog.SchemaDescriptor, catalog.TableDescriptor) error,
+) error {
+	return forEachDatabaseDesc(ctx, p, dbContext, false, /* requiresPrivileges */
+		func(db catalog.DatabaseDescriptor) error {
+			pubs, err := getPublications(ctx, p.InternalSQLTxn(), db.GetID())
+			if err != nil || len(pubs) == 0 {
+				return err
+			}
+			return forEachTableDesc(ctx, p, db, hideVirtual,
+				func(db catalog.DatabaseDescriptor, sc catalog.SchemaDescriptor, table catalog.TableDescriptor) error {
+					for i := range pubs {
+						pub := &pubs[i]
+						if pub.allTables && !includeAllTables || !pub.includes(table) {
+							continue
+						}
+						if err := fn(db, pub, sc, table); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+		})
+}
+
+func (p *planner) checkLogicalReplicationSupported(ctx context.Context, op string) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V23_2_LogicalReplication) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is not supported until the cluster upgrade is finalized", op)
+	}
+	return nil
+}
+
+type createPublicationNode struct {
+	n *tree.CreatePublication
+}
+
+// CreatePublication implements the CREATE PUBLICATION statement.
+func (p *planner) CreatePublication(
+	ctx context.Context, n *tree.CreatePublication,
+) (planNode, error) {
+	if err := p.checkLogicalReplicationSupported(ctx, "CREATE PUBLICATION"); err != nil {
+		return nil, err
+	}
+	return &createPublicationNode{n: n}, nil
+}
+
+func (n *createPublicationNode) startExec(params runParams) error {
+	ctx, p := params.ctx, params.p
+	dbDesc, err := p.Descriptors().ByNameWithLeased(p.txn).Get().Database(ctx, p.CurrentDatabase())
+	if err != nil {
+		return err
+	}
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return err
+	}
+	if n.n.AllTables {
+		isAdmin, err := p.UserHasAdminRole(ctx, p.User())
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return pgerror.New(pgcode.InsufficientPrivilege,
+				"only users with the admin role are allowed to create a FOR ALL TABLES publication")
+		}
+	}
+
+	tableIDs := tree.NewDArray(types.Int)
+	seen := make(map[descpb.ID]struct{})
+	for i := range n.n.Tables {
+		tn := &n.n.Tables[i]
+		_, tableDesc, err := resolver.ResolveExistingTableObject(ctx, p, tn, tree.ObjectLookupFlags{
+			Required:             true,
+			DesiredObjectKind:    tree.TableObject,
+			DesiredTableDescKind: tree.ResolveRequireTableDesc,
+		})
+		if err != nil {
+			return err
+		}
+		if tableDesc.GetParentID() != dbDesc.GetID() {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot add relation %q to a publication of database %q",
+				tn.FQString(), dbDesc.GetName())
+		}
+		if !isPublishableTable(tableDesc) {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot add relation %q to publication", tn.FQString())
+		}
+		hasOwnership, err := p.HasOwnership(ctx, tableDesc)
+		if err != nil {
+			return err
+		}
+		if !hasOwnership {
+			return pgerror.Newf(pgcode.InsufficientPrivilege,
+				"must be owner of table %s", tn.FQString())
+		}
+		if _, ok := seen[tableDesc.GetID()]; ok {
+			continue
+		}
+		seen[tableDesc.GetID()] = struct{}{}
+		if err := tableIDs.Append(tree.NewDInt(tree.DInt(tableDesc.GetID()))); err != nil {
+			return err
+		}
+	}
+
+	txn := p.InternalSQLTxn()
+	existing, err := getPublications(ctx, txn, dbDesc.GetID(), string(n.n.Name))
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return pgerror.Newf(pgcode.DuplicateObject, "publication %q already exists", n.n.Name)
+	}
+	if _, err := txn.ExecEx(
+		ctx, "create-publication", txn.KV(), sessiondata.NodeUserSessionDataOverride,
+		`INSERT INTO system.publications (database_id, name, owner, owner_id, all_tables, table_ids)
+VALUES ($1, $2, $3, (SELECT user_id FROM system.users WHERE username = $3), $4, $5)`,
+		dbDesc.GetID(), string(n.n.Name), p.User().Normalized(), n.n.AllTables, tableIDs,
+	); err != nil {
+		return errors.Wrap(err, "failed to create publication")
+	}
+	return nil
+}
+
+func (n *createPublicationNode) Next(_ runParams) (bool, error) { return false, nil }
+func (n *createPublicationNode) Values() tree.Datums            { return nil }
+func (n *createPublicationNode) Close(_ context.Context)        {}
+
+type dropPublicationNode struct {
+	n *tree.DropPublication
+}
+
+// DropPublication implements the DROP PUBLICATION statement.
+func (p *planner) DropPublication(ctx context.Context, n *tree.DropPublication) (planNode, error) {
+	if err := p.checkLogicalReplicationSupported(ctx, "DROP PUBLICATION"); err != nil {
+		return nil, err
+	}
+	return &dropPublicationNode{n: n}, nil
+}
+
+func (n *dropPublicationNode) startExec(params runParams) error {
+	ctx, p := params.ctx, params.p
+	dbDesc, err := p.Descriptors().ByNameWithLeased(p.txn).Get().Database(ctx, p.CurrentDatabase())
+	if err != nil {
+		return err
+	}
+	isAdmin, err := p.UserHasAdminRole(ctx, p.User())
+	if err != nil {
+		return err
+	}
+	txn := p.InternalSQLTxn()
+	for _, name := range n.n.Names {
+		pubs, err := getPublications(ctx, txn, dbDesc.GetID(), string(name))
+		if err != nil {
+			return err
+		}
+		if len(pubs) == 0 {
+			if n.n.IfExists {
+				continue
+			}
+			return pgerror.Newf(pgcode.UndefinedObject, "publication %q does not exist", name)
+		}
+		if !isAdmin && pubs[0].owner != p.User() {
+			return pgerror.Newf(pgcode.InsufficientPrivilege,
+				"must be owner of publication %s", name)
+		}
+		if _, err := txn.ExecEx(
+			ctx, "drop-publication", txn.KV(), sessiondata.NodeUserSessionDataOverride,
+			`DELETE FROM system.publications WHERE database_id = $1 AND name = $2`,
+			dbDesc.GetID(), string(name),
+		); err != nil {
+			return errors.Wrap(err, "failed to drop publication")
+		}
+	}
+	return nil
+}
+
+func (n *dropPublicationNode) Next(_ runParams) (bool, error) { return false, nil }
+func (n *dropPublicationNode) Values() tree.Datums            { return nil }
+func (n *dropPublicationNode) Close(_ context.Context)        {}
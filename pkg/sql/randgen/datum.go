@@ -29,12 +29,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
 	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -287,6 +289,14 @@ func RandDatumWithNullChance(
 		return tree.NewDTSVector(tsearch.RandomTSVector(rng))
 	case types.TSQueryFamily:
 		return tree.NewDTSQuery(tsearch.RandomTSQuery(rng))
+	case types.JsonpathFamily:
+		return tree.NewDJsonpath(jsonpath.Random(rng))
+	case types.PGVectorFamily:
+		dims := int(typ.Width())
+		if dims == 0 {
+			dims = 1 + rng.Intn(10)
+		}
+		return tree.NewDPGVector(vector.Random(rng, dims))
 	default:
 		panic(errors.AssertionFailedf("invalid type %v", typ.DebugString()))
 	}
@@ -479,6 +489,18 @@ func RandDatumSimple(rng *rand.Rand, typ *types.T) tree.Datum {
 		datum = tree.NewDTSQuery(tsearch.RandomTSQuery(rng))
 	case types.TSVectorFamily:
 		datum = tree.NewDTSVector(tsearch.RandomTSVector(rng))
+	case types.JsonpathFamily:
+		datum = tree.NewDJsonpath(jsonpath.Random(rng))
+	case types.PGVectorFamily:
+		dims := int(typ.Width())
+		if dims == 0 {
+			dims = 3
+		}
+		v := make(vector.T, dims)
+		for i := range v {
+			v[i] = float32(rng.Intn(10))
+		}
+		datum = tree.NewDPGVector(v)
 	}
 	return datum
 }
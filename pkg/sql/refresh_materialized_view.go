@@ -91,6 +91,15 @@ func (n *refreshMaterializedViewNode) startExec(params runParams) error {
 		)
 	}
 
+	// An incremental refresh writes the changes of the view into its existing
+	// set of indexes instead, unless the view has to be refreshed fully.
+	if n.n.Incrementally {
+		telemetry.Inc(sqltelemetry.SchemaRefreshMaterializedViewIncrementally)
+		if ok, err := n.refreshIncrementally(params); err != nil || ok {
+			return err
+		}
+	}
+
 	// Prepare the new set of indexes by cloning all existing indexes on the view.
 	newPrimaryIndex := n.desc.GetPrimaryIndex().IndexDescDeepCopy()
 	newIndexes := make([]descpb.IndexDescriptor, len(n.desc.PublicNonPrimaryIndexes()))
@@ -0,0 +1,925 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinsregistry"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+var incrementalRefreshMaxChangedRows = settings.RegisterIntSetting(
+	settings.TenantWritable,
+	"sql.materialized_view.incremental_refresh.max_changed_rows",
+	"maximum number of rows of the tables a materialized view depends on that "+
+		"may have changed for REFRESH MATERIALIZED VIEW ... INCREMENTALLY to "+
+		"refresh the view incrementally; views with more changes are refreshed fully",
+	10000,
+	settings.PositiveInt,
+	settings.WithPublic,
+)
+
+// incrementalRefreshOpName is the name of the internal statements run by
+// incremental refreshes.
+const incrementalRefreshOpName = "refresh-materialized-view-incrementally"
+
+// incrementalRefreshMaxPlaceholders bounds the number of placeholders of the
+// statements that write to the view.
+const incrementalRefreshMaxPlaceholders = 10000
+
+// incrementalRefreshGroupBatchSize is the number of groups of an aggregate
+// view recomputed by each query.
+const incrementalRefreshGroupBatchSize = 1000
+
+// errFullRefreshRequired marks the errors returned when a view can't be
+// refreshed incrementally this time, and has to be refreshed fully instead.
+var errFullRefreshRequired = errors.New("full refresh required")
+
+func fullRefreshRequiredf(format string, args ...interface{}) error {
+	return errors.Mark(errors.Newf(format, args...), errFullRefreshRequired)
+}
+
+// incrementalViewQuery is the analyzed query of a materialized view which
+// can be refreshed incrementally. Such queries are select-project-join
+// queries over tables, with inner joins only, which may group and aggregate
+// the joined rows.
+//
+// The view is refreshed with the changes made since the last refresh to the
+// rows of the tables, which are identified by their primary keys. A row of a
+// select-project-join query is affected by the changes if any of the rows
+// it's made of changed. The affected rows are computed as of the last
+// refresh, and as of now; the former are removed from the view, and the
+// latter added to it. For aggregations, the groups of the affected rows are
+// recomputed as a whole instead.
+type incrementalViewQuery struct {
+	query   string
+	sources []incrementalViewSource
+	// aggregate is set if the query groups its rows.
+	aggregate bool
+	// groupExprs are the GROUP BY expressions of the query, and groupCols the
+	// ordinals of the view columns that hold them.
+	groupExprs tree.Exprs
+	groupCols  []int
+}
+
+// incrementalViewSource is a table referenced by the FROM clause of a view
+// query.
+type incrementalViewSource struct {
+	table catalog.TableDescriptor
+	// keyCols are the references to the primary key columns of the table in
+	// the view query.
+	keyCols tree.Exprs
+}
+
+// parseIncrementalViewQuery parses a view query and returns its SELECT
+// clause, if it's eligible for incremental refresh.
+func parseIncrementalViewQuery(query string) (*tree.Select, *tree.SelectClause, error) {
+	stmt, err := parser.ParseOne(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return nil, nil, errors.AssertionFailedf("unexpected view query %s", query)
+	}
+	for {
+		if sel.With != nil || sel.Limit != nil || len(sel.Locking) > 0 {
+			return nil, nil, unimplementedIncrementalRefreshf("WITH, LIMIT and locking clauses")
+		}
+		paren, ok := sel.Select.(*tree.ParenSelect)
+		if !ok {
+			break
+		}
+		sel = paren.Select
+	}
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok {
+		return nil, nil, unimplementedIncrementalRefreshf("set operations and VALUES")
+	}
+	if clause.Distinct || clause.DistinctOn != nil {
+		return nil, nil, unimplementedIncrementalRefreshf("DISTINCT")
+	}
+	if clause.Window != nil {
+		return nil, nil, unimplementedIncrementalRefreshf("window functions")
+	}
+	if clause.TableSelect || clause.From.AsOf.Expr != nil {
+		return nil, nil, errors.AssertionFailedf("unexpected view query %s", query)
+	}
+	return sel, clause, nil
+}
+
+func unimplementedIncrementalRefreshf(what string) error {
+	return pgerror.Newf(pgcode.FeatureNotSupported,
+		"materialized views using %s cannot be refreshed incrementally", what)
+}
+
+// analyzeIncrementalViewQuery checks that the query of a materialized view is
+// eligible for incremental refresh, and analyzes it.
+func (p *planner) analyzeIncrementalViewQuery(
+	ctx context.Context, view catalog.TableDescriptor,
+) (*incrementalViewQuery, error) {
+	q := &incrementalViewQuery{query: view.GetViewQuery()}
+	_, clause, err := parseIncrementalViewQuery(q.query)
+	if err != nil {
+		return nil, err
+	}
+
+	var v incrementalViewQueryVisitor
+	var walkTableExpr func(tree.TableExpr) error
+	walkTableExpr = func(expr tree.TableExpr) error {
+		switch t := expr.(type) {
+		case *tree.AliasedTableExpr:
+			tn, ok := t.Expr.(*tree.TableName)
+			if !ok || t.Ordinality || t.Lateral || len(t.As.Cols) > 0 {
+				return unimplementedIncrementalRefreshf("subqueries, table functions and column aliases in FROM")
+			}
+			src, err := p.makeIncrementalViewSource(ctx, tn, t.As.Alias)
+			if err != nil {
+				return err
+			}
+			q.sources = append(q.sources, src)
+			return nil
+		case *tree.ParenTableExpr:
+			return walkTableExpr(t.Expr)
+		case *tree.JoinTableExpr:
+			switch t.JoinType {
+			case "", tree.AstInner, tree.AstCross:
+			default:
+				return unimplementedIncrementalRefreshf("outer joins")
+			}
+			if on, ok := t.Cond.(*tree.OnJoinCond); ok {
+				if err := v.walk(on.Expr); err != nil {
+					return err
+				}
+			}
+			if err := walkTableExpr(t.Left); err != nil {
+				return err
+			}
+			return walkTableExpr(t.Right)
+		default:
+			return unimplementedIncrementalRefreshf("subqueries, table functions and column aliases in FROM")
+		}
+	}
+	for _, expr := range clause.From.Tables {
+		if err := walkTableExpr(expr); err != nil {
+			return nil, err
+		}
+	}
+	if len(q.sources) == 0 {
+		return nil, unimplementedIncrementalRefreshf("no tables")
+	}
+
+	for _, expr := range clause.Exprs {
+		if err := v.walk(expr.Expr); err != nil {
+			return nil, err
+		}
+	}
+	if clause.Where != nil {
+		if err := v.walk(clause.Where.Expr); err != nil {
+			return nil, err
+		}
+	}
+	for _, expr := range clause.GroupBy {
+		if err := v.walk(expr); err != nil {
+			return nil, err
+		}
+	}
+	if clause.Having != nil {
+		if err := v.walk(clause.Having.Expr); err != nil {
+			return nil, err
+		}
+	}
+
+	q.aggregate = v.hasAggregate || len(clause.GroupBy) > 0 || clause.Having != nil
+	for _, groupExpr := range clause.GroupBy {
+		expr := groupExpr
+		// GROUP BY may refer to the columns of the query by ordinal or name.
+		switch t := groupExpr.(type) {
+		case *tree.NumVal:
+			ord, err := t.AsInt64()
+			if err == nil && ord >= 1 && int(ord) <= len(clause.Exprs) {
+				expr = clause.Exprs[ord-1].Expr
+			}
+		case *tree.UnresolvedName:
+			if t.NumParts == 1 {
+				for _, selectExpr := range clause.Exprs {
+					if string(selectExpr.As) == t.Parts[0] {
+						expr = selectExpr.Expr
+					}
+				}
+			}
+		}
+		col := -1
+		for i, selectExpr := range clause.Exprs {
+			if tree.AsString(selectExpr.Expr) == tree.AsString(expr) {
+				col = i
+				break
+			}
+		}
+		if col == -1 {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"materialized views must select their GROUP BY expressions to be "+
+					"refreshed incrementally, but %s is not selected", tree.AsString(groupExpr))
+		}
+		q.groupExprs = append(q.groupExprs, expr)
+		q.groupCols = append(q.groupCols, col)
+	}
+	return q, nil
+}
+
+// makeIncrementalViewSource resolves a table referenced by a view query.
+func (p *planner) makeIncrementalViewSource(
+	ctx context.Context, tn *tree.TableName, alias tree.Name,
+) (incrementalViewSource, error) {
+	// Resolve a copy of the name, since resolution qualifies it in place.
+	resolvedName := *tn
+	_, table, err := resolver.ResolveExistingTableObject(ctx, p, &resolvedName, tree.ObjectLookupFlags{
+		Required:             true,
+		DesiredObjectKind:    tree.TableObject,
+		DesiredTableDescKind: tree.ResolveRequireTableDesc,
+	})
+	if err != nil {
+		return incrementalViewSource{}, err
+	}
+	if table.IsVirtualTable() {
+		return incrementalViewSource{}, unimplementedIncrementalRefreshf("virtual tables")
+	}
+	// Refer to the columns of the table the way the query refers to the
+	// table.
+	prefix := tree.NameParts{string(alias)}
+	if alias == "" {
+		prefix = tree.NameParts{tn.Table()}
+		if tn.ExplicitSchema {
+			prefix[1] = tn.Schema()
+			if tn.ExplicitCatalog {
+				prefix[2] = tn.Catalog()
+			}
+		}
+	}
+	numParts := 2
+	for numParts < 4 && prefix[numParts-1] != "" {
+		numParts++
+	}
+	src := incrementalViewSource{table: table}
+	idx := table.GetPrimaryIndex()
+	for i := 0; i < idx.NumKeyColumns(); i++ {
+		name := &tree.UnresolvedName{NumParts: numParts}
+		name.Parts[0] = idx.GetKeyColumnName(i)
+		copy(name.Parts[1:], prefix[:numParts-1])
+		src.keyCols = append(src.keyCols, name)
+	}
+	return src, nil
+}
+
+// incrementalViewQueryVisitor checks that the expressions of a view query
+// can be evaluated on the changed rows only.
+type incrementalViewQueryVisitor struct {
+	hasAggregate bool
+	err          error
+}
+
+var _ tree.Visitor = &incrementalViewQueryVisitor{}
+
+func (v *incrementalViewQueryVisitor) walk(expr tree.Expr) error {
+	tree.WalkExprConst(v, expr)
+	return v.err
+}
+
+// VisitPre implements the tree.Visitor interface.
+func (v *incrementalViewQueryVisitor) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.err != nil {
+		return false, expr
+	}
+	switch t := expr.(type) {
+	case *tree.Subquery:
+		v.err = unimplementedIncrementalRefreshf("subqueries")
+	case *tree.FuncExpr:
+		if t.WindowDef != nil {
+			v.err = unimplementedIncrementalRefreshf("window functions")
+			break
+		}
+		name, ok := t.Func.FunctionReference.(*tree.UnresolvedName)
+		if !ok {
+			v.err = unimplementedIncrementalRefreshf("user-defined functions")
+			break
+		}
+		fnName := name.Parts[0]
+		if name.NumParts > 1 && name.Parts[1] != "pg_catalog" {
+			fnName = name.Parts[1] + "." + fnName
+		}
+		props, overloads := builtinsregistry.GetBuiltinProperties(fnName)
+		if props == nil {
+			v.err = unimplementedIncrementalRefreshf("user-defined functions")
+			break
+		}
+		for i := range overloads {
+			switch {
+			case overloads[i].Class == tree.AggregateClass:
+				v.hasAggregate = true
+			case overloads[i].Class != tree.NormalClass:
+				v.err = unimplementedIncrementalRefreshf("set-returning functions")
+			case overloads[i].Volatility > volatility.Immutable:
+				v.err = pgerror.Newf(pgcode.FeatureNotSupported,
+					"materialized views using %s functions such as %s() cannot be "+
+						"refreshed incrementally", overloads[i].Volatility, fnName)
+			}
+			if v.err != nil {
+				break
+			}
+		}
+	}
+	return v.err == nil, expr
+}
+
+// VisitPost implements the tree.Visitor interface.
+func (v *incrementalViewQueryVisitor) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+// makeQuery returns the view query, evaluated as of the given timestamp and
+// restricted to the rows that pass filter, if it's not nil. If groupsOnly is
+// set, the query returns the distinct values of the GROUP BY expressions
+// instead.
+func (q *incrementalViewQuery) makeQuery(
+	asOf hlc.Timestamp, filter tree.Expr, groupsOnly bool,
+) (string, error) {
+	sel, clause, err := parseIncrementalViewQuery(q.query)
+	if err != nil {
+		return "", err
+	}
+	sel.OrderBy = nil
+	clause.From.AsOf = tree.AsOfClause{Expr: tree.NewStrVal(asOf.AsOfSystemTime())}
+	if filter != nil {
+		if clause.Where == nil {
+			clause.Where = tree.NewWhere(tree.AstWhere, filter)
+		} else {
+			clause.Where.Expr = &tree.AndExpr{
+				Left:  &tree.ParenExpr{Expr: clause.Where.Expr},
+				Right: &tree.ParenExpr{Expr: filter},
+			}
+		}
+	}
+	if groupsOnly {
+		clause.Exprs = make(tree.SelectExprs, len(q.groupExprs))
+		for i, expr := range q.groupExprs {
+			clause.Exprs[i] = tree.SelectExpr{Expr: expr}
+		}
+		clause.Distinct = true
+		clause.GroupBy = nil
+		clause.Having = nil
+	}
+	return tree.AsStringWithFlags(sel, tree.FmtParsable), nil
+}
+
+// affectedRowsFilter returns a filter on the rows of the view query which
+// passes the rows made of at least one of the changed rows of the tables,
+// given by their primary keys.
+func (q *incrementalViewQuery) affectedRowsFilter(changed map[descpb.ID][]tree.Datums) tree.Expr {
+	var filter tree.Expr
+	for _, src := range q.sources {
+		keys := changed[src.table.GetID()]
+		if len(keys) == 0 {
+			continue
+		}
+		var left tree.Expr = &tree.Tuple{Exprs: src.keyCols}
+		right := &tree.Tuple{Exprs: make(tree.Exprs, len(keys))}
+		for i, key := range keys {
+			tuple := &tree.Tuple{Exprs: make(tree.Exprs, len(key))}
+			for j, d := range key {
+				tuple.Exprs[j] = d
+			}
+			right.Exprs[i] = tuple
+		}
+		if len(src.keyCols) == 1 {
+			left = src.keyCols[0]
+			for i, key := range keys {
+				right.Exprs[i] = key[0]
+			}
+		}
+		var expr tree.Expr = &tree.ComparisonExpr{
+			Operator: treecmp.MakeComparisonOperator(treecmp.In),
+			Left:     left,
+			Right:    right,
+		}
+		if filter != nil {
+			expr = &tree.OrExpr{Left: filter, Right: expr}
+		}
+		filter = expr
+	}
+	return filter
+}
+
+// groupsFilter returns a filter on the rows of the view query which passes
+// the rows of the given groups.
+func (q *incrementalViewQuery) groupsFilter(groups []tree.Datums) tree.Expr {
+	var filter tree.Expr
+	for _, group := range groups {
+		var groupFilter tree.Expr
+		for i, d := range group {
+			var expr tree.Expr = &tree.ComparisonExpr{
+				Operator: treecmp.MakeComparisonOperator(treecmp.IsNotDistinctFrom),
+				Left:     &tree.ParenExpr{Expr: q.groupExprs[i]},
+				Right:    d,
+			}
+			if groupFilter != nil {
+				expr = &tree.AndExpr{Left: groupFilter, Right: expr}
+			}
+			groupFilter = expr
+		}
+		groupFilter = &tree.ParenExpr{Expr: groupFilter}
+		if filter != nil {
+			groupFilter = &tree.OrExpr{Left: filter, Right: groupFilter}
+		}
+		filter = groupFilter
+	}
+	return filter
+}
+
+// refreshIncrementally refreshes the view with the changes made to the tables
+// it depends on since it was last refreshed. It returns false if the view has
+// to be refreshed fully instead, after notifying the client why.
+func (n *refreshMaterializedViewNode) refreshIncrementally(params runParams) (bool, error) {
+	ctx, p := params.ctx, params.p
+	q, err := p.analyzeIncrementalViewQuery(ctx, n.desc)
+	if err != nil {
+		return false, err
+	}
+	if err := n.applyChanges(params, q); err != nil {
+		if !errors.Is(err, errFullRefreshRequired) {
+			return false, err
+		}
+		p.BufferClientNotice(ctx, pgnotice.Newf(
+			"materialized view %q is refreshed fully: %v", n.desc.Name, err,
+		))
+		return false, nil
+	}
+	return true, nil
+}
+
+// applyChanges applies to the view the changes made to the tables it depends
+// on since it was last refreshed.
+func (n *refreshMaterializedViewNode) applyChanges(
+	params runParams, q *incrementalViewQuery,
+) error {
+	ctx, p := params.ctx, params.p
+	info := n.desc.MaterializedViewRefreshInfo
+	if info == nil || n.desc.IsRefreshViewRequired() {
+		return fullRefreshRequiredf("the view was not populated by a refresh supporting incremental refreshes")
+	}
+	startTS, endTS := info.AsOf, p.Txn().ReadTimestamp()
+
+	// The changes are read from the primary index of the tables, which must
+	// be the index that the tables had at the last refresh.
+	tables := make(map[descpb.ID]catalog.TableDescriptor)
+	for _, src := range q.sources {
+		tables[src.table.GetID()] = src.table
+	}
+	if err := p.ExecCfg().InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		if err := txn.KV().SetFixedTimestamp(ctx, startTS); err != nil {
+			return err
+		}
+		for id, table := range tables {
+			prev, err := txn.Descriptors().ByID(txn.KV()).Get().Table(ctx, id)
+			if err != nil {
+				return err
+			}
+			if prev.GetPrimaryIndexID() != table.GetPrimaryIndexID() {
+				return fullRefreshRequiredf(
+					"the primary index of %q changed since the last refresh", table.GetName())
+			}
+		}
+		return nil
+	}); err != nil {
+		return maybeMarkFullRefreshRequired(err)
+	}
+
+	changed := make(map[descpb.ID][]tree.Datums, len(tables))
+	limit := int(incrementalRefreshMaxChangedRows.Get(&p.ExecCfg().Settings.SV))
+	numChanged := 0
+	for id, table := range tables {
+		keys, err := changedPrimaryKeys(ctx, p.ExecCfg(), table, startTS, endTS, limit-numChanged)
+		if err != nil {
+			return maybeMarkFullRefreshRequired(err)
+		}
+		changed[id] = keys
+		numChanged += len(keys)
+		if numChanged > limit {
+			return fullRefreshRequiredf("more than %d rows changed since the last refresh", limit)
+		}
+	}
+
+	if numChanged > 0 {
+		w := incrementalViewWriter{p: p, view: n.desc}
+		var err error
+		if q.aggregate {
+			err = w.refreshGroups(ctx, q, changed, startTS, endTS)
+		} else {
+			err = w.refreshRows(ctx, q, changed, startTS, endTS)
+		}
+		if err != nil {
+			return maybeMarkFullRefreshRequired(err)
+		}
+	}
+
+	n.desc.MaterializedViewRefreshInfo = &descpb.TableDescriptor_MaterializedViewRefreshInfo{
+		AsOf:        endTS,
+		Incremental: true,
+	}
+	return p.writeTableDesc(ctx, n.desc)
+}
+
+// maybeMarkFullRefreshRequired marks the errors caused by reading data that
+// was garbage collected since the last refresh.
+func maybeMarkFullRefreshRequired(err error) error {
+	if errors.HasType(err, (*kvpb.BatchTimestampBeforeGCError)(nil)) {
+		return errors.Mark(
+			errors.Wrap(err, "the changes made since the last refresh were garbage collected"),
+			errFullRefreshRequired,
+		)
+	}
+	return err
+}
+
+// errChangedRowsLimitExceeded is returned by changedPrimaryKeys when the
+// limit of changed rows is exceeded.
+var errChangedRowsLimitExceeded = errors.New("limit of changed rows exceeded")
+
+// changedPrimaryKeys returns the primary keys of the rows of a table that
+// changed in (startTS, endTS]. The changes are read with ExportRequests, which
+// iterate over the table with a storage.MVCCIncrementalIterator, so only the
+// data written since startTS is read. At most limit+1 keys are returned, so
+// that callers can tell whether more than limit rows changed.
+func changedPrimaryKeys(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	table catalog.TableDescriptor,
+	startTS, endTS hlc.Timestamp,
+	limit int,
+) ([]tree.Datums, error) {
+	idx := table.GetPrimaryIndex()
+	colTypes := make([]*types.T, idx.NumKeyColumns())
+	for i := range colTypes {
+		col, err := catalog.MustFindColumnByID(table, idx.GetKeyColumnID(i))
+		if err != nil {
+			return nil, err
+		}
+		colTypes[i] = col.GetType()
+	}
+	colDirs := idx.IndexDesc().KeyColumnDirections
+
+	var res []tree.Datums
+	var alloc tree.DatumAlloc
+	seen := make(map[string]struct{})
+	addKey := func(key []byte) error {
+		rowKey, err := keys.EnsureSafeSplitKey(key)
+		if err != nil {
+			return err
+		}
+		if _, ok := seen[string(rowKey)]; ok {
+			return nil
+		}
+		if len(res) > limit {
+			return errChangedRowsLimitExceeded
+		}
+		seen[string(rowKey)] = struct{}{}
+		datums, err := rowenc.DecodeIndexKeyToDatums(execCfg.Codec, colTypes, colDirs, rowKey, &alloc)
+		if err != nil {
+			return err
+		}
+		res = append(res, datums)
+		return nil
+	}
+
+	span := table.PrimaryIndexSpan(execCfg.Codec)
+	for startKey := span.Key; startKey != nil; {
+		header := kvpb.Header{Timestamp: endTS}
+		req := &kvpb.ExportRequest{
+			RequestHeader: kvpb.RequestHeader{Key: startKey, EndKey: span.EndKey},
+			StartTime:     startTS,
+			MVCCFilter:    kvpb.MVCCFilter_Latest,
+		}
+		resp, pErr := kv.SendWrappedWith(ctx, execCfg.DB.NonTransactionalSender(), header, req)
+		if pErr != nil {
+			return nil, pErr.GoError()
+		}
+		exportResp := resp.(*kvpb.ExportResponse)
+		for _, file := range exportResp.Files {
+			if err := func() error {
+				iter, err := storage.NewMemSSTIterator(file.SST, false /* verify */, storage.IterOptions{
+					KeyTypes:   storage.IterKeyTypePointsAndRanges,
+					LowerBound: file.Span.Key,
+					UpperBound: file.Span.EndKey,
+				})
+				if err != nil {
+					return err
+				}
+				defer iter.Close()
+				for iter.SeekGE(storage.MVCCKey{Key: file.Span.Key}); ; iter.Next() {
+					if ok, err := iter.Valid(); err != nil {
+						return err
+					} else if !ok {
+						return nil
+					}
+					if _, hasRange := iter.HasPointAndRange(); hasRange {
+						return fullRefreshRequiredf(
+							"rows of %q were deleted by a range deletion since the last refresh",
+							table.GetName())
+					}
+					if err := addKey(iter.UnsafeKey().Key); err != nil {
+						return err
+					}
+				}
+			}(); err != nil {
+				if errors.Is(err, errChangedRowsLimitExceeded) {
+					return res, nil
+				}
+				return nil, err
+			}
+		}
+		startKey = nil
+		if exportResp.ResumeSpan != nil {
+			startKey = exportResp.ResumeSpan.Key
+		}
+	}
+	return res, nil
+}
+
+// incrementalViewWriter writes the changes of an incremental refresh to the
+// view, in the transaction of the refresh.
+type incrementalViewWriter struct {
+	p    *planner
+	view catalog.TableDescriptor
+}
+
+// query evaluates a query outside of the transaction of the refresh, since
+// the queries read as of the timestamps of the last and of this refresh.
+func (w incrementalViewWriter) query(ctx context.Context, query string) ([]tree.Datums, error) {
+	return w.p.ExecCfg().InternalDB.Executor().QueryBufferedEx(
+		ctx, incrementalRefreshOpName, nil, /* txn */
+		sessiondata.NodeUserSessionDataOverride, query,
+	)
+}
+
+func (w incrementalViewWriter) exec(ctx context.Context, stmt string, args ...interface{}) error {
+	_, err := w.p.InternalSQLTxn().ExecEx(
+		ctx, incrementalRefreshOpName, w.p.Txn(),
+		sessiondata.InternalExecutorOverride{
+			User:                           username.NodeUserName(),
+			AllowMaterializedViewMutations: true,
+		},
+		stmt, args...,
+	)
+	return err
+}
+
+// viewName returns a reference to the view by ID.
+func (w incrementalViewWriter) viewName() string {
+	return tree.AsStringWithFlags(&tree.TableRef{
+		TableID: int64(w.view.GetID()),
+		As:      tree.AliasClause{Alias: "v"},
+	}, tree.FmtParsable)
+}
+
+// deleteRows deletes the view rows whose given columns hold the given
+// values. If limit is positive, at most limit rows are deleted.
+func (w incrementalViewWriter) deleteRows(
+	ctx context.Context, cols []int, vals tree.Datums, limit int,
+) error {
+	var b strings.Builder
+	b.WriteString("DELETE FROM ")
+	b.WriteString(w.viewName())
+	args := make([]interface{}, len(cols))
+	for i, col := range cols {
+		if i == 0 {
+			b.WriteString(" WHERE ")
+		} else {
+			b.WriteString(" AND ")
+		}
+		b.WriteString(tree.NameString(w.view.VisibleColumns()[col].GetName()))
+		b.WriteString(" IS NOT DISTINCT FROM ")
+		fmt.Fprintf(&b, "$%d", i+1)
+		args[i] = vals[i]
+	}
+	if limit > 0 {
+		b.WriteString(" LIMIT ")
+		b.WriteString(tree.AsString(tree.NewDInt(tree.DInt(limit))))
+	}
+	return w.exec(ctx, b.String(), args...)
+}
+
+// insertRows inserts rows into the view.
+func (w incrementalViewWriter) insertRows(ctx context.Context, rows []tree.Datums) error {
+	cols := w.view.VisibleColumns()
+	batchSize := incrementalRefreshMaxPlaceholders / len(cols)
+	for len(rows) > 0 {
+		batch := rows
+		if len(batch) > batchSize {
+			batch = batch[:batchSize]
+		}
+		rows = rows[len(batch):]
+
+		var b strings.Builder
+		b.WriteString("INSERT INTO ")
+		b.WriteString(w.viewName())
+		b.WriteString(" (")
+		for i, col := range cols {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(tree.NameString(col.GetName()))
+		}
+		b.WriteString(") VALUES ")
+		args := make([]interface{}, 0, len(batch)*len(cols))
+		for i, row := range batch {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteByte('(')
+			for j := range cols {
+				if j > 0 {
+					b.WriteString(", ")
+				}
+				args = append(args, row[j])
+				fmt.Fprintf(&b, "$%d", len(args))
+			}
+			b.WriteByte(')')
+		}
+		if err := w.exec(ctx, b.String(), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// datumsKey returns a string which is equal for equal rows.
+func datumsKey(row tree.Datums) string {
+	return tree.AsStringWithFlags(&row, tree.FmtParsable)
+}
+
+// refreshRows refreshes a select-project-join view: the affected rows as of
+// the last refresh are deleted from the view, and the affected rows as of now
+// are inserted. Rows that are both are left in place.
+func (w incrementalViewWriter) refreshRows(
+	ctx context.Context,
+	q *incrementalViewQuery,
+	changed map[descpb.ID][]tree.Datums,
+	startTS, endTS hlc.Timestamp,
+) error {
+	filter := q.affectedRowsFilter(changed)
+	type rowDelta struct {
+		row   tree.Datums
+		count int
+	}
+	deltas := make(map[string]*rowDelta)
+	for i, ts := range []hlc.Timestamp{startTS, endTS} {
+		query, err := q.makeQuery(ts, filter, false /* groupsOnly */)
+		if err != nil {
+			return err
+		}
+		rows, err := w.query(ctx, query)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			key := datumsKey(row)
+			d, ok := deltas[key]
+			if !ok {
+				d = &rowDelta{row: row}
+				deltas[key] = d
+			}
+			if i == 0 {
+				d.count--
+			} else {
+				d.count++
+			}
+		}
+	}
+
+	// Apply the changes in a deterministic order.
+	keys := make([]string, 0, len(deltas))
+	for key := range deltas {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	allCols := make([]int, len(w.view.VisibleColumns()))
+	for i := range allCols {
+		allCols[i] = i
+	}
+	var inserts []tree.Datums
+	for _, key := range keys {
+		d := deltas[key]
+		if d.count < 0 {
+			if err := w.deleteRows(ctx, allCols, d.row, -d.count); err != nil {
+				return err
+			}
+		}
+		for i := 0; i < d.count; i++ {
+			inserts = append(inserts, d.row)
+		}
+	}
+	return w.insertRows(ctx, inserts)
+}
+
+// refreshGroups refreshes an aggregate view: the groups of the affected rows,
+// as of the last refresh and as of now, are deleted from the view and
+// recomputed.
+func (w incrementalViewWriter) refreshGroups(
+	ctx context.Context,
+	q *incrementalViewQuery,
+	changed map[descpb.ID][]tree.Datums,
+	startTS, endTS hlc.Timestamp,
+) error {
+	if len(q.groupExprs) == 0 {
+		// The query aggregates all its rows into a single group.
+		if err := w.exec(ctx, "DELETE FROM "+w.viewName()+" WHERE true"); err != nil {
+			return err
+		}
+		query, err := q.makeQuery(endTS, nil /* filter */, false /* groupsOnly */)
+		if err != nil {
+			return err
+		}
+		rows, err := w.query(ctx, query)
+		if err != nil {
+			return err
+		}
+		return w.insertRows(ctx, rows)
+	}
+
+	filter := q.affectedRowsFilter(changed)
+	var groups []tree.Datums
+	seen := make(map[string]struct{})
+	for _, ts := range []hlc.Timestamp{startTS, endTS} {
+		query, err := q.makeQuery(ts, filter, true /* groupsOnly */)
+		if err != nil {
+			return err
+		}
+		rows, err := w.query(ctx, query)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			key := datumsKey(row)
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				groups = append(groups, row)
+			}
+		}
+	}
+
+	for len(groups) > 0 {
+		batch := groups
+		if len(batch) > incrementalRefreshGroupBatchSize {
+			batch = batch[:incrementalRefreshGroupBatchSize]
+		}
+		groups = groups[len(batch):]
+
+		for _, group := range batch {
+			if err := w.deleteRows(ctx, q.groupCols, group, 0 /* limit */); err != nil {
+				return err
+			}
+		}
+		query, err := q.makeQuery(endTS, q.groupsFilter(batch), false /* groupsOnly */)
+		if err != nil {
+			return err
+		}
+		rows, err := w.query(ctx, query)
+		if err != nil {
+			return err
+		}
+		if err := w.insertRows(ctx, rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
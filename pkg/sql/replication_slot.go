@@ -0,0 +1,280 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsnutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgoutput"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgrepltree"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// maxReplicationSlotNameLength is the maximum length of a replication slot
+// name, which matches the maximum length of an identifier in PostgreSQL.
+const maxReplicationSlotNameLength = 63
+
+// replicationSlot is a row of system.replication_slots.
+type replicationSlot struct {
+	name       string
+	databaseID descpb.ID
+	plugin     string
+	owner      username.SQLUsername
+	// confirmedFlush is the position up to which the client has confirmed
+	// receiving the changes of the slot.
+	confirmedFlush lsn.LSN
+}
+
+// getReplicationSlots returns the replication slots, ordered by name. If names
+// is not empty, only the slots with these names are returned.
+func getReplicationSlots(
+	ctx context.Context, txn isql.Txn, names ...string,
+) ([]replicationSlot, error) {
+	query := `SELECT slot_name, database_id, plugin, owner, confirmed_flush_lsn FROM system.replication_slots`
+	var args []interface{}
+	if len(names) > 0 {
+		arr := tree.NewDArray(types.String)
+		for _, name := range names {
+			if err := arr.Append(tree.NewDString(name)); err != nil {
+				return nil, err
+			}
+		}
+		query += ` WHERE slot_name = ANY $1`
+		args = append(args, arr)
+	}
+	rows, err := txn.QueryBufferedEx(
+		ctx, "get-replication-slots", txn.KV(), sessiondata.NodeUserSessionDataOverride,
+		query+` ORDER BY slot_name`, args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]replicationSlot, len(rows))
+	for i, row := range rows {
+		slots[i] = replicationSlot{
+			name:           string(tree.MustBeDString(row[0])),
+			databaseID:     descpb.ID(tree.MustBeDInt(row[1])),
+			plugin:         string(tree.MustBeDString(row[2])),
+			owner:          username.MakeSQLUsernameFromPreNormalizedString(string(tree.MustBeDString(row[3]))),
+			confirmedFlush: tree.MustBeDPGLSN(row[4]).LSN,
+		}
+	}
+	return slots, nil
+}
+
+// getReplicationSlot returns the replication slot with the given name, or an
+// error if it does not exist.
+func getReplicationSlot(ctx context.Context, txn isql.Txn, name string) (replicationSlot, error) {
+	slots, err := getReplicationSlots(ctx, txn, name)
+	if err != nil {
+		return replicationSlot{}, err
+	}
+	if len(slots) == 0 {
+		return replicationSlot{}, pgerror.Newf(pgcode.UndefinedObject,
+			"replication slot %q does not exist", name)
+	}
+	return slots[0], nil
+}
+
+// advanceReplicationSlot records that the client of the given slot has
+// confirmed receiving all changes up to the given LSN. The confirmed position
+// of a slot never moves backwards.
+func advanceReplicationSlot(ctx context.Context, txn isql.Txn, name string, l lsn.LSN) error {
+	_, err := txn.ExecEx(
+		ctx, "advance-replication-slot", txn.KV(), sessiondata.NodeUserSessionDataOverride,
+		`UPDATE system.replication_slots SET confirmed_flush_lsn = $2
+WHERE slot_name = $1 AND confirmed_flush_lsn < $2`,
+		name, tree.NewDPGLSN(l),
+	)
+	return err
+}
+
+// validateReplicationSlotName checks that the name of a replication slot only
+// contains lower case letters, numbers and the underscore character, like
+// PostgreSQL requires.
+func validateReplicationSlotName(name string) error {
+	if name == "" {
+		return pgerror.New(pgcode.InvalidName, "replication slot name must not be empty")
+	}
+	if len(name) > maxReplicationSlotNameLength {
+		return pgerror.Newf(pgcode.NameTooLong, "replication slot name %q is too long", name)
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != '_' {
+			return errors.WithHint(
+				pgerror.Newf(pgcode.InvalidName,
+					"replication slot name %q contains invalid character", name),
+				"Replication slot names may only contain lower case letters, numbers, and the underscore character.",
+			)
+		}
+	}
+	return nil
+}
+
+type createReplicationSlotNode struct {
+	optColumnsSlot
+	slot  replicationSlot
+	shown bool
+}
+
+// CreateReplicationSlot implements the CREATE_REPLICATION_SLOT replication
+// command. Only permanent logical replication slots using the pgoutput plugin
+// are supported. The slot starts at the current time, which is returned as
+// the consistent point of the slot.
+func (p *planner) CreateReplicationSlot(
+	ctx context.Context, n *pgrepltree.CreateReplicationSlot,
+) (planNode, error) {
+	if err := p.checkLogicalReplicationSupported(ctx, "CREATE_REPLICATION_SLOT"); err != nil {
+		return nil, err
+	}
+	if n.Kind != pgrepltree.LogicalReplication {
+		return nil, unimplemented.New("physical replication slots", "physical replication slots are not supported")
+	}
+	if n.Temporary {
+		return nil, unimplemented.New("temporary replication slots", "temporary replication slots are not supported")
+	}
+	if string(n.Plugin) != pgoutput.PluginName {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"output plugin %q is not supported", n.Plugin)
+	}
+	for _, opt := range n.Options {
+		switch opt.Key {
+		case "snapshot":
+			// Snapshots cannot be exported, but the consistent point of the slot
+			// can be used to read the data it starts from with AS OF SYSTEM TIME.
+			if s, ok := opt.Value.(*tree.StrVal); ok && s.RawString() == "export" {
+				return nil, unimplemented.New("export snapshot", "exporting snapshots is not supported")
+			}
+		case "reserve_wal", "two_phase":
+		default:
+			return nil, pgerror.Newf(pgcode.Syntax,
+				"unrecognized CREATE_REPLICATION_SLOT option %q", opt.Key)
+		}
+	}
+	if err := validateReplicationSlotName(string(n.Slot)); err != nil {
+		return nil, err
+	}
+	if p.CurrentDatabase() == "" {
+		return nil, pgerror.New(pgcode.ObjectNotInPrerequisiteState,
+			"logical decoding requires a database connection")
+	}
+	dbDesc, err := p.Descriptors().ByNameWithLeased(p.txn).Get().Database(ctx, p.CurrentDatabase())
+	if err != nil {
+		return nil, err
+	}
+	// Check for an existing slot while planning, so that the error is sent
+	// before the description of the result rows.
+	existing, err := getReplicationSlots(ctx, p.InternalSQLTxn(), string(n.Slot))
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, pgerror.Newf(pgcode.DuplicateObject, "replication slot %q already exists", n.Slot)
+	}
+	return &createReplicationSlotNode{
+		slot: replicationSlot{
+			name:           string(n.Slot),
+			databaseID:     dbDesc.GetID(),
+			plugin:         string(n.Plugin),
+			owner:          p.User(),
+			confirmedFlush: lsnutil.HLCToLSN(p.Txn().ReadTimestamp()),
+		},
+	}, nil
+}
+
+func (n *createReplicationSlotNode) startExec(params runParams) error {
+	ctx, txn := params.ctx, params.p.InternalSQLTxn()
+	if _, err := txn.ExecEx(
+		ctx, "create-replication-slot", txn.KV(), sessiondata.NodeUserSessionDataOverride,
+		`INSERT INTO system.replication_slots (slot_name, database_id, plugin, owner, owner_id, confirmed_flush_lsn)
+VALUES ($1, $2, $3, $4, (SELECT user_id FROM system.users WHERE username = $4), $5)`,
+		n.slot.name, n.slot.databaseID, n.slot.plugin, n.slot.owner.Normalized(),
+		tree.NewDPGLSN(n.slot.confirmedFlush),
+	); err != nil {
+		return errors.Wrap(err, "failed to create replication slot")
+	}
+	return nil
+}
+
+func (n *createReplicationSlotNode) Next(_ runParams) (bool, error) {
+	if n.shown {
+		return false, nil
+	}
+	n.shown = true
+	return true, nil
+}
+
+func (n *createReplicationSlotNode) Values() tree.Datums {
+	return tree.Datums{
+		tree.NewDString(n.slot.name),                    // slot_name
+		tree.NewDString(n.slot.confirmedFlush.String()), // consistent_point
+		tree.DNull,                     // snapshot_name
+		tree.NewDString(n.slot.plugin), // output_plugin
+	}
+}
+
+func (n *createReplicationSlotNode) Close(_ context.Context) {}
+
+type dropReplicationSlotNode struct {
+	n *pgrepltree.DropReplicationSlot
+}
+
+// DropReplicationSlot implements the DROP_REPLICATION_SLOT replication
+// command.
+func (p *planner) DropReplicationSlot(
+	ctx context.Context, n *pgrepltree.DropReplicationSlot,
+) (planNode, error) {
+	if err := p.checkLogicalReplicationSupported(ctx, "DROP_REPLICATION_SLOT"); err != nil {
+		return nil, err
+	}
+	return &dropReplicationSlotNode{n: n}, nil
+}
+
+func (n *dropReplicationSlotNode) startExec(params runParams) error {
+	ctx, p := params.ctx, params.p
+	txn := p.InternalSQLTxn()
+	slot, err := getReplicationSlot(ctx, txn, string(n.n.Slot))
+	if err != nil {
+		return err
+	}
+	if slot.owner != p.User() {
+		isAdmin, err := p.UserHasAdminRole(ctx, p.User())
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return pgerror.Newf(pgcode.InsufficientPrivilege,
+				"must be owner of replication slot %s", slot.name)
+		}
+	}
+	if _, err := txn.ExecEx(
+		ctx, "drop-replication-slot", txn.KV(), sessiondata.NodeUserSessionDataOverride,
+		`DELETE FROM system.replication_slots WHERE slot_name = $1`, slot.name,
+	); err != nil {
+		return errors.Wrap(err, "failed to drop replication slot")
+	}
+	return nil
+}
+
+func (n *dropReplicationSlotNode) Next(_ runParams) (bool, error) { return false, nil }
+func (n *dropReplicationSlotNode) Values() tree.Datums            { return nil }
+func (n *dropReplicationSlotNode) Close(_ context.Context)        {}
@@ -11,6 +11,8 @@
 package row
 
 import (
+	"time"
+
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/errors"
@@ -61,3 +63,13 @@ func GetWaitPolicy(lockWaitPolicy descpb.ScanLockingWaitPolicy) lock.WaitPolicy
 		panic(errors.AssertionFailedf("unknown wait policy %s", lockWaitPolicy))
 	}
 }
+
+// GetLockTimeout returns the lock timeout to use for key-value requests against
+// a table, given the session's lock_timeout and the table's lock_timeout
+// storage parameter. The session setting takes precedence when it is set.
+func GetLockTimeout(sessionLockTimeout, tableLockTimeout time.Duration) time.Duration {
+	if sessionLockTimeout != 0 {
+		return sessionLockTimeout
+	}
+	return tableLockTimeout
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
@@ -553,6 +554,10 @@ func EncodeInvertedIndexKeys(
 	if !indexGeoConfig.IsEmpty() {
 		return EncodeGeoInvertedIndexTableKeys(val, keyPrefix, indexGeoConfig)
 	}
+	indexVectorConfig := index.GetVectorConfig()
+	if !indexVectorConfig.IsEmpty() {
+		return EncodeVectorInvertedIndexTableKeys(val, keyPrefix, indexVectorConfig)
+	}
 	return EncodeInvertedIndexTableKeys(val, keyPrefix, index.GetVersion())
 }
 
@@ -1012,6 +1017,28 @@ func EncodeGeoInvertedIndexTableKeys(
 	}
 }
 
+// EncodeVectorInvertedIndexTableKeys is the equivalent of
+// EncodeInvertedIndexTableKeys for vectors. A vector has a single key, which
+// identifies the list of the index that it is stored in.
+func EncodeVectorInvertedIndexTableKeys(
+	val tree.Datum, inKey []byte, indexVectorConfig vecindex.Config,
+) (key [][]byte, err error) {
+	if val == tree.DNull {
+		return nil, nil
+	}
+	v, ok := val.(*tree.DPGVector)
+	if !ok {
+		return nil, errors.Errorf("internal error: unexpected type: %s", val.ResolvedType().Family())
+	}
+	return [][]byte{EncodeVectorInvertedIndexKey(inKey, indexVectorConfig.Partition(v.T))}, nil
+}
+
+// EncodeVectorInvertedIndexKey appends the key of the given list of a vector
+// index to inKey.
+func EncodeVectorInvertedIndexKey(inKey []byte, list int) []byte {
+	return encoding.EncodeUvarintAscending(inKey, uint64(list))
+}
+
 func encodeGeoKeys(
 	inKey []byte, geoKeys []geoindex.Key, bbox geopb.BoundingBox,
 ) (keys [][]byte, err error) {
@@ -46,6 +46,7 @@ func InitIndexFetchSpec(
 		EncodingType:        index.GetEncodingType(),
 		NumKeySuffixColumns: uint32(index.NumKeySuffixColumns()),
 		GeoConfig:           index.GetGeoConfig(),
+		LockTimeout:         table.GetLockTimeout(),
 	}
 
 	maxKeysPerRow := table.IndexKeysPerRow(index)
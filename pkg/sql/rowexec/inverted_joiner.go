@@ -296,7 +296,7 @@ func newInvertedJoiner(
 			Txn:                        flowCtx.Txn,
 			LockStrength:               spec.LockingStrength,
 			LockWaitPolicy:             spec.LockingWaitPolicy,
-			LockTimeout:                flowCtx.EvalCtx.SessionData().LockTimeout,
+			LockTimeout:                row.GetLockTimeout(flowCtx.EvalCtx.SessionData().LockTimeout, spec.FetchSpec.LockTimeout),
 			Alloc:                      &ij.alloc,
 			MemMonitor:                 flowCtx.Mon,
 			Spec:                       &spec.FetchSpec,
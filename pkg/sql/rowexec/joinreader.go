@@ -562,7 +562,7 @@ func newJoinReader(
 			Txn:                        jr.txn,
 			LockStrength:               spec.LockingStrength,
 			LockWaitPolicy:             spec.LockingWaitPolicy,
-			LockTimeout:                flowCtx.EvalCtx.SessionData().LockTimeout,
+			LockTimeout:                row.GetLockTimeout(flowCtx.EvalCtx.SessionData().LockTimeout, spec.FetchSpec.LockTimeout),
 			Alloc:                      &jr.alloc,
 			MemMonitor:                 flowCtx.Mon,
 			Spec:                       &spec.FetchSpec,
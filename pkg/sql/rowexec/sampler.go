@@ -347,6 +347,8 @@ func (s *samplerProcessor) mainLoop(
 			switch s.outTypes[col].Family() {
 			case types.GeographyFamily, types.GeometryFamily:
 				invKeys, err = rowenc.EncodeGeoInvertedIndexTableKeys(row[col].Datum, nil /* inKey */, index.GeoConfig)
+			case types.PGVectorFamily:
+				invKeys, err = rowenc.EncodeVectorInvertedIndexTableKeys(row[col].Datum, nil /* inKey */, index.VectorConfig)
 			default:
 				invKeys, err = rowenc.EncodeInvertedIndexTableKeys(row[col].Datum, nil /* inKey */, index.Version)
 			}
@@ -470,7 +470,7 @@ func (z *zigzagJoiner) setupInfo(
 			Txn:                        flowCtx.Txn,
 			LockStrength:               spec.LockingStrength,
 			LockWaitPolicy:             spec.LockingWaitPolicy,
-			LockTimeout:                flowCtx.EvalCtx.SessionData().LockTimeout,
+			LockTimeout:                row.GetLockTimeout(flowCtx.EvalCtx.SessionData().LockTimeout, info.fetchSpec.LockTimeout),
 			Alloc:                      &info.alloc,
 			MemMonitor:                 flowCtx.Mon,
 			Spec:                       &spec.FetchSpec,
@@ -315,12 +315,30 @@ func (s *SQLScanner) Scan(lval ScanSymType) {
 			return
 		case '=': // <=
 			s.pos++
+			switch s.peek() {
+			case '>': // <=>
+				s.pos++
+				lval.SetID(lexbase.VECTOR_COS_DISTANCE)
+				return
+			}
 			lval.SetID(lexbase.LESS_EQUALS)
 			return
 		case '@': // <@
 			s.pos++
 			lval.SetID(lexbase.CONTAINED_BY)
 			return
+		case '-': // <-
+			if s.peekN(1) == '>' { // <->
+				s.pos += 2
+				lval.SetID(lexbase.VECTOR_DISTANCE)
+				return
+			}
+		case '#': // <#
+			if s.peekN(1) == '>' { // <#>
+				s.pos += 2
+				lval.SetID(lexbase.VECTOR_NEG_INNER_PRODUCT)
+				return
+			}
 		}
 		return
 
@@ -405,6 +423,10 @@ func (s *SQLScanner) Scan(lval ScanSymType) {
 			s.pos++
 			lval.SetID(lexbase.AT_AT)
 			return
+		case '?': // @?
+			s.pos++
+			lval.SetID(lexbase.JSON_PATH_EXISTS)
+			return
 		}
 		return
 
@@ -505,6 +505,11 @@ func (sc *SchemaChanger) maybeMakeAddTablePublic(
 			return nil
 		}
 		mut.State = descpb.DescriptorState_PUBLIC
+		if mut.MaterializedView() && !mut.IsRefreshViewRequired() {
+			mut.MaterializedViewRefreshInfo = &descpb.TableDescriptor_MaterializedViewRefreshInfo{
+				AsOf: mut.GetCreateAsOfTime(),
+			}
+		}
 		return txn.Descriptors().WriteDesc(ctx, true /* kvTrace */, mut, txn.KV())
 	})
 }
@@ -1542,6 +1547,16 @@ func (sc *SchemaChanger) done(ctx context.Context) error {
 				// If we are mutation is in the ADD state, then start GC jobs for the
 				// existing indexes on the table.
 				if m.Adding() {
+					// Record the timestamp as of which the view now holds the
+					// results of its query, which incremental refreshes start
+					// from. A refresh WITH NO DATA leaves the view empty, so
+					// it can't be incrementally refreshed.
+					scTable.MaterializedViewRefreshInfo = nil
+					if refresh.ShouldBackfill() {
+						scTable.MaterializedViewRefreshInfo = &descpb.TableDescriptor_MaterializedViewRefreshInfo{
+							AsOf: refresh.AsOf(),
+						}
+					}
 					desc := fmt.Sprintf("REFRESH MATERIALIZED VIEW %q cleanup", scTable.Name)
 					for _, idx := range scTable.ActiveIndexes() {
 						if err := sc.createIndexGCJob(ctx, idx.GetID(), txn, desc); err != nil {
@@ -2242,6 +2257,18 @@ func (sc *SchemaChanger) maybeDropValidatingConstraint(
 			"attempted to drop constraint %s, but it hadn't been added to the table descriptor yet",
 			constraint.GetName(),
 		)
+	} else if constraint.AsExclusion() != nil {
+		for j, c := range desc.Exclusions {
+			if c.Name == constraint.GetName() {
+				desc.Exclusions = append(desc.Exclusions[:j], desc.Exclusions[j+1:]...)
+				return nil
+			}
+		}
+		log.Infof(
+			ctx,
+			"attempted to drop constraint %s, but it hadn't been added to the table descriptor yet",
+			constraint.GetName(),
+		)
 	} else {
 		return errors.AssertionFailedf("unsupported constraint type: %s", constraint)
 	}
@@ -132,6 +132,11 @@ func alterTableAddConstraintChecks(
 		return isV222Active(t, mode, activeVersion)
 	}
 
+	// Exclusion constraints are only supported by the legacy schema changer.
+	if _, ok := t.ConstraintDef.(*tree.ExclusionConstraintTableDef); ok {
+		return false
+	}
+
 	// Start supporting all other ADD CONSTRAINTs from V23_1, including
 	// - ADD PRIMARY KEY NOT VALID
 	// - ADD UNIQUE [NOT VALID]
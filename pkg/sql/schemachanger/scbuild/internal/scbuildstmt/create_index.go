@@ -40,6 +40,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/storageparam"
 	"github.com/cockroachdb/cockroach/pkg/sql/storageparam/indexstorageparam"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/errors"
@@ -361,6 +362,16 @@ func processColNodeType(
 			}
 			invertedKind = catpb.InvertedIndexColumnKind_TRIGRAM
 			b.IncrementSchemaChangeIndexCounter("trigram_inverted")
+		case types.PGVectorFamily:
+			// The list assignment does not depend on the distance metric, so all
+			// of the pgvector operator classes share the same index layout.
+			switch columnNode.OpClass {
+			case "vector_l2_ops", "vector_cosine_ops", "vector_ip_ops", "":
+			default:
+				panic(newUndefinedOpclassError(columnNode.OpClass))
+			}
+			indexSpec.secondary.VectorConfig = vecindex.DefaultConfig()
+			b.IncrementSchemaChangeIndexCounter("vector_inverted")
 
 		}
 		relationElts := b.QueryByID(indexSpec.secondary.TableID)
@@ -924,7 +935,7 @@ func maybeAddIndexPredicate(b BuildCtx, n *tree.CreateIndex, idxSpec *indexSpec)
 }
 
 // maybeApplyStorageParameters apply any storage parameters into the index spec,
-// this is only used for GeoConfig today.
+// this is only used for GeoConfig and VectorConfig today.
 func maybeApplyStorageParameters(b BuildCtx, n *tree.CreateIndex, idxSpec *indexSpec) {
 	if len(n.StorageParams) == 0 {
 		return
@@ -933,6 +944,9 @@ func maybeApplyStorageParameters(b BuildCtx, n *tree.CreateIndex, idxSpec *index
 	if idxSpec.secondary.GeoConfig != nil {
 		dummyIndexDesc.GeoConfig = *idxSpec.secondary.GeoConfig
 	}
+	if idxSpec.secondary.VectorConfig != nil {
+		dummyIndexDesc.VectorConfig = *idxSpec.secondary.VectorConfig
+	}
 	storageParamSetter := &indexstorageparam.Setter{
 		IndexDesc: dummyIndexDesc,
 	}
@@ -945,4 +959,9 @@ func maybeApplyStorageParameters(b BuildCtx, n *tree.CreateIndex, idxSpec *index
 	} else {
 		idxSpec.secondary.GeoConfig = nil
 	}
+	if !dummyIndexDesc.VectorConfig.IsEmpty() {
+		idxSpec.secondary.VectorConfig = &dummyIndexDesc.VectorConfig
+	} else {
+		idxSpec.secondary.VectorConfig = nil
+	}
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/errors"
@@ -276,6 +277,18 @@ func GetSequenceOptions(
 }
 
 func (w *walkCtx) walkRelation(tbl catalog.TableDescriptor) {
+	if len(tbl.ExclusionConstraints()) > 0 {
+		panic(scerrors.NotImplementedErrorf(
+			nil, /* n */
+			"exclusion constraints are not supported by the declarative schema changer",
+		))
+	}
+	if len(tbl.GetInherits()) > 0 || len(tbl.GetInheritedBy()) > 0 || tbl.GetPartitionKey() != nil {
+		panic(scerrors.NotImplementedErrorf(
+			nil, /* n */
+			"table inheritance is not supported by the declarative schema changer",
+		))
+	}
 	switch {
 	case tbl.IsSequence():
 		w.ev(descriptorStatus(tbl), &scpb.Sequence{
@@ -590,6 +603,9 @@ func (w *walkCtx) walkIndex(tbl catalog.TableDescriptor, idx catalog.Index) {
 		if geoConfig := idx.GetGeoConfig(); !geoConfig.IsEmpty() {
 			index.GeoConfig = protoutil.Clone(&geoConfig).(*geoindex.Config)
 		}
+		if vectorConfig := idx.GetVectorConfig(); !vectorConfig.IsEmpty() {
+			index.VectorConfig = protoutil.Clone(&vectorConfig).(*vecindex.Config)
+		}
 		for i, c := range cpy.KeyColumnIDs {
 			invertedKind := catpb.InvertedIndexColumnKind_DEFAULT
 			if index.IsInverted && c == idx.InvertedColumnID() {
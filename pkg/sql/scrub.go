@@ -447,6 +447,9 @@ func createConstraintCheckOperations(
 			op = newSQLUniqueWithIndexConstraintCheckOperation(tableName, tableDesc, uwi, asOf)
 		} else if uwoi := constraint.AsUniqueWithoutIndex(); uwoi != nil {
 			op = newSQLUniqueWithoutIndexConstraintCheckOperation(tableName, tableDesc, uwoi, asOf)
+		} else if constraint.AsExclusion() != nil {
+			// SCRUB does not check exclusion constraints yet.
+			continue
 		} else {
 			return nil, errors.AssertionFailedf("unknown constraint type %T", constraint)
 		}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/cockroachdb/apd/v3"
 	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/geo/geomfn"
 	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
 	"github.com/cockroachdb/cockroach/pkg/geo/geos"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -546,6 +547,7 @@ var aggregates = map[string]builtinDefinition{
 	"st_memunion":   makeSTUnionBuiltin(),
 	"st_collect":    makeSTCollectBuiltin(),
 	"st_memcollect": makeSTCollectBuiltin(),
+	"st_asmvt":      makeSTAsMVTBuiltin(),
 
 	AnyNotNull: makePrivate(makeBuiltin(tree.FunctionProperties{},
 		makeImmutableAggOverloadWithReturnType(
@@ -795,6 +797,53 @@ func makeSTUnionBuiltin() builtinDefinition {
 	)
 }
 
+func makeSTAsMVTBuiltin() builtinDefinition {
+	const info = `Encodes the input rows as a layer of a Mapbox Vector Tile (MVT), following version 2.1 of the specification.
+
+The first geometry column of the rows is used as the feature geometry, and must already be in the coordinate space of the tile, for example as returned by ST_AsMVTGeom. The other non-NULL columns of the rows are encoded as the feature attributes, with the top-level keys of JSONB columns encoded as separate attributes. Rows with a NULL geometry are skipped.`
+	makeOverload := func(in []*types.T, info string) tree.Overload {
+		return makeAggOverload(
+			in,
+			types.Bytes,
+			newSTAsMVTAgg,
+			infoBuilder{info: info}.String(),
+			// Attributes which are not natively supported by the MVT format
+			// are encoded as strings, which can depend on the session (e.g.
+			// for TIMESTAMPTZ).
+			volatility.Stable,
+			true, /* calledOnNullInput */
+		)
+	}
+	return makeBuiltin(
+		tree.FunctionProperties{
+			AvailableOnPublicSchema: true,
+		},
+		makeOverload(
+			[]*types.T{types.AnyTuple},
+			info+"\n\nThe layer is named \"default\" and uses 4096 as the tile extent.",
+		),
+		makeOverload(
+			[]*types.T{types.AnyTuple, types.String},
+			info+"\n\nThe second argument is the name of the layer. Uses 4096 as the tile extent.",
+		),
+		makeOverload(
+			[]*types.T{types.AnyTuple, types.String, types.Int},
+			info+"\n\nThe second argument is the name of the layer, and the third argument the tile extent.",
+		),
+		makeOverload(
+			[]*types.T{types.AnyTuple, types.String, types.Int, types.String},
+			info+"\n\nThe second argument is the name of the layer, the third argument the tile extent, "+
+				"and the fourth argument the name of the geometry column.",
+		),
+		makeOverload(
+			[]*types.T{types.AnyTuple, types.String, types.Int, types.String, types.String},
+			info+"\n\nThe second argument is the name of the layer, the third argument the tile extent, "+
+				"the fourth argument the name of the geometry column, and the fifth argument the name of "+
+				"the integer column holding the feature ID.",
+		),
+	)
+}
+
 func makeRegressionAggregateBuiltin(
 	aggregateFunc func([]*types.T, *eval.Context, tree.Datums) eval.AggregateFunc, info string,
 ) builtinDefinition {
@@ -1218,6 +1267,222 @@ func (agg *stExtentAgg) Size() int64 {
 	return sizeOfSTExtentAggregate
 }
 
+type stAsMVTAgg struct {
+	acc mon.BoundAccount
+	// arguments contains the constant arguments of the aggregate, which follow
+	// the non-constant arguments passed to Add.
+	arguments tree.Datums
+	// layer is initialized on the first non-NULL row.
+	layer *geomfn.MVTLayer
+	// colNames are the names of the columns of the input rows.
+	colNames []string
+	// geomIdx and idIdx are the ordinals of the geometry and feature ID
+	// columns. idIdx is -1 if the features have no ID.
+	geomIdx, idIdx int
+	attrs          []geomfn.MVTAttribute
+}
+
+func newSTAsMVTAgg(_ []*types.T, evalCtx *eval.Context, arguments tree.Datums) eval.AggregateFunc {
+	return &stAsMVTAgg{
+		acc:       evalCtx.Planner.Mon().MakeBoundAccount(),
+		arguments: arguments,
+	}
+}
+
+// init sets up the layer using the type of the first row and the optional
+// arguments of the aggregate.
+func (agg *stAsMVTAgg) init(row *tree.DTuple, args tree.Datums) error {
+	name, extent := "default", 4096
+	var geomName, idName string
+	if len(args) > 0 && args[0] != tree.DNull {
+		name = string(tree.MustBeDString(args[0]))
+	}
+	if len(args) > 1 && args[1] != tree.DNull {
+		extent = int(tree.MustBeDInt(args[1]))
+	}
+	if len(args) > 2 && args[2] != tree.DNull {
+		geomName = string(tree.MustBeDString(args[2]))
+	}
+	if len(args) > 3 && args[3] != tree.DNull {
+		idName = string(tree.MustBeDString(args[3]))
+	}
+	layer, err := geomfn.NewMVTLayer(name, extent)
+	if err != nil {
+		return err
+	}
+
+	typ := row.ResolvedType()
+	labels := typ.TupleLabels()
+	agg.colNames = make([]string, len(typ.TupleContents()))
+	for i := range agg.colNames {
+		if i < len(labels) && labels[i] != "" {
+			agg.colNames[i] = labels[i]
+		} else {
+			// Postgres names the columns of anonymous records this way.
+			agg.colNames[i] = fmt.Sprintf("f%d", i+1)
+		}
+	}
+
+	agg.geomIdx, agg.idIdx = -1, -1
+	for i, t := range typ.TupleContents() {
+		if t.Family() == types.GeometryFamily && (geomName == "" || agg.colNames[i] == geomName) {
+			agg.geomIdx = i
+			break
+		}
+	}
+	if agg.geomIdx == -1 {
+		if geomName != "" {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"could not find column %q of geometry type", geomName)
+		}
+		return pgerror.New(pgcode.InvalidParameterValue, "could not find column of geometry type")
+	}
+	if idName != "" {
+		for i, t := range typ.TupleContents() {
+			if t.Family() == types.IntFamily && agg.colNames[i] == idName {
+				agg.idIdx = i
+				break
+			}
+		}
+		if agg.idIdx == -1 {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"could not find column %q of integer type", idName)
+		}
+	}
+	agg.layer = layer
+	return nil
+}
+
+// Add implements the AggregateFunc interface.
+func (agg *stAsMVTAgg) Add(ctx context.Context, firstArg tree.Datum, otherArgs ...tree.Datum) error {
+	if firstArg == tree.DNull {
+		return nil
+	}
+	row := tree.MustBeDTuple(firstArg)
+	if agg.layer == nil {
+		args := append(append(tree.Datums(nil), otherArgs...), agg.arguments...)
+		if err := agg.init(row, args); err != nil {
+			return err
+		}
+	}
+	if row.D[agg.geomIdx] == tree.DNull {
+		return nil
+	}
+	g := tree.MustBeDGeometry(row.D[agg.geomIdx]).Geometry
+
+	var id *uint64
+	if agg.idIdx >= 0 && row.D[agg.idIdx] != tree.DNull {
+		// Negative IDs cannot be represented, so they are ignored like in
+		// PostGIS.
+		if v := int64(tree.MustBeDInt(row.D[agg.idIdx])); v >= 0 {
+			u := uint64(v)
+			id = &u
+		}
+	}
+
+	contents := row.ResolvedType().TupleContents()
+	agg.attrs = agg.attrs[:0]
+	for i, d := range row.D {
+		if i == agg.geomIdx || i == agg.idIdx || d == tree.DNull {
+			continue
+		}
+		var err error
+		agg.attrs, err = appendMVTAttributes(agg.attrs, agg.colNames[i], contents[i], d)
+		if err != nil {
+			return err
+		}
+	}
+	if err := agg.layer.AddFeature(g, id, agg.attrs); err != nil {
+		return err
+	}
+	return agg.acc.ResizeTo(ctx, agg.layer.Size())
+}
+
+// appendMVTAttributes appends the attributes encoding the datum d of the
+// column with the given name and type.
+func appendMVTAttributes(
+	attrs []geomfn.MVTAttribute, key string, typ *types.T, d tree.Datum,
+) ([]geomfn.MVTAttribute, error) {
+	var v geomfn.MVTValue
+	switch t := tree.UnwrapDOidWrapper(d).(type) {
+	case *tree.DBool:
+		v = geomfn.MakeMVTBoolValue(bool(*t))
+	case *tree.DInt:
+		v = geomfn.MakeMVTIntValue(int64(*t))
+	case *tree.DFloat:
+		if typ.Width() == 32 {
+			v = geomfn.MakeMVTFloatValue(float32(*t))
+		} else {
+			v = geomfn.MakeMVTDoubleValue(float64(*t))
+		}
+	case *tree.DString:
+		v = geomfn.MakeMVTStringValue(string(*t))
+	case *tree.DJSON:
+		// The top-level keys of JSON objects are encoded as separate
+		// attributes. Nested objects and arrays, as well as nulls, are skipped.
+		it, err := t.JSON.ObjectIter()
+		if err != nil || it == nil {
+			return attrs, err
+		}
+		for it.Next() {
+			var v geomfn.MVTValue
+			switch j := it.Value(); j.Type() {
+			case json.StringJSONType:
+				s, err := j.AsText()
+				if err != nil {
+					return attrs, err
+				}
+				v = geomfn.MakeMVTStringValue(*s)
+			case json.NumberJSONType:
+				dec, _ := j.AsDecimal()
+				if i, err := dec.Int64(); err == nil {
+					v = geomfn.MakeMVTIntValue(i)
+				} else {
+					f, err := dec.Float64()
+					if err != nil {
+						return attrs, err
+					}
+					v = geomfn.MakeMVTDoubleValue(f)
+				}
+			case json.TrueJSONType, json.FalseJSONType:
+				v = geomfn.MakeMVTBoolValue(j.Type() == json.TrueJSONType)
+			default:
+				continue
+			}
+			attrs = append(attrs, geomfn.MVTAttribute{Key: it.Key(), Value: v})
+		}
+		return attrs, nil
+	default:
+		v = geomfn.MakeMVTStringValue(tree.AsStringWithFlags(d, tree.FmtPgwireText))
+	}
+	return append(attrs, geomfn.MVTAttribute{Key: key, Value: v}), nil
+}
+
+// Result implements the AggregateFunc interface.
+func (agg *stAsMVTAgg) Result() (tree.Datum, error) {
+	if agg.layer == nil {
+		// Like in PostGIS, an empty tile is returned if there were no rows.
+		return tree.NewDBytes(""), nil
+	}
+	return tree.NewDBytes(tree.DBytes(agg.layer.Encode())), nil
+}
+
+// Reset implements the AggregateFunc interface.
+func (agg *stAsMVTAgg) Reset(ctx context.Context) {
+	agg.layer = nil
+	agg.acc.Empty(ctx)
+}
+
+// Close implements the AggregateFunc interface.
+func (agg *stAsMVTAgg) Close(ctx context.Context) {
+	agg.acc.Close(ctx)
+}
+
+// Size implements the AggregateFunc interface.
+func (agg *stAsMVTAgg) Size() int64 {
+	return sizeOfSTAsMVTAggregate
+}
+
 func makeVarianceBuiltin() builtinDefinition {
 	return makeBuiltin(tree.FunctionProperties{},
 		makeImmutableAggOverload([]*types.T{types.Int}, types.Decimal, newIntVarianceAggregate,
@@ -1271,6 +1536,7 @@ var _ eval.AggregateFunc = &percentileContAggregate{}
 var _ eval.AggregateFunc = &stMakeLineAgg{}
 var _ eval.AggregateFunc = &stUnionAgg{}
 var _ eval.AggregateFunc = &stExtentAgg{}
+var _ eval.AggregateFunc = &stAsMVTAgg{}
 var _ eval.AggregateFunc = &regressionAccumulatorDecimalBase{}
 var _ eval.AggregateFunc = &finalRegressionAccumulatorDecimalBase{}
 var _ eval.AggregateFunc = &covarPopAggregate{}
@@ -1340,6 +1606,7 @@ const sizeOfSTMakeLineAggregate = int64(unsafe.Sizeof(stMakeLineAgg{}))
 const sizeOfSTUnionAggregate = int64(unsafe.Sizeof(stUnionAgg{}))
 const sizeOfSTCollectAggregate = int64(unsafe.Sizeof(stCollectAgg{}))
 const sizeOfSTExtentAggregate = int64(unsafe.Sizeof(stExtentAgg{}))
+const sizeOfSTAsMVTAggregate = int64(unsafe.Sizeof(stAsMVTAgg{}))
 
 // aggregateWithIntermediateResult is a common interface for aggregate functions
 // which can return a result without loss of precision. This is useful when an
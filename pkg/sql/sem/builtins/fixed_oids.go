@@ -2463,6 +2463,69 @@ var builtinOidsArray = []string{
 	2492: `make_timestamptz(year: int, month: int, day: int, hour: int, min: int, sec: float, timezone: string) -> timestamptz`,
 	2493: `date_trunc(element: string, input: timestamptz, timezone: string) -> timestamptz`,
 	2494: `make_date(year: int, month: int, day: int) -> date`,
+	2495: `pg_notify(channel: string, payload: string) -> void`,
+	2496: `pg_listening_channels() -> string`,
+	2497: `jsonb_path_exists(target: jsonb, path: jsonpath) -> bool`,
+	2498: `jsonb_path_exists(target: jsonb, path: jsonpath, vars: jsonb) -> bool`,
+	2499: `jsonb_path_exists(target: jsonb, path: jsonpath, vars: jsonb, silent: bool) -> bool`,
+	2500: `jsonb_path_match(target: jsonb, path: jsonpath) -> bool`,
+	2501: `jsonb_path_match(target: jsonb, path: jsonpath, vars: jsonb) -> bool`,
+	2502: `jsonb_path_match(target: jsonb, path: jsonpath, vars: jsonb, silent: bool) -> bool`,
+	2503: `jsonb_path_query_array(target: jsonb, path: jsonpath) -> jsonb`,
+	2504: `jsonb_path_query_array(target: jsonb, path: jsonpath, vars: jsonb) -> jsonb`,
+	2505: `jsonb_path_query_array(target: jsonb, path: jsonpath, vars: jsonb, silent: bool) -> jsonb`,
+	2506: `jsonb_path_query_first(target: jsonb, path: jsonpath) -> jsonb`,
+	2507: `jsonb_path_query_first(target: jsonb, path: jsonpath, vars: jsonb) -> jsonb`,
+	2508: `jsonb_path_query_first(target: jsonb, path: jsonpath, vars: jsonb, silent: bool) -> jsonb`,
+	2509: `jsonb_path_query(target: jsonb, path: jsonpath) -> jsonb`,
+	2510: `jsonb_path_query(target: jsonb, path: jsonpath, vars: jsonb) -> jsonb`,
+	2511: `jsonb_path_query(target: jsonb, path: jsonpath, vars: jsonb, silent: bool) -> jsonb`,
+	2512: `jsonpathrecv(input: anyelement) -> jsonpath`,
+	2513: `jsonpathout(jsonpath: jsonpath) -> bytes`,
+	2514: `jsonpathin(input: anyelement) -> jsonpath`,
+	2515: `jsonpath(string: string) -> jsonpath`,
+	2516: `jsonpath(jsonpath: jsonpath) -> jsonpath`,
+	2517: `varchar(jsonpath: jsonpath) -> varchar`,
+	2518: `text(jsonpath: jsonpath) -> string`,
+	2519: `bpchar(jsonpath: jsonpath) -> char`,
+	2520: `name(jsonpath: jsonpath) -> name`,
+	2521: `char(jsonpath: jsonpath) -> "char"`,
+	2522: `jsonpathsend(jsonpath: jsonpath) -> bytes`,
+	2523: `cosine_distance(v1: vector, v2: vector) -> float`,
+	2524: `inner_product(v1: vector, v2: vector) -> float`,
+	2525: `l1_distance(v1: vector, v2: vector) -> float`,
+	2526: `l2_distance(v1: vector, v2: vector) -> float`,
+	2527: `vector_dims(vector: vector) -> int`,
+	2528: `vector_norm(vector: vector) -> float`,
+	2529: `vectorrecv(input: anyelement) -> vector`,
+	2530: `vectorout(vector: vector) -> bytes`,
+	2531: `vectorin(input: anyelement) -> vector`,
+	2532: `vector(string: string) -> vector`,
+	2533: `vector(vector: vector) -> vector`,
+	2534: `varchar(vector: vector) -> varchar`,
+	2535: `text(vector: vector) -> string`,
+	2536: `bpchar(vector: vector) -> char`,
+	2537: `name(vector: vector) -> name`,
+	2538: `char(vector: vector) -> "char"`,
+	2539: `vectorsend(vector: vector) -> bytes`,
+	2540: `websearch_to_tsquery(config: string, text: string) -> tsquery`,
+	2541: `websearch_to_tsquery(text: string) -> tsquery`,
+	2542: `ts_rank_cd(weights: float[], vector: tsvector, query: tsquery, normalization: int) -> float4`,
+	2543: `ts_rank_cd(weights: float[], vector: tsvector, query: tsquery) -> float4`,
+	2544: `ts_rank_cd(vector: tsvector, query: tsquery, normalization: int) -> float4`,
+	2545: `ts_rank_cd(vector: tsvector, query: tsquery) -> float4`,
+	2546: `ts_headline(config: string, document: string, query: tsquery, options: string) -> string`,
+	2547: `ts_headline(config: string, document: string, query: tsquery) -> string`,
+	2548: `ts_headline(document: string, query: tsquery, options: string) -> string`,
+	2549: `ts_headline(document: string, query: tsquery) -> string`,
+	2550: `setweight(vector: tsvector, weight: "char") -> tsvector`,
+	2551: `setweight(vector: tsvector, weight: "char", lexemes: string[]) -> tsvector`,
+	2552: `ts_rewrite(query: tsquery, target: tsquery, substitute: tsquery) -> tsquery`,
+	2553: `st_asmvt(arg1: tuple) -> bytes`,
+	2554: `st_asmvt(arg1: tuple, arg2: string) -> bytes`,
+	2555: `st_asmvt(arg1: tuple, arg2: string, arg3: int) -> bytes`,
+	2556: `st_asmvt(arg1: tuple, arg2: string, arg3: int, arg4: string) -> bytes`,
+	2557: `st_asmvt(arg1: tuple, arg2: string, arg3: int, arg4: string, arg5: string) -> bytes`,
 }
 
 var builtinOidsBySignature map[string]oid.Oid
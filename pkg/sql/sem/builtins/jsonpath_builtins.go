@@ -0,0 +1,229 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
+)
+
+func init() {
+	for k, v := range jsonpathBuiltins {
+		v.props.Category = builtinconstants.CategoryJSON
+		v.props.AvailableOnPublicSchema = true
+		// Most builtins in this file are of the Normal class, but
+		// jsonb_path_query is of the Generator class.
+		const enforceClass = false
+		registerBuiltin(k, v, tree.NormalClass, enforceClass)
+	}
+}
+
+// jsonpathParamTypes returns the parameter types of the overloads of the
+// jsonb_path_* builtins. Like in Postgres, the vars and silent arguments are
+// optional.
+func jsonpathParamTypes() []tree.ParamTypes {
+	return []tree.ParamTypes{
+		{
+			{Name: "target", Typ: types.Jsonb},
+			{Name: "path", Typ: types.Jsonpath},
+		},
+		{
+			{Name: "target", Typ: types.Jsonb},
+			{Name: "path", Typ: types.Jsonpath},
+			{Name: "vars", Typ: types.Jsonb},
+		},
+		{
+			{Name: "target", Typ: types.Jsonb},
+			{Name: "path", Typ: types.Jsonpath},
+			{Name: "vars", Typ: types.Jsonb},
+			{Name: "silent", Typ: types.Bool},
+		},
+	}
+}
+
+// jsonpathArgs unpacks the arguments of the jsonb_path_* builtins.
+func jsonpathArgs(
+	args tree.Datums,
+) (target json.JSON, jp *jsonpath.Jsonpath, vars json.JSON, silent bool) {
+	target = tree.MustBeDJSON(args[0]).JSON
+	jp = &tree.MustBeDJsonpath(args[1]).Jsonpath
+	if len(args) > 2 {
+		vars = tree.MustBeDJSON(args[2]).JSON
+	}
+	if len(args) > 3 {
+		silent = bool(tree.MustBeDBool(args[3]))
+	}
+	return target, jp, vars, silent
+}
+
+// makeJsonpathBuiltin returns a builtin with an overload for each form of
+// jsonpathParamTypes.
+func makeJsonpathBuiltin(
+	retType *types.T,
+	fn func(target json.JSON, jp *jsonpath.Jsonpath, vars json.JSON, silent bool) (tree.Datum, error),
+	info string,
+) builtinDefinition {
+	var overloads []tree.Overload
+	for _, params := range jsonpathParamTypes() {
+		overloads = append(overloads, tree.Overload{
+			Types:      params,
+			ReturnType: tree.FixedReturnType(retType),
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return fn(jsonpathArgs(args))
+			},
+			Info:       info,
+			Volatility: volatility.Immutable,
+		})
+	}
+	return makeBuiltin(tree.FunctionProperties{}, overloads...)
+}
+
+const jsonpathArgsInfo = " If the vars argument is specified, it must be a JSON object whose " +
+	"fields provide named values to be substituted into the path expression. " +
+	"If the silent argument is specified and is true, the function suppresses " +
+	"the same errors as the @? and @@ operators do."
+
+var jsonpathBuiltins = map[string]builtinDefinition{
+	"jsonb_path_exists": makeJsonpathBuiltin(
+		types.Bool,
+		func(target json.JSON, jp *jsonpath.Jsonpath, vars json.JSON, silent bool) (tree.Datum, error) {
+			res, isNull, err := jsonpath.Exists(jp, target, vars, silent)
+			if err != nil || isNull {
+				return tree.DNull, err
+			}
+			return tree.MakeDBool(tree.DBool(res)), nil
+		},
+		"Checks whether the JSON path returns any item for the specified JSON value."+jsonpathArgsInfo,
+	),
+
+	"jsonb_path_match": makeJsonpathBuiltin(
+		types.Bool,
+		func(target json.JSON, jp *jsonpath.Jsonpath, vars json.JSON, silent bool) (tree.Datum, error) {
+			res, isNull, err := jsonpath.Match(jp, target, vars, silent)
+			if err != nil || isNull {
+				return tree.DNull, err
+			}
+			return tree.MakeDBool(tree.DBool(res)), nil
+		},
+		"Returns the result of a JSON path predicate check for the specified JSON value. "+
+			"Only the first item of the result is taken into account. If the result is "+
+			"not Boolean, then NULL is returned."+jsonpathArgsInfo,
+	),
+
+	"jsonb_path_query_array": makeJsonpathBuiltin(
+		types.Jsonb,
+		func(target json.JSON, jp *jsonpath.Jsonpath, vars json.JSON, silent bool) (tree.Datum, error) {
+			res, err := jsonpath.Query(jp, target, vars, silent)
+			if err != nil {
+				return nil, err
+			}
+			b := json.NewArrayBuilder(len(res))
+			for _, j := range res {
+				b.Add(j)
+			}
+			return tree.NewDJSON(b.Build()), nil
+		},
+		"Returns all JSON items returned by the JSON path for the specified JSON value, "+
+			"as a JSON array."+jsonpathArgsInfo,
+	),
+
+	"jsonb_path_query_first": makeJsonpathBuiltin(
+		types.Jsonb,
+		func(target json.JSON, jp *jsonpath.Jsonpath, vars json.JSON, silent bool) (tree.Datum, error) {
+			res, err := jsonpath.Query(jp, target, vars, silent)
+			if err != nil || len(res) == 0 {
+				return tree.DNull, err
+			}
+			return tree.NewDJSON(res[0]), nil
+		},
+		"Returns the first JSON item returned by the JSON path for the specified JSON value. "+
+			"Returns NULL if there are no results."+jsonpathArgsInfo,
+	),
+
+	"jsonb_path_query": func() builtinDefinition {
+		var overloads []tree.Overload
+		for _, params := range jsonpathParamTypes() {
+			overloads = append(overloads, makeGeneratorOverload(
+				params,
+				types.Jsonb,
+				makeJsonpathQueryGenerator,
+				"Returns all JSON items returned by the JSON path for the specified JSON value."+
+					jsonpathArgsInfo,
+				volatility.Immutable,
+			))
+		}
+		return makeBuiltin(genProps(), overloads...)
+	}(),
+}
+
+// jsonpathQueryGenerator is the generator for jsonb_path_query. The path is
+// evaluated in its entirety when the generator is started.
+type jsonpathQueryGenerator struct {
+	target json.JSON
+	jp     *jsonpath.Jsonpath
+	vars   json.JSON
+	silent bool
+
+	res     []json.JSON
+	nextIdx int
+	buf     [1]tree.Datum
+}
+
+var _ eval.ValueGenerator = &jsonpathQueryGenerator{}
+
+func makeJsonpathQueryGenerator(
+	_ context.Context, _ *eval.Context, args tree.Datums,
+) (eval.ValueGenerator, error) {
+	target, jp, vars, silent := jsonpathArgs(args)
+	return &jsonpathQueryGenerator{target: target, jp: jp, vars: vars, silent: silent}, nil
+}
+
+// ResolvedType implements the eval.ValueGenerator interface.
+func (g *jsonpathQueryGenerator) ResolvedType() *types.T {
+	return types.Jsonb
+}
+
+// Start implements the eval.ValueGenerator interface.
+func (g *jsonpathQueryGenerator) Start(_ context.Context, _ *kv.Txn) error {
+	res, err := jsonpath.Query(g.jp, g.target, g.vars, g.silent)
+	if err != nil {
+		return err
+	}
+	g.res = res
+	g.nextIdx = -1
+	return nil
+}
+
+// Next implements the eval.ValueGenerator interface.
+func (g *jsonpathQueryGenerator) Next(_ context.Context) (bool, error) {
+	g.nextIdx++
+	if g.nextIdx >= len(g.res) {
+		return false, nil
+	}
+	g.buf[0] = tree.NewDJSON(g.res[g.nextIdx])
+	return true, nil
+}
+
+// Values implements the eval.ValueGenerator interface.
+func (g *jsonpathQueryGenerator) Values() (tree.Datums, error) {
+	return g.buf[:], nil
+}
+
+// Close implements the eval.ValueGenerator interface.
+func (g *jsonpathQueryGenerator) Close(_ context.Context) {}
@@ -234,6 +234,45 @@ var tsearchBuiltins = map[string]builtinDefinition{
 			Volatility: volatility.Stable,
 		},
 	),
+	"websearch_to_tsquery": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "config", Typ: types.String}, {Name: "text", Typ: types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config := string(tree.MustBeDString(args[0]))
+				input := string(tree.MustBeDString(args[1]))
+				query, err := tsearch.WebSearchToTSQuery(config, input)
+				if err != nil {
+					return nil, err
+				}
+				return &tree.DTSQuery{TSQuery: query}, nil
+			},
+			Info: "Converts text to a tsquery, normalizing words according to the specified configuration." +
+				" Quoted word sequences are converted to <-> operators, the word \"or\" is converted to the" +
+				" | operator, a dash is converted to the ! operator, and the & operator is inserted between" +
+				" all other tokens.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "text", Typ: types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config := tsearch.GetConfigKey(evalCtx.SessionData().DefaultTextSearchConfig)
+				input := string(tree.MustBeDString(args[0]))
+				query, err := tsearch.WebSearchToTSQuery(config, input)
+				if err != nil {
+					return nil, err
+				}
+				return &tree.DTSQuery{TSQuery: query}, nil
+			},
+			Info: "Converts text to a tsquery, normalizing words according to the default configuration." +
+				" Quoted word sequences are converted to <-> operators, the word \"or\" is converted to the" +
+				" | operator, a dash is converted to the ! operator, and the & operator is inserted between" +
+				" all other tokens.",
+			Volatility: volatility.Stable,
+		},
+	),
 	"ts_rank": makeBuiltin(
 		tree.FunctionProperties{},
 		tree.Overload{
@@ -333,6 +372,273 @@ var tsearchBuiltins = map[string]builtinDefinition{
 			Volatility: volatility.Immutable,
 		},
 	),
+	"ts_rank_cd": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "weights", Typ: types.FloatArray},
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "query", Typ: types.TSQuery},
+				{Name: "normalization", Typ: types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				weights, err := getWeights(tree.MustBeDArray(args[0]))
+				if err != nil {
+					return nil, err
+				}
+				rank, err := tsearch.RankCD(
+					weights,
+					tree.MustBeDTSVector(args[1]).TSVector,
+					tree.MustBeDTSQuery(args[2]).TSQuery,
+					int(tree.MustBeDInt(args[3])),
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDFloat(tree.DFloat(rank)), nil
+			},
+			Info:       rankCDInfo,
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "weights", Typ: types.FloatArray},
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "query", Typ: types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				weights, err := getWeights(tree.MustBeDArray(args[0]))
+				if err != nil {
+					return nil, err
+				}
+				rank, err := tsearch.RankCD(
+					weights,
+					tree.MustBeDTSVector(args[1]).TSVector,
+					tree.MustBeDTSQuery(args[2]).TSQuery,
+					0, /* method */
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDFloat(tree.DFloat(rank)), nil
+			},
+			Info:       rankCDInfo,
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "query", Typ: types.TSQuery},
+				{Name: "normalization", Typ: types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				rank, err := tsearch.RankCD(
+					nil, /* weights */
+					tree.MustBeDTSVector(args[0]).TSVector,
+					tree.MustBeDTSQuery(args[1]).TSQuery,
+					int(tree.MustBeDInt(args[2])),
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDFloat(tree.DFloat(rank)), nil
+			},
+			Info:       rankCDInfo,
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "query", Typ: types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				rank, err := tsearch.RankCD(
+					nil, /* weights */
+					tree.MustBeDTSVector(args[0]).TSVector,
+					tree.MustBeDTSQuery(args[1]).TSQuery,
+					0, /* method */
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDFloat(tree.DFloat(rank)), nil
+			},
+			Info:       rankCDInfo,
+			Volatility: volatility.Immutable,
+		},
+	),
+	"ts_headline": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "config", Typ: types.String},
+				{Name: "document", Typ: types.String},
+				{Name: "query", Typ: types.TSQuery},
+				{Name: "options", Typ: types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				headline, err := tsearch.Headline(
+					string(tree.MustBeDString(args[0])),
+					string(tree.MustBeDString(args[1])),
+					tree.MustBeDTSQuery(args[2]).TSQuery,
+					string(tree.MustBeDString(args[3])),
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDString(headline), nil
+			},
+			Info:       headlineInfo + headlineOptionsInfo,
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "config", Typ: types.String},
+				{Name: "document", Typ: types.String},
+				{Name: "query", Typ: types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				headline, err := tsearch.Headline(
+					string(tree.MustBeDString(args[0])),
+					string(tree.MustBeDString(args[1])),
+					tree.MustBeDTSQuery(args[2]).TSQuery,
+					"", /* options */
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDString(headline), nil
+			},
+			Info:       headlineInfo,
+			Volatility: volatility.Immutable,
+			// Postgres takes a regconfig as the config, so the volatility check
+			// confuses this overload with ts_headline(document, query, options),
+			// which is Stable.
+			IgnoreVolatilityCheck: true,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "document", Typ: types.String},
+				{Name: "query", Typ: types.TSQuery},
+				{Name: "options", Typ: types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				headline, err := tsearch.Headline(
+					tsearch.GetConfigKey(evalCtx.SessionData().DefaultTextSearchConfig),
+					string(tree.MustBeDString(args[0])),
+					tree.MustBeDTSQuery(args[1]).TSQuery,
+					string(tree.MustBeDString(args[2])),
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDString(headline), nil
+			},
+			Info:       headlineInfo + headlineOptionsInfo,
+			Volatility: volatility.Stable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "document", Typ: types.String},
+				{Name: "query", Typ: types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				headline, err := tsearch.Headline(
+					tsearch.GetConfigKey(evalCtx.SessionData().DefaultTextSearchConfig),
+					string(tree.MustBeDString(args[0])),
+					tree.MustBeDTSQuery(args[1]).TSQuery,
+					"", /* options */
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDString(headline), nil
+			},
+			Info:       headlineInfo,
+			Volatility: volatility.Stable,
+		},
+	),
+	"setweight": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "weight", Typ: types.QChar},
+			},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				vector, err := tsearch.SetWeight(
+					tree.MustBeDTSVector(args[0]).TSVector,
+					getWeightChar(tree.MustBeDString(args[1])),
+					nil, /* lexemes */
+				)
+				if err != nil {
+					return nil, err
+				}
+				return &tree.DTSVector{TSVector: vector}, nil
+			},
+			Info:       "Assigns the given weight to each element of the vector.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "weight", Typ: types.QChar},
+				{Name: "lexemes", Typ: types.StringArray},
+			},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				arr := tree.MustBeDArray(args[2])
+				lexemes := make([]string, arr.Len())
+				for i, d := range arr.Array {
+					if d == tree.DNull {
+						return nil, pgerror.New(pgcode.NullValueNotAllowed, "lexeme array may not contain nulls")
+					}
+					lexemes[i] = string(tree.MustBeDString(d))
+				}
+				vector, err := tsearch.SetWeight(
+					tree.MustBeDTSVector(args[0]).TSVector,
+					getWeightChar(tree.MustBeDString(args[1])),
+					lexemes,
+				)
+				if err != nil {
+					return nil, err
+				}
+				return &tree.DTSVector{TSVector: vector}, nil
+			},
+			Info:       "Assigns the given weight to the elements of the vector that are listed in lexemes.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"ts_rewrite": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "query", Typ: types.TSQuery},
+				{Name: "target", Typ: types.TSQuery},
+				{Name: "substitute", Typ: types.TSQuery},
+			},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				query := tsearch.Rewrite(
+					tree.MustBeDTSQuery(args[0]).TSQuery,
+					tree.MustBeDTSQuery(args[1]).TSQuery,
+					tree.MustBeDTSQuery(args[2]).TSQuery,
+				)
+				return &tree.DTSQuery{TSQuery: query}, nil
+			},
+			Info:       "Replaces occurrences of target with substitute within the query.",
+			Volatility: volatility.Immutable,
+		},
+	),
 }
 
 func getWeights(arr *tree.DArray) ([]float32, error) {
@@ -348,3 +654,21 @@ func getWeights(arr *tree.DArray) ([]float32, error) {
 	}
 	return ret, nil
 }
+
+// getWeightChar returns the weight character of the "char" argument of
+// setweight. An empty argument returns 0, which is an invalid weight.
+func getWeightChar(d tree.DString) byte {
+	if len(d) == 0 {
+		return 0
+	}
+	return d[0]
+}
+
+const rankCDInfo = "Ranks vectors based on the frequency and proximity of their matching lexemes, " +
+	"using the cover density ranking method. The vector must contain positional information."
+
+const headlineInfo = "Returns an excerpt of the document in which the terms of the query are highlighted."
+
+const headlineOptionsInfo = " The options are a comma-separated list of option=value pairs, which " +
+	"may include StartSel, StopSel, MaxWords, MinWords, ShortWord, HighlightAll, MaxFragments and " +
+	"FragmentDelimiter."
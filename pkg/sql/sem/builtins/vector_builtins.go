@@ -0,0 +1,102 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
+)
+
+func init() {
+	for k, v := range vectorBuiltins {
+		v.props.Category = builtinconstants.CategoryVector
+		v.props.AvailableOnPublicSchema = true
+		const enforceClass = true
+		registerBuiltin(k, v, tree.NormalClass, enforceClass)
+	}
+}
+
+// makeVectorDistanceBuiltin returns a builtin that computes a distance
+// between two vectors.
+func makeVectorDistanceBuiltin(fn func(a, b vector.T) (float64, error), info string) builtinDefinition {
+	return makeBuiltin(defProps(),
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "v1", Typ: types.PGVector},
+				{Name: "v2", Typ: types.PGVector},
+			},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				v1 := tree.MustBeDPGVector(args[0])
+				v2 := tree.MustBeDPGVector(args[1])
+				dist, err := fn(v1.T, v2.T)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDFloat(tree.DFloat(dist)), nil
+			},
+			Info:       info,
+			Volatility: volatility.Immutable,
+		},
+	)
+}
+
+var vectorBuiltins = map[string]builtinDefinition{
+	"l2_distance": makeVectorDistanceBuiltin(
+		vector.L2Distance,
+		"Returns the Euclidean distance between the two vectors. "+
+			"This is equivalent to the <-> operator.",
+	),
+	"l1_distance": makeVectorDistanceBuiltin(
+		vector.L1Distance,
+		"Returns the taxicab distance between the two vectors.",
+	),
+	"cosine_distance": makeVectorDistanceBuiltin(
+		vector.CosDistance,
+		"Returns the cosine distance between the two vectors, which is one minus "+
+			"their cosine similarity. This is equivalent to the <=> operator.",
+	),
+	"inner_product": makeVectorDistanceBuiltin(
+		vector.InnerProduct,
+		"Returns the inner product of the two vectors. The <#> operator returns "+
+			"the negative of this value.",
+	),
+	"vector_dims": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "vector", Typ: types.PGVector}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				v := tree.MustBeDPGVector(args[0])
+				return tree.NewDInt(tree.DInt(len(v.T))), nil
+			},
+			Info:       "Returns the number of dimensions of the vector.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"vector_norm": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "vector", Typ: types.PGVector}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				v := tree.MustBeDPGVector(args[0])
+				return tree.NewDFloat(tree.DFloat(vector.Norm(v.T))), nil
+			},
+			Info:       "Returns the Euclidean norm of the vector.",
+			Volatility: volatility.Immutable,
+		},
+	),
+}
@@ -293,6 +293,25 @@ func LookupCast(src, tgt *types.T) (Cast, bool) {
 		}, true
 	}
 
+	// Casts between vectors and arrays of numbers are allowed in assignment
+	// contexts, as in pgvector.
+	if srcFamily == types.ArrayFamily && tgtFamily == types.PGVectorFamily {
+		switch src.ArrayContents().Family() {
+		case types.IntFamily, types.FloatFamily, types.DecimalFamily:
+			return Cast{
+				MaxContext: ContextAssignment,
+				Volatility: volatility.Immutable,
+			}, true
+		}
+	}
+	if srcFamily == types.PGVectorFamily && tgtFamily == types.ArrayFamily &&
+		tgt.ArrayContents().Family() == types.FloatFamily {
+		return Cast{
+			MaxContext: ContextAssignment,
+			Volatility: volatility.Immutable,
+		}, true
+	}
+
 	if tgts, ok := castMap[src.Oid()]; ok {
 		if c, ok := tgts[tgt.Oid()]; ok {
 			return c, true
@@ -29,9 +29,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/trigram"
 	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
 	"github.com/cockroachdb/errors"
 )
 
@@ -277,6 +279,19 @@ func (e *evaluator) EvalConcatStringOp(
 
 }
 
+func (e *evaluator) EvalConcatTSVectorOp(
+	ctx context.Context, _ *tree.ConcatTSVectorOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	v, err := tsearch.Concat(
+		tree.MustBeDTSVector(left).TSVector,
+		tree.MustBeDTSVector(right).TSVector,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &tree.DTSVector{TSVector: v}, nil
+}
+
 func (e *evaluator) EvalConcatVarBitOp(
 	ctx context.Context, _ *tree.ConcatVarBitOp, left, right tree.Datum,
 ) (tree.Datum, error) {
@@ -1229,6 +1244,68 @@ func (e *evaluator) EvalTSMatchesVectorQueryOp(
 	return tree.MakeDBool(tree.DBool(ret)), err
 }
 
+func (e *evaluator) EvalJSONPathMatchOp(
+	ctx context.Context, _ *tree.JSONPathMatchOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	j := tree.MustBeDJSON(left)
+	jp := tree.MustBeDJsonpath(right)
+	// Like Postgres, the operator form suppresses errors that the function
+	// form would raise.
+	ret, isNull, err := jsonpath.Match(&jp.Jsonpath, j.JSON, nil /* vars */, true /* silent */)
+	if err != nil || isNull {
+		return tree.DNull, err
+	}
+	return tree.MakeDBool(tree.DBool(ret)), nil
+}
+
+func (e *evaluator) EvalJSONPathExistsOp(
+	ctx context.Context, _ *tree.JSONPathExistsOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	j := tree.MustBeDJSON(left)
+	jp := tree.MustBeDJsonpath(right)
+	ret, isNull, err := jsonpath.Exists(&jp.Jsonpath, j.JSON, nil /* vars */, true /* silent */)
+	if err != nil || isNull {
+		return tree.DNull, err
+	}
+	return tree.MakeDBool(tree.DBool(ret)), nil
+}
+
+func (e *evaluator) EvalDistanceVectorOp(
+	ctx context.Context, _ *tree.DistanceVectorOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	v := tree.MustBeDPGVector(left)
+	q := tree.MustBeDPGVector(right)
+	ret, err := vector.L2Distance(v.T, q.T)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDFloat(tree.DFloat(ret)), nil
+}
+
+func (e *evaluator) EvalCosDistanceVectorOp(
+	ctx context.Context, _ *tree.CosDistanceVectorOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	v := tree.MustBeDPGVector(left)
+	q := tree.MustBeDPGVector(right)
+	ret, err := vector.CosDistance(v.T, q.T)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDFloat(tree.DFloat(ret)), nil
+}
+
+func (e *evaluator) EvalNegInnerProductVectorOp(
+	ctx context.Context, _ *tree.NegInnerProductVectorOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	v := tree.MustBeDPGVector(left)
+	q := tree.MustBeDPGVector(right)
+	ret, err := vector.NegInnerProduct(v.T, q.T)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDFloat(tree.DFloat(ret)), nil
+}
+
 func (e *evaluator) EvalPlusDateIntOp(
 	ctx context.Context, _ *tree.PlusDateIntOp, left, right tree.Datum,
 ) (tree.Datum, error) {
@@ -35,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
 	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -489,6 +490,10 @@ func performCastWithoutPrecisionTruncation(
 			s = t.String()
 		case *tree.DJSON:
 			s = t.JSON.String()
+		case *tree.DJsonpath:
+			s = t.Jsonpath.String()
+		case *tree.DPGVector:
+			s = t.T.String()
 		case *tree.DTSQuery:
 			s = t.TSQuery.String()
 		case *tree.DTSVector:
@@ -870,6 +875,62 @@ func performCastWithoutPrecisionTruncation(
 			}
 			return tree.ParseDJSON(string(j))
 		}
+	case types.JsonpathFamily:
+		if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.V23_2_Jsonpath) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"version %v must be finalized to use jsonpath",
+				clusterversion.ByKey(clusterversion.V23_2_Jsonpath))
+		}
+		switch v := d.(type) {
+		case *tree.DString:
+			return tree.ParseDJsonpath(string(*v))
+		case *tree.DCollatedString:
+			return tree.ParseDJsonpath(v.Contents)
+		case *tree.DJsonpath:
+			return d, nil
+		}
+	case types.PGVectorFamily:
+		if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.V23_2_PGVector) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"version %v must be finalized to use vector",
+				clusterversion.ByKey(clusterversion.V23_2_PGVector))
+		}
+		switch v := d.(type) {
+		case *tree.DString:
+			return tree.ParseDPGVector(string(*v))
+		case *tree.DCollatedString:
+			return tree.ParseDPGVector(v.Contents)
+		case *tree.DPGVector:
+			return d, nil
+		case *tree.DArray:
+			fs := make([]float64, len(v.Array))
+			for i, e := range v.Array {
+				switch e := e.(type) {
+				case *tree.DInt:
+					fs[i] = float64(*e)
+				case *tree.DFloat:
+					fs[i] = float64(*e)
+				case *tree.DDecimal:
+					f, err := e.Float64()
+					if err != nil {
+						return nil, err
+					}
+					fs[i] = f
+				default:
+					if e == tree.DNull {
+						return nil, pgerror.New(pgcode.NullValueNotAllowed,
+							"array must not contain nulls")
+					}
+					return nil, pgerror.Newf(pgcode.CannotCoerce,
+						"invalid cast: %s -> %s", d.ResolvedType(), t)
+				}
+			}
+			vec, err := vector.FromFloats(fs)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDPGVector(vec), nil
+		}
 	case types.TSQueryFamily:
 		if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.V23_1) {
 			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
@@ -903,6 +964,18 @@ func performCastWithoutPrecisionTruncation(
 		case *tree.DString:
 			res, _, err := tree.ParseDArrayFromString(evalCtx, string(*v), t.ArrayContents())
 			return res, err
+		case *tree.DPGVector:
+			dcast := tree.NewDArray(t.ArrayContents())
+			for _, f := range v.T {
+				ecast, err := performCast(ctx, evalCtx, tree.NewDFloat(tree.DFloat(f)), t.ArrayContents(), truncateWidth)
+				if err != nil {
+					return nil, err
+				}
+				if err := dcast.Append(ecast); err != nil {
+					return nil, err
+				}
+			}
+			return dcast, nil
 		case *tree.DArray:
 			dcast := tree.NewDArray(t.ArrayContents())
 			if err := dcast.MaybeSetCustomOid(t); err != nil {
@@ -404,6 +404,14 @@ type Planner interface {
 
 	// Optimizer returns the optimizer associated with this Planner, if any.
 	Optimizer() interface{}
+
+	// SendNotification sends a notification on the given channel, as with
+	// NOTIFY. It is delivered to the listening sessions when the current
+	// transaction commits.
+	SendNotification(ctx context.Context, channel, payload string) error
+
+	// ListeningChannels returns the channels the session listens on.
+	ListeningChannels() []string
 }
 
 // InternalRows is an iterator interface that's exposed by the internal
@@ -70,6 +70,19 @@ func (node *AlterBackupKMS) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.KMSInfo.OldKMSURI)
 }
 
+func (node *AlterBackupCompact) alterBackupCmd() {}
+
+var _ AlterBackupCmd = &AlterBackupCompact{}
+
+// AlterBackupCompact represents an alter_backup_cmd that compacts a backup
+// chain into a new full backup.
+type AlterBackupCompact struct{}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterBackupCompact) Format(ctx *FmtCtx) {
+	ctx.WriteString(" COMPACT")
+}
+
 // BackupKMS represents possible options used when altering a backup KMS
 type BackupKMS struct {
 	NewKMSURI StringOrPlaceholderOptList
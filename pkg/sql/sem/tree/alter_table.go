@@ -76,6 +76,10 @@ func (*AlterTablePartitionByTable) alterTableCmd()   {}
 func (*AlterTableInjectStats) alterTableCmd()        {}
 func (*AlterTableSetStorageParams) alterTableCmd()   {}
 func (*AlterTableResetStorageParams) alterTableCmd() {}
+func (*AlterTableInherit) alterTableCmd()            {}
+func (*AlterTableNoInherit) alterTableCmd()          {}
+func (*AlterTableAttachPartition) alterTableCmd()    {}
+func (*AlterTableDetachPartition) alterTableCmd()    {}
 
 var _ AlterTableCmd = &AlterTableAddColumn{}
 var _ AlterTableCmd = &AlterTableAddConstraint{}
@@ -96,6 +100,10 @@ var _ AlterTableCmd = &AlterTablePartitionByTable{}
 var _ AlterTableCmd = &AlterTableInjectStats{}
 var _ AlterTableCmd = &AlterTableSetStorageParams{}
 var _ AlterTableCmd = &AlterTableResetStorageParams{}
+var _ AlterTableCmd = &AlterTableInherit{}
+var _ AlterTableCmd = &AlterTableNoInherit{}
+var _ AlterTableCmd = &AlterTableAttachPartition{}
+var _ AlterTableCmd = &AlterTableDetachPartition{}
 
 // ColumnMutationCmd is the subset of AlterTableCmds that modify an
 // existing column.
@@ -644,6 +652,75 @@ func (node *AlterTableResetStorageParams) Format(ctx *FmtCtx) {
 	ctx.WriteString(")")
 }
 
+// AlterTableInherit represents an ALTER TABLE INHERIT command.
+type AlterTableInherit struct {
+	Parent TableName
+}
+
+// TelemetryName implements the AlterTableCmd interface.
+func (node *AlterTableInherit) TelemetryName() string {
+	return "inherit"
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableInherit) Format(ctx *FmtCtx) {
+	ctx.WriteString(" INHERIT ")
+	ctx.FormatNode(&node.Parent)
+}
+
+// AlterTableNoInherit represents an ALTER TABLE NO INHERIT command.
+type AlterTableNoInherit struct {
+	Parent TableName
+}
+
+// TelemetryName implements the AlterTableCmd interface.
+func (node *AlterTableNoInherit) TelemetryName() string {
+	return "no_inherit"
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableNoInherit) Format(ctx *FmtCtx) {
+	ctx.WriteString(" NO INHERIT ")
+	ctx.FormatNode(&node.Parent)
+}
+
+// AlterTableAttachPartition represents an ALTER TABLE ATTACH PARTITION
+// command.
+type AlterTableAttachPartition struct {
+	Partition TableName
+	Bound     *PartitionBoundSpec
+}
+
+// TelemetryName implements the AlterTableCmd interface.
+func (node *AlterTableAttachPartition) TelemetryName() string {
+	return "attach_partition"
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableAttachPartition) Format(ctx *FmtCtx) {
+	ctx.WriteString(" ATTACH PARTITION ")
+	ctx.FormatNode(&node.Partition)
+	ctx.WriteByte(' ')
+	ctx.FormatNode(node.Bound)
+}
+
+// AlterTableDetachPartition represents an ALTER TABLE DETACH PARTITION
+// command.
+type AlterTableDetachPartition struct {
+	Partition TableName
+}
+
+// TelemetryName implements the AlterTableCmd interface.
+func (node *AlterTableDetachPartition) TelemetryName() string {
+	return "detach_partition"
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableDetachPartition) Format(ctx *FmtCtx) {
+	ctx.WriteString(" DETACH PARTITION ")
+	ctx.FormatNode(&node.Partition)
+}
+
 // AlterTableLocality represents an ALTER TABLE LOCALITY command.
 type AlterTableLocality struct {
 	Name     *UnresolvedObjectName
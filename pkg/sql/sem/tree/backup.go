@@ -150,6 +150,8 @@ type RestoreOptions struct {
 	ExecutionLocality                Expr
 	ExperimentalOnline               bool
 	RemoveRegions                    bool
+	Filter                           Expr
+	Columns                          StringOrPlaceholderOptList
 }
 
 var _ NodeFormatter = &RestoreOptions{}
@@ -540,6 +542,18 @@ func (o *RestoreOptions) Format(ctx *FmtCtx) {
 		maybeAddSep()
 		ctx.WriteString("remove_regions")
 	}
+
+	if o.Filter != nil {
+		maybeAddSep()
+		ctx.WriteString("filter = ")
+		ctx.FormatNode(o.Filter)
+	}
+
+	if o.Columns != nil {
+		maybeAddSep()
+		ctx.WriteString("columns = ")
+		ctx.FormatNode(&o.Columns)
+	}
 }
 
 // CombineWith merges other backup options into this backup options struct.
@@ -703,6 +717,18 @@ func (o *RestoreOptions) CombineWith(other *RestoreOptions) error {
 		o.RemoveRegions = other.RemoveRegions
 	}
 
+	if o.Filter == nil {
+		o.Filter = other.Filter
+	} else if other.Filter != nil {
+		return errors.New("filter option specified multiple times")
+	}
+
+	if o.Columns == nil {
+		o.Columns = other.Columns
+	} else if other.Columns != nil {
+		return errors.New("columns option specified multiple times")
+	}
+
 	return nil
 }
 
@@ -730,7 +756,9 @@ func (o RestoreOptions) IsDefault() bool {
 		o.UnsafeRestoreIncompatibleVersion == options.UnsafeRestoreIncompatibleVersion &&
 		o.ExecutionLocality == options.ExecutionLocality &&
 		o.ExperimentalOnline == options.ExperimentalOnline &&
-		o.RemoveRegions == options.RemoveRegions
+		o.RemoveRegions == options.RemoveRegions &&
+		o.Filter == options.Filter &&
+		cmp.Equal(o.Columns, options.Columns)
 }
 
 // BackupTargetList represents a list of targets.
@@ -546,6 +546,8 @@ var (
 		types.PGLSNArray,
 		types.TSQuery,
 		types.TSVector,
+		types.Jsonpath,
+		types.PGVector,
 		types.VarBit,
 		types.AnyEnum,
 		types.AnyEnumArray,
@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/collatedstring"
 	"github.com/cockroachdb/cockroach/pkg/util/pretty"
@@ -426,6 +427,7 @@ func (*IndexTableDef) tableDef()                {}
 func (*FamilyTableDef) tableDef()               {}
 func (*ForeignKeyConstraintTableDef) tableDef() {}
 func (*CheckConstraintTableDef) tableDef()      {}
+func (*ExclusionConstraintTableDef) tableDef()  {}
 func (*LikeTableDef) tableDef()                 {}
 
 // TableDefs represents a list of table definitions.
@@ -1102,6 +1104,7 @@ type ConstraintTableDef interface {
 func (*UniqueConstraintTableDef) constraintTableDef()     {}
 func (*ForeignKeyConstraintTableDef) constraintTableDef() {}
 func (*CheckConstraintTableDef) constraintTableDef()      {}
+func (*ExclusionConstraintTableDef) constraintTableDef()  {}
 
 // UniqueConstraintTableDef represents a unique constraint within a CREATE
 // TABLE statement.
@@ -1256,6 +1259,79 @@ func (node *CheckConstraintTableDef) Format(ctx *FmtCtx) {
 	ctx.WriteByte(')')
 }
 
+// ExclusionConstraintTableDef represents an EXCLUDE constraint within a
+// CREATE TABLE statement.
+type ExclusionConstraintTableDef struct {
+	Name Name
+	// IndexMethod is the index access method specified with USING, if any.
+	IndexMethod Name
+	Elems       ExclusionElemList
+	Predicate   Expr
+	IfNotExists bool
+}
+
+// SetName implements the ConstraintTableDef interface.
+func (node *ExclusionConstraintTableDef) SetName(name Name) {
+	node.Name = name
+}
+
+// SetIfNotExists implements the ConstraintTableDef interface.
+func (node *ExclusionConstraintTableDef) SetIfNotExists() {
+	node.IfNotExists = true
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExclusionConstraintTableDef) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		if node.IfNotExists {
+			ctx.WriteString("IF NOT EXISTS ")
+		}
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteString("EXCLUDE ")
+	if node.IndexMethod != "" {
+		ctx.WriteString("USING ")
+		ctx.FormatNode(&node.IndexMethod)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Elems)
+	ctx.WriteByte(')')
+	if node.Predicate != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.Predicate)
+	}
+}
+
+// ExclusionElem is a single element of an EXCLUDE constraint: an index
+// element and the operator that it is compared with.
+type ExclusionElem struct {
+	IndexElem
+	Operator treecmp.ComparisonOperator
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExclusionElem) Format(ctx *FmtCtx) {
+	ctx.FormatNode(&node.IndexElem)
+	ctx.WriteString(" WITH ")
+	ctx.WriteString(node.Operator.String())
+}
+
+// ExclusionElemList is a list of ExclusionElem.
+type ExclusionElemList []ExclusionElem
+
+// Format implements the NodeFormatter interface.
+func (l *ExclusionElemList) Format(ctx *FmtCtx) {
+	for i := range *l {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*l)[i])
+	}
+}
+
 // FamilyTableDef represents a family definition within a CREATE TABLE
 // statement.
 type FamilyTableDef struct {
@@ -1326,6 +1402,11 @@ type PartitionByTable struct {
 	All bool
 
 	*PartitionBy
+
+	// Key is set instead of PartitionBy for a PARTITION BY RANGE (cols) clause
+	// without partitions, which makes the table a partitioned table whose
+	// partitions are separate tables.
+	Key NameList
 }
 
 // Format implements the NodeFormatter interface.
@@ -1334,6 +1415,12 @@ func (node *PartitionByTable) Format(ctx *FmtCtx) {
 		ctx.WriteString(` PARTITION BY NOTHING`)
 		return
 	}
+	if node.Key != nil {
+		ctx.WriteString(` PARTITION BY RANGE (`)
+		ctx.FormatNode(&node.Key)
+		ctx.WriteByte(')')
+		return
+	}
 	ctx.WriteString(` PARTITION `)
 	if node.All {
 		ctx.WriteString(`ALL `)
@@ -1502,6 +1589,28 @@ type CreateTable struct {
 	Defs     TableDefs
 	AsSource *Select
 	Locality *Locality
+	// Inherits lists the tables named in an INHERITS clause.
+	Inherits TableNames
+	// PartitionOf is set in CREATE TABLE ... PARTITION OF queries, along with
+	// PartitionBound. Defs is empty in these queries.
+	PartitionOf    *TableName
+	PartitionBound *PartitionBoundSpec
+}
+
+// PartitionBoundSpec represents a FOR VALUES FROM (...) TO (...) clause, which
+// specifies the range of partition key values of a partition.
+type PartitionBoundSpec struct {
+	From Exprs
+	To   Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *PartitionBoundSpec) Format(ctx *FmtCtx) {
+	ctx.WriteString("FOR VALUES FROM (")
+	ctx.FormatNode(&node.From)
+	ctx.WriteString(") TO (")
+	ctx.FormatNode(&node.To)
+	ctx.WriteByte(')')
 }
 
 // As returns true if this table represents a CREATE TABLE ... AS statement,
@@ -1559,9 +1668,21 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 		ctx.WriteString(" AS ")
 		ctx.FormatNode(node.AsSource)
 	} else {
-		ctx.WriteString(" (")
-		ctx.FormatNode(&node.Defs)
-		ctx.WriteByte(')')
+		if node.PartitionOf != nil {
+			ctx.WriteString(" PARTITION OF ")
+			ctx.FormatNode(node.PartitionOf)
+			ctx.WriteByte(' ')
+			ctx.FormatNode(node.PartitionBound)
+		} else {
+			ctx.WriteString(" (")
+			ctx.FormatNode(&node.Defs)
+			ctx.WriteByte(')')
+		}
+		if len(node.Inherits) > 0 {
+			ctx.WriteString(" INHERITS (")
+			ctx.FormatNode(&node.Inherits)
+			ctx.WriteByte(')')
+		}
 		if node.PartitionByTable != nil {
 			ctx.FormatNode(node.PartitionByTable)
 		}
@@ -1997,6 +2118,10 @@ type RefreshMaterializedView struct {
 	Name              *UnresolvedObjectName
 	Concurrently      bool
 	RefreshDataOption RefreshDataOption
+	// Incrementally is set for REFRESH MATERIALIZED VIEW ... INCREMENTALLY,
+	// which applies only the changes made to the tables the view depends on
+	// since the view was last refreshed.
+	Incrementally bool
 }
 
 // RefreshDataOption corresponds to arguments for the REFRESH MATERIALIZED VIEW
@@ -2028,6 +2153,9 @@ func (node *RefreshMaterializedView) Format(ctx *FmtCtx) {
 	case RefreshDataClear:
 		ctx.WriteString(" WITH NO DATA")
 	}
+	if node.Incrementally {
+		ctx.WriteString(" INCREMENTALLY")
+	}
 }
 
 // CreateStats represents a CREATE STATISTICS statement.
@@ -559,21 +559,24 @@ func (v *UDFDisallowanceVisitor) VisitPost(expr Expr) (newNode Expr) {
 type SchemaExprContext string
 
 const (
-	AlterColumnTypeUsingExpr        SchemaExprContext = "ALTER COLUMN TYPE USING EXPRESSION"
-	StoredComputedColumnExpr        SchemaExprContext = "STORED COMPUTED COLUMN"
-	VirtualComputedColumnExpr       SchemaExprContext = "VIRTUAL COMPUTED COLUMN"
-	ColumnOnUpdateExpr              SchemaExprContext = "ON UPDATE"
-	ColumnDefaultExprInAddColumn    SchemaExprContext = "DEFAULT (in ADD COLUMN)"
-	ColumnDefaultExprInNewTable     SchemaExprContext = "DEFAULT (in CREATE TABLE)"
-	ColumnDefaultExprInNewView      SchemaExprContext = "DEFAULT (in CREATE VIEW)"
-	ColumnDefaultExprInSetDefault   SchemaExprContext = "DEFAULT (in SET DEFAULT)"
-	CheckConstraintExpr             SchemaExprContext = "CHECK"
-	UniqueWithoutIndexPredicateExpr SchemaExprContext = "UNIQUE WITHOUT INDEX PREDICATE"
-	IndexPredicateExpr              SchemaExprContext = "INDEX PREDICATE"
-	ExpressionIndexElementExpr      SchemaExprContext = "EXPRESSION INDEX ELEMENT"
-	TTLExpirationExpr               SchemaExprContext = "TTL EXPIRATION EXPRESSION"
-	TTLDefaultExpr                  SchemaExprContext = "TTL DEFAULT"
-	TTLUpdateExpr                   SchemaExprContext = "TTL UPDATE"
+	AlterColumnTypeUsingExpr         SchemaExprContext = "ALTER COLUMN TYPE USING EXPRESSION"
+	StoredComputedColumnExpr         SchemaExprContext = "STORED COMPUTED COLUMN"
+	VirtualComputedColumnExpr        SchemaExprContext = "VIRTUAL COMPUTED COLUMN"
+	ColumnOnUpdateExpr               SchemaExprContext = "ON UPDATE"
+	ColumnDefaultExprInAddColumn     SchemaExprContext = "DEFAULT (in ADD COLUMN)"
+	ColumnDefaultExprInNewTable      SchemaExprContext = "DEFAULT (in CREATE TABLE)"
+	ColumnDefaultExprInNewView       SchemaExprContext = "DEFAULT (in CREATE VIEW)"
+	ColumnDefaultExprInSetDefault    SchemaExprContext = "DEFAULT (in SET DEFAULT)"
+	CheckConstraintExpr              SchemaExprContext = "CHECK"
+	UniqueWithoutIndexPredicateExpr  SchemaExprContext = "UNIQUE WITHOUT INDEX PREDICATE"
+	ExclusionConstraintPredicateExpr SchemaExprContext = "EXCLUDE PREDICATE"
+	IndexPredicateExpr               SchemaExprContext = "INDEX PREDICATE"
+	PartitionBoundExpr               SchemaExprContext = "PARTITION BOUND"
+	ExpressionIndexElementExpr       SchemaExprContext = "EXPRESSION INDEX ELEMENT"
+	TTLExpirationExpr                SchemaExprContext = "TTL EXPIRATION EXPRESSION"
+	TTLDefaultExpr                   SchemaExprContext = "TTL DEFAULT"
+	TTLUpdateExpr                    SchemaExprContext = "TTL UPDATE"
+	RestoreFilterExpr                SchemaExprContext = "RESTORE FILTER"
 )
 
 func ComputedColumnExprContext(isVirtual bool) SchemaExprContext {
@@ -0,0 +1,212 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import "github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+
+// TriggerActionTime describes when a trigger fires relative to the event that
+// activated it.
+type TriggerActionTime uint8
+
+const (
+	// TriggerActionTimeBefore indicates a BEFORE trigger.
+	TriggerActionTimeBefore TriggerActionTime = iota
+	// TriggerActionTimeAfter indicates an AFTER trigger.
+	TriggerActionTimeAfter
+	// TriggerActionTimeInsteadOf indicates an INSTEAD OF trigger.
+	TriggerActionTimeInsteadOf
+)
+
+var triggerActionTimeName = [...]string{
+	TriggerActionTimeBefore:    "BEFORE",
+	TriggerActionTimeAfter:     "AFTER",
+	TriggerActionTimeInsteadOf: "INSTEAD OF",
+}
+
+func (t TriggerActionTime) String() string {
+	return triggerActionTimeName[t]
+}
+
+// TriggerEventType describes the kind of statement that activates a trigger.
+type TriggerEventType uint8
+
+const (
+	// TriggerEventInsert indicates an INSERT event.
+	TriggerEventInsert TriggerEventType = iota
+	// TriggerEventUpdate indicates an UPDATE event.
+	TriggerEventUpdate
+	// TriggerEventDelete indicates a DELETE event.
+	TriggerEventDelete
+	// TriggerEventTruncate indicates a TRUNCATE event.
+	TriggerEventTruncate
+)
+
+var triggerEventTypeName = [...]string{
+	TriggerEventInsert:   "INSERT",
+	TriggerEventUpdate:   "UPDATE",
+	TriggerEventDelete:   "DELETE",
+	TriggerEventTruncate: "TRUNCATE",
+}
+
+func (t TriggerEventType) String() string {
+	return triggerEventTypeName[t]
+}
+
+// TriggerEvent represents one of the events that activates a trigger. Columns
+// is only set for UPDATE OF events.
+type TriggerEvent struct {
+	EventType TriggerEventType
+	Columns   NameList
+}
+
+// Format implements the NodeFormatter interface.
+func (node *TriggerEvent) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.EventType.String())
+	if len(node.Columns) > 0 {
+		ctx.WriteString(" OF ")
+		ctx.FormatNode(&node.Columns)
+	}
+}
+
+// TriggerEvents is a list of trigger events joined by OR.
+type TriggerEvents []*TriggerEvent
+
+// Format implements the NodeFormatter interface.
+func (node TriggerEvents) Format(ctx *FmtCtx) {
+	for i, e := range node {
+		if i > 0 {
+			ctx.WriteString(" OR ")
+		}
+		ctx.FormatNode(e)
+	}
+}
+
+// TriggerForEach describes whether a trigger fires once per modified row or
+// once per statement.
+type TriggerForEach uint8
+
+const (
+	// TriggerForEachStatement indicates a statement-level trigger. This is the
+	// default when FOR EACH is omitted.
+	TriggerForEachStatement TriggerForEach = iota
+	// TriggerForEachRow indicates a row-level trigger.
+	TriggerForEachRow
+)
+
+func (t TriggerForEach) String() string {
+	if t == TriggerForEachRow {
+		return "ROW"
+	}
+	return "STATEMENT"
+}
+
+// TriggerDeferrability describes whether a constraint trigger can be deferred
+// to the end of the transaction.
+type TriggerDeferrability uint8
+
+const (
+	// TriggerNotDeferrable indicates a trigger that cannot be deferred. This is
+	// the only option for regular triggers.
+	TriggerNotDeferrable TriggerDeferrability = iota
+	// TriggerDeferrableInitiallyImmediate indicates a constraint trigger that
+	// fires immediately by default, but can be deferred with SET CONSTRAINTS.
+	TriggerDeferrableInitiallyImmediate
+	// TriggerDeferrableInitiallyDeferred indicates a constraint trigger that is
+	// deferred to the end of the transaction by default.
+	TriggerDeferrableInitiallyDeferred
+)
+
+// CreateTrigger represents a CREATE [CONSTRAINT] TRIGGER statement.
+type CreateTrigger struct {
+	Replace    bool
+	Constraint bool
+	Name       Name
+	ActionTime TriggerActionTime
+	Events     TriggerEvents
+	Table      TableName
+	// Deferrability can only be set for constraint triggers.
+	Deferrability TriggerDeferrability
+	ForEach       TriggerForEach
+	When          Expr
+	FuncName      RoutineName
+	// FuncArgs holds the literal arguments passed to the trigger function. As in
+	// Postgres, they are always interpreted as strings.
+	FuncArgs []string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	if node.Constraint {
+		ctx.WriteString("CONSTRAINT ")
+	}
+	ctx.WriteString("TRIGGER ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	ctx.WriteString(node.ActionTime.String())
+	ctx.WriteByte(' ')
+	ctx.FormatNode(node.Events)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	switch node.Deferrability {
+	case TriggerDeferrableInitiallyImmediate:
+		ctx.WriteString(" DEFERRABLE")
+	case TriggerDeferrableInitiallyDeferred:
+		ctx.WriteString(" DEFERRABLE INITIALLY DEFERRED")
+	}
+	ctx.WriteString(" FOR EACH ")
+	ctx.WriteString(node.ForEach.String())
+	if node.When != nil {
+		ctx.WriteString(" WHEN (")
+		ctx.FormatNode(node.When)
+		ctx.WriteByte(')')
+	}
+	ctx.WriteString(" EXECUTE FUNCTION ")
+	ctx.FormatNode(&node.FuncName)
+	ctx.WriteByte('(')
+	for i, arg := range node.FuncArgs {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		if ctx.flags.HasFlags(FmtHideConstants) {
+			ctx.WriteString("'_'")
+		} else {
+			lexbase.EncodeSQLStringWithFlags(&ctx.Buffer, arg, ctx.flags.EncodeFlags())
+		}
+	}
+	ctx.WriteByte(')')
+}
+
+// DropTrigger represents a DROP TRIGGER statement.
+type DropTrigger struct {
+	IfExists     bool
+	Name         Name
+	Table        TableName
+	DropBehavior DropBehavior
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP TRIGGER ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteString(" ")
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
@@ -21,14 +21,15 @@ package tree
 
 // Delete represents a DELETE statement.
 type Delete struct {
-	Batch     *Batch
-	With      *With
-	Table     TableExpr
-	Where     *Where
-	OrderBy   OrderBy
-	Using     TableExprs
-	Limit     *Limit
-	Returning ReturningClause
+	Batch      *Batch
+	With       *With
+	Table      TableExpr
+	Where      *Where
+	OrderBy    OrderBy
+	Using      TableExprs
+	Limit      *Limit
+	WaitPolicy LockingWaitPolicy
+	Returning  ReturningClause
 }
 
 // Format implements the NodeFormatter interface.
@@ -54,6 +55,7 @@ func (node *Delete) Format(ctx *FmtCtx) {
 		ctx.WriteByte(' ')
 		ctx.FormatNode(node.Limit)
 	}
+	ctx.FormatNode(node.WaitPolicy)
 	if HasReturningClause(node.Returning) {
 		ctx.WriteByte(' ')
 		ctx.FormatNode(node.Returning)
@@ -81,6 +81,21 @@ type TSMatchesVectorQueryOp struct{}
 // TSMatchesQueryVectorOp is a BinaryEvalOp.
 type TSMatchesQueryVectorOp struct{}
 
+// JSONPathMatchOp is a BinaryEvalOp.
+type JSONPathMatchOp struct{}
+
+// JSONPathExistsOp is a BinaryEvalOp.
+type JSONPathExistsOp struct{}
+
+// DistanceVectorOp is a BinaryEvalOp.
+type DistanceVectorOp struct{}
+
+// CosDistanceVectorOp is a BinaryEvalOp.
+type CosDistanceVectorOp struct{}
+
+// NegInnerProductVectorOp is a BinaryEvalOp.
+type NegInnerProductVectorOp struct{}
+
 // AppendToMaybeNullArrayOp is a BinaryEvalOp.
 type AppendToMaybeNullArrayOp struct {
 	Typ *types.T
@@ -313,6 +328,8 @@ type (
 	ConcatJsonbOp struct{}
 	// ConcatStringOp is a BinaryEvalOp.
 	ConcatStringOp struct{}
+	// ConcatTSVectorOp is a BinaryEvalOp.
+	ConcatTSVectorOp struct{}
 	// ConcatVarBitOp is a BinaryEvalOp.
 	ConcatVarBitOp struct{}
 )
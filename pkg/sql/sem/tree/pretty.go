@@ -1166,6 +1166,7 @@ func (node *Update) doc(p *PrettyCfg) pretty.Doc {
 		node.Where.docRow(p),
 		node.OrderBy.docRow(p))
 	items = append(items, node.Limit.docTable(p)...)
+	items = append(items, node.WaitPolicy.docTable(p)...)
 	items = append(items, p.docReturning(node.Returning))
 	return p.rlTable(items...)
 }
@@ -1190,6 +1191,7 @@ func (node *Delete) doc(p *PrettyCfg) pretty.Doc {
 		node.Where.docRow(p),
 		node.OrderBy.docRow(p))
 	items = append(items, node.Limit.docTable(p)...)
+	items = append(items, node.WaitPolicy.docTable(p)...)
 	items = append(items, p.docReturning(node.Returning))
 	return p.rlTable(items...)
 }
@@ -1280,6 +1282,9 @@ func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 			title = pretty.ConcatSpace(title, p.bracket(`(`, p.Doc(&node.StorageParams), `)`))
 		}
 		title = pretty.ConcatSpace(title, pretty.Keyword("AS"))
+	} else if node.PartitionOf != nil {
+		title = pretty.ConcatSpace(title, pretty.Keyword("PARTITION OF"))
+		title = pretty.ConcatSpace(title, p.Doc(node.PartitionOf))
 	} else {
 		title = pretty.ConcatSpace(title,
 			p.bracket("(", p.Doc(&node.Defs), ")"),
@@ -1290,6 +1295,18 @@ func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 	if node.As() {
 		clauses = append(clauses, p.Doc(node.AsSource))
 	}
+	if node.PartitionBound != nil {
+		clauses = append(clauses, p.Doc(node.PartitionBound))
+	}
+	if len(node.Inherits) > 0 {
+		clauses = append(
+			clauses,
+			pretty.ConcatSpace(
+				pretty.Keyword(`INHERITS`),
+				p.bracket(`(`, p.Doc(&node.Inherits), `)`),
+			),
+		)
+	}
 	if node.PartitionByTable != nil {
 		clauses = append(clauses, p.Doc(node.PartitionByTable))
 	}
@@ -1481,6 +1498,12 @@ func (node *PartitionByTable) doc(p *PrettyCfg) pretty.Doc {
 	//
 	// PARTITION [ALL] BY RANGE (...)
 	//    ( ..values.. )
+	//
+	// PARTITION BY RANGE (...)
+	if node.Key != nil {
+		return pretty.ConcatSpace(pretty.Keyword(`PARTITION BY RANGE`),
+			p.bracket("(", p.Doc(&node.Key), ")"))
+	}
 	var kw string
 	kw = `PARTITION `
 	if node.All {
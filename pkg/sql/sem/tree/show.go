@@ -93,6 +93,8 @@ const (
 	BackupValidateDetails
 	// BackupConnectionTest identifies a SHOW BACKUP CONNECTION statement
 	BackupConnectionTest
+	// BackupDiffDetails identifies a SHOW BACKUP DIFF statement.
+	BackupDiffDetails
 )
 
 // TODO (msbutler): 22.2 after removing old style show backup syntax, rename
@@ -107,6 +109,10 @@ type ShowBackup struct {
 	From         bool
 	Details      ShowBackupDetails
 	Options      ShowBackupOptions
+
+	// DiffPath is the backup that Path is compared against in a
+	// SHOW BACKUP DIFF <path> AND <diff_path> statement.
+	DiffPath Expr
 }
 
 // Format implements the NodeFormatter interface.
@@ -127,6 +133,8 @@ func (node *ShowBackup) Format(ctx *FmtCtx) {
 		ctx.WriteString("SCHEMAS ")
 	case BackupConnectionTest:
 		ctx.WriteString("CONNECTION ")
+	case BackupDiffDetails:
+		ctx.WriteString("DIFF ")
 	}
 
 	if node.From {
@@ -134,6 +142,10 @@ func (node *ShowBackup) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(node.Path)
+	if node.DiffPath != nil {
+		ctx.WriteString(" AND ")
+		ctx.FormatNode(node.DiffPath)
+	}
 	if node.InCollection != nil {
 		ctx.WriteString(" IN ")
 		ctx.FormatNode(&node.InCollection)
@@ -61,6 +61,9 @@ const (
 	JSONFetchValPath
 	JSONFetchTextPath
 	TSMatch
+	VectorDistance
+	VectorCosDistance
+	VectorNegInnerProduct
 
 	NumBinaryOperatorSymbols
 )
@@ -68,24 +71,27 @@ const (
 var _ = NumBinaryOperatorSymbols
 
 var binaryOpName = [...]string{
-	Bitand:            "&",
-	Bitor:             "|",
-	Bitxor:            "#",
-	Plus:              "+",
-	Minus:             "-",
-	Mult:              "*",
-	Div:               "/",
-	FloorDiv:          "//",
-	Mod:               "%",
-	Pow:               "^",
-	Concat:            "||",
-	LShift:            "<<",
-	RShift:            ">>",
-	JSONFetchVal:      "->",
-	JSONFetchText:     "->>",
-	JSONFetchValPath:  "#>",
-	JSONFetchTextPath: "#>>",
-	TSMatch:           "@@",
+	Bitand:                "&",
+	Bitor:                 "|",
+	Bitxor:                "#",
+	Plus:                  "+",
+	Minus:                 "-",
+	Mult:                  "*",
+	Div:                   "/",
+	FloorDiv:              "//",
+	Mod:                   "%",
+	Pow:                   "^",
+	Concat:                "||",
+	LShift:                "<<",
+	RShift:                ">>",
+	JSONFetchVal:          "->",
+	JSONFetchText:         "->>",
+	JSONFetchValPath:      "#>",
+	JSONFetchTextPath:     "#>>",
+	TSMatch:               "@@",
+	VectorDistance:        "<->",
+	VectorCosDistance:     "<=>",
+	VectorNegInnerProduct: "<#>",
 }
 
 // IsPadded returns whether the binary operator needs to be padded.
@@ -21,14 +21,15 @@ package tree
 
 // Update represents an UPDATE statement.
 type Update struct {
-	With      *With
-	Table     TableExpr
-	Exprs     UpdateExprs
-	From      TableExprs
-	Where     *Where
-	OrderBy   OrderBy
-	Limit     *Limit
-	Returning ReturningClause
+	With       *With
+	Table      TableExpr
+	Exprs      UpdateExprs
+	From       TableExprs
+	Where      *Where
+	OrderBy    OrderBy
+	Limit      *Limit
+	WaitPolicy LockingWaitPolicy
+	Returning  ReturningClause
 }
 
 // Format implements the NodeFormatter interface.
@@ -54,6 +55,7 @@ func (node *Update) Format(ctx *FmtCtx) {
 		ctx.WriteByte(' ')
 		ctx.FormatNode(node.Limit)
 	}
+	ctx.FormatNode(node.WaitPolicy)
 	if HasReturningClause(node.Returning) {
 		ctx.WriteByte(' ')
 		ctx.FormatNode(node.Returning)
@@ -47,6 +47,9 @@ type InternalExecutorOverride struct {
 	// does **not** propagate further to "nested" executors that are spawned up
 	// by the "top" executor.
 	InjectRetryErrorsEnabled bool
+	// AllowMaterializedViewMutations, if true, allows the query to write to
+	// materialized views.
+	AllowMaterializedViewMutations bool
 }
 
 // NoSessionDataOverride is the empty InternalExecutorOverride which does not
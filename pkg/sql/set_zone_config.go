@@ -155,6 +155,14 @@ func init() {
 				c.InheritedLeasePreferences = false
 			},
 		},
+		{
+			field:        config.Tiering,
+			requiredType: types.String,
+			setter: func(c *zonepb.ZoneConfig, d tree.Datum) {
+				c.Tiering = &zonepb.TieringPolicy{}
+				loadYAML(c.Tiering, string(tree.MustBeDString(d)))
+			},
+		},
 	}
 	supportedZoneConfigOptions = make(map[tree.Name]zoneConfigOption, len(opts))
 	zoneOptionKeys = make([]string, len(opts))
@@ -986,6 +994,14 @@ func accumulateUniqueConstraints(zone *zonepb.ZoneConfig) []zonepb.Constraint {
 			addToValidate(constraint)
 		}
 	}
+	if zone.Tiering != nil {
+		for _, constraint := range zone.Tiering.Hot {
+			addToValidate(constraint)
+		}
+		for _, constraint := range zone.Tiering.Cold {
+			addToValidate(constraint)
+		}
+	}
 	return constraints
 }
 
@@ -1005,7 +1021,8 @@ func validateZoneAttrsAndLocalities(
 	zone *zonepb.ZoneConfig,
 ) error {
 	// Avoid RPCs to the Node/Region server if we don't have anything to validate.
-	if len(zone.Constraints) == 0 && len(zone.VoterConstraints) == 0 && len(zone.LeasePreferences) == 0 {
+	if len(zone.Constraints) == 0 && len(zone.VoterConstraints) == 0 && len(zone.LeasePreferences) == 0 &&
+		(zone.Tiering == nil || zone.Tiering.IsEmpty()) {
 		return nil
 	}
 	if execCfg.Codec.ForSystemTenant() {
@@ -179,6 +179,9 @@ func ShowCreateTable(
 	if err := showConstraintClause(ctx, desc, &p.RunParams(ctx).p.semaCtx, p.RunParams(ctx).p.SessionData(), f); err != nil {
 		return "", err
 	}
+	if err := showInheritanceClause(desc, dbPrefix, lCtx, f); err != nil {
+		return "", err
+	}
 
 	if err := ShowCreatePartitioning(
 		a, p.ExecCfg().Codec, desc, desc.GetPrimaryIndex(), desc.GetPrimaryIndex().GetPartitioning(),
@@ -197,6 +200,10 @@ func ShowCreateTable(
 		return "", err
 	}
 
+	if err := showAttachPartition(tn, desc, dbPrefix, lCtx, &f.Buffer); err != nil {
+		return "", err
+	}
+
 	if !displayOptions.IgnoreComments {
 		if err := showComments(tn, desc, selectComment(ctx, p, desc.GetID()), &f.Buffer); err != nil {
 			return "", err
@@ -568,6 +568,95 @@ func showFamilyClause(desc catalog.TableDescriptor, f *tree.FmtCtx) {
 	}
 }
 
+// inheritanceTableName returns the name of the table with the given ID to
+// display in the inheritance clauses of a CREATE statement.
+func inheritanceTableName(
+	id descpb.ID, dbPrefix string, lCtx simpleSchemaResolver, searchPath sessiondata.SearchPath,
+) (tree.TableName, error) {
+	if lCtx == nil {
+		tn := tree.MakeTableNameWithSchema(tree.Name(""), catconstants.PublicSchemaName, tree.Name(fmt.Sprintf("[%d as ref]", id)))
+		tn.ExplicitSchema = false
+		return tn, nil
+	}
+	table, err := lCtx.getTableByID(id)
+	if err != nil {
+		return tree.TableName{}, err
+	}
+	tn, err := getTableNameFromTableDescriptor(lCtx, table, dbPrefix)
+	if err != nil {
+		return tree.TableName{}, err
+	}
+	tn.ExplicitSchema = !searchPath.Contains(tn.SchemaName.String(), false /* includeImplicit */)
+	return tn, nil
+}
+
+// showInheritanceClause creates the INHERITS and PARTITION BY RANGE clauses
+// of a CREATE statement for a table with inheritance, writing them to
+// tree.FmtCtx f. The bound of a partition is shown separately, by
+// showAttachPartition.
+func showInheritanceClause(
+	desc catalog.TableDescriptor, dbPrefix string, lCtx simpleSchemaResolver, f *tree.FmtCtx,
+) error {
+	if inherits := desc.GetInherits(); len(inherits) > 0 && desc.GetPartitionBound() == nil {
+		f.WriteString(" INHERITS (")
+		for i, id := range inherits {
+			if i > 0 {
+				f.WriteString(", ")
+			}
+			tn, err := inheritanceTableName(id, dbPrefix, lCtx, sessiondata.EmptySearchPath)
+			if err != nil {
+				return err
+			}
+			f.FormatNode(&tn)
+		}
+		f.WriteString(")")
+	}
+	if key := desc.GetPartitionKey(); key != nil {
+		names, err := catalog.ColumnNamesForIDs(desc, key.ColumnIDs)
+		if err != nil {
+			return err
+		}
+		f.WriteString(" PARTITION BY RANGE (")
+		formatQuoteNames(&f.Buffer, names...)
+		f.WriteString(")")
+	}
+	return nil
+}
+
+// showAttachPartition creates the ALTER TABLE ... ATTACH PARTITION statement
+// which makes the table named tn a partition of its parent, if it is a
+// partition, and writes it to buf.
+func showAttachPartition(
+	tn *tree.TableName,
+	desc catalog.TableDescriptor,
+	dbPrefix string,
+	lCtx simpleSchemaResolver,
+	buf *bytes.Buffer,
+) error {
+	if desc.GetPartitionBound() == nil || len(desc.GetInherits()) != 1 {
+		return nil
+	}
+	parent, err := inheritanceTableName(desc.GetInherits()[0], dbPrefix, lCtx, sessiondata.EmptySearchPath)
+	if err != nil {
+		return err
+	}
+	bound, err := partitionBoundSpec(desc)
+	if err != nil {
+		return err
+	}
+	f := tree.NewFmtCtx(tree.FmtSimple)
+	f.WriteString(";\n")
+	f.FormatNode(&tree.AlterTable{
+		Table: parent.ToUnresolvedObjectName(),
+		Cmds: tree.AlterTableCmds{&tree.AlterTableAttachPartition{
+			Partition: *tn,
+			Bound:     bound,
+		}},
+	})
+	buf.WriteString(f.CloseAndGetString())
+	return nil
+}
+
 // showCreateLocality creates the LOCALITY clauses for a CREATE statement, writing them
 // to tree.FmtCtx f.
 func showCreateLocality(desc catalog.TableDescriptor, f *tree.FmtCtx) error {
@@ -787,6 +876,44 @@ func showConstraintClause(
 			f.WriteString(" NOT VALID")
 		}
 	}
+	for _, c := range desc.ExclusionConstraints() {
+		if c.Adding() {
+			continue
+		}
+		f.WriteString(",\n\t")
+		f.WriteString("CONSTRAINT ")
+		formatQuoteNames(&f.Buffer, c.GetName())
+		f.WriteString(" EXCLUDE ")
+		if method := c.ExclusionDesc().IndexMethod; method != "" {
+			f.WriteString("USING ")
+			f.WriteString(method)
+			f.WriteString(" ")
+		}
+		f.WriteString("(")
+		for i := 0; i < c.NumKeyColumns(); i++ {
+			if i > 0 {
+				f.WriteString(", ")
+			}
+			col, err := catalog.MustFindColumnByID(desc, c.GetKeyColumnID(i))
+			if err != nil {
+				return err
+			}
+			formatQuoteNames(&f.Buffer, col.GetName())
+			f.WriteString(" WITH ")
+			f.WriteString(c.GetOperator(i))
+		}
+		f.WriteString(")")
+		if c.IsPartial() {
+			f.WriteString(" WHERE ")
+			pred, err := schemaexpr.FormatExprForDisplay(
+				ctx, desc, c.GetPredicate(), semaCtx, sessionData, exprFmtFlags,
+			)
+			if err != nil {
+				return err
+			}
+			f.WriteString(pred)
+		}
+	}
 	f.WriteString("\n)")
 	return nil
 }
@@ -232,6 +232,14 @@ func zoneConfigToSQL(zs *tree.ZoneSpecifier, zone *zonepb.ZoneConfig) (string, e
 		maybeWriteComma(f)
 		f.Printf("\tlease_preferences = %s", lexbase.EscapeSQLString(prefs))
 	}
+	if zone.Tiering != nil {
+		tiering, err := yamlMarshalFlow(zone.Tiering)
+		if err != nil {
+			return "", err
+		}
+		maybeWriteComma(f)
+		f.Printf("\ttiering = %s", lexbase.EscapeSQLString(strings.TrimSpace(tiering)))
+	}
 	return f.String(), nil
 }
 
@@ -74,6 +74,11 @@ var (
 	// indexes counted in InvertedIndexCounter.
 	TrigramInvertedIndexCounter = telemetry.GetCounterOnce("sql.schema.trigram_inverted_index")
 
+	// VectorInvertedIndexCounter is to be incremented every time a vector
+	// inverted index is created. These are a subset of the indexes counted in
+	// InvertedIndexCounter.
+	VectorInvertedIndexCounter = telemetry.GetCounterOnce("sql.schema.vector_inverted_index")
+
 	// PartialIndexCounter is to be incremented every time a partial index is
 	// created. This includes both regular and inverted partial indexes.
 	PartialIndexCounter = telemetry.GetCounterOnce("sql.schema.partial_index")
@@ -178,6 +183,11 @@ var CreateUnloggedTableCounter = telemetry.GetCounterOnce("sql.schema.create_unl
 // view is refreshed.
 var SchemaRefreshMaterializedView = telemetry.GetCounterOnce("sql.schema.refresh_materialized_view")
 
+// SchemaRefreshMaterializedViewIncrementally is to be incremented every time
+// a materialized view is refreshed with REFRESH MATERIALIZED VIEW ...
+// INCREMENTALLY.
+var SchemaRefreshMaterializedViewIncrementally = telemetry.GetCounterOnce("sql.schema.refresh_materialized_view.incremental")
+
 // SchemaChangeErrorCounter is to be incremented for different types
 // of errors.
 func SchemaChangeErrorCounter(typ string) telemetry.Counter {
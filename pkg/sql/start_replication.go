@@ -0,0 +1,556 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsnutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgoutput"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgrepltree"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxlog"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/fsm"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// replicationKeepaliveInterval is the interval at which keepalive messages are
+// sent to the client of a logical replication stream. The keepalive messages
+// report the position up to which the stream is complete, and ask the client
+// to report its progress.
+const replicationKeepaliveInterval = 10 * time.Second
+
+// LogicalReplicationEvent is produced by the feed of a logical replication
+// stream. It is either the change of a row or a checkpoint.
+type LogicalReplicationEvent struct {
+	// Resolved is set if the event is a checkpoint, in which case all the
+	// changes at or below this timestamp have been produced and the other
+	// fields are unset.
+	Resolved hlc.Timestamp
+
+	// Table is the descriptor of the changed table, at the version that was
+	// current when the row changed.
+	Table catalog.TableDescriptor
+	// Timestamp is the MVCC timestamp of the change.
+	Timestamp hlc.Timestamp
+	// Row contains the values of the public, non-virtual columns of the table,
+	// in order. If the row was deleted, only the primary key columns are set.
+	Row tree.Datums
+	// Deleted is set if the row was deleted.
+	Deleted bool
+	// Existed is set if the row existed before the change, which makes the
+	// change an update rather than an insert.
+	Existed bool
+}
+
+// RunLogicalReplicationFeedCCL is the public hook point for the CCL-licensed
+// code which produces the changes streamed by START_REPLICATION. It calls emit
+// with the changes to the given tables that happened after startTime, in no
+// particular order, interleaved with checkpoints. It runs until the context is
+// canceled or an error occurs.
+var RunLogicalReplicationFeedCCL = func(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	tables []catalog.TableDescriptor,
+	startTime hlc.Timestamp,
+	emit func(context.Context, LogicalReplicationEvent) error,
+) error {
+	return sqlerrors.NewCCLRequiredError(errors.New(
+		"streaming logical replication changes requires a CCL binary"))
+}
+
+// execStartReplication executes a START_REPLICATION command, which takes over
+// the connection until the client ends the stream.
+func (ex *connExecutor) execStartReplication(
+	ctx context.Context, cmd StartReplication, res StartReplicationResult,
+) (fsm.Event, fsm.EventPayload) {
+	defer close(cmd.Done)
+	if _, isNoTxn := ex.machine.CurState().(stateNoTxn); !isNoTxn {
+		return ex.makeErrEvent(pgerror.New(pgcode.ActiveSQLTransaction,
+			"START_REPLICATION cannot run inside a transaction block"), cmd.Stmt)
+	}
+
+	ex.incrementStartedStmtCounter(cmd.Stmt)
+	ctx, cancelQuery := ctxlog.WithCancel(ctx)
+	queryID := ex.server.cfg.GenerateID()
+	ex.addActiveQuery(cmd.ParsedStmt, nil /* placeholders */, queryID, cancelQuery)
+	defer func() {
+		ex.removeActiveQuery(queryID, cmd.Stmt)
+		cancelQuery()
+	}()
+
+	s, startTime, err := ex.newLogicalReplicationStream(ctx, cmd.Stmt, res)
+	if err != nil {
+		return ex.makeErrEvent(err, cmd.Stmt)
+	}
+	if err := s.run(ctx, startTime, cmd.Feedback); err != nil {
+		return ex.makeErrEvent(err, cmd.Stmt)
+	}
+	ex.incrementExecutedStmtCounter(cmd.Stmt)
+	return nil, nil
+}
+
+// newLogicalReplicationStream validates a START_REPLICATION command and
+// returns the stream it starts, along with the timestamp after which changes
+// are streamed.
+func (ex *connExecutor) newLogicalReplicationStream(
+	ctx context.Context, n *pgrepltree.StartReplication, res StartReplicationResult,
+) (*logicalReplicationStream, hlc.Timestamp, error) {
+	execCfg := ex.server.cfg
+	if !execCfg.Settings.Version.IsActive(ctx, clusterversion.V23_2_LogicalReplication) {
+		return nil, hlc.Timestamp{}, pgerror.New(pgcode.FeatureNotSupported,
+			"START_REPLICATION is not supported until the cluster upgrade is finalized")
+	}
+	if n.Kind != pgrepltree.LogicalReplication {
+		return nil, hlc.Timestamp{}, unimplemented.New("physical replication",
+			"physical replication is not supported")
+	}
+	pubNames, err := parsePgoutputOptions(n.Options)
+	if err != nil {
+		return nil, hlc.Timestamp{}, err
+	}
+	dbName := ex.sessionData().Database
+	if dbName == "" {
+		return nil, hlc.Timestamp{}, pgerror.New(pgcode.ObjectNotInPrerequisiteState,
+			"logical decoding requires a database connection")
+	}
+
+	s := &logicalReplicationStream{
+		execCfg:     execCfg,
+		res:         res,
+		relations:   make(map[descpb.ID]publishedRelation),
+		schemaNames: make(map[descpb.ID]string),
+	}
+	if err := execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		s.tables = s.tables[:0]
+		slot, err := getReplicationSlot(ctx, txn, string(n.Slot))
+		if err != nil {
+			return err
+		}
+		s.slot = slot
+		db, err := txn.Descriptors().ByNameWithLeased(txn.KV()).Get().Database(ctx, dbName)
+		if err != nil {
+			return err
+		}
+		if slot.databaseID != db.GetID() {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"replication slot %q was not created in this database", slot.name)
+		}
+		pubs, err := getPublications(ctx, txn, db.GetID(), pubNames...)
+		if err != nil {
+			return err
+		}
+		for _, name := range pubNames {
+			if !publicationExists(pubs, name) {
+				return pgerror.Newf(pgcode.UndefinedObject, "publication %q does not exist", name)
+			}
+		}
+		all, err := txn.Descriptors().GetAllTablesInDatabase(ctx, txn.KV(), db)
+		if err != nil {
+			return err
+		}
+		return all.ForEachDescriptor(func(desc catalog.Descriptor) error {
+			table, ok := desc.(catalog.TableDescriptor)
+			if !ok || !table.Public() {
+				return nil
+			}
+			for i := range pubs {
+				if !pubs[i].includes(table) {
+					continue
+				}
+				if table.NumFamilies() > 1 {
+					return pgerror.Newf(pgcode.FeatureNotSupported,
+						"table %q has more than one column family, which logical replication does not support",
+						table.GetName())
+				}
+				s.tables = append(s.tables, table)
+				return nil
+			}
+			return nil
+		})
+	}); err != nil {
+		return nil, hlc.Timestamp{}, err
+	}
+
+	// Streaming resumes after the last position confirmed by the client, which
+	// covers all the changes whose timestamp maps to that position.
+	s.sent = s.slot.confirmedFlush
+	if n.LSN > s.sent {
+		s.sent = n.LSN
+	}
+	s.confirmed = s.slot.confirmedFlush
+	return s, lsnutil.LSNToHLC(s.sent + 1).Prev(), nil
+}
+
+// parsePgoutputOptions validates the options of the pgoutput plugin and
+// returns the names of the publications to stream.
+func parsePgoutputOptions(opts pgrepltree.Options) (pubNames []string, _ error) {
+	var hasProtoVersion bool
+	for _, opt := range opts {
+		// An option without a value is a boolean option which is set.
+		val := "true"
+		if s, ok := opt.Value.(*tree.StrVal); ok {
+			val = s.RawString()
+		}
+		switch opt.Key {
+		case "proto_version":
+			if val != "1" {
+				return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+					"client sent proto_version=%s but only protocol version %d is supported",
+					val, pgoutput.ProtoVersion)
+			}
+			hasProtoVersion = true
+		case "publication_names":
+			for _, name := range strings.Split(val, ",") {
+				name = strings.TrimSpace(name)
+				if len(name) >= 2 && name[0] == '"' && name[len(name)-1] == '"' {
+					name = strings.ReplaceAll(name[1:len(name)-1], `""`, `"`)
+				} else {
+					name = strings.ToLower(name)
+				}
+				if name == "" {
+					return nil, pgerror.New(pgcode.InvalidName, "invalid publication_names syntax")
+				}
+				pubNames = append(pubNames, name)
+			}
+		case "binary", "messages", "streaming":
+			switch strings.ToLower(val) {
+			case "off", "false", "0":
+			default:
+				return nil, unimplemented.Newf("pgoutput "+string(opt.Key),
+					"the pgoutput %s option is not supported", opt.Key)
+			}
+		case "origin":
+		default:
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized pgoutput option: %s", opt.Key)
+		}
+	}
+	if !hasProtoVersion {
+		return nil, pgerror.New(pgcode.InvalidParameterValue, "proto_version option missing")
+	}
+	if len(pubNames) == 0 {
+		return nil, pgerror.New(pgcode.InvalidParameterValue, "publication_names parameter missing")
+	}
+	return pubNames, nil
+}
+
+func publicationExists(pubs []publication, name string) bool {
+	for i := range pubs {
+		if pubs[i].name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// publishedRelation is a relation which has been described to the client.
+type publishedRelation struct {
+	pgoutput.Relation
+	version descpb.DescriptorVersion
+}
+
+// logicalReplicationStream streams the changes to the tables of a set of
+// publications to a client, encoded in the pgoutput format.
+//
+// The changes are buffered until they are resolved, at which point they are
+// sent grouped in transactions by LSN, in LSN order. The LSN of a change is
+// derived from its timestamp, so changes committed at the same time are sent
+// in the same transaction. The tables of the stream are resolved when the
+// stream starts, so tables added to a FOR ALL TABLES publication afterwards
+// are only streamed once the client reconnects.
+type logicalReplicationStream struct {
+	execCfg *ExecutorConfig
+	res     StartReplicationResult
+	slot    replicationSlot
+	tables  []catalog.TableDescriptor
+
+	// pending holds the changes which are not resolved yet.
+	pending []LogicalReplicationEvent
+	// sent is the position up to which all changes have been sent.
+	sent lsn.LSN
+	// confirmed is the position up to which the client has confirmed
+	// receiving the changes.
+	confirmed lsn.LSN
+	// lastSent is the time at which the last message was sent.
+	lastSent time.Time
+	// xid is the transaction ID of the last transaction sent.
+	xid uint32
+
+	relations   map[descpb.ID]publishedRelation
+	schemaNames map[descpb.ID]string
+
+	msg, buf []byte
+}
+
+// run streams the changes after startTime until the client ends the stream by
+// closing feedback, or an error occurs.
+func (s *logicalReplicationStream) run(
+	ctx context.Context, startTime hlc.Timestamp, feedback <-chan []byte,
+) error {
+	if err := s.res.SendCopyBoth(ctx); err != nil {
+		return err
+	}
+
+	feedCtx, cancelFeed := context.WithCancel(ctx)
+	events := make(chan LogicalReplicationEvent)
+	feedErrCh := make(chan error, 1)
+	if err := s.execCfg.DistSQLSrv.Stopper.RunAsyncTask(feedCtx, "logical-replication-feed", func(ctx context.Context) {
+		feedErrCh <- RunLogicalReplicationFeedCCL(ctx, s.execCfg, s.tables, startTime,
+			func(ctx context.Context, ev LogicalReplicationEvent) error {
+				select {
+				case events <- ev:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+	}); err != nil {
+		cancelFeed()
+		return err
+	}
+	feedDone := false
+	defer func() {
+		cancelFeed()
+		if !feedDone {
+			<-feedErrCh
+		}
+	}()
+
+	keepalive := time.NewTicker(replicationKeepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		select {
+		case ev := <-events:
+			if ev.Resolved.IsEmpty() {
+				s.pending = append(s.pending, ev)
+				continue
+			}
+			if err := s.flush(ctx, ev.Resolved); err != nil {
+				return err
+			}
+
+		case data, ok := <-feedback:
+			if !ok {
+				// The client ended the stream.
+				return s.res.SendCopyDone(ctx)
+			}
+			if err := s.handleFeedback(ctx, data); err != nil {
+				return err
+			}
+
+		case <-keepalive.C:
+			if timeutil.Since(s.lastSent) >= replicationKeepaliveInterval {
+				if err := s.sendKeepalive(ctx, true /* replyRequested */); err != nil {
+					return err
+				}
+			}
+
+		case err := <-feedErrCh:
+			feedDone = true
+			if err == nil {
+				err = errors.AssertionFailedf("logical replication feed stopped unexpectedly")
+			}
+			return err
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// flush sends the changes which are known to be complete given that all the
+// changes at or below resolved have been received.
+func (s *logicalReplicationStream) flush(ctx context.Context, resolved hlc.Timestamp) error {
+	// Changes committed after resolved may still share the LSN of resolved, so
+	// only the changes with a lower LSN are complete.
+	end := lsnutil.HLCToLSN(resolved)
+	if end == 0 || end-1 <= s.sent {
+		return nil
+	}
+	sort.SliceStable(s.pending, func(i, j int) bool {
+		return s.pending[i].Timestamp.Less(s.pending[j].Timestamp)
+	})
+	n := sort.Search(len(s.pending), func(i int) bool {
+		return lsnutil.HLCToLSN(s.pending[i].Timestamp) >= end
+	})
+	for ready := s.pending[:n]; len(ready) > 0; {
+		commitLSN := lsnutil.HLCToLSN(ready[0].Timestamp)
+		i := 1
+		for i < len(ready) && lsnutil.HLCToLSN(ready[i].Timestamp) == commitLSN {
+			i++
+		}
+		if err := s.sendTransaction(ctx, commitLSN, ready[:i]); err != nil {
+			return err
+		}
+		ready = ready[i:]
+	}
+	s.pending = append(s.pending[:0], s.pending[n:]...)
+	s.sent = end - 1
+	if n == 0 {
+		return nil
+	}
+	return s.res.Flush(ctx)
+}
+
+// sendTransaction sends the given changes, which all map to commitLSN, as one
+// transaction.
+func (s *logicalReplicationStream) sendTransaction(
+	ctx context.Context, commitLSN lsn.LSN, changes []LogicalReplicationEvent,
+) error {
+	s.xid++
+	commitTime := changes[0].Timestamp.GoTime()
+	s.msg = pgoutput.AppendBegin(s.msg[:0], commitLSN, commitTime, s.xid)
+	if err := s.send(ctx, commitLSN); err != nil {
+		return err
+	}
+	for _, ev := range changes {
+		rel, err := s.relation(ctx, commitLSN, ev.Table)
+		if err != nil {
+			return err
+		}
+		if len(ev.Row) != len(rel.Columns) {
+			return errors.AssertionFailedf("expected %d values for a row of %q, found %d",
+				len(rel.Columns), rel.Name, len(ev.Row))
+		}
+		switch {
+		case ev.Deleted:
+			s.msg = pgoutput.AppendDelete(s.msg[:0], rel, ev.Row)
+		case ev.Existed:
+			s.msg = pgoutput.AppendUpdate(s.msg[:0], rel, ev.Row)
+		default:
+			s.msg = pgoutput.AppendInsert(s.msg[:0], rel, ev.Row)
+		}
+		if err := s.send(ctx, commitLSN); err != nil {
+			return err
+		}
+	}
+	s.msg = pgoutput.AppendCommit(s.msg[:0], commitLSN, commitLSN, commitTime)
+	return s.send(ctx, commitLSN)
+}
+
+// relation returns the description of the given table, which is sent to the
+// client first if the client does not know the current version of the table.
+func (s *logicalReplicationStream) relation(
+	ctx context.Context, commitLSN lsn.LSN, table catalog.TableDescriptor,
+) (*pgoutput.Relation, error) {
+	if rel, ok := s.relations[table.GetID()]; ok && rel.version == table.GetVersion() {
+		return &rel.Relation, nil
+	}
+	scName, err := s.schemaName(ctx, table.GetParentSchemaID())
+	if err != nil {
+		return nil, err
+	}
+	rel := publishedRelation{
+		Relation: pgoutput.Relation{
+			ID:        uint32(table.GetID()),
+			Namespace: scName,
+			Name:      table.GetName(),
+		},
+		version: table.GetVersion(),
+	}
+	keyCols := table.GetPrimaryIndex().CollectKeyColumnIDs()
+	for _, col := range table.PublicColumns() {
+		if col.IsVirtual() {
+			continue
+		}
+		rel.Columns = append(rel.Columns, pgoutput.Column{
+			Name: col.GetName(),
+			Type: col.GetType(),
+			Key:  keyCols.Contains(col.GetID()),
+		})
+	}
+	s.relations[table.GetID()] = rel
+	s.msg = pgoutput.AppendRelation(s.msg[:0], &rel.Relation)
+	if err := s.send(ctx, commitLSN); err != nil {
+		return nil, err
+	}
+	return &rel.Relation, nil
+}
+
+func (s *logicalReplicationStream) schemaName(ctx context.Context, id descpb.ID) (string, error) {
+	if name, ok := s.schemaNames[id]; ok {
+		return name, nil
+	}
+	var name string
+	if err := s.execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		sc, err := txn.Descriptors().ByIDWithLeased(txn.KV()).Get().Schema(ctx, id)
+		if err != nil {
+			return err
+		}
+		name = sc.GetName()
+		return nil
+	}); err != nil {
+		return "", err
+	}
+	s.schemaNames[id] = name
+	return name, nil
+}
+
+// send sends the logical replication message in s.msg.
+func (s *logicalReplicationStream) send(ctx context.Context, walStart lsn.LSN) error {
+	s.lastSent = timeutil.Now()
+	s.buf = pgoutput.AppendXLogData(s.buf[:0], walStart, walStart, s.lastSent, s.msg)
+	return s.res.SendCopyData(ctx, s.buf, false /* isHeader */)
+}
+
+func (s *logicalReplicationStream) sendKeepalive(ctx context.Context, replyRequested bool) error {
+	s.lastSent = timeutil.Now()
+	s.buf = pgoutput.AppendPrimaryKeepalive(s.buf[:0], s.sent, s.lastSent, replyRequested)
+	if err := s.res.SendCopyData(ctx, s.buf, false /* isHeader */); err != nil {
+		return err
+	}
+	return s.res.Flush(ctx)
+}
+
+// handleFeedback handles a message sent by the client. The position confirmed
+// by the client is persisted in the replication slot, so that streaming
+// resumes from it.
+func (s *logicalReplicationStream) handleFeedback(ctx context.Context, data []byte) error {
+	u, err := pgoutput.ParseClientMessage(data)
+	if err != nil {
+		return pgerror.Wrap(err, pgcode.ProtocolViolation, "invalid replication feedback")
+	}
+	if u == nil {
+		return nil
+	}
+	if flushed := u.Flushed; flushed > s.confirmed {
+		// The client cannot confirm changes which were not sent.
+		if flushed > s.sent {
+			flushed = s.sent
+		}
+		if err := s.execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+			return advanceReplicationSlot(ctx, txn, s.slot.name, flushed)
+		}); err != nil {
+			return err
+		}
+		s.confirmed = flushed
+	}
+	if u.ReplyRequested {
+		return s.sendKeepalive(ctx, false /* replyRequested */)
+	}
+	return nil
+}
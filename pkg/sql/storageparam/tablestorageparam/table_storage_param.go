@@ -16,6 +16,7 @@ import (
 	"context"
 	"math"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -26,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/storageparam"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
@@ -545,6 +547,64 @@ var tableParams = map[string]tableParam{
 			return nil
 		},
 	},
+	`lock_timeout`: {
+		onSet: func(ctx context.Context, po *Setter, semaCtx *tree.SemaContext, evalCtx *eval.Context, key string, datum tree.Datum) error {
+			var d *tree.DInterval
+			if stringVal, err := paramparse.DatumAsString(ctx, evalCtx, key, datum); err == nil {
+				// Like the lock_timeout session variable, a bare number is
+				// interpreted as milliseconds.
+				d, err = tree.ParseDIntervalWithTypeMetadata(
+					evalCtx.SessionData().GetIntervalStyle(),
+					stringVal,
+					types.IntervalTypeMetadata{
+						DurationField: types.IntervalDurationField{
+							DurationType: types.IntervalDurationType_MILLISECOND,
+						},
+					},
+				)
+				if err != nil {
+					return pgerror.Wrapf(
+						err,
+						pgcode.InvalidParameterValue,
+						`value of %q must be an interval`,
+						key,
+					)
+				}
+			} else {
+				var ok bool
+				d, ok = datum.(*tree.DInterval)
+				if !ok || d == nil {
+					return pgerror.Newf(
+						pgcode.InvalidParameterValue,
+						`value of %q must be an interval`,
+						key,
+					)
+				}
+			}
+			nanos, _, _, err := d.Encode()
+			if err != nil {
+				return pgerror.Wrapf(
+					err,
+					pgcode.InvalidParameterValue,
+					`invalid value for %q`,
+					key,
+				)
+			}
+			if nanos < 0 {
+				return pgerror.Newf(
+					pgcode.InvalidParameterValue,
+					`value of %q must be at least zero`,
+					key,
+				)
+			}
+			po.TableDesc.LockTimeout = time.Duration(nanos)
+			return nil
+		},
+		onReset: func(ctx context.Context, po *Setter, evalCtx *eval.Context, key string) error {
+			po.TableDesc.LockTimeout = 0
+			return nil
+		},
+	},
 }
 
 func nonNegativeIntWithMaximum(max int64) func(int64) error {
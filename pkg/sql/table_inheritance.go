@@ -0,0 +1,1063 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// This file implements Postgres-style table inheritance. A table which
+// inherits from another table has a superset of its columns, and its rows
+// are included in scans of the other table. Partitions created with CREATE
+// TABLE ... PARTITION OF or attached with ALTER TABLE ... ATTACH PARTITION are
+// inheritance children of a table declared with PARTITION BY RANGE (cols),
+// which additionally have a range bound on those columns. Each partition is a
+// separate table with its own descriptor, so it can be detached instantly
+// and kept as a standalone table.
+//
+// Inheritance is only supported by the legacy schema changer.
+
+// checkTableInheritanceSupported returns an error if the cluster version
+// does not yet support table inheritance.
+func (p *planner) checkTableInheritanceSupported(ctx context.Context) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V23_2_TableInheritance) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"table inheritance is not supported until the cluster upgrade is finalized")
+	}
+	return nil
+}
+
+// hasInheritance returns true if the table inherits from other tables, is
+// inherited by other tables, or is a partitioned table.
+func hasInheritance(desc catalog.TableDescriptor) bool {
+	return len(desc.GetInherits()) > 0 || len(desc.GetInheritedBy()) > 0 ||
+		desc.GetPartitionKey() != nil
+}
+
+// checkAlterTableCmdAllowedWithInheritance returns an error if the given ALTER
+// TABLE command changes the columns of a table with inheritance, which is not
+// supported, since the columns of inheriting tables have to match the
+// columns of the tables they inherit from.
+func checkAlterTableCmdAllowedWithInheritance(
+	desc catalog.TableDescriptor, cmd tree.AlterTableCmd,
+) error {
+	if !hasInheritance(desc) {
+		return nil
+	}
+	var op string
+	switch cmd.(type) {
+	case *tree.AlterTableAddColumn:
+		op = "ADD COLUMN"
+	case *tree.AlterTableDropColumn:
+		op = "DROP COLUMN"
+	case *tree.AlterTableRenameColumn:
+		op = "RENAME COLUMN"
+	case *tree.AlterTableAlterColumnType:
+		op = "ALTER COLUMN TYPE"
+	case *tree.AlterTableAlterPrimaryKey:
+		op = "ALTER PRIMARY KEY"
+	default:
+		return nil
+	}
+	return unimplemented.NewWithIssuef(22456,
+		"ALTER TABLE ... %s is not supported on table %q with inheritance", op, desc.GetName())
+}
+
+// prepareCreateTableInheritance adds the definitions of the columns and
+// constraints which a new table inherits from the tables listed in its
+// INHERITS or PARTITION OF clause to n.Defs. The inherited columns are
+// added as LIKE table definitions, which are expanded by
+// replaceLikeTableOpts. It returns the set of column definitions which were
+// specified locally, for use by mergeInheritedColumnDefs.
+func prepareCreateTableInheritance(
+	params runParams, n *tree.CreateTable,
+) (localCols map[*tree.ColumnTableDef]struct{}, _ error) {
+	if len(n.Inherits) == 0 && n.PartitionOf == nil &&
+		(n.PartitionByTable == nil || len(n.PartitionByTable.Key) == 0) {
+		return nil, nil
+	}
+	if err := params.p.checkTableInheritanceSupported(params.ctx); err != nil {
+		return nil, err
+	}
+	if n.PartitionByTable != nil && len(n.PartitionByTable.Key) > 0 && len(n.Inherits) > 0 {
+		return nil, pgerror.New(pgcode.WrongObjectType,
+			"cannot create partitioned table as inheritance child")
+	}
+
+	localCols = make(map[*tree.ColumnTableDef]struct{})
+	localChecks := make(map[tree.Name]struct{})
+	for _, def := range n.Defs {
+		switch d := def.(type) {
+		case *tree.ColumnTableDef:
+			localCols[d] = struct{}{}
+		case *tree.CheckConstraintTableDef:
+			if d.Name != "" {
+				localChecks[d.Name] = struct{}{}
+			}
+		}
+	}
+
+	if n.PartitionOf != nil {
+		parent, err := resolveInheritanceParent(params, n, n.PartitionOf)
+		if err != nil {
+			return nil, err
+		}
+		if parent.GetPartitionKey() == nil {
+			return nil, pgerror.Newf(pgcode.WrongObjectType,
+				"table %q is not partitioned", parent.GetName())
+		}
+		// A partition has exactly the columns, constraints and indexes of the
+		// partitioned table.
+		n.Defs = append(tree.TableDefs{&tree.LikeTableDef{
+			Name:    *n.PartitionOf,
+			Options: []tree.LikeTableOption{{Opt: tree.LikeTableOptAll}},
+		}}, n.Defs...)
+		return localCols, nil
+	}
+
+	var inherited tree.TableDefs
+	seen := catalog.DescriptorIDSet{}
+	for i := range n.Inherits {
+		parent, err := resolveInheritanceParent(params, n, &n.Inherits[i])
+		if err != nil {
+			return nil, err
+		}
+		if parent.GetPartitionKey() != nil {
+			return nil, pgerror.Newf(pgcode.WrongObjectType,
+				"cannot inherit from partitioned table %q", parent.GetName())
+		}
+		if seen.Contains(parent.GetID()) {
+			return nil, pgerror.Newf(pgcode.DuplicateRelation,
+				"relation %q would be inherited from more than once", parent.GetName())
+		}
+		seen.Add(parent.GetID())
+		// Inherit the columns with their defaults and computed expressions, and
+		// the check constraints. Indexes and unique constraints are not
+		// inherited, as in Postgres.
+		inherited = append(inherited, &tree.LikeTableDef{
+			Name: n.Inherits[i],
+			Options: []tree.LikeTableOption{
+				{Opt: tree.LikeTableOptDefaults},
+				{Opt: tree.LikeTableOptGenerated},
+			},
+		})
+		for _, ck := range parent.EnforcedCheckConstraints() {
+			if ck.IsHashShardingConstraint() {
+				continue
+			}
+			name := tree.Name(ck.GetName())
+			if _, ok := localChecks[name]; ok {
+				continue
+			}
+			localChecks[name] = struct{}{}
+			expr, err := parser.ParseExpr(ck.GetExpr())
+			if err != nil {
+				return nil, err
+			}
+			inherited = append(inherited, &tree.CheckConstraintTableDef{Name: name, Expr: expr})
+		}
+	}
+	n.Defs = append(inherited, n.Defs...)
+	return localCols, nil
+}
+
+// resolveInheritanceParent resolves a table which the new table in n
+// inherits from.
+func resolveInheritanceParent(
+	params runParams, n *tree.CreateTable, name *tree.TableName,
+) (*tabledesc.Mutable, error) {
+	_, parent, err := params.p.ResolveMutableTableDescriptor(
+		params.ctx, name, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := params.p.CheckPrivilege(params.ctx, parent, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if parent.IsTemporary() && !n.Persistence.IsTemporary() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"cannot inherit from temporary relation %q", parent.GetName())
+	}
+	return parent, nil
+}
+
+// mergeInheritedColumnDefs merges the definitions of columns with the same
+// name which were inherited from different tables, or which were both
+// inherited and specified locally. The types of merged columns must match.
+// Columns specified more than once locally are left alone, so that
+// NewTableDesc reports them.
+func mergeInheritedColumnDefs(
+	params runParams, n *tree.CreateTable, localCols map[*tree.ColumnTableDef]struct{},
+) error {
+	defs := make(tree.TableDefs, 0, len(n.Defs))
+	byName := make(map[tree.Name]int)
+	for _, def := range n.Defs {
+		col, ok := def.(*tree.ColumnTableDef)
+		if !ok {
+			defs = append(defs, def)
+			continue
+		}
+		idx, ok := byName[col.Name]
+		if !ok {
+			byName[col.Name] = len(defs)
+			defs = append(defs, def)
+			continue
+		}
+		prev := defs[idx].(*tree.ColumnTableDef)
+		_, prevLocal := localCols[prev]
+		_, local := localCols[col]
+		if prevLocal && local {
+			defs = append(defs, def)
+			continue
+		}
+		resolver := params.p.semaCtx.GetTypeResolver()
+		prevTyp, err := tree.ResolveType(params.ctx, prev.Type, resolver)
+		if err != nil {
+			return err
+		}
+		typ, err := tree.ResolveType(params.ctx, col.Type, resolver)
+		if err != nil {
+			return err
+		}
+		if !inheritedTypesMatch(prevTyp, typ) {
+			return errors.WithDetailf(
+				pgerror.Newf(pgcode.DatatypeMismatch, "column %q has a type conflict", col.Name),
+				"%s versus %s", prevTyp.SQLString(), typ.SQLString(),
+			)
+		}
+		if local {
+			params.p.BufferClientNotice(params.ctx,
+				pgnotice.Newf("merging column %q with inherited definition", col.Name))
+		} else {
+			params.p.BufferClientNotice(params.ctx,
+				pgnotice.Newf("merging multiple inherited definitions of column %q", col.Name))
+		}
+		// The later definition takes precedence, but the column stays NOT
+		// NULL if any of the definitions is.
+		merged := *col
+		if prev.Nullable.Nullability == tree.NotNull {
+			merged.Nullable.Nullability = tree.NotNull
+		}
+		if merged.DefaultExpr.Expr == nil {
+			merged.DefaultExpr = prev.DefaultExpr
+		}
+		defs[idx] = &merged
+		if local {
+			localCols[&merged] = struct{}{}
+		}
+	}
+	n.Defs = defs
+	return nil
+}
+
+// inheritedTypesMatch returns true if a column of type a can be inherited by
+// a column of type b.
+func inheritedTypesMatch(a, b *types.T) bool {
+	if a.UserDefined() || b.UserDefined() {
+		return a.Oid() == b.Oid()
+	}
+	return a.Identical(b)
+}
+
+// finishCreateTableInheritance records the inheritance relationships of the
+// new table desc on desc and on the tables it inherits from. For partitioned
+// tables, it records the partition key, and for partitions, their bound.
+func (p *planner) finishCreateTableInheritance(
+	ctx context.Context, n *tree.CreateTable, desc *tabledesc.Mutable,
+) error {
+	if n.PartitionByTable != nil && len(n.PartitionByTable.Key) > 0 {
+		key := &descpb.TableDescriptor_PartitionKey{}
+		for _, name := range n.PartitionByTable.Key {
+			col, err := catalog.MustFindColumnByTreeName(desc, name)
+			if err != nil {
+				return err
+			}
+			for _, id := range key.ColumnIDs {
+				if id == col.GetID() {
+					return pgerror.Newf(pgcode.DuplicateColumn,
+						"column %q appears more than once in partition key", name)
+				}
+			}
+			key.ColumnIDs = append(key.ColumnIDs, col.GetID())
+		}
+		desc.PartitionKey = key
+	}
+
+	parents := n.Inherits
+	if n.PartitionOf != nil {
+		parents = tree.TableNames{*n.PartitionOf}
+	}
+	for i := range parents {
+		_, parent, err := p.ResolveMutableTableDescriptor(
+			ctx, &parents[i], true /* required */, tree.ResolveRequireTableDesc,
+		)
+		if err != nil {
+			return err
+		}
+		if n.PartitionOf != nil {
+			bound, err := p.makePartitionBound(ctx, parent, desc, n.PartitionBound)
+			if err != nil {
+				return err
+			}
+			desc.PartitionBound = bound
+		}
+		linkInheritance(parent, desc)
+		if err := p.writeSchemaChange(
+			ctx, parent, descpb.InvalidMutationID,
+			fmt.Sprintf("updating table %q after creating inheriting table %q", parent.GetName(), desc.GetName()),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alterTableInherit implements ALTER TABLE child INHERIT parent.
+func (p *planner) alterTableInherit(
+	ctx context.Context, child *tabledesc.Mutable, parentName *tree.TableName,
+) error {
+	if err := p.checkTableInheritanceSupported(ctx); err != nil {
+		return err
+	}
+	_, parent, err := p.ResolveMutableTableDescriptor(
+		ctx, parentName, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return err
+	}
+	if err := p.CheckPrivilege(ctx, parent, privilege.CREATE); err != nil {
+		return err
+	}
+	if child.PartitionBound != nil {
+		return pgerror.New(pgcode.WrongObjectType, "cannot change inheritance of a partition")
+	}
+	if child.PartitionKey != nil {
+		return pgerror.New(pgcode.WrongObjectType, "cannot change inheritance of partitioned table")
+	}
+	if parent.PartitionKey != nil {
+		return pgerror.Newf(pgcode.WrongObjectType,
+			"cannot inherit from partitioned table %q", parent.GetName())
+	}
+	if parent.IsTemporary() && !child.IsTemporary() {
+		return pgerror.Newf(pgcode.WrongObjectType,
+			"cannot inherit from temporary relation %q", parent.GetName())
+	}
+	if containsDescID(child.Inherits, parent.GetID()) {
+		return pgerror.Newf(pgcode.DuplicateRelation,
+			"relation %q would be inherited from more than once", parent.GetName())
+	}
+	// Prevent cycles: the parent must not already be a descendant of the
+	// child, or the child itself.
+	if isDescendant, err := p.isInheritanceDescendant(ctx, child, parent); err != nil {
+		return err
+	} else if isDescendant {
+		return pgerror.Newf(pgcode.DuplicateRelation,
+			"circular inheritance not allowed: %q is already a child of %q",
+			child.GetName(), parent.GetName())
+	}
+	if err := checkInheritedColumns(parent, child, false /* exact */); err != nil {
+		return err
+	}
+	linkInheritance(parent, child)
+	return p.writeSchemaChange(
+		ctx, parent, descpb.InvalidMutationID,
+		fmt.Sprintf("updating table %q after table %q inherited from it", parent.GetName(), child.GetName()),
+	)
+}
+
+// alterTableNoInherit implements ALTER TABLE child NO INHERIT parent.
+func (p *planner) alterTableNoInherit(
+	ctx context.Context, child *tabledesc.Mutable, parentName *tree.TableName,
+) error {
+	_, parent, err := p.ResolveMutableTableDescriptor(
+		ctx, parentName, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return err
+	}
+	if child.PartitionBound != nil {
+		return pgerror.New(pgcode.WrongObjectType, "cannot change inheritance of a partition")
+	}
+	if !containsDescID(child.Inherits, parent.GetID()) {
+		return pgerror.Newf(pgcode.UndefinedTable,
+			"relation %q is not a parent of relation %q", parent.GetName(), child.GetName())
+	}
+	unlinkInheritance(parent, child)
+	return p.writeSchemaChange(
+		ctx, parent, descpb.InvalidMutationID,
+		fmt.Sprintf("updating table %q after table %q stopped inheriting from it", parent.GetName(), child.GetName()),
+	)
+}
+
+// alterTableAttachPartition implements ALTER TABLE parent ATTACH PARTITION
+// child FOR VALUES FROM (...) TO (...). The existing rows of the child are
+// validated against the bound in the current transaction.
+func (p *planner) alterTableAttachPartition(
+	ctx context.Context,
+	parent *tabledesc.Mutable,
+	childName *tree.TableName,
+	spec *tree.PartitionBoundSpec,
+) error {
+	if err := p.checkTableInheritanceSupported(ctx); err != nil {
+		return err
+	}
+	if parent.PartitionKey == nil {
+		return pgerror.Newf(pgcode.WrongObjectType, "table %q is not partitioned", parent.GetName())
+	}
+	_, child, err := p.ResolveMutableTableDescriptor(
+		ctx, childName, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return err
+	}
+	if err := p.CheckPrivilege(ctx, child, privilege.CREATE); err != nil {
+		return err
+	}
+	if child.GetID() == parent.GetID() {
+		return pgerror.Newf(pgcode.WrongObjectType,
+			"cannot attach table %q as a partition of itself", child.GetName())
+	}
+	switch {
+	case child.PartitionBound != nil:
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"%q is already a partition", child.GetName())
+	case len(child.Inherits) > 0:
+		return pgerror.New(pgcode.WrongObjectType, "cannot attach inheritance child as partition")
+	case len(child.InheritedBy) > 0:
+		return pgerror.New(pgcode.WrongObjectType, "cannot attach inheritance parent as partition")
+	case child.PartitionKey != nil:
+		return unimplemented.NewWithIssue(22456, "attaching a partitioned table as a partition is not supported")
+	}
+	if parent.IsTemporary() != child.IsTemporary() {
+		return pgerror.Newf(pgcode.WrongObjectType,
+			"cannot attach a %s relation as partition of %s relation %q",
+			persistenceString(child), persistenceString(parent), parent.GetName())
+	}
+	if err := checkInheritedColumns(parent, child, true /* exact */); err != nil {
+		return err
+	}
+	bound, err := p.makePartitionBound(ctx, parent, child, spec)
+	if err != nil {
+		return err
+	}
+
+	// Validate the existing rows of the child before the bound is recorded, so
+	// that the optimizer does not yet know about the bound.
+	expr, err := partitionBoundCheckExpr(child, bound)
+	if err != nil {
+		return err
+	}
+	violatingRow, _, err := validateCheckExpr(
+		ctx, &p.semaCtx, p.InternalSQLTxn(), p.SessionData(), tree.Serialize(expr), child,
+		0, /* indexIDForValidation */
+	)
+	if err != nil {
+		return err
+	}
+	if len(violatingRow) > 0 {
+		return pgerror.Newf(pgcode.CheckViolation,
+			"partition constraint of relation %q is violated by some row", child.GetName())
+	}
+
+	child.PartitionBound = bound
+	linkInheritance(parent, child)
+	return p.writeSchemaChange(
+		ctx, child, descpb.InvalidMutationID,
+		fmt.Sprintf("updating table %q after attaching it as a partition of %q", child.GetName(), parent.GetName()),
+	)
+}
+
+// alterTableDetachPartition implements ALTER TABLE parent DETACH PARTITION
+// child. Only the descriptors are changed, so the child becomes a standalone
+// table immediately.
+func (p *planner) alterTableDetachPartition(
+	ctx context.Context, parent *tabledesc.Mutable, childName *tree.TableName,
+) error {
+	_, child, err := p.ResolveMutableTableDescriptor(
+		ctx, childName, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return err
+	}
+	if err := p.CheckPrivilege(ctx, child, privilege.CREATE); err != nil {
+		return err
+	}
+	if child.PartitionBound == nil || !containsDescID(parent.InheritedBy, child.GetID()) {
+		return pgerror.Newf(pgcode.UndefinedTable,
+			"relation %q is not a partition of relation %q", child.GetName(), parent.GetName())
+	}
+	child.PartitionBound = nil
+	unlinkInheritance(parent, child)
+	return p.writeSchemaChange(
+		ctx, child, descpb.InvalidMutationID,
+		fmt.Sprintf("updating table %q after detaching it from %q", child.GetName(), parent.GetName()),
+	)
+}
+
+func persistenceString(desc catalog.TableDescriptor) string {
+	if desc.IsTemporary() {
+		return "temporary"
+	}
+	return "permanent"
+}
+
+// checkInheritedColumns returns an error if child does not have all of the
+// columns of parent, with the same types. If exact is true, child must not
+// have any other columns either.
+func checkInheritedColumns(parent, child *tabledesc.Mutable, exact bool) error {
+	for _, col := range parent.PublicColumns() {
+		if implicit, err := isImplicitlyCreatedBySystem(parent, col.ColumnDesc()); err != nil {
+			return err
+		} else if implicit {
+			continue
+		}
+		childCol := catalog.FindColumnByName(child, col.GetName())
+		if childCol == nil || !childCol.Public() {
+			return pgerror.Newf(pgcode.DatatypeMismatch,
+				"child table is missing column %q", col.GetName())
+		}
+		if !inheritedTypesMatch(col.GetType(), childCol.GetType()) {
+			return pgerror.Newf(pgcode.DatatypeMismatch,
+				"child table %q has different type for column %q", child.GetName(), col.GetName())
+		}
+		if !col.IsNullable() && childCol.IsNullable() {
+			return pgerror.Newf(pgcode.DatatypeMismatch,
+				"column %q in child table must be marked NOT NULL", col.GetName())
+		}
+	}
+	if !exact {
+		return nil
+	}
+	for _, col := range child.PublicColumns() {
+		if implicit, err := isImplicitlyCreatedBySystem(child, col.ColumnDesc()); err != nil {
+			return err
+		} else if implicit {
+			continue
+		}
+		if parentCol := catalog.FindColumnByName(parent, col.GetName()); parentCol == nil {
+			return errors.WithDetail(
+				pgerror.Newf(pgcode.DatatypeMismatch,
+					"table %q contains column %q not found in parent %q",
+					child.GetName(), col.GetName(), parent.GetName()),
+				"The new partition may contain only the columns present in parent.",
+			)
+		}
+	}
+	return nil
+}
+
+// isInheritanceDescendant returns true if desc is the same table as ancestor
+// or one of its inheritance descendants.
+func (p *planner) isInheritanceDescendant(
+	ctx context.Context, ancestor, desc catalog.TableDescriptor,
+) (bool, error) {
+	if desc.GetID() == ancestor.GetID() {
+		return true, nil
+	}
+	for _, id := range desc.GetInherits() {
+		parent, err := p.Descriptors().ByID(p.txn).Get().Table(ctx, id)
+		if err != nil {
+			return false, err
+		}
+		if ok, err := p.isInheritanceDescendant(ctx, ancestor, parent); err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// canDropInheritanceParent returns an error if the table desc cannot be
+// dropped because other tables which are not also being dropped inherit from
+// it.
+func (p *planner) canDropInheritanceParent(
+	ctx context.Context,
+	desc *tabledesc.Mutable,
+	dropping map[descpb.ID]toDelete,
+	behavior tree.DropBehavior,
+) error {
+	for _, id := range desc.InheritedBy {
+		if _, ok := dropping[id]; ok {
+			continue
+		}
+		child, err := p.Descriptors().MutableByID(p.txn).Table(ctx, id)
+		if err != nil {
+			return err
+		}
+		if behavior != tree.DropCascade {
+			return errors.WithHint(
+				errors.WithDetailf(
+					pgerror.Newf(pgcode.DependentObjectsStillExist,
+						"cannot drop table %q because other objects depend on it", desc.GetName()),
+					"table %q depends on table %q", child.GetName(), desc.GetName(),
+				),
+				"Use DROP ... CASCADE to drop the dependent objects too.",
+			)
+		}
+		if err := p.canDropTable(ctx, child, true /* checkOwnership */); err != nil {
+			return err
+		}
+		if err := p.canDropInheritanceParent(ctx, child, dropping, behavior); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropTableInheritance removes the inheritance relationships of a table which
+// is being dropped. Tables which inherit from it are dropped as well, which
+// is only allowed with CASCADE, see canDropInheritanceParent, unless the
+// database or schema of the table is being dropped. It returns the names of
+// views which were dropped along with those tables.
+func (p *planner) dropTableInheritance(
+	ctx context.Context,
+	desc *tabledesc.Mutable,
+	droppingParent bool,
+	jobDesc string,
+	behavior tree.DropBehavior,
+) (droppedViews []string, _ error) {
+	for _, id := range desc.Inherits {
+		parent, err := p.Descriptors().MutableByID(p.txn).Table(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if parent.Dropped() || !containsDescID(parent.InheritedBy, desc.GetID()) {
+			continue
+		}
+		unlinkInheritance(parent, desc)
+		if err := p.writeSchemaChange(
+			ctx, parent, descpb.InvalidMutationID,
+			fmt.Sprintf("updating table %q after dropping inheriting table %q", parent.GetName(), desc.GetName()),
+		); err != nil {
+			return nil, err
+		}
+	}
+	desc.Inherits = nil
+	desc.PartitionBound = nil
+
+	children := desc.InheritedBy
+	desc.InheritedBy = nil
+	for _, id := range children {
+		child, err := p.Descriptors().MutableByID(p.txn).Table(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if child.Dropped() {
+			continue
+		}
+		if droppingParent {
+			// The children in the same database or schema are dropped along
+			// with it, and the others become standalone tables.
+			child.Inherits = removeDescID(child.Inherits, desc.GetID())
+			child.PartitionBound = nil
+			if err := p.writeSchemaChange(
+				ctx, child, descpb.InvalidMutationID,
+				fmt.Sprintf("updating table %q after dropping parent table %q", child.GetName(), desc.GetName()),
+			); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		views, err := p.dropTableImpl(ctx, child, droppingParent, jobDesc, behavior)
+		if err != nil {
+			return nil, err
+		}
+		droppedViews = append(droppedViews, views...)
+	}
+	return droppedViews, nil
+}
+
+// linkInheritance records that child inherits from parent.
+func linkInheritance(parent, child *tabledesc.Mutable) {
+	child.Inherits = append(child.Inherits, parent.GetID())
+	parent.InheritedBy = append(parent.InheritedBy, child.GetID())
+}
+
+// unlinkInheritance removes the record that child inherits from parent.
+func unlinkInheritance(parent, child *tabledesc.Mutable) {
+	child.Inherits = removeDescID(child.Inherits, parent.GetID())
+	parent.InheritedBy = removeDescID(parent.InheritedBy, child.GetID())
+}
+
+func containsDescID(ids []descpb.ID, id descpb.ID) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+func removeDescID(ids []descpb.ID, id descpb.ID) []descpb.ID {
+	ret := ids[:0]
+	for _, other := range ids {
+		if other != id {
+			ret = append(ret, other)
+		}
+	}
+	if len(ret) == 0 {
+		return nil
+	}
+	return ret
+}
+
+// makePartitionBound evaluates the bound of a new partition child of the
+// partitioned table parent, and checks that it does not overlap with the
+// bounds of the other partitions of parent.
+func (p *planner) makePartitionBound(
+	ctx context.Context,
+	parent, child catalog.TableDescriptor,
+	spec *tree.PartitionBoundSpec,
+) (*descpb.TableDescriptor_PartitionBound, error) {
+	keyNames, err := catalog.ColumnNamesForIDs(parent, parent.GetPartitionKey().ColumnIDs)
+	if err != nil {
+		return nil, err
+	}
+	bound := &descpb.TableDescriptor_PartitionBound{
+		ColumnIDs: make([]descpb.ColumnID, len(keyNames)),
+	}
+	cols := make([]catalog.Column, len(keyNames))
+	for i, name := range keyNames {
+		col, err := catalog.MustFindColumnByName(child, name)
+		if err != nil {
+			return nil, err
+		}
+		cols[i] = col
+		bound.ColumnIDs[i] = col.GetID()
+	}
+	evalCtx := p.EvalContext()
+	from, err := evalPartitionBound(ctx, &p.semaCtx, evalCtx, cols, spec.From, "FROM")
+	if err != nil {
+		return nil, err
+	}
+	to, err := evalPartitionBound(ctx, &p.semaCtx, evalCtx, cols, spec.To, "TO")
+	if err != nil {
+		return nil, err
+	}
+	if cmp, err := comparePartitionBounds(evalCtx, from, to); err != nil {
+		return nil, err
+	} else if cmp >= 0 {
+		return nil, errors.WithDetailf(
+			pgerror.Newf(pgcode.InvalidObjectDefinition,
+				"empty range bound specified for partition %q", child.GetName()),
+			"Specified lower bound %s is greater than or equal to upper bound %s.", from, to,
+		)
+	}
+
+	for _, id := range parent.GetInheritedBy() {
+		if id == child.GetID() {
+			continue
+		}
+		sibling, err := p.Descriptors().ByID(p.txn).Get().Table(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if sibling.GetPartitionBound() == nil {
+			continue
+		}
+		siblingFrom, siblingTo, err := decodePartitionBound(ctx, &p.semaCtx, evalCtx, sibling)
+		if err != nil {
+			return nil, err
+		}
+		// The ranges [from, to) and [siblingFrom, siblingTo) overlap if each of
+		// them starts before the other one ends.
+		cmpFrom, err := comparePartitionBounds(evalCtx, from, siblingTo)
+		if err != nil {
+			return nil, err
+		}
+		cmpTo, err := comparePartitionBounds(evalCtx, siblingFrom, to)
+		if err != nil {
+			return nil, err
+		}
+		if cmpFrom < 0 && cmpTo < 0 {
+			return nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+				"partition %q would overlap partition %q", child.GetName(), sibling.GetName())
+		}
+	}
+
+	bound.From = serializePartitionBound(from)
+	bound.To = serializePartitionBound(to)
+	return bound, nil
+}
+
+// evalPartitionBound type checks and evaluates the values of one side of a
+// partition bound against the partition key columns. The values may end with
+// any number of MINVALUE or MAXVALUE.
+func evalPartitionBound(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *eval.Context,
+	cols []catalog.Column,
+	exprs tree.Exprs,
+	side string,
+) (*rowenc.PartitionTuple, error) {
+	if len(exprs) != len(cols) {
+		return nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+			"%s must specify exactly one value per partitioning column", side)
+	}
+	t := &rowenc.PartitionTuple{}
+	for i, expr := range exprs {
+		expr = tree.StripParens(expr)
+		special, isSpecial := partitionBoundSpecialValue(expr)
+		if t.SpecialCount > 0 && (!isSpecial || special != t.Special) {
+			return nil, pgerror.Newf(pgcode.InvalidTableDefinition,
+				"every bound following %s must also be %s", t.Special, t.Special)
+		}
+		if isSpecial {
+			t.Special = special
+			t.SpecialCount++
+			continue
+		}
+		typedExpr, err := schemaexpr.SanitizeVarFreeExpr(
+			ctx, expr, cols[i].GetType(), tree.PartitionBoundExpr, semaCtx,
+			volatility.Immutable, false, /* allowAssignmentCast */
+		)
+		if err != nil {
+			return nil, err
+		}
+		datum, err := eval.Expr(ctx, evalCtx, typedExpr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "evaluating %s", typedExpr)
+		}
+		if datum == tree.DNull {
+			return nil, pgerror.New(pgcode.InvalidTableDefinition,
+				"cannot specify NULL in range bound")
+		}
+		t.Datums = append(t.Datums, datum)
+	}
+	return t, nil
+}
+
+// partitionBoundSpecialValue returns the special value represented by expr,
+// if it is MINVALUE or MAXVALUE.
+func partitionBoundSpecialValue(expr tree.Expr) (rowenc.PartitionSpecialValCode, bool) {
+	switch t := expr.(type) {
+	case tree.PartitionMinVal:
+		return rowenc.PartitionMinVal, true
+	case tree.PartitionMaxVal:
+		return rowenc.PartitionMaxVal, true
+	case *tree.UnresolvedName:
+		if t.NumParts == 1 {
+			switch t.Parts[0] {
+			case "minvalue":
+				return rowenc.PartitionMinVal, true
+			case "maxvalue":
+				return rowenc.PartitionMaxVal, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// comparePartitionBounds compares two partition bounds of the same partition
+// key. MINVALUE is less than and MAXVALUE is greater than any other value.
+func comparePartitionBounds(evalCtx *eval.Context, a, b *rowenc.PartitionTuple) (int, error) {
+	for i, n := 0, len(a.Datums)+a.SpecialCount; i < n; i++ {
+		aDatum, bDatum := i < len(a.Datums), i < len(b.Datums)
+		switch {
+		case aDatum && bDatum:
+			if cmp, err := a.Datums[i].CompareError(evalCtx, b.Datums[i]); err != nil || cmp != 0 {
+				return cmp, err
+			}
+		case aDatum:
+			if b.Special == rowenc.PartitionMinVal {
+				return 1, nil
+			}
+			return -1, nil
+		case bDatum:
+			if a.Special == rowenc.PartitionMinVal {
+				return -1, nil
+			}
+			return 1, nil
+		default:
+			if a.Special == b.Special {
+				// All of the remaining values are the same special value.
+				return 0, nil
+			}
+			if a.Special == rowenc.PartitionMinVal {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// serializePartitionBound returns the representation of one side of a
+// partition bound which is stored in the table descriptor.
+func serializePartitionBound(t *rowenc.PartitionTuple) []string {
+	vals := make([]string, 0, len(t.Datums)+t.SpecialCount)
+	for _, d := range t.Datums {
+		vals = append(vals, tree.Serialize(d))
+	}
+	for i := 0; i < t.SpecialCount; i++ {
+		vals = append(vals, t.Special.String())
+	}
+	return vals
+}
+
+// parsePartitionBound parses one side of a partition bound stored in a table
+// descriptor.
+func parsePartitionBound(vals []string) (tree.Exprs, error) {
+	exprs := make(tree.Exprs, len(vals))
+	for i, val := range vals {
+		expr, err := parser.ParseExpr(val)
+		if err != nil {
+			return nil, err
+		}
+		switch special, _ := partitionBoundSpecialValue(expr); special {
+		case rowenc.PartitionMinVal:
+			expr = tree.PartitionMinVal{}
+		case rowenc.PartitionMaxVal:
+			expr = tree.PartitionMaxVal{}
+		}
+		exprs[i] = expr
+	}
+	return exprs, nil
+}
+
+// decodePartitionBound evaluates the bound of the partition desc.
+func decodePartitionBound(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *eval.Context,
+	desc catalog.TableDescriptor,
+) (from, to *rowenc.PartitionTuple, _ error) {
+	bound := desc.GetPartitionBound()
+	cols := make([]catalog.Column, len(bound.ColumnIDs))
+	for i, id := range bound.ColumnIDs {
+		col, err := catalog.MustFindColumnByID(desc, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		cols[i] = col
+	}
+	fromExprs, err := parsePartitionBound(bound.From)
+	if err != nil {
+		return nil, nil, err
+	}
+	toExprs, err := parsePartitionBound(bound.To)
+	if err != nil {
+		return nil, nil, err
+	}
+	if from, err = evalPartitionBound(ctx, semaCtx, evalCtx, cols, fromExprs, "FROM"); err != nil {
+		return nil, nil, err
+	}
+	if to, err = evalPartitionBound(ctx, semaCtx, evalCtx, cols, toExprs, "TO"); err != nil {
+		return nil, nil, err
+	}
+	return from, to, nil
+}
+
+// partitionBoundCheckExpr returns an expression which holds for the rows of
+// desc that are within the given partition bound. For example, the bound
+// FROM (1, 2) TO (3, MAXVALUE) on columns (a, b) results in:
+//
+//	a IS NOT NULL AND b IS NOT NULL AND (a, b) >= (1, 2) AND a <= 3
+func partitionBoundCheckExpr(
+	desc catalog.TableDescriptor, bound *descpb.TableDescriptor_PartitionBound,
+) (tree.Expr, error) {
+	names, err := catalog.ColumnNamesForIDs(desc, bound.ColumnIDs)
+	if err != nil {
+		return nil, err
+	}
+	cols := make(tree.Exprs, len(names))
+	var expr tree.Expr
+	and := func(e tree.Expr) {
+		if expr == nil {
+			expr = e
+		} else {
+			expr = &tree.AndExpr{Left: expr, Right: e}
+		}
+	}
+	for i, name := range names {
+		cols[i] = &tree.ColumnItem{ColumnName: tree.Name(name)}
+		and(&tree.IsNotNullExpr{Expr: cols[i]})
+	}
+
+	// compare builds a comparison of the partition key columns with the values
+	// preceding the first MINVALUE or MAXVALUE of one side of the bound. The
+	// first special value determines whether the comparison is inclusive.
+	compare := func(
+		vals []string, op, opIfMaxValue treecmp.ComparisonOperatorSymbol,
+	) error {
+		exprs, err := parsePartitionBound(vals)
+		if err != nil {
+			return err
+		}
+		var prefix tree.Exprs
+		for _, e := range exprs {
+			if special, ok := partitionBoundSpecialValue(e); ok {
+				if special == rowenc.PartitionMaxVal {
+					op = opIfMaxValue
+				}
+				break
+			}
+			prefix = append(prefix, e)
+		}
+		if len(prefix) == 0 {
+			return nil
+		}
+		var left, right tree.Expr = cols[0], prefix[0]
+		if len(prefix) > 1 {
+			left = &tree.Tuple{Exprs: cols[:len(prefix)]}
+			right = &tree.Tuple{Exprs: prefix}
+		}
+		and(&tree.ComparisonExpr{
+			Operator: treecmp.MakeComparisonOperator(op),
+			Left:     left,
+			Right:    right,
+		})
+		return nil
+	}
+	if err := compare(bound.From, treecmp.GE, treecmp.GT); err != nil {
+		return nil, err
+	}
+	if err := compare(bound.To, treecmp.LT, treecmp.LE); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+// partitionBoundSpec returns the bound of the partition desc as it is
+// specified in a FOR VALUES clause.
+func partitionBoundSpec(desc catalog.TableDescriptor) (*tree.PartitionBoundSpec, error) {
+	bound := desc.GetPartitionBound()
+	from, err := parsePartitionBound(bound.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parsePartitionBound(bound.To)
+	if err != nil {
+		return nil, err
+	}
+	return &tree.PartitionBoundSpec{From: from, To: to}, nil
+}
@@ -165,9 +165,9 @@ func (tb *tableWriterBase) init(
 	}
 	tb.txn = txn
 	tb.desc = tableDesc
-	tb.lockTimeout = 0
+	tb.lockTimeout = tableDesc.GetLockTimeout()
 	if evalCtx != nil {
-		tb.lockTimeout = evalCtx.SessionData().LockTimeout
+		tb.lockTimeout = row.GetLockTimeout(evalCtx.SessionData().LockTimeout, tb.lockTimeout)
 	}
 	tb.forceProductionBatchSizes = evalCtx != nil && evalCtx.TestingKnobs.ForceProductionValues
 	tb.maxBatchSize = mutations.MaxBatchSize(tb.forceProductionBatchSizes)
@@ -107,6 +107,8 @@ var OidToType = map[oid.Oid]*T{
 	oidext.T_geometry:  Geometry,
 	oidext.T_geography: Geography,
 	oidext.T_box2d:     Box2D,
+	oidext.T_jsonpath:  Jsonpath,
+	oidext.T_pgvector:  PGVector,
 }
 
 // oidToArrayOid maps scalar type Oids to their corresponding array type Oid.
@@ -153,6 +155,8 @@ var oidToArrayOid = map[oid.Oid]oid.Oid{
 	oidext.T_geometry:  oidext.T__geometry,
 	oidext.T_geography: oidext.T__geography,
 	oidext.T_box2d:     oidext.T__box2d,
+	oidext.T_jsonpath:  oidext.T__jsonpath,
+	oidext.T_pgvector:  oidext.T__pgvector,
 }
 
 // familyToOid maps each type family to a default OID value that is used when
@@ -188,6 +192,8 @@ var familyToOid = map[Family]oid.Oid{
 	GeometryFamily:  oidext.T_geometry,
 	GeographyFamily: oidext.T_geography,
 	Box2DFamily:     oidext.T_box2d,
+	JsonpathFamily:  oidext.T_jsonpath,
+	PGVectorFamily:  oidext.T_pgvector,
 }
 
 // ArrayOids is a set of all oids which correspond to an array type.
@@ -488,6 +488,27 @@ var (
 		},
 	}
 
+	// Jsonpath is the jsonpath type, which represents a SQL/JSON path
+	// expression.
+	Jsonpath = &T{
+		InternalType: InternalType{
+			Family: JsonpathFamily,
+			Oid:    oidext.T_jsonpath,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// PGVector is the type of a vector from the pgvector extension, which is a
+	// fixed-length array of floats. This is the vector type with an unspecified
+	// number of dimensions.
+	PGVector = &T{
+		InternalType: InternalType{
+			Family: PGVectorFamily,
+			Oid:    oidext.T_pgvector,
+			Locale: &emptyLocale,
+		},
+	}
+
 	// TSQuery is the tsquery type, which represents a full text search query.
 	TSQuery = &T{
 		InternalType: InternalType{
@@ -872,6 +893,19 @@ func MakeString(width int32) *T {
 		Family: StringFamily, Oid: oid.T_text, Width: width, Locale: &emptyLocale}}
 }
 
+// MakePGVector constructs a new instance of the VECTOR type (oid =
+// T_pgvector) having the given number of dimensions (0 = unspecified number).
+func MakePGVector(dims int32) *T {
+	if dims == 0 {
+		return PGVector
+	}
+	if dims < 0 {
+		panic(errors.AssertionFailedf("dimensions %d cannot be negative", dims))
+	}
+	return &T{InternalType: InternalType{
+		Family: PGVectorFamily, Oid: oidext.T_pgvector, Width: dims, Locale: &emptyLocale}}
+}
+
 // MakeVarChar constructs a new instance of the VARCHAR type (oid = T_varchar)
 // having the given max # characters (0 = unspecified number).
 func MakeVarChar(width int32) *T {
@@ -1259,6 +1293,7 @@ func (t *T) Locale() string {
 //	STRING        : max # of characters
 //	COLLATEDSTRING: max # of characters
 //	BIT           : max # of bits
+//	VECTOR        : # of dimensions
 //
 // Width is always 0 for other types.
 func (t *T) Width() int32 {
@@ -1309,7 +1344,7 @@ func (t *T) TypeModifier() int32 {
 			// var header size.
 			return width + 4
 		}
-	case BitFamily:
+	case BitFamily, PGVectorFamily:
 		if width := t.Width(); width != 0 {
 			return width
 		}
@@ -1464,8 +1499,10 @@ var familyNames = map[Family]string{
 	IntFamily:            "int",
 	IntervalFamily:       "interval",
 	JsonFamily:           "jsonb",
+	JsonpathFamily:       "jsonpath",
 	OidFamily:            "oid",
 	PGLSNFamily:          "pg_lsn",
+	PGVectorFamily:       "vector",
 	StringFamily:         "string",
 	TimeFamily:           "time",
 	TimestampFamily:      "timestamp",
@@ -1742,6 +1779,13 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 		}
 	case PGLSNFamily:
 		return "pg_lsn"
+	case JsonpathFamily:
+		return "jsonpath"
+	case PGVectorFamily:
+		if !haveTypmod || typmod <= 0 {
+			return "vector"
+		}
+		return fmt.Sprintf("vector(%d)", typmod)
 	case StringFamily, CollatedStringFamily:
 		switch t.Oid() {
 		case oid.T_text:
@@ -1894,6 +1938,10 @@ func (t *T) SQLString() string {
 		}
 	case GeometryFamily, GeographyFamily:
 		return strings.ToUpper(t.Name() + t.InternalType.GeoMetadata.SQLString())
+	case PGVectorFamily:
+		if t.Width() > 0 {
+			return fmt.Sprintf("VECTOR(%d)", t.Width())
+		}
 	case IntervalFamily:
 		switch t.InternalType.IntervalDurationField.DurationType {
 		case IntervalDurationType_UNSET:
@@ -1975,7 +2023,7 @@ func (t *T) SQLStringForError() redact.RedactableString {
 		IntervalFamily, StringFamily, BytesFamily, TimestampTZFamily, CollatedStringFamily, OidFamily,
 		UnknownFamily, UuidFamily, INetFamily, TimeFamily, JsonFamily, TimeTZFamily, BitFamily,
 		GeometryFamily, GeographyFamily, Box2DFamily, VoidFamily, EncodedKeyFamily, TSQueryFamily,
-		TSVectorFamily, AnyFamily, PGLSNFamily:
+		TSVectorFamily, AnyFamily, PGLSNFamily, JsonpathFamily, PGVectorFamily:
 		// These types do not contain other types, and do not require redaction.
 		return redact.Sprint(redact.SafeString(t.SQLString()))
 	}
@@ -2715,6 +2763,8 @@ func IsValidArrayElementType(t *T) (valid bool, issueNum int) {
 		return false, 90886
 	case TSVectorFamily:
 		return false, 90886
+	case PGVectorFamily:
+		return false, 121432
 	default:
 		return true, 0
 	}
@@ -2934,7 +2984,6 @@ var postgresPredefinedTypeIssues = map[string]int{
 	"box":           21286,
 	"cidr":          18846,
 	"circle":        21286,
-	"jsonpath":      22513,
 	"line":          21286,
 	"lseg":          21286,
 	"macaddr":       45813,
@@ -0,0 +1,169 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package vecindex implements the partitioning scheme of approximate nearest
+// neighbor indexes on VECTOR columns.
+//
+// A vector index is an inverted file (IVF) index: the vector space is divided
+// into a number of lists, each of which is identified by a centroid, and each
+// vector is stored in the list whose centroid is closest to it. A nearest
+// neighbor search scans the lists whose centroids are closest to the query
+// vector, which is called probing, and ranks the vectors found there by their
+// exact distance from the query vector. The search is approximate because a
+// close vector can be stored in a list that is not probed.
+//
+// Unlike pgvector, the centroids are not trained on the indexed data. Instead,
+// they are random unit vectors generated from a fixed seed, so that they only
+// depend on the number of dimensions and lists. This allows index entries to
+// be computed from the indexed vector alone, and therefore to be maintained
+// transactionally with the rest of the row. A vector belongs to the list whose
+// centroid points in the most similar direction, so that the partitioning is
+// suitable for all distance metrics.
+package vecindex
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
+)
+
+// DefaultLists is the number of lists in a vector index if the lists storage
+// parameter is not specified.
+const DefaultLists = 100
+
+// MaxLists is the maximum number of lists in a vector index.
+const MaxLists = 32768
+
+// DefaultConfig returns the default config of a vector index.
+func DefaultConfig() *Config {
+	return &Config{Lists: DefaultLists}
+}
+
+// IsEmpty returns whether the config contains a vector index configuration.
+func (c *Config) IsEmpty() bool {
+	return c.Lists == 0
+}
+
+// Validate returns an error if the config is not valid.
+func (c *Config) Validate() error {
+	if c.Lists < 1 || c.Lists > MaxLists {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"lists must be between 1 and %d", MaxLists)
+	}
+	return nil
+}
+
+// Partition returns the list that the given vector is stored in.
+func (c *Config) Partition(v vector.T) int {
+	centroids := getCentroids(len(v), int(c.Lists))
+	best, bestScore := 0, 0.0
+	for i, centroid := range centroids {
+		if score := dot(v, centroid); i == 0 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// Probe returns the lists that must be scanned to find the nearest neighbors
+// of the given query vector, in ascending order. At most probes lists are
+// returned.
+func (c *Config) Probe(q vector.T, probes int) []int {
+	n := int(c.Lists)
+	if probes >= n {
+		lists := make([]int, n)
+		for i := range lists {
+			lists[i] = i
+		}
+		return lists
+	}
+	if probes < 1 {
+		probes = 1
+	}
+	centroids := getCentroids(len(q), n)
+	scores := make([]float64, n)
+	lists := make([]int, n)
+	for i, centroid := range centroids {
+		scores[i] = dot(q, centroid)
+		lists[i] = i
+	}
+	// Break ties by list number so that the result is deterministic.
+	sort.Slice(lists, func(i, j int) bool {
+		if scores[lists[i]] != scores[lists[j]] {
+			return scores[lists[i]] > scores[lists[j]]
+		}
+		return lists[i] < lists[j]
+	})
+	lists = lists[:probes]
+	sort.Ints(lists)
+	return lists
+}
+
+func dot(a, b vector.T) float64 {
+	var res float64
+	for i := range a {
+		res += float64(a[i]) * float64(b[i])
+	}
+	return res
+}
+
+type centroidsKey struct {
+	dims, lists int
+}
+
+var centroidsCache struct {
+	syncutil.Mutex
+	m map[centroidsKey][]vector.T
+}
+
+// getCentroids returns the centroids of the lists of a vector index with the
+// given number of dimensions and lists. The centroids are cached, since they
+// are needed every time an index entry is computed.
+func getCentroids(dims, lists int) []vector.T {
+	key := centroidsKey{dims: dims, lists: lists}
+	centroidsCache.Lock()
+	defer centroidsCache.Unlock()
+	if c, ok := centroidsCache.m[key]; ok {
+		return c
+	}
+	if centroidsCache.m == nil {
+		centroidsCache.m = make(map[centroidsKey][]vector.T)
+	}
+	c := makeCentroids(dims, lists)
+	centroidsCache.m[key] = c
+	return c
+}
+
+// makeCentroids generates the centroids of the lists of a vector index.
+//
+// The centroids determine the keys of index entries, so the generated values
+// must never change. Note that math/rand guarantees that the sequence
+// generated by a source with a given seed is stable.
+func makeCentroids(dims, lists int) []vector.T {
+	rng := rand.New(rand.NewSource(int64(dims)<<32 | int64(lists)))
+	centroids := make([]vector.T, lists)
+	for i := range centroids {
+		c := make(vector.T, dims)
+		for j := range c {
+			c[j] = float32(rng.NormFloat64())
+		}
+		if norm := vector.Norm(c); norm > 0 {
+			for j := range c {
+				c[j] = float32(float64(c[j]) / norm)
+			}
+		}
+		centroids[i] = c
+	}
+	return centroids
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package vecindex
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionAndProbe(t *testing.T) {
+	rng, _ := randutil.NewTestRand()
+	for _, lists := range []int32{1, 2, 10, 100} {
+		c := Config{Lists: lists}
+		require.NoError(t, c.Validate())
+		for i := 0; i < 100; i++ {
+			v := vector.Random(rng, 1+rng.Intn(20))
+			p := c.Partition(v)
+			require.True(t, p >= 0 && p < int(lists))
+
+			// The list of a vector is always the first one probed for it.
+			require.Equal(t, []int{p}, c.Probe(v, 1))
+
+			probes := 1 + rng.Intn(int(lists))
+			lists := c.Probe(v, probes)
+			require.Len(t, lists, probes)
+			require.Contains(t, lists, p)
+			for j := 1; j < len(lists); j++ {
+				require.Less(t, lists[j-1], lists[j])
+			}
+		}
+	}
+}
+
+// TestPartitionStable verifies that the partitioning of vectors does not
+// change, which would corrupt existing indexes.
+func TestPartitionStable(t *testing.T) {
+	c := Config{Lists: 8}
+	for _, tc := range []struct {
+		v        vector.T
+		expected int
+	}{
+		{v: vector.T{1, 0, 0}, expected: 7},
+		{v: vector.T{0, 1, 0}, expected: 4},
+		{v: vector.T{0, 0, 1}, expected: 0},
+		{v: vector.T{-1, -1, -1}, expected: 6},
+		{v: vector.T{0, 0, 0}, expected: 0},
+	} {
+		require.Equal(t, tc.expected, c.Partition(tc.v), "%s", tc.v)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	require.Error(t, (&Config{Lists: 0}).Validate())
+	require.Error(t, (&Config{Lists: MaxLists + 1}).Validate())
+	require.True(t, (&Config{}).IsEmpty())
+	require.False(t, DefaultConfig().IsEmpty())
+}
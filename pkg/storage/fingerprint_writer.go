@@ -219,6 +219,35 @@ func (f *fingerprintWriter) stripIndexPrefix(key []byte) []byte {
 	return remainder
 }
 
+// PointKeyFingerprinter maintains a XOR aggregate of the fingerprints of the
+// point keys added to it, hashed the same way as by MVCCExportFingerprint. It
+// lets callers that already iterate over point keys, for instance when reading
+// backup files, fingerprint them without going through an export.
+type PointKeyFingerprinter struct {
+	fw fingerprintWriter
+}
+
+// MakePointKeyFingerprinter creates a new PointKeyFingerprinter.
+func MakePointKeyFingerprinter(opts MVCCExportFingerprintOptions) PointKeyFingerprinter {
+	return PointKeyFingerprinter{fw: fingerprintWriter{
+		hasher:  fnv.New64(),
+		xorAgg:  &uintXorAggregate{},
+		options: opts,
+	}}
+}
+
+// Add adds the fingerprint of the given point key and its value to the
+// aggregate. Keys of the tables which fingerprinting ignores are skipped.
+func (f *PointKeyFingerprinter) Add(key MVCCKey, value []byte) error {
+	return f.fw.PutRawMVCC(key, value)
+}
+
+// Fingerprint returns the aggregated fingerprint of the point keys added so
+// far.
+func (f *PointKeyFingerprinter) Fingerprint() uint64 {
+	return f.fw.xorAgg.result()
+}
+
 // FingerprintRangekeys iterates over the provided SSTs, that are expected to
 // contain only rangekeys, and maintains a XOR aggregate of each rangekey's
 // fingerprint.
@@ -6802,6 +6802,8 @@ func TestMVCCExportFingerprint(t *testing.T) {
 		expectedFingerprint, expectedRangeKeys := oracle.getFingerprintAndRangeKeys(ctx, t)
 		require.Equal(t, expectedFingerprint, actualFingerprint)
 		require.Equal(t, expectedRangeKeys, actualRangekeys)
+		// A PointKeyFingerprinter fed the same point keys agrees with the oracle.
+		require.Equal(t, expectedFingerprint, oracle.getPointKeyFingerprinterFingerprint(ctx, t))
 	}
 
 	engine := createTestPebbleEngine()
@@ -7091,6 +7093,39 @@ func (f *fingerprintOracle) fingerprintPointKeys(t *testing.T, dataSST []byte) u
 	return xorAgg
 }
 
+// getPointKeyFingerprinterFingerprint exports the point keys in the interval
+// determined by the supplied `MVCCExportOptions` to a pebble SST, and returns
+// the fingerprint that a PointKeyFingerprinter computes over them.
+func (f *fingerprintOracle) getPointKeyFingerprinterFingerprint(
+	ctx context.Context, t *testing.T,
+) uint64 {
+	t.Helper()
+
+	var dest bytes.Buffer
+	_, _, err := MVCCExportToSST(ctx, f.st, f.engine, *f.opts, &dest)
+	require.NoError(t, err)
+	iter, err := NewMemSSTIterator(dest.Bytes(), false, IterOptions{
+		KeyTypes:   IterKeyTypePointsOnly,
+		LowerBound: keys.LocalMax,
+		UpperBound: keys.MaxKey,
+	})
+	require.NoError(t, err)
+	defer iter.Close()
+
+	fingerprinter := MakePointKeyFingerprinter(f.opts.FingerprintOptions)
+	for iter.SeekGE(MVCCKey{Key: keys.MinKey}); ; iter.Next() {
+		valid, err := iter.Valid()
+		require.NoError(t, err)
+		if !valid {
+			break
+		}
+		v, err := iter.UnsafeValue()
+		require.NoError(t, err)
+		require.NoError(t, fingerprinter.Add(iter.UnsafeKey(), v))
+	}
+	return fingerprinter.Fingerprint()
+}
+
 func getRangeKeys(t *testing.T, dataSST []byte) []MVCCRangeKeyStack {
 	t.Helper()
 
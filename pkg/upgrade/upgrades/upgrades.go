@@ -330,6 +330,18 @@ var upgrades = []upgradebase.Upgrade{
 		upgrade.NoPrecondition,
 		grantExecuteToPublicOnAllFunctions,
 	),
+	upgrade.NewTenantUpgrade(
+		"create system.notifications table",
+		toCV(clusterversion.V23_2_NotificationsTable),
+		upgrade.NoPrecondition,
+		createNotificationsTable,
+	),
+	upgrade.NewTenantUpgrade(
+		"create system.publications and system.replication_slots tables",
+		toCV(clusterversion.V23_2_LogicalReplication),
+		upgrade.NoPrecondition,
+		createLogicalReplicationTables,
+	),
 }
 
 var (
@@ -0,0 +1,139 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import "time"
+
+// datetimeKind identifies the SQL type of a value produced by the
+// .datetime() item method.
+type datetimeKind int
+
+const (
+	dtDate datetimeKind = iota
+	dtTime
+	dtTimeTZ
+	dtTimestamp
+	dtTimestampTZ
+)
+
+// datetime is a date or time value produced by the .datetime() item method.
+// Values without a time zone are stored in UTC.
+type datetime struct {
+	kind datetimeKind
+	t    time.Time
+}
+
+// datetimeFormats lists the ISO 8601 formats recognized by .datetime() when
+// no template is given, in the same order that Postgres tries them. time.Parse
+// accepts fractional seconds after the seconds field even though the layouts
+// do not mention them.
+var datetimeFormats = []struct {
+	layout string
+	kind   datetimeKind
+}{
+	{"2006-01-02", dtDate},
+	{"15:04:05-07:00", dtTimeTZ},
+	{"15:04:05-07", dtTimeTZ},
+	{"15:04:05", dtTime},
+	{"2006-01-02 15:04:05-07:00", dtTimestampTZ},
+	{"2006-01-02 15:04:05-07", dtTimestampTZ},
+	{"2006-01-02 15:04:05", dtTimestamp},
+	{"2006-01-02T15:04:05-07:00", dtTimestampTZ},
+	{"2006-01-02T15:04:05-07", dtTimestampTZ},
+	{"2006-01-02T15:04:05", dtTimestamp},
+}
+
+// parseDatetime parses s using the first matching format of
+// datetimeFormats. It returns false if no format matches.
+func parseDatetime(s string) (*datetime, bool) {
+	for _, f := range datetimeFormats {
+		if t, err := time.Parse(f.layout, s); err == nil {
+			return &datetime{kind: f.kind, t: t}, true
+		}
+	}
+	return nil, false
+}
+
+// String returns the ISO 8601 representation of the value, which is used
+// when the value is converted back to JSON.
+func (d *datetime) String() string {
+	switch d.kind {
+	case dtDate:
+		return d.t.Format("2006-01-02")
+	case dtTime:
+		return d.t.Format("15:04:05.999999")
+	case dtTimeTZ:
+		return d.t.Format("15:04:05.999999-07:00")
+	case dtTimestamp:
+		return d.t.Format("2006-01-02T15:04:05.999999")
+	default:
+		return d.t.Format("2006-01-02T15:04:05.999999-07:00")
+	}
+}
+
+// typeName returns the result of the .type() item method for the value.
+func (d *datetime) typeName() string {
+	switch d.kind {
+	case dtDate:
+		return "date"
+	case dtTime:
+		return "time without time zone"
+	case dtTimeTZ:
+		return "time with time zone"
+	case dtTimestamp:
+		return "timestamp without time zone"
+	default:
+		return "timestamp with time zone"
+	}
+}
+
+// compareDatetime compares two datetime values. It returns false if the
+// values are not comparable, either because they have unrelated types or
+// because the comparison would depend on the session time zone.
+func compareDatetime(a, b *datetime) (int, bool) {
+	switch {
+	case a.kind == b.kind:
+	case (a.kind == dtDate || a.kind == dtTimestamp) &&
+		(b.kind == dtDate || b.kind == dtTimestamp):
+	default:
+		return 0, false
+	}
+	if c := compareTimes(a.t, b.t); c != 0 || a.kind != dtTimeTZ {
+		return c, true
+	}
+	// Times with time zone that represent the same instant are ordered by
+	// their offset from UTC.
+	_, aOffset := a.t.Zone()
+	_, bOffset := b.t.Zone()
+	return compareInts(bOffset, aOffset), true
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
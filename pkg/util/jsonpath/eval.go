@@ -0,0 +1,993 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+var (
+	// decimalCtx, exactCtx and highPrecisionCtx match the contexts used for
+	// DECIMAL arithmetic in SQL.
+	decimalCtx = &apd.Context{
+		Precision:   20,
+		Rounding:    apd.RoundHalfUp,
+		MaxExponent: 2000,
+		MinExponent: -2000,
+		Traps:       apd.DefaultTraps,
+	}
+	exactCtx         = decimalCtx.WithPrecision(0)
+	highPrecisionCtx = decimalCtx.WithPrecision(2000)
+)
+
+// item is a value produced while evaluating a path. Values produced by the
+// .datetime() method have no JSON equivalent, so they are kept separately
+// until they are returned.
+type item struct {
+	j  json.JSON
+	dt *datetime
+}
+
+func jsonItem(j json.JSON) item {
+	return item{j: j}
+}
+
+// toJSON returns the JSON representation of the item.
+func (it item) toJSON() json.JSON {
+	if it.dt != nil {
+		return json.FromString(it.dt.String())
+	}
+	return it.j
+}
+
+func (it item) isArray() bool {
+	return it.dt == nil && it.j.Type() == json.ArrayJSONType
+}
+
+func (it item) isObject() bool {
+	return it.dt == nil && it.j.Type() == json.ObjectJSONType
+}
+
+// asDecimal returns the numeric value of the item, or nil if it is not a
+// number.
+func (it item) asDecimal() *apd.Decimal {
+	if it.dt != nil {
+		return nil
+	}
+	d, ok := it.j.AsDecimal()
+	if !ok {
+		return nil
+	}
+	return d
+}
+
+// asString returns the string value of the item, or false if it is not a
+// string.
+func (it item) asString() (string, bool) {
+	if it.dt != nil || it.j.Type() != json.StringJSONType {
+		return "", false
+	}
+	s, err := it.j.AsText()
+	if err != nil || s == nil {
+		return "", false
+	}
+	return *s, true
+}
+
+// elements returns the elements of an array item.
+func (it item) elements() []item {
+	arr, _ := it.j.AsArray()
+	res := make([]item, len(arr))
+	for i := range arr {
+		res[i] = jsonItem(arr[i])
+	}
+	return res
+}
+
+// tribool is the result of a predicate, which can be unknown if it could not
+// be evaluated.
+type tribool int
+
+const (
+	triFalse tribool = iota
+	triTrue
+	triUnknown
+)
+
+func makeTribool(b bool) tribool {
+	if b {
+		return triTrue
+	}
+	return triFalse
+}
+
+// execError wraps an error raised by the evaluation of a path that can be
+// suppressed, either with the silent argument of the jsonb_path_* builtins
+// or by being raised while evaluating a predicate, in which case the
+// predicate evaluates to unknown. Errors that are not wrapped are always
+// returned.
+type execError struct {
+	cause error
+}
+
+func (e *execError) Error() string { return e.cause.Error() }
+
+// Unwrap implements the errors.Wrapper interface.
+func (e *execError) Unwrap() error { return e.cause }
+
+func execErrorf(code pgcode.Code, format string, args ...interface{}) error {
+	return &execError{cause: pgerror.Newf(code, format, args...)}
+}
+
+func isExecError(err error) bool {
+	var e *execError
+	return errors.As(err, &e)
+}
+
+// errStop is returned by an emit function to stop the evaluation early once
+// the result is known.
+var errStop = errors.New("stop evaluation")
+
+// emitFn receives the items produced by an expression, one at a time.
+type emitFn func(item) error
+
+// evaluator holds the state of the evaluation of a path. Items are produced
+// depth-first, so that the items emitted before an error are the same as in
+// Postgres.
+type evaluator struct {
+	strict bool
+	root   json.JSON
+	vars   json.JSON
+	// ignoreStructuralErrors is true if structural errors, such as a missing
+	// key, produce no items instead of an error. It is set in lax mode and
+	// while evaluating the accessors following .**.
+	ignoreStructuralErrors bool
+	// arraySize is the size of the innermost array being subscripted, which
+	// is used to evaluate LAST, or -1 outside of subscripts.
+	arraySize int
+	// nextID is used to generate the id field of the .keyvalue() results.
+	nextID int64
+}
+
+// lax returns whether arrays are automatically wrapped and unwrapped.
+func (e *evaluator) lax() bool {
+	return !e.strict
+}
+
+// execute evaluates the path against target, passing each resulting item to
+// emit.
+func execute(jp *Jsonpath, target, vars json.JSON, emit emitFn) error {
+	if vars != nil && vars.Type() != json.ObjectJSONType && vars.Type() != json.NullJSONType {
+		return errors.WithDetail(
+			pgerror.New(pgcode.InvalidParameterValue, `"vars" argument is not an object`),
+			"Jsonb object is expected.",
+		)
+	}
+	e := evaluator{
+		strict:                 jp.Strict,
+		root:                   target,
+		vars:                   vars,
+		ignoreStructuralErrors: !jp.Strict,
+		arraySize:              -1,
+	}
+	return e.eval(jp.Expr, jsonItem(target), emit)
+}
+
+// finish converts the error returned by execute into the error returned to
+// the caller. It returns true if a suppressible error occurred and silent is
+// set.
+func finish(err error, silent bool) (suppressed bool, _ error) {
+	var e *execError
+	if errors.As(err, &e) {
+		if silent {
+			return true, nil
+		}
+		return false, e.cause
+	}
+	return false, err
+}
+
+// Query evaluates the path against target and returns the selected items, as
+// jsonb_path_query does. vars is an object containing the values of the
+// variables referenced by the path, and may be nil. If silent is true,
+// errors raised during the evaluation are suppressed and the items selected
+// until then are returned.
+func Query(jp *Jsonpath, target, vars json.JSON, silent bool) ([]json.JSON, error) {
+	var res []json.JSON
+	err := execute(jp, target, vars, func(it item) error {
+		res = append(res, it.toJSON())
+		return nil
+	})
+	if _, err := finish(err, silent); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Exists returns whether the path selects any item from target, as
+// jsonb_path_exists does. The result is NULL if an error was suppressed.
+func Exists(jp *Jsonpath, target, vars json.JSON, silent bool) (res bool, isNull bool, _ error) {
+	err := execute(jp, target, vars, func(item) error {
+		res = true
+		// In strict mode, the evaluation has to continue to check whether any
+		// error occurs.
+		if jp.Strict {
+			return nil
+		}
+		return errStop
+	})
+	if errors.Is(err, errStop) {
+		return true, false, nil
+	}
+	suppressed, err := finish(err, silent)
+	if err != nil || suppressed {
+		return false, true, err
+	}
+	return res, false, nil
+}
+
+// Match returns the result of a path predicate evaluated against target, as
+// jsonb_path_match does. The result is NULL if the predicate is unknown or
+// an error was suppressed.
+func Match(jp *Jsonpath, target, vars json.JSON, silent bool) (res bool, isNull bool, _ error) {
+	var items []item
+	err := execute(jp, target, vars, func(it item) error {
+		items = append(items, it)
+		return nil
+	})
+	if suppressed, err := finish(err, silent); err != nil || suppressed {
+		return false, true, err
+	}
+	if len(items) == 1 && items[0].dt == nil {
+		switch items[0].j.Type() {
+		case json.TrueJSONType:
+			return true, false, nil
+		case json.FalseJSONType:
+			return false, false, nil
+		case json.NullJSONType:
+			return false, true, nil
+		}
+	}
+	if silent {
+		return false, true, nil
+	}
+	return false, false, pgerror.New(pgcode.SingletonSQLJSONItemRequired, "single boolean result is expected")
+}
+
+// eval evaluates an expression, passing each resulting item to emit. cur is
+// the item bound to @.
+func (e *evaluator) eval(expr Expr, cur item, emit emitFn) error {
+	switch t := expr.(type) {
+	case Root:
+		return emit(jsonItem(e.root))
+	case Current:
+		return emit(cur)
+	case Last:
+		if e.arraySize < 0 {
+			return errors.AssertionFailedf("evaluating jsonpath LAST outside of array subscript")
+		}
+		return emit(jsonItem(json.FromInt(e.arraySize - 1)))
+	case *Variable:
+		var v json.JSON
+		if e.vars != nil {
+			var err error
+			if v, err = e.vars.FetchValKey(t.Name); err != nil {
+				return err
+			}
+		}
+		if v == nil {
+			return pgerror.Newf(pgcode.UndefinedObject, "could not find jsonpath variable %q", t.Name)
+		}
+		return emit(jsonItem(v))
+	case *Literal:
+		return emit(jsonItem(t.toJSON()))
+	case *Path:
+		return e.eval(t.Start, cur, func(it item) error {
+			return e.evalSteps(t.Steps, it, cur, e.lax(), emit)
+		})
+	case *Binary:
+		if t.Op.isArithmetic() {
+			return e.evalArithmetic(t, cur, emit)
+		}
+	case *Unary:
+		if t.Op != OpNot {
+			return e.evalUnary(t, cur, emit)
+		}
+	}
+	res, err := e.evalPredicate(expr, cur)
+	if err != nil {
+		return err
+	}
+	switch res {
+	case triTrue:
+		return emit(jsonItem(json.TrueJSONValue))
+	case triFalse:
+		return emit(jsonItem(json.FalseJSONValue))
+	default:
+		return emit(jsonItem(json.NullJSONValue))
+	}
+}
+
+func (l *Literal) toJSON() json.JSON {
+	switch l.Kind {
+	case BoolLiteral:
+		return json.FromBool(l.Bool)
+	case NumLiteral:
+		return json.FromDecimal(l.Num)
+	case StrLiteral:
+		return json.FromString(l.Str)
+	default:
+		return json.NullJSONValue
+	}
+}
+
+// collect evaluates an expression and returns all of the resulting items. In
+// lax mode, arrays in the result are unwrapped if unwrap is true.
+func (e *evaluator) collect(expr Expr, cur item, unwrap bool) ([]item, error) {
+	var res []item
+	err := e.eval(expr, cur, func(it item) error {
+		if unwrap && e.lax() && it.isArray() {
+			res = append(res, it.elements()...)
+		} else {
+			res = append(res, it)
+		}
+		return nil
+	})
+	return res, err
+}
+
+// evalSteps applies a chain of accessors to an item. unwrap is true if the
+// first accessor should unwrap an array item in lax mode.
+func (e *evaluator) evalSteps(steps []Step, it, cur item, unwrap bool, emit emitFn) error {
+	if len(steps) == 0 {
+		return emit(it)
+	}
+	next := func(it item) error {
+		return e.evalSteps(steps[1:], it, cur, e.lax(), emit)
+	}
+	// unwrapArray applies the accessor to each element of an array item.
+	unwrapArray := func() error {
+		for _, elem := range it.elements() {
+			if err := e.evalSteps(steps, elem, cur, false /* unwrap */, emit); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	switch s := steps[0].(type) {
+	case *Key:
+		switch {
+		case it.isObject():
+			v, err := it.j.FetchValKey(s.Name)
+			if err != nil {
+				return err
+			}
+			if v != nil {
+				return next(jsonItem(v))
+			}
+			if !e.ignoreStructuralErrors {
+				return execErrorf(pgcode.SQLJSONMemberNotFound, "JSON object does not contain key %q", s.Name)
+			}
+		case unwrap && it.isArray():
+			return unwrapArray()
+		case !e.ignoreStructuralErrors:
+			return execErrorf(pgcode.SQLJSONMemberNotFound, "jsonpath member accessor can only be applied to an object")
+		}
+		return nil
+
+	case AnyKey:
+		switch {
+		case it.isObject():
+			iter, err := it.j.ObjectIter()
+			if err != nil {
+				return err
+			}
+			for iter.Next() {
+				if err := next(jsonItem(iter.Value())); err != nil {
+					return err
+				}
+			}
+		case unwrap && it.isArray():
+			return unwrapArray()
+		case !e.ignoreStructuralErrors:
+			return execErrorf(pgcode.SQLJSONMemberNotFound,
+				"jsonpath wildcard member accessor can only be applied to an object")
+		}
+		return nil
+
+	case AnyIndex:
+		switch {
+		case it.isArray():
+			for _, elem := range it.elements() {
+				if err := next(elem); err != nil {
+					return err
+				}
+			}
+		case e.lax():
+			return next(it)
+		case !e.ignoreStructuralErrors:
+			return execErrorf(pgcode.SQLJSONArrayNotFound,
+				"jsonpath wildcard array accessor can only be applied to an array")
+		}
+		return nil
+
+	case *Index:
+		return e.evalIndex(s, it, cur, next)
+
+	case *Recursive:
+		if s.From == 0 {
+			if err := e.ignoringStructuralErrors(func() error { return next(it) }); err != nil {
+				return err
+			}
+		}
+		return e.evalRecursive(s, it, 1 /* level */, next)
+
+	case *Filter:
+		if unwrap && it.isArray() {
+			return unwrapArray()
+		}
+		res, err := e.evalPredicate(s.Pred, it)
+		if err != nil || res != triTrue {
+			return err
+		}
+		return next(it)
+
+	case *Method:
+		if unwrap && it.isArray() && s.Name != "type" && s.Name != "size" {
+			return unwrapArray()
+		}
+		return e.evalMethod(s, it, next)
+	}
+	return errors.AssertionFailedf("unknown jsonpath accessor %T", steps[0])
+}
+
+// ignoringStructuralErrors calls fn with structural errors ignored.
+func (e *evaluator) ignoringStructuralErrors(fn func() error) error {
+	defer func(ignore bool) { e.ignoreStructuralErrors = ignore }(e.ignoreStructuralErrors)
+	e.ignoreStructuralErrors = true
+	return fn()
+}
+
+// evalRecursive implements the .** accessor for the children of it, which
+// are at the given nesting level.
+func (e *evaluator) evalRecursive(s *Recursive, it item, level int, next emitFn) error {
+	var children []item
+	switch {
+	case it.isArray():
+		children = it.elements()
+	case it.isObject():
+		iter, err := it.j.ObjectIter()
+		if err != nil {
+			return err
+		}
+		for iter.Next() {
+			children = append(children, jsonItem(iter.Value()))
+		}
+	default:
+		return nil
+	}
+	for _, child := range children {
+		isContainer := child.isArray() || child.isObject()
+		var selected bool
+		if s.From == LastLevel {
+			// The LAST level selects the leaves of the document.
+			selected = !isContainer
+		} else {
+			selected = level >= s.From && (s.To == LastLevel || level <= s.To)
+		}
+		if selected {
+			if err := e.ignoringStructuralErrors(func() error { return next(child) }); err != nil {
+				return err
+			}
+		}
+		if isContainer && (s.To == LastLevel || level < s.To) {
+			if err := e.evalRecursive(s, child, level+1, next); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// evalIndex implements the [subscript, ...] accessor. In lax mode, a
+// non-array item is treated as an array containing only that item.
+func (e *evaluator) evalIndex(s *Index, it, cur item, next emitFn) error {
+	var elems []item
+	switch {
+	case it.isArray():
+		elems = it.elements()
+	case e.lax():
+		elems = []item{it}
+	case !e.ignoreStructuralErrors:
+		return execErrorf(pgcode.SQLJSONArrayNotFound, "jsonpath array accessor can only be applied to an array")
+	default:
+		return nil
+	}
+	defer func(size int) { e.arraySize = size }(e.arraySize)
+	e.arraySize = len(elems)
+	for _, sub := range s.Subscripts {
+		from, err := e.evalSubscript(sub.From, cur)
+		if err != nil {
+			return err
+		}
+		to := from
+		if sub.To != nil {
+			if to, err = e.evalSubscript(sub.To, cur); err != nil {
+				return err
+			}
+		}
+		if !e.ignoreStructuralErrors && (from < 0 || from > to || to >= len(elems)) {
+			return execErrorf(pgcode.InvalidSQLJSONSubscript, "jsonpath array subscript is out of bounds")
+		}
+		if from < 0 {
+			from = 0
+		}
+		if to >= len(elems) {
+			to = len(elems) - 1
+		}
+		for i := from; i <= to; i++ {
+			if err := next(elems[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// evalSubscript evaluates an array subscript, which must be a single number.
+// The number is truncated to an integer.
+func (e *evaluator) evalSubscript(expr Expr, cur item) (int, error) {
+	items, err := e.collect(expr, cur, false /* unwrap */)
+	if err != nil {
+		return 0, err
+	}
+	var d *apd.Decimal
+	if len(items) == 1 {
+		d = items[0].asDecimal()
+	}
+	if d == nil {
+		return 0, execErrorf(pgcode.InvalidSQLJSONSubscript, "jsonpath array subscript is not a single numeric value")
+	}
+	var truncated apd.Decimal
+	d.Modf(&truncated, nil /* frac */)
+	i, err := truncated.Int64()
+	if err != nil || i > math.MaxInt32 || i < math.MinInt32 {
+		return 0, execErrorf(pgcode.InvalidSQLJSONSubscript, "jsonpath array subscript is out of integer range")
+	}
+	return int(i), nil
+}
+
+// evalMethod evaluates an item method.
+func (e *evaluator) evalMethod(m *Method, it item, next emitFn) error {
+	switch m.Name {
+	case "type":
+		return next(jsonItem(json.FromString(typeName(it))))
+
+	case "size":
+		if it.isArray() {
+			return next(jsonItem(json.FromInt(it.j.Len())))
+		}
+		if e.lax() {
+			return next(jsonItem(json.FromInt(1)))
+		}
+		if !e.ignoreStructuralErrors {
+			return execErrorf(pgcode.SQLJSONArrayNotFound, "jsonpath item method .size() can only be applied to an array")
+		}
+		return nil
+
+	case "abs", "floor", "ceiling":
+		d := it.asDecimal()
+		if d == nil {
+			return execErrorf(pgcode.NonNumericSQLJSONItem,
+				"jsonpath item method .%s() can only be applied to a numeric value", m.Name)
+		}
+		var res apd.Decimal
+		var err error
+		switch m.Name {
+		case "abs":
+			res.Abs(d)
+		case "floor":
+			_, err = exactCtx.Floor(&res, d)
+		case "ceiling":
+			_, err = exactCtx.Ceil(&res, d)
+		}
+		if err != nil {
+			return err
+		}
+		return next(jsonItem(json.FromDecimal(res)))
+
+	case "double":
+		var f float64
+		if d := it.asDecimal(); d != nil {
+			var err error
+			if f, err = d.Float64(); err != nil {
+				return execErrorf(pgcode.NonNumericSQLJSONItem,
+					"numeric argument of jsonpath item method .double() is out of range for type double precision")
+			}
+		} else if s, ok := it.asString(); ok {
+			var err error
+			if f, err = strconv.ParseFloat(strings.TrimSpace(s), 64); err != nil {
+				return execErrorf(pgcode.NonNumericSQLJSONItem,
+					"string argument of jsonpath item method .double() is not a valid representation of a double precision number")
+			}
+		} else {
+			return execErrorf(pgcode.NonNumericSQLJSONItem,
+				"jsonpath item method .double() can only be applied to a string or numeric value")
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return execErrorf(pgcode.NonNumericSQLJSONItem,
+				"NaN or Infinity is not allowed for jsonpath item method .double()")
+		}
+		var res apd.Decimal
+		if _, err := res.SetFloat64(f); err != nil {
+			return err
+		}
+		return next(jsonItem(json.FromDecimal(res)))
+
+	case "keyvalue":
+		if !it.isObject() {
+			return execErrorf(pgcode.SQLJSONObjectNotFound,
+				"jsonpath item method .keyvalue() can only be applied to an object")
+		}
+		id := e.nextID
+		e.nextID++
+		iter, err := it.j.ObjectIter()
+		if err != nil {
+			return err
+		}
+		for iter.Next() {
+			b := json.NewObjectBuilder(3)
+			b.Add("id", json.FromInt64(id))
+			b.Add("key", json.FromString(iter.Key()))
+			b.Add("value", iter.Value())
+			if err := next(jsonItem(b.Build())); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "datetime":
+		s, ok := it.asString()
+		if !ok {
+			return execErrorf(pgcode.InvalidArgumentForSQLJSONDatetimeFunction,
+				"jsonpath item method .datetime() can only be applied to a string")
+		}
+		if m.Arg != nil {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"jsonpath item method .datetime() with a template is not supported")
+		}
+		dt, ok := parseDatetime(s)
+		if !ok {
+			return &execError{cause: errors.WithHint(
+				pgerror.Newf(pgcode.InvalidArgumentForSQLJSONDatetimeFunction,
+					"datetime format is not recognized: %q", s),
+				"Use a datetime template argument to specify the input data format.",
+			)}
+		}
+		return next(item{dt: dt})
+	}
+	return errors.AssertionFailedf("unknown jsonpath item method .%s()", m.Name)
+}
+
+// typeName returns the result of the .type() item method.
+func typeName(it item) string {
+	if it.dt != nil {
+		return it.dt.typeName()
+	}
+	switch it.j.Type() {
+	case json.NullJSONType:
+		return "null"
+	case json.TrueJSONType, json.FalseJSONType:
+		return "boolean"
+	case json.NumberJSONType:
+		return "number"
+	case json.StringJSONType:
+		return "string"
+	case json.ArrayJSONType:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// evalArithmetic evaluates a binary arithmetic operator, whose operands must
+// both be single numbers.
+func (e *evaluator) evalArithmetic(b *Binary, cur item, emit emitFn) error {
+	operand := func(expr Expr, side string) (*apd.Decimal, error) {
+		items, err := e.collect(expr, cur, true /* unwrap */)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 1 {
+			if d := items[0].asDecimal(); d != nil {
+				return d, nil
+			}
+		}
+		return nil, execErrorf(pgcode.SingletonSQLJSONItemRequired,
+			"%s operand of jsonpath operator %s is not a single numeric value", side, b.Op)
+	}
+	left, err := operand(b.Left, "left")
+	if err != nil {
+		return err
+	}
+	right, err := operand(b.Right, "right")
+	if err != nil {
+		return err
+	}
+	var res apd.Decimal
+	switch b.Op {
+	case OpAdd:
+		_, err = exactCtx.Add(&res, left, right)
+	case OpSub:
+		_, err = exactCtx.Sub(&res, left, right)
+	case OpMul:
+		_, err = exactCtx.Mul(&res, left, right)
+	case OpDiv, OpMod:
+		if right.IsZero() {
+			return execErrorf(pgcode.DivisionByZero, "division by zero")
+		}
+		if b.Op == OpDiv {
+			_, err = decimalCtx.Quo(&res, left, right)
+		} else {
+			_, err = highPrecisionCtx.Rem(&res, left, right)
+		}
+	}
+	if err != nil {
+		return &execError{cause: pgerror.WithCandidateCode(err, pgcode.NumericValueOutOfRange)}
+	}
+	return emit(jsonItem(json.FromDecimal(res)))
+}
+
+// evalUnary evaluates the unary + and - operators, which are applied to each
+// item of the operand.
+func (e *evaluator) evalUnary(u *Unary, cur item, emit emitFn) error {
+	items, err := e.collect(u.Operand, cur, true /* unwrap */)
+	if err != nil {
+		return err
+	}
+	for _, it := range items {
+		d := it.asDecimal()
+		if d == nil {
+			return execErrorf(pgcode.SQLJSONNumberNotFound,
+				"operand of unary jsonpath operator %s is not a numeric value", u.Op)
+		}
+		if u.Op == OpMinus {
+			var res apd.Decimal
+			res.Neg(d)
+			it = jsonItem(json.FromDecimal(res))
+		}
+		if err := emit(it); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evalPredicate evaluates a predicate with three-valued logic. Suppressible
+// errors raised while evaluating the operands make the predicate unknown.
+func (e *evaluator) evalPredicate(expr Expr, cur item) (tribool, error) {
+	switch t := expr.(type) {
+	case *Binary:
+		switch t.Op {
+		case OpAnd:
+			left, err := e.evalPredicate(t.Left, cur)
+			if err != nil || left == triFalse {
+				return left, err
+			}
+			right, err := e.evalPredicate(t.Right, cur)
+			if err != nil || right == triTrue {
+				return left, err
+			}
+			return right, nil
+		case OpOr:
+			left, err := e.evalPredicate(t.Left, cur)
+			if err != nil || left == triTrue {
+				return left, err
+			}
+			right, err := e.evalPredicate(t.Right, cur)
+			if err != nil || right == triFalse {
+				return left, err
+			}
+			return right, nil
+		}
+		return e.evalComparison(t.Left, t.Right, true /* unwrapRight */, cur, func(l, r item) tribool {
+			return compareItems(t.Op, l, r)
+		})
+	case *Unary:
+		res, err := e.evalPredicate(t.Operand, cur)
+		if err != nil {
+			return res, err
+		}
+		switch res {
+		case triTrue:
+			return triFalse, nil
+		case triFalse:
+			return triTrue, nil
+		}
+		return triUnknown, nil
+	case *IsUnknown:
+		res, err := e.evalPredicate(t.Pred, cur)
+		return makeTribool(res == triUnknown), err
+	case *ExistsExpr:
+		var found bool
+		err := e.eval(t.Expr, cur, func(item) error {
+			found = true
+			if e.strict {
+				return nil
+			}
+			return errStop
+		})
+		if err != nil && !errors.Is(err, errStop) {
+			if isExecError(err) {
+				return triUnknown, nil
+			}
+			return triUnknown, err
+		}
+		return makeTribool(found), nil
+	case *LikeRegex:
+		return e.evalComparison(t.Expr, nil, false /* unwrapRight */, cur, func(l, _ item) tribool {
+			s, ok := l.asString()
+			if !ok {
+				return triUnknown
+			}
+			return makeTribool(t.re.MatchString(s))
+		})
+	case *StartsWith:
+		return e.evalComparison(t.Expr, t.Prefix, false /* unwrapRight */, cur, func(l, r item) tribool {
+			s, ok := l.asString()
+			prefix, ok2 := r.asString()
+			if !ok || !ok2 {
+				return triUnknown
+			}
+			return makeTribool(strings.HasPrefix(s, prefix))
+		})
+	}
+	return triUnknown, errors.AssertionFailedf("unexpected jsonpath predicate %T", expr)
+}
+
+// evalComparison evaluates a predicate that is true if cmp is true for any
+// pair of items of the left and right operands. The left operand is unwrapped
+// in lax mode, and so is the right one if unwrapRight is true. If right is
+// nil, cmp is called with each item of the left operand only. In strict mode, the
+// predicate is unknown if cmp is unknown for any pair; in lax mode, it is
+// true as soon as cmp is true for a pair.
+func (e *evaluator) evalComparison(
+	left, right Expr, unwrapRight bool, cur item, cmp func(l, r item) tribool,
+) (tribool, error) {
+	collect := func(expr Expr, unwrap bool) ([]item, bool, error) {
+		items, err := e.collect(expr, cur, unwrap)
+		if err != nil {
+			if isExecError(err) {
+				return nil, false, nil
+			}
+			return nil, false, err
+		}
+		return items, true, nil
+	}
+	lItems, ok, err := collect(left, true /* unwrap */)
+	if err != nil || !ok {
+		return triUnknown, err
+	}
+	rItems := []item{{}}
+	if right != nil {
+		if rItems, ok, err = collect(right, unwrapRight); err != nil || !ok {
+			return triUnknown, err
+		}
+	}
+	found, unknown := false, false
+	for _, l := range lItems {
+		for _, r := range rItems {
+			switch cmp(l, r) {
+			case triUnknown:
+				if e.strict {
+					return triUnknown, nil
+				}
+				unknown = true
+			case triTrue:
+				if !e.strict {
+					return triTrue, nil
+				}
+				found = true
+			}
+		}
+	}
+	switch {
+	case found:
+		return triTrue, nil
+	case unknown:
+		return triUnknown, nil
+	}
+	return triFalse, nil
+}
+
+// compareItems compares two items with a comparison operator. Items of
+// different types are not comparable, except that null is not equal to any
+// other value. Arrays and objects are not comparable either.
+func compareItems(op Operator, l, r item) tribool {
+	var c int
+	switch {
+	case l.dt != nil && r.dt != nil:
+		var ok bool
+		if c, ok = compareDatetime(l.dt, r.dt); !ok {
+			return triUnknown
+		}
+	case l.dt != nil || r.dt != nil:
+		if (l.dt == nil && l.j.Type() == json.NullJSONType) ||
+			(r.dt == nil && r.j.Type() == json.NullJSONType) {
+			return makeTribool(op == OpNe)
+		}
+		return triUnknown
+	default:
+		lt, rt := scalarType(l.j), scalarType(r.j)
+		if lt != rt {
+			if lt == json.NullJSONType || rt == json.NullJSONType {
+				return makeTribool(op == OpNe)
+			}
+			return triUnknown
+		}
+		switch lt {
+		case json.NullJSONType:
+		case json.TrueJSONType:
+			lb, _ := l.j.AsBool()
+			rb, _ := r.j.AsBool()
+			c = compareInts(boolToInt(lb), boolToInt(rb))
+		case json.NumberJSONType:
+			ld, _ := l.j.AsDecimal()
+			rd, _ := r.j.AsDecimal()
+			c = ld.Cmp(rd)
+		case json.StringJSONType:
+			ls, _ := l.asString()
+			rs, _ := r.asString()
+			c = strings.Compare(ls, rs)
+		default:
+			return triUnknown
+		}
+	}
+	switch op {
+	case OpEq:
+		return makeTribool(c == 0)
+	case OpNe:
+		return makeTribool(c != 0)
+	case OpLt:
+		return makeTribool(c < 0)
+	case OpLe:
+		return makeTribool(c <= 0)
+	case OpGt:
+		return makeTribool(c > 0)
+	case OpGe:
+		return makeTribool(c >= 0)
+	}
+	return triUnknown
+}
+
+// scalarType returns the type of a JSON value, with false and true merged
+// into TrueJSONType.
+func scalarType(j json.JSON) json.Type {
+	if t := j.Type(); t != json.FalseJSONType {
+		return t
+	}
+	return json.TrueJSONType
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,197 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	const doc = `{
+		"a": {"b": [1, 2, 3], "c": "xyz"},
+		"items": [
+			{"name": "apple", "price": 1.5, "tags": ["fruit"]},
+			{"name": "bread", "price": 3, "tags": []},
+			{"name": "cheese", "price": 10, "tags": ["dairy", "aged"]}
+		],
+		"n": null,
+		"d": "2023-05-01",
+		"ts": "2023-05-01 12:30:00+02"
+	}`
+	for _, tc := range []struct {
+		path     string
+		vars     string
+		expected string
+	}{
+		{`$.a.c`, ``, `"xyz"`},
+		{`$.a.b`, ``, `[1, 2, 3]`},
+		{`$.a.b[*]`, ``, `1 2 3`},
+		{`$.a.b[0]`, ``, `1`},
+		{`$.a.b[last]`, ``, `3`},
+		{`$.a.b[1 to last]`, ``, `2 3`},
+		{`$.a.b[0, 2]`, ``, `1 3`},
+		{`$.a.b[10]`, ``, ``},
+		{`$.missing`, ``, ``},
+		{`$.a.*`, ``, `[1, 2, 3] "xyz"`},
+		{`$.items.name`, ``, `"apple" "bread" "cheese"`},
+		{`$.items[*] ? (@.price > 2).name`, ``, `"bread" "cheese"`},
+		{`$.items ? (@.price > 2).name`, ``, `"bread" "cheese"`},
+		{`$.items[*] ? (@.tags == "dairy").name`, ``, `"cheese"`},
+		{`$.items[*] ? (exists (@.tags[*])).name`, ``, `"apple" "cheese"`},
+		{`$.items[*] ? (@.name starts with "b").price`, ``, `3`},
+		{`$.items[*] ? (@.name like_regex "^A" flag "i").name`, ``, `"apple"`},
+		{`$.items[*] ? (@.price > $min).name`, `{"min": 5}`, `"cheese"`},
+		{`$.items[*] ? (@.price > 2 && @.price < 5).name`, ``, `"bread"`},
+		{`$.items[*] ? (!(@.price > 2)).name`, ``, `"apple"`},
+		{`$.items[*] ? ((@.name > 1) is unknown).name`, ``, `"apple" "bread" "cheese"`},
+		{`$.items.size()`, ``, `3`},
+		{`$.a.c.size()`, ``, `1`},
+		{`$.items[0].price + $.items[1].price`, ``, `4.5`},
+		{`$.a.b[2] * 2 - 1`, ``, `5`},
+		{`$.a.b[0] / 3`, ``, `0.33333333333333333333`},
+		{`$.a.b[2] % 2`, ``, `1`},
+		{`-$.a.b`, ``, `-1 -2 -3`},
+		{`$.a.b[*] > 2`, ``, `true`},
+		{`$.a.b[*] > 5`, ``, `false`},
+		{`$.a.c > 5`, ``, `null`},
+		{`$.n == null`, ``, `true`},
+		{`$.n != 1`, ``, `true`},
+		{`$.a.b.type()`, ``, `"array"`},
+		{`$.a.b[*].type()`, ``, `"number" "number" "number"`},
+		{`$.items[0].price.floor()`, ``, `1`},
+		{`$.items[0].price.ceiling()`, ``, `2`},
+		{`(-$.items[0].price).abs()`, ``, `1.5`},
+		{`"1.50".double()`, ``, `1.5`},
+		{`$.a.keyvalue()`, ``, `{"id": 0, "key": "b", "value": [1, 2, 3]} {"id": 0, "key": "c", "value": "xyz"}`},
+		// In lax mode, the array of items is also selected by .** and unwrapped
+		// by .price, so each price is selected twice.
+		{`$.**.price`, ``, `1.5 3 10 1.5 3 10`},
+		{`$.a.**{1}`, ``, `[1, 2, 3] "xyz"`},
+		{`$.a.**{last}`, ``, `1 2 3 "xyz"`},
+		{`$.d.datetime()`, ``, `"2023-05-01"`},
+		{`$.d.datetime().type()`, ``, `"date"`},
+		{`$.ts.datetime()`, ``, `"2023-05-01T12:30:00+02:00"`},
+		{`$.ts.datetime().type()`, ``, `"timestamp with time zone"`},
+		{`$.d.datetime() < "2024-01-01".datetime()`, ``, `true`},
+		{`$.d.datetime() == "2023-05-01 00:00:00".datetime()`, ``, `true`},
+		{`$.d.datetime() == "12:00:00".datetime()`, ``, `null`},
+		{`$.d.datetime() < $.ts.datetime()`, ``, `null`},
+		{`strict $.a.b[*]`, ``, `1 2 3`},
+		{`strict $.items[*] ? (@.price > 2).name`, ``, `"bread" "cheese"`},
+		{`strict $.**.price`, ``, `1.5 3 10`},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			jp, err := Parse(tc.path)
+			require.NoError(t, err)
+			target, err := json.ParseJSON(doc)
+			require.NoError(t, err)
+			var vars json.JSON
+			if tc.vars != "" {
+				vars, err = json.ParseJSON(tc.vars)
+				require.NoError(t, err)
+			}
+			res, err := Query(jp, target, vars, false /* silent */)
+			require.NoError(t, err)
+			strs := make([]string, len(res))
+			for i := range res {
+				strs[i] = res[i].String()
+			}
+			require.Equal(t, tc.expected, strings.Join(strs, " "))
+		})
+	}
+}
+
+func TestQueryError(t *testing.T) {
+	for _, tc := range []struct {
+		doc  string
+		path string
+		err  string
+	}{
+		{`{"a": 1}`, `strict $.b`, `JSON object does not contain key "b"`},
+		{`{"a": 1}`, `strict $.a.b`, `jsonpath member accessor can only be applied to an object`},
+		{`{"a": 1}`, `strict $.a[*]`, `jsonpath wildcard array accessor can only be applied to an array`},
+		{`[1, 2]`, `strict $[2]`, `jsonpath array subscript is out of bounds`},
+		{`[1, 2]`, `$["a"]`, `jsonpath array subscript is not a single numeric value`},
+		{`[1, 2]`, `$ + 1`, `left operand of jsonpath operator + is not a single numeric value`},
+		{`1`, `$ / 0`, `division by zero`},
+		{`"a"`, `-$`, `operand of unary jsonpath operator - is not a numeric value`},
+		{`"a"`, `$.abs()`, `jsonpath item method .abs() can only be applied to a numeric value`},
+		{`"a"`, `$.double()`, `string argument of jsonpath item method .double() is not a valid representation of a double precision number`},
+		{`1`, `$.keyvalue()`, `jsonpath item method .keyvalue() can only be applied to an object`},
+		{`1`, `$.datetime()`, `jsonpath item method .datetime() can only be applied to a string`},
+		{`"foo"`, `$.datetime()`, `datetime format is not recognized: "foo"`},
+		{`1`, `$x`, `could not find jsonpath variable "x"`},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			jp, err := Parse(tc.path)
+			require.NoError(t, err)
+			target, err := json.ParseJSON(tc.doc)
+			require.NoError(t, err)
+			_, err = Query(jp, target, nil /* vars */, false /* silent */)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestExistsAndMatch(t *testing.T) {
+	target, err := json.ParseJSON(`{"a": [1, 2, 3], "b": "x"}`)
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		path      string
+		silent    bool
+		exists    string
+		match     string
+		matchErr  string
+		existsErr string
+	}{
+		{path: `$.a[*] ? (@ > 2)`, exists: `true`, matchErr: `single boolean result is expected`},
+		{path: `$.a[*] ? (@ > 5)`, exists: `false`, matchErr: `single boolean result is expected`},
+		{path: `$.a[*] > 2`, exists: `true`, match: `true`},
+		{path: `$.a[*] > 5`, exists: `true`, match: `false`},
+		{path: `$.b > 5`, exists: `true`, match: `NULL`},
+		{path: `strict $.c`, silent: true, exists: `NULL`, match: `NULL`},
+		{path: `strict $.c`, existsErr: `JSON object does not contain key "c"`,
+			matchErr: `JSON object does not contain key "c"`},
+		{path: `$.c`, exists: `false`, match: `NULL`, silent: true},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			format := func(res, isNull bool) string {
+				if isNull {
+					return "NULL"
+				}
+				if res {
+					return "true"
+				}
+				return "false"
+			}
+			jp, err := Parse(tc.path)
+			require.NoError(t, err)
+			res, isNull, err := Exists(jp, target, nil /* vars */, tc.silent)
+			if tc.existsErr != "" {
+				require.EqualError(t, err, tc.existsErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.exists, format(res, isNull))
+			}
+			res, isNull, err = Match(jp, target, nil /* vars */, tc.silent)
+			if tc.matchErr != "" {
+				require.EqualError(t, err, tc.matchErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.match, format(res, isNull))
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import "github.com/cockroachdb/cockroach/pkg/util/json"
+
+// KeyEquality returns the chain of object keys and the constant value of a
+// path that only matches documents in which the value reached by following
+// the keys from the root equals the constant. It is used to constrain
+// inverted indexes on JSON columns.
+//
+// If predicate is true, the path is interpreted as a predicate check, as by
+// the @@ operator, and must have the form:
+//
+//	$.k1.k2 == const
+//
+// Otherwise, the path is interpreted as an existence check, as by the @?
+// operator, and must have one of the forms:
+//
+//	$.k1 ? (@.k2 == const)
+//	$.k1.k2 ? (@ == const)
+//
+// ok is false if the path does not have one of these forms. Note that in lax
+// mode, arrays are unwrapped when the keys are followed, so the keys do not
+// fully determine the structure of matching documents.
+func KeyEquality(jp *Jsonpath, predicate bool) (keys []string, val json.JSON, ok bool) {
+	if predicate {
+		return keyEquality(jp.Expr, Root{})
+	}
+	p, ok := jp.Expr.(*Path)
+	if !ok || p.Start != (Root{}) || len(p.Steps) == 0 {
+		return nil, nil, false
+	}
+	f, ok := p.Steps[len(p.Steps)-1].(*Filter)
+	if !ok {
+		return nil, nil, false
+	}
+	prefix, ok := keyChain(p.Steps[:len(p.Steps)-1])
+	if !ok {
+		return nil, nil, false
+	}
+	suffix, val, ok := keyEquality(f.Pred, Current{})
+	if !ok {
+		return nil, nil, false
+	}
+	return append(prefix, suffix...), val, true
+}
+
+// keyEquality matches an equality comparison between a chain of keys
+// following start and a literal.
+func keyEquality(expr Expr, start Expr) (keys []string, val json.JSON, ok bool) {
+	b, ok := expr.(*Binary)
+	if !ok || b.Op != OpEq {
+		return nil, nil, false
+	}
+	left, right := b.Left, b.Right
+	if _, ok := left.(*Literal); ok {
+		left, right = right, left
+	}
+	l, ok := right.(*Literal)
+	if !ok {
+		return nil, nil, false
+	}
+	switch t := left.(type) {
+	case Root, Current:
+		if t != start {
+			return nil, nil, false
+		}
+	case *Path:
+		if t.Start != start {
+			return nil, nil, false
+		}
+		if keys, ok = keyChain(t.Steps); !ok {
+			return nil, nil, false
+		}
+	default:
+		return nil, nil, false
+	}
+	return keys, l.toJSON(), true
+}
+
+// keyChain returns the names of the keys if all of the steps are object key
+// accessors.
+func keyChain(steps []Step) ([]string, bool) {
+	keys := make([]string, 0, len(steps))
+	for _, s := range steps {
+		k, ok := s.(*Key)
+		if !ok {
+			return nil, false
+		}
+		keys = append(keys, k.Name)
+	}
+	return keys, true
+}
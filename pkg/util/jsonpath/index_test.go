@@ -0,0 +1,58 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyEquality(t *testing.T) {
+	for _, tc := range []struct {
+		path      string
+		predicate bool
+		keys      []string
+		val       string
+	}{
+		{path: `$.a.b == 1`, predicate: true, keys: []string{"a", "b"}, val: `1`},
+		{path: `"x" == $.a`, predicate: true, keys: []string{"a"}, val: `"x"`},
+		{path: `$ == null`, predicate: true, keys: nil, val: `null`},
+		{path: `strict $.a == true`, predicate: true, keys: []string{"a"}, val: `true`},
+		{path: `$.a ? (@.b == 1)`, keys: []string{"a", "b"}, val: `1`},
+		{path: `$.a.b ? (@ == "x")`, keys: []string{"a", "b"}, val: `"x"`},
+		{path: `$ ? (@.a == 1)`, keys: []string{"a"}, val: `1`},
+		// Paths that do not have one of the supported forms.
+		{path: `$.a.b == 1`},
+		{path: `$.a ? (@.b == 1)`, predicate: true},
+		{path: `$.a > 1`, predicate: true},
+		{path: `$.a == $.b`, predicate: true},
+		{path: `$.a[0] == 1`, predicate: true},
+		{path: `$.a.size() == 1`, predicate: true},
+		{path: `$.a ? (@.b == 1 && @.c == 2)`},
+		{path: `$.a ? (@.b == 1).c`},
+		{path: `$.a ? ($.b == 1)`},
+		{path: `$.a`},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			jp, err := Parse(tc.path)
+			require.NoError(t, err)
+			keys, val, ok := KeyEquality(jp, tc.predicate)
+			if tc.val == "" {
+				require.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			require.Equal(t, tc.keys, keys)
+			require.Equal(t, tc.val, val.String())
+		})
+	}
+}
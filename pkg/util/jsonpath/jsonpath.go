@@ -0,0 +1,517 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package jsonpath implements the SQL/JSON path language, which is used by
+// the JSONPATH type and the jsonb_path_* family of builtins to select items
+// from a JSON document.
+package jsonpath
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/apd/v3"
+)
+
+// Jsonpath is a parsed SQL/JSON path expression.
+type Jsonpath struct {
+	// Strict is true if the path is evaluated in strict mode, in which
+	// structural errors are reported instead of being ignored and arrays are
+	// not automatically unwrapped.
+	Strict bool
+	// Expr is the root expression of the path.
+	Expr Expr
+}
+
+// String returns the canonical representation of the path, which matches the
+// output of Postgres.
+func (j *Jsonpath) String() string {
+	var buf strings.Builder
+	if j.Strict {
+		buf.WriteString("strict ")
+	}
+	j.Expr.format(&buf, true /* brackets */)
+	return buf.String()
+}
+
+// Expr is a node in a path expression.
+type Expr interface {
+	// format writes the expression to buf. If brackets is true, operators are
+	// wrapped in parentheses.
+	format(buf *strings.Builder, brackets bool)
+}
+
+// Root is the $ primary, which refers to the JSON document being queried.
+type Root struct{}
+
+// Current is the @ primary, which refers to the item being filtered.
+type Current struct{}
+
+// Last is the LAST primary, which refers to the last index of the innermost
+// array being subscripted.
+type Last struct{}
+
+// Variable is a $name primary, which refers to a value passed in the vars
+// argument of the jsonb_path_* builtins.
+type Variable struct {
+	Name string
+}
+
+// Literal is a scalar constant: a string, number, boolean or null.
+type Literal struct {
+	Kind LiteralKind
+	Str  string
+	Num  apd.Decimal
+	Bool bool
+}
+
+// LiteralKind identifies the type of a Literal.
+type LiteralKind int
+
+const (
+	// NullLiteral is the null constant.
+	NullLiteral LiteralKind = iota
+	// BoolLiteral is the true or false constant.
+	BoolLiteral
+	// NumLiteral is a numeric constant.
+	NumLiteral
+	// StrLiteral is a string constant.
+	StrLiteral
+)
+
+// Path is a primary followed by a chain of accessors, such as $.a[*].
+type Path struct {
+	Start Expr
+	Steps []Step
+}
+
+// Step is an accessor in a Path.
+type Step interface {
+	formatStep(buf *strings.Builder)
+}
+
+// Key is the .name accessor.
+type Key struct {
+	Name string
+}
+
+// AnyKey is the .* accessor.
+type AnyKey struct{}
+
+// AnyIndex is the [*] accessor.
+type AnyIndex struct{}
+
+// Index is the [subscript, ...] accessor.
+type Index struct {
+	Subscripts []Subscript
+}
+
+// Subscript is a single array index or an inclusive index range in an Index
+// accessor. To is nil for a single index.
+type Subscript struct {
+	From Expr
+	To   Expr
+}
+
+// LastLevel is used as the bound of a Recursive accessor to represent the
+// LAST level.
+const LastLevel = -1
+
+// Recursive is the .** accessor, which selects all items at the given levels
+// of nesting below the current item.
+type Recursive struct {
+	From int
+	To   int
+}
+
+// Filter is the ? (predicate) accessor.
+type Filter struct {
+	Pred Expr
+}
+
+// Method is an item method accessor such as .type() or .datetime().
+type Method struct {
+	Name string
+	// Arg is the template of the .datetime() method, if any.
+	Arg *string
+}
+
+// Operator identifies the operator of a Binary or Unary expression.
+type Operator int
+
+const (
+	_ Operator = iota
+	// OpAnd is the && operator.
+	OpAnd
+	// OpOr is the || operator.
+	OpOr
+	// OpNot is the ! operator.
+	OpNot
+	// OpEq is the == operator.
+	OpEq
+	// OpNe is the != operator.
+	OpNe
+	// OpLt is the < operator.
+	OpLt
+	// OpLe is the <= operator.
+	OpLe
+	// OpGt is the > operator.
+	OpGt
+	// OpGe is the >= operator.
+	OpGe
+	// OpAdd is the binary + operator.
+	OpAdd
+	// OpSub is the binary - operator.
+	OpSub
+	// OpMul is the * operator.
+	OpMul
+	// OpDiv is the / operator.
+	OpDiv
+	// OpMod is the % operator.
+	OpMod
+	// OpPlus is the unary + operator.
+	OpPlus
+	// OpMinus is the unary - operator.
+	OpMinus
+)
+
+var operatorStrings = [...]string{
+	OpAnd:   "&&",
+	OpOr:    "||",
+	OpNot:   "!",
+	OpEq:    "==",
+	OpNe:    "!=",
+	OpLt:    "<",
+	OpLe:    "<=",
+	OpGt:    ">",
+	OpGe:    ">=",
+	OpAdd:   "+",
+	OpSub:   "-",
+	OpMul:   "*",
+	OpDiv:   "/",
+	OpMod:   "%",
+	OpPlus:  "+",
+	OpMinus: "-",
+}
+
+func (o Operator) String() string {
+	return operatorStrings[o]
+}
+
+// isComparison returns whether the operator is a comparison operator.
+func (o Operator) isComparison() bool {
+	return o >= OpEq && o <= OpGe
+}
+
+// isArithmetic returns whether the operator is a binary arithmetic operator.
+func (o Operator) isArithmetic() bool {
+	return o >= OpAdd && o <= OpMod
+}
+
+// Binary is an expression with two operands: a logical, comparison or
+// arithmetic operator.
+type Binary struct {
+	Op          Operator
+	Left, Right Expr
+}
+
+// Unary is an expression with a single operand: the ! predicate or the unary
+// + and - operators.
+type Unary struct {
+	Op      Operator
+	Operand Expr
+}
+
+// ExistsExpr is the exists (expr) predicate.
+type ExistsExpr struct {
+	Expr Expr
+}
+
+// IsUnknown is the (predicate) is unknown predicate.
+type IsUnknown struct {
+	Pred Expr
+}
+
+// LikeRegex is the expr like_regex "pattern" [flag "flags"] predicate.
+type LikeRegex struct {
+	Expr    Expr
+	Pattern string
+	Flags   string
+
+	re *regexp.Regexp
+}
+
+// StartsWith is the expr starts with "prefix" predicate.
+type StartsWith struct {
+	Expr   Expr
+	Prefix Expr
+}
+
+// priority returns the binding strength of the expression when printed, which
+// determines where parentheses are needed.
+func priority(e Expr) int {
+	switch t := e.(type) {
+	case *Binary:
+		switch t.Op {
+		case OpOr:
+			return 0
+		case OpAnd:
+			return 1
+		case OpAdd, OpSub:
+			return 3
+		case OpMul, OpDiv, OpMod:
+			return 4
+		default:
+			return 2
+		}
+	case *Unary:
+		if t.Op == OpNot {
+			return 6
+		}
+		return 5
+	case *StartsWith, *LikeRegex:
+		return 2
+	default:
+		return 6
+	}
+}
+
+// isPredicate returns whether the expression evaluates to a boolean.
+func isPredicate(e Expr) bool {
+	switch t := e.(type) {
+	case *Binary:
+		return !t.Op.isArithmetic()
+	case *Unary:
+		return t.Op == OpNot
+	case *ExistsExpr, *IsUnknown, *LikeRegex, *StartsWith:
+		return true
+	default:
+		return false
+	}
+}
+
+func (Root) format(buf *strings.Builder, _ bool) {
+	buf.WriteByte('$')
+}
+
+func (Current) format(buf *strings.Builder, _ bool) {
+	buf.WriteByte('@')
+}
+
+func (Last) format(buf *strings.Builder, _ bool) {
+	buf.WriteString("last")
+}
+
+func (v *Variable) format(buf *strings.Builder, _ bool) {
+	buf.WriteByte('$')
+	writeString(buf, v.Name)
+}
+
+func (l *Literal) format(buf *strings.Builder, _ bool) {
+	switch l.Kind {
+	case NullLiteral:
+		buf.WriteString("null")
+	case BoolLiteral:
+		if l.Bool {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case NumLiteral:
+		buf.WriteString(l.Num.Text('f'))
+	case StrLiteral:
+		writeString(buf, l.Str)
+	}
+}
+
+func (p *Path) format(buf *strings.Builder, brackets bool) {
+	switch p.Start.(type) {
+	case Root, Current, Last, *Variable, *Literal:
+		p.Start.format(buf, brackets)
+	default:
+		buf.WriteByte('(')
+		p.Start.format(buf, false /* brackets */)
+		buf.WriteByte(')')
+	}
+	for _, s := range p.Steps {
+		s.formatStep(buf)
+	}
+}
+
+func (k *Key) formatStep(buf *strings.Builder) {
+	buf.WriteByte('.')
+	writeString(buf, k.Name)
+}
+
+func (AnyKey) formatStep(buf *strings.Builder) {
+	buf.WriteString(".*")
+}
+
+func (AnyIndex) formatStep(buf *strings.Builder) {
+	buf.WriteString("[*]")
+}
+
+func (i *Index) formatStep(buf *strings.Builder) {
+	buf.WriteByte('[')
+	for n, s := range i.Subscripts {
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		s.From.format(buf, true /* brackets */)
+		if s.To != nil {
+			buf.WriteString(" to ")
+			s.To.format(buf, true /* brackets */)
+		}
+	}
+	buf.WriteByte(']')
+}
+
+func formatLevel(level int) string {
+	if level == LastLevel {
+		return "last"
+	}
+	return fmt.Sprint(level)
+}
+
+func (r *Recursive) formatStep(buf *strings.Builder) {
+	buf.WriteString(".**")
+	switch {
+	case r.From == 0 && r.To == LastLevel:
+	case r.From == r.To:
+		fmt.Fprintf(buf, "{%s}", formatLevel(r.From))
+	default:
+		fmt.Fprintf(buf, "{%s to %s}", formatLevel(r.From), formatLevel(r.To))
+	}
+}
+
+func (f *Filter) formatStep(buf *strings.Builder) {
+	buf.WriteString("?(")
+	f.Pred.format(buf, false /* brackets */)
+	buf.WriteByte(')')
+}
+
+func (m *Method) formatStep(buf *strings.Builder) {
+	buf.WriteByte('.')
+	buf.WriteString(m.Name)
+	buf.WriteByte('(')
+	if m.Arg != nil {
+		writeString(buf, *m.Arg)
+	}
+	buf.WriteByte(')')
+}
+
+// formatOperand writes an operand of e, adding parentheses if the operand
+// binds less tightly than e.
+func formatOperand(buf *strings.Builder, e, operand Expr) {
+	operand.format(buf, priority(operand) <= priority(e))
+}
+
+func (b *Binary) format(buf *strings.Builder, brackets bool) {
+	if brackets {
+		buf.WriteByte('(')
+	}
+	formatOperand(buf, b, b.Left)
+	buf.WriteByte(' ')
+	buf.WriteString(b.Op.String())
+	buf.WriteByte(' ')
+	formatOperand(buf, b, b.Right)
+	if brackets {
+		buf.WriteByte(')')
+	}
+}
+
+func (u *Unary) format(buf *strings.Builder, brackets bool) {
+	if u.Op == OpNot {
+		buf.WriteString("!(")
+		u.Operand.format(buf, false /* brackets */)
+		buf.WriteByte(')')
+		return
+	}
+	if brackets {
+		buf.WriteByte('(')
+	}
+	buf.WriteString(u.Op.String())
+	formatOperand(buf, u, u.Operand)
+	if brackets {
+		buf.WriteByte(')')
+	}
+}
+
+func (e *ExistsExpr) format(buf *strings.Builder, _ bool) {
+	buf.WriteString("exists (")
+	e.Expr.format(buf, false /* brackets */)
+	buf.WriteByte(')')
+}
+
+func (u *IsUnknown) format(buf *strings.Builder, _ bool) {
+	buf.WriteByte('(')
+	u.Pred.format(buf, false /* brackets */)
+	buf.WriteString(") is unknown")
+}
+
+func (l *LikeRegex) format(buf *strings.Builder, brackets bool) {
+	if brackets {
+		buf.WriteByte('(')
+	}
+	formatOperand(buf, l, l.Expr)
+	buf.WriteString(" like_regex ")
+	writeString(buf, l.Pattern)
+	if l.Flags != "" {
+		buf.WriteString(" flag ")
+		writeString(buf, l.Flags)
+	}
+	if brackets {
+		buf.WriteByte(')')
+	}
+}
+
+func (s *StartsWith) format(buf *strings.Builder, brackets bool) {
+	if brackets {
+		buf.WriteByte('(')
+	}
+	formatOperand(buf, s, s.Expr)
+	buf.WriteString(" starts with ")
+	formatOperand(buf, s, s.Prefix)
+	if brackets {
+		buf.WriteByte(')')
+	}
+}
+
+// writeString writes s as a double-quoted string, escaping it the same way
+// as a JSON string.
+func writeString(buf *strings.Builder, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
@@ -0,0 +1,859 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+// tokenKind identifies the kind of a lexical token of a path expression.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	// tokPunct is an operator or punctuation, such as == or [.
+	tokPunct
+	// tokIdent is an unquoted identifier, which may also be a keyword.
+	tokIdent
+	// tokString is a double-quoted string.
+	tokString
+	// tokNumber is a numeric constant.
+	tokNumber
+	// tokVariable is a $name or $"name" variable reference.
+	tokVariable
+)
+
+type token struct {
+	kind tokenKind
+	// val is the punctuation, identifier, number or unescaped string.
+	val string
+}
+
+// punctuation lists the multi-character operators before the
+// single-character ones, so that the longest match wins.
+var punctuation = []string{
+	"==", "!=", "<>", "<=", ">=", "&&", "||", "**",
+	"<", ">", "!", "+", "-", "*", "/", "%", "(", ")", "[", "]", "{", "}",
+	".", ",", "?", "$", "@",
+}
+
+// lex splits a path expression into tokens.
+func lex(input string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+		case c == '"':
+			s, n, err := lexString(input[i:])
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, token{kind: tokString, val: s})
+			i += n
+		case c == '$' && i+1 < len(input) && input[i+1] == '"':
+			s, n, err := lexString(input[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, token{kind: tokVariable, val: s})
+			i += n + 1
+		case c == '$' && i+1 < len(input) && isIdentStart(input[i+1:]):
+			n := lexIdent(input[i+1:])
+			toks = append(toks, token{kind: tokVariable, val: input[i+1 : i+1+n]})
+			i += n + 1
+		case c >= '0' && c <= '9':
+			n := lexNumber(input[i:])
+			if i+n < len(input) && isIdentStart(input[i+n:]) {
+				return nil, syntaxErrorf("trailing junk after numeric literal")
+			}
+			toks = append(toks, token{kind: tokNumber, val: input[i : i+n]})
+			i += n
+		case isIdentStart(input[i:]):
+			n := lexIdent(input[i:])
+			toks = append(toks, token{kind: tokIdent, val: input[i : i+n]})
+			i += n
+		default:
+			found := false
+			for _, p := range punctuation {
+				if strings.HasPrefix(input[i:], p) {
+					toks = append(toks, token{kind: tokPunct, val: p})
+					i += len(p)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, syntaxErrorf("unexpected character %q", c)
+			}
+		}
+	}
+	return append(toks, token{kind: tokEOF}), nil
+}
+
+func isIdentStart(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func lexIdent(s string) int {
+	n := 0
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+		n += size
+	}
+	return n
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// lexNumber returns the length of the numeric constant at the start of s.
+func lexNumber(s string) int {
+	n := 0
+	for n < len(s) && isDigit(s[n]) {
+		n++
+	}
+	// A decimal point is only part of the number if it is followed by a digit,
+	// so that accessors can be applied to integers, as in 1.type().
+	if n+1 < len(s) && s[n] == '.' && isDigit(s[n+1]) {
+		n++
+		for n < len(s) && isDigit(s[n]) {
+			n++
+		}
+	}
+	if n < len(s) && (s[n] == 'e' || s[n] == 'E') {
+		m := n + 1
+		if m < len(s) && (s[m] == '+' || s[m] == '-') {
+			m++
+		}
+		if m < len(s) && isDigit(s[m]) {
+			for m < len(s) && isDigit(s[m]) {
+				m++
+			}
+			n = m
+		}
+	}
+	return n
+}
+
+// lexString unescapes the double-quoted string at the start of s, returning
+// the string and the number of bytes consumed.
+func lexString(s string) (string, int, error) {
+	var buf strings.Builder
+	for i := 1; i < len(s); {
+		c := s[i]
+		switch c {
+		case '"':
+			return buf.String(), i + 1, nil
+		case '\\':
+			if i+1 >= len(s) {
+				return "", 0, syntaxErrorf("unexpected end of quoted string")
+			}
+			i++
+			switch s[i] {
+			case 'b':
+				buf.WriteByte('\b')
+			case 'f':
+				buf.WriteByte('\f')
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 't':
+				buf.WriteByte('\t')
+			case 'v':
+				buf.WriteByte('\v')
+			case 'x':
+				if i+2 >= len(s) {
+					return "", 0, syntaxErrorf("invalid hexadecimal character sequence")
+				}
+				v, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+				if err != nil {
+					return "", 0, syntaxErrorf("invalid hexadecimal character sequence")
+				}
+				buf.WriteRune(rune(v))
+				i += 2
+			case 'u':
+				r, n, err := lexUnicodeEscape(s[i+1:])
+				if err != nil {
+					return "", 0, err
+				}
+				buf.WriteRune(r)
+				i += n
+			default:
+				buf.WriteByte(s[i])
+			}
+			i++
+		default:
+			buf.WriteByte(c)
+			i++
+		}
+	}
+	return "", 0, syntaxErrorf("unexpected end of quoted string")
+}
+
+// lexUnicodeEscape decodes the \uXXXX or \u{X...} escape whose hexadecimal
+// digits start at s, returning the rune and the number of bytes consumed.
+func lexUnicodeEscape(s string) (rune, int, error) {
+	var digits string
+	var n int
+	if strings.HasPrefix(s, "{") {
+		end := strings.IndexByte(s, '}')
+		if end < 2 || end > 7 {
+			return 0, 0, syntaxErrorf("invalid Unicode escape sequence")
+		}
+		digits, n = s[1:end], end+1
+	} else {
+		if len(s) < 4 {
+			return 0, 0, syntaxErrorf("invalid Unicode escape sequence")
+		}
+		digits, n = s[:4], 4
+	}
+	v, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil || v == 0 || v > unicode.MaxRune {
+		return 0, 0, syntaxErrorf("invalid Unicode escape sequence")
+	}
+	return rune(v), n, nil
+}
+
+func syntaxErrorf(format string, args ...interface{}) error {
+	return pgerror.Newf(pgcode.Syntax, format, args...)
+}
+
+// parser is a recursive-descent parser for path expressions. Operators bind,
+// from least to most tightly: ||, &&, comparisons and the starts with and
+// like_regex predicates, binary + and -, *, / and %, unary + and -, and
+// finally accessors.
+type parser struct {
+	toks []token
+	pos  int
+	// inSubscript is true while parsing an array subscript, where LAST is
+	// allowed, including in filters nested in the subscript.
+	inSubscript bool
+	// inFilter is true while parsing a filter predicate, where @ is allowed.
+	inFilter bool
+}
+
+// Parse parses a path expression.
+func Parse(input string) (*Jsonpath, error) {
+	toks, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{toks: toks}
+	var jp Jsonpath
+	switch {
+	case p.peekIdent("strict"):
+		jp.Strict = true
+		p.pos++
+	case p.peekIdent("lax"):
+		p.pos++
+	}
+	if jp.Expr, err = p.parseOr(); err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, p.unexpected()
+	}
+	return &jp, nil
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// backup undoes the call to next that returned t.
+func (p *parser) backup(t token) {
+	if t.kind != tokEOF {
+		p.pos--
+	}
+}
+
+func (p *parser) peekPunct(s string) bool {
+	t := p.peek()
+	return t.kind == tokPunct && t.val == s
+}
+
+func (p *parser) peekIdent(s string) bool {
+	t := p.peek()
+	return t.kind == tokIdent && t.val == s
+}
+
+// accept consumes the next token if it is the punctuation s.
+func (p *parser) accept(s string) bool {
+	if p.peekPunct(s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(s string) error {
+	if !p.accept(s) {
+		return p.unexpected()
+	}
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+	if t.kind == tokEOF {
+		return syntaxErrorf("syntax error at end of jsonpath input")
+	}
+	return syntaxErrorf("syntax error at or near %q of jsonpath input", t.val)
+}
+
+func (p *parser) expectPredicate(e Expr) error {
+	if !isPredicate(e) {
+		return syntaxErrorf("boolean expression expected")
+	}
+	return nil
+}
+
+func (p *parser) expectValue(e Expr) error {
+	if isPredicate(e) {
+		return syntaxErrorf("unexpected boolean expression")
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPredicate(left); err != nil {
+			return nil, err
+		}
+		if err := p.expectPredicate(right); err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: OpOr, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPredicate(left); err != nil {
+			return nil, err
+		}
+		if err := p.expectPredicate(right); err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: OpAnd, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+var comparisonOps = map[string]Operator{
+	"==": OpEq,
+	"!=": OpNe,
+	"<>": OpNe,
+	"<":  OpLt,
+	"<=": OpLe,
+	">":  OpGt,
+	">=": OpGe,
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if op, ok := comparisonOps[t.val]; ok && t.kind == tokPunct {
+		p.pos++
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectValue(left); err != nil {
+			return nil, err
+		}
+		if err := p.expectValue(right); err != nil {
+			return nil, err
+		}
+		return &Binary{Op: op, Left: left, Right: right}, nil
+	}
+	switch {
+	case p.peekIdent("starts"):
+		p.pos++
+		if !p.peekIdent("with") {
+			return nil, p.unexpected()
+		}
+		p.pos++
+		if err := p.expectValue(left); err != nil {
+			return nil, err
+		}
+		t := p.next()
+		var prefix Expr
+		switch t.kind {
+		case tokString:
+			prefix = &Literal{Kind: StrLiteral, Str: t.val}
+		case tokVariable:
+			prefix = &Variable{Name: t.val}
+		default:
+			p.backup(t)
+			return nil, p.unexpected()
+		}
+		return &StartsWith{Expr: left, Prefix: prefix}, nil
+	case p.peekIdent("like_regex"):
+		p.pos++
+		if err := p.expectValue(left); err != nil {
+			return nil, err
+		}
+		t := p.next()
+		if t.kind != tokString {
+			p.backup(t)
+			return nil, p.unexpected()
+		}
+		lr := &LikeRegex{Expr: left, Pattern: t.val}
+		if p.peekIdent("flag") {
+			p.pos++
+			t := p.next()
+			if t.kind != tokString {
+				p.backup(t)
+				return nil, p.unexpected()
+			}
+			lr.Flags = t.val
+		}
+		if lr.re, err = compileRegex(lr.Pattern, lr.Flags); err != nil {
+			return nil, err
+		}
+		return lr, nil
+	}
+	return left, nil
+}
+
+// compileRegex compiles the pattern of a like_regex predicate, translating
+// the XQuery flags into Go regular expression flags.
+func compileRegex(pattern, flags string) (*regexp.Regexp, error) {
+	var goFlags strings.Builder
+	quote := false
+	for _, f := range flags {
+		switch f {
+		case 'i', 's', 'm':
+			goFlags.WriteRune(f)
+		case 'q':
+			quote = true
+		case 'x':
+			return nil, pgerror.New(pgcode.FeatureNotSupported,
+				`XQuery "x" flag (expanded regular expressions) is not implemented`)
+		default:
+			return nil, errors.WithDetailf(
+				pgerror.New(pgcode.InvalidParameterValue, "invalid input syntax for type jsonpath"),
+				"Unrecognized flag character %q in LIKE_REGEX predicate.", f,
+			)
+		}
+	}
+	if quote {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if goFlags.Len() > 0 {
+		pattern = "(?" + goFlags.String() + ")" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, pgerror.Wrap(err, pgcode.InvalidRegularExpression, "invalid regular expression")
+	}
+	return re, nil
+}
+
+func (p *parser) parseAdditive() (Expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var op Operator
+		switch {
+		case p.accept("+"):
+			op = OpAdd
+		case p.accept("-"):
+			op = OpSub
+		default:
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.makeArithmetic(op, left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseMultiplicative() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var op Operator
+		switch {
+		case p.accept("*"):
+			op = OpMul
+		case p.accept("/"):
+			op = OpDiv
+		case p.accept("%"):
+			op = OpMod
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.makeArithmetic(op, left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) makeArithmetic(op Operator, left, right Expr) (Expr, error) {
+	if err := p.expectValue(left); err != nil {
+		return nil, err
+	}
+	if err := p.expectValue(right); err != nil {
+		return nil, err
+	}
+	return &Binary{Op: op, Left: left, Right: right}, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	var op Operator
+	switch {
+	case p.accept("+"):
+		op = OpPlus
+	case p.accept("-"):
+		op = OpMinus
+	case p.accept("!"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		pred, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPredicate(pred); err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return &Unary{Op: OpNot, Operand: pred}, nil
+	default:
+		return p.parseAccessors()
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectValue(operand); err != nil {
+		return nil, err
+	}
+	// Fold signs into numeric constants, as Postgres does.
+	if lit, ok := operand.(*Literal); ok && lit.Kind == NumLiteral {
+		if op == OpMinus {
+			lit.Num.Neg(&lit.Num)
+		}
+		return lit, nil
+	}
+	return &Unary{Op: op, Operand: operand}, nil
+}
+
+// parseAccessors parses a primary followed by any number of accessors.
+func (p *parser) parseAccessors() (Expr, error) {
+	start, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	var steps []Step
+	// Accessors applied to a parenthesized path extend the path.
+	if path, ok := start.(*Path); ok {
+		start, steps = path.Start, path.Steps
+	}
+	for {
+		var step Step
+		var err error
+		switch {
+		case p.accept("."):
+			step, err = p.parseDotAccessor()
+		case p.accept("["):
+			step, err = p.parseIndex()
+		case p.accept("?"):
+			step, err = p.parseFilter()
+		default:
+			if len(steps) == 0 {
+				return start, nil
+			}
+			return &Path{Start: start, Steps: steps}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokPunct:
+		switch t.val {
+		case "$":
+			return Root{}, nil
+		case "@":
+			if !p.inFilter {
+				return nil, syntaxErrorf("@ is not allowed in root expressions")
+			}
+			return Current{}, nil
+		case "(":
+			e, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			if p.peekIdent("is") {
+				p.pos++
+				if !p.peekIdent("unknown") {
+					return nil, p.unexpected()
+				}
+				p.pos++
+				if err := p.expectPredicate(e); err != nil {
+					return nil, err
+				}
+				return &IsUnknown{Pred: e}, nil
+			}
+			return e, nil
+		}
+	case tokString:
+		return &Literal{Kind: StrLiteral, Str: t.val}, nil
+	case tokNumber:
+		lit := &Literal{Kind: NumLiteral}
+		if _, _, err := lit.Num.SetString(t.val); err != nil {
+			return nil, syntaxErrorf("invalid numeric literal %q", t.val)
+		}
+		return lit, nil
+	case tokVariable:
+		return &Variable{Name: t.val}, nil
+	case tokIdent:
+		switch t.val {
+		case "null":
+			return &Literal{Kind: NullLiteral}, nil
+		case "true", "false":
+			return &Literal{Kind: BoolLiteral, Bool: t.val == "true"}, nil
+		case "last":
+			if !p.inSubscript {
+				return nil, syntaxErrorf("LAST is allowed only in array subscripts")
+			}
+			return Last{}, nil
+		case "exists":
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			e, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectValue(e); err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return &ExistsExpr{Expr: e}, nil
+		}
+	}
+	p.backup(t)
+	return nil, p.unexpected()
+}
+
+// methods lists the supported item methods.
+var methods = map[string]bool{
+	"type":     true,
+	"size":     true,
+	"double":   true,
+	"ceiling":  true,
+	"floor":    true,
+	"abs":      true,
+	"keyvalue": true,
+	"datetime": true,
+}
+
+// parseDotAccessor parses the accessor following a period: a key, .*, .**
+// or an item method.
+func (p *parser) parseDotAccessor() (Step, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		return &Key{Name: t.val}, nil
+	case tokIdent:
+		if !p.accept("(") {
+			return &Key{Name: t.val}, nil
+		}
+		if !methods[t.val] {
+			return nil, syntaxErrorf("unknown item method .%s()", t.val)
+		}
+		m := &Method{Name: t.val}
+		if t.val == "datetime" && p.peek().kind == tokString {
+			arg := p.next().val
+			m.Arg = &arg
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return m, nil
+	case tokPunct:
+		switch t.val {
+		case "*":
+			return AnyKey{}, nil
+		case "**":
+			r := &Recursive{From: 0, To: LastLevel}
+			if p.accept("{") {
+				var err error
+				if r.From, err = p.parseLevel(); err != nil {
+					return nil, err
+				}
+				r.To = r.From
+				if p.peekIdent("to") {
+					p.pos++
+					if r.To, err = p.parseLevel(); err != nil {
+						return nil, err
+					}
+				}
+				if err := p.expect("}"); err != nil {
+					return nil, err
+				}
+			}
+			return r, nil
+		}
+	}
+	p.backup(t)
+	return nil, p.unexpected()
+}
+
+func (p *parser) parseLevel() (int, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokIdent && t.val == "last":
+		return LastLevel, nil
+	case t.kind == tokNumber:
+		level, err := strconv.ParseUint(t.val, 10, 31)
+		if err != nil {
+			return 0, syntaxErrorf("invalid level %q", t.val)
+		}
+		return int(level), nil
+	}
+	p.backup(t)
+	return 0, p.unexpected()
+}
+
+func (p *parser) parseIndex() (Step, error) {
+	if p.accept("*") {
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return AnyIndex{}, nil
+	}
+	defer func(inSubscript bool) { p.inSubscript = inSubscript }(p.inSubscript)
+	p.inSubscript = true
+	idx := &Index{}
+	for {
+		var s Subscript
+		var err error
+		if s.From, err = p.parseSubscriptExpr(); err != nil {
+			return nil, err
+		}
+		if p.peekIdent("to") {
+			p.pos++
+			if s.To, err = p.parseSubscriptExpr(); err != nil {
+				return nil, err
+			}
+		}
+		idx.Subscripts = append(idx.Subscripts, s)
+		if p.accept("]") {
+			return idx, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseSubscriptExpr() (Expr, error) {
+	e, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectValue(e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (p *parser) parseFilter() (Step, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	defer func(inFilter bool) { p.inFilter = inFilter }(p.inFilter)
+	p.inFilter = true
+	pred, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectPredicate(pred); err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return &Filter{Pred: pred}, nil
+}
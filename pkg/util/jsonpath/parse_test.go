@@ -0,0 +1,109 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{`$`, `$`},
+		{`lax $`, `$`},
+		{`strict $`, `strict $`},
+		{`$.a`, `$."a"`},
+		{`$."a b"`, `$."a b"`},
+		{`$.a.b.c`, `$."a"."b"."c"`},
+		{`$.last`, `$."last"`},
+		{`$.*`, `$.*`},
+		{`$[*]`, `$[*]`},
+		{`$[1]`, `$[1]`},
+		{`$[1, 2 to 3]`, `$[1,2 to 3]`},
+		{`$[last]`, `$[last]`},
+		{`$[last - 1]`, `$[(last - 1)]`},
+		{`$.**`, `$.**`},
+		{`$.**{2}`, `$.**{2}`},
+		{`$.**{1 to last}`, `$.**{1 to last}`},
+		{`$.**{last}`, `$.**{last}`},
+		{`$.a.type()`, `$."a".type()`},
+		{`$.datetime()`, `$.datetime()`},
+		{`$.a ? (@ > 1)`, `$."a"?(@ > 1)`},
+		{`$ ? (@.a > 1 && @.b < 2 || !(@.c == "x"))`, `$?(@."a" > 1 && @."b" < 2 || !(@."c" == "x"))`},
+		{`$ ? ((@.a > 1 || @.b < 2) && @.c == 1)`, `$?((@."a" > 1 || @."b" < 2) && @."c" == 1)`},
+		{`$ ? (exists (@.a))`, `$?(exists (@."a"))`},
+		{`$ ? ((@ > 1) is unknown)`, `$?((@ > 1) is unknown)`},
+		{`$ ? (@ like_regex "^a" flag "i")`, `$?(@ like_regex "^a" flag "i")`},
+		{`$ ? (@ starts with "a")`, `$?(@ starts with "a")`},
+		{`$ ? (@ starts with $x)`, `$?(@ starts with $"x")`},
+		{`$.a == 1`, `($."a" == 1)`},
+		{`$.a <> 1`, `($."a" != 1)`},
+		{`$.a + 1 * 2`, `($."a" + 1 * 2)`},
+		{`($.a + 1) * 2`, `(($."a" + 1) * 2)`},
+		{`-$.a`, `(-$."a")`},
+		{`-1`, `-1`},
+		{`- - 1`, `1`},
+		{`1.5e3`, `1500`},
+		{`0.001`, `0.001`},
+		{`"a\nb\"c"`, `"a\nb\"c"`},
+		{`"A\x42\u{43}"`, `"ABC"`},
+		{`$var`, `$"var"`},
+		{`$"my var"`, `$"my var"`},
+		{`null`, `null`},
+		{`true`, `true`},
+		{`($.a).b`, `$."a"."b"`},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			jp, err := Parse(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, jp.String())
+
+			// The canonical representation round trips.
+			jp, err = Parse(tc.expected)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, jp.String())
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		err   string
+	}{
+		{``, `syntax error at end of jsonpath input`},
+		{`$.`, `syntax error at end of jsonpath input`},
+		{`$[1`, `syntax error at end of jsonpath input`},
+		{`$ $`, `syntax error at or near "$" of jsonpath input`},
+		{`@`, `@ is not allowed in root expressions`},
+		{`last`, `LAST is allowed only in array subscripts`},
+		{`$.a.foo()`, `unknown item method .foo()`},
+		{`$ ? (@.a)`, `boolean expression expected`},
+		{`$.a && $.b`, `boolean expression expected`},
+		{`$.a == 1 == 2`, `syntax error at or near "==" of jsonpath input`},
+		{`($.a == 1) + 1`, `unexpected boolean expression`},
+		{`"abc`, `unexpected end of quoted string`},
+		{`1a`, `trailing junk after numeric literal`},
+		{`$ ? (@ like_regex "a" flag "z")`, `invalid input syntax for type jsonpath`},
+		{`$ ? (@ like_regex "a" flag "x")`, `XQuery "x" flag (expanded regular expressions) is not implemented`},
+		{`$ ? (@ like_regex "(")`, `invalid regular expression`},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := Parse(tc.input)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+var alphabet = "abcdefghijklmnopqrstuvwxyz"
+
+// Random returns a random Jsonpath for testing.
+func Random(rng *rand.Rand) Jsonpath {
+	var sb strings.Builder
+	if rng.Intn(4) == 0 {
+		sb.WriteString("strict ")
+	}
+	sb.WriteString("$")
+	nSteps := rng.Intn(4)
+	for i := 0; i < nSteps; i++ {
+		switch rng.Intn(5) {
+		case 0:
+			sb.WriteString("[*]")
+		case 1:
+			sb.WriteString("[")
+			sb.WriteString(strconv.Itoa(rng.Intn(5)))
+			sb.WriteString("]")
+		case 2:
+			sb.WriteString(".*")
+		default:
+			sb.WriteString(".")
+			sb.WriteString(strconv.Quote(randomKey(rng)))
+		}
+	}
+	switch rng.Intn(3) {
+	case 0:
+		sb.WriteString(" ? (@.")
+		sb.WriteString(strconv.Quote(randomKey(rng)))
+		sb.WriteString(" > ")
+		sb.WriteString(strconv.Itoa(rng.Intn(10)))
+		sb.WriteString(")")
+	case 1:
+		sb.WriteString(" == ")
+		sb.WriteString(strconv.Quote(randomKey(rng)))
+	}
+	jp, err := Parse(sb.String())
+	if err != nil {
+		panic(err)
+	}
+	return *jp
+}
+
+func randomKey(rng *rand.Rand) string {
+	l := make([]byte, 1+rng.Intn(5))
+	for i := range l {
+		l[i] = alphabet[rng.Intn(len(alphabet))]
+	}
+	return string(l)
+}
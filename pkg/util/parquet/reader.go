@@ -0,0 +1,181 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"github.com/apache/arrow/go/v11/parquet"
+	"github.com/apache/arrow/go/v11/parquet/file"
+	"github.com/apache/arrow/go/v11/parquet/schema"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// A Reader reads datums from a parquet file, one row group at a time.
+//
+// Tuple columns are not supported.
+type Reader struct {
+	reader   *file.Reader
+	colNames []string
+	typs     []*types.T
+}
+
+// NewReader constructs a Reader for the given parquet file.
+//
+// The column types are taken from the CRDB-specific metadata in the file, if
+// present (see WithReaderMetadata). Otherwise, colTypes, keyed by column name,
+// defines how the data in each column is decoded. In that case, the physical
+// representation of each column must match the one used by Writer for the
+// same type.
+func NewReader(f parquet.ReaderAtSeeker, colTypes map[string]*types.T) (*Reader, error) {
+	reader, err := file.NewParquetReader(f)
+	if err != nil {
+		return nil, err
+	}
+	r := &Reader{reader: reader}
+	if err := r.init(colTypes); err != nil {
+		return nil, errors.CombineErrors(err, reader.Close())
+	}
+	return r, nil
+}
+
+func (r *Reader) init(colTypes map[string]*types.T) error {
+	sch := r.reader.MetaData().Schema
+	numCols := sch.Root().NumFields()
+	if sch.NumColumns() != numCols {
+		return errors.New("parquet files with nested group columns are not supported")
+	}
+	r.colNames = make([]string, numCols)
+	for i := range r.colNames {
+		r.colNames[i] = sch.Root().Field(i).Name()
+	}
+
+	var typOids, typFamilies []int
+	kvMeta := r.reader.MetaData().KeyValueMetadata()
+	if tupleMeta := kvMeta.FindValue(tupleIndexesMetaKey); tupleMeta != nil {
+		tupleColumns, err := deserialize2DIntArray(*tupleMeta)
+		if err != nil {
+			return err
+		}
+		if len(tupleColumns) > 0 {
+			return errors.New("parquet files with tuple columns are not supported")
+		}
+	}
+	oidsMeta, familiesMeta := kvMeta.FindValue(typeOidMetaKey), kvMeta.FindValue(typeFamilyMetaKey)
+	if oidsMeta != nil && familiesMeta != nil {
+		var err error
+		if typOids, err = deserializeIntArray(*oidsMeta); err != nil {
+			return err
+		}
+		if typFamilies, err = deserializeIntArray(*familiesMeta); err != nil {
+			return err
+		}
+		if len(typOids) != numCols || len(typFamilies) != numCols {
+			return errors.Newf("type metadata describes %d columns, but the file contains %d columns",
+				len(typOids), numCols)
+		}
+	}
+
+	r.typs = make([]*types.T, numCols)
+	for i, name := range r.colNames {
+		if typOids != nil {
+			if typ, ok := types.OidToType[oid.Oid(typOids[i])]; ok && typ.Family() == types.Family(typFamilies[i]) {
+				// The metadata describes the element type of array columns.
+				if isArrayColumn(sch.Column(i)) {
+					typ = types.MakeArray(typ)
+				}
+				r.typs[i] = typ
+				continue
+			}
+		}
+		typ, ok := colTypes[name]
+		if !ok {
+			return errors.Newf("cannot determine the type of parquet column %q", name)
+		}
+		r.typs[i] = typ
+	}
+	return nil
+}
+
+// ColumnNames returns the names of the columns in the file.
+func (r *Reader) ColumnNames() []string {
+	return r.colNames
+}
+
+// NumRowGroups returns the number of row groups in the file.
+func (r *Reader) NumRowGroups() int {
+	return r.reader.NumRowGroups()
+}
+
+// ReadRowGroup reads all the rows in the given row group.
+func (r *Reader) ReadRowGroup(rg int) ([]tree.Datums, error) {
+	rgr := r.reader.RowGroup(rg)
+	numRows := rgr.NumRows()
+	rows := make([]tree.Datums, numRows)
+	for i := range rows {
+		rows[i] = make(tree.Datums, len(r.colNames))
+	}
+	for colIdx, typ := range r.typs {
+		col, err := rgr.Column(colIdx)
+		if err != nil {
+			return nil, err
+		}
+		isArray := isArrayColumn(col.Descriptor())
+		if isArray != (typ.Family() == types.ArrayFamily) {
+			return nil, errors.Newf("parquet column %q cannot be decoded as %s", r.colNames[colIdx], typ.SQLString())
+		}
+		decTyp := typ
+		if isArray {
+			decTyp = typ.ArrayContents()
+		}
+		dec, err := decoderFromFamilyAndType(decTyp.Oid(), decTyp.Family())
+		if err != nil {
+			return nil, err
+		}
+		colDatums, err := readColInRowGroup(col, dec, numRows, isArray, false /* isTuple */)
+		if err != nil {
+			if errors.HasAssertionFailure(err) {
+				// The physical type of the column does not match the
+				// decoder for the requested type.
+				return nil, errors.Newf("parquet column %q cannot be decoded as %s",
+					r.colNames[colIdx], typ.SQLString())
+			}
+			return nil, errors.Wrapf(err, "reading parquet column %q", r.colNames[colIdx])
+		}
+		for rowIdx, d := range colDatums {
+			if arr, ok := d.(*tree.DArray); ok {
+				// readColInRowGroup only populates the elements of arrays.
+				arr.ParamTyp = decTyp
+				for _, elem := range arr.Array {
+					if elem == tree.DNull {
+						arr.HasNulls = true
+					} else {
+						arr.HasNonNulls = true
+					}
+				}
+			}
+			rows[rowIdx][colIdx] = d
+		}
+	}
+	return rows, nil
+}
+
+// isArrayColumn returns whether the physical column encodes an array. See
+// the comment on arrayEntryNonNilDefLevel for how arrays are encoded.
+func isArrayColumn(col *schema.Column) bool {
+	return col.MaxDefinitionLevel() == 3
+}
+
+// Close closes the Reader.
+func (r *Reader) Close() error {
+	return r.reader.Close()
+}
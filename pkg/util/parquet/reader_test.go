@@ -0,0 +1,114 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/util/buildutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader(t *testing.T) {
+	colNames := []string{"i", "s", "a"}
+	colTypes := []*types.T{types.Int, types.String, types.IntArray}
+	sch, err := NewSchema(colNames, colTypes)
+	require.NoError(t, err)
+
+	arr := tree.NewDArray(types.Int)
+	require.NoError(t, arr.Append(tree.NewDInt(1)))
+	require.NoError(t, arr.Append(tree.DNull))
+	written := []tree.Datums{
+		{tree.NewDInt(1), tree.NewDString("a"), arr},
+		{tree.DNull, tree.NewDString(""), tree.DNull},
+		{tree.NewDInt(3), tree.DNull, tree.NewDArray(types.Int)},
+	}
+
+	// skipIfTestBuild skips tests which rely on the absence of reader
+	// metadata, which test builds always write.
+	skipIfTestBuild := func(t *testing.T) {
+		if buildutil.CrdbTestBuild {
+			skip.IgnoreLint(t, "test builds always write reader metadata")
+		}
+	}
+
+	for _, withMeta := range []bool{false, true} {
+		t.Run(fmt.Sprintf("metadata=%t", withMeta), func(t *testing.T) {
+			if !withMeta {
+				skipIfTestBuild(t)
+			}
+			var buf bytes.Buffer
+			opts := []Option{WithMaxRowGroupLength(2)}
+			if withMeta {
+				opts = append(opts, WithReaderMetadata())
+			}
+			w, err := NewWriter(sch, &buf, opts...)
+			require.NoError(t, err)
+			for _, row := range written {
+				require.NoError(t, w.AddRow(row))
+			}
+			require.NoError(t, w.Close())
+
+			var typMap map[string]*types.T
+			if !withMeta {
+				typMap = map[string]*types.T{"i": types.Int, "s": types.String, "a": types.IntArray}
+			}
+			r, err := NewReader(bytes.NewReader(buf.Bytes()), typMap)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, r.Close()) }()
+			require.Equal(t, colNames, r.ColumnNames())
+			require.Equal(t, 2, r.NumRowGroups())
+
+			var read []tree.Datums
+			for rg := 0; rg < r.NumRowGroups(); rg++ {
+				rows, err := r.ReadRowGroup(rg)
+				require.NoError(t, err)
+				read = append(read, rows...)
+			}
+			require.Equal(t, len(written), len(read))
+			for i := range written {
+				for j := range written[i] {
+					ValidateDatum(t, written[i][j], read[i][j])
+				}
+			}
+		})
+	}
+
+	t.Run("missing type", func(t *testing.T) {
+		skipIfTestBuild(t)
+		var buf bytes.Buffer
+		w, err := NewWriter(sch, &buf)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		_, err = NewReader(bytes.NewReader(buf.Bytes()), map[string]*types.T{"i": types.Int})
+		require.ErrorContains(t, err, `cannot determine the type of parquet column "s"`)
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		skipIfTestBuild(t)
+		var buf bytes.Buffer
+		w, err := NewWriter(sch, &buf)
+		require.NoError(t, err)
+		require.NoError(t, w.AddRow(written[0]))
+		require.NoError(t, w.Close())
+		r, err := NewReader(bytes.NewReader(buf.Bytes()),
+			map[string]*types.T{"i": types.Bool, "s": types.String, "a": types.IntArray})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, r.Close()) }()
+		_, err = r.ReadRowGroup(0)
+		require.ErrorContains(t, err, `parquet column "i" cannot be decoded as BOOL`)
+	})
+}
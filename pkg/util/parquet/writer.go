@@ -30,6 +30,10 @@ type config struct {
 
 	// Arbitrary kv metadata.
 	metadata metadata.KeyValueMetadata
+
+	// readerMetadata indicates whether to write the CRDB-specific type
+	// metadata used by Reader.
+	readerMetadata bool
 }
 
 // An Option is a configurable setting for the Writer.
@@ -92,6 +96,16 @@ func WithMetadata(m map[string]string) Option {
 	}
 }
 
+// WithReaderMetadata configures the writer to include the CRDB-specific
+// column type metadata in the file, so that a Reader can reconstruct the
+// written datums exactly.
+func WithReaderMetadata() Option {
+	return func(c *config) error {
+		c.readerMetadata = true
+		return nil
+	}
+}
+
 var allowedVersions = map[string]parquet.Version{
 	"v1.0": parquet.V1_0,
 	"v2.4": parquet.V1_0,
@@ -180,9 +194,9 @@ func NewWriter(sch *SchemaDefinition, sink io.Writer, opts ...Option) (*Writer,
 			return nil, err
 		}
 	}
-	// Add additional metadata required to use the reader utility functions in
-	// testutils.go.
-	if buildutil.CrdbTestBuild {
+	// Add additional metadata required to use the Reader and the reader
+	// utility functions in testutils.go.
+	if buildutil.CrdbTestBuild || cfg.readerMetadata {
 		if err := WithMetadata(MakeReaderMetadata(sch)).apply(&cfg); err != nil {
 			return nil, err
 		}
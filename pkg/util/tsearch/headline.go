@@ -0,0 +1,506 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// headlineOptions are the options that control the output of ts_headline.
+// See https://www.postgresql.org/docs/current/textsearch-controls.html#TEXTSEARCH-HEADLINE
+type headlineOptions struct {
+	// startSel and stopSel are the strings with which to delimit query words
+	// appearing in the document.
+	startSel string
+	stopSel  string
+	// maxWords and minWords determine the longest and shortest headlines to
+	// output.
+	maxWords int
+	minWords int
+	// shortWord is the length of the words that are dropped at the start and
+	// end of a headline, unless they are query terms.
+	shortWord int
+	// highlightAll, if set, causes the whole document to be used as the
+	// headline, ignoring the three preceding options.
+	highlightAll bool
+	// maxFragments is the maximum number of text fragments to display. Zero
+	// selects a non-fragment-based headline generation method.
+	maxFragments int
+	// fragmentDelimiter is the string used to separate fragments.
+	fragmentDelimiter string
+}
+
+var defaultHeadlineOptions = headlineOptions{
+	startSel:          "<b>",
+	stopSel:           "</b>",
+	maxWords:          35,
+	minWords:          15,
+	shortWord:         3,
+	fragmentDelimiter: " ... ",
+}
+
+// parseHeadlineOptions parses the options string of ts_headline, which is a
+// comma-separated list of option=value pairs. Values may be quoted with single
+// or double quotes.
+func parseHeadlineOptions(input string) (headlineOptions, error) {
+	opts := defaultHeadlineOptions
+	pairs, err := parseOptionList(input)
+	if err != nil {
+		return opts, err
+	}
+	for _, pair := range pairs {
+		key, val := pair[0], pair[1]
+		var intVal *int
+		switch strings.ToLower(key) {
+		case "startsel":
+			opts.startSel = val
+		case "stopsel":
+			opts.stopSel = val
+		case "fragmentdelimiter":
+			opts.fragmentDelimiter = val
+		case "highlightall":
+			switch strings.ToLower(val) {
+			case "1", "on", "true", "t", "y", "yes":
+				opts.highlightAll = true
+			default:
+				opts.highlightAll = false
+			}
+		case "maxwords":
+			intVal = &opts.maxWords
+		case "minwords":
+			intVal = &opts.minWords
+		case "shortword":
+			intVal = &opts.shortWord
+		case "maxfragments":
+			intVal = &opts.maxFragments
+		default:
+			return opts, pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized headline parameter: %q", key)
+		}
+		if intVal != nil {
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return opts, pgerror.Newf(pgcode.InvalidTextRepresentation,
+					"invalid input syntax for type integer: %q", val)
+			}
+			*intVal = n
+		}
+	}
+	if !opts.highlightAll {
+		if opts.minWords >= opts.maxWords {
+			return opts, pgerror.New(pgcode.InvalidParameterValue,
+				"MinWords should be less than MaxWords")
+		}
+		if opts.minWords <= 0 {
+			return opts, pgerror.New(pgcode.InvalidParameterValue,
+				"MinWords should be positive")
+		}
+		if opts.shortWord < 0 {
+			return opts, pgerror.New(pgcode.InvalidParameterValue,
+				"ShortWord should be >= 0")
+		}
+		if opts.maxFragments < 0 {
+			return opts, pgerror.New(pgcode.InvalidParameterValue,
+				"MaxFragments should be >= 0")
+		}
+	}
+	return opts, nil
+}
+
+// parseOptionList parses a comma-separated list of key=value pairs.
+func parseOptionList(input string) ([][2]string, error) {
+	var ret [][2]string
+	syntaxError := func() ([][2]string, error) {
+		return nil, pgerror.Newf(pgcode.Syntax, "invalid parameter list format: %q", input)
+	}
+	pos := 0
+	skipSpace := func() {
+		for pos < len(input) && unicode.IsSpace(rune(input[pos])) {
+			pos++
+		}
+	}
+	// readToken reads either a quoted string or a run of characters up to the
+	// next whitespace, comma or equals sign.
+	readToken := func() (string, bool) {
+		if pos >= len(input) {
+			return "", false
+		}
+		if q := input[pos]; q == '\'' || q == '"' {
+			var buf strings.Builder
+			pos++
+			for pos < len(input) {
+				if input[pos] == q {
+					// A doubled quote is an escaped quote.
+					if pos+1 < len(input) && input[pos+1] == q {
+						buf.WriteByte(q)
+						pos += 2
+						continue
+					}
+					pos++
+					return buf.String(), true
+				}
+				buf.WriteByte(input[pos])
+				pos++
+			}
+			return "", false
+		}
+		start := pos
+		for pos < len(input) && input[pos] != ',' && input[pos] != '=' &&
+			!unicode.IsSpace(rune(input[pos])) {
+			pos++
+		}
+		return input[start:pos], pos > start
+	}
+	for {
+		skipSpace()
+		if pos >= len(input) {
+			return ret, nil
+		}
+		key, ok := readToken()
+		if !ok {
+			return syntaxError()
+		}
+		skipSpace()
+		if pos >= len(input) || input[pos] != '=' {
+			return syntaxError()
+		}
+		pos++
+		skipSpace()
+		val, ok := readToken()
+		if !ok {
+			return syntaxError()
+		}
+		ret = append(ret, [2]string{key, val})
+		skipSpace()
+		if pos < len(input) {
+			if input[pos] != ',' {
+				return syntaxError()
+			}
+			pos++
+		}
+	}
+}
+
+// headlineToken is a token of a document processed by ts_headline. Tokens are
+// either words or runs of the non-word characters in between words.
+type headlineToken struct {
+	text   string
+	isWord bool
+	// highlight is set for words that match one of the non-negated terms of the
+	// query.
+	highlight bool
+}
+
+// Headline implements the ts_headline builtin, which returns an excerpt of the
+// input document in which the words that match the query are highlighted. The
+// options string controls the output as described by headlineOptions.
+func Headline(config string, document string, q TSQuery, options string) (string, error) {
+	opts, err := parseHeadlineOptions(options)
+	if err != nil {
+		return "", err
+	}
+
+	// Split the document into word and non-word tokens, and build the
+	// representation of the document that's used to find covers of the query.
+	var tokens []headlineToken
+	var doc []coverEntry
+	// wordTokens maps each position in the document to its token index.
+	var wordTokens []int
+	queryLeaves := sortAndDistinctQueryTerms(q)
+	positiveLeaves := positiveQueryTerms(q.root, nil /* leaves */, false /* negated */)
+	for pos := 0; pos < len(document); {
+		r, _ := utf8.DecodeRuneInString(document[pos:])
+		isWord := unicode.IsOneOf(validCharTables, r)
+		end := strings.IndexFunc(document[pos:], func(r rune) bool {
+			return unicode.IsOneOf(validCharTables, r) != isWord
+		})
+		if end < 0 {
+			end = len(document)
+		} else {
+			end += pos
+		}
+		tok := headlineToken{text: document[pos:end], isWord: isWord}
+		pos = end
+		if isWord {
+			wordTokens = append(wordTokens, len(tokens))
+			lexeme, stopWord, err := TSLexize(config, tok.text)
+			if err != nil {
+				return "", err
+			}
+			if !stopWord {
+				tok.highlight = matchesAnyQueryTerm(lexeme, positiveLeaves)
+				if matchesAnyQueryTerm(lexeme, queryLeaves) {
+					position := len(wordTokens)
+					if position > maxTSVectorPosition {
+						position = maxTSVectorPosition
+					}
+					p := tsPosition{position: uint16(position)}
+					doc = append(doc, coverEntry{
+						position: p.position,
+						terms:    []tsTerm{{lexeme: lexeme, positions: []tsPosition{p}}},
+					})
+				}
+			}
+		}
+		tokens = append(tokens, tok)
+	}
+	if len(tokens) == 0 {
+		return "", nil
+	}
+
+	h := headliner{tokens: tokens, opts: opts}
+	if opts.highlightAll {
+		return h.write([][2]int{{0, len(tokens) - 1}}), nil
+	}
+
+	// Find the covers of the query, in terms of token indexes.
+	var covers []headlineFragment
+	if q.root != nil {
+		c := coverFinder{doc: doc, q: q}
+		for {
+			begin, end, ok, err := c.next()
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				break
+			}
+			f := headlineFragment{
+				begin: wordTokens[doc[begin].position-1],
+				end:   wordTokens[doc[end].position-1],
+			}
+			f.words, f.highlights = h.count(f.begin, f.end)
+			covers = append(covers, f)
+		}
+	}
+
+	if len(covers) == 0 {
+		// Without any covers, the headline is the first MinWords words of the
+		// document.
+		end := 0
+		for i, words := 0, 0; i < len(tokens) && words < opts.minWords; i++ {
+			if tokens[i].isWord {
+				words++
+			}
+			end = i
+		}
+		return h.write([][2]int{{0, end}}), nil
+	}
+	if opts.maxFragments == 0 {
+		return h.write([][2]int{h.bestCover(covers)}), nil
+	}
+	return h.write(h.fragments(covers)), nil
+}
+
+// positiveQueryTerms appends the leaves of the query that aren't negated to
+// the input slice.
+func positiveQueryTerms(n *tsNode, leaves []*tsNode, negated bool) []*tsNode {
+	if n == nil {
+		return leaves
+	}
+	switch n.op {
+	case invalid:
+		if !negated {
+			leaves = append(leaves, n)
+		}
+		return leaves
+	case not:
+		return positiveQueryTerms(n.l, leaves, !negated)
+	}
+	leaves = positiveQueryTerms(n.l, leaves, negated)
+	return positiveQueryTerms(n.r, leaves, negated)
+}
+
+// matchesAnyQueryTerm returns whether the lexeme matches any of the query
+// leaves, regardless of the weights they request.
+func matchesAnyQueryTerm(lexeme string, leaves []*tsNode) bool {
+	for _, leaf := range leaves {
+		if leaf.term.lexeme == lexeme ||
+			(leaf.term.isPrefixMatch() && strings.HasPrefix(lexeme, leaf.term.lexeme)) {
+			return true
+		}
+	}
+	return false
+}
+
+// headlineFragment is a range of tokens of a document, along with its number
+// of words and highlighted words.
+type headlineFragment struct {
+	begin, end        int
+	words, highlights int
+}
+
+// headliner chooses and writes the fragments of a headline.
+type headliner struct {
+	tokens []headlineToken
+	opts   headlineOptions
+}
+
+// count returns the number of words and highlighted words within the given
+// range of tokens.
+func (h *headliner) count(begin, end int) (words int, highlights int) {
+	for i := begin; i <= end; i++ {
+		if h.tokens[i].isWord {
+			words++
+			if h.tokens[i].highlight {
+				highlights++
+			}
+		}
+	}
+	return words, highlights
+}
+
+// bestCover chooses the cover with the most highlighted words, preferring
+// earlier covers, and adjusts it to have between MinWords and MaxWords words.
+// This roughly corresponds to the mark_hl_words function in Postgres
+// wparser_def.c.
+func (h *headliner) bestCover(covers []headlineFragment) [2]int {
+	best := covers[0]
+	for _, c := range covers[1:] {
+		if c.highlights > best.highlights {
+			best = c
+		}
+	}
+	begin, end := h.stretch(best.begin, best.end, h.opts.minWords, h.opts.maxWords, 0, len(h.tokens)-1)
+	return h.trimShortWords(begin, end)
+}
+
+// fragments chooses up to MaxFragments non-overlapping covers, preferring the
+// ones with the most highlighted words, and stretches each of them to up to
+// MaxWords words. The fragments are returned in document order. This roughly
+// corresponds to the mark_hl_fragments function in Postgres wparser_def.c.
+func (h *headliner) fragments(covers []headlineFragment) [][2]int {
+	sort.SliceStable(covers, func(i, j int) bool {
+		if covers[i].highlights != covers[j].highlights {
+			return covers[i].highlights > covers[j].highlights
+		}
+		return covers[i].words < covers[j].words
+	})
+	var chosen []headlineFragment
+	for _, c := range covers {
+		if len(chosen) >= h.opts.maxFragments {
+			break
+		}
+		overlaps := false
+		for _, o := range chosen {
+			if c.begin <= o.end && o.begin <= c.end {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			chosen = append(chosen, c)
+		}
+	}
+	sort.Slice(chosen, func(i, j int) bool {
+		return chosen[i].begin < chosen[j].begin
+	})
+	ret := make([][2]int, len(chosen))
+	for i, c := range chosen {
+		// Stretch each fragment without overlapping its neighbors.
+		lo, hi := 0, len(h.tokens)-1
+		if i > 0 {
+			lo = ret[i-1][1] + 1
+		}
+		if i < len(chosen)-1 {
+			hi = chosen[i+1].begin - 1
+		}
+		stretch := 0
+		if c.words < h.opts.maxWords {
+			stretch = (h.opts.maxWords - c.words) / 2
+		}
+		begin, end := h.stretch(c.begin, c.end, c.words+2*stretch, h.opts.maxWords, lo, hi)
+		ret[i] = h.trimShortWords(begin, end)
+	}
+	return ret
+}
+
+// stretch extends the given range of tokens to the right, and then to the
+// left, until it contains at least minWords words, without going past the
+// lo and hi token indexes. If the range contains more than maxWords words, it
+// is instead truncated to its first maxWords words.
+func (h *headliner) stretch(begin, end, minWords, maxWords, lo, hi int) (int, int) {
+	words, _ := h.count(begin, end)
+	if words > maxWords {
+		words = 0
+		for i := begin; i <= end; i++ {
+			if h.tokens[i].isWord {
+				words++
+				if words == maxWords {
+					return begin, i
+				}
+			}
+		}
+	}
+	for i := end + 1; i <= hi && words < minWords; i++ {
+		if h.tokens[i].isWord {
+			words++
+		}
+		end = i
+	}
+	for i := begin - 1; i >= lo && words < minWords; i-- {
+		if h.tokens[i].isWord {
+			words++
+		}
+		begin = i
+	}
+	return begin, end
+}
+
+// trimShortWords drops the words that are no longer than ShortWord from the
+// start and the end of the given range of tokens, unless they are highlighted.
+func (h *headliner) trimShortWords(begin, end int) [2]int {
+	isShort := func(i int) bool {
+		t := h.tokens[i]
+		return t.isWord && !t.highlight && utf8.RuneCountInString(t.text) <= h.opts.shortWord
+	}
+	for begin < end && isShort(begin) {
+		begin++
+		for begin < end && !h.tokens[begin].isWord {
+			begin++
+		}
+	}
+	for end > begin && isShort(end) {
+		end--
+		for end > begin && !h.tokens[end].isWord {
+			end--
+		}
+	}
+	return [2]int{begin, end}
+}
+
+// write returns the headline composed of the given fragments.
+func (h *headliner) write(fragments [][2]int) string {
+	var buf strings.Builder
+	for i, f := range fragments {
+		if i > 0 {
+			buf.WriteString(h.opts.fragmentDelimiter)
+		}
+		for j := f[0]; j <= f[1]; j++ {
+			t := h.tokens[j]
+			if t.highlight {
+				buf.WriteString(h.opts.startSel)
+				buf.WriteString(t.text)
+				buf.WriteString(h.opts.stopSel)
+			} else {
+				buf.WriteString(t.text)
+			}
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeadline(t *testing.T) {
+	for _, tc := range []struct {
+		config   string
+		document string
+		query    string
+		options  string
+		expected string
+	}{
+		{
+			config: "english",
+			document: `The most common type of search
+is to find all documents containing given query terms
+and return them in order of their similarity to the
+query.`,
+			query: "query & similar",
+			expected: `containing given <b>query</b> terms
+and return them in order of their <b>similarity</b> to the
+<b>query</b>.`,
+		},
+		{
+			config:   "english",
+			document: "The quick brown fox jumps over the lazy dog.",
+			query:    "fox | dog",
+			options:  "HighlightAll=true",
+			expected: "The quick brown <b>fox</b> jumps over the lazy <b>dog</b>.",
+		},
+		{
+			config:   "english",
+			document: "The quick brown fox jumps over the lazy dog.",
+			query:    "jump",
+			options:  `StartSel = "[", StopSel=']', HighlightAll=on`,
+			expected: "The quick brown fox [jumps] over the lazy dog.",
+		},
+		{
+			config:   "simple",
+			document: "cat bites dog",
+			query:    "cat & !dog",
+			options:  "HighlightAll=1",
+			expected: "<b>cat</b> bites dog",
+		},
+		{
+			config:   "simple",
+			document: "a b c d e f",
+			query:    "zzz",
+			options:  "MinWords=2, MaxWords=3",
+			expected: "a b",
+		},
+		{
+			config:   "simple",
+			document: "the cat sat on a mat",
+			query:    "cat",
+			options:  "MinWords=4, MaxWords=5, ShortWord=1",
+			expected: "<b>cat</b> sat on",
+		},
+		{
+			config:   "simple",
+			document: "one two three four five six seven",
+			query:    "two <-> three",
+			options:  "MinWords=1, MaxWords=2",
+			expected: "<b>two</b> <b>three</b>",
+		},
+		{
+			config:   "simple",
+			document: "alpha beta gamma delta epsilon zeta eta theta iota kappa alpha",
+			query:    "alpha",
+			options:  "MaxFragments=2, MaxWords=3, MinWords=1",
+			expected: "<b>alpha</b> beta gamma ... iota kappa <b>alpha</b>",
+		},
+		{
+			config:   "simple",
+			document: "alpha beta gamma delta epsilon zeta eta theta iota kappa alpha",
+			query:    "alpha",
+			options:  `MaxFragments=1, MaxWords=3, MinWords=1, FragmentDelimiter="|"`,
+			expected: "<b>alpha</b> beta gamma",
+		},
+	} {
+		t.Log(tc.document)
+		q, err := ToTSQuery(tc.config, tc.query)
+		require.NoError(t, err)
+		actual, err := Headline(tc.config, tc.document, q, tc.options)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, actual)
+	}
+}
+
+func TestHeadlineOptionsError(t *testing.T) {
+	for _, tc := range []string{
+		`MinWords=10, MaxWords=5`,
+		`MinWords=0`,
+		`ShortWord=-1`,
+		`MaxFragments=-1`,
+		`MaxWords=abc`,
+		`Foo=1`,
+		`MaxWords`,
+		`MaxWords=5 MinWords=1`,
+		`StartSel="<b>`,
+	} {
+		t.Log(tc)
+		_, err := parseHeadlineOptions(tc)
+		assert.Error(t, err)
+	}
+}
@@ -13,6 +13,7 @@ package tsearch
 import (
 	"sort"
 	"strconv"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 
@@ -415,3 +416,120 @@ func (p *tsVectorLexer) syntaxError() (TSVector, error) {
 	}
 	return TSVector{}, pgerror.Newf(pgcode.Syntax, "syntax error in %s: %s", typ, p.input)
 }
+
+// lexWebSearch lexes the input according to the websearch_to_tsquery syntax,
+// which is modeled after the syntax of web search engines and never produces
+// syntax errors. The returned terms are already connected by TSQuery
+// operators, and each lexeme term holds a single token.
+//
+// The syntax is as follows:
+//   - Unquoted text is converted to terms separated by & operators.
+//   - Text inside of double quotes is converted to terms separated by <->
+//     operators. An unmatched double quote is ignored.
+//   - The word "or" (in any case) is converted to the | operator.
+//   - A dash at the beginning of a word or a quoted phrase is converted to the
+//     ! operator.
+//
+// Other punctuation is ignored.
+func lexWebSearch(input string) (TSVector, error) {
+	if len(input) >= maxTSVectorLen {
+		return nil, pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"string is too long for tsquery (%d bytes, max %d bytes)",
+			len(input), maxTSVectorLen)
+	}
+	var ret TSVector
+	// pendingOr is set when we've seen an "or" word after an operand, in which
+	// case the next operand is connected with | instead of &.
+	pendingOr := false
+	// negated is set when we've seen a dash at the start of a word, in which
+	// case the next operand is negated.
+	negated := false
+	// atWordStart is true if the previous rune was whitespace, or if we're at
+	// the start of the input.
+	atWordStart := true
+
+	addOperand := func(tokens []string) error {
+		if len(tokens) == 0 {
+			negated = false
+			return nil
+		}
+		if len(ret) > 0 {
+			op := and
+			if pendingOr {
+				op = or
+			}
+			ret = append(ret, tsTerm{operator: op})
+		}
+		if negated {
+			ret = append(ret, tsTerm{operator: not})
+		}
+		pendingOr, negated = false, false
+		if len(tokens) > 1 {
+			ret = append(ret, tsTerm{operator: lparen})
+		}
+		for i := range tokens {
+			if i > 0 {
+				ret = append(ret, tsTerm{operator: followedby, followedN: 1})
+			}
+			term, err := newLexemeTerm(tokens[i])
+			if err != nil {
+				return err
+			}
+			ret = append(ret, term)
+		}
+		if len(tokens) > 1 {
+			ret = append(ret, tsTerm{operator: rparen})
+		}
+		return nil
+	}
+
+	for pos := 0; pos < len(input); {
+		r, n := utf8.DecodeRuneInString(input[pos:])
+		switch {
+		case unicode.IsSpace(r):
+			pos += n
+			atWordStart = true
+			negated = false
+		case r == '"':
+			// Consume a phrase up to the closing quote. An unmatched quote is
+			// ignored.
+			start := pos + n
+			end := strings.IndexByte(input[start:], '"')
+			if end < 0 {
+				pos = start
+				continue
+			}
+			end += start
+			pos = end + 1
+			if err := addOperand(TSParse(input[start:end])); err != nil {
+				return nil, err
+			}
+			atWordStart = false
+		case r == '-' && atWordStart:
+			pos += n
+			negated = true
+			atWordStart = false
+		default:
+			// Consume a word up to the next whitespace or quote.
+			end := strings.IndexFunc(input[pos:], func(r rune) bool {
+				return r == '"' || unicode.IsSpace(r)
+			})
+			if end < 0 {
+				end = len(input)
+			} else {
+				end += pos
+			}
+			word := input[pos:end]
+			pos = end
+			atWordStart = false
+			if strings.EqualFold(word, "or") && len(ret) > 0 && !negated {
+				pendingOr = true
+				continue
+			}
+			if err := addOperand(TSParse(word)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ret, nil
+}
@@ -25,12 +25,8 @@ var defaultWeights = [4]float32{0.1, 0.2, 0.4, 1.0}
 // 0, the default, ignores the document length.
 // 1 devides the rank by 1 + the logarithm of the document length.
 // 2 divides the rank by the document length.
-// 4 divides the rank by the mean harmonic distance between extents.
-//
-//	NOTE: This is only implemented by ts_rank_cd, which is currently not
-//	implemented by CockroachDB. This constant is left for consistency with
-//	the original PostgreSQL source code.
-//
+// 4 divides the rank by the mean harmonic distance between extents. This is
+// only implemented by ts_rank_cd.
 // 8 divides the rank by the number of unique words in document.
 // 16 divides the rank by 1 + the logarithm of the number of unique words in document.
 // 32 divides the rank by itself + 1.
@@ -44,8 +40,7 @@ const (
 	// rankNormLength divides the rank by the document length.
 	rankNormLength = 0x02
 	// rankNormExtdist divides the rank by the mean harmonic distance between extents.
-	// Note, this is only implemented by ts_rank_cd, which is not currently implemented
-	// by CockroachDB. The constant is kept for consistency with Postgres.
+	// Note, this is only implemented by ts_rank_cd.
 	rankNormExtdist = 0x04
 	// rankNormUniq divides the rank by the number of unique words in document.
 	rankNormUniq = 0x08
@@ -57,7 +52,6 @@ const (
 
 // Defeat the unused linter.
 var _ = rankNoNorm
-var _ = rankNormExtdist
 
 // cntLen returns the count of represented lexemes in a tsvector, including
 // the number of repeated lexemes in the vector.
@@ -284,3 +278,217 @@ func wordDistance(dist int) float32 {
 	}
 	return float32(1.0 / (1.005 + 0.05*math.Exp(float64(float32(dist)/1.5-2))))
 }
+
+// RankCD implements the ts_rank_cd functionality, which ranks a tsvector
+// against a tsquery using the "cover density" ranking described in Clarke,
+// Cormack, and Tudhope's "Relevance Ranking for One to Three Term Queries".
+// Unlike Rank, it takes the proximity of the matching lexemes into account,
+// so it requires positional information in the tsvector. The weights and method
+// parameters have the same meaning as for Rank.
+//
+// This function is translated from the calc_rank_cd function in tsrank.c.
+// https://github.com/postgres/postgres/blob/765f5df726918bcdcfd16bcc5418e48663d1dd59/src/backend/utils/adt/tsrank.c#L854
+func RankCD(weights []float32, v TSVector, q TSQuery, method int) (float32, error) {
+	w := defaultWeights
+	if weights != nil {
+		copy(w[:4], weights[:4])
+	}
+	var invWeights [4]float64
+	for i := range w {
+		invWeights[i] = 1.0 / float64(w[i])
+	}
+	if len(v) == 0 || q.root == nil {
+		return 0, nil
+	}
+
+	doc := makeCoverDocument(v, q)
+	c := coverFinder{doc: doc, q: q}
+	var res, sumDist, prevExtPos float64
+	nExtent := 0
+	for {
+		begin, end, ok, err := c.next()
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			break
+		}
+		var invSum float64
+		for i := begin; i <= end; i++ {
+			invSum += invWeights[doc[i].weight.val()]
+		}
+		cPos := float64(end-begin+1) / invSum
+
+		// If the document is big enough, positions might have been truncated to
+		// the maximum position. In this case we approximate the number of noise
+		// words as half of the cover's length.
+		p, q := int(doc[begin].position), int(doc[end].position)
+		nNoise := (q - p) - (end - begin)
+		if nNoise < 0 {
+			nNoise = (end - begin) / 2
+		}
+		res += cPos / float64(1+nNoise)
+
+		curExtPos := float64(q+p) / 2.0
+		// Prevent a division by zero in the case of multiple lexemes at the same
+		// position.
+		if nExtent > 0 && curExtPos > prevExtPos {
+			sumDist += 1.0 / (curExtPos - prevExtPos)
+		}
+		prevExtPos = curExtPos
+		nExtent++
+	}
+
+	if method&rankNormLoglength > 0 {
+		res /= math.Log(float64(cntLen(v) + 1))
+	}
+
+	if method&rankNormLength > 0 {
+		l := cntLen(v)
+		if l > 0 {
+			res /= float64(l)
+		}
+	}
+
+	if method&rankNormExtdist > 0 && nExtent > 0 && sumDist > 0 {
+		res /= float64(nExtent) / sumDist
+	}
+
+	if method&rankNormUniq > 0 {
+		res /= float64(len(v))
+	}
+
+	if method&rankNormLoguniq > 0 {
+		res /= math.Log(float64(len(v)+1)) / math.Log(2.0)
+	}
+
+	if method&rankNormRdivrplus1 > 0 {
+		res /= res + 1
+	}
+
+	return float32(res), nil
+}
+
+// coverEntry is a single position of a document that matches at least one of
+// the terms of a query.
+type coverEntry struct {
+	position uint16
+	weight   tsWeight
+	// terms contains a single-position term for each of the lexemes at this
+	// position that matched the query.
+	terms []tsTerm
+}
+
+// makeCoverDocument returns the positions of the vector that match at least
+// one of the query terms, ordered by position. This corresponds to the
+// get_docrep function in Postgres tsrank.c.
+func makeCoverDocument(v TSVector, q TSQuery) []coverEntry {
+	queryLeaves := sortAndDistinctQueryTerms(q)
+	var doc []coverEntry
+	entryByPosition := make(map[uint16]int)
+	for _, leaf := range queryLeaves {
+		queryWeight := weightAny
+		if len(leaf.term.positions) > 0 {
+			if w := leaf.term.positions[0].weight &^ weightStar; w != 0 {
+				queryWeight = w
+			}
+		}
+		target := leaf.term.lexeme
+		prefixMatch := leaf.term.isPrefixMatch()
+		i := sort.Search(len(v), func(i int) bool {
+			return v[i].lexeme >= target
+		})
+		for ; i < len(v); i++ {
+			if prefixMatch {
+				if !strings.HasPrefix(v[i].lexeme, target) {
+					break
+				}
+			} else if v[i].lexeme != target {
+				break
+			}
+			// Note that stripped lexemes, which have no positions, are ignored.
+			for _, pos := range v[i].positions {
+				if !pos.weight.matches(queryWeight) {
+					continue
+				}
+				idx, ok := entryByPosition[pos.position]
+				if !ok {
+					idx = len(doc)
+					entryByPosition[pos.position] = idx
+					doc = append(doc, coverEntry{position: pos.position, weight: pos.weight})
+				}
+				doc[idx].terms = append(doc[idx].terms, tsTerm{
+					lexeme:    v[i].lexeme,
+					positions: []tsPosition{pos},
+				})
+			}
+		}
+	}
+	sort.Slice(doc, func(i, j int) bool {
+		return doc[i].position < doc[j].position
+	})
+	return doc
+}
+
+// coverFinder iterates over the covers of a query within a document. A cover
+// is a minimal range of the document that satisfies the query. This
+// corresponds to the Cover function in Postgres tsrank.c.
+type coverFinder struct {
+	doc []coverEntry
+	q   TSQuery
+	// pos is the index in doc at which the search for the next cover starts.
+	pos int
+	// buf is reused to hold the terms of the range being checked.
+	buf TSVector
+}
+
+// next returns the indexes of the first and last entries in the document of
+// the next cover, or false if there are no more covers.
+func (c *coverFinder) next() (begin int, end int, ok bool, err error) {
+	for c.pos < len(c.doc) {
+		// Find the upper bound of the cover by moving up from the current
+		// position.
+		end = -1
+		c.buf = c.buf[:0]
+		for i := c.pos; i < len(c.doc); i++ {
+			if ok, err = c.matches(i); err != nil {
+				return 0, 0, false, err
+			} else if ok {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return 0, 0, false, nil
+		}
+		// Find the lower bound of the cover by moving down from the upper bound.
+		begin = -1
+		c.buf = c.buf[:0]
+		for i := end; i >= c.pos; i-- {
+			if ok, err = c.matches(i); err != nil {
+				return 0, 0, false, err
+			} else if ok {
+				begin = i
+				break
+			}
+		}
+		if begin >= 0 {
+			// The search for the next cover starts after the beginning of this one.
+			c.pos = begin + 1
+			return begin, end, true, nil
+		}
+		c.pos++
+	}
+	return 0, 0, false, nil
+}
+
+// matches adds the terms of the i'th entry of the document to the range being
+// checked, and returns whether the range satisfies the query.
+func (c *coverFinder) matches(i int) (bool, error) {
+	c.buf = append(c.buf, c.doc[i].terms...)
+	v, err := normalizeTSVector(append(TSVector(nil), c.buf...))
+	if err != nil {
+		return false, err
+	}
+	return EvalTSQuery(c.q, v)
+}
@@ -44,3 +44,40 @@ func TestRank(t *testing.T) {
 		assert.Equalf(t, tt.expected, actual, "Rank(%v, %v, %v, %v)", tt.weights, tt.v, tt.q, tt.method)
 	}
 }
+
+func TestRankCD(t *testing.T) {
+	tests := []struct {
+		weights  []float32
+		v        string
+		q        string
+		method   int
+		expected float32
+	}{
+		{v: "a:1 b:2", q: "a & b", expected: 0.1},
+		{v: "a:1 b:3", q: "a & b", expected: 0.05},
+		{v: "a:1A b:2", q: "a & b", expected: 0.18181819},
+		{weights: []float32{0.1, 0.2, 0.4, 0.5}, v: "a:1A b:2", q: "a & b", expected: 0.16666667},
+		{v: "a:1 b:2", q: "a | b", expected: 0.2},
+		{v: "a:1 b:2", q: "a <-> b", expected: 0.1},
+		{v: "a:1 b:3", q: "a <-> b", expected: 0},
+		{v: "a:1 b:2", q: "a & !b", expected: 0.1},
+		{v: "a:1 b:2 a:3", q: "a & b", expected: 0.2},
+		{v: "ab:1 b:2", q: "a:* & b", expected: 0.1},
+		{v: "a:1B b:2", q: "a:A & b", expected: 0},
+		{v: "a b", q: "a & b", expected: 0},
+		{v: "a:1 b:2", q: "a | b", method: 1, expected: 0.18204784},
+		{v: "a:1 b:2", q: "a | b", method: 2, expected: 0.1},
+		{v: "a:1 b:2 c:6", q: "a | b", method: 4, expected: 0.1},
+		{v: "a:1 b:2 c:3", q: "a | b", method: 8, expected: 0.06666667},
+		{v: "a:1 b:2", q: "a | b", method: 32, expected: 0.16666667},
+	}
+	for _, tt := range tests {
+		v, err := ParseTSVector(tt.v)
+		assert.NoError(t, err)
+		q, err := ParseTSQuery(tt.q)
+		assert.NoError(t, err)
+		actual, err := RankCD(tt.weights, v, q, tt.method)
+		assert.NoError(t, err)
+		assert.InDeltaf(t, tt.expected, actual, 1e-7, "RankCD(%v, %v, %v, %v)", tt.weights, tt.v, tt.q, tt.method)
+	}
+}
@@ -12,6 +12,7 @@ package tsearch
 
 import (
 	"github.com/blevesearch/snowballstem"
+	"github.com/blevesearch/snowballstem/arabic"
 	"github.com/blevesearch/snowballstem/danish"
 	"github.com/blevesearch/snowballstem/dutch"
 	"github.com/blevesearch/snowballstem/english"
@@ -19,12 +20,15 @@ import (
 	"github.com/blevesearch/snowballstem/french"
 	"github.com/blevesearch/snowballstem/german"
 	"github.com/blevesearch/snowballstem/hungarian"
+	"github.com/blevesearch/snowballstem/irish"
 	"github.com/blevesearch/snowballstem/italian"
 	"github.com/blevesearch/snowballstem/norwegian"
 	"github.com/blevesearch/snowballstem/portuguese"
+	"github.com/blevesearch/snowballstem/romanian"
 	"github.com/blevesearch/snowballstem/russian"
 	"github.com/blevesearch/snowballstem/spanish"
 	"github.com/blevesearch/snowballstem/swedish"
+	"github.com/blevesearch/snowballstem/tamil"
 	"github.com/blevesearch/snowballstem/turkish"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -36,6 +40,8 @@ func getStemmer(config string) (func(env *snowballstem.Env) bool, error) {
 		return func(env *snowballstem.Env) bool {
 			return true
 		}, nil
+	case "arabic":
+		return arabic.Stem, nil
 	case "english":
 		return english.Stem, nil
 	case "danish":
@@ -50,18 +56,24 @@ func getStemmer(config string) (func(env *snowballstem.Env) bool, error) {
 		return german.Stem, nil
 	case "hungarian":
 		return hungarian.Stem, nil
+	case "irish":
+		return irish.Stem, nil
 	case "italian":
 		return italian.Stem, nil
 	case "norwegian":
 		return norwegian.Stem, nil
 	case "portuguese":
 		return portuguese.Stem, nil
+	case "romanian":
+		return romanian.Stem, nil
 	case "russian":
 		return russian.Stem, nil
 	case "spanish":
 		return spanish.Stem, nil
 	case "swedish":
 		return swedish.Stem, nil
+	case "tamil":
+		return tamil.Stem, nil
 	case "turkish":
 		return turkish.Stem, nil
 	}
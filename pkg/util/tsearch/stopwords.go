@@ -45,4 +45,9 @@ func init() {
 	}
 	// The simple text search config has no stopwords.
 	stopwordsMap["simple"] = nil
+	// Like in Postgres, these snowball text search configs have no stopword
+	// lists.
+	for _, name := range []string{"arabic", "irish", "romanian", "tamil"} {
+		stopwordsMap[name] = nil
+	}
 }
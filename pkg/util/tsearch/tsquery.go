@@ -433,6 +433,18 @@ func PhraseToTSQuery(config string, input string) (TSQuery, error) {
 	return toTSQuery(config, followedby, input)
 }
 
+// WebSearchToTSQuery implements the websearch_to_tsquery builtin, which lexes
+// an input written in a web search engine style syntax, performs stopwording
+// and normalization on the tokens, and returns a parsed query. See
+// lexWebSearch for a description of the syntax.
+func WebSearchToTSQuery(config string, input string) (TSQuery, error) {
+	vector, err := lexWebSearch(input)
+	if err != nil {
+		return TSQuery{}, err
+	}
+	return normalizeTSQueryTerms(config, invalid, input, vector)
+}
+
 // toTSQuery implements the to_tsquery builtin, which lexes an input,
 // performs stopwording and normalization on the tokens, and returns a parsed
 // query. If the interpose operator is not invalid, it's interposed between each
@@ -442,6 +454,15 @@ func toTSQuery(config string, interpose tsOperator, input string) (TSQuery, erro
 	if err != nil {
 		return TSQuery{}, err
 	}
+	return normalizeTSQueryTerms(config, interpose, input, vector)
+}
+
+// normalizeTSQueryTerms performs stopwording and normalization on the lexed
+// terms of the input, and returns a parsed query. If the interpose operator is
+// not invalid, it's interposed between each token in the input.
+func normalizeTSQueryTerms(
+	config string, interpose tsOperator, input string, vector TSVector,
+) (TSQuery, error) {
 	tokens := make(TSVector, 0, len(vector))
 	foundStopwords := false
 	for i := range vector {
@@ -652,3 +673,122 @@ func cleanupStopword(node *tsNode) (ret *tsNode, lAdd int, rAdd int) {
 	// Otherwise we found a non-phrase operator; keep it as-is.
 	return node, 0, 0
 }
+
+// Rewrite implements the ts_rewrite builtin, which replaces every occurrence of
+// the target query within the input query with the substitute query. Like in
+// Postgres, an & or | target matches any & or | node of the input that
+// contains all of the target's operands, in any order.
+func Rewrite(q TSQuery, target TSQuery, substitute TSQuery) TSQuery {
+	if q.root == nil || target.root == nil || substitute.root == nil {
+		return q
+	}
+	return TSQuery{root: rewriteNode(q.root, target.root, substitute.root)}
+}
+
+// rewriteNode returns a copy of the input node with every occurrence of the
+// target replaced by the substitute.
+func rewriteNode(n *tsNode, target *tsNode, substitute *tsNode) *tsNode {
+	if n.equals(target) {
+		return substitute.clone()
+	}
+	if n.op == invalid {
+		return n
+	}
+	if (n.op == and || n.op == or) && target.op == n.op {
+		if rest, ok := removeOperands(n.flatten(nil), target.flatten(nil)); ok {
+			if len(rest) == 0 {
+				return substitute.clone()
+			}
+			var ret *tsNode
+			for _, operand := range rest {
+				operand = rewriteNode(operand, target, substitute)
+				if ret == nil {
+					ret = operand
+				} else {
+					ret = &tsNode{op: n.op, l: ret, r: operand}
+				}
+			}
+			return &tsNode{op: n.op, l: ret, r: substitute.clone()}
+		}
+	}
+	ret := *n
+	ret.l = rewriteNode(n.l, target, substitute)
+	if n.r != nil {
+		ret.r = rewriteNode(n.r, target, substitute)
+	}
+	return &ret
+}
+
+// removeOperands removes each of the targets from the operands, returning the
+// remaining operands and true if all of the targets were found.
+func removeOperands(operands []*tsNode, targets []*tsNode) ([]*tsNode, bool) {
+	removed := make([]bool, len(operands))
+	for _, t := range targets {
+		found := false
+		for i, o := range operands {
+			if !removed[i] && o.equals(t) {
+				removed[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	rest := make([]*tsNode, 0, len(operands)-len(targets))
+	for i, o := range operands {
+		if !removed[i] {
+			rest = append(rest, o)
+		}
+	}
+	return rest, true
+}
+
+// flatten appends the operands of the tree of nodes with the same operator as
+// the receiver to the input slice.
+func (n *tsNode) flatten(operands []*tsNode) []*tsNode {
+	for _, child := range []*tsNode{n.l, n.r} {
+		if child.op == n.op {
+			operands = child.flatten(operands)
+		} else {
+			operands = append(operands, child)
+		}
+	}
+	return operands
+}
+
+// equals returns whether the receiver is structurally identical to the input.
+func (n *tsNode) equals(o *tsNode) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	if n.op != o.op || n.followedN != o.followedN {
+		return false
+	}
+	if n.op == invalid {
+		if n.term.lexeme != o.term.lexeme {
+			return false
+		}
+		var nWeight, oWeight tsWeight
+		if len(n.term.positions) > 0 {
+			nWeight = n.term.positions[0].weight
+		}
+		if len(o.term.positions) > 0 {
+			oWeight = o.term.positions[0].weight
+		}
+		return nWeight == oWeight
+	}
+	return n.l.equals(o.l) && n.r.equals(o.r)
+}
+
+// clone returns a deep copy of the receiver.
+func (n *tsNode) clone() *tsNode {
+	if n == nil {
+		return nil
+	}
+	ret := *n
+	ret.l = n.l.clone()
+	ret.r = n.r.clone()
+	return &ret
+}
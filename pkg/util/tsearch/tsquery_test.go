@@ -295,3 +295,70 @@ func TestParseTSQueryError(t *testing.T) {
 		assert.Error(t, err)
 	}
 }
+
+func TestWebSearchToTSQuery(t *testing.T) {
+	for _, tc := range []struct {
+		config      string
+		input       string
+		expectedStr string
+	}{
+		{`simple`, `foo bar`, `'foo' & 'bar'`},
+		{`simple`, `foo or bar`, `'foo' | 'bar'`},
+		{`simple`, `foo OR bar baz`, `'foo' | 'bar' & 'baz'`},
+		{`simple`, `or foo`, `'or' & 'foo'`},
+		{`simple`, `foo or`, `'foo'`},
+		{`simple`, `foo -bar`, `'foo' & !'bar'`},
+		{`simple`, `foo - bar`, `'foo' & 'bar'`},
+		{`simple`, `foo-bar`, `'foo' <-> 'bar'`},
+		{`simple`, `-foo-bar`, `!( 'foo' <-> 'bar' )`},
+		{`simple`, `"foo bar" baz`, `'foo' <-> 'bar' & 'baz'`},
+		{`simple`, `baz -"foo bar"`, `'baz' & !( 'foo' <-> 'bar' )`},
+		{`simple`, `"foo bar`, `'foo' & 'bar'`},
+		{`simple`, `abc "pg_class pg`, `'abc' & 'pg' <-> 'class' & 'pg'`},
+		{`simple`, `foo & | ! bar`, `'foo' & 'bar'`},
+		{`simple`, `"foo" or "bar"`, `'foo' | 'bar'`},
+		{`english`, `"supernovae stars" -crab`, `'supernova' <-> 'star' & !'crab'`},
+		{`english`, `"sad cat" or "fat rat"`, `'sad' <-> 'cat' | 'fat' <-> 'rat'`},
+		{`english`, `the cat`, `'cat'`},
+		{`english`, `"the cat" -the`, `'cat'`},
+	} {
+		t.Log(tc.input)
+		query, err := WebSearchToTSQuery(tc.config, tc.input)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expectedStr, query.String())
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	for _, tc := range []struct {
+		query       string
+		target      string
+		substitute  string
+		expectedStr string
+	}{
+		{`a & b`, `a`, `c`, `'c' & 'b'`},
+		{`a & b`, `a`, `foo|bar`, `( 'foo' | 'bar' ) & 'b'`},
+		{`a & b & c`, `c & a`, `d`, `'b' & 'd'`},
+		{`b & a`, `a & b`, `d`, `'d'`},
+		{`a | b & c`, `a & c`, `d`, `'a' | 'b' & 'c'`},
+		{`a <-> b | !a`, `a`, `x <-> y`, `'x' <-> 'y' <-> 'b' | !( 'x' <-> 'y' )`},
+		{`a:A & a`, `a`, `b`, `'a':A & 'b'`},
+		{`a:* & a`, `a:*`, `b`, `'b' & 'a'`},
+	} {
+		t.Log(tc.query)
+		q, err := ParseTSQuery(tc.query)
+		require.NoError(t, err)
+		target, err := ParseTSQuery(tc.target)
+		require.NoError(t, err)
+		substitute, err := ParseTSQuery(tc.substitute)
+		require.NoError(t, err)
+		actual := Rewrite(q, target, substitute)
+		assert.Equal(t, tc.expectedStr, actual.String())
+		// The input query must not be modified.
+		assert.Equal(t, q.String(), func() string {
+			orig, err := ParseTSQuery(tc.query)
+			require.NoError(t, err)
+			return orig.String()
+		}())
+	}
+}
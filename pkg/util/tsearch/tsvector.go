@@ -377,12 +377,14 @@ func normalizeTSVector(ret TSVector) (TSVector, error) {
 	return ret, nil
 }
 
-var validCharTables = []*unicode.RangeTable{unicode.Letter, unicode.Number}
+// validCharTables are the character classes that make up words. Marks are
+// included since they are part of the words of scripts like Tamil.
+var validCharTables = []*unicode.RangeTable{unicode.Letter, unicode.Mark, unicode.Number}
 
 // TSParse is the function that splits an input text into a list of
 // tokens. For now, the parser that we use is very simple: it merely lowercases
 // the input and splits it into tokens based on assuming that non-letter,
-// non-number characters are whitespace.
+// non-mark, non-number characters are whitespace.
 //
 // The Postgres text search parser is much, much more sophisticated. The
 // documentation (https://www.postgresql.org/docs/current/textsearch-parsers.html)
@@ -450,3 +452,94 @@ func DocumentToTSVector(config string, input string) (TSVector, error) {
 	}
 	return normalizeTSVector(vector)
 }
+
+// tsWeightFromChar returns the tsWeight that corresponds to the input weight
+// character, which is one of A, B, C or D, in either case.
+func tsWeightFromChar(c byte) (tsWeight, error) {
+	switch c {
+	case 'A', 'a':
+		return weightA, nil
+	case 'B', 'b':
+		return weightB, nil
+	case 'C', 'c':
+		return weightC, nil
+	case 'D', 'd':
+		// We don't explicitly store weightD, since it's the default.
+		return 0, nil
+	}
+	return 0, pgerror.Newf(pgcode.InvalidParameterValue, "unrecognized weight: %d", c)
+}
+
+// SetWeight implements the setweight builtin, which returns a copy of the
+// input vector with the given weight assigned to each of its positions. If
+// lexemes is non-nil, only positions of the listed lexemes are modified.
+// Lexemes without positions are left unchanged.
+func SetWeight(v TSVector, weight byte, lexemes []string) (TSVector, error) {
+	w, err := tsWeightFromChar(weight)
+	if err != nil {
+		return nil, err
+	}
+	var filter map[string]struct{}
+	if lexemes != nil {
+		filter = make(map[string]struct{}, len(lexemes))
+		for _, l := range lexemes {
+			filter[l] = struct{}{}
+		}
+	}
+	ret := make(TSVector, len(v))
+	for i := range v {
+		ret[i] = v[i]
+		if filter != nil {
+			if _, ok := filter[v[i].lexeme]; !ok {
+				continue
+			}
+		}
+		if len(v[i].positions) == 0 {
+			continue
+		}
+		ret[i].positions = make([]tsPosition, len(v[i].positions))
+		for j, pos := range v[i].positions {
+			ret[i].positions[j] = tsPosition{position: pos.position, weight: w}
+		}
+	}
+	return ret, nil
+}
+
+// Concat implements the tsvector || tsvector operator. The positions of the
+// right vector are shifted by the largest position of the left vector, so the
+// result behaves like a vector of the concatenation of the two documents. This
+// allows building a single vector out of documents that were processed with
+// different text search configurations.
+func Concat(l TSVector, r TSVector) (TSVector, error) {
+	var maxPos uint16
+	for i := range l {
+		for _, pos := range l[i].positions {
+			if pos.position > maxPos {
+				maxPos = pos.position
+			}
+		}
+	}
+	ret := make(TSVector, 0, len(l)+len(r))
+	for i := range l {
+		ret = append(ret, tsTerm{
+			lexeme:    l[i].lexeme,
+			positions: append([]tsPosition(nil), l[i].positions...),
+		})
+	}
+	for i := range r {
+		term := tsTerm{lexeme: r[i].lexeme}
+		if len(r[i].positions) > 0 {
+			term.positions = make([]tsPosition, len(r[i].positions))
+			for j, pos := range r[i].positions {
+				p := int(pos.position) + int(maxPos)
+				if p > maxTSVectorPosition {
+					// Postgres silently truncates positions larger than 16383 to 16383.
+					p = maxTSVectorPosition
+				}
+				term.positions[j] = tsPosition{position: uint16(p), weight: pos.weight}
+			}
+		}
+		ret = append(ret, term)
+	}
+	return normalizeTSVector(ret)
+}
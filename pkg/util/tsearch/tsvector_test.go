@@ -218,3 +218,72 @@ func BenchmarkTSVector(b *testing.B) {
 		}
 	})
 }
+
+func TestSetWeight(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		weight   byte
+		lexemes  []string
+		expected string
+	}{
+		{`a:1 b:2B c:3A`, 'A', nil, `'a':1A 'b':2A 'c':3A`},
+		{`a:1 b:2B c:3A`, 'd', nil, `'a':1 'b':2 'c':3`},
+		{`a:1,4 b:2B c`, 'c', nil, `'a':1C,4C 'b':2C 'c'`},
+		{`a:1 b:2B c:3A`, 'B', []string{"a", "c", "z"}, `'a':1B 'b':2B 'c':3B`},
+		{`a:1 b:2 c:3`, 'A', []string{}, `'a':1 'b':2 'c':3`},
+	} {
+		v, err := ParseTSVector(tc.input)
+		require.NoError(t, err)
+		actual, err := SetWeight(v, tc.weight, tc.lexemes)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, actual.String())
+		// The input vector must not be modified.
+		assert.Equal(t, tc.input, strings.ReplaceAll(v.String(), "'", ""))
+	}
+
+	v, err := ParseTSVector(`a:1`)
+	require.NoError(t, err)
+	_, err = SetWeight(v, 'E', nil)
+	assert.Error(t, err)
+}
+
+func TestConcat(t *testing.T) {
+	for _, tc := range []struct {
+		l, r     string
+		expected string
+	}{
+		{`a:1 b:2`, `c:1 d:2`, `'a':1 'b':2 'c':3 'd':4`},
+		{`a:1 b:2`, `a:1B c:2`, `'a':1,3B 'b':2 'c':4`},
+		{`a b`, `c:1 a:3`, `'a':3 'b' 'c':1`},
+		{`a:1 b:2`, `a c`, `'a':1 'b':2 'c'`},
+		{``, `a:1`, `'a':1`},
+		{`a:16383`, `b:2`, `'a':16383 'b':16383`},
+	} {
+		l, err := ParseTSVector(tc.l)
+		require.NoError(t, err)
+		r, err := ParseTSVector(tc.r)
+		require.NoError(t, err)
+		actual, err := Concat(l, r)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, actual.String())
+	}
+}
+
+func TestDocumentToTSVectorConfigs(t *testing.T) {
+	for _, tc := range []struct {
+		config   string
+		input    string
+		expected string
+	}{
+		{`english`, `the running dogs`, `'dog':3 'run':2`},
+		{`french`, `les chevaux courants`, `'cheval':2 'cour':3 'le':1`},
+		{`romanian`, `cărțile frumoase copiilor`, `'cop':3 'cărț':1 'frumoas':2`},
+		{`irish`, `na gcapall bhfuinneoga`, `'capall':2 'fuinneoga':3 'na':1`},
+		{`arabic`, `الكتابات`, `'كتاب':1`},
+		{`tamil`, `பள்ளிகளில்`, `'பள்ளி':1`},
+	} {
+		v, err := DocumentToTSVector(tc.config, tc.input)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, v.String())
+	}
+}
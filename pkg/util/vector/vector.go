@@ -0,0 +1,283 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package vector implements the VECTOR data type of the pgvector extension,
+// which is a fixed-length array of single precision floats that is typically
+// used to store embeddings.
+package vector
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// MaxDim is the maximum number of dimensions a vector can have.
+const MaxDim = 16000
+
+// T is the type of a vector.
+type T []float32
+
+// ParseVector parses the Postgres string representation of a vector, which
+// is a comma-separated list of numbers enclosed in square brackets.
+func ParseVector(input string) (T, error) {
+	s := strings.TrimSpace(input)
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return nil, invalidSyntaxError(input)
+	}
+	s = s[1 : len(s)-1]
+	if strings.TrimSpace(s) == "" {
+		return nil, pgerror.New(pgcode.DataException, "vector must have at least 1 dimension")
+	}
+	parts := strings.Split(s, ",")
+	if len(parts) > MaxDim {
+		return nil, pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"vector cannot have more than %d dimensions", MaxDim)
+	}
+	ret := make(T, len(parts))
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return nil, pgerror.Newf(pgcode.NumericValueOutOfRange,
+					"%q is out of range for type vector", strings.TrimSpace(part))
+			}
+			return nil, invalidSyntaxError(input)
+		}
+		if err := checkElement(float32(f)); err != nil {
+			return nil, err
+		}
+		ret[i] = float32(f)
+	}
+	return ret, nil
+}
+
+func invalidSyntaxError(input string) error {
+	return pgerror.Newf(pgcode.InvalidTextRepresentation,
+		"invalid input syntax for type vector: %q", input)
+}
+
+func checkElement(f float32) error {
+	if math.IsNaN(float64(f)) {
+		return pgerror.New(pgcode.DataException, "NaN not allowed in vector")
+	}
+	if math.IsInf(float64(f), 0) {
+		return pgerror.New(pgcode.DataException, "infinite value not allowed in vector")
+	}
+	return nil
+}
+
+// FromFloats returns a vector with the given elements. It returns an error if
+// any of the elements is not finite or if there are too many or too few
+// elements.
+func FromFloats(fs []float64) (T, error) {
+	if len(fs) == 0 {
+		return nil, pgerror.New(pgcode.DataException, "vector must have at least 1 dimension")
+	}
+	if len(fs) > MaxDim {
+		return nil, pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"vector cannot have more than %d dimensions", MaxDim)
+	}
+	ret := make(T, len(fs))
+	for i, f := range fs {
+		if f > math.MaxFloat32 || f < -math.MaxFloat32 {
+			return nil, pgerror.Newf(pgcode.NumericValueOutOfRange,
+				"%s is out of range for type vector", strconv.FormatFloat(f, 'g', -1, 64))
+		}
+		ret[i] = float32(f)
+		if err := checkElement(ret[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// String implements the fmt.Stringer interface.
+func (v T) String() string {
+	var sb strings.Builder
+	v.Format(&sb)
+	return sb.String()
+}
+
+// Format writes the string representation of the vector to the given builder.
+func (v T) Format(sb *strings.Builder) {
+	sb.WriteByte('[')
+	for i, f := range v {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.FormatFloat(float64(f), 'g', -1, 32))
+	}
+	sb.WriteByte(']')
+}
+
+// Size returns the size of the vector in bytes.
+func (v T) Size() uintptr {
+	return uintptr(len(v)) * 4
+}
+
+// Compare returns -1, 0 or 1 depending on whether v is less than, equal to or
+// greater than other. Vectors are compared element by element, and a vector
+// that is a prefix of the other is smaller, as in pgvector.
+func (v T) Compare(other T) int {
+	for i := 0; i < len(v) && i < len(other); i++ {
+		if v[i] < other[i] {
+			return -1
+		} else if v[i] > other[i] {
+			return 1
+		}
+	}
+	if len(v) < len(other) {
+		return -1
+	} else if len(v) > len(other) {
+		return 1
+	}
+	return 0
+}
+
+// CheckDims returns an error if the vector does not have the given number of
+// dimensions.
+func (v T) CheckDims(dims int) error {
+	if len(v) != dims {
+		return pgerror.Newf(pgcode.DataException,
+			"expected %d dimensions, not %d", dims, len(v))
+	}
+	return nil
+}
+
+// Encode encodes the vector into a serialized representation for on-disk
+// storage.
+func Encode(appendTo []byte, v T) ([]byte, error) {
+	appendTo = encoding.EncodeUint32Ascending(appendTo, uint32(len(v)))
+	for _, f := range v {
+		appendTo = encoding.EncodeUint32Ascending(appendTo, math.Float32bits(f))
+	}
+	return appendTo, nil
+}
+
+// Decode decodes a vector in disk-storage representation from the input byte
+// slice.
+func Decode(b []byte) (ret T, err error) {
+	var n, bits uint32
+	b, n, err = encoding.DecodeUint32Ascending(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != int(n)*4 {
+		return nil, errors.AssertionFailedf(
+			"expected %d bytes for vector with %d dimensions, found %d", n*4, n, len(b))
+	}
+	ret = make(T, n)
+	for i := range ret {
+		b, bits, err = encoding.DecodeUint32Ascending(b)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = math.Float32frombits(bits)
+	}
+	return ret, nil
+}
+
+func checkSameDims(a, b T) error {
+	if len(a) != len(b) {
+		return pgerror.Newf(pgcode.DataException,
+			"different vector dimensions %d and %d", len(a), len(b))
+	}
+	return nil
+}
+
+// L2Distance returns the Euclidean distance between the two vectors.
+func L2Distance(a, b T) (float64, error) {
+	if err := checkSameDims(a, b); err != nil {
+		return 0, err
+	}
+	var dist float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		dist += d * d
+	}
+	return math.Sqrt(dist), nil
+}
+
+// L1Distance returns the taxicab distance between the two vectors.
+func L1Distance(a, b T) (float64, error) {
+	if err := checkSameDims(a, b); err != nil {
+		return 0, err
+	}
+	var dist float64
+	for i := range a {
+		dist += math.Abs(float64(a[i]) - float64(b[i]))
+	}
+	return dist, nil
+}
+
+// InnerProduct returns the inner product of the two vectors.
+func InnerProduct(a, b T) (float64, error) {
+	if err := checkSameDims(a, b); err != nil {
+		return 0, err
+	}
+	return dot(a, b), nil
+}
+
+// NegInnerProduct returns the negative inner product of the two vectors. It is
+// used by the <#> operator so that smaller values indicate closer vectors.
+func NegInnerProduct(a, b T) (float64, error) {
+	p, err := InnerProduct(a, b)
+	return -p, err
+}
+
+// CosDistance returns the cosine distance between the two vectors, which is
+// one minus their cosine similarity. The result is NaN if either vector has a
+// norm of zero.
+func CosDistance(a, b T) (float64, error) {
+	if err := checkSameDims(a, b); err != nil {
+		return 0, err
+	}
+	normA, normB := Norm(a), Norm(b)
+	if normA == 0 || normB == 0 {
+		return math.NaN(), nil
+	}
+	similarity := dot(a, b) / (normA * normB)
+	// Clamp the similarity to account for floating point error.
+	if similarity > 1 {
+		similarity = 1
+	} else if similarity < -1 {
+		similarity = -1
+	}
+	return 1 - similarity, nil
+}
+
+// Norm returns the Euclidean norm of the vector.
+func Norm(v T) float64 {
+	return math.Sqrt(dot(v, v))
+}
+
+func dot(a, b T) float64 {
+	var res float64
+	for i := range a {
+		res += float64(a[i]) * float64(b[i])
+	}
+	return res
+}
+
+// Random returns a random vector with the given number of dimensions.
+func Random(rng *rand.Rand, dims int) T {
+	v := make(T, dims)
+	for i := range v {
+		v[i] = float32(rng.NormFloat64())
+	}
+	return v
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package vector
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVector(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{input: "[1,2,3]", expected: "[1,2,3]"},
+		{input: " [ 1.5 , -2 ,3e2 ] ", expected: "[1.5,-2,300]"},
+		{input: "[0.1]", expected: "[0.1]"},
+		{input: "[1e20]", expected: "[1e+20]"},
+		{input: "[]", err: "vector must have at least 1 dimension"},
+		{input: "1,2", err: "invalid input syntax for type vector"},
+		{input: "[1,,2]", err: "invalid input syntax for type vector"},
+		{input: "[a]", err: "invalid input syntax for type vector"},
+		{input: "[NaN]", err: "NaN not allowed in vector"},
+		{input: "[Inf]", err: "infinite value not allowed in vector"},
+		{input: "[1e40]", err: "out of range for type vector"},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			v, err := ParseVector(tc.input)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, v.String())
+		})
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	for i := 1; i < 20; i++ {
+		v := Random(rng, i)
+		b, err := Encode(nil, v)
+		require.NoError(t, err)
+		decoded, err := Decode(b)
+		require.NoError(t, err)
+		require.Equal(t, v, decoded)
+		require.Equal(t, 0, v.Compare(decoded))
+	}
+}
+
+func TestDistances(t *testing.T) {
+	a, b := T{1, 2, 3}, T{3, 2, 1}
+
+	d, err := L2Distance(a, b)
+	require.NoError(t, err)
+	require.InDelta(t, math.Sqrt(8), d, 1e-9)
+
+	d, err = L1Distance(a, b)
+	require.NoError(t, err)
+	require.Equal(t, 4.0, d)
+
+	d, err = InnerProduct(a, b)
+	require.NoError(t, err)
+	require.Equal(t, 10.0, d)
+
+	d, err = NegInnerProduct(a, b)
+	require.NoError(t, err)
+	require.Equal(t, -10.0, d)
+
+	d, err = CosDistance(a, b)
+	require.NoError(t, err)
+	require.InDelta(t, 1-10.0/14.0, d, 1e-9)
+
+	d, err = CosDistance(a, T{0, 0, 0})
+	require.NoError(t, err)
+	require.True(t, math.IsNaN(d))
+
+	_, err = L2Distance(a, T{1, 2})
+	require.EqualError(t, err, "different vector dimensions 3 and 2")
+}
+
+func TestCompare(t *testing.T) {
+	require.Equal(t, -1, T{1, 2}.Compare(T{1, 3}))
+	require.Equal(t, 1, T{2}.Compare(T{1, 3}))
+	require.Equal(t, -1, T{1}.Compare(T{1, 3}))
+	require.Equal(t, 0, T{1, 3}.Compare(T{1, 3}))
+}